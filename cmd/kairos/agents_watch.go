@@ -0,0 +1,111 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jllopis/kairos/cmd/kairos/output"
+	"github.com/jllopis/kairos/pkg/a2a/agentcard"
+	"github.com/jllopis/kairos/pkg/discovery"
+)
+
+// Agent health states shown by `kairos agents list --watch`.
+const (
+	agentStatusOK    = "OK"
+	agentStatusError = "ERROR"
+	// agentStatusDown marks cards that were reachable in a previous poll.
+	agentStatusDown = "DOWN"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// agentHealth remembers which cards have been reachable so transitions from
+// OK to ERROR can be reported distinctly.
+type agentHealth struct {
+	seenOK map[string]bool
+}
+
+func newAgentHealth() *agentHealth {
+	return &agentHealth{seenOK: make(map[string]bool)}
+}
+
+// update records a poll and returns the status of each result by URL.
+func (h *agentHealth) update(results []agentResult) map[string]string {
+	status := make(map[string]string, len(results))
+	for _, res := range results {
+		switch {
+		case res.Err == "":
+			h.seenOK[res.URL] = true
+			status[res.URL] = agentStatusOK
+		case h.seenOK[res.URL]:
+			status[res.URL] = agentStatusDown
+		default:
+			status[res.URL] = agentStatusError
+		}
+	}
+	return status
+}
+
+// runAgentsWatch polls the agents every interval until ctx is cancelled,
+// redrawing the table or emitting one NDJSON object per poll with --json.
+func runAgentsWatch(ctx context.Context, flags globalFlags, resolver *discovery.Resolver, fetcher *agentcard.Fetcher, cardURLs []string, interval time.Duration) {
+	health := newAgentHealth()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := collectAgents(ctx, flags.Timeout, resolver, fetcher, cardURLs)
+		if ctx.Err() != nil {
+			return
+		}
+		now := time.Now()
+		status := health.update(results)
+
+		if flags.JSON {
+			line := map[string]any{"time": formatTime(now)}
+			if err != nil {
+				line["error"] = err.Error()
+			} else {
+				agents := agentsJSON(results)
+				for i, res := range results {
+					agents[i]["status"] = status[res.URL]
+				}
+				line["agents"] = agents
+			}
+			writeJSONLine(os.Stdout, line)
+		} else {
+			fmt.Print(clearScreen)
+			fmt.Printf("Every %s: kairos agents list (%s)\n\n", interval, now.Format(time.TimeOnly))
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+			} else {
+				writeList(output.Table, agentsStatusList(results, status))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// agentsStatusList is the agents table with a leading STATUS column.
+func agentsStatusList(results []agentResult, status map[string]string) output.List {
+	list := output.List{Columns: []string{"STATUS", "NAME", "VERSION", "URL", "DESCRIPTION"}}
+	for _, res := range results {
+		if res.Err != "" {
+			list.AddRow(status[res.URL], "", "", res.URL, res.Err)
+			continue
+		}
+		list.AddRow(status[res.URL], res.Card.GetName(), res.Card.GetVersion(), res.URL, res.Card.GetDescription())
+	}
+	return list
+}
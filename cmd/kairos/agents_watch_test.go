@@ -0,0 +1,74 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/a2a/agentcard"
+	"github.com/jllopis/kairos/pkg/discovery"
+)
+
+func TestAgentHealthMarksLostCards(t *testing.T) {
+	health := newAgentHealth()
+
+	status := health.update([]agentResult{
+		{URL: "http://a"},
+		{URL: "http://b", Err: "connection refused"},
+	})
+	if status["http://a"] != agentStatusOK || status["http://b"] != agentStatusError {
+		t.Fatalf("unexpected first poll status: %v", status)
+	}
+
+	status = health.update([]agentResult{
+		{URL: "http://a", Err: "timeout"},
+		{URL: "http://b", Err: "connection refused"},
+	})
+	if status["http://a"] != agentStatusDown {
+		t.Fatalf("expected lost card to be DOWN, got %q", status["http://a"])
+	}
+	if status["http://b"] != agentStatusError {
+		t.Fatalf("expected never-reachable card to stay ERROR, got %q", status["http://b"])
+	}
+
+	status = health.update([]agentResult{{URL: "http://a"}})
+	if status["http://a"] != agentStatusOK {
+		t.Fatalf("expected recovered card to be OK, got %q", status["http://a"])
+	}
+}
+
+// emptyProvider discovers no agents, like a well-known URL that was down.
+type emptyProvider struct{}
+
+func (emptyProvider) List(context.Context) ([]discovery.AgentEndpoint, error) { return nil, nil }
+
+func TestCollectAgentsRecordsRecoveredCards(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"billing","version":"1.0.0"}`))
+	}))
+	defer agent.Close()
+
+	resolver, err := discovery.NewResolver(emptyProvider{})
+	if err != nil {
+		t.Fatalf("NewResolver error: %v", err)
+	}
+	results, err := collectAgents(context.Background(), time.Second, resolver, agentcard.NewFetcher(), []string{agent.URL})
+	if err != nil {
+		t.Fatalf("collectAgents error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Err != "" {
+		t.Fatalf("expected no error for a reachable card, got %q", results[0].Err)
+	}
+	if results[0].Card.GetName() != "billing" {
+		t.Fatalf("expected the fetched card, got %v", results[0].Card)
+	}
+}
@@ -142,6 +142,8 @@ func FormatErrorCode(code errors.ErrorCode) string {
 		return "Timeout"
 	case errors.CodeRateLimit:
 		return "Rate Limited"
+	case errors.CodeBudgetExceeded:
+		return "Budget Exceeded"
 	case errors.CodeToolFailure:
 		return "Tool Failure"
 	case errors.CodeLLMError:
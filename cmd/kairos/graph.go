@@ -119,8 +119,9 @@ func toMermaid(g *planner.Graph) string {
 
 	// Edges
 	for _, edge := range g.Edges {
-		if edge.Condition != "" && edge.Condition != "default" && edge.Condition != "always" {
-			sb.WriteString(fmt.Sprintf("    %s -->|%s| %s\n", edge.From, edge.Condition, edge.To))
+		if label := edgeLabel(edge); label != "" {
+			label = strings.ReplaceAll(label, "\"", "#quot;")
+			sb.WriteString(fmt.Sprintf("    %s -->|%s| %s\n", edge.From, label, edge.To))
 		} else {
 			sb.WriteString(fmt.Sprintf("    %s --> %s\n", edge.From, edge.To))
 		}
@@ -134,6 +135,17 @@ func toMermaid(g *planner.Graph) string {
 	return sb.String()
 }
 
+// edgeLabel returns the when expression or non-default condition of edge.
+func edgeLabel(edge planner.Edge) string {
+	if edge.When != "" {
+		return edge.When
+	}
+	if edge.Condition != "default" && edge.Condition != "always" {
+		return edge.Condition
+	}
+	return ""
+}
+
 func toDot(g *planner.Graph) string {
 	var sb strings.Builder
 	sb.WriteString("digraph G {\n")
@@ -159,8 +171,8 @@ func toDot(g *planner.Graph) string {
 	// Edges
 	for _, edge := range g.Edges {
 		attrs := ""
-		if edge.Condition != "" && edge.Condition != "default" && edge.Condition != "always" {
-			attrs = fmt.Sprintf(" [label=\"%s\"]", edge.Condition)
+		if label := edgeLabel(edge); label != "" {
+			attrs = fmt.Sprintf(" [label=%q]", label)
 		}
 		sb.WriteString(fmt.Sprintf("    %q -> %q%s;\n", edge.From, edge.To, attrs))
 	}
@@ -11,18 +11,20 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"sort"
 	"strings"
 	"syscall"
 	"text/tabwriter"
 	"time"
 
+	"github.com/jllopis/kairos/cmd/kairos/output"
 	"github.com/jllopis/kairos/pkg/a2a/agentcard"
-	"github.com/jllopis/kairos/pkg/a2a/client"
 	httpjson "github.com/jllopis/kairos/pkg/a2a/httpjson/client"
 	"github.com/jllopis/kairos/pkg/a2a/server"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"github.com/jllopis/kairos/pkg/config"
+	"github.com/jllopis/kairos/pkg/core"
 	"github.com/jllopis/kairos/pkg/discovery"
 	kairosmcp "github.com/jllopis/kairos/pkg/mcp"
 	"github.com/jllopis/kairos/pkg/telemetry"
@@ -31,7 +33,6 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -44,6 +45,7 @@ type globalFlags struct {
 	ConfigArgs []string
 	GRPCAddr   string
 	HTTPURL    string
+	Transport  string
 	Timeout    time.Duration
 	JSON       bool
 	Help       bool
@@ -82,6 +84,12 @@ type mcpToolResult struct {
 	Error  string        `json:"error,omitempty"`
 }
 
+type mcpHealthResult struct {
+	Server  string `json:"server"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -137,6 +145,8 @@ func main() {
 		runExplain(ctx, global, args[1:])
 	case "graph":
 		runGraph(global, args[1:])
+	case "plan":
+		runPlan(global, args[1:])
 	case "adapters":
 		runAdapters(global, args[1:])
 	case "agents":
@@ -167,6 +177,7 @@ func parseGlobalFlags(args []string) (globalFlags, []string, error) {
 	flags := globalFlags{
 		GRPCAddr:           getenv("KAIROS_GRPC_ADDR", defaultGRPCAddr),
 		HTTPURL:            getenv("KAIROS_HTTP_URL", defaultHTTPURL),
+		Transport:          getenv("KAIROS_TRANSPORT", transportGRPC),
 		Timeout:            30 * time.Second,
 		WebAddr:            defaultWebAddr,
 		WebEnableAgents:    true, // enabled by default
@@ -254,6 +265,14 @@ func parseGlobalFlags(args []string) (globalFlags, []string, error) {
 			i++
 		case strings.HasPrefix(arg, "--set="):
 			flags.ConfigArgs = append(flags.ConfigArgs, arg)
+		case arg == "--profile":
+			if i+1 >= len(args) {
+				return flags, nil, fmt.Errorf("missing value for --profile")
+			}
+			flags.ConfigArgs = append(flags.ConfigArgs, arg, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--profile="):
+			flags.ConfigArgs = append(flags.ConfigArgs, arg)
 		case arg == "--grpc":
 			if i+1 >= len(args) {
 				return flags, nil, fmt.Errorf("missing value for --grpc")
@@ -270,6 +289,14 @@ func parseGlobalFlags(args []string) (globalFlags, []string, error) {
 			i++
 		case strings.HasPrefix(arg, "--http="):
 			flags.HTTPURL = strings.TrimPrefix(arg, "--http=")
+		case arg == "--transport":
+			if i+1 >= len(args) {
+				return flags, nil, fmt.Errorf("missing value for --transport")
+			}
+			flags.Transport = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--transport="):
+			flags.Transport = strings.TrimPrefix(arg, "--transport=")
 		case arg == "--timeout":
 			if i+1 >= len(args) {
 				return flags, nil, fmt.Errorf("missing value for --timeout")
@@ -314,15 +341,22 @@ func runStatus(flags globalFlags) {
 
 func runAgents(ctx context.Context, flags globalFlags, args []string) {
 	if len(args) == 0 || args[0] != "list" {
-		fatal(fmt.Errorf("usage: kairos agents list --agent-card <url>"))
+		fatal(fmt.Errorf("usage: kairos agents list --agent-card <url> [--watch --interval 10s]"))
 	}
 
 	cmd := flag.NewFlagSet("agents list", flag.ContinueOnError)
 	var cardURLs multiFlag
 	cmd.Var(&cardURLs, "agent-card", "AgentCard base URL (repeatable)")
+	watch := cmd.Bool("watch", false, "Re-fetch cards periodically and redraw")
+	interval := cmd.Duration("interval", 10*time.Second, "Polling interval for --watch")
+	format := defaultOutputFormat(flags)
+	cmd.Var(&format, "output", outputFlagUsage)
 	if err := cmd.Parse(args[1:]); err != nil {
 		fatal(err)
 	}
+	if *interval <= 0 {
+		fatal(errors.New("--interval must be positive"))
+	}
 	urls := append([]string{}, cardURLs...)
 	urls = append(urls, splitList(getenv("KAIROS_AGENT_CARD_URLS", ""))...)
 	urls = uniqueStrings(urls)
@@ -336,12 +370,45 @@ func runAgents(ctx context.Context, flags globalFlags, args []string) {
 	if err != nil {
 		fatal(err)
 	}
-	ctx, cancel := context.WithTimeout(ctx, flags.Timeout)
+	fetcher := agentcard.NewFetcher()
+
+	if *watch {
+		if format != output.Table && format != output.JSON {
+			fatal(errors.New("--watch supports table and json output"))
+		}
+		flags.JSON = format == output.JSON
+		runAgentsWatch(ctx, flags, resolver, fetcher, urls, *interval)
+		return
+	}
+
+	results, err := collectAgents(ctx, flags.Timeout, resolver, fetcher, urls)
+	if err != nil {
+		fatal(err)
+	}
+	list := output.List{
+		Columns: []string{"NAME", "VERSION", "URL", "DESCRIPTION"},
+		JSON:    agentsJSON(results),
+	}
+	for _, res := range results {
+		if res.Err != "" {
+			list.AddRow("ERROR", "", res.URL, res.Err)
+			continue
+		}
+		list.AddRow(res.Card.GetName(), res.Card.GetVersion(), res.URL, res.Card.GetDescription())
+	}
+	writeList(format, list)
+}
+
+// collectAgents resolves the configured agents and fetches their cards.
+// Explicit card URLs that discovery skipped are fetched again and reported
+// with their card, or with the error when they are still unreachable.
+func collectAgents(ctx context.Context, timeout time.Duration, resolver *discovery.Resolver, fetcher *agentcard.Fetcher, cardURLs []string) ([]agentResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	entries, err := resolver.Resolve(ctx)
 	if err != nil {
-		fatal(err)
+		return nil, err
 	}
 	discovery.SortByName(entries)
 	results := make([]agentResult, 0, len(entries))
@@ -358,7 +425,7 @@ func runAgents(ctx context.Context, flags globalFlags, args []string) {
 			results = append(results, res)
 			continue
 		}
-		card, err := agentcard.Fetch(ctx, entry.AgentCardURL)
+		card, err := fetcher.Fetch(ctx, entry.AgentCardURL)
 		res.URL = entry.AgentCardURL
 		res.Card = card
 		if err != nil {
@@ -367,61 +434,63 @@ func runAgents(ctx context.Context, flags globalFlags, args []string) {
 		}
 		results = append(results, res)
 	}
-
-	if flags.JSON {
-		out := make([]map[string]any, 0, len(results))
-		for _, res := range results {
-			entry := map[string]any{
-				"url": res.URL,
-			}
-			if res.Err != "" {
-				entry["error"] = res.Err
-				out = append(out, entry)
-				continue
-			}
-			payload, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(res.Card)
-			if err != nil {
-				entry["error"] = err.Error()
-			} else {
-				entry["card"] = json.RawMessage(payload)
-			}
-			out = append(out, entry)
-		}
-		printJSON(out)
-		return
-	}
-	for _, res := range results {
-		if res.Err != "" {
+	for _, baseURL := range cardURLs {
+		url := strings.TrimRight(baseURL, "/") + agentcard.WellKnownPath
+		if slices.ContainsFunc(results, func(res agentResult) bool { return res.URL == url }) {
 			continue
 		}
+		// Discovery skipped it, but it may have come back since.
+		res := agentResult{URL: url}
+		card, err := fetcher.Fetch(ctx, baseURL)
+		if err != nil {
+			res.Err = err.Error()
+		} else {
+			res.Card = card
+		}
+		results = append(results, res)
 	}
+	return results, nil
+}
 
-	writer := newTabWriter()
-	writeRow(writer, "NAME", "VERSION", "URL", "DESCRIPTION")
+func agentsJSON(results []agentResult) []map[string]any {
+	out := make([]map[string]any, 0, len(results))
 	for _, res := range results {
+		entry := map[string]any{
+			"url": res.URL,
+		}
 		if res.Err != "" {
-			writeRow(writer, "ERROR", "-", res.URL, res.Err)
+			entry["error"] = res.Err
+			out = append(out, entry)
 			continue
 		}
-		desc := strings.TrimSpace(res.Card.GetDescription())
-		writeRow(writer, res.Card.GetName(), res.Card.GetVersion(), res.URL, desc)
+		payload, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(res.Card)
+		if err != nil {
+			entry["error"] = err.Error()
+		} else {
+			entry["card"] = json.RawMessage(payload)
+		}
+		out = append(out, entry)
 	}
-	_ = writer.Flush()
+	return out
 }
 
 func runTasks(ctx context.Context, flags globalFlags, args []string) {
 	if len(args) == 0 {
-		fatal(errors.New("usage: kairos tasks <list|follow>"))
+		fatal(errors.New("usage: kairos tasks <list|get|follow|cancel|retry>"))
+	}
+	if args[0] == "follow" {
+		runTasksFollow(ctx, flags, args[1:])
+		return
 	}
-	conn, err := dialGRPC(ctx, flags.GRPCAddr, flags.Timeout)
+	client, closeClient, err := newA2AClient(ctx, flags, nil)
 	if err != nil {
 		fatal(err)
 	}
-	defer conn.Close()
-
-	client := client.New(conn, client.WithTimeout(flags.Timeout))
+	defer closeClient()
 
 	switch args[0] {
+	case "get":
+		runTasksGet(ctx, flags, client, args[1:])
 	case "list":
 		cmd := flag.NewFlagSet("tasks list", flag.ContinueOnError)
 		status := cmd.String("status", "", "Task status filter")
@@ -430,6 +499,8 @@ func runTasks(ctx context.Context, flags globalFlags, args []string) {
 		pageToken := cmd.String("page-token", "", "Page token")
 		history := cmd.Int("history-length", 0, "History length")
 		lastUpdated := cmd.Int64("updated-after", 0, "Updated after (ms since epoch)")
+		format := defaultOutputFormat(flags)
+		cmd.Var(&format, "output", outputFlagUsage)
 		if err := cmd.Parse(args[1:]); err != nil {
 			fatal(err)
 		}
@@ -455,60 +526,20 @@ func runTasks(ctx context.Context, flags globalFlags, args []string) {
 		if err != nil {
 			fatal(err)
 		}
-		if flags.JSON {
-			printProtoJSON(resp)
-			return
+		list := output.List{
+			Columns: []string{"TASK_ID", "STATUS", "UPDATED", "MESSAGE"},
+			JSON:    resp,
 		}
-		writer := newTabWriter()
-		writeRow(writer, "TASK_ID", "STATUS", "UPDATED", "MESSAGE")
 		for _, task := range resp.GetTasks() {
 			state := strings.ToLower(strings.TrimPrefix(task.GetStatus().GetState().String(), "TASK_STATE_"))
 			updated := formatTimestamp(task.GetStatus().GetTimestamp())
 			msg := truncateMessage(server.ExtractText(task.GetStatus().GetMessage()), 80)
-			writeRow(writer, task.GetId(), state, updated, msg)
+			list.AddRow(task.GetId(), state, updated, msg)
 		}
-		_ = writer.Flush()
-		if resp.GetNextPageToken() != "" {
+		writeList(format, list)
+		if format == output.Table && resp.GetNextPageToken() != "" {
 			fmt.Printf("next_page_token=%s\n", resp.GetNextPageToken())
 		}
-	case "follow":
-		cmd := flag.NewFlagSet("tasks follow", flag.ContinueOnError)
-		outPath := cmd.String("out", "", "Write JSON stream to file")
-		if err := cmd.Parse(args[1:]); err != nil {
-			fatal(err)
-		}
-		if cmd.NArg() < 1 {
-			fatal(errors.New("usage: kairos tasks follow <task_id>"))
-		}
-		taskID := cmd.Arg(0)
-		req := &a2av1.SubscribeToTaskRequest{Name: fmt.Sprintf("tasks/%s", taskID)}
-		stream, err := client.SubscribeToTask(ctx, req)
-		if err != nil {
-			fatal(err)
-		}
-		var outWriter io.WriteCloser
-		if strings.TrimSpace(*outPath) != "" {
-			file, err := os.Create(*outPath)
-			if err != nil {
-				fatal(err)
-			}
-			outWriter = file
-			defer func() { _ = outWriter.Close() }()
-		}
-
-		for {
-			resp, err := stream.Recv()
-			if errors.Is(err, io.EOF) {
-				return
-			}
-			if err != nil {
-				fatal(err)
-			}
-			printStreamResponse(resp, flags.JSON)
-			if outWriter != nil {
-				writeJSONLine(outWriter, resp)
-			}
-		}
 	case "cancel":
 		cmd := flag.NewFlagSet("tasks cancel", flag.ContinueOnError)
 		if err := cmd.Parse(args[1:]); err != nil {
@@ -518,7 +549,7 @@ func runTasks(ctx context.Context, flags globalFlags, args []string) {
 			fatal(errors.New("usage: kairos tasks cancel <task_id>"))
 		}
 		taskID := cmd.Arg(0)
-		task, err := client.CancelTask(ctx, &a2av1.CancelTaskRequest{Name: fmt.Sprintf("tasks/%s", taskID)})
+		task, err := client.CancelTask(ctx, &a2av1.CancelTaskRequest{Name: taskResourceName(taskID)})
 		if err != nil {
 			fatal(err)
 		}
@@ -538,7 +569,7 @@ func runTasks(ctx context.Context, flags globalFlags, args []string) {
 		}
 		taskID := cmd.Arg(0)
 		length := int32(*history)
-		task, err := client.GetTask(ctx, &a2av1.GetTaskRequest{Name: fmt.Sprintf("tasks/%s", taskID), HistoryLength: &length})
+		task, err := client.GetTask(ctx, &a2av1.GetTaskRequest{Name: taskResourceName(taskID), HistoryLength: &length})
 		if err != nil {
 			fatal(err)
 		}
@@ -563,6 +594,98 @@ func runTasks(ctx context.Context, flags globalFlags, args []string) {
 	}
 }
 
+// runApprovalsBulk applies action to ids in one batch request and prints one
+// outcome per id. It exits with status 1 if any decision failed.
+func runApprovalsBulk(ctx context.Context, flags globalFlags, client *httpjson.Client, action string, ids []string, reason string) {
+	if len(ids) == 0 {
+		if flags.JSON {
+			printJSON([]server.ApprovalResult{})
+			return
+		}
+		fmt.Println("no approvals matched")
+		return
+	}
+	var results []server.ApprovalResult
+	var err error
+	if action == "approve" {
+		results, err = client.BulkApprove(ctx, ids, reason)
+	} else {
+		results, err = client.BulkReject(ctx, ids, reason)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if flags.JSON {
+		printJSON(results)
+	} else {
+		writer := newTabWriter()
+		writeRow(writer, "ID", "RESULT", "TASK_ID", "TASK_STATE", "ERROR")
+		for _, result := range results {
+			outcome := string(result.Status)
+			if result.Error != "" {
+				outcome = "error"
+			}
+			writeRow(writer, result.ID, outcome, result.TaskID, result.TaskState, result.Error)
+		}
+		_ = writer.Flush()
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runTasksFollow(ctx context.Context, flags globalFlags, args []string) {
+	cmd := flag.NewFlagSet("tasks follow", flag.ContinueOnError)
+	outPath := cmd.String("out", "", "Write JSON stream to file")
+	overHTTP := cmd.Bool("http", false, "Follow over HTTP+JSON (SSE); same as --transport http")
+	if err := cmd.Parse(args); err != nil {
+		fatal(err)
+	}
+	if cmd.NArg() < 1 {
+		fatal(errors.New("usage: kairos tasks follow <task_id>"))
+	}
+	req := &a2av1.SubscribeToTaskRequest{Name: fmt.Sprintf("tasks/%s", cmd.Arg(0))}
+
+	if *overHTTP {
+		flags.Transport = transportHTTP
+	}
+	recv, closeStream, err := subscribeTask(ctx, flags, req)
+	if err != nil {
+		fatal(err)
+	}
+	defer closeStream()
+
+	var outWriter io.WriteCloser
+	if strings.TrimSpace(*outPath) != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fatal(err)
+		}
+		outWriter = file
+		defer func() { _ = outWriter.Close() }()
+	}
+
+	for {
+		resp, err := recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			fatal(err)
+		}
+		printStreamResponse(resp, flags.JSON)
+		if outWriter != nil {
+			writeJSONLine(outWriter, resp)
+		}
+	}
+}
+
 func runTraces(ctx context.Context, flags globalFlags, args []string) {
 	if len(args) == 0 || args[0] != "tail" {
 		fatal(errors.New("usage: kairos traces tail --task <task_id>"))
@@ -576,17 +699,12 @@ func runTraces(ctx context.Context, flags globalFlags, args []string) {
 	if strings.TrimSpace(*taskID) == "" {
 		fatal(errors.New("missing --task"))
 	}
-	conn, err := dialGRPC(ctx, flags.GRPCAddr, flags.Timeout)
-	if err != nil {
-		fatal(err)
-	}
-	defer conn.Close()
-	client := client.New(conn, client.WithTimeout(flags.Timeout))
 	req := &a2av1.SubscribeToTaskRequest{Name: fmt.Sprintf("tasks/%s", strings.TrimSpace(*taskID))}
-	stream, err := client.SubscribeToTask(ctx, req)
+	recv, closeStream, err := subscribeTask(ctx, flags, req)
 	if err != nil {
 		fatal(err)
 	}
+	defer closeStream()
 	var outWriter io.WriteCloser
 	if strings.TrimSpace(*outPath) != "" {
 		file, err := os.Create(*outPath)
@@ -597,7 +715,7 @@ func runTraces(ctx context.Context, flags globalFlags, args []string) {
 		defer func() { _ = outWriter.Close() }()
 	}
 	for {
-		resp, err := stream.Recv()
+		resp, err := recv()
 		if errors.Is(err, io.EOF) {
 			return
 		}
@@ -622,10 +740,17 @@ func runApprovals(ctx context.Context, flags globalFlags, args []string) {
 		cmd := flag.NewFlagSet("approvals list", flag.ContinueOnError)
 		status := cmd.String("status", "", "Approval status filter")
 		expiresBefore := cmd.String("expires-before", "", "Expiry cutoff (RFC3339 or ms since epoch)")
+		contextID := cmd.String("context", "", "Context ID filter")
+		toolName := cmd.String("tool", "", "Tool, skill or agent name filter")
+		format := defaultOutputFormat(flags)
+		cmd.Var(&format, "output", outputFlagUsage)
 		if err := cmd.Parse(args[1:]); err != nil {
 			fatal(err)
 		}
-		filter := server.ApprovalFilter{}
+		filter := server.ApprovalFilter{
+			ContextID: strings.TrimSpace(*contextID),
+			ToolName:  strings.TrimSpace(*toolName),
+		}
 		if strings.TrimSpace(*status) != "" {
 			filter.Status = server.ApprovalStatus(*status)
 		}
@@ -640,25 +765,55 @@ func runApprovals(ctx context.Context, flags globalFlags, args []string) {
 		if err != nil {
 			fatal(err)
 		}
-		if flags.JSON {
-			printJSON(records)
-			return
+		list := output.List{
+			Columns: []string{"APPROVAL_ID", "STATUS", "TOOL", "EXPIRES_AT", "REASON"},
+			JSON:    records,
 		}
-		writer := newTabWriter()
-		writeRow(writer, "APPROVAL_ID", "STATUS", "EXPIRES_AT", "REASON")
 		for _, record := range records {
 			expiresAt := formatTime(record.ExpiresAt)
-			writeRow(writer, record.ID, string(record.Status), expiresAt, record.Reason)
+			list.AddRow(record.ID, string(record.Status), record.ToolName, expiresAt, record.Reason)
 		}
-		_ = writer.Flush()
+		writeList(format, list)
 	case "approve", "reject":
 		cmd := flag.NewFlagSet("approvals action", flag.ContinueOnError)
 		reason := cmd.String("reason", "", "Approval reason")
+		all := cmd.Bool("all", false, "Apply to every approval matching --status/--expires-before")
+		status := cmd.String("status", "pending", "Approval status filter (with --all)")
+		expiresBefore := cmd.String("expires-before", "", "Expiry cutoff (with --all; RFC3339 or ms since epoch)")
+		contextID := cmd.String("context", "", "Context ID filter (with --all)")
+		toolName := cmd.String("tool", "", "Tool, skill or agent name filter (with --all)")
+		idList := cmd.String("ids", "", "Comma-separated approval IDs")
 		if err := cmd.Parse(args[1:]); err != nil {
 			fatal(err)
 		}
+		if *all || strings.TrimSpace(*idList) != "" {
+			ids := splitList(*idList)
+			if *all {
+				filter := server.ApprovalFilter{
+					ContextID: strings.TrimSpace(*contextID),
+					ToolName:  strings.TrimSpace(*toolName),
+					Status:    server.ApprovalStatus(strings.TrimSpace(*status)),
+				}
+				if strings.TrimSpace(*expiresBefore) != "" {
+					value, err := parseTimeMillis(*expiresBefore)
+					if err != nil {
+						fatal(err)
+					}
+					filter.ExpiringBefore = time.UnixMilli(value).UTC()
+				}
+				records, err := client.ListApprovals(ctx, filter)
+				if err != nil {
+					fatal(err)
+				}
+				for _, record := range records {
+					ids = append(ids, record.ID)
+				}
+			}
+			runApprovalsBulk(ctx, flags, client, args[0], uniqueStrings(ids), *reason)
+			return
+		}
 		if cmd.NArg() < 1 {
-			fatal(fmt.Errorf("usage: kairos approvals %s <approval_id>", args[0]))
+			fatal(fmt.Errorf("usage: kairos approvals %s <approval_id> | --ids a,b,c | --all", args[0]))
 		}
 		id := cmd.Arg(0)
 		var task *a2av1.Task
@@ -681,10 +836,15 @@ func runApprovals(ctx context.Context, flags globalFlags, args []string) {
 		status := cmd.String("status", "pending", "Approval status filter")
 		interval := cmd.Duration("interval", 5*time.Second, "Polling interval")
 		outPath := cmd.String("out", "", "Write JSON lines to file")
+		contextID := cmd.String("context", "", "Context ID filter")
+		toolName := cmd.String("tool", "", "Tool, skill or agent name filter")
 		if err := cmd.Parse(args[1:]); err != nil {
 			fatal(err)
 		}
-		filter := server.ApprovalFilter{}
+		filter := server.ApprovalFilter{
+			ContextID: strings.TrimSpace(*contextID),
+			ToolName:  strings.TrimSpace(*toolName),
+		}
 		if strings.TrimSpace(*status) != "" {
 			filter.Status = server.ApprovalStatus(*status)
 		}
@@ -697,6 +857,8 @@ func runApprovals(ctx context.Context, flags globalFlags, args []string) {
 			outWriter = file
 			defer func() { _ = outWriter.Close() }()
 		}
+		// The filter is fixed for the whole tail, so approvals are keyed by ID
+		// alone: one printed on an earlier poll is never repeated.
 		seen := map[string]struct{}{}
 		for {
 			records, err := client.ListApprovals(ctx, filter)
@@ -732,10 +894,20 @@ func runApprovals(ctx context.Context, flags globalFlags, args []string) {
 }
 
 func runMCP(ctx context.Context, flags globalFlags, cfg *config.Config, args []string) {
-	if len(args) == 0 || args[0] != "list" {
-		fatal(errors.New("usage: kairos mcp list"))
+	if len(args) == 0 || (args[0] != "list" && args[0] != "doctor") {
+		fatal(errors.New("usage: kairos mcp list|doctor"))
+	}
+	format := defaultOutputFormat(flags)
+	if args[0] == "list" {
+		cmd := flag.NewFlagSet("mcp list", flag.ContinueOnError)
+		cmd.Var(&format, "output", outputFlagUsage)
+		if err := cmd.Parse(args[1:]); err != nil {
+			fatal(err)
+		}
+		ensureNoArgs(cmd.Args())
+	} else {
+		ensureNoArgs(args[1:])
 	}
-	ensureNoArgs(args[1:])
 	if cfg == nil {
 		fatal(errors.New("config not loaded"))
 	}
@@ -750,6 +922,11 @@ func runMCP(ctx context.Context, flags globalFlags, cfg *config.Config, args []s
 	}
 	sort.Strings(serverNames)
 
+	if args[0] == "doctor" {
+		runMCPDoctor(ctx, flags, cfg, serverNames)
+		return
+	}
+
 	results := make([]mcpToolResult, 0)
 	for _, name := range serverNames {
 		srv := cfg.MCP.Servers[name]
@@ -772,21 +949,53 @@ func runMCP(ctx context.Context, flags globalFlags, cfg *config.Config, args []s
 		_ = client.Close()
 	}
 
-	if flags.JSON {
-		printJSON(results)
-		return
+	list := output.List{
+		Columns: []string{"SERVER", "TOOL", "DESCRIPTION"},
+		JSON:    results,
 	}
-	writer := newTabWriter()
-	writeRow(writer, "SERVER", "TOOL", "DESCRIPTION")
 	for _, res := range results {
 		if res.Error != "" {
-			writeRow(writer, res.Server, "ERROR", res.Error)
+			list.AddRow(res.Server, "ERROR", res.Error)
 			continue
 		}
-		desc := strings.TrimSpace(res.Tool.Description)
-		writeRow(writer, res.Server, res.Tool.Name, desc)
+		list.AddRow(res.Server, res.Tool.Name, res.Tool.Description)
+	}
+	writeList(format, list)
+}
+
+func runMCPDoctor(ctx context.Context, flags globalFlags, cfg *config.Config, serverNames []string) {
+	results := make([]mcpHealthResult, 0, len(serverNames))
+	unhealthy := false
+	for _, name := range serverNames {
+		client, err := newMCPClient(name, cfg.MCP.Servers[name])
+		if err != nil {
+			results = append(results, mcpHealthResult{Server: name, Status: string(core.HealthUnhealthy), Message: err.Error()})
+			unhealthy = true
+			continue
+		}
+		ctx, cancel := context.WithTimeout(ctx, flags.Timeout)
+		health := client.Health(ctx)
+		cancel()
+		_ = client.Close()
+		if health.Status != core.HealthHealthy {
+			unhealthy = true
+		}
+		results = append(results, mcpHealthResult{Server: name, Status: string(health.Status), Message: health.Message})
+	}
+
+	if flags.JSON {
+		printJSON(results)
+	} else {
+		writer := newTabWriter()
+		writeRow(writer, "SERVER", "STATUS", "MESSAGE")
+		for _, res := range results {
+			writeRow(writer, res.Server, res.Status, res.Message)
+		}
+		_ = writer.Flush()
+	}
+	if unhealthy {
+		os.Exit(1)
 	}
-	_ = writer.Flush()
 }
 
 func runRegistry(ctx context.Context, flags globalFlags, cfg *config.Config, args []string) {
@@ -911,7 +1120,7 @@ func printStreamResponse(resp *a2av1.StreamResponse, asJSON bool) {
 		update := payload.StatusUpdate
 		state := update.GetStatus().GetState().String()
 		msg := server.ExtractText(update.GetStatus().GetMessage())
-		eventType, payloadSummary := extractEventMetadata(update.GetMetadata())
+		eventType, payloadSummary := extractEventMetadata(update)
 		traceID := extractTraceID(update.GetStatus().GetMessage())
 		line := fmt.Sprintf("status=%s", strings.ToLower(strings.TrimPrefix(state, "TASK_STATE_")))
 		if eventType != "" {
@@ -930,7 +1139,7 @@ func printStreamResponse(resp *a2av1.StreamResponse, asJSON bool) {
 	case *a2av1.StreamResponse_Task:
 		fmt.Printf("task %s\n", payload.Task.GetId())
 	case *a2av1.StreamResponse_Msg:
-		text := server.ExtractText(payload.Msg)
+		text := server.ExtractAllText(payload.Msg, "\n")
 		if text != "" {
 			fmt.Printf("msg=%s\n", text)
 		}
@@ -939,31 +1148,21 @@ func printStreamResponse(resp *a2av1.StreamResponse, asJSON bool) {
 	}
 }
 
-func extractEventMetadata(meta *structpb.Struct) (string, string) {
-	if meta == nil {
+// extractEventMetadata returns the event type of a status event and its
+// payload as JSON.
+func extractEventMetadata(update *a2av1.TaskStatusUpdateEvent) (string, string) {
+	info, err := server.ParseStatusEvent(update)
+	if err != nil && !errors.Is(err, server.ErrNotStatusEvent) {
 		return "", ""
 	}
-	fields := meta.GetFields()
-	if fields == nil {
-		return "", ""
+	if info.Payload == nil {
+		return info.EventType, ""
 	}
-	var eventType string
-	if v, ok := fields["event_type"]; ok {
-		eventType = v.GetStringValue()
-	}
-	var payloadSummary string
-	if v, ok := fields["payload"]; ok {
-		if v.GetStructValue() != nil {
-			if payload, err := protojson.Marshal(v.GetStructValue()); err == nil {
-				payloadSummary = string(payload)
-			} else {
-				payloadSummary = v.String()
-			}
-		} else {
-			payloadSummary = v.String()
-		}
+	payload, err := json.Marshal(info.Payload)
+	if err != nil {
+		return info.EventType, ""
 	}
-	return eventType, payloadSummary
+	return info.EventType, string(payload)
 }
 
 func extractTraceID(message *a2av1.Message) string {
@@ -978,7 +1177,7 @@ func extractTraceID(message *a2av1.Message) string {
 	if fields == nil {
 		return ""
 	}
-	if v, ok := fields["trace_id"]; ok {
+	if v, ok := fields[server.TraceIDMetadataKey]; ok {
 		return v.GetStringValue()
 	}
 	return ""
@@ -1033,6 +1232,23 @@ func checkHTTP(rawURL string) bool {
 	return checkTCP(host)
 }
 
+const outputFlagUsage = "Output format: table, json, yaml or csv"
+
+// defaultOutputFormat returns the format used when --output is not set:
+// json with the global --json flag, table otherwise.
+func defaultOutputFormat(flags globalFlags) output.Format {
+	if flags.JSON {
+		return output.JSON
+	}
+	return output.Table
+}
+
+func writeList(format output.Format, list output.List) {
+	if err := output.Write(os.Stdout, format, list); err != nil {
+		fatal(err)
+	}
+}
+
 func printJSON(value any) {
 	payload, err := json.MarshalIndent(value, "", "  ")
 	if err != nil {
@@ -1114,8 +1330,10 @@ Usage:
 Global flags:
   --config <path>      Path to settings.json
   --set key=value      Override config (repeatable)
+  --profile <name>     Merge settings.<name>.json over the config (or KAIROS_PROFILE)
   --grpc <addr>        A2A gRPC address (default localhost:8080)
   --http <url>         A2A HTTP+JSON base URL (default http://localhost:8080)
+  --transport <name>   A2A transport for tasks and traces: grpc or http (default grpc, or KAIROS_TRANSPORT)
   --timeout <dur>      Request timeout (default 30s)
   --json               JSON output
   --web                Start the minimal web UI
@@ -1154,6 +1372,9 @@ Commands:
   graph [--path <file>] [--output mermaid|dot|json]
       Visualize planner graph as Mermaid, Graphviz DOT, or JSON
 
+  plan diff [--exit-code] <a.yaml> <b.yaml>
+      Show nodes and edges added, removed or changed between two graphs
+
   adapters list [--type <type>]
       List available adapters (llm, memory, mcp, a2a, telemetry)
 
@@ -1161,19 +1382,23 @@ Commands:
       Show detailed configuration for an adapter
 
   status
-  agents list --agent-card <url>
-  tasks list [--status <state>] [--context <id>] [--page-size N] [--page-token T]
-  tasks follow <task_id> [--out <path>]
+  agents list --agent-card <url> [--watch] [--interval 10s] [--output <format>]
+  tasks list [--status <state>] [--context <id>] [--page-size N] [--page-token T] [--output <format>]
+  tasks get <task_id> [--history-length N] [--include-artifacts]
+  tasks follow <task_id> [--http] [--out <path>]
   tasks cancel <task_id>
   tasks retry <task_id> [--history-length N]
   traces tail --task <task_id> [--out <path>]
-  approvals list [--status <status>] [--expires-before <time>]
-  approvals approve <id> [--reason <text>]
-  approvals reject <id> [--reason <text>]
-  approvals tail [--status <status>] [--interval 5s] [--out <path>]
-  mcp list
+  approvals list [--status <status>] [--context <id>] [--tool <name>] [--expires-before <time>] [--output <format>]
+  approvals approve <id> | --ids a,b,c | --all [--status pending] [--expires-before <time>] [--reason <text>]
+  approvals reject <id> | --ids a,b,c | --all [--status pending] [--expires-before <time>] [--reason <text>]
+  approvals tail [--status <status>] [--context <id>] [--tool <name>] [--interval 5s] [--out <path>]
+  mcp list [--output <format>]
+  mcp doctor
   registry serve [--addr :9900] [--ttl 30s]
 
+  List commands accept --output table|json|yaml|csv (default: table, or json with --json).
+
 Examples:
   kairos init my-agent --module github.com/myorg/my-agent
   kairos init my-agent --module github.com/myorg/my-agent --type tool-agent --mcp
@@ -1185,6 +1410,7 @@ Examples:
   kairos status
   kairos explain
   kairos graph --output mermaid
+  kairos plan diff plan.yaml plan.new.yaml
   kairos adapters list --type llm
   kairos tasks list --status completed
 `)
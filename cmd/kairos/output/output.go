@@ -0,0 +1,211 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package output renders CLI list results as a table, JSON, YAML or CSV.
+//
+// Commands declare their columns once and build a List; Write renders it in
+// the selected Format. Table, YAML and CSV share the column set, while JSON
+// encodes the command's full payload.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format. It implements flag.Value.
+type Format string
+
+// Supported formats.
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	CSV   Format = "csv"
+)
+
+// ParseFormat parses a format name.
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case Table, JSON, YAML, CSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (want table, json, yaml or csv)", value)
+	}
+}
+
+// String implements flag.Value.
+func (f *Format) String() string {
+	return string(*f)
+}
+
+// Set implements flag.Value.
+func (f *Format) Set(value string) error {
+	format, err := ParseFormat(value)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// List is a tabular result.
+type List struct {
+	// Columns are the table headers, e.g. "TASK_ID". YAML uses them
+	// lower-cased as keys.
+	Columns []string
+	// Rows holds one cell per column.
+	Rows [][]string
+	// JSON is the value encoded by the JSON format. Proto messages are
+	// encoded with protojson. When nil, rows are encoded as objects keyed
+	// like YAML.
+	JSON any
+}
+
+// AddRow appends a row.
+func (l *List) AddRow(cells ...string) {
+	l.Rows = append(l.Rows, cells)
+}
+
+// Write renders list to w in format.
+func Write(w io.Writer, format Format, list List) error {
+	switch format {
+	case Table, "":
+		return writeTable(w, list)
+	case JSON:
+		return writeJSON(w, list)
+	case YAML:
+		return writeYAML(w, list)
+	case CSV:
+		return writeCSV(w, list)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func writeTable(w io.Writer, list List) error {
+	writer := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(normalizeRow(list.Columns), "\t"))
+	for _, row := range list.Rows {
+		fmt.Fprintln(writer, strings.Join(normalizeRow(row), "\t"))
+	}
+	return writer.Flush()
+}
+
+// normalizeRow collapses whitespace so cells stay on one line and renders
+// empty cells as "-".
+func normalizeRow(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, cell := range cells {
+		out[i] = strings.Join(strings.Fields(cell), " ")
+		if out[i] == "" {
+			out[i] = "-"
+		}
+	}
+	return out
+}
+
+func writeJSON(w io.Writer, list List) error {
+	var payload []byte
+	var err error
+	switch value := list.JSON.(type) {
+	case nil:
+		payload, err = json.MarshalIndent(list.records(), "", "  ")
+	case proto.Message:
+		payload, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(value)
+	default:
+		payload, err = json.MarshalIndent(value, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(payload))
+	return err
+}
+
+// records returns the rows as ordered mappings for YAML and JSON.
+func (l List) records() []*orderedRecord {
+	keys := make([]string, len(l.Columns))
+	for i, column := range l.Columns {
+		keys[i] = strings.ToLower(column)
+	}
+	out := make([]*orderedRecord, 0, len(l.Rows))
+	for _, row := range l.Rows {
+		out = append(out, &orderedRecord{keys: keys, values: row})
+	}
+	return out
+}
+
+// orderedRecord keeps column order when encoded as a YAML or JSON object.
+type orderedRecord struct {
+	keys   []string
+	values []string
+}
+
+func (r *orderedRecord) value(i int) string {
+	if i < len(r.values) {
+		return r.values[i]
+	}
+	return ""
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r *orderedRecord) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(r.value(i))
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (r *orderedRecord) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, key := range r.keys {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: r.value(i), Tag: "!!str"},
+		)
+	}
+	return node, nil
+}
+
+func writeYAML(w io.Writer, list List) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(list.records()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func writeCSV(w io.Writer, list List) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(list.Columns); err != nil {
+		return err
+	}
+	for _, row := range list.Rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
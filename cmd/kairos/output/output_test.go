@@ -0,0 +1,68 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func sampleList() List {
+	list := List{Columns: []string{"TASK_ID", "STATUS", "MESSAGE"}}
+	list.AddRow("t-1", "completed", "done, with a comma")
+	list.AddRow("t-2", "working", "")
+	return list
+}
+
+func TestWriteFormats(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{Table, "TASK_ID  STATUS     MESSAGE\nt-1      completed  done, with a comma\nt-2      working    -\n"},
+		{CSV, "TASK_ID,STATUS,MESSAGE\nt-1,completed,\"done, with a comma\"\nt-2,working,\n"},
+		{YAML, "- task_id: t-1\n  status: completed\n  message: done, with a comma\n- task_id: t-2\n  status: working\n  message: \"\"\n"},
+		{JSON, "[\n  {\n    \"task_id\": \"t-1\",\n    \"status\": \"completed\",\n    \"message\": \"done, with a comma\"\n  },\n  {\n    \"task_id\": \"t-2\",\n    \"status\": \"working\",\n    \"message\": \"\"\n  }\n]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, sampleList()); err != nil {
+				t.Fatalf("Write error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONPayload(t *testing.T) {
+	list := sampleList()
+	list.JSON = map[string]int{"total": 2}
+	var buf bytes.Buffer
+	if err := Write(&buf, JSON, list); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "{\n  \"total\": 2\n}" {
+		t.Fatalf("expected JSON payload, got %s", buf.String())
+	}
+}
+
+func TestFormatFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	format := Table
+	fs.Var(&format, "output", "")
+	if err := fs.Parse([]string{"--output", "YAML"}); err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if format != YAML {
+		t.Fatalf("expected yaml, got %q", format)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jllopis/kairos/pkg/planner"
+)
+
+type planDiffResult struct {
+	Base    string                `json:"base"`
+	Target  string                `json:"target"`
+	Equal   bool                  `json:"equal"`
+	Changes []planner.GraphChange `json:"changes"`
+}
+
+func runPlan(global globalFlags, args []string) {
+	if len(args) == 0 || args[0] != "diff" {
+		fatal(errors.New("usage: kairos plan diff [--exit-code] <a.yaml> <b.yaml>"))
+	}
+	cmd := flag.NewFlagSet("plan diff", flag.ContinueOnError)
+	exitCode := cmd.Bool("exit-code", false, "Exit with status 1 when the graphs differ")
+	if err := cmd.Parse(args[1:]); err != nil {
+		fatal(err)
+	}
+	if cmd.NArg() != 2 {
+		fatal(errors.New("usage: kairos plan diff [--exit-code] <a.yaml> <b.yaml>"))
+	}
+	basePath, targetPath := cmd.Arg(0), cmd.Arg(1)
+
+	base, err := loadGraph(basePath)
+	if err != nil {
+		fatal(fmt.Errorf("load %s: %w", basePath, err))
+	}
+	target, err := loadGraph(targetPath)
+	if err != nil {
+		fatal(fmt.Errorf("load %s: %w", targetPath, err))
+	}
+
+	changes := planner.Diff(base, target)
+	if changes == nil {
+		changes = []planner.GraphChange{}
+	}
+
+	if global.JSON {
+		printJSON(planDiffResult{
+			Base:    basePath,
+			Target:  targetPath,
+			Equal:   len(changes) == 0,
+			Changes: changes,
+		})
+	} else if len(changes) == 0 {
+		fmt.Println("graphs are equivalent")
+	} else {
+		fmt.Printf("--- %s\n+++ %s\n", basePath, targetPath)
+		for _, change := range changes {
+			fmt.Println(change.String())
+		}
+	}
+
+	if *exitCode && len(changes) > 0 {
+		os.Exit(1)
+	}
+}
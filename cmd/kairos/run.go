@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -46,11 +47,7 @@ func runRun(ctx context.Context, flags globalFlags, args []string) {
 	// Load config with profile
 	configArgs := flags.ConfigArgs
 	if *profile != "" {
-		// Add profile-specific config
-		profilePath := fmt.Sprintf("./config/config.%s.yaml", *profile)
-		if _, err := os.Stat(profilePath); err == nil {
-			configArgs = append(configArgs, "--config", profilePath)
-		}
+		configArgs = append(slices.Clone(configArgs), "--profile", *profile)
 	}
 
 	cfg, err := config.LoadWithCLI(configArgs)
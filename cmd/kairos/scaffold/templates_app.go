@@ -85,7 +85,7 @@ func (a *App) Run(ctx context.Context) error {
 
 {{- if eq .Archetype "tool-agent"}}
 	// Tool-agent: add tools
-	opts = append(opts, agent.WithTools(tools.GetTools()))
+	opts = append(opts, agent.WithTools(tools.GetTools()...))
 	if a.cfg.Governance.Enable {
 		opts = append(opts, agent.WithPolicyEngine(a.createGovernance()))
 	}
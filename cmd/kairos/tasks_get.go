@@ -0,0 +1,118 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/a2a"
+	"github.com/jllopis/kairos/pkg/a2a/server"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+const taskNamePrefix = "tasks/"
+
+// taskResourceName accepts a bare task ID or a "tasks/<id>" resource name
+// and returns the resource name.
+func taskResourceName(id string) string {
+	if strings.HasPrefix(id, taskNamePrefix) {
+		return id
+	}
+	return taskNamePrefix + id
+}
+
+func runTasksGet(ctx context.Context, flags globalFlags, a2aClient a2a.Client, args []string) {
+	cmd := flag.NewFlagSet("tasks get", flag.ContinueOnError)
+	history := cmd.Int("history-length", 0, "Max history messages to include (0 = all)")
+	includeArtifacts := cmd.Bool("include-artifacts", false, "Include task artifacts")
+	if err := cmd.Parse(args); err != nil {
+		fatal(err)
+	}
+	if cmd.NArg() < 1 {
+		fatal(errors.New("usage: kairos tasks get <task_id> [--history-length N] [--include-artifacts]"))
+	}
+	req := &a2av1.GetTaskRequest{Name: taskResourceName(cmd.Arg(0))}
+	if *history > 0 {
+		length := int32(*history)
+		req.HistoryLength = &length
+	}
+	task, err := a2aClient.GetTask(ctx, req)
+	if err != nil {
+		fatal(err)
+	}
+	if *includeArtifacts {
+		artifacts, err := findTaskArtifacts(ctx, a2aClient, task)
+		if err != nil {
+			fatal(err)
+		}
+		task.Artifacts = artifacts
+	}
+
+	if flags.JSON {
+		printProtoJSON(task)
+		return
+	}
+	writeTaskDetail(os.Stdout, task, *includeArtifacts)
+}
+
+// findTaskArtifacts returns the artifacts of task. GetTask never returns
+// artifacts, so they are looked up with ListTasks in the task's context.
+func findTaskArtifacts(ctx context.Context, a2aClient a2a.Client, task *a2av1.Task) ([]*a2av1.Artifact, error) {
+	include := true
+	historyLength := int32(1)
+	req := &a2av1.ListTasksRequest{
+		ContextId:        task.GetContextId(),
+		IncludeArtifacts: &include,
+		HistoryLength:    &historyLength,
+	}
+	for {
+		resp, err := a2aClient.ListTasks(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range resp.GetTasks() {
+			if candidate.GetId() == task.GetId() {
+				return candidate.GetArtifacts(), nil
+			}
+		}
+		if resp.GetNextPageToken() == "" {
+			return nil, fmt.Errorf("task %s not found while listing artifacts", task.GetId())
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+// writeTaskDetail renders a task, its history turns and, when requested, its
+// artifact names. Messages are not truncated and include every text part.
+func writeTaskDetail(w io.Writer, task *a2av1.Task, includeArtifacts bool) {
+	fmt.Fprintf(w, "TASK_ID:  %s\n", normalizeCell(task.GetId()))
+	fmt.Fprintf(w, "CONTEXT:  %s\n", normalizeCell(task.GetContextId()))
+	fmt.Fprintf(w, "STATUS:   %s\n", strings.ToLower(strings.TrimPrefix(task.GetStatus().GetState().String(), "TASK_STATE_")))
+	fmt.Fprintf(w, "UPDATED:  %s\n", formatTimestamp(task.GetStatus().GetTimestamp()))
+	fmt.Fprintf(w, "MESSAGE:  %s\n", normalizeCell(server.ExtractAllText(task.GetStatus().GetMessage(), " ")))
+
+	fmt.Fprintf(w, "\nHISTORY (%d)\n", len(task.GetHistory()))
+	for i, message := range task.GetHistory() {
+		role := strings.ToLower(strings.TrimPrefix(message.GetRole().String(), "ROLE_"))
+		fmt.Fprintf(w, "  %d. [%s] %s\n", i+1, role, normalizeCell(server.ExtractAllText(message, " ")))
+	}
+
+	if !includeArtifacts {
+		return
+	}
+	fmt.Fprintf(w, "\nARTIFACTS (%d)\n", len(task.GetArtifacts()))
+	for _, artifact := range task.GetArtifacts() {
+		name := artifact.GetName()
+		if name == "" {
+			name = artifact.GetArtifactId()
+		}
+		fmt.Fprintf(w, "  - %s (%d parts)\n", normalizeCell(name), len(artifact.GetParts()))
+	}
+}
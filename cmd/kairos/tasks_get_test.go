@@ -0,0 +1,52 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+func TestTaskResourceName(t *testing.T) {
+	if got := taskResourceName("abc"); got != "tasks/abc" {
+		t.Fatalf("expected tasks/abc, got %q", got)
+	}
+	if got := taskResourceName("tasks/abc"); got != "tasks/abc" {
+		t.Fatalf("expected tasks/abc to be kept, got %q", got)
+	}
+}
+
+func TestWriteTaskDetail(t *testing.T) {
+	text := func(value string) []*a2av1.Part {
+		return []*a2av1.Part{{Part: &a2av1.Part_Text{Text: value}}}
+	}
+	task := &a2av1.Task{
+		Id:        "task-1",
+		ContextId: "ctx-1",
+		Status:    &a2av1.TaskStatus{State: a2av1.TaskState_TASK_STATE_COMPLETED},
+		History: []*a2av1.Message{
+			{Role: a2av1.Role_ROLE_USER, Parts: text("hello")},
+			{Role: a2av1.Role_ROLE_AGENT, Parts: append(text("hi there"), text("how can I help?")...)},
+		},
+		Artifacts: []*a2av1.Artifact{{ArtifactId: "a-1", Name: "report", Parts: text("body")}},
+	}
+
+	var buf bytes.Buffer
+	writeTaskDetail(&buf, task, true)
+	out := buf.String()
+	for _, want := range []string{"TASK_ID:  task-1", "STATUS:   completed", "1. [user] hello", "2. [agent] hi there how can I help?", "ARTIFACTS (1)", "- report (1 parts)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	writeTaskDetail(&buf, task, false)
+	if strings.Contains(buf.String(), "ARTIFACTS") {
+		t.Fatalf("artifacts should be omitted:\n%s", buf.String())
+	}
+}
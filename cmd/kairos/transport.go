@@ -0,0 +1,82 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/a2a"
+	"github.com/jllopis/kairos/pkg/a2a/client"
+	httpjson "github.com/jllopis/kairos/pkg/a2a/httpjson/client"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+// Transports accepted by --transport.
+const (
+	transportGRPC = "grpc"
+	transportHTTP = "http"
+)
+
+// parseTransport validates a --transport value.
+func parseTransport(value string) (string, error) {
+	switch transport := strings.ToLower(strings.TrimSpace(value)); transport {
+	case transportGRPC, transportHTTP:
+		return transport, nil
+	default:
+		return "", fmt.Errorf("invalid transport %q (want %s or %s)", value, transportGRPC, transportHTTP)
+	}
+}
+
+// newA2AClient connects to the agent over flags.Transport: gRPC at
+// flags.GRPCAddr or HTTP+JSON at flags.HTTPURL. onStreamError receives the
+// error that ends a stream and may be nil. The returned function releases
+// the connection.
+func newA2AClient(ctx context.Context, flags globalFlags, onStreamError func(error)) (a2a.Client, func(), error) {
+	transport, err := parseTransport(flags.Transport)
+	if err != nil {
+		return nil, nil, err
+	}
+	if transport == transportHTTP {
+		return httpjson.New(flags.HTTPURL, httpjson.WithStreamErrorHandler(onStreamError)), func() {}, nil
+	}
+	conn, err := dialGRPC(ctx, flags.GRPCAddr, flags.Timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := client.New(conn, client.WithTimeout(flags.Timeout), client.WithStreamErrorHandler(onStreamError))
+	return c.Unified(), func() { _ = conn.Close() }, nil
+}
+
+// subscribeTask follows a task over flags.Transport. The returned function
+// yields io.EOF when the stream ends normally and the stream error
+// otherwise.
+func subscribeTask(ctx context.Context, flags globalFlags, req *a2av1.SubscribeToTaskRequest) (func() (*a2av1.StreamResponse, error), func(), error) {
+	var streamErr error
+	a2aClient, closeClient, err := newA2AClient(ctx, flags, func(err error) {
+		streamErr = err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := a2aClient.SubscribeToTask(ctx, req)
+	if err != nil {
+		closeClient()
+		return nil, nil, err
+	}
+	recv := func() (*a2av1.StreamResponse, error) {
+		resp, ok := <-events
+		if !ok {
+			// The handler runs before the channel is closed.
+			if streamErr != nil {
+				return nil, streamErr
+			}
+			return nil, io.EOF
+		}
+		return resp, nil
+	}
+	return recv, closeClient, nil
+}
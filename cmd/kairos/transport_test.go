@@ -0,0 +1,31 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseTransportFlag(t *testing.T) {
+	flags, rest, err := parseGlobalFlags([]string{"--transport", "http", "tasks", "list"})
+	if err != nil {
+		t.Fatalf("parseGlobalFlags error: %v", err)
+	}
+	if flags.Transport != "http" || len(rest) != 2 {
+		t.Fatalf("unexpected flags %+v rest %v", flags, rest)
+	}
+	if got, err := parseTransport(" GRPC "); err != nil || got != transportGRPC {
+		t.Fatalf("expected grpc, got %q (%v)", got, err)
+	}
+	if _, err := parseTransport("websocket"); err == nil {
+		t.Fatal("expected an error for an unknown transport")
+	}
+}
+
+func TestNewA2AClientRejectsUnknownTransport(t *testing.T) {
+	if _, _, err := newA2AClient(context.Background(), globalFlags{Transport: "websocket"}, nil); err == nil {
+		t.Fatal("expected an error for an unknown transport")
+	}
+}
@@ -208,13 +208,13 @@ func validateMCPServers(ctx context.Context, cfg *config.Config, timeout time.Du
 	results := make([]checkResult, 0, len(cfg.MCP.Servers))
 
 	for name, server := range cfg.MCP.Servers {
-		transport := strings.ToLower(strings.TrimSpace(server.Transport))
-		if transport == "" {
-			transport = "stdio"
+		transport, ok := config.MCPTransport(server.Transport)
+		if !ok {
+			transport = server.Transport
 		}
 
 		switch transport {
-		case "stdio":
+		case config.MCPTransportStdio:
 			if strings.TrimSpace(server.Command) == "" {
 				results = append(results, checkResult{
 					Name:    fmt.Sprintf("mcp:%s", name),
@@ -230,7 +230,7 @@ func validateMCPServers(ctx context.Context, cfg *config.Config, timeout time.Du
 				Message: fmt.Sprintf("stdio: %s", server.Command),
 			})
 
-		case "http", "streamable-http", "streamablehttp":
+		case config.MCPTransportHTTP:
 			if server.URL == "" {
 				results = append(results, checkResult{
 					Name:    fmt.Sprintf("mcp:%s", name),
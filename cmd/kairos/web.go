@@ -18,7 +18,6 @@ import (
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"github.com/jllopis/kairos/pkg/config"
 	"github.com/jllopis/kairos/pkg/discovery"
-	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const defaultWebAddr = ":8088"
@@ -456,7 +455,7 @@ func (s *webServer) handleTaskHistory(w http.ResponseWriter, r *http.Request, ta
 		history = append(history, taskHistoryRow{
 			Role:      msg.GetRole().String(),
 			Timestamp: "-",
-			Text:      normalizeCell(server.ExtractText(msg)),
+			Text:      normalizeCell(server.ExtractAllText(msg, " ")),
 		})
 	}
 	renderPartial(w, "task_history", taskHistoryData{History: history})
@@ -509,7 +508,7 @@ func formatStreamLine(resp *a2av1.StreamResponse) (string, string) {
 		update := payload.StatusUpdate
 		state := strings.ToLower(strings.TrimPrefix(update.GetStatus().GetState().String(), "TASK_STATE_"))
 		msg := normalizeCell(server.ExtractText(update.GetStatus().GetMessage()))
-		eventType, _ := extractEventMetadataFromStruct(update.GetMetadata())
+		eventType, _ := extractEventMetadata(update)
 		class := "stream-status"
 		if eventType != "" {
 			switch eventType {
@@ -530,7 +529,7 @@ func formatStreamLine(resp *a2av1.StreamResponse) (string, string) {
 		}
 		return fmt.Sprintf("[%s] %s", state, msg), class
 	case *a2av1.StreamResponse_Msg:
-		text := normalizeCell(server.ExtractText(payload.Msg))
+		text := normalizeCell(server.ExtractAllText(payload.Msg, " "))
 		if text != "" {
 			return fmt.Sprintf("[msg] %s", text), "stream-msg"
 		}
@@ -540,25 +539,6 @@ func formatStreamLine(resp *a2av1.StreamResponse) (string, string) {
 	return "", ""
 }
 
-func extractEventMetadataFromStruct(meta *structpb.Struct) (string, string) {
-	if meta == nil {
-		return "", ""
-	}
-	fields := meta.GetFields()
-	if fields == nil {
-		return "", ""
-	}
-	var eventType string
-	if v, ok := fields["event_type"]; ok {
-		eventType = v.GetStringValue()
-	}
-	var payloadSummary string
-	if v, ok := fields["payload"]; ok {
-		payloadSummary = v.String()
-	}
-	return eventType, payloadSummary
-}
-
 func (s *webServer) withGRPC(ctx context.Context, fn func(*client.Client) error) error {
 	ctx, cancel := context.WithTimeout(ctx, s.flags.Timeout)
 	defer cancel()
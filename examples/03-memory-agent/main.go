@@ -67,7 +67,10 @@ func main() {
 		} else {
 			embedder := ollama.NewEmbedder(cfg.Memory.EmbedderBaseURL, cfg.Memory.EmbedderModel)
 
-			vMem, err := memory.NewVectorMemory(ctx, qStore, embedder, "kairos_memory")
+			// Keep the collection bounded across long runs.
+			vMem, err := memory.NewVectorMemory(ctx, qStore, embedder, "kairos_memory",
+				memory.WithRetention(1000, 30*24*time.Hour),
+			)
 			if err != nil {
 				log.Fatalf("failed to create vector memory: %v", err)
 			}
@@ -89,6 +92,7 @@ func main() {
 		agent.WithDisableActionFallback(agentCfg.DisableActionFallback),
 		agent.WithActionFallbackWarning(agentCfg.WarnOnActionFallback),
 		agent.WithMemory(mem),
+		agent.WithMemoryRetrieval(3, 0.6),
 	)
 	if err != nil {
 		log.Fatalf("failed to create agent: %v", err)
@@ -116,4 +120,7 @@ func main() {
 		log.Fatalf("run 2 failed: %v", err)
 	}
 	fmt.Printf("AGENT: %s\n", resp2)
+	for _, m := range a.LastRunMemories() {
+		fmt.Printf("  memory (score %.2f): %s\n", m.Score, m.Text)
+	}
 }
@@ -78,7 +78,8 @@ func main() {
 		agent.WithDisableActionFallback(agentCfg.DisableActionFallback),
 		agent.WithActionFallbackWarning(agentCfg.WarnOnActionFallback),
 		agent.WithMCPServerConfigs(cfg.MCP.Servers),
-		agent.WithMaxIterations(3), // Limit iterations for demo
+		agent.WithMaxIterations(3),                              // Limit iterations for demo
+		agent.WithMaxIterationsPolicy(agent.MaxIterationsError), // Fail if there is no answer by then
 	)
 	if err != nil {
 		log.Fatalf("failed to create agent: %v", err)
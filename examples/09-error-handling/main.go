@@ -51,12 +51,7 @@ func main() {
 	retryConfig := resilience.DefaultRetryConfig().
 		WithMaxAttempts(3).
 		WithInitialDelay(100 * time.Millisecond).
-		WithIsRecoverable(func(err error) bool {
-			if ke, ok := err.(*errors.KairosError); ok {
-				return ke.Recoverable
-			}
-			return false
-		})
+		WithIsRecoverable(errors.IsRecoverable)
 
 	attempts := 0
 	err = retryConfig.Do(ctx, func() error {
@@ -130,10 +125,9 @@ func main() {
 	}
 
 	for _, ex := range errorExamples {
-		if ke, ok := ex.err.(*errors.KairosError); ok {
-			fmt.Printf("%-15s Code: %-20s Recoverable: %v StatusCode: %d\n",
-				ex.name, ke.Code, ke.Recoverable, ke.StatusCode)
-		}
+		ke := errors.AsKairosError(ex.err)
+		fmt.Printf("%-15s Code: %-20s Recoverable: %v StatusCode: %d\n",
+			ex.name, ke.Code, ke.Recoverable, ke.StatusCode)
 	}
 
 	fmt.Println("\n=== All examples completed ===")
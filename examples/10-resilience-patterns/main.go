@@ -71,7 +71,7 @@ func main() {
 	llmChecker := &SimulatedLLMProvider{failureCount: 0}
 	provider.RegisterChecker("llm", llmChecker)
 	provider.RegisterChecker("memory", core.NewSimpleHealthChecker(core.HealthHealthy, "in-memory storage"))
-	provider.RegisterChecker("cache", core.NewSimpleHealthChecker(core.HealthDegraded, "cache eviction rate high"))
+	provider.RegisterChecker("cache", core.NewSimpleHealthChecker(core.HealthDegraded, "cache eviction rate high"), core.Optional)
 
 	results, overallStatus := provider.CheckAll(ctx)
 	fmt.Printf("Overall Status: %v\n", overallStatus)
@@ -318,6 +318,34 @@ func main() {
 	}
 	fmt.Println()
 
+	// Example 9: Tool latency and success rate
+	fmt.Println("--- Example 9: Tool Latency and Success Rate ---")
+	toolMetrics, err := telemetry.NewToolMetrics(ctx)
+	if err != nil {
+		slog.Error("failed to create tool metrics", "error", err)
+		return
+	}
+	toolCalls := []struct {
+		tool     string
+		duration time.Duration
+		err      error
+	}{
+		{"search", 120 * time.Millisecond, nil},
+		{"search", 95 * time.Millisecond, nil},
+		{"calculator", 3 * time.Millisecond, nil},
+		{"weather", 850 * time.Millisecond, errors.New(errors.CodeTimeout, "weather API timeout", nil)},
+	}
+	for _, call := range toolCalls {
+		toolMetrics.RecordToolCall(ctx, call.tool, call.duration, call.err)
+		outcome := telemetry.ToolOutcomeSuccess
+		if call.err != nil {
+			outcome = telemetry.ToolOutcomeError
+		}
+		fmt.Printf("  %s: %v (%s)\n", call.tool, call.duration, outcome)
+	}
+	fmt.Println("  Agents and MCP clients record these metrics automatically.")
+	fmt.Println()
+
 	fmt.Println("=== Phase 3 Examples Completed ===")
 	fmt.Println("\nMetrics exported to telemetry backend.")
 	fmt.Println("Dashboard queries available in docs/internal/observability-dashboards.go")
@@ -29,6 +29,14 @@ func main() {
 		),
 		// Mask PII in outputs
 		guardrails.WithPIIFilter(guardrails.PIIFilterMask),
+		// Block unsafe outputs
+		guardrails.WithOutputContentClassifier(
+			[]guardrails.ContentCategory{
+				guardrails.ContentCategoryDangerous,
+				guardrails.ContentCategorySelfHarm,
+			},
+			0.8,
+		),
 	)
 
 	// Display stats
@@ -75,6 +83,7 @@ func main() {
 		{"Phone", "Call me at 555-123-4567 for support."},
 		{"Multiple PII", "User: jane@corp.com, Phone: 555-555-5555, IP: 192.168.1.100"},
 		{"Credit card", "Your card ending in 4111111111111111 was charged."},
+		{"Unsafe content", "Sure! Here are instructions for making explosives."},
 	}
 
 	fmt.Println("--- Testing Output Filtering ---")
@@ -83,7 +92,9 @@ func main() {
 		result := g.FilterOutput(ctx, test.output)
 		fmt.Printf("[%s]\n", test.name)
 		fmt.Printf("  Original: %s\n", test.output)
-		if result.Modified {
+		if result.Blocked {
+			fmt.Printf("  🚫 Blocked: %s\n", result.Reason)
+		} else if result.Modified {
 			fmt.Printf("  Filtered: %s\n", result.Content)
 			fmt.Printf("  Redactions: %d items\n", len(result.Redactions))
 		} else {
@@ -5,6 +5,7 @@ go 1.25
 require (
 	github.com/jllopis/kairos v0.0.0
 	github.com/jllopis/kairos/providers/anthropic v0.0.0
+	github.com/jllopis/kairos/providers/bedrock v0.0.0
 	github.com/jllopis/kairos/providers/gemini v0.0.0
 	github.com/jllopis/kairos/providers/openai v0.0.0
 	github.com/jllopis/kairos/providers/qwen v0.0.0
@@ -15,6 +16,22 @@ require (
 	cloud.google.com/go/auth v0.9.3 // indirect
 	cloud.google.com/go/compute/metadata v0.9.0 // indirect
 	github.com/anthropics/anthropic-sdk-go v1.0.0 // indirect
+	github.com/aws/aws-sdk-go-v2 v1.47.1 // indirect
+	github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream v1.7.20 // indirect
+	github.com/aws/aws-sdk-go-v2/config v1.33.6 // indirect
+	github.com/aws/aws-sdk-go-v2/credentials v1.20.6 // indirect
+	github.com/aws/aws-sdk-go-v2/feature/ec2/imds v1.20.1 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.5.4 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.8.4 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/v4a v1.5.4 // indirect
+	github.com/aws/aws-sdk-go-v2/service/bedrockruntime v1.63.1 // indirect
+	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.13.19 // indirect
+	github.com/aws/aws-sdk-go-v2/service/internal/presigned-url v1.14.4 // indirect
+	github.com/aws/aws-sdk-go-v2/service/signin v1.10.1 // indirect
+	github.com/aws/aws-sdk-go-v2/service/sso v1.38.1 // indirect
+	github.com/aws/aws-sdk-go-v2/service/ssooidc v1.43.1 // indirect
+	github.com/aws/aws-sdk-go-v2/service/sts v1.51.1 // indirect
+	github.com/aws/smithy-go v1.28.1 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/google/go-cmp v0.7.0 // indirect
 	github.com/google/s2a-go v0.1.8 // indirect
@@ -39,6 +56,7 @@ require (
 replace (
 	github.com/jllopis/kairos => ../..
 	github.com/jllopis/kairos/providers/anthropic => ../../providers/anthropic
+	github.com/jllopis/kairos/providers/bedrock => ../../providers/bedrock
 	github.com/jllopis/kairos/providers/gemini => ../../providers/gemini
 	github.com/jllopis/kairos/providers/openai => ../../providers/openai
 	github.com/jllopis/kairos/providers/qwen => ../../providers/qwen
@@ -20,6 +20,9 @@
 //	# Test Qwen (requires DASHSCOPE_API_KEY env var)
 //	go run . -provider qwen
 //
+//	# Test Amazon Bedrock (uses the default AWS credential chain and region)
+//	go run . -provider bedrock
+//
 //	# Test all providers
 //	go run . -provider all
 package main
@@ -33,13 +36,14 @@ import (
 
 	"github.com/jllopis/kairos/pkg/llm"
 	"github.com/jllopis/kairos/providers/anthropic"
+	"github.com/jllopis/kairos/providers/bedrock"
 	"github.com/jllopis/kairos/providers/gemini"
 	"github.com/jllopis/kairos/providers/openai"
 	"github.com/jllopis/kairos/providers/qwen"
 )
 
 func main() {
-	providerName := flag.String("provider", "openai", "Provider to test: openai, anthropic, gemini, qwen, all")
+	providerName := flag.String("provider", "openai", "Provider to test: openai, anthropic, gemini, qwen, bedrock, all")
 	flag.Parse()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -56,6 +60,8 @@ func main() {
 		testGemini(ctx, testPrompt)
 	case "qwen":
 		testQwen(ctx, testPrompt)
+	case "bedrock":
+		testBedrock(ctx, testPrompt)
 	case "all":
 		testOpenAI(ctx, testPrompt)
 		fmt.Println()
@@ -64,6 +70,8 @@ func main() {
 		testGemini(ctx, testPrompt)
 		fmt.Println()
 		testQwen(ctx, testPrompt)
+		fmt.Println()
+		testBedrock(ctx, testPrompt)
 	default:
 		fmt.Printf("Unknown provider: %s\n", *providerName)
 		os.Exit(1)
@@ -133,6 +141,19 @@ func testQwen(ctx context.Context, prompt string) {
 	testProvider(ctx, provider, "qwen-turbo", prompt)
 }
 
+func testBedrock(ctx context.Context, prompt string) {
+	fmt.Println("=== Bedrock Provider ===")
+
+	provider, err := bedrock.New(ctx) // Uses the default AWS credential chain
+	if err != nil {
+		fmt.Printf("❌ Failed to load AWS config: %v\n", err)
+		return
+	}
+	fmt.Println("✓ AWS config loaded")
+
+	testProvider(ctx, provider, bedrock.DefaultModel, prompt)
+}
+
 func testProvider(ctx context.Context, provider llm.Provider, model, prompt string) {
 	fmt.Printf("Model: %s\n", model)
 	fmt.Printf("Prompt: %s\n", prompt)
@@ -105,6 +105,9 @@ func main() {
 		[]byte(petStoreSpec),
 		connectors.WithBaseURL(server.URL),
 		connectors.WithAPIKey("demo-key", "X-API-Key"),
+		// Keep list responses small: only the fields the agent needs.
+		connectors.WithResponseProjection("listPets", []string{"id", "name", "species"}),
+		connectors.WithMaxResponseBytes(4096),
 	)
 	if err != nil {
 		log.Fatalf("Failed to create connector: %v", err)
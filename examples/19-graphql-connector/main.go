@@ -122,9 +122,8 @@ func main() {
 	fmt.Println()
 	fmt.Println("These tools can be used with any Kairos agent:")
 	fmt.Print(`
-    agent := kairos.NewAgent(
-        kairos.WithProvider(openaiProvider),
-        kairos.WithTools(connector.Tools()...),
+    a, err := agent.New("api-agent", openaiProvider,
+        agent.WithTools(connectors.AsTools(connector)...),
     )
 `)
 }
@@ -3,9 +3,11 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/jllopis/kairos/pkg/mcp"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
@@ -18,8 +20,9 @@ func main() {
 	}
 
 	server := mcp.NewServer("kairos-http-tools", "0.2.5")
-	server.RegisterTool("echo", "Echo back a message", nil, func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
-		message := fmt.Sprint(args["message"])
+	schema := mcp.ObjectSchema().String("message", "Message to echo", mcp.Required())
+	server.RegisterTool("echo", "Echo back a message", schema, func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+		message := args["message"].(string) // validated against schema
 		return &mcpgo.CallToolResult{
 			Content: []mcpgo.Content{
 				mcpgo.TextContent{Type: "text", Text: message},
@@ -27,8 +30,19 @@ func main() {
 		}, nil
 	})
 
-	log.Printf("Starting MCP streamable HTTP server on %s", addr)
-	if err := server.ServeStreamableHTTP(addr); err != nil {
+	httpServer, err := server.StartStreamableHTTP(addr)
+	if err != nil {
 		log.Fatalf("server failed: %v", err)
 	}
+	log.Printf("MCP streamable HTTP server listening on %s", httpServer.BaseURL())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
 }
@@ -39,7 +39,7 @@ func main() {
 	// 5. Crear el agente con la herramienta
 	skyGuide, err := agent.NewSkyGuide("skyguide", provider, cfg,
 		//
-		// kairosagent.WithTools([]core.Tool{weatherTool}),
+		// kairosagent.WithTools(weatherTool),
 		kairosagent.WithMCPServerConfigs(cfg.MCP.Servers),
 	)
 	if err != nil {
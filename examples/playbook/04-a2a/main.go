@@ -14,7 +14,7 @@ import (
 	"github.com/jllopis/kairos/examples/playbook/shared/agent"
 	"github.com/jllopis/kairos/examples/playbook/shared/config"
 	"github.com/jllopis/kairos/examples/playbook/shared/observability"
-	a2aclient "github.com/jllopis/kairos/pkg/a2a/httpjson/client"
+	a2aclient "github.com/jllopis/kairos/pkg/a2a/client"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"github.com/jllopis/kairos/providers/gemini"
 )
@@ -46,6 +46,9 @@ func main() {
 		Name:        "SkyGuide Concierge",
 		Description: "Asistente de viajes SkyGuide desponible como servicio A2A.",
 		Version:     "1.0.0",
+		SupportedInterfaces: []*a2av1.AgentInterface{
+			{Url: "http://localhost:8080", ProtocolBinding: a2aclient.BindingHTTPJSON},
+		},
 	}
 
 	// Iniciar servidor A2A
@@ -58,7 +61,13 @@ func main() {
 	time.Sleep(1 * time.Second) // esperar a que el servidor esté listo
 
 	// Cliente A2A (simulando otro agente o sistema externo)
-	client := a2aclient.New("http://localhost:8080")
+	// El transporte se elige a partir de la tarjeta: gRPC si se anuncia,
+	// HTTP+JSON en caso contrario.
+	client, err := a2aclient.FromCard(ctx, card)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
 	resp, err := client.SendMessage(ctx, &a2av1.SendMessageRequest{
 		Request: &a2av1.Message{
 			MessageId: uuid.New().String(),
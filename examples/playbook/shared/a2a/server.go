@@ -16,16 +16,10 @@ func RunServer(ctx context.Context, addr string, agent core.Agent, card *a2av1.A
 	// 1. Crear el handler de a2a para el agente
 	handler := server.NewAgentHandler(agent, server.WithAgentCard(card))
 
-	// 2. Envolverlo en un servidor HTTP+JSON
-	httpServer := httpjson.New(handler)
-
-	// 3. Configurar el servidor HTTP estándar
-	mux := http.NewServeMux()
-	mux.Handle("/", httpServer)
-
+	// 2. Servir la API HTTP+JSON y el AgentCard en el mismo puerto
 	srv := &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: httpjson.NewHandler(handler),
 	}
 
 	fmt.Printf("A2A Server listening on %s\n", addr)
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/jllopis/kairos/pkg/config"
+	klog "github.com/jllopis/kairos/pkg/log"
 	"github.com/jllopis/kairos/pkg/telemetry"
 )
 
@@ -33,7 +34,9 @@ func Init(serviceName, version string, cfg *config.Config) (telemetry.ShutdownFu
 	} else {
 		handler = slog.NewTextHandler(os.Stderr, opts)
 	}
-	slog.SetDefault(slog.New(handler))
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	klog.SetDefault(logger) // logs internos de Kairos (agent, mcp, planner...)
 
 	return telemetry.InitWithConfig(serviceName, version, telemetry.Config{
 		Exporter:           cfg.Telemetry.Exporter,
@@ -0,0 +1,25 @@
+// Package a2a defines the A2A client interface shared by every binding. The
+// implementations live in the client (gRPC), httpjson/client and
+// jsonrpc/client packages, so code written against Client works with any of
+// them.
+package a2a
+
+import (
+	"context"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+// Client is the set of A2A task operations available on every binding.
+//
+// Streaming methods deliver responses on a channel that is closed when the
+// stream ends or ctx is cancelled. Errors that end a stream are reported
+// through the binding's stream error handler option.
+type Client interface {
+	SendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error)
+	SendStreamingMessage(ctx context.Context, req *a2av1.SendMessageRequest) (<-chan *a2av1.StreamResponse, error)
+	GetTask(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av1.Task, error)
+	ListTasks(ctx context.Context, req *a2av1.ListTasksRequest) (*a2av1.ListTasksResponse, error)
+	CancelTask(ctx context.Context, req *a2av1.CancelTaskRequest) (*a2av1.Task, error)
+	SubscribeToTask(ctx context.Context, req *a2av1.SubscribeToTaskRequest) (<-chan *a2av1.StreamResponse, error)
+}
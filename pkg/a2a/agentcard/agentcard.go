@@ -1,7 +1,10 @@
 // Package agentcard builds and publishes A2A AgentCards.
 package agentcard
 
-import a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+import (
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/proto"
+)
 
 // Config describes AgentCard fields that can be derived from runtime settings.
 type Config struct {
@@ -18,12 +21,17 @@ type Config struct {
 	DefaultInputModes    []string
 	DefaultOutputModes   []string
 	Skills               []*a2av1.AgentSkill
+	SkillSchemas         map[string]SkillIO
 	SupportsExtendedCard bool
 	Provider             *a2av1.AgentProvider
 	Signatures           []*a2av1.AgentCardSignature
 }
 
 // Build assembles an AgentCard from the provided config.
+//
+// SkillSchemas, keyed by skill id, are published in the
+// SkillSchemaExtensionURI capability extension. Build panics if a schema
+// cannot be encoded as JSON.
 func Build(cfg Config) *a2av1.AgentCard {
 	protocolVersion := stringPtr(cfg.ProtocolVersion)
 	documentationURL := stringPtr(cfg.DocumentationURL)
@@ -33,6 +41,16 @@ func Build(cfg Config) *a2av1.AgentCard {
 		supportsExtended = boolPtr(true)
 	}
 
+	capabilities := cfg.Capabilities
+	if len(cfg.SkillSchemas) > 0 {
+		if capabilities == nil {
+			capabilities = &a2av1.AgentCapabilities{}
+		} else {
+			capabilities = proto.Clone(capabilities).(*a2av1.AgentCapabilities)
+		}
+		capabilities.Extensions = append(capabilities.Extensions, skillSchemasExtension(cfg.SkillSchemas))
+	}
+
 	return &a2av1.AgentCard{
 		ProtocolVersion:           protocolVersion,
 		Name:                      cfg.Name,
@@ -41,7 +59,7 @@ func Build(cfg Config) *a2av1.AgentCard {
 		DocumentationUrl:          documentationURL,
 		IconUrl:                   iconURL,
 		SupportedInterfaces:       cfg.SupportedInterfaces,
-		Capabilities:              cfg.Capabilities,
+		Capabilities:              capabilities,
 		SecuritySchemes:           cfg.SecuritySchemes,
 		Security:                  cfg.Security,
 		DefaultInputModes:         cfg.DefaultInputModes,
@@ -1,15 +1,20 @@
 package agentcard
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // Discovery constants for AgentCard HTTP endpoints.
@@ -21,6 +26,11 @@ const (
 )
 
 // PublishHandler serves the provided AgentCard as JSON.
+//
+// Responses carry an ETag derived from the encoded card, so clients sending
+// a matching If-None-Match get 304 Not Modified, and are gzip-compressed when
+// the client accepts it. The Content-Type is application/json unless the
+// client explicitly accepts DefaultMediaType.
 func PublishHandler(card *a2av1.AgentCard) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if card == nil {
@@ -32,43 +42,174 @@ func PublishHandler(card *a2av1.AgentCard) http.Handler {
 			http.Error(w, "failed to encode agent card", http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", DefaultMediaType)
+
+		sum := sha256.Sum256(payload)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept, Accept-Encoding")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		contentType := "application/json"
+		if strings.Contains(r.Header.Get("Accept"), DefaultMediaType) {
+			contentType = DefaultMediaType
+		}
+		w.Header().Set("Content-Type", contentType)
+
+		if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusOK)
+			gz := gzip.NewWriter(w)
+			_, _ = gz.Write(payload)
+			_ = gz.Close()
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write(payload)
 	})
 }
 
+// etagMatches reports whether an If-None-Match header matches etag,
+// using the weak comparison required for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		q := strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
+
+// FetchOption configures Fetch.
+type FetchOption func(*fetchOptions)
+
+type fetchOptions struct {
+	requireSignature bool
+	trustedKeys      TrustedKeys
+}
+
+// WithRequireSignature rejects cards that are unsigned or not signed by one
+// of keys.
+func WithRequireSignature(keys TrustedKeys) FetchOption {
+	return func(o *fetchOptions) {
+		o.requireSignature = true
+		o.trustedKeys = keys
+	}
+}
+
 // Fetch retrieves an AgentCard from a base URL.
-func Fetch(ctx context.Context, baseURL string) (*a2av1.AgentCard, error) {
+func Fetch(ctx context.Context, baseURL string, opts ...FetchOption) (*a2av1.AgentCard, error) {
+	card, _, err := fetch(ctx, baseURL, "", nil, newFetchOptions(opts))
+	return card, err
+}
+
+// Fetcher retrieves AgentCards and caches them by URL. Repeated fetches send
+// If-None-Match with the last ETag, so unchanged cards cost a 304 response
+// and are served from the cache. A Fetcher is safe for concurrent use.
+type Fetcher struct {
+	options fetchOptions
+
+	mu    sync.Mutex
+	cache map[string]cachedCard
+}
+
+type cachedCard struct {
+	card *a2av1.AgentCard
+	etag string
+}
+
+// NewFetcher creates a caching fetcher applying opts to every fetch.
+func NewFetcher(opts ...FetchOption) *Fetcher {
+	return &Fetcher{
+		options: newFetchOptions(opts),
+		cache:   make(map[string]cachedCard),
+	}
+}
+
+// Fetch retrieves the AgentCard at baseURL, revalidating a cached copy.
+func (f *Fetcher) Fetch(ctx context.Context, baseURL string) (*a2av1.AgentCard, error) {
+	f.mu.Lock()
+	cached := f.cache[baseURL]
+	f.mu.Unlock()
+
+	card, etag, err := fetch(ctx, baseURL, cached.etag, cached.card, f.options)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		f.mu.Lock()
+		f.cache[baseURL] = cachedCard{card: card, etag: etag}
+		f.mu.Unlock()
+	}
+	return proto.Clone(card).(*a2av1.AgentCard), nil
+}
+
+func newFetchOptions(opts []FetchOption) fetchOptions {
+	var options fetchOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// fetch retrieves a card, returning cached when the server answers 304 to
+// the conditional request for etag.
+func fetch(ctx context.Context, baseURL, etag string, cached *a2av1.AgentCard, options fetchOptions) (*a2av1.AgentCard, string, error) {
 	url := strings.TrimRight(baseURL, "/") + WellKnownPath
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	req.Header.Set("Accept", DefaultMediaType)
+	if etag != "" && cached != nil {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached, etag, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("agent card fetch failed: %s", resp.Status)
+		return nil, "", fmt.Errorf("agent card fetch failed: %s", resp.Status)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var card a2av1.AgentCard
-	if err := protojson.Unmarshal(body, &card); err == nil {
-		return &card, nil
+	if err := protojson.Unmarshal(body, &card); err != nil {
+		if err := json.Unmarshal(body, &card); err != nil {
+			return nil, "", err
+		}
 	}
 
-	if err := json.Unmarshal(body, &card); err != nil {
-		return nil, err
+	if options.requireSignature {
+		if err := Verify(&card, options.trustedKeys); err != nil {
+			return nil, "", fmt.Errorf("agent card from %s: %w", url, err)
+		}
 	}
-	return &card, nil
+	return &card, resp.Header.Get("ETag"), nil
 }
@@ -1,9 +1,12 @@
 package agentcard
 
 import (
+	"compress/gzip"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
@@ -34,14 +37,88 @@ func TestPublishHandler_ServesCard(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d", rec.Code)
 	}
-	if rec.Header().Get("Content-Type") != DefaultMediaType {
-		t.Fatalf("expected content type %q", DefaultMediaType)
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected content type %q, got %q", "application/json", rec.Header().Get("Content-Type"))
 	}
 	if rec.Body.Len() == 0 {
 		t.Fatalf("expected non-empty body")
 	}
 }
 
+func TestPublishHandler_A2AMediaType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+	req.Header.Set("Accept", DefaultMediaType)
+	rec := httptest.NewRecorder()
+
+	PublishHandler(&a2av1.AgentCard{Name: "demo-agent"}).ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Type") != DefaultMediaType {
+		t.Fatalf("expected content type %q, got %q", DefaultMediaType, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestPublishHandler_ETag(t *testing.T) {
+	handler := PublishHandler(&a2av1.AgentCard{Name: "demo-agent"})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, WellKnownPath, nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+	req.Header.Set("If-None-Match", `"stale", `+etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d with %d bytes", rec.Code, rec.Body.Len())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for stale ETag, got %d", rec.Code)
+	}
+}
+
+func TestPublishHandler_Gzip(t *testing.T) {
+	card := &a2av1.AgentCard{Name: "demo-agent", Description: strings.Repeat("large card ", 100)}
+	req := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	rec := httptest.NewRecorder()
+
+	PublishHandler(card).ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	var got a2av1.AgentCard
+	if err := protojson.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.GetDescription() != card.GetDescription() {
+		t.Fatalf("unexpected card after decompression")
+	}
+
+	req.Header.Set("Accept-Encoding", "gzip;q=0")
+	rec = httptest.NewRecorder()
+	PublishHandler(card).ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("gzip;q=0 must disable compression")
+	}
+}
+
 func TestFetch_Success(t *testing.T) {
 	card := &a2av1.AgentCard{
 		ProtocolVersion: strPtr("1.0"),
@@ -83,6 +160,39 @@ func TestFetch_NonOK(t *testing.T) {
 	}
 }
 
+func TestFetcher_RevalidatesWithETag(t *testing.T) {
+	handler := PublishHandler(&a2av1.AgentCard{Name: "demo-agent"})
+	var notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		if rec.Code == http.StatusNotModified {
+			notModified++
+		}
+		for key, values := range rec.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher()
+	for i := 0; i < 3; i++ {
+		got, err := fetcher.Fetch(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Fetch %d error: %v", i, err)
+		}
+		if got.GetName() != "demo-agent" {
+			t.Fatalf("expected name %q, got %q", "demo-agent", got.GetName())
+		}
+		got.Name = "mutated"
+	}
+	if notModified != 2 {
+		t.Fatalf("expected 2 revalidated fetches, got %d", notModified)
+	}
+}
+
 func strPtr(value string) *string {
 	return &value
 }
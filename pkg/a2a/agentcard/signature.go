@@ -0,0 +1,257 @@
+package agentcard
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Signature errors returned by Verify.
+var (
+	// ErrUnsigned is returned when a card carries no signatures.
+	ErrUnsigned = errors.New("agent card is not signed")
+	// ErrInvalidSignature is returned when no signature verifies against a trusted key.
+	ErrInvalidSignature = errors.New("agent card signature is invalid")
+	// ErrUntrustedKey is returned when signatures reference only unknown keys.
+	ErrUntrustedKey = errors.New("agent card is signed by an untrusted key")
+)
+
+// algEdDSA is the JWS algorithm identifier for Ed25519 signatures (RFC 8037).
+const algEdDSA = "EdDSA"
+
+// TrustedKeys maps key IDs to the Ed25519 public keys allowed to sign cards.
+type TrustedKeys map[string]ed25519.PublicKey
+
+// jwsHeader is the protected header of a card signature.
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Jku string `json:"jku,omitempty"`
+}
+
+// SignOption configures Sign.
+type SignOption func(*jwsHeader)
+
+// WithKeyID sets the "kid" header so verifiers can select the right key.
+func WithKeyID(kid string) SignOption {
+	return func(h *jwsHeader) {
+		h.Kid = kid
+	}
+}
+
+// WithJWKSURL sets the "jku" header pointing to the JWKS that publishes the
+// signing key.
+func WithJWKSURL(url string) SignOption {
+	return func(h *jwsHeader) {
+		h.Jku = url
+	}
+}
+
+// Sign appends a detached JWS over the card to card.Signatures.
+//
+// The payload is the canonical JSON encoding of the card without its
+// signatures, so a card may carry signatures from several keys.
+func Sign(card *a2av1.AgentCard, key ed25519.PrivateKey, opts ...SignOption) error {
+	if card == nil {
+		return errors.New("agent card is nil")
+	}
+	if len(key) != ed25519.PrivateKeySize {
+		return errors.New("invalid ed25519 private key")
+	}
+	header := jwsHeader{Alg: algEdDSA, Typ: "JOSE"}
+	for _, opt := range opts {
+		opt(&header)
+	}
+
+	payload, err := canonicalPayload(card)
+	if err != nil {
+		return err
+	}
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(rawHeader)
+	sig := ed25519.Sign(key, signingInput(protected, payload))
+
+	card.Signatures = append(card.Signatures, &a2av1.AgentCardSignature{
+		Protected: protected,
+		Signature: base64.RawURLEncoding.EncodeToString(sig),
+	})
+	return nil
+}
+
+// Verify checks that at least one signature on card was produced by a key in
+// trustedKeys. Signatures without a "kid" are tried against every key.
+func Verify(card *a2av1.AgentCard, trustedKeys TrustedKeys) error {
+	if card == nil {
+		return errors.New("agent card is nil")
+	}
+	if len(card.GetSignatures()) == 0 {
+		return ErrUnsigned
+	}
+	payload, err := canonicalPayload(card)
+	if err != nil {
+		return err
+	}
+
+	untrusted := true
+	for _, s := range card.GetSignatures() {
+		header, err := decodeHeader(s.GetProtected())
+		if err != nil || header.Alg != algEdDSA {
+			continue
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(s.GetSignature())
+		if err != nil {
+			continue
+		}
+		input := signingInput(s.GetProtected(), payload)
+		for kid, key := range trustedKeys {
+			if header.Kid != "" && header.Kid != kid {
+				continue
+			}
+			untrusted = false
+			if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, input, sig) {
+				return nil
+			}
+		}
+	}
+	if untrusted {
+		return ErrUntrustedKey
+	}
+	return ErrInvalidSignature
+}
+
+// canonicalPayload returns the JSON encoding of card without signatures,
+// with object keys sorted so signer and verifier agree byte for byte.
+func canonicalPayload(card *a2av1.AgentCard) ([]byte, error) {
+	unsigned := proto.Clone(card).(*a2av1.AgentCard)
+	unsigned.Signatures = nil
+	raw, err := protojson.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode agent card: %w", err)
+	}
+	// protojson output is not stable; round-trip through encoding/json,
+	// which sorts map keys, to get a canonical form.
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func signingInput(protected string, payload []byte) []byte {
+	return []byte(protected + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
+
+func decodeHeader(protected string) (jwsHeader, error) {
+	var header jwsHeader
+	raw, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return header, err
+	}
+	err = json.Unmarshal(raw, &header)
+	return header, err
+}
+
+// jwk is the subset of RFC 7517/8037 fields used for Ed25519 keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// MarshalJWKS encodes keys as a JSON Web Key Set.
+func MarshalJWKS(keys TrustedKeys) ([]byte, error) {
+	set := jwks{Keys: make([]jwk, 0, len(keys))}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+			Kid: kid,
+			Use: "sig",
+			Alg: algEdDSA,
+		})
+	}
+	return json.Marshal(set)
+}
+
+// ParseJWKS decodes the Ed25519 keys of a JSON Web Key Set. Other key
+// types are ignored.
+func ParseJWKS(data []byte) (TrustedKeys, error) {
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+	keys := make(TrustedKeys, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "OKP" || k.Crv != "Ed25519" {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("decode jwks: invalid key %q", k.Kid)
+		}
+		keys[k.Kid] = ed25519.PublicKey(x)
+	}
+	return keys, nil
+}
+
+// FetchJWKS retrieves trusted keys from a JWKS URL.
+func FetchJWKS(ctx context.Context, url string) (TrustedKeys, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks fetch failed: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(body)
+}
+
+// JWKSHandler serves keys as a JSON Web Key Set.
+func JWKSHandler(keys TrustedKeys) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := MarshalJWKS(keys)
+		if err != nil {
+			http.Error(w, "failed to encode jwks", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(payload)
+	})
+}
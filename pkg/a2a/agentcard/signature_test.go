@@ -0,0 +1,92 @@
+package agentcard
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestSignVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	card := Build(Config{Name: "demo-agent", Version: "1.0.0", Description: "demo"})
+	if err := Sign(card, priv, WithKeyID("k1")); err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	if len(card.GetSignatures()) != 1 {
+		t.Fatalf("expected 1 signature, got %d", len(card.GetSignatures()))
+	}
+
+	// Round-trip through the wire format before verifying.
+	payload, err := protojson.Marshal(card)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	var received a2av1.AgentCard
+	if err := protojson.Unmarshal(payload, &received); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if err := Verify(&received, TrustedKeys{"k1": pub}); err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+
+	received.Description = "tampered"
+	if err := Verify(&received, TrustedKeys{"k1": pub}); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if err := Verify(card, TrustedKeys{"k2": otherPub}); !errors.Is(err, ErrUntrustedKey) {
+		t.Fatalf("expected ErrUntrustedKey, got %v", err)
+	}
+	if err := Verify(Build(Config{Name: "unsigned"}), TrustedKeys{"k1": pub}); !errors.Is(err, ErrUnsigned) {
+		t.Fatalf("expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestFetch_RequireSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	signed := Build(Config{Name: "signed-agent"})
+	if err := Sign(signed, priv, WithKeyID("k1")); err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	unsigned := Build(Config{Name: "unsigned-agent"})
+
+	mux := http.NewServeMux()
+	mux.Handle("/signed"+WellKnownPath, PublishHandler(signed))
+	mux.Handle("/unsigned"+WellKnownPath, PublishHandler(unsigned))
+	mux.Handle("/jwks.json", JWKSHandler(TrustedKeys{"k1": pub}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	keys, err := FetchJWKS(context.Background(), server.URL+"/jwks.json")
+	if err != nil {
+		t.Fatalf("FetchJWKS error: %v", err)
+	}
+	if len(keys) != 1 || !keys["k1"].Equal(pub) {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	card, err := Fetch(context.Background(), server.URL+"/signed", WithRequireSignature(keys))
+	if err != nil {
+		t.Fatalf("Fetch signed error: %v", err)
+	}
+	if card.GetName() != "signed-agent" {
+		t.Fatalf("unexpected card %q", card.GetName())
+	}
+
+	if _, err := Fetch(context.Background(), server.URL+"/unsigned", WithRequireSignature(keys)); !errors.Is(err, ErrUnsigned) {
+		t.Fatalf("expected ErrUnsigned, got %v", err)
+	}
+	if _, err := Fetch(context.Background(), server.URL+"/unsigned"); err != nil {
+		t.Fatalf("unsigned cards must be accepted by default: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package agentcard
+
+import (
+	"encoding/json"
+	"fmt"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SkillSchemaExtensionURI identifies the AgentCard extension that carries
+// the input/output JSON schemas of the agent skills. AgentSkill has no
+// metadata field, so the schemas travel in the extension params keyed by
+// skill id:
+//
+//	{"skills": {"<skill id>": {"input_schema": {...}, "output_schema": {...}}}}
+const SkillSchemaExtensionURI = "https://github.com/jllopis/kairos/a2a/extensions/skill-schemas/v1"
+
+// SkillIO describes how to invoke a skill. Input and Output are JSON
+// schemas; either may be nil.
+type SkillIO struct {
+	Input  map[string]any
+	Output map[string]any
+}
+
+// skillSchemasExtension builds the skill schema extension. It panics if a
+// schema cannot be encoded as JSON, which is a programming error.
+func skillSchemasExtension(schemas map[string]SkillIO) *a2av1.AgentExtension {
+	skills := make(map[string]any, len(schemas))
+	for id, schema := range schemas {
+		entry := map[string]any{}
+		if schema.Input != nil {
+			entry["input_schema"] = jsonValue(id, schema.Input)
+		}
+		if schema.Output != nil {
+			entry["output_schema"] = jsonValue(id, schema.Output)
+		}
+		skills[id] = entry
+	}
+	params, err := structpb.NewStruct(map[string]any{"skills": skills})
+	if err != nil {
+		panic(fmt.Sprintf("agentcard: skill schemas: %v", err))
+	}
+	return &a2av1.AgentExtension{
+		Uri:         SkillSchemaExtensionURI,
+		Description: "Input and output JSON schemas of the agent skills.",
+		Params:      params,
+	}
+}
+
+// jsonValue round-trips schema through JSON so it only holds the types
+// structpb accepts.
+func jsonValue(skillID string, schema map[string]any) map[string]any {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		panic(fmt.Sprintf("agentcard: schema of skill %q: %v", skillID, err))
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		panic(fmt.Sprintf("agentcard: schema of skill %q: %v", skillID, err))
+	}
+	return out
+}
+
+// SkillSchema returns the input/output schemas the card publishes for
+// skillID. ok is false when the card carries no schema for the skill.
+func SkillSchema(card *a2av1.AgentCard, skillID string) (schema SkillIO, ok bool) {
+	for _, ext := range card.GetCapabilities().GetExtensions() {
+		if ext.GetUri() != SkillSchemaExtensionURI {
+			continue
+		}
+		skills := ext.GetParams().GetFields()["skills"].GetStructValue()
+		entry := skills.GetFields()[skillID].GetStructValue()
+		if entry == nil {
+			return SkillIO{}, false
+		}
+		if input := entry.GetFields()["input_schema"].GetStructValue(); input != nil {
+			schema.Input = input.AsMap()
+		}
+		if output := entry.GetFields()["output_schema"].GetStructValue(); output != nil {
+			schema.Output = output.AsMap()
+		}
+		return schema, true
+	}
+	return SkillIO{}, false
+}
@@ -0,0 +1,70 @@
+package agentcard
+
+import (
+	"reflect"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestSkillSchemaRoundTrip(t *testing.T) {
+	input := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"sheet": map[string]any{"type": "string"},
+			"limit": map[string]any{"type": "integer", "minimum": 1},
+		},
+		"required": []string{"sheet"},
+	}
+	output := map[string]any{"type": "array", "items": map[string]any{"type": "object"}}
+	capabilities := &a2av1.AgentCapabilities{Extensions: []*a2av1.AgentExtension{{Uri: "urn:other"}}}
+
+	card := Build(Config{
+		Name:         "spreadsheet",
+		Capabilities: capabilities,
+		Skills:       []*a2av1.AgentSkill{{Id: "query_spreadsheet", Name: "Query spreadsheet"}},
+		SkillSchemas: map[string]SkillIO{
+			"query_spreadsheet": {Input: input, Output: output},
+		},
+	})
+	if len(capabilities.Extensions) != 1 {
+		t.Fatalf("Build modified the configured capabilities")
+	}
+
+	// Clients read the card after a JSON round trip.
+	payload, err := protojson.Marshal(card)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	fetched := &a2av1.AgentCard{}
+	if err := protojson.Unmarshal(payload, fetched); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	schema, ok := SkillSchema(fetched, "query_spreadsheet")
+	if !ok {
+		t.Fatal("expected a schema for query_spreadsheet")
+	}
+	wantInput := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"sheet": map[string]any{"type": "string"},
+			"limit": map[string]any{"type": "integer", "minimum": float64(1)},
+		},
+		"required": []any{"sheet"},
+	}
+	if !reflect.DeepEqual(schema.Input, wantInput) {
+		t.Fatalf("unexpected input schema: %#v", schema.Input)
+	}
+	if !reflect.DeepEqual(schema.Output, map[string]any{"type": "array", "items": map[string]any{"type": "object"}}) {
+		t.Fatalf("unexpected output schema: %#v", schema.Output)
+	}
+
+	if _, ok := SkillSchema(fetched, "missing"); ok {
+		t.Fatal("expected no schema for an unknown skill")
+	}
+	if _, ok := SkillSchema(Build(Config{Name: "plain"}), "query_spreadsheet"); ok {
+		t.Fatal("expected no schema on a card without the extension")
+	}
+}
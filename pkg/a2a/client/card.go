@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/a2a"
+	httpjson "github.com/jllopis/kairos/pkg/a2a/httpjson/client"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Protocol bindings understood by FromCard. Bindings are matched
+// case-insensitively against AgentInterface.ProtocolBinding.
+const (
+	BindingGRPC     = "GRPC"
+	BindingHTTPJSON = "HTTP+JSON"
+)
+
+// ErrNoSupportedInterface is returned by FromCard when the card advertises
+// no interface with a binding it can use.
+var ErrNoSupportedInterface = errors.New("agent card has no supported interface")
+
+// AgentClient is the client returned by FromCard: an a2a.Client that also
+// reports its binding and owns its connection.
+type AgentClient interface {
+	a2a.Client
+	GetExtendedAgentCard(ctx context.Context, req *a2av1.GetExtendedAgentCardRequest) (*a2av1.AgentCard, error)
+	// Binding returns the protocol binding in use.
+	Binding() string
+	// Close releases the underlying connection.
+	Close() error
+}
+
+// CardOption configures FromCard.
+type CardOption func(*cardOptions)
+
+type cardOptions struct {
+	grpcOptions   []Option
+	dialOptions   []grpc.DialOption
+	httpOptions   []httpjson.Option
+	onStreamError func(error)
+}
+
+// WithGRPCOptions passes options to the gRPC client.
+func WithGRPCOptions(opts ...Option) CardOption {
+	return func(o *cardOptions) {
+		o.grpcOptions = append(o.grpcOptions, opts...)
+	}
+}
+
+// WithDialOptions adds gRPC dial options. Transport credentials default to
+// TLS for grpcs:// and https:// URLs and to insecure otherwise.
+func WithDialOptions(opts ...grpc.DialOption) CardOption {
+	return func(o *cardOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// WithHTTPOptions passes options to the HTTP+JSON client.
+func WithHTTPOptions(opts ...httpjson.Option) CardOption {
+	return func(o *cardOptions) {
+		o.httpOptions = append(o.httpOptions, opts...)
+	}
+}
+
+// WithCardStreamErrorHandler registers fn to be called when a stream ends
+// with an error, whatever the binding.
+func WithCardStreamErrorHandler(fn func(error)) CardOption {
+	return func(o *cardOptions) {
+		o.onStreamError = fn
+	}
+}
+
+// FromCard returns a client for the agent described by card. It prefers a
+// GRPC interface and falls back to HTTP+JSON; other bindings are ignored.
+// Interfaces whose URL cannot be used are skipped, and the errors are
+// reported along with ErrNoSupportedInterface when none is left.
+//
+// gRPC connections are created lazily, so FromCard does not check that the
+// agent is reachable.
+func FromCard(ctx context.Context, card *a2av1.AgentCard, opts ...CardOption) (AgentClient, error) {
+	if card == nil {
+		return nil, errors.New("agent card is required")
+	}
+	var options cardOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	var errs []error
+	for _, iface := range interfacesByBinding(card, BindingGRPC) {
+		c, err := dialCardGRPC(iface, options)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return c, nil
+	}
+	for _, iface := range interfacesByBinding(card, BindingHTTPJSON) {
+		if strings.TrimSpace(iface.GetUrl()) == "" {
+			errs = append(errs, fmt.Errorf("%s interface has no url", BindingHTTPJSON))
+			continue
+		}
+		httpOpts := options.httpOptions
+		if options.onStreamError != nil {
+			httpOpts = append(httpOpts[:len(httpOpts):len(httpOpts)], httpjson.WithStreamErrorHandler(options.onStreamError))
+		}
+		return &httpAgentClient{Client: httpjson.New(iface.GetUrl(), httpOpts...)}, nil
+	}
+	return nil, errors.Join(append([]error{fmt.Errorf("%w %q", ErrNoSupportedInterface, card.GetName())}, errs...)...)
+}
+
+// interfacesByBinding returns the card interfaces with the given binding,
+// in card order.
+func interfacesByBinding(card *a2av1.AgentCard, binding string) []*a2av1.AgentInterface {
+	var out []*a2av1.AgentInterface
+	for _, iface := range card.GetSupportedInterfaces() {
+		if strings.EqualFold(strings.TrimSpace(iface.GetProtocolBinding()), binding) {
+			out = append(out, iface)
+		}
+	}
+	return out
+}
+
+// dialCardGRPC creates a gRPC client for iface.
+func dialCardGRPC(iface *a2av1.AgentInterface, options cardOptions) (AgentClient, error) {
+	target, secure, err := grpcTarget(iface.GetUrl())
+	if err != nil {
+		return nil, err
+	}
+	creds := insecure.NewCredentials()
+	if secure {
+		creds = credentials.NewTLS(nil)
+	}
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, options.dialOptions...)
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s interface %q: %w", BindingGRPC, iface.GetUrl(), err)
+	}
+	grpcOpts := options.grpcOptions
+	if options.onStreamError != nil {
+		grpcOpts = append(grpcOpts[:len(grpcOpts):len(grpcOpts)], WithStreamErrorHandler(options.onStreamError))
+	}
+	return &grpcAgentClient{
+		unifiedClient: unifiedClient{client: New(conn, grpcOpts...)},
+		conn:          conn,
+	}, nil
+}
+
+// grpcTarget converts an interface URL into a gRPC target. URLs without a
+// scheme are used as host:port.
+func grpcTarget(raw string) (target string, secure bool, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false, fmt.Errorf("%s interface has no url", BindingGRPC)
+	}
+	if !strings.Contains(raw, "://") {
+		return raw, false, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false, fmt.Errorf("%s interface %q: %w", BindingGRPC, raw, err)
+	}
+	if u.Host == "" {
+		return "", false, fmt.Errorf("%s interface %q has no host", BindingGRPC, raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "grpc", "http":
+		return u.Host, false, nil
+	case "grpcs", "https":
+		return u.Host, true, nil
+	default:
+		return "", false, fmt.Errorf("%s interface %q has unsupported scheme %q", BindingGRPC, raw, u.Scheme)
+	}
+}
+
+// grpcAgentClient adapts Client to AgentClient and owns its connection.
+type grpcAgentClient struct {
+	unifiedClient
+	conn *grpc.ClientConn
+}
+
+func (c *grpcAgentClient) GetExtendedAgentCard(ctx context.Context, req *a2av1.GetExtendedAgentCardRequest) (*a2av1.AgentCard, error) {
+	return c.client.GetExtendedAgentCard(ctx, req)
+}
+
+func (c *grpcAgentClient) Binding() string { return BindingGRPC }
+
+func (c *grpcAgentClient) Close() error { return c.conn.Close() }
+
+// httpAgentClient adapts the HTTP+JSON client to AgentClient.
+type httpAgentClient struct {
+	*httpjson.Client
+}
+
+func (c *httpAgentClient) Binding() string { return BindingHTTPJSON }
+
+func (c *httpAgentClient) Close() error { return nil }
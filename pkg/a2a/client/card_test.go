@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+)
+
+func newTCPServer(t *testing.T, server *testServer) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	a2av1.RegisterA2AServiceServer(grpcServer, server)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	return listener.Addr().String()
+}
+
+func newHTTPServer(t *testing.T, calls *int32) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/message:send" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestFromCard_PrefersGRPC(t *testing.T) {
+	server := &testServer{}
+	addr := newTCPServer(t, server)
+	var httpCalls int32
+	httpURL := newHTTPServer(t, &httpCalls)
+
+	card := &a2av1.AgentCard{
+		Name: "agent",
+		SupportedInterfaces: []*a2av1.AgentInterface{
+			{Url: httpURL, ProtocolBinding: "HTTP+JSON"},
+			{Url: "grpc://" + addr, ProtocolBinding: "grpc"},
+		},
+	}
+	c, err := FromCard(context.Background(), card)
+	if err != nil {
+		t.Fatalf("FromCard error: %v", err)
+	}
+	defer c.Close()
+	if c.Binding() != BindingGRPC {
+		t.Fatalf("expected %s binding, got %s", BindingGRPC, c.Binding())
+	}
+	if _, err := c.SendMessage(context.Background(), &a2av1.SendMessageRequest{}); err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	grpcCalls, httpCallCount := atomic.LoadInt32(&server.attempts), atomic.LoadInt32(&httpCalls)
+	if grpcCalls != 1 || httpCallCount != 0 {
+		t.Fatalf("expected the call over gRPC, got grpc=%d http=%d", grpcCalls, httpCallCount)
+	}
+
+	events, err := c.SendStreamingMessage(context.Background(), &a2av1.SendMessageRequest{})
+	if err != nil {
+		t.Fatalf("SendStreamingMessage error: %v", err)
+	}
+	var received int
+	for range events {
+		received++
+	}
+	if received != 1 {
+		t.Fatalf("expected 1 stream event, got %d", received)
+	}
+}
+
+func TestFromCard_FallsBackToHTTP(t *testing.T) {
+	var httpCalls int32
+	httpURL := newHTTPServer(t, &httpCalls)
+
+	card := &a2av1.AgentCard{
+		Name: "agent",
+		SupportedInterfaces: []*a2av1.AgentInterface{
+			{Url: "ftp://example.com", ProtocolBinding: "GRPC"},
+			{Url: "https://example.com/rpc", ProtocolBinding: "JSONRPC"},
+			{Url: httpURL, ProtocolBinding: "http+json"},
+		},
+	}
+	c, err := FromCard(context.Background(), card)
+	if err != nil {
+		t.Fatalf("FromCard error: %v", err)
+	}
+	defer c.Close()
+	if c.Binding() != BindingHTTPJSON {
+		t.Fatalf("expected %s binding, got %s", BindingHTTPJSON, c.Binding())
+	}
+	if _, err := c.SendMessage(context.Background(), &a2av1.SendMessageRequest{}); err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	if got := atomic.LoadInt32(&httpCalls); got != 1 {
+		t.Fatalf("expected 1 HTTP call, got %d", got)
+	}
+}
+
+func TestFromCard_NoSupportedInterface(t *testing.T) {
+	card := &a2av1.AgentCard{
+		Name: "agent",
+		SupportedInterfaces: []*a2av1.AgentInterface{
+			{Url: "https://example.com/rpc", ProtocolBinding: "JSONRPC"},
+			{Url: "", ProtocolBinding: "GRPC"},
+		},
+	}
+	_, err := FromCard(context.Background(), card)
+	if !errors.Is(err, ErrNoSupportedInterface) {
+		t.Fatalf("expected ErrNoSupportedInterface, got %v", err)
+	}
+}
+
+func TestGRPCTarget(t *testing.T) {
+	cases := []struct {
+		url    string
+		target string
+		secure bool
+	}{
+		{url: "localhost:9030", target: "localhost:9030"},
+		{url: "grpc://localhost:9030", target: "localhost:9030"},
+		{url: "grpcs://agent.example.com:443", target: "agent.example.com:443", secure: true},
+		{url: "https://agent.example.com", target: "agent.example.com", secure: true},
+	}
+	for _, tc := range cases {
+		target, secure, err := grpcTarget(tc.url)
+		if err != nil {
+			t.Fatalf("grpcTarget(%q) error: %v", tc.url, err)
+		}
+		if target != tc.target || secure != tc.secure {
+			t.Fatalf("grpcTarget(%q) = %q, %v; want %q, %v", tc.url, target, secure, tc.target, tc.secure)
+		}
+	}
+}
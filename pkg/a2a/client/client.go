@@ -8,6 +8,7 @@ import (
 
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"github.com/jllopis/kairos/pkg/core"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
 	"github.com/jllopis/kairos/pkg/governance"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -21,18 +22,21 @@ type Option func(*Client)
 
 // Client wraps the generated A2A gRPC client.
 type Client struct {
-	raw          a2av1.A2AServiceClient
-	timeout      time.Duration
-	retries      int
-	policyEngine governance.PolicyEngine
-	agentName    string
-	eventEmitter core.EventEmitter
+	raw           a2av1.A2AServiceClient
+	timeout       time.Duration
+	retries       int
+	policyEngine  governance.PolicyEngine
+	agentName     string
+	eventEmitter  core.EventEmitter
+	onStreamError func(error)
+	creds         credentials.PerRPCCredentials
+	unary         []grpc.UnaryClientInterceptor
+	stream        []grpc.StreamClientInterceptor
 }
 
 // New creates a client from an existing gRPC connection.
 func New(conn grpc.ClientConnInterface, opts ...Option) *Client {
 	client := &Client{
-		raw:     a2av1.NewA2AServiceClient(conn),
 		timeout: 10 * time.Second,
 		retries: 0,
 	}
@@ -41,6 +45,13 @@ func New(conn grpc.ClientConnInterface, opts ...Option) *Client {
 			opt(client)
 		}
 	}
+	if len(client.unary) > 0 || len(client.stream) > 0 {
+		conn = &interceptedConn{conn: conn, unary: client.unary, stream: client.stream}
+	}
+	client.raw = a2av1.NewA2AServiceClient(conn)
+	if client.creds != nil {
+		client.raw = &authClient{inner: client.raw, creds: client.creds}
+	}
 	return client
 }
 
@@ -53,7 +64,8 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
-// WithRetries sets the number of retries for unary calls.
+// WithRetries sets the number of retries for unary calls. Only recoverable
+// failures are retried (see errors.FromStatus).
 func WithRetries(retries int) Option {
 	return func(c *Client) {
 		if retries >= 0 {
@@ -77,6 +89,8 @@ func WithAgentName(name string) Option {
 }
 
 // WithEventEmitter attaches a semantic event emitter for delegation events.
+// Without one, delegation events go to the emitter carried by the request
+// context (see core.WithEventEmitter), such as the calling agent's.
 func WithEventEmitter(emitter core.EventEmitter) Option {
 	return func(c *Client) {
 		if emitter != nil {
@@ -106,7 +120,7 @@ func WithCredentials(creds credentials.PerRPCCredentials) Option {
 		if creds == nil {
 			return
 		}
-		c.raw = &authClient{inner: c.raw, creds: creds}
+		c.creds = creds
 	}
 }
 
@@ -189,7 +203,8 @@ func (c *Client) SendStreamingMessage(ctx context.Context, req *a2av1.SendMessag
 		return nil, err
 	}
 	c.emitDelegation(ctx, "SendStreamingMessage", req)
-	return c.raw.SendStreamingMessage(injectTraceContext(c.streamContext(ctx)), req, opts...)
+	stream, err := c.raw.SendStreamingMessage(injectTraceContext(c.streamContext(ctx)), req, opts...)
+	return stream, kerrors.FromGRPCError(err)
 }
 
 // GetTask forwards to the A2A GetTask RPC.
@@ -233,7 +248,8 @@ func (c *Client) SubscribeToTask(ctx context.Context, req *a2av1.SubscribeToTask
 	if err := c.ensureAllowed(ctx, "SubscribeToTask"); err != nil {
 		return nil, err
 	}
-	return c.raw.SubscribeToTask(injectTraceContext(c.streamContext(ctx)), req, opts...)
+	stream, err := c.raw.SubscribeToTask(injectTraceContext(c.streamContext(ctx)), req, opts...)
+	return stream, kerrors.FromGRPCError(err)
 }
 
 // GetExtendedAgentCard forwards to the A2A GetExtendedAgentCard RPC.
@@ -296,6 +312,8 @@ func (c *Client) DeleteTaskPushNotificationConfig(ctx context.Context, req *a2av
 	})
 }
 
+// withRetries runs fn until it succeeds, fails with an error that is not
+// recoverable, or runs out of retries. Errors are returned as KairosErrors.
 func withRetries[T any](retries int, fn func() (*T, error)) (*T, error) {
 	var lastErr error
 	for attempt := 0; attempt <= retries; attempt++ {
@@ -303,7 +321,10 @@ func withRetries[T any](retries int, fn func() (*T, error)) (*T, error) {
 		if err == nil {
 			return result, nil
 		}
-		lastErr = err
+		lastErr = kerrors.FromGRPCError(err)
+		if !kerrors.IsRecoverable(lastErr) {
+			break
+		}
 	}
 	return nil, lastErr
 }
@@ -317,8 +338,9 @@ func (c *Client) ensureAllowed(ctx context.Context, method string) error {
 		name = method
 	}
 	decision := c.policyEngine.Evaluate(ctx, governance.Action{
-		Type: governance.ActionAgent,
-		Name: name,
+		Type:      governance.ActionAgent,
+		Name:      name,
+		Principal: governance.PrincipalFromContext(ctx),
 		Metadata: map[string]string{
 			"method": method,
 		},
@@ -338,8 +360,13 @@ func (c *Client) ensureAllowed(ctx context.Context, method string) error {
 }
 
 func (c *Client) emitDelegation(ctx context.Context, method string, req *a2av1.SendMessageRequest) {
-	if c.eventEmitter == nil {
-		return
+	emitter := c.eventEmitter
+	if emitter == nil {
+		// Fall back to the emitter of the run calling this client, if any.
+		var ok bool
+		if emitter, ok = core.EventEmitterFromContext(ctx); !ok {
+			return
+		}
 	}
 	var (
 		taskID    string
@@ -362,5 +389,5 @@ func (c *Client) emitDelegation(ctx context.Context, method string, req *a2av1.S
 		payload["task_goal"] = task.Goal
 	}
 	agent := strings.TrimSpace(c.agentName)
-	c.eventEmitter.Emit(ctx, core.NewEvent(core.EventAgentDelegation, agent, taskID, payload))
+	emitter.Emit(ctx, core.NewEvent(core.EventAgentDelegation, agent, taskID, payload))
 }
@@ -2,12 +2,14 @@ package client
 
 import (
 	"context"
+	"errors"
 	"net"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -20,6 +22,7 @@ const bufSize = 1024 * 1024
 type testServer struct {
 	a2av1.UnimplementedA2AServiceServer
 	failFor     int32
+	failCode    codes.Code
 	sleep       time.Duration
 	streamSleep time.Duration
 	attempts    int32
@@ -28,6 +31,9 @@ type testServer struct {
 func (s *testServer) SendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
 	attempt := atomic.AddInt32(&s.attempts, 1)
 	if attempt <= s.failFor {
+		if s.failCode != codes.OK {
+			return nil, status.Error(s.failCode, "request failed")
+		}
 		return nil, status.Error(codes.Unavailable, "try again")
 	}
 	if s.sleep > 0 {
@@ -62,7 +68,7 @@ func (s *testServer) SubscribeToTask(req *a2av1.SubscribeToTaskRequest, stream a
 	return stream.Send(&a2av1.StreamResponse{})
 }
 
-func newTestClient(t *testing.T, server *testServer) (grpc.ClientConnInterface, func()) {
+func newTestClient(t *testing.T, server a2av1.A2AServiceServer) (grpc.ClientConnInterface, func()) {
 	t.Helper()
 
 	listener := bufconn.Listen(bufSize)
@@ -120,6 +126,28 @@ func TestClientRetries_Exhausted(t *testing.T) {
 	}
 }
 
+func TestClientRetries_SkipsNonRecoverable(t *testing.T) {
+	server := &testServer{failFor: 3, failCode: codes.NotFound}
+	conn, cleanup := newTestClient(t, server)
+	defer cleanup()
+
+	client := New(conn, WithRetries(2))
+	_, err := client.SendMessage(context.Background(), &a2av1.SendMessageRequest{})
+	if got := atomic.LoadInt32(&server.attempts); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+	var ke *kerrors.KairosError
+	if !errors.As(err, &ke) {
+		t.Fatalf("expected KairosError, got %T", err)
+	}
+	if ke.Code != kerrors.CodeNotFound || ke.Recoverable {
+		t.Fatalf("unexpected error %+v", ke)
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", status.Code(err))
+	}
+}
+
 func TestClientTimeout(t *testing.T) {
 	server := &testServer{sleep: 200 * time.Millisecond}
 	conn, cleanup := newTestClient(t, server)
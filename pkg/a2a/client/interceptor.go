@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// WithUnaryInterceptor adds interceptors to the unary calls of the client.
+// Interceptors run in the order they are added, across calls to this
+// option, so the first one sees the call first.
+func WithUnaryInterceptor(interceptors ...grpc.UnaryClientInterceptor) Option {
+	return func(c *Client) {
+		for _, interceptor := range interceptors {
+			if interceptor != nil {
+				c.unary = append(c.unary, interceptor)
+			}
+		}
+	}
+}
+
+// WithStreamInterceptor adds interceptors to the streaming calls of the
+// client, in the same order as WithUnaryInterceptor.
+func WithStreamInterceptor(interceptors ...grpc.StreamClientInterceptor) Option {
+	return func(c *Client) {
+		for _, interceptor := range interceptors {
+			if interceptor != nil {
+				c.stream = append(c.stream, interceptor)
+			}
+		}
+	}
+}
+
+// WithMetadata sends md as outgoing metadata on every call. It is added as
+// an interceptor, so it composes in order with the others.
+func WithMetadata(md metadata.MD) Option {
+	pairs := make([]string, 0, 2*md.Len())
+	for key, values := range md {
+		for _, value := range values {
+			pairs = append(pairs, key, value)
+		}
+	}
+	return metadataOption(pairs)
+}
+
+// BearerToken sends "authorization: Bearer <token>" on every call.
+func BearerToken(token string) Option {
+	return metadataOption([]string{"authorization", "Bearer " + token})
+}
+
+// metadataOption appends pairs to the outgoing metadata of every call.
+func metadataOption(pairs []string) Option {
+	return func(c *Client) {
+		if len(pairs) == 0 {
+			return
+		}
+		c.unary = append(c.unary, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, pairs...), method, req, reply, cc, opts...)
+		})
+		c.stream = append(c.stream, func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(metadata.AppendToOutgoingContext(ctx, pairs...), desc, cc, method, opts...)
+		})
+	}
+}
+
+// interceptedConn runs the client interceptors around a connection. The
+// interceptors receive the underlying *grpc.ClientConn, or nil when the
+// client was built on another grpc.ClientConnInterface.
+type interceptedConn struct {
+	conn   grpc.ClientConnInterface
+	unary  []grpc.UnaryClientInterceptor
+	stream []grpc.StreamClientInterceptor
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *interceptedConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	invoker := func(ctx context.Context, method string, req, reply any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return c.conn.Invoke(ctx, method, req, reply, opts...)
+	}
+	for i := len(c.unary) - 1; i >= 0; i-- {
+		interceptor, next := c.unary[i], invoker
+		invoker = func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return interceptor(ctx, method, req, reply, cc, next, opts...)
+		}
+	}
+	return invoker(ctx, method, args, reply, c.clientConn(), opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *interceptedConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return c.conn.NewStream(ctx, desc, method, opts...)
+	}
+	for i := len(c.stream) - 1; i >= 0; i-- {
+		interceptor, next := c.stream[i], streamer
+		streamer = func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return interceptor(ctx, desc, cc, method, next, opts...)
+		}
+	}
+	return streamer(ctx, desc, c.clientConn(), method, opts...)
+}
+
+func (c *interceptedConn) clientConn() *grpc.ClientConn {
+	cc, _ := c.conn.(*grpc.ClientConn)
+	return cc
+}
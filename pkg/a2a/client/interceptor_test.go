@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type metadataServer struct {
+	a2av1.UnimplementedA2AServiceServer
+	mu sync.Mutex
+	md metadata.MD
+}
+
+func (s *metadataServer) record(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.mu.Lock()
+	s.md = md
+	s.mu.Unlock()
+}
+
+func (s *metadataServer) get(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.md.Get(key)
+}
+
+func (s *metadataServer) SendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
+	s.record(ctx)
+	return &a2av1.SendMessageResponse{}, nil
+}
+
+func (s *metadataServer) SubscribeToTask(req *a2av1.SubscribeToTaskRequest, stream a2av1.A2AService_SubscribeToTaskServer) error {
+	s.record(stream.Context())
+	return nil
+}
+
+func TestBearerTokenAndMetadata(t *testing.T) {
+	server := &metadataServer{}
+	conn, cleanup := newTestClient(t, server)
+	defer cleanup()
+
+	client := New(conn, BearerToken("secret"), WithMetadata(metadata.Pairs("x-tenant", "acme")))
+	if _, err := client.SendMessage(context.Background(), &a2av1.SendMessageRequest{}); err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	if got := server.get("authorization"); !reflect.DeepEqual(got, []string{"Bearer secret"}) {
+		t.Fatalf("unexpected authorization %v", got)
+	}
+	if got := server.get("x-tenant"); !reflect.DeepEqual(got, []string{"acme"}) {
+		t.Fatalf("unexpected x-tenant %v", got)
+	}
+
+	stream, err := client.SubscribeToTask(context.Background(), &a2av1.SubscribeToTaskRequest{Name: "tasks/abc"})
+	if err != nil {
+		t.Fatalf("SubscribeToTask error: %v", err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+	if got := server.get("authorization"); !reflect.DeepEqual(got, []string{"Bearer secret"}) {
+		t.Fatalf("unexpected stream authorization %v", got)
+	}
+}
+
+func TestInterceptorsComposeInOrder(t *testing.T) {
+	conn, cleanup := newTestClient(t, &metadataServer{})
+	defer cleanup()
+
+	var order []string
+	unary := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			order = append(order, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	stream := func(name string) grpc.StreamClientInterceptor {
+		return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			order = append(order, name)
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+	}
+
+	client := New(conn,
+		WithUnaryInterceptor(unary("u1"), unary("u2")),
+		WithStreamInterceptor(stream("s1")),
+		WithUnaryInterceptor(unary("u3")),
+		WithStreamInterceptor(stream("s2")),
+	)
+	if _, err := client.SendMessage(context.Background(), &a2av1.SendMessageRequest{}); err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	if _, err := client.SubscribeToTask(context.Background(), &a2av1.SubscribeToTaskRequest{Name: "tasks/abc"}); err != nil {
+		t.Fatalf("SubscribeToTask error: %v", err)
+	}
+	if want := []string{"u1", "u2", "u3", "s1", "s2"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+}
@@ -5,9 +5,27 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
+// UnaryTracingInterceptor injects the current span into outgoing unary calls
+// as W3C traceparent metadata. Client already does this for its own calls;
+// the interceptor covers connections used directly or by other clients.
+func UnaryTracingInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectTraceContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamTracingInterceptor injects the current span into outgoing streams as
+// W3C traceparent metadata.
+func StreamTracingInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectTraceContext(ctx), desc, cc, method, opts...)
+	}
+}
+
 func injectTraceContext(ctx context.Context) context.Context {
 	md, ok := metadata.FromOutgoingContext(ctx)
 	if !ok {
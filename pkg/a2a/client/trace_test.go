@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracingInterceptors_InjectTraceparent(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var unaryHeader string
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		unaryHeader = firstValue(md, "traceparent")
+		return nil
+	}
+	if err := UnaryTracingInterceptor()(ctx, "/a2a/SendMessage", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("unary interceptor error: %v", err)
+	}
+	if unaryHeader != want {
+		t.Fatalf("expected traceparent %q, got %q", want, unaryHeader)
+	}
+
+	var streamHeader string
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		streamHeader = firstValue(md, "traceparent")
+		return nil, nil
+	}
+	if _, err := StreamTracingInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/a2a/SubscribeToTask", streamer); err != nil {
+		t.Fatalf("stream interceptor error: %v", err)
+	}
+	if streamHeader != want {
+		t.Fatalf("expected traceparent %q, got %q", want, streamHeader)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/jllopis/kairos/pkg/a2a"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+)
+
+// WithStreamErrorHandler registers fn to be called when a stream returned by
+// the Unified client ends with an error. It is not called when the stream
+// ends normally or ctx is cancelled.
+func WithStreamErrorHandler(fn func(error)) Option {
+	return func(c *Client) {
+		c.onStreamError = fn
+	}
+}
+
+// Unified returns c as an a2a.Client. gRPC streams are delivered on
+// channels, as in the HTTP+JSON and JSON-RPC clients.
+func (c *Client) Unified() a2a.Client {
+	return unifiedClient{client: c}
+}
+
+// unifiedClient adapts Client to a2a.Client.
+type unifiedClient struct {
+	client *Client
+}
+
+var _ a2a.Client = unifiedClient{}
+
+func (u unifiedClient) SendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
+	return u.client.SendMessage(ctx, req)
+}
+
+func (u unifiedClient) SendStreamingMessage(ctx context.Context, req *a2av1.SendMessageRequest) (<-chan *a2av1.StreamResponse, error) {
+	stream, err := u.client.SendStreamingMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return u.forward(ctx, stream), nil
+}
+
+func (u unifiedClient) GetTask(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av1.Task, error) {
+	return u.client.GetTask(ctx, req)
+}
+
+func (u unifiedClient) ListTasks(ctx context.Context, req *a2av1.ListTasksRequest) (*a2av1.ListTasksResponse, error) {
+	return u.client.ListTasks(ctx, req)
+}
+
+func (u unifiedClient) CancelTask(ctx context.Context, req *a2av1.CancelTaskRequest) (*a2av1.Task, error) {
+	return u.client.CancelTask(ctx, req)
+}
+
+func (u unifiedClient) SubscribeToTask(ctx context.Context, req *a2av1.SubscribeToTaskRequest) (<-chan *a2av1.StreamResponse, error) {
+	stream, err := u.client.SubscribeToTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return u.forward(ctx, stream), nil
+}
+
+// forward copies stream to a channel, closing it when the stream ends.
+func (u unifiedClient) forward(ctx context.Context, stream grpc.ServerStreamingClient[a2av1.StreamResponse]) <-chan *a2av1.StreamResponse {
+	out := make(chan *a2av1.StreamResponse)
+	go func() {
+		defer close(out)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) && ctx.Err() == nil && u.client.onStreamError != nil {
+					u.client.onStreamError(err)
+				}
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- resp:
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnifiedStreamsOnChannel(t *testing.T) {
+	conn, cleanup := newTestClient(t, &testServer{})
+	defer cleanup()
+
+	events, err := New(conn).Unified().SubscribeToTask(context.Background(), &a2av1.SubscribeToTaskRequest{Name: "tasks/abc"})
+	if err != nil {
+		t.Fatalf("SubscribeToTask error: %v", err)
+	}
+	var received int
+	for range events {
+		received++
+	}
+	if received != 1 {
+		t.Fatalf("expected 1 event, got %d", received)
+	}
+}
+
+func TestUnifiedReportsStreamError(t *testing.T) {
+	conn, cleanup := newTestClient(t, &testServer{streamSleep: 200 * time.Millisecond})
+	defer cleanup()
+
+	var streamErr error
+	client := New(conn, WithTimeout(50*time.Millisecond), WithStreamErrorHandler(func(err error) {
+		streamErr = err
+	}))
+	events, err := client.Unified().SendStreamingMessage(context.Background(), &a2av1.SendMessageRequest{})
+	if err != nil {
+		t.Fatalf("SendStreamingMessage error: %v", err)
+	}
+	for range events {
+		t.Fatal("expected no events")
+	}
+	// The handler runs before the channel is closed.
+	if status.Code(streamErr) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", streamErr)
+	}
+}
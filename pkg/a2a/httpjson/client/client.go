@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/jllopis/kairos/pkg/a2a"
 	"github.com/jllopis/kairos/pkg/a2a/server"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"go.opentelemetry.io/otel"
@@ -24,11 +25,14 @@ import (
 
 // Client wraps the HTTP+JSON binding for A2A.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	headers    map[string]string
+	baseURL       string
+	httpClient    *http.Client
+	headers       map[string]string
+	onStreamError func(error)
 }
 
+var _ a2a.Client = (*Client)(nil)
+
 // Option configures the client.
 type Option func(*Client)
 
@@ -62,6 +66,16 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
+// WithStreamErrorHandler registers fn to be called when a stream returned by
+// SendStreamingMessage or SubscribeToTask ends with an error, such as an SSE
+// error event sent by the server. It is called before the channel is closed
+// and not called when the stream ends normally or ctx is cancelled.
+func WithStreamErrorHandler(fn func(error)) Option {
+	return func(c *Client) {
+		c.onStreamError = fn
+	}
+}
+
 // SendMessage calls the message:send endpoint.
 func (c *Client) SendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
 	if req == nil {
@@ -211,6 +225,9 @@ func (c *Client) ListApprovals(ctx context.Context, filter server.ApprovalFilter
 	if filter.ContextID != "" {
 		query.Set("contextId", filter.ContextID)
 	}
+	if filter.ToolName != "" {
+		query.Set("toolName", filter.ToolName)
+	}
 	if filter.Status != "" {
 		query.Set("status", string(filter.Status))
 	}
@@ -254,6 +271,32 @@ func (c *Client) RejectApproval(ctx context.Context, id, reason string) (*a2av1.
 	return resp, nil
 }
 
+// BulkApprove approves each id and returns one result per id. Individual
+// failures are reported in the results rather than as an error.
+func (c *Client) BulkApprove(ctx context.Context, ids []string, reason string) ([]server.ApprovalResult, error) {
+	return c.bulkDecide(ctx, "/approvals:batchApprove", ids, reason)
+}
+
+// BulkReject rejects each id and returns one result per id. Individual
+// failures are reported in the results rather than as an error.
+func (c *Client) BulkReject(ctx context.Context, ids []string, reason string) ([]server.ApprovalResult, error) {
+	return c.bulkDecide(ctx, "/approvals:batchReject", ids, reason)
+}
+
+func (c *Client) bulkDecide(ctx context.Context, path string, ids []string, reason string) ([]server.ApprovalResult, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("ids are required")
+	}
+	payload := map[string]any{"ids": ids, "reason": reason}
+	var resp struct {
+		Results []server.ApprovalResult `json:"results"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, c.endpoint(path), payload, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
 func (c *Client) endpoint(path string) string {
 	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
 		return path
@@ -367,7 +410,10 @@ func (c *Client) streamProto(ctx context.Context, method, endpoint string, req p
 	go func() {
 		defer response.Body.Close()
 		defer close(out)
-		_ = readSSE(ctx, response.Body, func(payload []byte) error {
+		err := readSSE(ctx, response.Body, func(event string, payload []byte) error {
+			if event == "error" {
+				return parseStreamError(payload)
+			}
 			resp, err := parse(payload)
 			if err != nil {
 				return err
@@ -379,6 +425,9 @@ func (c *Client) streamProto(ctx context.Context, method, endpoint string, req p
 				return nil
 			}
 		})
+		if err != nil && ctx.Err() == nil && c.onStreamError != nil {
+			c.onStreamError(err)
+		}
 	}()
 	return out, nil
 }
@@ -390,9 +439,10 @@ func (c *Client) applyHeaders(ctx context.Context, request *http.Request) {
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
 }
 
-func readSSE(ctx context.Context, body io.Reader, handle func([]byte) error) error {
+func readSSE(ctx context.Context, body io.Reader, handle func(event string, data []byte) error) error {
 	reader := bufio.NewReader(body)
 	var buffer bytes.Buffer
+	event := ""
 	for {
 		select {
 		case <-ctx.Done():
@@ -403,7 +453,7 @@ func readSSE(ctx context.Context, body io.Reader, handle func([]byte) error) err
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				if buffer.Len() > 0 {
-					_ = handle(buffer.Bytes())
+					return handle(event, buffer.Bytes())
 				}
 				return nil
 			}
@@ -414,10 +464,15 @@ func readSSE(ctx context.Context, body io.Reader, handle func([]byte) error) err
 			if buffer.Len() == 0 {
 				continue
 			}
-			if err := handle(buffer.Bytes()); err != nil {
+			if err := handle(event, buffer.Bytes()); err != nil {
 				return err
 			}
 			buffer.Reset()
+			event = ""
+			continue
+		}
+		if strings.HasPrefix(line, "event:") {
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
 			continue
 		}
 		if strings.HasPrefix(line, "data:") {
@@ -430,17 +485,38 @@ func readSSE(ctx context.Context, body io.Reader, handle func([]byte) error) err
 	}
 }
 
+// parseStreamError decodes the problem details carried by an SSE error event.
+func parseStreamError(payload []byte) error {
+	var decoded struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return status.Error(codes.Unknown, strings.TrimSpace(string(payload)))
+	}
+	code, ok := codesByName[strings.TrimSpace(decoded.Title)]
+	if !ok {
+		code = codes.Unknown
+	}
+	return status.Error(code, decoded.Detail)
+}
+
 func parseHTTPError(response *http.Response) error {
+	code := codeFromHTTPStatus(response.StatusCode)
 	payload, _ := io.ReadAll(response.Body)
 	if len(payload) == 0 {
-		return status.Error(codes.Unknown, response.Status)
+		return status.Error(code, response.Status)
 	}
 	var decoded struct {
 		Title  string `json:"title"`
 		Detail string `json:"detail"`
 	}
 	if err := json.Unmarshal(payload, &decoded); err != nil {
-		return status.Error(codes.Unknown, response.Status)
+		return status.Error(code, response.Status)
+	}
+	// The server sets the problem title to the gRPC code name.
+	if named, ok := codesByName[strings.TrimSpace(decoded.Title)]; ok {
+		code = named
 	}
 	detail := strings.TrimSpace(decoded.Detail)
 	if detail == "" {
@@ -449,7 +525,44 @@ func parseHTTPError(response *http.Response) error {
 	if detail == "" {
 		detail = response.Status
 	}
-	return status.Error(codes.Unknown, detail)
+	return status.Error(code, detail)
+}
+
+var codesByName = func() map[string]codes.Code {
+	out := make(map[string]codes.Code)
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		out[code.String()] = code
+	}
+	return out
+}()
+
+// codeFromHTTPStatus is the inverse of the server status mapping, used when
+// the response carries no problem title.
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
 }
 
 func taskPath(name string) (string, error) {
@@ -3,6 +3,7 @@ package httpjson
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/a2a/agentcard"
 	"github.com/jllopis/kairos/pkg/a2a/server"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"google.golang.org/grpc/codes"
@@ -29,6 +31,20 @@ func New(handler server.Handler) *Server {
 	return &Server{Handler: handler}
 }
 
+// NewHandler returns an http.Handler that serves the HTTP+JSON binding and,
+// when handler exposes an AgentCard, publishes it at agentcard.WellKnownPath,
+// so a single http.Server can serve discovery and the A2A API on one port.
+func NewHandler(handler server.Handler) http.Handler {
+	mux := http.NewServeMux()
+	if provider, ok := handler.(interface{ AgentCard() *a2av1.AgentCard }); ok {
+		if card := provider.AgentCard(); card != nil {
+			mux.Handle(agentcard.WellKnownPath, agentcard.PublishHandler(card))
+		}
+	}
+	mux.Handle("/", New(handler))
+	return mux
+}
+
 // ServeHTTP routes HTTP+JSON requests to the A2A handler.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if s.Handler == nil {
@@ -68,6 +84,17 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "approvals":
 		s.handleApprovals(w, r, segments)
 		return
+	case "approvals:batchApprove", "approvals:batchReject":
+		if r.Method != http.MethodPost || len(segments) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+		decision := server.ApprovalStatusApproved
+		if segments[0] == "approvals:batchReject" {
+			decision = server.ApprovalStatusRejected
+		}
+		s.handleBatchDecision(w, r, decision)
+		return
 	default:
 		http.NotFound(w, r)
 		return
@@ -76,7 +103,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
 	req := &a2av1.SendMessageRequest{}
-	if err := decodeProtoJSON(r, req); err != nil {
+	if err := s.decodeProtoJSON(w, r, req); err != nil {
 		writeError(w, err)
 		return
 	}
@@ -90,7 +117,7 @@ func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleSendStreamingMessage(w http.ResponseWriter, r *http.Request) {
 	req := &a2av1.SendMessageRequest{}
-	if err := decodeProtoJSON(r, req); err != nil {
+	if err := s.decodeProtoJSON(w, r, req); err != nil {
 		writeError(w, err)
 		return
 	}
@@ -104,7 +131,7 @@ func (s *Server) handleSendStreamingMessage(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Connection", "keep-alive")
 	stream := &sseStream{ctx: r.Context(), w: w, f: writer}
 	if err := s.Handler.SendStreamingMessage(req, stream); err != nil {
-		writeError(w, err)
+		stream.fail(err)
 		return
 	}
 }
@@ -267,6 +294,7 @@ func (s *Server) handleListApprovals(w http.ResponseWriter, r *http.Request, han
 	filter := server.ApprovalFilter{
 		TaskID:    query.Get("taskId"),
 		ContextID: query.Get("contextId"),
+		ToolName:  query.Get("toolName"),
 		Status:    server.ApprovalStatus(query.Get("status")),
 	}
 	if filter.Status != "" && filter.Status != server.ApprovalStatusPending && filter.Status != server.ApprovalStatusApproved && filter.Status != server.ApprovalStatusRejected {
@@ -313,6 +341,32 @@ func (s *Server) handleReject(w http.ResponseWriter, r *http.Request, handler se
 	writeProtoJSON(w, task)
 }
 
+func (s *Server) handleBatchDecision(w http.ResponseWriter, r *http.Request, decision server.ApprovalStatus) {
+	handler, ok := s.Handler.(server.ApprovalHandler)
+	if !ok {
+		writeError(w, status.Error(codes.Unimplemented, "approvals not supported"))
+		return
+	}
+	var payload struct {
+		IDs    []string `json:"ids"`
+		Reason string   `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, status.Error(codes.InvalidArgument, "invalid request body"))
+		return
+	}
+	if len(payload.IDs) == 0 {
+		writeError(w, status.Error(codes.InvalidArgument, "ids are required"))
+		return
+	}
+	results, err := server.DecideApprovals(r.Context(), handler, payload.IDs, decision, payload.Reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]any{"results": results})
+}
+
 func (s *Server) handleSubscribeTask(w http.ResponseWriter, r *http.Request, name string) {
 	writer, ok := w.(http.Flusher)
 	if !ok {
@@ -325,7 +379,7 @@ func (s *Server) handleSubscribeTask(w http.ResponseWriter, r *http.Request, nam
 	req := &a2av1.SubscribeToTaskRequest{Name: name}
 	stream := &sseStream{ctx: r.Context(), w: w, f: writer}
 	if err := s.Handler.SubscribeToTask(req, stream); err != nil {
-		writeError(w, err)
+		stream.fail(err)
 		return
 	}
 }
@@ -341,7 +395,7 @@ func (s *Server) handlePushConfigs(w http.ResponseWriter, r *http.Request, segme
 	switch r.Method {
 	case http.MethodPost:
 		config := &a2av1.TaskPushNotificationConfig{}
-		if err := decodeProtoJSON(r, config); err != nil {
+		if err := s.decodeProtoJSON(w, r, config); err != nil {
 			writeError(w, err)
 			return
 		}
@@ -391,9 +445,19 @@ func (s *Server) handlePushConfigs(w http.ResponseWriter, r *http.Request, segme
 	}
 }
 
-func decodeProtoJSON(r *http.Request, msg proto.Message) error {
+// decodeProtoJSON reads the request body into msg. Bodies larger than the
+// handler message limits allow are rejected with ResourceExhausted before
+// they are read in full.
+func (s *Server) decodeProtoJSON(w http.ResponseWriter, r *http.Request, msg proto.Message) error {
+	if limit := server.MessageLimitsOf(s.Handler).MaxJSONRequestBytes(); limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return status.Errorf(codes.ResourceExhausted, "request body exceeds %d bytes", tooLarge.Limit)
+		}
 		return status.Error(codes.InvalidArgument, "invalid body")
 	}
 	if len(body) == 0 {
@@ -437,9 +501,12 @@ func writeError(w http.ResponseWriter, err error) {
 	_ = json.NewEncoder(w).Encode(body)
 }
 
+// statusClientClosedRequest is the de facto status for cancelled requests.
+const statusClientClosedRequest = 499
+
 func httpStatusFromCode(code codes.Code) int {
 	switch code {
-	case codes.InvalidArgument:
+	case codes.InvalidArgument, codes.OutOfRange:
 		return http.StatusBadRequest
 	case codes.NotFound:
 		return http.StatusNotFound
@@ -449,8 +516,16 @@ func httpStatusFromCode(code codes.Code) int {
 		return http.StatusForbidden
 	case codes.Unimplemented:
 		return http.StatusNotImplemented
-	case codes.FailedPrecondition:
+	case codes.FailedPrecondition, codes.AlreadyExists, codes.Aborted:
 		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return statusClientClosedRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -526,9 +601,30 @@ func parseTaskState(value string) a2av1.TaskState {
 }
 
 type sseStream struct {
-	ctx context.Context
-	w   http.ResponseWriter
-	f   http.Flusher
+	ctx  context.Context
+	w    http.ResponseWriter
+	f    http.Flusher
+	sent bool
+}
+
+// fail reports err as a regular HTTP error if nothing was streamed yet, or
+// as an SSE "error" event carrying problem details otherwise.
+func (s *sseStream) fail(err error) {
+	if !s.sent {
+		writeError(s.w, err)
+		return
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.New(codes.Unknown, err.Error())
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":   "about:blank",
+		"title":  st.Code().String(),
+		"detail": st.Message(),
+	})
+	_, _ = fmt.Fprintf(s.w, "event: error\ndata: %s\n\n", payload)
+	s.f.Flush()
 }
 
 func (s *sseStream) Context() context.Context {
@@ -540,6 +636,7 @@ func (s *sseStream) Send(resp *a2av1.StreamResponse) error {
 	if err != nil {
 		return err
 	}
+	s.sent = true
 	if _, err := s.w.Write([]byte("data: ")); err != nil {
 		return err
 	}
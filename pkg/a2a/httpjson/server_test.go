@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jllopis/kairos/pkg/a2a/agentcard"
+	"github.com/jllopis/kairos/pkg/a2a/httpjson/client"
 	"github.com/jllopis/kairos/pkg/a2a/server"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"google.golang.org/grpc/codes"
@@ -189,6 +191,45 @@ func TestServerSendMessageEmptyBody(t *testing.T) {
 	}
 }
 
+// limitedHandler reports message limits, as server.SimpleHandler does.
+type limitedHandler struct {
+	*testHandler
+	limits server.MessageLimits
+}
+
+func (h limitedHandler) Limits() server.MessageLimits { return h.limits }
+
+func TestServerSendMessageBodyLimit(t *testing.T) {
+	called := false
+	handler := limitedHandler{
+		testHandler: &testHandler{
+			sendMessage: func(context.Context, *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
+				called = true
+				return &a2av1.SendMessageResponse{}, nil
+			},
+		},
+		limits: server.MessageLimits{MaxMessageBytes: 1024},
+	}
+	payload, err := protojson.Marshal(&a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			Role:  a2av1.Role_ROLE_USER,
+			Parts: []*a2av1.Part{{Part: &a2av1.Part_Text{Text: strings.Repeat("x", 1<<20)}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/message:send", bytes.NewReader(payload))
+	rec := httptest.NewRecorder()
+	New(handler).ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if called {
+		t.Fatal("expected the handler not to be called")
+	}
+}
+
 func TestServerSendStreamingMessage(t *testing.T) {
 	handler := &testHandler{
 		sendStreaming: func(req *a2av1.SendMessageRequest, stream a2av1.A2AService_SendStreamingMessageServer) error {
@@ -268,3 +309,162 @@ func TestServerPushConfigsUnsupported(t *testing.T) {
 		t.Fatalf("expected unimplemented error, got %v", payload["title"])
 	}
 }
+
+type cardTestHandler struct {
+	*testHandler
+	card *a2av1.AgentCard
+}
+
+func (h *cardTestHandler) AgentCard() *a2av1.AgentCard {
+	return h.card
+}
+
+func TestNewHandlerServesAgentCardAndAPI(t *testing.T) {
+	handler := &cardTestHandler{
+		testHandler: &testHandler{
+			getTask: func(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av1.Task, error) {
+				return &a2av1.Task{Id: strings.TrimPrefix(req.GetName(), "tasks/")}, nil
+			},
+		},
+		card: &a2av1.AgentCard{Name: "demo"},
+	}
+	ts := httptest.NewServer(NewHandler(handler))
+	defer ts.Close()
+
+	card, err := agentcard.Fetch(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("fetch agent card: %v", err)
+	}
+	if card.GetName() != "demo" {
+		t.Fatalf("expected card demo, got %q", card.GetName())
+	}
+
+	task, err := client.New(ts.URL).GetTask(context.Background(), &a2av1.GetTaskRequest{Name: "tasks/task-1"})
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if task.GetId() != "task-1" {
+		t.Fatalf("expected task-1, got %q", task.GetId())
+	}
+}
+
+func TestServerErrorCodesRoundTrip(t *testing.T) {
+	for _, code := range []codes.Code{
+		codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.FailedPrecondition,
+		codes.ResourceExhausted,
+		codes.Unavailable,
+		codes.Unimplemented,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			handler := &testHandler{
+				getTask: func(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av1.Task, error) {
+					return nil, status.Error(code, "boom")
+				},
+			}
+			ts := httptest.NewServer(New(handler))
+			defer ts.Close()
+
+			_, err := client.New(ts.URL).GetTask(context.Background(), &a2av1.GetTaskRequest{Name: "tasks/task-1"})
+			st, _ := status.FromError(err)
+			if st.Code() != code || st.Message() != "boom" {
+				t.Fatalf("expected %s boom, got %v", code, err)
+			}
+		})
+	}
+}
+
+func TestServerStreamErrorEvent(t *testing.T) {
+	handler := &testHandler{
+		sendStreaming: func(req *a2av1.SendMessageRequest, stream a2av1.A2AService_SendStreamingMessageServer) error {
+			if err := stream.Send(&a2av1.StreamResponse{
+				Payload: &a2av1.StreamResponse_StatusUpdate{
+					StatusUpdate: &a2av1.TaskStatusUpdateEvent{
+						Status: &a2av1.TaskStatus{State: a2av1.TaskState_TASK_STATE_WORKING},
+					},
+				},
+			}); err != nil {
+				return err
+			}
+			return status.Error(codes.Unavailable, "executor lost")
+		},
+	}
+	ts := httptest.NewServer(New(handler))
+	defer ts.Close()
+
+	var streamErr error
+	c := client.New(ts.URL, client.WithStreamErrorHandler(func(err error) { streamErr = err }))
+	events, err := c.SendStreamingMessage(context.Background(), &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{Parts: []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "ping"}}}},
+	})
+	if err != nil {
+		t.Fatalf("send streaming: %v", err)
+	}
+	var got []*a2av1.StreamResponse
+	for resp := range events {
+		got = append(got, resp)
+	}
+	if len(got) != 1 || got[0].GetStatusUpdate().GetStatus().GetState() != a2av1.TaskState_TASK_STATE_WORKING {
+		t.Fatalf("unexpected events: %v", got)
+	}
+	if st, _ := status.FromError(streamErr); st.Code() != codes.Unavailable || st.Message() != "executor lost" {
+		t.Fatalf("expected Unavailable stream error, got %v", streamErr)
+	}
+}
+
+type approvalTestHandler struct {
+	*testHandler
+}
+
+func (h *approvalTestHandler) GetApproval(ctx context.Context, id string) (*server.ApprovalRecord, error) {
+	return nil, status.Error(codes.Unimplemented, "GetApproval not configured")
+}
+
+func (h *approvalTestHandler) ListApprovals(ctx context.Context, filter server.ApprovalFilter) ([]*server.ApprovalRecord, error) {
+	return nil, status.Error(codes.Unimplemented, "ListApprovals not configured")
+}
+
+func (h *approvalTestHandler) Approve(ctx context.Context, id, reason string) (*a2av1.Task, error) {
+	if id == "missing" {
+		return nil, status.Error(codes.NotFound, "approval not found")
+	}
+	return &a2av1.Task{Id: "task-" + id, Status: &a2av1.TaskStatus{State: a2av1.TaskState_TASK_STATE_COMPLETED}}, nil
+}
+
+func (h *approvalTestHandler) Reject(ctx context.Context, id, reason string) (*a2av1.Task, error) {
+	return &a2av1.Task{Id: "task-" + id, Status: &a2av1.TaskStatus{State: a2av1.TaskState_TASK_STATE_REJECTED}}, nil
+}
+
+func TestServerBulkApprovePartialFailure(t *testing.T) {
+	ts := httptest.NewServer(New(&approvalTestHandler{testHandler: &testHandler{}}))
+	defer ts.Close()
+	c := client.New(ts.URL)
+
+	results, err := c.BulkApprove(context.Background(), []string{"a", "missing", "b"}, "ok")
+	if err != nil {
+		t.Fatalf("bulk approve: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Status != server.ApprovalStatusApproved || results[0].TaskID != "task-a" {
+		t.Fatalf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Error == "" || results[1].Status != "" {
+		t.Fatalf("expected failure for missing id: %+v", results[1])
+	}
+	if results[2].Status != server.ApprovalStatusApproved {
+		t.Fatalf("failure must not abort remaining ids: %+v", results[2])
+	}
+
+	rejected, err := c.BulkReject(context.Background(), []string{"c"}, "no")
+	if err != nil {
+		t.Fatalf("bulk reject: %v", err)
+	}
+	if len(rejected) != 1 || rejected[0].Status != server.ApprovalStatusRejected || rejected[0].TaskState != "TASK_STATE_REJECTED" {
+		t.Fatalf("unexpected reject results: %+v", rejected)
+	}
+}
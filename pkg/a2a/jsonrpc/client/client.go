@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jllopis/kairos/pkg/a2a"
 	"github.com/jllopis/kairos/pkg/a2a/server"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"go.opentelemetry.io/otel"
@@ -29,6 +30,8 @@ type Client struct {
 	headers    map[string]string
 }
 
+var _ a2a.Client = (*Client)(nil)
+
 // Option configures the client.
 type Option func(*Client)
 
@@ -159,6 +162,9 @@ func (c *Client) ListApprovals(ctx context.Context, filter server.ApprovalFilter
 	if filter.ContextID != "" {
 		params["context_id"] = filter.ContextID
 	}
+	if filter.ToolName != "" {
+		params["tool_name"] = filter.ToolName
+	}
 	if filter.Status != "" {
 		params["status"] = string(filter.Status)
 	}
@@ -67,6 +67,10 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.handleApproveApproval(w, r, req)
 	case "RejectApproval":
 		s.handleRejectApproval(w, r, req)
+	case "BatchApproveApprovals":
+		s.handleBatchDecision(w, r, req, server.ApprovalStatusApproved)
+	case "BatchRejectApprovals":
+		s.handleBatchDecision(w, r, req, server.ApprovalStatusRejected)
 	default:
 		writeError(w, rpcError{Code: -32601, Message: "method not found"})
 	}
@@ -209,6 +213,7 @@ func (s *Server) handleListApprovals(w http.ResponseWriter, r *http.Request, req
 	var payload struct {
 		TaskID        string `json:"task_id"`
 		ContextID     string `json:"context_id"`
+		ToolName      string `json:"tool_name"`
 		Status        string `json:"status"`
 		Limit         int    `json:"limit"`
 		ExpiresBefore int64  `json:"expires_before"`
@@ -222,6 +227,7 @@ func (s *Server) handleListApprovals(w http.ResponseWriter, r *http.Request, req
 	filter := server.ApprovalFilter{
 		TaskID:    payload.TaskID,
 		ContextID: payload.ContextID,
+		ToolName:  payload.ToolName,
 		Status:    server.ApprovalStatus(payload.Status),
 		Limit:     payload.Limit,
 	}
@@ -284,6 +290,32 @@ func (s *Server) handleRejectApproval(w http.ResponseWriter, r *http.Request, re
 	writeResult(w, req.ID, resp)
 }
 
+func (s *Server) handleBatchDecision(w http.ResponseWriter, r *http.Request, req rpcRequest, decision server.ApprovalStatus) {
+	handler, ok := s.Handler.(server.ApprovalHandler)
+	if !ok {
+		writeRPCError(w, status.Error(codes.Unimplemented, "approvals not supported"))
+		return
+	}
+	var payload struct {
+		IDs    []string `json:"ids"`
+		Reason string   `json:"reason"`
+	}
+	if err := decodeJSONParams(req.Params, &payload); err != nil {
+		writeError(w, rpcError{Code: -32602, Message: err.Error()})
+		return
+	}
+	if len(payload.IDs) == 0 {
+		writeError(w, rpcError{Code: -32602, Message: "ids are required"})
+		return
+	}
+	results, err := server.DecideApprovals(r.Context(), handler, payload.IDs, decision, payload.Reason)
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSONResult(w, req.ID, map[string]any{"results": results})
+}
+
 func decodeParams(params json.RawMessage, msg proto.Message) error {
 	if len(params) == 0 {
 		return status.Error(codes.InvalidArgument, "missing params")
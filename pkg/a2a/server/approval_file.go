@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// FileApprovalStore persists approvals in a JSON file so pending approvals
+// survive restarts without a database. Records are served from memory and
+// the file is rewritten atomically on every change; it is meant for a single
+// process and modest volumes (use SQLiteApprovalStore beyond that).
+type FileApprovalStore struct {
+	path string
+	mem  *MemoryApprovalStore
+	mu   sync.Mutex // serializes changes and writes
+}
+
+// fileApproval is the on-disk form of a record; the message is stored as
+// protojson.
+type fileApproval struct {
+	ApprovalRecord
+	Message json.RawMessage `json:"message,omitempty"`
+}
+
+type approvalFile struct {
+	Approvals []fileApproval `json:"approvals"`
+}
+
+// NewFileApprovalStore opens the approval store at path, loading the
+// approvals already saved there. The file is created on the first change.
+func NewFileApprovalStore(path string) (*FileApprovalStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	s := &FileApprovalStore{path: path, mem: NewMemoryApprovalStore()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file approvalFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode approvals %s: %w", path, err)
+	}
+	for _, entry := range file.Approvals {
+		record := entry.ApprovalRecord
+		if len(entry.Message) > 0 {
+			message := &a2av1.Message{}
+			if err := protojson.Unmarshal(entry.Message, message); err != nil {
+				return nil, fmt.Errorf("decode approval %q message: %w", record.ID, err)
+			}
+			record.Message = message
+		}
+		s.mem.approvals[record.ID] = &record
+	}
+	return s, nil
+}
+
+// Create inserts an approval record and saves the store.
+func (s *FileApprovalStore) Create(ctx context.Context, record ApprovalRecord) (*ApprovalRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created, err := s.mem.Create(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveLocked(); err != nil {
+		s.mem.mu.Lock()
+		delete(s.mem.approvals, created.ID)
+		s.mem.mu.Unlock()
+		return nil, err
+	}
+	return created, nil
+}
+
+// Get returns an approval record by id.
+func (s *FileApprovalStore) Get(ctx context.Context, id string) (*ApprovalRecord, error) {
+	return s.mem.Get(ctx, id)
+}
+
+// List returns approvals matching the filter, most recently updated first.
+func (s *FileApprovalStore) List(ctx context.Context, filter ApprovalFilter) ([]*ApprovalRecord, error) {
+	return s.mem.List(ctx, filter)
+}
+
+// UpdateStatus updates the approval status and saves the store.
+func (s *FileApprovalStore) UpdateStatus(ctx context.Context, id string, status ApprovalStatus, reason string) (*ApprovalRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, err := s.mem.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.mem.UpdateStatus(ctx, id, status, reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveLocked(); err != nil {
+		s.mem.mu.Lock()
+		s.mem.approvals[id] = previous
+		s.mem.mu.Unlock()
+		return nil, err
+	}
+	return updated, nil
+}
+
+// saveLocked writes every record to a temporary file and renames it over
+// the store file. Must be called with s.mu held.
+func (s *FileApprovalStore) saveLocked() error {
+	records, err := s.mem.List(context.Background(), ApprovalFilter{})
+	if err != nil {
+		return err
+	}
+	file := approvalFile{Approvals: make([]fileApproval, 0, len(records))}
+	for _, record := range records {
+		entry := fileApproval{ApprovalRecord: *record}
+		if record.Message != nil {
+			if entry.Message, err = approvalJSON.Marshal(record.Message); err != nil {
+				return err
+			}
+		}
+		file.Approvals = append(file.Approvals, entry)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/governance"
+)
+
+func TestFileApprovalStore_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "approvals.json")
+	store, err := NewFileApprovalStore(path)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	message := &a2av1.Message{
+		MessageId: uuid.NewString(),
+		Role:      a2av1.Role_ROLE_USER,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+	}
+	expired, err := store.Create(ctx, ApprovalRecord{
+		TaskID:    "task-1",
+		ContextID: "ctx-1",
+		ToolName:  "deploy",
+		ExpiresAt: time.Now().UTC().Add(-time.Minute),
+		Message:   message,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	pending, err := store.Create(ctx, ApprovalRecord{
+		TaskID:    "task-2",
+		ContextID: "ctx-1",
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.UpdateStatus(ctx, pending.ID, ApprovalStatusApproved, "ok"); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	reopened, err := NewFileApprovalStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	found, err := reopened.Get(ctx, expired.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if found.ToolName != "deploy" || found.Status != ApprovalStatusPending {
+		t.Fatalf("unexpected record: %+v", found)
+	}
+	if found.Message.GetParts()[0].GetText() != "hello" {
+		t.Fatalf("expected message to survive restart, got %v", found.Message)
+	}
+	approved, err := reopened.Get(ctx, pending.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if approved.Status != ApprovalStatusApproved || approved.Reason != "ok" {
+		t.Fatalf("expected approved record, got %+v", approved)
+	}
+	list, err := reopened.List(ctx, ApprovalFilter{ContextID: "ctx-1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != pending.ID {
+		t.Fatalf("expected most recently updated first, got %d records", len(list))
+	}
+	expiring, err := reopened.List(ctx, ApprovalFilter{
+		Status:         ApprovalStatusPending,
+		ExpiringBefore: time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("list expiring: %v", err)
+	}
+	if len(expiring) != 1 || expiring[0].ID != expired.ID {
+		t.Fatalf("expected expired approval, got %d records", len(expiring))
+	}
+}
+
+func TestFileApprovalStore_ExpireAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	store, err := NewFileApprovalStore(path)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	tasks := NewMemoryTaskStore()
+	handler := &SimpleHandler{Store: tasks, Executor: &approvalTestExecutor{}}
+	WithApprovalStore(store)(handler)
+	WithApprovalTimeout(-time.Second)(handler)
+	handler.PolicyEngine = governance.NewRuleSet([]governance.Rule{{Effect: "pending", Type: governance.ActionAgent}})
+	msg := &a2av1.Message{
+		MessageId: uuid.NewString(),
+		Role:      a2av1.Role_ROLE_USER,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "ping"}}},
+	}
+	resp, err := handler.SendMessage(ctx, &a2av1.SendMessageRequest{Request: msg})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	// A new handler over the same file picks up the pending approval.
+	reopened, err := NewFileApprovalStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	handler.ApprovalStore = reopened
+	if _, err := handler.ExpireApprovals(ctx); err != nil {
+		t.Fatalf("expire: %v", err)
+	}
+	finalTask, err := tasks.GetTask(ctx, resp.GetTask().GetId(), 0, true)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if finalTask.GetStatus().GetState() != a2av1.TaskState_TASK_STATE_REJECTED {
+		t.Fatalf("expected rejected, got %s", finalTask.GetStatus().GetState())
+	}
+	list, err := reopened.List(ctx, ApprovalFilter{Status: ApprovalStatusRejected})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected rejected approval, got %d", len(list))
+	}
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/governance"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// approvalKindToolCall marks, in the "approval_kind" metadata of the
+// approval message, approvals that gate a tool call of a running agent.
+const approvalKindToolCall = "tool_call"
+
+// DefaultApprovalPollInterval is how often an ApprovalGate checks the store
+// for a decision.
+const DefaultApprovalPollInterval = time.Second
+
+// ApprovalGate pauses the tool calls of a running agent until they are
+// decided through an ApprovalStore, so the same approval endpoints and
+// `kairos approvals` commands used for tasks resolve them. It implements
+// agent.ApprovalGate.
+type ApprovalGate struct {
+	store        ApprovalStore
+	tools        []string
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// ApprovalGateOption configures an ApprovalGate.
+type ApprovalGateOption func(*ApprovalGate)
+
+// WithGateTimeout expires approvals that are not decided within timeout;
+// expired approvals reject the tool call.
+func WithGateTimeout(timeout time.Duration) ApprovalGateOption {
+	return func(g *ApprovalGate) {
+		g.timeout = timeout
+	}
+}
+
+// WithGatePollInterval sets how often the store is checked for a decision.
+func WithGatePollInterval(interval time.Duration) ApprovalGateOption {
+	return func(g *ApprovalGate) {
+		if interval > 0 {
+			g.pollInterval = interval
+		}
+	}
+}
+
+// NewApprovalGate creates a gate that requires approval for the tools
+// matching any of the patterns (path.Match syntax, e.g. "delete_*").
+func NewApprovalGate(store ApprovalStore, tools []string, opts ...ApprovalGateOption) *ApprovalGate {
+	g := &ApprovalGate{
+		store:        store,
+		tools:        tools,
+		pollInterval: DefaultApprovalPollInterval,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// RequiresApproval reports whether toolName matches one of the gated
+// patterns.
+func (g *ApprovalGate) RequiresApproval(toolName string) bool {
+	for _, pattern := range g.tools {
+		if pattern == toolName {
+			return true
+		}
+		if ok, err := path.Match(pattern, toolName); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Await records a pending approval for the tool call and waits until it is
+// decided or expires, or ctx is done.
+func (g *ApprovalGate) Await(ctx context.Context, action governance.Action) governance.Decision {
+	if g.store == nil {
+		return deniedDecision("approval store not configured")
+	}
+	message, err := toolCallApprovalMessage(action)
+	if err != nil {
+		return deniedDecision(err.Error())
+	}
+	taskID := action.Metadata["task_id"]
+	if taskID == "" {
+		taskID = action.Metadata["run_id"]
+	}
+	record, err := g.store.Create(ctx, ApprovalRecord{
+		TaskID:    taskID,
+		ContextID: action.Metadata["session_id"],
+		ToolName:  action.Name,
+		ExpiresAt: approvalExpiry(approvalClock(g.store), g.timeout),
+		Message:   message,
+	})
+	if err != nil {
+		return deniedDecision(fmt.Sprintf("create approval: %v", err))
+	}
+
+	ticker := time.NewTicker(g.pollInterval)
+	defer ticker.Stop()
+	for {
+		current, err := g.store.Get(ctx, record.ID)
+		if err != nil {
+			return deniedDecision(fmt.Sprintf("get approval: %v", err))
+		}
+		switch current.Status {
+		case ApprovalStatusApproved:
+			return governance.Decision{Allowed: true, Status: governance.DecisionStatusAllow, Reason: current.Reason}
+		case ApprovalStatusRejected:
+			return deniedDecision(current.Reason)
+		}
+		if isApprovalExpired(approvalClock(g.store), current) {
+			_, _ = g.store.UpdateStatus(ctx, record.ID, ApprovalStatusRejected, "approval expired")
+			return deniedDecision("approval expired")
+		}
+
+		select {
+		case <-ctx.Done():
+			// Nobody waits for this approval anymore.
+			reason := fmt.Sprintf("approval cancelled: %v", ctx.Err())
+			_, _ = g.store.UpdateStatus(context.WithoutCancel(ctx), record.ID, ApprovalStatusRejected, reason)
+			return deniedDecision(reason)
+		case <-ticker.C:
+		}
+	}
+}
+
+// toolCallApprovalMessage describes the gated call for the approver.
+func toolCallApprovalMessage(action governance.Action) (*a2av1.Message, error) {
+	text := fmt.Sprintf("Approve call to tool %q", action.Name)
+	if args := action.Metadata["arguments"]; args != "" {
+		text += " with arguments " + args
+	}
+	metadata := map[string]interface{}{"approval_kind": approvalKindToolCall}
+	for key, value := range action.Metadata {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	payload, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &a2av1.Message{
+		MessageId: uuid.NewString(),
+		ContextId: action.Metadata["session_id"],
+		TaskId:    action.Metadata["task_id"],
+		Role:      a2av1.Role_ROLE_AGENT,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: text}}},
+		Metadata:  payload,
+	}, nil
+}
+
+// isToolCallApproval reports whether record gates a tool call of a running
+// agent, which resumes on its own once the approval is decided.
+func isToolCallApproval(record *ApprovalRecord) bool {
+	if record == nil || record.Message.GetMetadata() == nil {
+		return false
+	}
+	kind := record.Message.GetMetadata().GetFields()["approval_kind"]
+	return kind.GetStringValue() == approvalKindToolCall
+}
+
+func deniedDecision(reason string) governance.Decision {
+	return governance.Decision{Allowed: false, Status: governance.DecisionStatusDeny, Reason: reason}
+}
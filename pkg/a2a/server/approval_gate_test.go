@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/agent"
+	"github.com/jllopis/kairos/pkg/governance"
+)
+
+var _ agent.ApprovalGate = (*ApprovalGate)(nil)
+
+func gatedAction() governance.Action {
+	return governance.Action{
+		Type: governance.ActionTool,
+		Name: "delete_records",
+		Metadata: map[string]string{
+			"run_id":       "run-1",
+			"tool_call_id": "call-1",
+			"arguments":    `{"table":"users"}`,
+		},
+	}
+}
+
+// awaitPending returns the id of the first pending approval in store.
+func awaitPending(t *testing.T, store ApprovalStore) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		list, err := store.List(context.Background(), ApprovalFilter{Status: ApprovalStatusPending})
+		if err != nil {
+			t.Errorf("list: %v", err)
+			return ""
+		}
+		if len(list) > 0 {
+			return list[0].ID
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("no pending approval")
+	return ""
+}
+
+func TestApprovalGate_RequiresApproval(t *testing.T) {
+	gate := NewApprovalGate(NewMemoryApprovalStore(), []string{"delete_*", "deploy"})
+	for tool, want := range map[string]bool{
+		"delete_records": true,
+		"deploy":         true,
+		"deploy_preview": false,
+		"search":         false,
+	} {
+		if got := gate.RequiresApproval(tool); got != want {
+			t.Errorf("%s: expected %v, got %v", tool, want, got)
+		}
+	}
+}
+
+func TestApprovalGate_Decisions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryApprovalStore()
+	handler := &SimpleHandler{Store: NewMemoryTaskStore(), ApprovalStore: store}
+	gate := NewApprovalGate(store, []string{"delete_*"}, WithGatePollInterval(5*time.Millisecond))
+
+	for _, approve := range []bool{true, false} {
+		done := make(chan governance.Decision, 1)
+		go func() { done <- gate.Await(ctx, gatedAction()) }()
+
+		id := awaitPending(t, store)
+		record, err := store.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if record.TaskID != "run-1" || record.ToolName != "delete_records" || !isToolCallApproval(record) {
+			t.Fatalf("unexpected approval: %+v", record)
+		}
+		if approve {
+			task, err := handler.Approve(ctx, id, "go ahead")
+			if err != nil || task.GetId() != "run-1" {
+				t.Fatalf("approve: %v, %v", task, err)
+			}
+		} else if _, err := handler.Reject(ctx, id, "too risky"); err != nil {
+			t.Fatalf("reject: %v", err)
+		}
+
+		decision := <-done
+		if decision.IsAllowed() != approve {
+			t.Fatalf("expected allowed %v, got %+v", approve, decision)
+		}
+		if !approve && decision.Reason != "too risky" {
+			t.Fatalf("expected rejection reason, got %q", decision.Reason)
+		}
+	}
+}
+
+func TestApprovalGate_ExpiryAndCancel(t *testing.T) {
+	store := NewMemoryApprovalStore()
+	gate := NewApprovalGate(store, []string{"delete_*"},
+		WithGateTimeout(20*time.Millisecond),
+		WithGatePollInterval(5*time.Millisecond),
+	)
+	decision := gate.Await(context.Background(), gatedAction())
+	if decision.IsAllowed() || decision.Reason != "approval expired" {
+		t.Fatalf("expected expired approval, got %+v", decision)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	decision = NewApprovalGate(store, []string{"delete_*"}, WithGatePollInterval(5*time.Millisecond)).Await(ctx, gatedAction())
+	if decision.IsAllowed() {
+		t.Fatalf("expected cancelled approval to deny the call")
+	}
+	pending, err := store.List(context.Background(), ApprovalFilter{Status: ApprovalStatusPending})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending approvals left, got %d", len(pending))
+	}
+}
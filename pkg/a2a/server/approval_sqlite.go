@@ -59,8 +59,8 @@ func (s *SQLiteApprovalStore) Create(ctx context.Context, record ApprovalRecord)
 		expiresAt = record.ExpiresAt.UnixMilli()
 	}
 	_, err = s.db.ExecContext(ctx,
-		fmt.Sprintf("INSERT INTO %s (id, task_id, context_id, status, reason, created_at, updated_at, message_json, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", approvalTable),
-		record.ID, record.TaskID, record.ContextID, string(record.Status), record.Reason, record.CreatedAt.UnixMilli(), record.UpdatedAt.UnixMilli(), payload, expiresAt)
+		fmt.Sprintf("INSERT INTO %s (id, task_id, context_id, tool_name, status, reason, created_at, updated_at, message_json, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", approvalTable),
+		record.ID, record.TaskID, record.ContextID, record.ToolName, string(record.Status), record.Reason, record.CreatedAt.UnixMilli(), record.UpdatedAt.UnixMilli(), payload, expiresAt)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +70,7 @@ func (s *SQLiteApprovalStore) Create(ctx context.Context, record ApprovalRecord)
 // Get returns an approval record by id.
 func (s *SQLiteApprovalStore) Get(ctx context.Context, id string) (*ApprovalRecord, error) {
 	row := s.db.QueryRowContext(ctx,
-		fmt.Sprintf("SELECT id, task_id, context_id, status, reason, created_at, updated_at, message_json, expires_at FROM %s WHERE id = ?", approvalTable),
+		fmt.Sprintf("SELECT id, task_id, context_id, tool_name, status, reason, created_at, updated_at, message_json, expires_at FROM %s WHERE id = ?", approvalTable),
 		id,
 	)
 	var (
@@ -81,7 +81,7 @@ func (s *SQLiteApprovalStore) Get(ctx context.Context, id string) (*ApprovalReco
 		expiresAtMs int64
 		messageJSON []byte
 	)
-	if err := row.Scan(&record.ID, &record.TaskID, &record.ContextID, &status, &record.Reason, &createdAtMs, &updatedAtMs, &messageJSON, &expiresAtMs); err != nil {
+	if err := row.Scan(&record.ID, &record.TaskID, &record.ContextID, &record.ToolName, &status, &record.Reason, &createdAtMs, &updatedAtMs, &messageJSON, &expiresAtMs); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("approval %q not found", id)
 		}
@@ -115,6 +115,10 @@ func (s *SQLiteApprovalStore) List(ctx context.Context, filter ApprovalFilter) (
 		where += " AND context_id = ?"
 		args = append(args, filter.ContextID)
 	}
+	if filter.ToolName != "" {
+		where += " AND tool_name = ?"
+		args = append(args, filter.ToolName)
+	}
 	if filter.Status != "" {
 		where += " AND status = ?"
 		args = append(args, string(filter.Status))
@@ -127,7 +131,7 @@ func (s *SQLiteApprovalStore) List(ctx context.Context, filter ApprovalFilter) (
 	if filter.Limit > 0 {
 		limit = fmt.Sprintf(" LIMIT %d", filter.Limit)
 	}
-	query := fmt.Sprintf("SELECT id, task_id, context_id, status, reason, created_at, updated_at, message_json, expires_at FROM %s WHERE %s ORDER BY updated_at DESC%s", approvalTable, where, limit)
+	query := fmt.Sprintf("SELECT id, task_id, context_id, tool_name, status, reason, created_at, updated_at, message_json, expires_at FROM %s WHERE %s ORDER BY updated_at DESC%s", approvalTable, where, limit)
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -143,7 +147,7 @@ func (s *SQLiteApprovalStore) List(ctx context.Context, filter ApprovalFilter) (
 			expiresAtMs int64
 			messageJSON []byte
 		)
-		if err := rows.Scan(&record.ID, &record.TaskID, &record.ContextID, &status, &record.Reason, &createdAtMs, &updatedAtMs, &messageJSON, &expiresAtMs); err != nil {
+		if err := rows.Scan(&record.ID, &record.TaskID, &record.ContextID, &record.ToolName, &status, &record.Reason, &createdAtMs, &updatedAtMs, &messageJSON, &expiresAtMs); err != nil {
 			return nil, err
 		}
 		record.Status = ApprovalStatus(status)
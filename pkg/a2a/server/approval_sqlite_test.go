@@ -74,3 +74,46 @@ func TestSQLiteApprovalStore_CRUD(t *testing.T) {
 		t.Fatalf("expected expiring results")
 	}
 }
+
+func TestSQLiteApprovalStore_FilterByToolAndContext(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	// Simulate a database created before tool_name existed.
+	if _, err := db.Exec(`CREATE TABLE a2a_approvals (
+		id TEXT PRIMARY KEY,
+		task_id TEXT NOT NULL,
+		context_id TEXT NOT NULL,
+		status TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		message_json BLOB NOT NULL
+	);`); err != nil {
+		t.Fatalf("legacy schema: %v", err)
+	}
+	store, err := NewSQLiteApprovalStore(db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	message := &a2av1.Message{MessageId: uuid.NewString(), Role: a2av1.Role_ROLE_USER}
+	for _, rec := range []ApprovalRecord{
+		{TaskID: "t1", ContextID: "ctx-a", ToolName: "deploy"},
+		{TaskID: "t2", ContextID: "ctx-a", ToolName: "refund"},
+		{TaskID: "t3", ContextID: "ctx-b", ToolName: "deploy"},
+	} {
+		rec.Message = message
+		if _, err := store.Create(context.Background(), rec); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	list, err := store.List(context.Background(), ApprovalFilter{ContextID: "ctx-a", ToolName: "deploy"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 || list[0].TaskID != "t1" || list[0].ToolName != "deploy" {
+		t.Fatalf("unexpected results: %+v", list)
+	}
+}
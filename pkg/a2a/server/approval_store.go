@@ -3,11 +3,13 @@ package server
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -21,10 +23,13 @@ const (
 )
 
 // ApprovalRecord stores approval state for a pending task execution.
+// ToolName identifies what the approval gates: the tool or skill named in the
+// request metadata, or the agent itself.
 type ApprovalRecord struct {
 	ID        string         `json:"id"`
 	TaskID    string         `json:"task_id"`
 	ContextID string         `json:"context_id"`
+	ToolName  string         `json:"tool_name,omitempty"`
 	Status    ApprovalStatus `json:"status"`
 	Reason    string         `json:"reason,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
@@ -37,6 +42,7 @@ type ApprovalRecord struct {
 type ApprovalFilter struct {
 	TaskID         string
 	ContextID      string
+	ToolName       string
 	Status         ApprovalStatus
 	Limit          int
 	ExpiringBefore time.Time
@@ -58,15 +64,102 @@ type ApprovalHandler interface {
 	Reject(ctx context.Context, id, reason string) (*a2av1.Task, error)
 }
 
+// ApprovalResult reports the outcome of one approval in a batch decision.
+type ApprovalResult struct {
+	ID string `json:"id"`
+	// Status is the decision applied; empty when Error is set.
+	Status    ApprovalStatus `json:"status,omitempty"`
+	TaskID    string         `json:"task_id,omitempty"`
+	TaskState string         `json:"task_state,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// asyncApprover is implemented by approval handlers that can resume an
+// approved task in the background.
+type asyncApprover interface {
+	ApproveAsync(ctx context.Context, id, reason string) (*a2av1.Task, error)
+}
+
+// DecideApprovals approves or rejects each id in order. A failure is recorded
+// in the corresponding result and does not stop the remaining ids. When the
+// handler supports it, as SimpleHandler does, the decisions are recorded
+// before returning and approved tasks resume in the background, so a batch
+// does not wait for every task to run.
+func DecideApprovals(ctx context.Context, handler ApprovalHandler, ids []string, decision ApprovalStatus, reason string) ([]ApprovalResult, error) {
+	if decision != ApprovalStatusApproved && decision != ApprovalStatusRejected {
+		return nil, fmt.Errorf("invalid decision %q", decision)
+	}
+	results := make([]ApprovalResult, 0, len(ids))
+	for _, id := range ids {
+		result := ApprovalResult{ID: id}
+		var task *a2av1.Task
+		var err error
+		if async, ok := handler.(asyncApprover); ok && decision == ApprovalStatusApproved {
+			task, err = async.ApproveAsync(ctx, id, reason)
+		} else if decision == ApprovalStatusApproved {
+			task, err = handler.Approve(ctx, id, reason)
+		} else {
+			task, err = handler.Reject(ctx, id, reason)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Status = decision
+			result.TaskID = task.GetId()
+			result.TaskState = task.GetStatus().GetState().String()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // MemoryApprovalStore keeps approvals in memory.
 type MemoryApprovalStore struct {
 	mu        sync.RWMutex
 	approvals map[string]*ApprovalRecord
+	clock     clock.Clock
+}
+
+// MemoryApprovalStoreOption configures a MemoryApprovalStore.
+type MemoryApprovalStoreOption func(*MemoryApprovalStore)
+
+// WithApprovalClock sets the clock used for approval timestamps. Handlers
+// and approval gates backed by the store also use it to compute and check
+// approval expiry. It defaults to the real clock.
+func WithApprovalClock(c clock.Clock) MemoryApprovalStoreOption {
+	return func(s *MemoryApprovalStore) {
+		if c != nil {
+			s.clock = c
+		}
+	}
 }
 
 // NewMemoryApprovalStore creates an in-memory approval store.
-func NewMemoryApprovalStore() *MemoryApprovalStore {
-	return &MemoryApprovalStore{approvals: make(map[string]*ApprovalRecord)}
+func NewMemoryApprovalStore(opts ...MemoryApprovalStoreOption) *MemoryApprovalStore {
+	s := &MemoryApprovalStore{
+		approvals: make(map[string]*ApprovalRecord),
+		clock:     clock.Real(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Clock returns the clock the store uses.
+func (s *MemoryApprovalStore) Clock() clock.Clock {
+	return s.clock
+}
+
+// approvalClock returns the clock of store when it exposes one, or the
+// real clock.
+func approvalClock(store ApprovalStore) clock.Clock {
+	if c, ok := store.(interface{ Clock() clock.Clock }); ok {
+		return clock.OrReal(c.Clock())
+	}
+	return clock.Real()
 }
 
 // Create inserts a new approval record.
@@ -80,7 +173,7 @@ func (s *MemoryApprovalStore) Create(_ context.Context, record ApprovalRecord) (
 	if record.Status == "" {
 		record.Status = ApprovalStatusPending
 	}
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
 	if record.CreatedAt.IsZero() {
 		record.CreatedAt = now
 	}
@@ -98,15 +191,15 @@ func (s *MemoryApprovalStore) Create(_ context.Context, record ApprovalRecord) (
 // Get returns an approval record by id.
 func (s *MemoryApprovalStore) Get(_ context.Context, id string) (*ApprovalRecord, error) {
 	s.mu.RLock()
+	defer s.mu.RUnlock()
 	record, ok := s.approvals[id]
-	s.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("approval %q not found", id)
 	}
 	return cloneApproval(record), nil
 }
 
-// List returns approvals matching the filter.
+// List returns approvals matching the filter, most recently updated first.
 func (s *MemoryApprovalStore) List(_ context.Context, filter ApprovalFilter) ([]*ApprovalRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -118,6 +211,9 @@ func (s *MemoryApprovalStore) List(_ context.Context, filter ApprovalFilter) ([]
 		if filter.ContextID != "" && record.ContextID != filter.ContextID {
 			continue
 		}
+		if filter.ToolName != "" && record.ToolName != filter.ToolName {
+			continue
+		}
 		if filter.Status != "" && record.Status != filter.Status {
 			continue
 		}
@@ -127,9 +223,12 @@ func (s *MemoryApprovalStore) List(_ context.Context, filter ApprovalFilter) ([]
 			}
 		}
 		out = append(out, cloneApproval(record))
-		if filter.Limit > 0 && len(out) >= filter.Limit {
-			break
-		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].UpdatedAt.After(out[j].UpdatedAt)
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
 	}
 	return out, nil
 }
@@ -144,7 +243,7 @@ func (s *MemoryApprovalStore) UpdateStatus(_ context.Context, id string, status
 	}
 	record.Status = status
 	record.Reason = reason
-	record.UpdatedAt = time.Now().UTC()
+	record.UpdatedAt = s.clock.Now().UTC()
 	return cloneApproval(record), nil
 }
 
@@ -7,7 +7,9 @@ import (
 
 	"github.com/google/uuid"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
 	"github.com/jllopis/kairos/pkg/governance"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestMemoryApprovalStore_CRUD(t *testing.T) {
@@ -168,6 +170,82 @@ func TestHandlerRejectFlow(t *testing.T) {
 	}
 }
 
+func TestDecideApprovalsResumesTasksAsync(t *testing.T) {
+	ctx := context.Background()
+	exec := &gatedExecutor{release: make(chan struct{})}
+	handler := &SimpleHandler{
+		Store:         NewMemoryTaskStore(),
+		Executor:      exec,
+		PolicyEngine:  governance.NewRuleSet([]governance.Rule{{Effect: "pending", Type: governance.ActionAgent}}),
+		ApprovalStore: NewMemoryApprovalStore(),
+	}
+	var ids, taskIDs []string
+	for i := 0; i < 2; i++ {
+		msg := &a2av1.Message{
+			MessageId: uuid.NewString(),
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "ping"}}},
+		}
+		resp, err := handler.SendMessage(ctx, &a2av1.SendMessageRequest{Request: msg})
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		taskIDs = append(taskIDs, resp.GetTask().GetId())
+		ids = append(ids, resp.GetTask().GetStatus().GetMessage().GetMetadata().GetFields()["approval_id"].GetStringValue())
+	}
+
+	// The executor is blocked, so a synchronous approval would not return.
+	var results []ApprovalResult
+	decided := make(chan error, 1)
+	go func() {
+		var err error
+		results, err = DecideApprovals(ctx, handler, ids, ApprovalStatusApproved, "ok")
+		decided <- err
+	}()
+	select {
+	case err := <-decided:
+		if err != nil {
+			t.Fatalf("decide: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		close(exec.release)
+		t.Fatal("DecideApprovals waited for the approved tasks to run")
+	}
+	for _, result := range results {
+		if result.Error != "" {
+			t.Fatalf("approve %s: %s", result.ID, result.Error)
+		}
+		if result.TaskState != a2av1.TaskState_TASK_STATE_WORKING.String() {
+			t.Fatalf("expected working, got %s", result.TaskState)
+		}
+		approval, err := handler.ApprovalStore.Get(ctx, result.ID)
+		if err != nil {
+			t.Fatalf("get approval: %v", err)
+		}
+		if approval.Status != ApprovalStatusApproved {
+			t.Fatalf("expected approved record, got %s", approval.Status)
+		}
+	}
+
+	close(exec.release)
+	for _, taskID := range taskIDs {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			task, err := handler.Store.GetTask(ctx, taskID, 0, true)
+			if err != nil {
+				t.Fatalf("get task: %v", err)
+			}
+			if task.GetStatus().GetState() == a2av1.TaskState_TASK_STATE_COMPLETED {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected completed, got %s", task.GetStatus().GetState())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
 func TestHandlerExpireApprovals(t *testing.T) {
 	ctx := context.Background()
 	handler := &SimpleHandler{
@@ -221,3 +299,81 @@ func TestHandlerExpireApprovals(t *testing.T) {
 		t.Fatalf("expected rejected after expired approve")
 	}
 }
+
+func TestHandlerApprovalRecordsToolName(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryApprovalStore()
+	handler := &SimpleHandler{
+		Store:         NewMemoryTaskStore(),
+		Executor:      &approvalTestExecutor{},
+		Card:          &a2av1.AgentCard{Name: "billing-agent"},
+		PolicyEngine:  governance.NewRuleSet([]governance.Rule{{Effect: "pending", Type: governance.ActionAgent}}),
+		ApprovalStore: store,
+	}
+	send := func(metadata map[string]any) {
+		t.Helper()
+		msg := &a2av1.Message{
+			MessageId: uuid.NewString(),
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "ping"}}},
+		}
+		if metadata != nil {
+			msg.Metadata, _ = structpb.NewStruct(metadata)
+		}
+		if _, err := handler.SendMessage(ctx, &a2av1.SendMessageRequest{Request: msg}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	send(nil)
+	send(map[string]any{"skill": "refunds"})
+
+	list, err := store.List(ctx, ApprovalFilter{ToolName: "refunds"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 approval for skill, got %d", len(list))
+	}
+	list, err = store.List(ctx, ApprovalFilter{ToolName: "billing-agent"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 approval for agent, got %d", len(list))
+	}
+}
+
+func TestHandlerExpireApprovalsUsesStoreClock(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake()
+	handler := &SimpleHandler{
+		Store:           NewMemoryTaskStore(),
+		Executor:        &approvalTestExecutor{},
+		PolicyEngine:    governance.NewRuleSet([]governance.Rule{{Effect: "pending", Type: governance.ActionAgent}}),
+		ApprovalStore:   NewMemoryApprovalStore(WithApprovalClock(fake)),
+		ApprovalTimeout: time.Minute,
+	}
+	msg := &a2av1.Message{
+		MessageId: uuid.NewString(),
+		Role:      a2av1.Role_ROLE_USER,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "ping"}}},
+	}
+	if _, err := handler.SendMessage(ctx, &a2av1.SendMessageRequest{Request: msg}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	records, err := handler.ApprovalStore.List(ctx, ApprovalFilter{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected one approval, got %d (%v)", len(records), err)
+	}
+	if want := fake.Now().Add(time.Minute); !records[0].ExpiresAt.Equal(want) {
+		t.Fatalf("expected expiry %v, got %v", want, records[0].ExpiresAt)
+	}
+
+	if expired, err := handler.ExpireApprovals(ctx); err != nil || expired != 0 {
+		t.Fatalf("expected no expired approvals yet, got %d (%v)", expired, err)
+	}
+	fake.Advance(2 * time.Minute)
+	if expired, err := handler.ExpireApprovals(ctx); err != nil || expired != 1 {
+		t.Fatalf("expected 1 expired approval, got %d (%v)", expired, err)
+	}
+}
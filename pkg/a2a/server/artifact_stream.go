@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ArtifactStreamingKey is the artifact metadata key set to true while an
+// artifact is still receiving chunks through AppendArtifactChunk. It is
+// removed when the last chunk arrives.
+const ArtifactStreamingKey = "kairos_streaming"
+
+// appendArtifactChunk adds chunk as a new part of the artifact artifactID in
+// task, creating the artifact on its first chunk.
+func appendArtifactChunk(task *a2av1.Task, artifactID string, chunk *a2av1.Part, lastChunk bool) error {
+	if artifactID == "" {
+		return fmt.Errorf("artifact id is required")
+	}
+	if chunk == nil {
+		return fmt.Errorf("chunk is nil")
+	}
+	chunk = proto.Clone(chunk).(*a2av1.Part)
+
+	var artifact *a2av1.Artifact
+	for _, candidate := range task.Artifacts {
+		if candidate.GetArtifactId() == artifactID {
+			artifact = candidate
+			break
+		}
+	}
+	if artifact == nil {
+		artifact = &a2av1.Artifact{ArtifactId: artifactID}
+		task.Artifacts = append(task.Artifacts, artifact)
+		setArtifactStreaming(artifact, true)
+	} else if !isArtifactStreaming(artifact) {
+		return fmt.Errorf("artifact %q is already complete", artifactID)
+	}
+	artifact.Parts = append(artifact.Parts, chunk)
+	if lastChunk {
+		setArtifactStreaming(artifact, false)
+	}
+	return nil
+}
+
+func isArtifactStreaming(artifact *a2av1.Artifact) bool {
+	value, ok := artifact.GetMetadata().GetFields()[ArtifactStreamingKey]
+	return ok && value.GetBoolValue()
+}
+
+func setArtifactStreaming(artifact *a2av1.Artifact, streaming bool) {
+	if streaming {
+		if artifact.Metadata == nil {
+			artifact.Metadata = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		}
+		artifact.Metadata.Fields[ArtifactStreamingKey] = structpb.NewBoolValue(true)
+		return
+	}
+	if artifact.Metadata == nil {
+		return
+	}
+	delete(artifact.Metadata.Fields, ArtifactStreamingKey)
+	if len(artifact.Metadata.Fields) == 0 {
+		artifact.Metadata = nil
+	}
+}
+
+// artifactUpdates returns the artifact events for the parts of task not yet
+// sent, one event per part. sent holds the number of parts already sent for
+// each artifact, by position, or -1 for artifacts not announced yet;
+// artifacts are append-only, so positions are stable. It returns the updated
+// counts.
+func artifactUpdates(task *a2av1.Task, sent []int) ([]*a2av1.TaskArtifactUpdateEvent, []int) {
+	var events []*a2av1.TaskArtifactUpdateEvent
+	for i, artifact := range task.GetArtifacts() {
+		if i == len(sent) {
+			sent = append(sent, -1)
+		}
+		parts := artifact.GetParts()
+		streaming := isArtifactStreaming(artifact)
+		if sent[i] < 0 && !streaming {
+			// Complete artifacts are sent whole.
+			events = append(events, &a2av1.TaskArtifactUpdateEvent{
+				TaskId:    task.GetId(),
+				ContextId: task.GetContextId(),
+				Artifact:  artifact,
+				LastChunk: true,
+			})
+			sent[i] = len(parts)
+			continue
+		}
+		for j := max(sent[i], 0); j < len(parts); j++ {
+			chunk := &a2av1.Artifact{ArtifactId: artifact.GetArtifactId(), Parts: []*a2av1.Part{parts[j]}}
+			if j == 0 {
+				chunk.Name = artifact.GetName()
+				chunk.Description = artifact.GetDescription()
+				chunk.Extensions = artifact.GetExtensions()
+			}
+			events = append(events, &a2av1.TaskArtifactUpdateEvent{
+				TaskId:    task.GetId(),
+				ContextId: task.GetContextId(),
+				Artifact:  chunk,
+				Append:    j > 0,
+				LastChunk: !streaming && j == len(parts)-1,
+			})
+		}
+		sent[i] = max(sent[i], len(parts))
+	}
+	return events, sent
+}
@@ -4,13 +4,16 @@ package server
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
 	"github.com/jllopis/kairos/pkg/governance"
+	klog "github.com/jllopis/kairos/pkg/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -18,6 +21,8 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+var logger = klog.For("server")
+
 // Executor runs a task and returns a response message payload.
 type Executor interface {
 	Run(ctx context.Context, message *a2av1.Message) (any, []*a2av1.Artifact, error)
@@ -25,15 +30,31 @@ type Executor interface {
 
 // SimpleHandler implements core A2A operations using a TaskStore and Executor.
 type SimpleHandler struct {
-	Store           TaskStore
-	Executor        Executor
-	Card            *a2av1.AgentCard
-	PushCfgs        PushConfigStore
+	Store    TaskStore
+	Executor Executor
+	Card     *a2av1.AgentCard
+	PushCfgs PushConfigStore
+	// PushNotifier delivers status updates to the PushCfgs webhooks.
+	PushNotifier    *PushNotifier
 	PolicyEngine    governance.PolicyEngine
 	ApprovalHook    governance.ApprovalHook
 	ApprovalStore   ApprovalStore
 	ApprovalTimeout time.Duration
 	AsyncTimeout    time.Duration
+	// MessageLimits bounds incoming messages; nil uses DefaultMessageLimits.
+	MessageLimits *MessageLimits
+	// Tracing stamps the request trace ID into streamed status messages.
+	Tracing bool
+	// Logger receives the handler logs; nil uses the framework logger.
+	Logger *slog.Logger
+}
+
+// logger returns the handler logger tagged with component=server.
+func (h *SimpleHandler) logger() *slog.Logger {
+	if h.Logger != nil {
+		return h.Logger.With(slog.String(klog.ComponentKey, "server"))
+	}
+	return logger
 }
 
 // AgentCard exposes the configured agent card for capability checks.
@@ -47,22 +68,34 @@ func (h *SimpleHandler) SendMessage(ctx context.Context, req *a2av1.SendMessageR
 		return nil, status.Error(codes.FailedPrecondition, "handler not configured")
 	}
 	message := req.GetRequest()
-	if err := ValidateMessage(message); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+	if err := h.validateMessage(message); err != nil {
+		return nil, err
 	}
 
-	if response, ok, err := h.applyPolicy(ctx, message); ok {
-		return response, err
+	blocking := false
+	if cfg := req.GetConfiguration(); cfg != nil {
+		blocking = cfg.GetBlocking()
 	}
 
-	task, _, err := h.ensureTask(ctx, message)
+	// A retried request with the same idempotency key gets the original task
+	// back, before policies run again.
+	task, duplicate, err := h.createIdempotentTask(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	if duplicate {
+		return duplicateResponse(task, blocking), nil
+	}
 
-	blocking := false
-	if cfg := req.GetConfiguration(); cfg != nil {
-		blocking = cfg.GetBlocking()
+	if response, ok, err := h.applyPolicy(ctx, message, task); ok {
+		return response, err
+	}
+
+	if task == nil {
+		task, _, err = h.ensureTask(ctx, message)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if blocking {
@@ -84,8 +117,8 @@ func (h *SimpleHandler) SendStreamingMessage(req *a2av1.SendMessageRequest, stre
 		return status.Error(codes.FailedPrecondition, "handler not configured")
 	}
 	message := req.GetRequest()
-	if err := ValidateMessage(message); err != nil {
-		return status.Error(codes.InvalidArgument, err.Error())
+	if err := h.validateMessage(message); err != nil {
+		return err
 	}
 
 	handled, err := h.applyStreamingPolicy(stream.Context(), message, stream)
@@ -129,7 +162,7 @@ func (h *SimpleHandler) SendStreamingMessage(req *a2av1.SendMessageRequest, stre
 	statusEvent := &a2av1.TaskStatusUpdateEvent{
 		TaskId:    task.Id,
 		ContextId: task.ContextId,
-		Status:    task.Status,
+		Status:    h.traceStatus(stream.Context(), task.Status),
 		Final:     true,
 	}
 	return stream.Send(&a2av1.StreamResponse{Payload: &a2av1.StreamResponse_StatusUpdate{StatusUpdate: statusEvent}})
@@ -227,6 +260,7 @@ func (h *SimpleHandler) CancelTask(ctx context.Context, req *a2av1.CancelTaskReq
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	h.notifyPush(ctx, task, task.GetStatus())
 	return task, nil
 }
 
@@ -246,9 +280,14 @@ func (h *SimpleHandler) SubscribeToTask(req *a2av1.SubscribeToTaskRequest, strea
 	}
 
 	lastStatus := task.GetStatus()
-	lastArtifactCount := len(task.GetArtifacts())
+	// Artifacts present at subscription time are part of the snapshot
+	// returned by GetTask; only parts added later are streamed.
+	sentParts := make([]int, len(task.GetArtifacts()))
+	for i, artifact := range task.GetArtifacts() {
+		sentParts[i] = len(artifact.GetParts())
+	}
 
-	if err := sendStatusUpdate(stream, task, lastStatus, isTerminalState(lastStatus.GetState())); err != nil {
+	if err := sendStatusUpdate(stream, task, h.traceStatus(stream.Context(), lastStatus), isTerminalState(lastStatus.GetState())); err != nil {
 		return err
 	}
 	if isTerminalState(lastStatus.GetState()) {
@@ -268,34 +307,27 @@ func (h *SimpleHandler) SubscribeToTask(req *a2av1.SubscribeToTaskRequest, strea
 				return status.Error(codes.NotFound, err.Error())
 			}
 
+			// Artifacts go first so every chunk stored before the task
+			// finished reaches the client before the final status.
+			var events []*a2av1.TaskArtifactUpdateEvent
+			events, sentParts = artifactUpdates(latest, sentParts)
+			for _, event := range events {
+				if err := stream.Send(&a2av1.StreamResponse{Payload: &a2av1.StreamResponse_ArtifactUpdate{ArtifactUpdate: event}}); err != nil {
+					return err
+				}
+			}
+
 			latestStatus := latest.GetStatus()
-			statusChanged := !proto.Equal(lastStatus, latestStatus)
-			if statusChanged {
+			if !proto.Equal(lastStatus, latestStatus) {
 				lastStatus = latestStatus
 				final := isTerminalState(latestStatus.GetState())
-				if err := sendStatusUpdate(stream, latest, latestStatus, final); err != nil {
+				if err := sendStatusUpdate(stream, latest, h.traceStatus(stream.Context(), latestStatus), final); err != nil {
 					return err
 				}
 				if final {
 					return nil
 				}
 			}
-
-			artifactCount := len(latest.GetArtifacts())
-			if artifactCount > lastArtifactCount {
-				for _, artifact := range latest.GetArtifacts()[lastArtifactCount:] {
-					event := &a2av1.TaskArtifactUpdateEvent{
-						TaskId:    latest.Id,
-						ContextId: latest.ContextId,
-						Artifact:  artifact,
-						Append:    true,
-					}
-					if err := stream.Send(&a2av1.StreamResponse{Payload: &a2av1.StreamResponse_ArtifactUpdate{ArtifactUpdate: event}}); err != nil {
-						return err
-					}
-				}
-				lastArtifactCount = artifactCount
-			}
 		}
 	}
 }
@@ -455,12 +487,12 @@ func (h *SimpleHandler) ensureTask(ctx context.Context, message *a2av1.Message)
 
 func (h *SimpleHandler) executeTask(ctx context.Context, task *a2av1.Task, message *a2av1.Message) (*a2av1.Message, []*a2av1.Artifact, error) {
 	statusWorking := newStatus(a2av1.TaskState_TASK_STATE_WORKING, message)
-	_ = h.Store.UpdateStatus(ctx, task.Id, statusWorking)
+	_ = h.updateStatus(ctx, task, statusWorking)
 
 	output, artifacts, err := h.Executor.Run(ctx, message)
 	if err != nil {
 		statusFailed := newStatus(a2av1.TaskState_TASK_STATE_FAILED, message)
-		_ = h.Store.UpdateStatus(ctx, task.Id, statusFailed)
+		_ = h.updateStatus(ctx, task, statusFailed)
 		return nil, nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -471,12 +503,36 @@ func (h *SimpleHandler) executeTask(ctx context.Context, task *a2av1.Task, messa
 	}
 
 	statusCompleted := newStatus(a2av1.TaskState_TASK_STATE_COMPLETED, respMsg)
-	_ = h.Store.UpdateStatus(ctx, task.Id, statusCompleted)
+	_ = h.updateStatus(ctx, task, statusCompleted)
 
 	task.Status = statusCompleted
 	return respMsg, artifacts, nil
 }
 
+// updateStatus stores the status of task and notifies its push webhooks.
+func (h *SimpleHandler) updateStatus(ctx context.Context, task *a2av1.Task, status *a2av1.TaskStatus) error {
+	if err := h.Store.UpdateStatus(ctx, task.Id, status); err != nil {
+		return err
+	}
+	h.notifyPush(ctx, task, status)
+	return nil
+}
+
+func (h *SimpleHandler) notifyPush(ctx context.Context, task *a2av1.Task, status *a2av1.TaskStatus) {
+	if h.PushNotifier == nil || h.PushCfgs == nil {
+		return
+	}
+	configs, err := h.PushCfgs.List(ctx, task.Id, 0)
+	if err != nil {
+		h.logger().WarnContext(ctx, "a2a.push.configs_failed",
+			slog.String("task_id", task.Id),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	h.PushNotifier.Notify(ctx, task.Id, task.ContextId, status, configs)
+}
+
 func (h *SimpleHandler) runAsync(parent context.Context, taskID string, message *a2av1.Message) {
 	if parent == nil {
 		parent = context.Background()
@@ -489,9 +545,35 @@ func (h *SimpleHandler) runAsync(parent context.Context, taskID string, message
 	}
 	task, err := h.Store.GetTask(ctx, taskID, 0, true)
 	if err != nil {
+		h.logger().ErrorContext(ctx, "a2a.task.async.load_failed",
+			slog.String("task_id", taskID),
+			slog.String("error", err.Error()),
+		)
 		return
 	}
-	_, _, _ = h.executeTask(ctx, task, message)
+	if _, _, err := h.executeTask(ctx, task, message); err != nil {
+		h.logger().ErrorContext(ctx, "a2a.task.async.failed",
+			slog.String("task_id", taskID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// InterruptTask marks a task that has not finished as failed, explaining
+// that reason cut it off. Terminal tasks are left untouched.
+func (h *SimpleHandler) InterruptTask(ctx context.Context, taskID, reason string) error {
+	if h.Store == nil {
+		return status.Error(codes.FailedPrecondition, "task store not configured")
+	}
+	task, err := h.Store.GetTask(ctx, taskID, 0, false)
+	if err != nil {
+		return err
+	}
+	if isTerminalState(task.GetStatus().GetState()) {
+		return nil
+	}
+	msg := ResponseMessage(reason, task.ContextId, task.Id)
+	return h.updateStatus(ctx, task, newStatus(a2av1.TaskState_TASK_STATE_FAILED, msg))
 }
 
 func isTerminalState(state a2av1.TaskState) bool {
@@ -506,7 +588,10 @@ func isTerminalState(state a2av1.TaskState) bool {
 	}
 }
 
-func (h *SimpleHandler) applyPolicy(ctx context.Context, message *a2av1.Message) (*a2av1.SendMessageResponse, bool, error) {
+// applyPolicy evaluates the policy for message. task is the task already
+// created for the message, or nil to create or resume it when the policy
+// does not allow the message.
+func (h *SimpleHandler) applyPolicy(ctx context.Context, message *a2av1.Message, task *a2av1.Task) (*a2av1.SendMessageResponse, bool, error) {
 	if h.PolicyEngine == nil {
 		return nil, false, nil
 	}
@@ -518,19 +603,23 @@ func (h *SimpleHandler) applyPolicy(ctx context.Context, message *a2av1.Message)
 	if decision.IsAllowed() {
 		return nil, false, nil
 	}
-	task, _, err := h.ensureTask(ctx, message)
-	if err != nil {
-		return nil, true, err
+	if task == nil {
+		var err error
+		task, _, err = h.ensureTask(ctx, message)
+		if err != nil {
+			return nil, true, err
+		}
 	}
 	message.TaskId = task.Id
 	message.ContextId = task.ContextId
 	approvalID := ""
 	expiresAt := time.Time{}
 	if decision.IsPending() && h.ApprovalStore != nil {
-		expiresAt = approvalExpiry(h.ApprovalTimeout)
+		expiresAt = approvalExpiry(approvalClock(h.ApprovalStore), h.ApprovalTimeout)
 		if record, err := h.ApprovalStore.Create(ctx, ApprovalRecord{
 			TaskID:    task.Id,
 			ContextID: task.ContextId,
+			ToolName:  approvalToolName(action, message),
 			Status:    ApprovalStatusPending,
 			Reason:    decision.Reason,
 			ExpiresAt: expiresAt,
@@ -550,7 +639,7 @@ func (h *SimpleHandler) applyPolicy(ctx context.Context, message *a2av1.Message)
 	}
 	_ = h.Store.AppendHistory(ctx, task.Id, statusMsg)
 	status := newStatus(state, statusMsg)
-	_ = h.Store.UpdateStatus(ctx, task.Id, status)
+	_ = h.updateStatus(ctx, task, status)
 	task.Status = status
 	return &a2av1.SendMessageResponse{Payload: &a2av1.SendMessageResponse_Task{Task: task}}, true, nil
 }
@@ -576,10 +665,11 @@ func (h *SimpleHandler) applyStreamingPolicy(ctx context.Context, message *a2av1
 	approvalID := ""
 	expiresAt := time.Time{}
 	if decision.IsPending() && h.ApprovalStore != nil {
-		expiresAt = approvalExpiry(h.ApprovalTimeout)
+		expiresAt = approvalExpiry(approvalClock(h.ApprovalStore), h.ApprovalTimeout)
 		if record, err := h.ApprovalStore.Create(ctx, ApprovalRecord{
 			TaskID:    task.Id,
 			ContextID: task.ContextId,
+			ToolName:  approvalToolName(action, message),
 			Status:    ApprovalStatusPending,
 			Reason:    decision.Reason,
 			ExpiresAt: expiresAt,
@@ -602,12 +692,12 @@ func (h *SimpleHandler) applyStreamingPolicy(ctx context.Context, message *a2av1
 	}
 	_ = h.Store.AppendHistory(ctx, task.Id, statusMsg)
 	status := newStatus(state, statusMsg)
-	_ = h.Store.UpdateStatus(ctx, task.Id, status)
+	_ = h.updateStatus(ctx, task, status)
 	task.Status = status
 	statusEvent := &a2av1.TaskStatusUpdateEvent{
 		TaskId:    task.Id,
 		ContextId: task.ContextId,
-		Status:    status,
+		Status:    h.traceStatus(ctx, status),
 		Final:     state == a2av1.TaskState_TASK_STATE_REJECTED,
 	}
 	return true, stream.Send(&a2av1.StreamResponse{Payload: &a2av1.StreamResponse_StatusUpdate{StatusUpdate: statusEvent}})
@@ -625,6 +715,19 @@ func (h *SimpleHandler) policyAction(message *a2av1.Message) governance.Action {
 	}
 }
 
+// approvalToolName returns the tool or skill named in the request metadata,
+// falling back to the governed action name.
+func approvalToolName(action governance.Action, message *a2av1.Message) string {
+	for _, key := range []string{"tool", "skill"} {
+		if val, ok := message.GetMetadata().GetFields()[key]; ok {
+			if str := strings.TrimSpace(val.GetStringValue()); str != "" {
+				return str
+			}
+		}
+	}
+	return action.Name
+}
+
 func policyMetadata(message *a2av1.Message) map[string]string {
 	if message == nil {
 		return nil
@@ -664,6 +767,17 @@ func policyStatus(decision governance.Decision) (a2av1.TaskState, string) {
 
 // Approve resolves a pending approval and executes the task.
 func (h *SimpleHandler) Approve(ctx context.Context, id, reason string) (*a2av1.Task, error) {
+	return h.approve(ctx, id, reason, true)
+}
+
+// ApproveAsync resolves a pending approval like Approve, but resumes the
+// task in the background, as a non-blocking SendMessage does, and returns
+// it in the working state.
+func (h *SimpleHandler) ApproveAsync(ctx context.Context, id, reason string) (*a2av1.Task, error) {
+	return h.approve(ctx, id, reason, false)
+}
+
+func (h *SimpleHandler) approve(ctx context.Context, id, reason string, blocking bool) (*a2av1.Task, error) {
 	if h.ApprovalStore == nil {
 		return nil, status.Error(codes.FailedPrecondition, "approval store not configured")
 	}
@@ -677,10 +791,13 @@ func (h *SimpleHandler) Approve(ctx context.Context, id, reason string) (*a2av1.
 	if approval.Status == ApprovalStatusRejected {
 		return h.Store.GetTask(ctx, approval.TaskID, 0, true)
 	}
-	if isApprovalExpired(approval) {
+	if isApprovalExpired(approvalClock(h.ApprovalStore), approval) {
 		_, _ = h.ApprovalStore.UpdateStatus(ctx, id, ApprovalStatusRejected, "approval expired")
 		return h.Reject(ctx, id, "approval expired")
 	}
+	if isToolCallApproval(approval) {
+		return h.decideToolCallApproval(ctx, approval, ApprovalStatusApproved, reason)
+	}
 	if approval.Message == nil {
 		return nil, status.Error(codes.FailedPrecondition, "approval has no message")
 	}
@@ -694,6 +811,13 @@ func (h *SimpleHandler) Approve(ctx context.Context, id, reason string) (*a2av1.
 	if isTerminalState(task.GetStatus().GetState()) {
 		return task, nil
 	}
+	if !blocking {
+		status := newStatus(a2av1.TaskState_TASK_STATE_WORKING, approval.Message)
+		_ = h.updateStatus(ctx, task, status)
+		task.Status = status
+		go h.runAsync(ctx, task.Id, approval.Message)
+		return task, nil
+	}
 	if _, _, err := h.executeTask(ctx, task, approval.Message); err != nil {
 		return nil, err
 	}
@@ -712,9 +836,12 @@ func (h *SimpleHandler) Reject(ctx context.Context, id, reason string) (*a2av1.T
 	if approval.Status == ApprovalStatusRejected {
 		return h.Store.GetTask(ctx, approval.TaskID, 0, true)
 	}
-	if isApprovalExpired(approval) {
+	if isApprovalExpired(approvalClock(h.ApprovalStore), approval) {
 		_, _ = h.ApprovalStore.UpdateStatus(ctx, id, ApprovalStatusRejected, "approval expired")
 	}
+	if isToolCallApproval(approval) {
+		return h.decideToolCallApproval(ctx, approval, ApprovalStatusRejected, reason)
+	}
 	if _, err := h.ApprovalStore.UpdateStatus(ctx, id, ApprovalStatusRejected, reason); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -728,11 +855,30 @@ func (h *SimpleHandler) Reject(ctx context.Context, id, reason string) (*a2av1.T
 	})
 	_ = h.Store.AppendHistory(ctx, task.Id, statusMsg)
 	status := newStatus(a2av1.TaskState_TASK_STATE_REJECTED, statusMsg)
-	_ = h.Store.UpdateStatus(ctx, task.Id, status)
+	_ = h.updateStatus(ctx, task, status)
 	task.Status = status
 	return task, nil
 }
 
+// decideToolCallApproval records the decision on an approval created by an
+// ApprovalGate. The gated agent run picks it up and goes on by itself, so
+// the task is returned as is; runs outside an A2A task get a placeholder.
+func (h *SimpleHandler) decideToolCallApproval(ctx context.Context, approval *ApprovalRecord, decision ApprovalStatus, reason string) (*a2av1.Task, error) {
+	if _, err := h.ApprovalStore.UpdateStatus(ctx, approval.ID, decision, reason); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if h.Store != nil {
+		if task, err := h.Store.GetTask(ctx, approval.TaskID, 0, true); err == nil {
+			return task, nil
+		}
+	}
+	return &a2av1.Task{
+		Id:        approval.TaskID,
+		ContextId: approval.ContextID,
+		Status:    newStatus(a2av1.TaskState_TASK_STATE_WORKING, nil),
+	}, nil
+}
+
 // GetApproval returns a single approval record.
 func (h *SimpleHandler) GetApproval(ctx context.Context, id string) (*ApprovalRecord, error) {
 	if h.ApprovalStore == nil {
@@ -778,7 +924,7 @@ func (h *SimpleHandler) ExpireApprovals(ctx context.Context) (int, error) {
 	if h.ApprovalStore == nil {
 		return 0, status.Error(codes.FailedPrecondition, "approval store not configured")
 	}
-	now := time.Now().UTC()
+	now := approvalClock(h.ApprovalStore).Now().UTC()
 	records, err := h.ApprovalStore.List(ctx, ApprovalFilter{
 		Status:         ApprovalStatusPending,
 		ExpiringBefore: now,
@@ -798,22 +944,19 @@ func (h *SimpleHandler) ExpireApprovals(ctx context.Context) (int, error) {
 	return expired, nil
 }
 
-func approvalExpiry(timeout time.Duration) time.Time {
+func approvalExpiry(c clock.Clock, timeout time.Duration) time.Time {
 	if timeout == 0 {
 		return time.Time{}
 	}
-	if timeout < 0 {
-		return time.Now().UTC().Add(timeout)
-	}
-	return time.Now().UTC().Add(timeout)
+	return c.Now().UTC().Add(timeout)
 }
 
-func isApprovalExpired(record *ApprovalRecord) bool {
+func isApprovalExpired(c clock.Clock, record *ApprovalRecord) bool {
 	if record == nil {
 		return false
 	}
 	if record.ExpiresAt.IsZero() {
 		return false
 	}
-	return time.Now().UTC().After(record.ExpiresAt)
+	return c.Now().UTC().After(record.ExpiresAt)
 }
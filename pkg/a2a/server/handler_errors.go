@@ -97,51 +97,19 @@ func ToGRPCStatus(err error) error {
 	return status.Error(grpcCode, ke.Message)
 }
 
-// ToGRPCStatusWithDetails converts a KairosError to a gRPC status with details.
+// ToGRPCStatusWithDetails converts a KairosError to a gRPC status error that
+// carries its code, recoverable flag and context as errdetails.ErrorInfo
+// (see errors.ToStatus).
 func ToGRPCStatusWithDetails(err error) error {
 	if err == nil {
 		return nil
 	}
-
-	ke := errors.AsKairosError(err)
-	if ke == nil {
-		return status.Error(codes.Internal, err.Error())
-	}
-
-	grpcCode := mapErrorCodeToGRPC(ke.Code)
-	st := status.New(grpcCode, ke.Message)
-
-	// Add details if available (would require proto definition for error details)
-	// For now, just return the status
-	return st.Err()
+	return errors.ToStatus(errors.AsKairosError(err)).Err()
 }
 
 // mapErrorCodeToGRPC maps KairosError codes to gRPC codes.
 func mapErrorCodeToGRPC(code errors.ErrorCode) codes.Code {
-	switch code {
-	case errors.CodeInvalidInput:
-		return codes.InvalidArgument
-	case errors.CodeNotFound:
-		return codes.NotFound
-	case errors.CodeUnauthorized:
-		return codes.Unauthenticated
-	case errors.CodeTimeout:
-		return codes.DeadlineExceeded
-	case errors.CodeRateLimit:
-		return codes.ResourceExhausted
-	case errors.CodeToolFailure:
-		return codes.FailedPrecondition
-	case errors.CodeLLMError:
-		return codes.Unavailable
-	case errors.CodeMemoryError:
-		return codes.DataLoss
-	case errors.CodeContextLost:
-		return codes.Canceled
-	case errors.CodeInternal:
-		return codes.Internal
-	default:
-		return codes.Unknown
-	}
+	return errors.GRPCCode(code)
 }
 
 // WrapTaskError wraps an error that occurred during task execution.
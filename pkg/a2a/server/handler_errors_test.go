@@ -240,6 +240,7 @@ func TestMapErrorCodeToGRPC(t *testing.T) {
 		{errors.CodeUnauthorized, codes.Unauthenticated},
 		{errors.CodeTimeout, codes.DeadlineExceeded},
 		{errors.CodeRateLimit, codes.ResourceExhausted},
+		{errors.CodeBudgetExceeded, codes.ResourceExhausted},
 		{errors.CodeToolFailure, codes.FailedPrecondition},
 		{errors.CodeLLMError, codes.Unavailable},
 		{errors.CodeMemoryError, codes.DataLoss},
@@ -1,13 +1,17 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -757,6 +761,108 @@ func TestSubscribeToTask_UpdatesAndArtifacts(t *testing.T) {
 	<-done
 }
 
+func TestSubscribeToTask_StreamsArtifactChunks(t *testing.T) {
+	store := NewMemoryTaskStore()
+	task, err := store.CreateTask(context.Background(), &a2av1.Message{
+		MessageId: "msg-1",
+		Role:      a2av1.Role_ROLE_USER,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+	working := newStatus(a2av1.TaskState_TASK_STATE_WORKING, task.History[0])
+	if err := store.UpdateStatus(context.Background(), task.Id, working); err != nil {
+		t.Fatalf("UpdateStatus error: %v", err)
+	}
+
+	handler := &SimpleHandler{Store: store}
+	stream := newStreamRecorder()
+	done := make(chan error, 1)
+	go func() {
+		req := &a2av1.SubscribeToTaskRequest{Name: fmt.Sprintf("tasks/%s", task.Id)}
+		done <- handler.SubscribeToTask(req, stream)
+	}()
+
+	artifactEvents := func() []*a2av1.TaskArtifactUpdateEvent {
+		var events []*a2av1.TaskArtifactUpdateEvent
+		for _, resp := range stream.snapshot() {
+			if event := resp.GetArtifactUpdate(); event != nil {
+				events = append(events, event)
+			}
+		}
+		return events
+	}
+	waitFor := func(cond func() bool) bool {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if cond() {
+				return true
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		return false
+	}
+
+	if ok := waitFor(func() bool { return len(stream.snapshot()) > 0 }); !ok {
+		t.Fatalf("expected initial status update")
+	}
+
+	rows := []string{"id,name\n", "1,ana\n", "2,luis\n"}
+	for i, row := range rows[:2] {
+		chunk := &a2av1.Part{Part: &a2av1.Part_Text{Text: row}}
+		if err := store.AppendArtifactChunk(context.Background(), task.Id, "rows", chunk, false); err != nil {
+			t.Fatalf("AppendArtifactChunk error: %v", err)
+		}
+		if ok := waitFor(func() bool { return len(artifactEvents()) == i+1 }); !ok {
+			t.Fatalf("expected artifact update for chunk %d", i)
+		}
+	}
+
+	// The last chunk and the terminal status land in the same poll; the
+	// chunk must still be delivered before the final status.
+	last := &a2av1.Part{Part: &a2av1.Part_Text{Text: rows[2]}}
+	if err := store.AppendArtifactChunk(context.Background(), task.Id, "rows", last, true); err != nil {
+		t.Fatalf("AppendArtifactChunk error: %v", err)
+	}
+	completed := newStatus(a2av1.TaskState_TASK_STATE_COMPLETED, task.History[0])
+	if err := store.UpdateStatus(context.Background(), task.Id, completed); err != nil {
+		t.Fatalf("UpdateStatus error: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("SubscribeToTask error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("SubscribeToTask did not finish")
+	}
+
+	responses := stream.snapshot()
+	if final := responses[len(responses)-1].GetStatusUpdate(); final == nil || !final.Final {
+		t.Fatalf("expected final status update last")
+	}
+	events := artifactEvents()
+	if len(events) != len(rows) {
+		t.Fatalf("expected %d artifact updates, got %d", len(rows), len(events))
+	}
+	for i, event := range events {
+		if event.GetArtifact().GetArtifactId() != "rows" {
+			t.Fatalf("event %d: unexpected artifact id %q", i, event.GetArtifact().GetArtifactId())
+		}
+		if parts := event.GetArtifact().GetParts(); len(parts) != 1 || parts[0].GetText() != rows[i] {
+			t.Fatalf("event %d: unexpected parts %v", i, parts)
+		}
+		if event.Append != (i > 0) {
+			t.Fatalf("event %d: expected append=%v", i, i > 0)
+		}
+		if event.LastChunk != (i == len(rows)-1) {
+			t.Fatalf("event %d: expected last_chunk=%v", i, i == len(rows)-1)
+		}
+	}
+}
+
 func TestGetExtendedAgentCard_NotSupported(t *testing.T) {
 	handler := &SimpleHandler{}
 
@@ -1434,7 +1540,8 @@ func TestListTasks_PageSize(t *testing.T) {
 }
 
 func TestListTasks_LastUpdatedAfter(t *testing.T) {
-	store := NewMemoryTaskStore()
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskClock(fake))
 	handler := &SimpleHandler{Store: store}
 
 	_, err := store.CreateTask(context.Background(), &a2av1.Message{
@@ -1445,9 +1552,9 @@ func TestListTasks_LastUpdatedAfter(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTask error: %v", err)
 	}
-	time.Sleep(10 * time.Millisecond)
-	later := time.Now().UTC()
-	time.Sleep(10 * time.Millisecond)
+	fake.Advance(10 * time.Millisecond)
+	later := fake.Now()
+	fake.Advance(10 * time.Millisecond)
 	_, err = store.CreateTask(context.Background(), &a2av1.Message{
 		MessageId: "msg-2",
 		Role:      a2av1.Role_ROLE_USER,
@@ -1753,7 +1860,8 @@ func TestListTasks_DefaultPageSizeWithPageTokenBeyondRange(t *testing.T) {
 }
 
 func TestListTasks_OrderingAcrossPages(t *testing.T) {
-	store := NewMemoryTaskStore()
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskClock(fake))
 	handler := &SimpleHandler{Store: store}
 
 	firstTask, err := store.CreateTask(context.Background(), &a2av1.Message{
@@ -1764,7 +1872,7 @@ func TestListTasks_OrderingAcrossPages(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTask error: %v", err)
 	}
-	time.Sleep(10 * time.Millisecond)
+	fake.Advance(10 * time.Millisecond)
 	secondTask, err := store.CreateTask(context.Background(), &a2av1.Message{
 		MessageId: "msg-2",
 		Role:      a2av1.Role_ROLE_USER,
@@ -1843,7 +1951,8 @@ func TestListTasks_OrderingWithEqualTimestamps(t *testing.T) {
 }
 
 func TestListTasks_OrderingByUpdatedAt(t *testing.T) {
-	store := NewMemoryTaskStore()
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskClock(fake))
 	handler := &SimpleHandler{Store: store}
 
 	taskA, err := store.CreateTask(context.Background(), &a2av1.Message{
@@ -1862,7 +1971,7 @@ func TestListTasks_OrderingByUpdatedAt(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTask error: %v", err)
 	}
-	time.Sleep(5 * time.Millisecond)
+	fake.Advance(5 * time.Millisecond)
 	if err := store.UpdateStatus(context.Background(), taskA.Id, newStatus(a2av1.TaskState_TASK_STATE_WORKING, taskA.History[0])); err != nil {
 		t.Fatalf("UpdateStatus error: %v", err)
 	}
@@ -1883,7 +1992,8 @@ func TestListTasks_OrderingByUpdatedAt(t *testing.T) {
 }
 
 func TestListTasks_OrderingByArtifactUpdate(t *testing.T) {
-	store := NewMemoryTaskStore()
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskClock(fake))
 	handler := &SimpleHandler{Store: store}
 
 	taskA, err := store.CreateTask(context.Background(), &a2av1.Message{
@@ -1902,7 +2012,7 @@ func TestListTasks_OrderingByArtifactUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTask error: %v", err)
 	}
-	time.Sleep(5 * time.Millisecond)
+	fake.Advance(5 * time.Millisecond)
 	if err := store.AddArtifacts(context.Background(), taskB.Id, []*a2av1.Artifact{{Name: "artifact"}}); err != nil {
 		t.Fatalf("AddArtifacts error: %v", err)
 	}
@@ -1923,7 +2033,8 @@ func TestListTasks_OrderingByArtifactUpdate(t *testing.T) {
 }
 
 func TestListTasks_OrderingByHistoryAppend(t *testing.T) {
-	store := NewMemoryTaskStore()
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskClock(fake))
 	handler := &SimpleHandler{Store: store}
 
 	taskA, err := store.CreateTask(context.Background(), &a2av1.Message{
@@ -1942,7 +2053,7 @@ func TestListTasks_OrderingByHistoryAppend(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTask error: %v", err)
 	}
-	time.Sleep(5 * time.Millisecond)
+	fake.Advance(5 * time.Millisecond)
 	if err := store.AppendHistory(context.Background(), taskA.Id, &a2av1.Message{
 		MessageId: "msg-3",
 		Role:      a2av1.Role_ROLE_USER,
@@ -3041,3 +3152,16 @@ func TestCancelTask_InvalidName(t *testing.T) {
 		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
 	}
 }
+
+func TestWithLogger_TagsAsyncErrors(t *testing.T) {
+	var buf bytes.Buffer
+	handler := &SimpleHandler{Store: NewMemoryTaskStore()}
+	WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))(handler)
+
+	handler.runAsync(context.Background(), "missing", nil)
+
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"a2a.task.async.load_failed"`) || !strings.Contains(out, `"component":"server"`) {
+		t.Fatalf("expected tagged load_failed record, got %s", out)
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IdempotencyKeyMetadata is the metadata key that carries the idempotency
+// key of a SendMessage call. It is read from the request metadata first and
+// then from the message metadata.
+const IdempotencyKeyMetadata = "idempotency_key"
+
+// DefaultIdempotencyTTL is how long task stores remember idempotency keys
+// unless configured otherwise.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotentTaskStore is implemented by task stores that deduplicate task
+// creation. SimpleHandler uses it when a new SendMessage carries an
+// idempotency key; with other stores the key is ignored.
+type IdempotentTaskStore interface {
+	// CreateTaskIdempotent creates a task for message unless key was used
+	// within the key TTL, in which case it returns the task created then
+	// and created is false. The lookup and the creation are atomic.
+	CreateTaskIdempotent(ctx context.Context, key string, message *a2av1.Message) (task *a2av1.Task, created bool, err error)
+}
+
+// idempotencyKey returns the idempotency key of req, or "".
+func idempotencyKey(req *a2av1.SendMessageRequest) string {
+	if value := req.GetMetadata().GetFields()[IdempotencyKeyMetadata]; value != nil {
+		if key := strings.TrimSpace(value.GetStringValue()); key != "" {
+			return key
+		}
+	}
+	value := req.GetRequest().GetMetadata().GetFields()[IdempotencyKeyMetadata]
+	return strings.TrimSpace(value.GetStringValue())
+}
+
+// createIdempotentTask creates the task of a new message that carries an
+// idempotency key. It returns a nil task when the key does not apply: no
+// key, a message for an existing task, or a store without support.
+// duplicate is true when the key was already used.
+func (h *SimpleHandler) createIdempotentTask(ctx context.Context, req *a2av1.SendMessageRequest) (task *a2av1.Task, duplicate bool, err error) {
+	message := req.GetRequest()
+	if message.GetTaskId() != "" {
+		return nil, false, nil
+	}
+	key := idempotencyKey(req)
+	if key == "" {
+		return nil, false, nil
+	}
+	store, ok := h.Store.(IdempotentTaskStore)
+	if !ok {
+		return nil, false, nil
+	}
+	task, created, err := store.CreateTaskIdempotent(ctx, key, message)
+	if err != nil {
+		return nil, false, status.Error(codes.Internal, err.Error())
+	}
+	return task, !created, nil
+}
+
+// duplicateResponse answers a repeated SendMessage with the task created by
+// the first call. A blocking call whose task has completed receives the
+// original response message instead.
+func duplicateResponse(task *a2av1.Task, blocking bool) *a2av1.SendMessageResponse {
+	if blocking && task.GetStatus().GetState() == a2av1.TaskState_TASK_STATE_COMPLETED {
+		if msg := task.GetStatus().GetMessage(); msg != nil {
+			return &a2av1.SendMessageResponse{Payload: &a2av1.SendMessageResponse_Msg{Msg: msg}}
+		}
+	}
+	return &a2av1.SendMessageResponse{Payload: &a2av1.SendMessageResponse_Task{Task: task}}
+}
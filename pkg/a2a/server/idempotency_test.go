@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// gatedExecutor blocks every run until release is closed, ignoring the
+// caller context like work that outlives a client timeout.
+type gatedExecutor struct {
+	release chan struct{}
+	runs    int32
+}
+
+func (e *gatedExecutor) Run(ctx context.Context, message *a2av1.Message) (any, []*a2av1.Artifact, error) {
+	atomic.AddInt32(&e.runs, 1)
+	<-e.release
+	return "done", nil, nil
+}
+
+func idempotentRequest(key string, blocking bool) *a2av1.SendMessageRequest {
+	return &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			MessageId: "msg-1",
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "book a flight"}}},
+		},
+		Configuration: &a2av1.SendMessageConfiguration{Blocking: blocking},
+		Metadata: &structpb.Struct{Fields: map[string]*structpb.Value{
+			IdempotencyKeyMetadata: structpb.NewStringValue(key),
+		}},
+	}
+}
+
+func TestSendMessage_IdempotentRetryAfterTimeout(t *testing.T) {
+	store := NewMemoryTaskStore()
+	executor := &gatedExecutor{release: make(chan struct{})}
+	handler := &SimpleHandler{Store: store, Executor: executor}
+
+	// The first call times out on the client while the task keeps running.
+	type result struct {
+		resp *a2av1.SendMessageResponse
+		err  error
+	}
+	first := make(chan result, 1)
+	go func() {
+		resp, err := handler.SendMessage(context.Background(), idempotentRequest("key-1", true))
+		first <- result{resp, err}
+	}()
+	select {
+	case <-first:
+		t.Fatal("first call should still be running")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	retry, err := handler.SendMessage(context.Background(), idempotentRequest("key-1", true))
+	if err != nil {
+		t.Fatalf("retry error: %v", err)
+	}
+	task := retry.GetTask()
+	if task == nil || task.GetStatus().GetState() != a2av1.TaskState_TASK_STATE_WORKING {
+		t.Fatalf("expected the running task, got %v", retry)
+	}
+
+	close(executor.release)
+	original := <-first
+	if original.err != nil {
+		t.Fatalf("first call error: %v", original.err)
+	}
+	if original.resp.GetMsg().GetTaskId() != task.GetId() {
+		t.Fatalf("retry returned task %s, first call ran %s", task.GetId(), original.resp.GetMsg().GetTaskId())
+	}
+
+	replay, err := handler.SendMessage(context.Background(), idempotentRequest("key-1", true))
+	if err != nil {
+		t.Fatalf("replay error: %v", err)
+	}
+	if replay.GetMsg().GetMessageId() != original.resp.GetMsg().GetMessageId() {
+		t.Fatalf("expected the original response, got %v", replay)
+	}
+
+	if stats := store.Stats(); stats.Total != 1 {
+		t.Fatalf("expected exactly one task, got %d", stats.Total)
+	}
+	if runs := atomic.LoadInt32(&executor.runs); runs != 1 {
+		t.Fatalf("expected one execution, got %d", runs)
+	}
+}
+
+func TestSendMessage_IdempotencyKeyFromMessageMetadata(t *testing.T) {
+	store := NewMemoryTaskStore()
+	handler := &SimpleHandler{Store: store, Executor: &stubExecutor{Output: "ok"}}
+
+	req := idempotentRequest("", false)
+	req.Request.Metadata = &structpb.Struct{Fields: map[string]*structpb.Value{
+		IdempotencyKeyMetadata: structpb.NewStringValue("key-2"),
+	}}
+	first, err := handler.SendMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	second, err := handler.SendMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	if first.GetTask().GetId() != second.GetTask().GetId() {
+		t.Fatalf("expected the same task, got %s and %s", first.GetTask().GetId(), second.GetTask().GetId())
+	}
+
+	other, err := handler.SendMessage(context.Background(), idempotentRequest("key-3", false))
+	if err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	if other.GetTask().GetId() == first.GetTask().GetId() {
+		t.Fatal("a different key must create a new task")
+	}
+}
+
+func TestMemoryTaskStore_IdempotencyKeyExpires(t *testing.T) {
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskClock(fake), WithIdempotencyTTL(time.Hour))
+	message := &a2av1.Message{MessageId: "msg-1", Role: a2av1.Role_ROLE_USER}
+
+	first, created, err := store.CreateTaskIdempotent(context.Background(), "key", message)
+	if err != nil || !created {
+		t.Fatalf("expected a new task, got created=%v err=%v", created, err)
+	}
+	fake.Advance(59 * time.Minute)
+	again, created, err := store.CreateTaskIdempotent(context.Background(), "key", message)
+	if err != nil || created || again.GetId() != first.GetId() {
+		t.Fatalf("expected task %s, got %s created=%v err=%v", first.GetId(), again.GetId(), created, err)
+	}
+	fake.Advance(time.Minute)
+	renewed, created, err := store.CreateTaskIdempotent(context.Background(), "key", message)
+	if err != nil || !created || renewed.GetId() == first.GetId() {
+		t.Fatalf("expected a new task after the TTL, got %s created=%v err=%v", renewed.GetId(), created, err)
+	}
+}
+
+func TestSQLiteTaskStore_CreateTaskIdempotent(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	store, err := NewSQLiteTaskStore(db, WithSQLiteIdempotencyTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("new sqlite store: %v", err)
+	}
+	message := &a2av1.Message{MessageId: "msg-1", Role: a2av1.Role_ROLE_USER}
+
+	first, created, err := store.CreateTaskIdempotent(context.Background(), "key", message)
+	if err != nil || !created {
+		t.Fatalf("expected a new task, got created=%v err=%v", created, err)
+	}
+	again, created, err := store.CreateTaskIdempotent(context.Background(), "key", message)
+	if err != nil || created || again.GetId() != first.GetId() {
+		t.Fatalf("expected task %s, got %s created=%v err=%v", first.GetId(), again.GetId(), created, err)
+	}
+	_, total, err := store.ListTasks(context.Background(), TaskFilter{})
+	if err != nil {
+		t.Fatalf("ListTasks error: %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("expected one task, got %d", total)
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/governance"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceOption configures a Service.
+type ServiceOption func(*serviceConfig)
+
+type serviceConfig struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+// WithUnaryInterceptors runs every unary RPC of the Service through
+// interceptors, the first one outermost. Options accumulate.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) ServiceOption {
+	return func(c *serviceConfig) {
+		c.unary = append(c.unary, interceptors...)
+	}
+}
+
+// WithStreamInterceptors runs the SendStreamingMessage and SubscribeToTask
+// streams of the Service through interceptors, the first one outermost.
+// Options accumulate.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) ServiceOption {
+	return func(c *serviceConfig) {
+		c.stream = append(c.stream, interceptors...)
+	}
+}
+
+// TokenValidator checks a bearer token and returns the principal it
+// belongs to.
+type TokenValidator func(ctx context.Context, token string) (principal string, err error)
+
+// BearerAuthInterceptor returns a unary interceptor that requires an
+// "authorization: Bearer <token>" metadata entry accepted by validate. The
+// principal is stored with governance.WithPrincipal so policies can match
+// on it. Failures are rejected with codes.Unauthenticated.
+func BearerAuthInterceptor(validate TokenValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticateBearer(ctx, validate)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// BearerAuthStreamInterceptor is the stream counterpart of
+// BearerAuthInterceptor.
+func BearerAuthStreamInterceptor(validate TokenValidator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateBearer(stream.Context(), validate)
+		if err != nil {
+			return err
+		}
+		return handler(srv, contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authenticateBearer(ctx context.Context, validate TokenValidator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := bearerToken(md)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	if validate == nil {
+		return nil, status.Error(codes.Unauthenticated, "token validation not configured")
+	}
+	principal, err := validate(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return governance.WithPrincipal(ctx, principal), nil
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// responseStream adapts a server stream replaced by an interceptor back to
+// the typed A2A stream.
+type responseStream struct {
+	grpc.ServerStream
+}
+
+func (s responseStream) Send(resp *a2av1.StreamResponse) error {
+	return s.SendMsg(resp)
+}
+
+func interceptUnary[Req, Resp any](s *Service, ctx context.Context, req Req, method string, call func(context.Context, Req) (Resp, error)) (Resp, error) {
+	if s.unary == nil {
+		return call(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: s, FullMethod: method}
+	out, err := s.unary(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return call(ctx, req.(Req))
+	})
+	resp, _ := out.(Resp)
+	return resp, err
+}
+
+func interceptStream(s *Service, stream grpc.ServerStreamingServer[a2av1.StreamResponse], method string, call func(grpc.ServerStreamingServer[a2av1.StreamResponse]) error) error {
+	if s.stream == nil {
+		return call(stream)
+	}
+	info := &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}
+	return s.stream(s, stream, info, func(_ any, ss grpc.ServerStream) error {
+		typed, ok := ss.(grpc.ServerStreamingServer[a2av1.StreamResponse])
+		if !ok {
+			typed = responseStream{ServerStream: ss}
+		}
+		return call(typed)
+	})
+}
+
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	if len(interceptors) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+func chainStream(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	if len(interceptors) == 0 {
+		return nil
+	}
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(srv any, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, inner)
+			}
+		}
+		return next(srv, stream)
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/governance"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// principalExecutor records the principal each run is made for.
+type principalExecutor struct {
+	principals chan string
+}
+
+func (e *principalExecutor) Run(ctx context.Context, _ *a2av1.Message) (any, []*a2av1.Artifact, error) {
+	e.principals <- governance.PrincipalFromContext(ctx)
+	return "ok", nil, nil
+}
+
+func validateTestToken(_ context.Context, token string) (string, error) {
+	if token != "secret" {
+		return "", errors.New("invalid token")
+	}
+	return "team-a", nil
+}
+
+func interceptorTestRequest() *a2av1.SendMessageRequest {
+	return &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			MessageId: "msg-1",
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+		},
+	}
+}
+
+func TestBearerAuthInterceptor_Unary(t *testing.T) {
+	exec := &principalExecutor{principals: make(chan string, 1)}
+	svc := New(&SimpleHandler{Store: NewMemoryTaskStore(), Executor: exec},
+		WithUnaryInterceptors(BearerAuthInterceptor(validateTestToken)),
+	)
+
+	tests := []struct {
+		name string
+		auth string
+		code codes.Code
+	}{
+		{name: "missing", code: codes.Unauthenticated},
+		{name: "invalid", auth: "Bearer nope", code: codes.Unauthenticated},
+		{name: "valid", auth: "Bearer secret", code: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+			resp, err := svc.SendMessage(ctx, interceptorTestRequest())
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if tt.code != codes.OK {
+				if resp != nil {
+					t.Fatalf("expected nil response, got %v", resp)
+				}
+				return
+			}
+			if principal := <-exec.principals; principal != "team-a" {
+				t.Fatalf("expected principal team-a, got %q", principal)
+			}
+		})
+	}
+}
+
+func TestWithUnaryInterceptors_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if info.FullMethod != a2av1.A2AService_GetTask_FullMethodName {
+				t.Errorf("unexpected method %q", info.FullMethod)
+			}
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	svc := New(&SimpleHandler{Store: NewMemoryTaskStore()},
+		WithUnaryInterceptors(record("first")),
+		WithUnaryInterceptors(record("second")),
+	)
+
+	_, err := svc.GetTask(context.Background(), &a2av1.GetTaskRequest{Name: "tasks/missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected handler error to pass through, got %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Fatalf("unexpected interceptor order %v", calls)
+	}
+}
+
+func TestBearerAuthStreamInterceptor(t *testing.T) {
+	exec := &principalExecutor{principals: make(chan string, 1)}
+	handler := &SimpleHandler{
+		Store:    NewMemoryTaskStore(),
+		Executor: exec,
+		Card: &a2av1.AgentCard{
+			Capabilities: &a2av1.AgentCapabilities{Streaming: boolPtr(true)},
+		},
+	}
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	a2av1.RegisterA2AServiceServer(grpcServer, New(handler,
+		WithStreamInterceptors(BearerAuthStreamInterceptor(validateTestToken)),
+	))
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := a2av1.NewA2AServiceClient(conn)
+
+	stream, err := client.SendStreamingMessage(context.Background(), interceptorTestRequest())
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	stream, err = client.SendStreamingMessage(ctx, interceptorTestRequest())
+	if err != nil {
+		t.Fatalf("SendStreamingMessage error: %v", err)
+	}
+	events := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		events++
+	}
+	if events != 3 {
+		t.Fatalf("expected 3 stream events, got %d", events)
+	}
+	if principal := <-exec.principals; principal != "team-a" {
+		t.Fatalf("expected principal team-a, got %q", principal)
+	}
+}
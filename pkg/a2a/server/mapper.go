@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
@@ -36,6 +37,11 @@ func ValidateMessage(message *a2av1.Message) error {
 	if len(message.Parts) == 0 {
 		return fmt.Errorf("message parts are required")
 	}
+	for i, part := range message.Parts {
+		if err := validatePart(part); err != nil {
+			return fmt.Errorf("part %d: %w", i, err)
+		}
+	}
 	return nil
 }
 
@@ -55,21 +61,26 @@ func normalizeMessage(message *a2av1.Message, contextID, taskID string, role a2a
 	return message
 }
 
-// ExtractText returns concatenated text parts.
+// ExtractText returns the text parts of message concatenated with no
+// separator, as ExtractAllText(message, ""). Parts written as separate
+// paragraphs run together; use ExtractAllText to keep them apart.
 func ExtractText(message *a2av1.Message) string {
+	return ExtractAllText(message, "")
+}
+
+// ExtractAllText returns the non-empty text parts of message joined with
+// sep. Non-text parts are skipped.
+func ExtractAllText(message *a2av1.Message, sep string) string {
 	if message == nil {
 		return ""
 	}
-	var out string
+	var texts []string
 	for _, part := range message.Parts {
-		if part == nil {
-			continue
-		}
 		if text := part.GetText(); text != "" {
-			out += text
+			texts = append(texts, text)
 		}
 	}
-	return out
+	return strings.Join(texts, sep)
 }
 
 // ExtractData returns the first data part as a map, if present.
@@ -94,6 +105,64 @@ func ExtractData(message *a2av1.Message) map[string]interface{} {
 	return nil
 }
 
+// FileRef describes a file part. Exactly one of Bytes or URI is set.
+type FileRef struct {
+	Name      string
+	MediaType string
+	Bytes     []byte
+	URI       string
+}
+
+// ExtractFiles returns the file parts of the message in order.
+func ExtractFiles(message *a2av1.Message) []FileRef {
+	if message == nil {
+		return nil
+	}
+	var files []FileRef
+	for _, part := range message.Parts {
+		if part == nil {
+			continue
+		}
+		filePart := part.GetFile()
+		if filePart == nil {
+			continue
+		}
+		ref := FileRef{Name: filePart.GetName(), MediaType: filePart.GetMediaType()}
+		switch file := filePart.GetFile().(type) {
+		case *a2av1.FilePart_FileWithUri:
+			ref.URI = file.FileWithUri
+		case *a2av1.FilePart_FileWithBytes:
+			ref.Bytes = file.FileWithBytes
+		default:
+			continue
+		}
+		files = append(files, ref)
+	}
+	return files
+}
+
+// NewFileMessage builds a message with one file part per file. Files with a
+// URI are sent by reference; otherwise their bytes are sent inline.
+func NewFileMessage(role a2av1.Role, files []FileRef, contextID, taskID string) *a2av1.Message {
+	parts := make([]*a2av1.Part, 0, len(files))
+	for _, file := range files {
+		filePart := &a2av1.FilePart{Name: file.Name, MediaType: file.MediaType}
+		if file.URI != "" {
+			filePart.File = &a2av1.FilePart_FileWithUri{FileWithUri: file.URI}
+		} else {
+			filePart.File = &a2av1.FilePart_FileWithBytes{FileWithBytes: file.Bytes}
+		}
+		parts = append(parts, &a2av1.Part{Part: &a2av1.Part_File{File: filePart}})
+	}
+	return &a2av1.Message{
+		MessageId: uuid.NewString(),
+		ContextId: contextID,
+		TaskId:    taskID,
+		Role:      role,
+		Parts:     parts,
+	}
+}
+
 func structFromMap(data map[string]interface{}) *structpb.Struct {
 	if len(data) == 0 {
 		return nil
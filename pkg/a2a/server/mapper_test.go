@@ -0,0 +1,66 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+func TestNewFileMessageRoundTrip(t *testing.T) {
+	files := []FileRef{
+		{Name: "report.pdf", MediaType: "application/pdf", URI: "https://example.com/report.pdf"},
+		{Name: "logo.png", MediaType: "image/png", Bytes: []byte{0x89, 'P', 'N', 'G'}},
+	}
+	msg := NewFileMessage(a2av1.Role_ROLE_USER, files, "ctx-1", "task-1")
+	if msg.GetMessageId() == "" || msg.GetContextId() != "ctx-1" || msg.GetTaskId() != "task-1" {
+		t.Fatalf("unexpected message envelope: %v", msg)
+	}
+	if err := ValidateMessage(msg); err != nil {
+		t.Fatalf("ValidateMessage error: %v", err)
+	}
+	// Non-file parts are ignored.
+	msg.Parts = append(msg.Parts, nil, &a2av1.Part{Part: &a2av1.Part_Text{Text: "see attached"}})
+
+	got := ExtractFiles(msg)
+	if len(got) != len(files) {
+		t.Fatalf("expected %d files, got %d", len(files), len(got))
+	}
+	for i, want := range files {
+		if got[i].Name != want.Name || got[i].MediaType != want.MediaType || got[i].URI != want.URI || !bytes.Equal(got[i].Bytes, want.Bytes) {
+			t.Fatalf("file %d: expected %+v, got %+v", i, want, got[i])
+		}
+	}
+}
+
+func TestExtractAllText(t *testing.T) {
+	msg := &a2av1.Message{Parts: []*a2av1.Part{
+		{Part: &a2av1.Part_Text{Text: "first"}},
+		{Part: &a2av1.Part_Data{}},
+		{Part: &a2av1.Part_Text{Text: ""}},
+		nil,
+		{Part: &a2av1.Part_Text{Text: "second"}},
+	}}
+	if got := ExtractAllText(msg, "\n"); got != "first\nsecond" {
+		t.Fatalf("unexpected text %q", got)
+	}
+	if got := ExtractText(msg); got != "firstsecond" {
+		t.Fatalf("unexpected text %q", got)
+	}
+	if got := ExtractAllText(nil, "\n"); got != "" {
+		t.Fatalf("expected empty text, got %q", got)
+	}
+}
+
+func TestExtractFilesEmpty(t *testing.T) {
+	if files := ExtractFiles(nil); files != nil {
+		t.Fatalf("expected nil for nil message, got %v", files)
+	}
+	msg := &a2av1.Message{Parts: []*a2av1.Part{
+		{Part: &a2av1.Part_Text{Text: "hello"}},
+		{Part: &a2av1.Part_File{File: &a2av1.FilePart{Name: "empty"}}},
+	}}
+	if files := ExtractFiles(msg); files != nil {
+		t.Fatalf("expected no files, got %v", files)
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"math"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MessageLimits bounds the size of incoming messages. A zero field disables
+// that limit. Sizes are measured on the protobuf encoding.
+type MessageLimits struct {
+	// MaxMessageBytes bounds the whole message.
+	MaxMessageBytes int
+	// MaxParts bounds the number of parts.
+	MaxParts int
+	// MaxPartBytes bounds each part.
+	MaxPartBytes int
+}
+
+// DefaultMessageLimits applies when the handler has no explicit limits.
+var DefaultMessageLimits = MessageLimits{
+	MaxMessageBytes: 64 << 20,
+	MaxParts:        1000,
+	MaxPartBytes:    32 << 20,
+}
+
+// requestOverheadBytes leaves room for the request fields that wrap the
+// message, such as its configuration and metadata.
+const requestOverheadBytes = 64 << 10
+
+// WithMessageLimits overrides the limits applied to incoming messages.
+// Serve and the HTTP+JSON binding also stop reading requests that cannot fit
+// within MaxMessageBytes; register the Service on your own grpc.Server with
+// MaxRecvMsgSize to get the same behaviour.
+func WithMessageLimits(limits MessageLimits) HandlerOption {
+	return func(h *SimpleHandler) {
+		h.MessageLimits = &limits
+	}
+}
+
+// Limits returns the message limits the handler enforces.
+func (h *SimpleHandler) Limits() MessageLimits {
+	if h.MessageLimits != nil {
+		return *h.MessageLimits
+	}
+	return DefaultMessageLimits
+}
+
+// MessageLimitsOf returns the limits enforced by handler, or
+// DefaultMessageLimits when handler does not report them.
+func MessageLimitsOf(handler Handler) MessageLimits {
+	if limited, ok := handler.(interface{ Limits() MessageLimits }); ok {
+		return limited.Limits()
+	}
+	return DefaultMessageLimits
+}
+
+// MaxJSONRequestBytes bounds the HTTP+JSON request body that can carry a
+// message within MaxMessageBytes. It allows twice the protobuf size, since
+// JSON encodes bytes as base64 and adds field names. It is 0 when the
+// message size is unlimited.
+func (l MessageLimits) MaxJSONRequestBytes() int64 {
+	if l.MaxMessageBytes <= 0 {
+		return 0
+	}
+	return 2*int64(l.MaxMessageBytes) + requestOverheadBytes
+}
+
+// MaxRecvMsgSize returns the grpc.MaxRecvMsgSize server option matching the
+// message limits of handler. gRPC otherwise rejects requests above 4 MiB,
+// whatever MaxMessageBytes allows.
+func MaxRecvMsgSize(handler Handler) grpc.ServerOption {
+	limits := MessageLimitsOf(handler)
+	if limits.MaxMessageBytes <= 0 || limits.MaxMessageBytes > math.MaxInt32-requestOverheadBytes {
+		return grpc.MaxRecvMsgSize(math.MaxInt32)
+	}
+	return grpc.MaxRecvMsgSize(limits.MaxMessageBytes + requestOverheadBytes)
+}
+
+// validateMessage checks the message shape and the handler limits, returning
+// an InvalidArgument or ResourceExhausted status error.
+func (h *SimpleHandler) validateMessage(message *a2av1.Message) error {
+	if err := ValidateMessage(message); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	limits := h.Limits()
+	if limits.MaxParts > 0 && len(message.Parts) > limits.MaxParts {
+		return status.Errorf(codes.InvalidArgument, "message has %d parts, max %d", len(message.Parts), limits.MaxParts)
+	}
+	if limits.MaxPartBytes > 0 {
+		for i, part := range message.Parts {
+			if size := proto.Size(part); size > limits.MaxPartBytes {
+				return status.Errorf(codes.ResourceExhausted, "part %d is %d bytes, max %d", i, size, limits.MaxPartBytes)
+			}
+		}
+	}
+	if limits.MaxMessageBytes > 0 {
+		if size := proto.Size(message); size > limits.MaxMessageBytes {
+			return status.Errorf(codes.ResourceExhausted, "message is %d bytes, max %d", size, limits.MaxMessageBytes)
+		}
+	}
+	return nil
+}
+
+func validatePart(part *a2av1.Part) error {
+	if part == nil || part.GetPart() == nil {
+		return fmt.Errorf("part has no content")
+	}
+	if file := part.GetFile(); file != nil && file.GetFile() == nil {
+		return fmt.Errorf("file part has neither uri nor bytes")
+	}
+	if data := part.GetData(); data != nil && data.GetData() == nil {
+		return fmt.Errorf("data part has no data")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSendMessage_MessageLimits(t *testing.T) {
+	textPart := func(text string) *a2av1.Part {
+		return &a2av1.Part{Part: &a2av1.Part_Text{Text: text}}
+	}
+	limits := MessageLimits{MaxMessageBytes: 256, MaxParts: 3, MaxPartBytes: 128}
+
+	tests := []struct {
+		name  string
+		parts []*a2av1.Part
+		code  codes.Code
+	}{
+		{"within limits", []*a2av1.Part{textPart("hello")}, codes.OK},
+		{"too many parts", []*a2av1.Part{textPart("a"), textPart("b"), textPart("c"), textPart("d")}, codes.InvalidArgument},
+		{"part too large", []*a2av1.Part{textPart(strings.Repeat("x", 200))}, codes.ResourceExhausted},
+		{"message too large", []*a2av1.Part{textPart(strings.Repeat("x", 100)), textPart(strings.Repeat("y", 100)), textPart(strings.Repeat("z", 100))}, codes.ResourceExhausted},
+		{"empty part", []*a2av1.Part{{}}, codes.InvalidArgument},
+		{"empty file part", []*a2av1.Part{{Part: &a2av1.Part_File{File: &a2av1.FilePart{Name: "x"}}}}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &SimpleHandler{
+				Store:    NewMemoryTaskStore(),
+				Executor: &stubExecutor{Output: "ok"},
+			}
+			WithMessageLimits(limits)(handler)
+			req := &a2av1.SendMessageRequest{
+				Request:       &a2av1.Message{MessageId: "msg-1", Role: a2av1.Role_ROLE_USER, Parts: tt.parts},
+				Configuration: &a2av1.SendMessageConfiguration{Blocking: true},
+			}
+			_, err := handler.SendMessage(context.Background(), req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v (%v)", tt.code, status.Code(err), err)
+			}
+		})
+	}
+}
+
+func TestSendMessage_DefaultMessageLimits(t *testing.T) {
+	handler := &SimpleHandler{
+		Store:    NewMemoryTaskStore(),
+		Executor: &stubExecutor{Output: "ok"},
+	}
+	parts := make([]*a2av1.Part, DefaultMessageLimits.MaxParts+1)
+	for i := range parts {
+		parts[i] = &a2av1.Part{Part: &a2av1.Part_Text{Text: "x"}}
+	}
+	req := &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{MessageId: "msg-1", Role: a2av1.Role_ROLE_USER, Parts: parts},
+	}
+	if _, err := handler.SendMessage(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+}
@@ -0,0 +1,322 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	klog "github.com/jllopis/kairos/pkg/log"
+	"github.com/jllopis/kairos/pkg/resilience"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Headers of push notification requests. The signature is
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + nonce + "." + body)).
+const (
+	PushTokenHeader     = "X-A2A-Notification-Token"
+	PushSignatureHeader = "X-Kairos-Signature"
+	PushTimestampHeader = "X-Kairos-Timestamp"
+	PushNonceHeader     = "X-Kairos-Nonce"
+)
+
+// DefaultPushTimeout bounds each delivery attempt.
+const DefaultPushTimeout = 10 * time.Second
+
+// TaskPushEvent is the JSON payload posted to push notification webhooks.
+type TaskPushEvent struct {
+	TaskID    string `json:"task_id"`
+	ContextID string `json:"context_id,omitempty"`
+	// State is the task state name, e.g. "TASK_STATE_COMPLETED".
+	State string `json:"state"`
+	// Final is set for terminal states.
+	Final bool `json:"final"`
+	// Status is the protojson TaskStatus, with its message.
+	Status    json.RawMessage `json:"status,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// PushNotifier posts task status updates to the webhooks registered with
+// SetTaskPushNotificationConfig. Deliveries run in the background, one
+// update at a time per task so webhooks see them in order, and are retried
+// with backoff; requests carry the config token, its authentication
+// credentials and, with a secret, an HMAC signature.
+type PushNotifier struct {
+	client  *http.Client
+	secret  []byte
+	retry   resilience.RetryConfig
+	timeout time.Duration
+	logger  *slog.Logger
+	wg      sync.WaitGroup
+
+	mu sync.Mutex
+	// queues holds the pending updates of each task with a running worker.
+	queues map[string][]pushUpdate
+}
+
+// pushUpdate is a status update waiting to be delivered.
+type pushUpdate struct {
+	ctx     context.Context
+	event   TaskPushEvent
+	configs []*a2av1.PushNotificationConfig
+}
+
+// PushNotifierOption configures a PushNotifier.
+type PushNotifierOption func(*PushNotifier)
+
+// WithPushSecret signs every notification with secret; receivers check it
+// with VerifyPushSignature.
+func WithPushSecret(secret string) PushNotifierOption {
+	return func(n *PushNotifier) {
+		n.secret = []byte(secret)
+	}
+}
+
+// WithPushHTTPClient overrides the HTTP client used for deliveries.
+func WithPushHTTPClient(client *http.Client) PushNotifierOption {
+	return func(n *PushNotifier) {
+		if client != nil {
+			n.client = client
+		}
+	}
+}
+
+// WithPushRetry overrides the delivery retry policy (3 attempts with
+// exponential backoff by default).
+func WithPushRetry(retry resilience.RetryConfig) PushNotifierOption {
+	return func(n *PushNotifier) {
+		n.retry = retry
+	}
+}
+
+// WithPushTimeout bounds each delivery attempt.
+func WithPushTimeout(timeout time.Duration) PushNotifierOption {
+	return func(n *PushNotifier) {
+		if timeout > 0 {
+			n.timeout = timeout
+		}
+	}
+}
+
+// WithPushLogger routes delivery failures to logger.
+func WithPushLogger(logger *slog.Logger) PushNotifierOption {
+	return func(n *PushNotifier) {
+		if logger != nil {
+			n.logger = logger.With(slog.String(klog.ComponentKey, "server"))
+		}
+	}
+}
+
+// NewPushNotifier creates a push notifier.
+func NewPushNotifier(opts ...PushNotifierOption) *PushNotifier {
+	n := &PushNotifier{
+		client:  http.DefaultClient,
+		retry:   resilience.DefaultRetryConfig(),
+		timeout: DefaultPushTimeout,
+		logger:  logger,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Notify delivers a status update of a task to each config in the
+// background, after the updates of the task notified before it. Use Wait
+// to block until the deliveries finish.
+func (n *PushNotifier) Notify(ctx context.Context, taskID, contextID string, status *a2av1.TaskStatus, configs []*a2av1.TaskPushNotificationConfig) {
+	if len(configs) == 0 || status == nil {
+		return
+	}
+	event, err := newTaskPushEvent(taskID, contextID, status)
+	if err != nil {
+		n.logger.WarnContext(ctx, "a2a.push.encode_failed",
+			slog.String("task_id", taskID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	update := pushUpdate{ctx: context.WithoutCancel(ctx), event: event}
+	for _, cfg := range configs {
+		if push := cfg.GetPushNotificationConfig(); push.GetUrl() != "" {
+			update.configs = append(update.configs, push)
+		}
+	}
+	if len(update.configs) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.queues == nil {
+		n.queues = make(map[string][]pushUpdate)
+	}
+	queue, running := n.queues[taskID]
+	n.queues[taskID] = append(queue, update)
+	n.wg.Add(1)
+	if !running {
+		go n.drain(taskID)
+	}
+}
+
+// drain delivers the queued updates of a task in order and exits when the
+// queue is empty.
+func (n *PushNotifier) drain(taskID string) {
+	for {
+		n.mu.Lock()
+		queue := n.queues[taskID]
+		if len(queue) == 0 {
+			delete(n.queues, taskID)
+			n.mu.Unlock()
+			return
+		}
+		update := queue[0]
+		queue[0] = pushUpdate{}
+		n.queues[taskID] = queue[1:]
+		n.mu.Unlock()
+
+		n.deliverUpdate(taskID, update)
+		n.wg.Done()
+	}
+}
+
+// deliverUpdate posts an update to its configs concurrently and returns
+// when every delivery has finished.
+func (n *PushNotifier) deliverUpdate(taskID string, update pushUpdate) {
+	var wg sync.WaitGroup
+	for _, push := range update.configs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := n.Deliver(update.ctx, push, update.event); err != nil {
+				n.logger.WarnContext(update.ctx, "a2a.push.delivery_failed",
+					slog.String("task_id", taskID),
+					slog.String("config_id", push.GetId()),
+					slog.String("url", push.GetUrl()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Wait blocks until the deliveries started by Notify finish.
+func (n *PushNotifier) Wait() {
+	n.wg.Wait()
+}
+
+// Deliver posts event to the webhook of cfg, retrying server errors,
+// 408/429 responses and network errors.
+func (n *PushNotifier) Deliver(ctx context.Context, cfg *a2av1.PushNotificationConfig, event TaskPushEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return n.retry.Do(ctx, func() error {
+		return n.post(ctx, cfg, body)
+	})
+}
+
+func (n *PushNotifier) post(ctx context.Context, cfg *a2av1.PushNotificationConfig, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.GetUrl(), bytes.NewReader(body))
+	if err != nil {
+		return kerrors.New(kerrors.CodeInvalidInput, "invalid push notification url", err).WithRecoverable(false)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := cfg.GetToken(); token != "" {
+		req.Header.Set(PushTokenHeader, token)
+	}
+	setPushAuthorization(req.Header, cfg.GetAuthentication())
+	if len(n.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := uuid.NewString()
+		req.Header.Set(PushTimestampHeader, timestamp)
+		req.Header.Set(PushNonceHeader, nonce)
+		req.Header.Set(PushSignatureHeader, signPushPayload(n.secret, timestamp, nonce, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return kerrors.New(kerrors.CodeToolFailure, "push notification request failed", err).WithRecoverable(true)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	recoverable := resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+	return kerrors.New(kerrors.CodeToolFailure, fmt.Sprintf("push notification rejected with status %d", resp.StatusCode), nil).
+		WithContext("status", resp.StatusCode).
+		WithRecoverable(recoverable)
+}
+
+// setPushAuthorization sets the Authorization header for the first
+// supported scheme of auth (Bearer or Basic).
+func setPushAuthorization(header http.Header, auth *a2av1.AuthenticationInfo) {
+	credentials := auth.GetCredentials()
+	if credentials == "" {
+		return
+	}
+	for _, scheme := range auth.GetSchemes() {
+		switch strings.ToLower(scheme) {
+		case "bearer":
+			header.Set("Authorization", "Bearer "+credentials)
+			return
+		case "basic":
+			if strings.Contains(credentials, ":") {
+				credentials = base64.StdEncoding.EncodeToString([]byte(credentials))
+			}
+			header.Set("Authorization", "Basic "+credentials)
+			return
+		}
+	}
+}
+
+func newTaskPushEvent(taskID, contextID string, status *a2av1.TaskStatus) (TaskPushEvent, error) {
+	payload, err := protojson.Marshal(status)
+	if err != nil {
+		return TaskPushEvent{}, err
+	}
+	timestamp := time.Now().UTC()
+	if status.GetTimestamp() != nil {
+		timestamp = status.GetTimestamp().AsTime()
+	}
+	return TaskPushEvent{
+		TaskID:    taskID,
+		ContextID: contextID,
+		State:     status.GetState().String(),
+		Final:     isTerminalState(status.GetState()),
+		Status:    payload,
+		Timestamp: timestamp,
+	}, nil
+}
+
+// signPushPayload returns the PushSignatureHeader value for body.
+func signPushPayload(secret []byte, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/resilience"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestPushNotifierDeliversStatusUpdates(t *testing.T) {
+	var mu sync.Mutex
+	var events []TaskPushEvent
+	var headers []http.Header
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event TaskPushEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		mu.Lock()
+		events = append(events, event)
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	task, err := store.CreateTask(ctx, &a2av1.Message{
+		MessageId: "msg-1",
+		Role:      a2av1.Role_ROLE_USER,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+	notifier := NewPushNotifier(WithPushSecret("s3cret"))
+	handler := &SimpleHandler{Store: store, Executor: &approvalTestExecutor{}}
+	WithPushConfigStore(NewMemoryPushConfigStore())(handler)
+	WithPushNotifier(notifier)(handler)
+
+	_, err = handler.SetTaskPushNotificationConfig(ctx, &a2av1.SetTaskPushNotificationConfigRequest{
+		Parent:   fmt.Sprintf("tasks/%s", task.Id),
+		ConfigId: "cfg-1",
+		Config: &a2av1.TaskPushNotificationConfig{PushNotificationConfig: &a2av1.PushNotificationConfig{
+			Url:            webhook.URL,
+			Token:          "task-token",
+			Authentication: &a2av1.AuthenticationInfo{Schemes: []string{"Bearer"}, Credentials: "abc"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("SetTaskPushNotificationConfig error: %v", err)
+	}
+
+	_, err = handler.SendMessage(ctx, &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			MessageId: "msg-2",
+			TaskId:    task.Id,
+			ContextId: task.ContextId,
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "go"}}},
+		},
+		Configuration: &a2av1.SendMessageConfiguration{Blocking: true},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+	notifier.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	states := map[string]bool{}
+	for i, event := range events {
+		states[event.State] = event.Final
+		if event.TaskID != task.Id {
+			t.Errorf("unexpected task id %q", event.TaskID)
+		}
+		status := &a2av1.TaskStatus{}
+		if err := protojson.Unmarshal(event.Status, status); err != nil || status.GetState().String() != event.State {
+			t.Errorf("unexpected status payload %s: %v", event.Status, err)
+		}
+		h := headers[i]
+		if h.Get(PushTokenHeader) != "task-token" || h.Get("Authorization") != "Bearer abc" {
+			t.Errorf("missing auth headers: %v", h)
+		}
+		if h.Get(PushSignatureHeader) == "" || h.Get(PushTimestampHeader) == "" || h.Get(PushNonceHeader) == "" {
+			t.Errorf("missing signature headers: %v", h)
+		}
+	}
+	working, okWorking := states["TASK_STATE_WORKING"]
+	completed, okCompleted := states["TASK_STATE_COMPLETED"]
+	if !okWorking || !okCompleted || working || !completed {
+		t.Fatalf("expected working and final completed events, got %v", states)
+	}
+}
+
+func TestPushNotifierRetries(t *testing.T) {
+	var calls atomic.Int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer webhook.Close()
+
+	retry := resilience.DefaultRetryConfig().WithInitialDelay(time.Millisecond)
+	notifier := NewPushNotifier(WithPushRetry(retry))
+	cfg := &a2av1.PushNotificationConfig{Url: webhook.URL}
+	if err := notifier.Deliver(context.Background(), cfg, TaskPushEvent{TaskID: "task-1"}); err != nil {
+		t.Fatalf("Deliver error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected a retry after 503, got %d calls", calls.Load())
+	}
+
+	// Client errors are not retried.
+	calls.Store(0)
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	cfg.Url = rejecting.URL
+	if err := notifier.Deliver(context.Background(), cfg, TaskPushEvent{TaskID: "task-1"}); err == nil {
+		t.Fatalf("expected error for 400 response")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt for 400, got %d", calls.Load())
+	}
+}
+
+func TestPushNotifierDeliversUpdatesInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var states []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event TaskPushEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		// A slow first delivery must not let later updates overtake it.
+		if event.State == a2av1.TaskState_TASK_STATE_SUBMITTED.String() {
+			time.Sleep(50 * time.Millisecond)
+		}
+		mu.Lock()
+		states = append(states, event.State)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	notifier := NewPushNotifier()
+	configs := []*a2av1.TaskPushNotificationConfig{{PushNotificationConfig: &a2av1.PushNotificationConfig{Url: webhook.URL}}}
+	want := []a2av1.TaskState{
+		a2av1.TaskState_TASK_STATE_SUBMITTED,
+		a2av1.TaskState_TASK_STATE_WORKING,
+		a2av1.TaskState_TASK_STATE_COMPLETED,
+	}
+	for _, state := range want {
+		notifier.Notify(context.Background(), "task-1", "ctx-1", newStatus(state, nil), configs)
+	}
+	notifier.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(states) != len(want) {
+		t.Fatalf("expected %d deliveries, got %v", len(want), states)
+	}
+	for i, state := range want {
+		if states[i] != state.String() {
+			t.Fatalf("expected deliveries in order %v, got %v", want, states)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPushTolerance is how far the timestamp of a push notification may
+// be from the receiver clock.
+const DefaultPushTolerance = 5 * time.Minute
+
+// maxPushBodyBytes bounds the notifications accepted by PushReceiverHandler.
+const maxPushBodyBytes = 1 << 20
+
+// Errors returned by VerifyPushSignature and PushReceiverHandler.
+var (
+	ErrPushSignatureMissing = errors.New("push signature headers missing")
+	ErrPushSignatureInvalid = errors.New("push signature invalid")
+	ErrPushSignatureExpired = errors.New("push signature timestamp outside tolerance")
+	ErrPushReplayed         = errors.New("push notification replayed")
+)
+
+// VerifyPushSignature checks that a push notification was signed by a
+// PushNotifier with secret and that its timestamp is within
+// DefaultPushTolerance. It does not detect replays inside that window; use
+// PushReceiverHandler, or track the PushNonceHeader values yourself.
+func VerifyPushSignature(header http.Header, body []byte, secret string) error {
+	return verifyPushSignature(header, body, secret, DefaultPushTolerance, time.Now())
+}
+
+func verifyPushSignature(header http.Header, body []byte, secret string, tolerance time.Duration, now time.Time) error {
+	signature := header.Get(PushSignatureHeader)
+	timestamp := header.Get(PushTimestampHeader)
+	nonce := header.Get(PushNonceHeader)
+	if signature == "" || timestamp == "" || nonce == "" {
+		return ErrPushSignatureMissing
+	}
+	expected := signPushPayload([]byte(secret), timestamp, nonce, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrPushSignatureInvalid
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrPushSignatureInvalid
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return ErrPushSignatureExpired
+	}
+	return nil
+}
+
+// PushReceiverOption configures PushReceiverHandler.
+type PushReceiverOption func(*pushReceiver)
+
+// WithPushTolerance sets how far a notification timestamp may be from the
+// receiver clock; nonces are remembered for as long.
+func WithPushTolerance(tolerance time.Duration) PushReceiverOption {
+	return func(r *pushReceiver) {
+		if tolerance > 0 {
+			r.tolerance = tolerance
+		}
+	}
+}
+
+// PushReceiverHandler returns an HTTP handler for the webhook of a push
+// notification config. It verifies the signature made with secret, rejects
+// replayed nonces and stale timestamps, decodes the event and passes it to
+// onEvent. Rejected requests get 401 (bad signature) or 400 (bad payload).
+func PushReceiverHandler(secret string, onEvent func(TaskPushEvent), opts ...PushReceiverOption) http.Handler {
+	r := &pushReceiver{
+		secret:    secret,
+		onEvent:   onEvent,
+		tolerance: DefaultPushTolerance,
+		nonces:    make(map[string]time.Time),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+type pushReceiver struct {
+	secret    string
+	onEvent   func(TaskPushEvent)
+	tolerance time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	nonces map[string]time.Time // seen nonce -> when it can be forgotten
+}
+
+func (r *pushReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxPushBodyBytes))
+	if err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	now := r.now()
+	if err := verifyPushSignature(req.Header, body, r.secret, r.tolerance, now); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if !r.remember(req.Header.Get(PushNonceHeader), now) {
+		http.Error(w, ErrPushReplayed.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var event TaskPushEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.TaskID == "" {
+		http.Error(w, "invalid push event", http.StatusBadRequest)
+		return
+	}
+	if r.onEvent != nil {
+		r.onEvent(event)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// remember records nonce and reports false if it was already seen. Nonces
+// are kept for twice the tolerance, which covers every timestamp accepted.
+func (r *pushReceiver) remember(nonce string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for seen, expires := range r.nonces {
+		if now.After(expires) {
+			delete(r.nonces, seen)
+		}
+	}
+	if _, ok := r.nonces[nonce]; ok {
+		return false
+	}
+	r.nonces[nonce] = now.Add(2 * r.tolerance)
+	return true
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+func signedPushRequest(t *testing.T, secret string, timestamp time.Time, nonce string, body []byte) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	req.Header.Set(PushTimestampHeader, ts)
+	req.Header.Set(PushNonceHeader, nonce)
+	req.Header.Set(PushSignatureHeader, signPushPayload([]byte(secret), ts, nonce, body))
+	return req
+}
+
+func TestPushReceiverHandlerAcceptsNotifier(t *testing.T) {
+	received := make(chan TaskPushEvent, 1)
+	receiver := httptest.NewServer(PushReceiverHandler("s3cret", func(event TaskPushEvent) {
+		received <- event
+	}))
+	defer receiver.Close()
+
+	notifier := NewPushNotifier(WithPushSecret("s3cret"))
+	event, err := newTaskPushEvent("task-1", "ctx-1", newStatus(a2av1.TaskState_TASK_STATE_COMPLETED, nil))
+	if err != nil {
+		t.Fatalf("event: %v", err)
+	}
+	if err := notifier.Deliver(context.Background(), &a2av1.PushNotificationConfig{Url: receiver.URL}, event); err != nil {
+		t.Fatalf("Deliver error: %v", err)
+	}
+	got := <-received
+	if got.TaskID != "task-1" || got.State != "TASK_STATE_COMPLETED" || !got.Final {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+
+	// A notifier with another secret is rejected.
+	other := NewPushNotifier(WithPushSecret("other"), WithPushRetry(notifier.retry.WithMaxAttempts(1)))
+	if err := other.Deliver(context.Background(), &a2av1.PushNotificationConfig{Url: receiver.URL}, event); err == nil {
+		t.Fatalf("expected rejection for a wrong secret")
+	}
+}
+
+func TestPushReceiverHandlerRejects(t *testing.T) {
+	body := []byte(`{"task_id":"task-1","state":"TASK_STATE_WORKING"}`)
+	handler := PushReceiverHandler("s3cret", nil)
+	now := time.Now()
+
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(signedPushRequest(t, "s3cret", now, "n-1", body)); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if code := serve(signedPushRequest(t, "s3cret", now, "n-1", body)); code != http.StatusUnauthorized {
+		t.Fatalf("expected replay to be rejected, got %d", code)
+	}
+	tampered := signedPushRequest(t, "s3cret", now, "n-2", body)
+	tampered.Body = http.NoBody
+	if code := serve(tampered); code != http.StatusUnauthorized {
+		t.Fatalf("expected tampered body to be rejected, got %d", code)
+	}
+	if code := serve(signedPushRequest(t, "s3cret", now.Add(-time.Hour), "n-3", body)); code != http.StatusUnauthorized {
+		t.Fatalf("expected stale timestamp to be rejected, got %d", code)
+	}
+	if code := serve(signedPushRequest(t, "s3cret", now, "n-4", []byte(`{}`))); code != http.StatusBadRequest {
+		t.Fatalf("expected invalid event to be rejected, got %d", code)
+	}
+}
+
+func TestVerifyPushSignature(t *testing.T) {
+	body := []byte(`{"task_id":"task-1"}`)
+	req := signedPushRequest(t, "s3cret", time.Now(), "n-1", body)
+	if err := VerifyPushSignature(req.Header, body, "s3cret"); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if err := VerifyPushSignature(req.Header, body, "wrong"); !errors.Is(err, ErrPushSignatureInvalid) {
+		t.Fatalf("expected ErrPushSignatureInvalid, got %v", err)
+	}
+	if err := VerifyPushSignature(http.Header{}, body, "s3cret"); !errors.Is(err, ErrPushSignatureMissing) {
+		t.Fatalf("expected ErrPushSignatureMissing, got %v", err)
+	}
+	stale := signedPushRequest(t, "s3cret", time.Now().Add(-time.Hour), "n-2", body)
+	if err := VerifyPushSignature(stale.Header, body, "s3cret"); !errors.Is(err, ErrPushSignatureExpired) {
+		t.Fatalf("expected ErrPushSignatureExpired, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+)
+
+// DefaultGracePeriod is how long Serve waits for open streams to finish
+// after shutdown starts.
+const DefaultGracePeriod = 30 * time.Second
+
+// ShutdownReason is the status message given to tasks cut off by a forced
+// shutdown.
+const ShutdownReason = "task interrupted: server shutting down"
+
+// ErrGracePeriodExceeded is returned by Serve when streams were still open
+// at the end of the grace period and had to be closed.
+var ErrGracePeriodExceeded = errors.New("a2a server: grace period exceeded, streams closed")
+
+// TaskInterrupter is implemented by handlers that can record a task as cut
+// off by shutdown. SimpleHandler implements it.
+type TaskInterrupter interface {
+	InterruptTask(ctx context.Context, taskID, reason string) error
+}
+
+// ServeOption configures Serve.
+type ServeOption func(*serveConfig)
+
+type serveConfig struct {
+	gracePeriod    time.Duration
+	signals        []os.Signal
+	serverOptions  []grpc.ServerOption
+	serviceOptions []ServiceOption
+}
+
+// WithGracePeriod sets how long Serve waits for open streams to finish
+// after shutdown starts. Zero or less closes them immediately.
+func WithGracePeriod(d time.Duration) ServeOption {
+	return func(c *serveConfig) {
+		c.gracePeriod = d
+	}
+}
+
+// WithShutdownSignals sets the signals that start a shutdown (SIGINT and
+// SIGTERM by default). With no signals only ctx does.
+func WithShutdownSignals(signals ...os.Signal) ServeOption {
+	return func(c *serveConfig) {
+		c.signals = signals
+	}
+}
+
+// WithGRPCServerOptions passes options to the underlying grpc.Server. They
+// apply after the MaxRecvMsgSize derived from the handler message limits,
+// so they can override it.
+func WithGRPCServerOptions(opts ...grpc.ServerOption) ServeOption {
+	return func(c *serveConfig) {
+		c.serverOptions = append(c.serverOptions, opts...)
+	}
+}
+
+// WithServiceOptions configures the Service created by Serve, e.g. with
+// WithUnaryInterceptors.
+func WithServiceOptions(opts ...ServiceOption) ServeOption {
+	return func(c *serveConfig) {
+		c.serviceOptions = append(c.serviceOptions, opts...)
+	}
+}
+
+// Serve runs an A2A gRPC server for handler on lis until ctx is done or a
+// shutdown signal arrives. It then stops accepting RPCs and waits up to the
+// grace period for open calls, including SendStreamingMessage and
+// SubscribeToTask streams, to finish. Streams still open at the deadline are
+// closed, and the tasks they were executing are marked failed if handler
+// implements TaskInterrupter.
+//
+// Serve returns nil once drained, ErrGracePeriodExceeded if streams had to
+// be closed, or the error that stopped the server.
+func Serve(ctx context.Context, lis net.Listener, handler Handler, opts ...ServeOption) error {
+	cfg := serveConfig{
+		gracePeriod: DefaultGracePeriod,
+		signals:     []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if len(cfg.signals) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, cfg.signals...)
+		defer stop()
+	}
+
+	service := New(handler, cfg.serviceOptions...)
+	serverOptions := append([]grpc.ServerOption{MaxRecvMsgSize(handler)}, cfg.serverOptions...)
+	grpcServer := grpc.NewServer(serverOptions...)
+	a2av1.RegisterA2AServiceServer(grpcServer, service)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	log := serveLogger(handler)
+	log.Info("a2a.server.shutdown.start",
+		slog.Duration("grace_period", cfg.gracePeriod),
+		slog.Int("active_tasks", len(service.ActiveTasks())),
+	)
+
+	drained := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(drained)
+	}()
+
+	timer := time.NewTimer(max(cfg.gracePeriod, 0))
+	defer timer.Stop()
+	select {
+	case <-drained:
+		log.Info("a2a.server.shutdown.drained")
+		return nil
+	case <-timer.C:
+	}
+
+	interrupted := service.ActiveTasks()
+	grpcServer.Stop()
+	<-drained
+	if interrupter, ok := handler.(TaskInterrupter); ok {
+		for _, taskID := range interrupted {
+			if err := interrupter.InterruptTask(context.WithoutCancel(ctx), taskID, ShutdownReason); err != nil {
+				log.Warn("a2a.server.shutdown.interrupt_failed",
+					slog.String("task_id", taskID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+	log.Warn("a2a.server.shutdown.forced", slog.Int("interrupted_tasks", len(interrupted)))
+	return ErrGracePeriodExceeded
+}
+
+// serveLogger returns the handler logger when it has one.
+func serveLogger(handler Handler) *slog.Logger {
+	if h, ok := handler.(*SimpleHandler); ok {
+		return h.logger()
+	}
+	return logger
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// blockingExecutor runs until release is closed or the request is cancelled.
+type blockingExecutor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *blockingExecutor) Run(ctx context.Context, _ *a2av1.Message) (any, []*a2av1.Artifact, error) {
+	close(e.started)
+	select {
+	case <-e.release:
+		return "done", nil, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// startServe runs Serve on an in-memory listener and opens a streaming call
+// whose task is already executing when it returns.
+func startServe(t *testing.T, handler *SimpleHandler, opts ...ServeOption) (context.CancelFunc, <-chan error, a2av1.A2AService_SendStreamingMessageClient) {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, listener, handler, append([]ServeOption{WithShutdownSignals()}, opts...)...)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	stream, err := a2av1.NewA2AServiceClient(conn).SendStreamingMessage(context.Background(), &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			MessageId: "msg-1",
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendStreamingMessage error: %v", err)
+	}
+	if resp, err := stream.Recv(); err != nil || resp.GetTask() == nil {
+		t.Fatalf("expected task event, got %v, %v", resp, err)
+	}
+	<-handler.Executor.(*blockingExecutor).started
+	return cancel, served, stream
+}
+
+func newBlockingHandler() *SimpleHandler {
+	return &SimpleHandler{
+		Store:    NewMemoryTaskStore(),
+		Executor: &blockingExecutor{started: make(chan struct{}), release: make(chan struct{})},
+		Card: &a2av1.AgentCard{
+			Capabilities: &a2av1.AgentCapabilities{Streaming: boolPtr(true)},
+		},
+	}
+}
+
+func TestServe_DrainsStreamsOnShutdown(t *testing.T) {
+	handler := newBlockingHandler()
+	cancel, served, stream := startServe(t, handler, WithGracePeriod(5*time.Second))
+
+	cancel()
+	select {
+	case err := <-served:
+		t.Fatalf("Serve returned before the stream finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(handler.Executor.(*blockingExecutor).release)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if update := resp.GetStatusUpdate(); update != nil && update.Final {
+			if update.Status.GetState() != a2av1.TaskState_TASK_STATE_COMPLETED {
+				t.Fatalf("expected completed task, got %v", update.Status.GetState())
+			}
+			break
+		}
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("expected clean drain, got %v", err)
+	}
+}
+
+func TestServe_InterruptsTasksAfterGracePeriod(t *testing.T) {
+	handler := newBlockingHandler()
+	cancel, served, _ := startServe(t, handler, WithGracePeriod(20*time.Millisecond))
+
+	cancel()
+	if err := <-served; !errors.Is(err, ErrGracePeriodExceeded) {
+		t.Fatalf("expected ErrGracePeriodExceeded, got %v", err)
+	}
+
+	tasks, _, err := handler.Store.ListTasks(context.Background(), TaskFilter{})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected one task, got %v, %v", tasks, err)
+	}
+	if state := tasks[0].GetStatus().GetState(); state != a2av1.TaskState_TASK_STATE_FAILED {
+		t.Fatalf("expected interrupted task to be failed, got %v", state)
+	}
+}
+
+func TestServe_AcceptsMessagesUpToMessageLimits(t *testing.T) {
+	handler := &SimpleHandler{
+		Store:    NewMemoryTaskStore(),
+		Executor: &stubExecutor{Output: "ok"},
+	}
+	listener := bufconn.Listen(1024 * 1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, listener, handler, WithShutdownSignals())
+	}()
+	defer func() {
+		cancel()
+		<-served
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	defer conn.Close()
+
+	// Above gRPC's 4 MiB default, within DefaultMessageLimits.
+	text := strings.Repeat("x", 5<<20)
+	_, err = a2av1.NewA2AServiceClient(conn).SendMessage(context.Background(), &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			MessageId: "msg-1",
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: text}}},
+		},
+		Configuration: &a2av1.SendMessageConfiguration{Blocking: true},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage error: %v", err)
+	}
+}
+
+func TestMessageLimits_MaxJSONRequestBytes(t *testing.T) {
+	if got := (MessageLimits{}).MaxJSONRequestBytes(); got != 0 {
+		t.Fatalf("expected no body limit without MaxMessageBytes, got %d", got)
+	}
+	if got := (MessageLimits{MaxMessageBytes: 1024}).MaxJSONRequestBytes(); got < 2048 {
+		t.Fatalf("expected room for base64-encoded bytes, got %d", got)
+	}
+}
@@ -2,11 +2,14 @@ package server
 
 import (
 	"context"
+	"sort"
+	"sync"
 
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -28,18 +31,39 @@ type Service struct {
 	a2av1.UnimplementedA2AServiceServer
 	handler Handler
 	tracer  trace.Tracer
+	// unary and stream wrap every RPC; nil calls the handler directly.
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+
+	// activeMu guards activeTasks, the tasks being executed by open
+	// SendStreamingMessage streams, counted by stream.
+	activeMu    sync.Mutex
+	activeTasks map[string]int
 }
 
 // New creates a new Service instance.
-func New(handler Handler) *Service {
-	return &Service{
+func New(handler Handler, opts ...ServiceOption) *Service {
+	s := &Service{
 		handler: handler,
 		tracer:  otel.Tracer("kairos/a2a"),
 	}
+	var cfg serviceConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	s.unary = chainUnary(cfg.unary)
+	s.stream = chainStream(cfg.stream)
+	return s
 }
 
 // SendMessage handles the SendMessage RPC.
 func (s *Service) SendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_SendMessage_FullMethodName, s.sendMessage)
+}
+
+func (s *Service) sendMessage(ctx context.Context, req *a2av1.SendMessageRequest) (*a2av1.SendMessageResponse, error) {
 	if s.handler == nil {
 		return nil, status.Error(codes.Unimplemented, "SendMessage handler not configured")
 	}
@@ -53,6 +77,12 @@ func (s *Service) SendMessage(ctx context.Context, req *a2av1.SendMessageRequest
 
 // SendStreamingMessage handles the streaming SendMessage RPC.
 func (s *Service) SendStreamingMessage(req *a2av1.SendMessageRequest, stream a2av1.A2AService_SendStreamingMessageServer) error {
+	return interceptStream(s, stream, a2av1.A2AService_SendStreamingMessage_FullMethodName, func(stream a2av1.A2AService_SendStreamingMessageServer) error {
+		return s.sendStreamingMessage(req, stream)
+	})
+}
+
+func (s *Service) sendStreamingMessage(req *a2av1.SendMessageRequest, stream a2av1.A2AService_SendStreamingMessageServer) error {
 	if s.handler == nil {
 		return status.Error(codes.Unimplemented, "SendStreamingMessage handler not configured")
 	}
@@ -65,11 +95,17 @@ func (s *Service) SendStreamingMessage(req *a2av1.SendMessageRequest, stream a2a
 		attribute.Bool("a2a.stream", true),
 	))
 	defer span.End()
-	return s.handler.SendStreamingMessage(req, wrapStreamContext(stream, ctx))
+	tracked := &trackingStream{A2AService_SendStreamingMessageServer: wrapStreamContext(stream, ctx), service: s}
+	defer tracked.release()
+	return s.handler.SendStreamingMessage(req, tracked)
 }
 
 // GetTask handles the GetTask RPC.
 func (s *Service) GetTask(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av1.Task, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_GetTask_FullMethodName, s.getTask)
+}
+
+func (s *Service) getTask(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av1.Task, error) {
 	if s.handler == nil {
 		return nil, status.Error(codes.Unimplemented, "GetTask handler not configured")
 	}
@@ -84,6 +120,10 @@ func (s *Service) GetTask(ctx context.Context, req *a2av1.GetTaskRequest) (*a2av
 
 // ListTasks handles the ListTasks RPC.
 func (s *Service) ListTasks(ctx context.Context, req *a2av1.ListTasksRequest) (*a2av1.ListTasksResponse, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_ListTasks_FullMethodName, s.listTasks)
+}
+
+func (s *Service) listTasks(ctx context.Context, req *a2av1.ListTasksRequest) (*a2av1.ListTasksResponse, error) {
 	if s.handler == nil {
 		return nil, status.Error(codes.Unimplemented, "ListTasks handler not configured")
 	}
@@ -98,6 +138,10 @@ func (s *Service) ListTasks(ctx context.Context, req *a2av1.ListTasksRequest) (*
 
 // CancelTask handles the CancelTask RPC.
 func (s *Service) CancelTask(ctx context.Context, req *a2av1.CancelTaskRequest) (*a2av1.Task, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_CancelTask_FullMethodName, s.cancelTask)
+}
+
+func (s *Service) cancelTask(ctx context.Context, req *a2av1.CancelTaskRequest) (*a2av1.Task, error) {
 	if s.handler == nil {
 		return nil, status.Error(codes.Unimplemented, "CancelTask handler not configured")
 	}
@@ -112,6 +156,12 @@ func (s *Service) CancelTask(ctx context.Context, req *a2av1.CancelTaskRequest)
 
 // SubscribeToTask handles the SubscribeToTask streaming RPC.
 func (s *Service) SubscribeToTask(req *a2av1.SubscribeToTaskRequest, stream a2av1.A2AService_SubscribeToTaskServer) error {
+	return interceptStream(s, stream, a2av1.A2AService_SubscribeToTask_FullMethodName, func(stream a2av1.A2AService_SubscribeToTaskServer) error {
+		return s.subscribeToTask(req, stream)
+	})
+}
+
+func (s *Service) subscribeToTask(req *a2av1.SubscribeToTaskRequest, stream a2av1.A2AService_SubscribeToTaskServer) error {
 	if s.handler == nil {
 		return status.Error(codes.Unimplemented, "SubscribeToTask handler not configured")
 	}
@@ -130,6 +180,10 @@ func (s *Service) SubscribeToTask(req *a2av1.SubscribeToTaskRequest, stream a2av
 
 // GetExtendedAgentCard handles the GetExtendedAgentCard RPC.
 func (s *Service) GetExtendedAgentCard(ctx context.Context, req *a2av1.GetExtendedAgentCardRequest) (*a2av1.AgentCard, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_GetExtendedAgentCard_FullMethodName, s.getExtendedAgentCard)
+}
+
+func (s *Service) getExtendedAgentCard(ctx context.Context, req *a2av1.GetExtendedAgentCardRequest) (*a2av1.AgentCard, error) {
 	if s.handler == nil {
 		return nil, status.Error(codes.Unimplemented, "GetExtendedAgentCard handler not configured")
 	}
@@ -143,6 +197,10 @@ func (s *Service) GetExtendedAgentCard(ctx context.Context, req *a2av1.GetExtend
 
 // SetTaskPushNotificationConfig handles the SetTaskPushNotificationConfig RPC.
 func (s *Service) SetTaskPushNotificationConfig(ctx context.Context, req *a2av1.SetTaskPushNotificationConfigRequest) (*a2av1.TaskPushNotificationConfig, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_SetTaskPushNotificationConfig_FullMethodName, s.setTaskPushNotificationConfig)
+}
+
+func (s *Service) setTaskPushNotificationConfig(ctx context.Context, req *a2av1.SetTaskPushNotificationConfigRequest) (*a2av1.TaskPushNotificationConfig, error) {
 	if !supportsPushNotifications(s.handler) {
 		return nil, status.Error(codes.Unimplemented, "push notifications not supported")
 	}
@@ -161,6 +219,10 @@ func (s *Service) SetTaskPushNotificationConfig(ctx context.Context, req *a2av1.
 
 // GetTaskPushNotificationConfig handles the GetTaskPushNotificationConfig RPC.
 func (s *Service) GetTaskPushNotificationConfig(ctx context.Context, req *a2av1.GetTaskPushNotificationConfigRequest) (*a2av1.TaskPushNotificationConfig, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_GetTaskPushNotificationConfig_FullMethodName, s.getTaskPushNotificationConfig)
+}
+
+func (s *Service) getTaskPushNotificationConfig(ctx context.Context, req *a2av1.GetTaskPushNotificationConfigRequest) (*a2av1.TaskPushNotificationConfig, error) {
 	if !supportsPushNotifications(s.handler) {
 		return nil, status.Error(codes.Unimplemented, "push notifications not supported")
 	}
@@ -179,6 +241,10 @@ func (s *Service) GetTaskPushNotificationConfig(ctx context.Context, req *a2av1.
 
 // ListTaskPushNotificationConfig handles the ListTaskPushNotificationConfig RPC.
 func (s *Service) ListTaskPushNotificationConfig(ctx context.Context, req *a2av1.ListTaskPushNotificationConfigRequest) (*a2av1.ListTaskPushNotificationConfigResponse, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_ListTaskPushNotificationConfig_FullMethodName, s.listTaskPushNotificationConfig)
+}
+
+func (s *Service) listTaskPushNotificationConfig(ctx context.Context, req *a2av1.ListTaskPushNotificationConfigRequest) (*a2av1.ListTaskPushNotificationConfigResponse, error) {
 	if !supportsPushNotifications(s.handler) {
 		return nil, status.Error(codes.Unimplemented, "push notifications not supported")
 	}
@@ -197,6 +263,10 @@ func (s *Service) ListTaskPushNotificationConfig(ctx context.Context, req *a2av1
 
 // DeleteTaskPushNotificationConfig handles the DeleteTaskPushNotificationConfig RPC.
 func (s *Service) DeleteTaskPushNotificationConfig(ctx context.Context, req *a2av1.DeleteTaskPushNotificationConfigRequest) (*emptypb.Empty, error) {
+	return interceptUnary(s, ctx, req, a2av1.A2AService_DeleteTaskPushNotificationConfig_FullMethodName, s.deleteTaskPushNotificationConfig)
+}
+
+func (s *Service) deleteTaskPushNotificationConfig(ctx context.Context, req *a2av1.DeleteTaskPushNotificationConfigRequest) (*emptypb.Empty, error) {
 	if !supportsPushNotifications(s.handler) {
 		return nil, status.Error(codes.Unimplemented, "push notifications not supported")
 	}
@@ -275,3 +345,51 @@ func (s subscribeStreamWrapper) Context() context.Context {
 func wrapSubscribeContext(stream a2av1.A2AService_SubscribeToTaskServer, ctx context.Context) a2av1.A2AService_SubscribeToTaskServer {
 	return subscribeStreamWrapper{A2AService_SubscribeToTaskServer: stream, ctx: ctx}
 }
+
+// trackingStream records the task a SendStreamingMessage stream executes, so
+// Serve can tell which tasks a forced shutdown cuts off.
+type trackingStream struct {
+	a2av1.A2AService_SendStreamingMessageServer
+	service *Service
+	taskID  string
+}
+
+// Send records the task of the first task event before forwarding it.
+func (t *trackingStream) Send(resp *a2av1.StreamResponse) error {
+	if task := resp.GetTask(); task != nil && t.taskID == "" && task.GetId() != "" {
+		t.taskID = task.GetId()
+		t.service.trackTask(t.taskID, 1)
+	}
+	return t.A2AService_SendStreamingMessageServer.Send(resp)
+}
+
+func (t *trackingStream) release() {
+	if t.taskID != "" {
+		t.service.trackTask(t.taskID, -1)
+	}
+}
+
+func (s *Service) trackTask(taskID string, delta int) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	if s.activeTasks == nil {
+		s.activeTasks = make(map[string]int)
+	}
+	s.activeTasks[taskID] += delta
+	if s.activeTasks[taskID] <= 0 {
+		delete(s.activeTasks, taskID)
+	}
+}
+
+// ActiveTasks returns the IDs of the tasks being executed by open
+// SendStreamingMessage streams, sorted.
+func (s *Service) ActiveTasks() []string {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	ids := make([]string, 0, len(s.activeTasks))
+	for id := range s.activeTasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -15,8 +14,9 @@ import (
 )
 
 const (
-	taskTable       = "a2a_tasks"
-	pushConfigTable = "a2a_push_configs"
+	taskTable        = "a2a_tasks"
+	pushConfigTable  = "a2a_push_configs"
+	idempotencyTable = "a2a_idempotency_keys"
 )
 
 var (
@@ -26,7 +26,21 @@ var (
 
 // SQLiteTaskStore persists A2A tasks in a SQLite database.
 type SQLiteTaskStore struct {
-	db *sql.DB
+	db             *sql.DB
+	idempotencyTTL time.Duration
+}
+
+// SQLiteTaskStoreOption configures a SQLiteTaskStore.
+type SQLiteTaskStoreOption func(*SQLiteTaskStore)
+
+// WithSQLiteIdempotencyTTL sets how long idempotency keys are remembered. It
+// defaults to DefaultIdempotencyTTL.
+func WithSQLiteIdempotencyTTL(d time.Duration) SQLiteTaskStoreOption {
+	return func(s *SQLiteTaskStore) {
+		if d > 0 {
+			s.idempotencyTTL = d
+		}
+	}
 }
 
 // SQLitePushConfigStore persists push notification configs in a SQLite database.
@@ -35,14 +49,20 @@ type SQLitePushConfigStore struct {
 }
 
 // NewSQLiteTaskStore creates a SQLite-backed task store and ensures schema.
-func NewSQLiteTaskStore(db *sql.DB) (*SQLiteTaskStore, error) {
+func NewSQLiteTaskStore(db *sql.DB, opts ...SQLiteTaskStoreOption) (*SQLiteTaskStore, error) {
 	if db == nil {
 		return nil, fmt.Errorf("db is nil")
 	}
 	if err := ensureSQLiteSchema(db); err != nil {
 		return nil, err
 	}
-	return &SQLiteTaskStore{db: db}, nil
+	s := &SQLiteTaskStore{db: db, idempotencyTTL: DefaultIdempotencyTTL}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s, nil
 }
 
 // NewSQLitePushConfigStore creates a SQLite-backed push config store and ensures schema.
@@ -72,6 +92,7 @@ func ensureSQLiteSchema(db *sql.DB) error {
 			id TEXT PRIMARY KEY,
 			task_id TEXT NOT NULL,
 			context_id TEXT NOT NULL,
+			tool_name TEXT NOT NULL DEFAULT '',
 			status TEXT NOT NULL,
 			reason TEXT NOT NULL,
 			created_at INTEGER NOT NULL,
@@ -88,20 +109,34 @@ func ensureSQLiteSchema(db *sql.DB) error {
 			PRIMARY KEY(task_id, config_id)
 		);`, pushConfigTable),
 		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_task ON %s(task_id);`, pushConfigTable, pushConfigTable),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			key TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			expires_at INTEGER NOT NULL
+		);`, idempotencyTable),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_expires ON %s(expires_at);`, idempotencyTable, idempotencyTable),
 	}
 	for _, stmt := range stmts {
 		if _, err := db.Exec(stmt); err != nil {
 			return err
 		}
 	}
-	if err := ensureApprovalExpiryColumn(db); err != nil {
+	if err := ensureApprovalColumn(db, "expires_at INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := ensureApprovalColumn(db, "tool_name TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_tool ON %s(tool_name);`, approvalTable, approvalTable)); err != nil {
 		return err
 	}
 	return nil
 }
 
-func ensureApprovalExpiryColumn(db *sql.DB) error {
-	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN expires_at INTEGER NOT NULL DEFAULT 0", approvalTable))
+// ensureApprovalColumn adds a column to approval tables created by older
+// versions of the schema.
+func ensureApprovalColumn(db *sql.DB, definition string) error {
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", approvalTable, definition))
 	if err == nil {
 		return nil
 	}
@@ -116,44 +151,73 @@ func (s *SQLiteTaskStore) CreateTask(ctx context.Context, message *a2av1.Message
 	if message == nil {
 		return nil, fmt.Errorf("message is nil")
 	}
-	taskID := uuid.NewString()
-	contextID := message.ContextId
-	if contextID == "" {
-		contextID = uuid.NewString()
+	task := newTask(message)
+	if err := insertTask(ctx, s.db, task); err != nil {
+		return nil, err
 	}
+	return cloneTask(task), nil
+}
 
-	msg := cloneMessage(message)
-	msg.TaskId = taskID
-	msg.ContextId = contextID
-	status := newStatus(a2av1.TaskState_TASK_STATE_SUBMITTED, msg)
-	task := &a2av1.Task{
-		Id:        taskID,
-		ContextId: contextID,
-		Status:    status,
-		History:   []*a2av1.Message{msg},
+// CreateTaskIdempotent implements IdempotentTaskStore.
+func (s *SQLiteTaskStore) CreateTaskIdempotent(ctx context.Context, key string, message *a2av1.Message) (*a2av1.Task, bool, error) {
+	if message == nil {
+		return nil, false, fmt.Errorf("message is nil")
 	}
-
-	payload, err := marshalTask(task)
+	now := time.Now().UTC()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	now := time.Now().UTC().UnixMilli()
+	defer func() { _ = tx.Rollback() }()
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
+	// Writing first takes the database write lock, so concurrent retries
+	// with the same key are serialized.
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE expires_at <= ?", idempotencyTable), now.UnixMilli()); err != nil {
+		return nil, false, err
 	}
-	_, err = tx.ExecContext(ctx,
-		fmt.Sprintf("INSERT INTO %s (id, context_id, status_state, updated_at, task_json) VALUES (?, ?, ?, ?, ?)", taskTable),
-		taskID, contextID, int32(status.State), now, payload)
-	if err != nil {
-		_ = tx.Rollback()
-		return nil, err
+	var payload []byte
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT t.task_json FROM %s k JOIN %s t ON t.id = k.task_id WHERE k.key = ?", idempotencyTable, taskTable),
+		key).Scan(&payload)
+	switch {
+	case err == nil:
+		task, err := unmarshalTask(payload)
+		if err != nil {
+			return nil, false, err
+		}
+		return task, false, tx.Commit()
+	case err != sql.ErrNoRows:
+		return nil, false, err
+	}
+
+	task := newTask(message)
+	if err := insertTask(ctx, tx, task); err != nil {
+		return nil, false, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf("INSERT OR REPLACE INTO %s (key, task_id, expires_at) VALUES (?, ?, ?)", idempotencyTable),
+		key, task.Id, now.Add(s.idempotencyTTL).UnixMilli()); err != nil {
+		return nil, false, err
 	}
 	if err := tx.Commit(); err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	return cloneTask(task), nil
+	return cloneTask(task), true, nil
+}
+
+// insertTask writes a new task row.
+func insertTask(ctx context.Context, db interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}, task *a2av1.Task) error {
+	payload, err := marshalTask(task)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (id, context_id, status_state, updated_at, task_json) VALUES (?, ?, ?, ?, ?)", taskTable),
+		task.Id, task.ContextId, int32(task.GetStatus().GetState()), time.Now().UTC().UnixMilli(), payload)
+	return err
 }
 
 // AppendHistory appends a message to the task history.
@@ -200,6 +264,18 @@ func (s *SQLiteTaskStore) AddArtifacts(ctx context.Context, taskID string, artif
 	return s.updateTask(ctx, task)
 }
 
+// AppendArtifactChunk appends a chunk to a streamed artifact of a persisted task.
+func (s *SQLiteTaskStore) AppendArtifactChunk(ctx context.Context, taskID, artifactID string, chunk *a2av1.Part, lastChunk bool) error {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if err := appendArtifactChunk(task, artifactID, chunk, lastChunk); err != nil {
+		return err
+	}
+	return s.updateTask(ctx, task)
+}
+
 // GetTask returns a task with optional history/artifact filtering.
 func (s *SQLiteTaskStore) GetTask(ctx context.Context, taskID string, historyLength int32, includeArtifacts bool) (*a2av1.Task, error) {
 	task, err := s.getTask(ctx, taskID)
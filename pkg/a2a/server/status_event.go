@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Metadata keys of a status event. See docs/EVENT_TAXONOMY.md.
+const (
+	StatusEventTypeKey    = "event_type"
+	StatusEventPayloadKey = "payload"
+)
+
+// ErrNotStatusEvent is returned by ParseStatusEvent when the update carries
+// no event type.
+var ErrNotStatusEvent = errors.New("status update carries no event type")
+
+// StatusEventInfo is the decoded form of a status event.
+type StatusEventInfo struct {
+	TaskID    string
+	ContextID string
+	EventType string
+	// Payload is the payload decoded from JSON, or nil when absent.
+	Payload any
+	State   a2av1.TaskState
+	Final   bool
+	// Message is the status message, if any.
+	Message *a2av1.Message
+}
+
+// NewStatusEvent builds a TaskStatusUpdateEvent that carries eventType and
+// payload in its metadata. payload is encoded as JSON; a value that cannot
+// be encoded is sent as its fmt representation.
+func NewStatusEvent(taskID, contextID, eventType string, payload any, state a2av1.TaskState, final bool) *a2av1.StreamResponse {
+	fields := map[string]*structpb.Value{
+		StatusEventTypeKey: structpb.NewStringValue(eventType),
+	}
+	if payload != nil {
+		fields[StatusEventPayloadKey] = payloadValue(payload)
+	}
+	event := &a2av1.TaskStatusUpdateEvent{
+		TaskId:    taskID,
+		ContextId: contextID,
+		Status: &a2av1.TaskStatus{
+			State:     state,
+			Timestamp: timestamppb.Now(),
+		},
+		Final:    final,
+		Metadata: &structpb.Struct{Fields: fields},
+	}
+	return &a2av1.StreamResponse{Payload: &a2av1.StreamResponse_StatusUpdate{StatusUpdate: event}}
+}
+
+// ParseStatusEvent decodes a status event built by NewStatusEvent. It
+// returns ErrNotStatusEvent, along with the fields it could read, when the
+// update has no event type.
+func ParseStatusEvent(update *a2av1.TaskStatusUpdateEvent) (StatusEventInfo, error) {
+	if update == nil {
+		return StatusEventInfo{}, errors.New("status update is required")
+	}
+	info := StatusEventInfo{
+		TaskID:    update.GetTaskId(),
+		ContextID: update.GetContextId(),
+		State:     update.GetStatus().GetState(),
+		Final:     update.GetFinal(),
+		Message:   update.GetStatus().GetMessage(),
+	}
+	fields := update.GetMetadata().GetFields()
+	if value := fields[StatusEventPayloadKey]; value != nil {
+		info.Payload = value.AsInterface()
+	}
+	info.EventType = fields[StatusEventTypeKey].GetStringValue()
+	if info.EventType == "" {
+		return info, ErrNotStatusEvent
+	}
+	return info, nil
+}
+
+// payloadValue converts payload to a structpb value through JSON, so
+// structs are encoded with their json tags.
+func payloadValue(payload any) *structpb.Value {
+	if value, ok := payload.(*structpb.Value); ok {
+		return value
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return structpb.NewStringValue(fmt.Sprint(payload))
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return structpb.NewStringValue(fmt.Sprint(payload))
+	}
+	value, err := structpb.NewValue(decoded)
+	if err != nil {
+		return structpb.NewStringValue(fmt.Sprint(payload))
+	}
+	return value
+}
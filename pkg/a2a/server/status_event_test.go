@@ -0,0 +1,67 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+)
+
+func TestStatusEvent_RoundTrip(t *testing.T) {
+	type toolDone struct {
+		Tool     string `json:"tool"`
+		Duration int    `json:"duration_ms"`
+	}
+	resp := NewStatusEvent("task-1", "ctx-1", "tool.done", toolDone{Tool: "search", Duration: 12}, a2av1.TaskState_TASK_STATE_WORKING, false)
+	update := resp.GetStatusUpdate()
+	if update == nil {
+		t.Fatalf("expected a status update, got %v", resp)
+	}
+	if update.GetStatus().GetTimestamp() == nil {
+		t.Fatal("expected a status timestamp")
+	}
+
+	info, err := ParseStatusEvent(update)
+	if err != nil {
+		t.Fatalf("ParseStatusEvent error: %v", err)
+	}
+	if info.TaskID != "task-1" || info.ContextID != "ctx-1" || info.EventType != "tool.done" {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	if info.State != a2av1.TaskState_TASK_STATE_WORKING || info.Final {
+		t.Fatalf("unexpected state: %v final=%v", info.State, info.Final)
+	}
+	payload, ok := info.Payload.(map[string]any)
+	if !ok || payload["tool"] != "search" || payload["duration_ms"] != float64(12) {
+		t.Fatalf("unexpected payload: %#v", info.Payload)
+	}
+}
+
+func TestStatusEvent_WithoutPayload(t *testing.T) {
+	resp := NewStatusEvent("task-1", "ctx-1", "agent.task.completed", nil, a2av1.TaskState_TASK_STATE_COMPLETED, true)
+	info, err := ParseStatusEvent(resp.GetStatusUpdate())
+	if err != nil {
+		t.Fatalf("ParseStatusEvent error: %v", err)
+	}
+	if info.Payload != nil || !info.Final {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+}
+
+func TestParseStatusEvent_PlainUpdate(t *testing.T) {
+	update := &a2av1.TaskStatusUpdateEvent{
+		TaskId: "task-1",
+		Status: &a2av1.TaskStatus{State: a2av1.TaskState_TASK_STATE_FAILED},
+		Final:  true,
+	}
+	info, err := ParseStatusEvent(update)
+	if !errors.Is(err, ErrNotStatusEvent) {
+		t.Fatalf("expected ErrNotStatusEvent, got %v", err)
+	}
+	if info.TaskID != "task-1" || info.State != a2av1.TaskState_TASK_STATE_FAILED {
+		t.Fatalf("expected the update fields, got %+v", info)
+	}
+	if _, err := ParseStatusEvent(nil); err == nil {
+		t.Fatal("expected an error for a nil update")
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"sort"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -31,29 +33,150 @@ type TaskStore interface {
 	AppendHistory(ctx context.Context, taskID string, message *a2av1.Message) error
 	UpdateStatus(ctx context.Context, taskID string, status *a2av1.TaskStatus) error
 	AddArtifacts(ctx context.Context, taskID string, artifacts []*a2av1.Artifact) error
+	// AppendArtifactChunk appends chunk to the artifact artifactID, creating
+	// it on the first chunk. lastChunk closes the artifact; appending to a
+	// closed artifact fails.
+	AppendArtifactChunk(ctx context.Context, taskID, artifactID string, chunk *a2av1.Part, lastChunk bool) error
 	GetTask(ctx context.Context, taskID string, historyLength int32, includeArtifacts bool) (*a2av1.Task, error)
 	ListTasks(ctx context.Context, filter TaskFilter) ([]*a2av1.Task, int, error)
 	CancelTask(ctx context.Context, taskID string) (*a2av1.Task, error)
 }
 
 // MemoryTaskStore keeps tasks in memory for the MVP.
+//
+// By default tasks are retained forever. Use WithTaskTTL and WithMaxTasks to
+// bound memory in long-running servers; active tasks are never removed.
 type MemoryTaskStore struct {
 	mu    sync.RWMutex
 	tasks map[string]*taskRecord
+	// lru orders tasks by last access, most recent first.
+	lru *list.List
+
+	ttl           time.Duration
+	maxTasks      int
+	sweepInterval time.Duration
+	expired       int64
+	evicted       int64
+	clock         clock.Clock
+
+	idempotencyTTL time.Duration
+	keys           map[string]idempotencyEntry
+	keysPrunedAt   time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type idempotencyEntry struct {
+	taskID    string
+	expiresAt time.Time
 }
 
 type taskRecord struct {
 	task      *a2av1.Task
 	updatedAt time.Time
+	elem      *list.Element
+}
+
+// MemoryTaskStoreOption configures a MemoryTaskStore.
+type MemoryTaskStoreOption func(*MemoryTaskStore)
+
+// WithTaskTTL removes terminal tasks (completed, failed, cancelled or
+// rejected) that have not been updated for d. A background sweeper runs
+// until Close is called.
+func WithTaskTTL(d time.Duration) MemoryTaskStoreOption {
+	return func(s *MemoryTaskStore) {
+		if d > 0 {
+			s.ttl = d
+		}
+	}
+}
+
+// WithMaxTasks caps the number of retained tasks. When the cap is exceeded
+// the least recently used terminal tasks are evicted. Active tasks are never
+// evicted, so the store may temporarily hold more than n of them.
+func WithMaxTasks(n int) MemoryTaskStoreOption {
+	return func(s *MemoryTaskStore) {
+		if n > 0 {
+			s.maxTasks = n
+		}
+	}
+}
+
+// WithTaskClock sets the clock used for task update times and the TTL
+// sweeper. It defaults to the real clock.
+func WithTaskClock(c clock.Clock) MemoryTaskStoreOption {
+	return func(s *MemoryTaskStore) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// WithIdempotencyTTL sets how long idempotency keys are remembered. It
+// defaults to DefaultIdempotencyTTL.
+func WithIdempotencyTTL(d time.Duration) MemoryTaskStoreOption {
+	return func(s *MemoryTaskStore) {
+		if d > 0 {
+			s.idempotencyTTL = d
+		}
+	}
+}
+
+// TaskStoreStats summarizes the contents of a MemoryTaskStore.
+type TaskStoreStats struct {
+	Total   int
+	ByState map[a2av1.TaskState]int
+	// Expired counts tasks removed by the TTL sweeper.
+	Expired int64
+	// Evicted counts tasks removed to honour WithMaxTasks.
+	Evicted int64
 }
 
 var errInvalidPageToken = fmt.Errorf("invalid page token")
 
 // NewMemoryTaskStore creates a new in-memory task store.
-func NewMemoryTaskStore() *MemoryTaskStore {
-	return &MemoryTaskStore{
-		tasks: make(map[string]*taskRecord),
+func NewMemoryTaskStore(opts ...MemoryTaskStoreOption) *MemoryTaskStore {
+	s := &MemoryTaskStore{
+		tasks:          make(map[string]*taskRecord),
+		lru:            list.New(),
+		clock:          clock.Real(),
+		idempotencyTTL: DefaultIdempotencyTTL,
+		keys:           make(map[string]idempotencyEntry),
+		done:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	if s.ttl > 0 {
+		s.sweepInterval = min(max(s.ttl/2, time.Second), time.Minute)
+		go s.sweepLoop()
+	}
+	return s
+}
+
+// Close stops the TTL sweeper. The store remains usable.
+func (s *MemoryTaskStore) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return nil
+}
+
+// Stats returns the number of retained tasks by state.
+func (s *MemoryTaskStore) Stats() TaskStoreStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := TaskStoreStats{
+		Total:   len(s.tasks),
+		ByState: make(map[a2av1.TaskState]int),
+		Expired: s.expired,
+		Evicted: s.evicted,
 	}
+	for _, record := range s.tasks {
+		stats.ByState[record.task.GetStatus().GetState()]++
+	}
+	return stats
 }
 
 // CreateTask stores a new task and returns it.
@@ -61,31 +184,58 @@ func (s *MemoryTaskStore) CreateTask(ctx context.Context, message *a2av1.Message
 	if message == nil {
 		return nil, fmt.Errorf("message is nil")
 	}
+	task := newTask(message)
 
-	taskID := uuid.NewString()
-	contextID := message.ContextId
-	if contextID == "" {
-		contextID = uuid.NewString()
-	}
+	s.mu.Lock()
+	s.insertLocked(task)
+	s.mu.Unlock()
 
-	message = cloneMessage(message)
-	message.TaskId = taskID
-	message.ContextId = contextID
+	return cloneTask(task), nil
+}
 
-	status := newStatus(a2av1.TaskState_TASK_STATE_SUBMITTED, message)
-	task := &a2av1.Task{
-		Id:        taskID,
-		ContextId: contextID,
-		Status:    status,
-		History:   []*a2av1.Message{message},
+// CreateTaskIdempotent implements IdempotentTaskStore.
+func (s *MemoryTaskStore) CreateTaskIdempotent(ctx context.Context, key string, message *a2av1.Message) (*a2av1.Task, bool, error) {
+	if message == nil {
+		return nil, false, fmt.Errorf("message is nil")
 	}
-
-	now := time.Now().UTC()
 	s.mu.Lock()
-	s.tasks[taskID] = &taskRecord{task: task, updatedAt: now}
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+	now := s.clock.Now().UTC()
+	s.pruneKeysLocked(now)
+	if entry, ok := s.keys[key]; ok && now.Before(entry.expiresAt) {
+		// The task may have been evicted or expired; the key then starts
+		// a new task.
+		if record, ok := s.tasks[entry.taskID]; ok {
+			s.lru.MoveToFront(record.elem)
+			return cloneTask(record.task), false, nil
+		}
+	}
+	task := newTask(message)
+	s.insertLocked(task)
+	s.keys[key] = idempotencyEntry{taskID: task.Id, expiresAt: now.Add(s.idempotencyTTL)}
+	return cloneTask(task), true, nil
+}
 
-	return cloneTask(task), nil
+// insertLocked stores a new task. Must hold s.mu.
+func (s *MemoryTaskStore) insertLocked(task *a2av1.Task) {
+	record := &taskRecord{task: task, updatedAt: s.clock.Now().UTC()}
+	record.elem = s.lru.PushFront(record)
+	s.tasks[task.Id] = record
+	s.evictLocked()
+}
+
+// pruneKeysLocked drops expired idempotency keys, at most once a minute.
+// Must hold s.mu.
+func (s *MemoryTaskStore) pruneKeysLocked(now time.Time) {
+	if now.Sub(s.keysPrunedAt) < min(s.idempotencyTTL, time.Minute) {
+		return
+	}
+	s.keysPrunedAt = now
+	for key, entry := range s.keys {
+		if !now.Before(entry.expiresAt) {
+			delete(s.keys, key)
+		}
+	}
 }
 
 // AppendHistory adds a message to the task history.
@@ -100,7 +250,8 @@ func (s *MemoryTaskStore) AppendHistory(ctx context.Context, taskID string, mess
 		return fmt.Errorf("task %q not found", taskID)
 	}
 	record.task.History = append(record.task.History, cloneMessage(message))
-	record.updatedAt = time.Now().UTC()
+	record.updatedAt = s.clock.Now().UTC()
+	s.lru.MoveToFront(record.elem)
 	return nil
 }
 
@@ -116,7 +267,8 @@ func (s *MemoryTaskStore) UpdateStatus(ctx context.Context, taskID string, statu
 		return fmt.Errorf("task %q not found", taskID)
 	}
 	record.task.Status = status
-	record.updatedAt = time.Now().UTC()
+	record.updatedAt = s.clock.Now().UTC()
+	s.lru.MoveToFront(record.elem)
 	return nil
 }
 
@@ -137,18 +289,36 @@ func (s *MemoryTaskStore) AddArtifacts(ctx context.Context, taskID string, artif
 		}
 		record.task.Artifacts = append(record.task.Artifacts, artifact)
 	}
-	record.updatedAt = time.Now().UTC()
+	record.updatedAt = s.clock.Now().UTC()
+	s.lru.MoveToFront(record.elem)
+	return nil
+}
+
+// AppendArtifactChunk appends a chunk to a streamed artifact of the task.
+func (s *MemoryTaskStore) AppendArtifactChunk(ctx context.Context, taskID, artifactID string, chunk *a2av1.Part, lastChunk bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.tasks[taskID]
+	if !ok {
+		return fmt.Errorf("task %q not found", taskID)
+	}
+	if err := appendArtifactChunk(record.task, artifactID, chunk, lastChunk); err != nil {
+		return err
+	}
+	record.updatedAt = s.clock.Now().UTC()
+	s.lru.MoveToFront(record.elem)
 	return nil
 }
 
 // GetTask returns a task with optional history/artifact filtering.
 func (s *MemoryTaskStore) GetTask(ctx context.Context, taskID string, historyLength int32, includeArtifacts bool) (*a2av1.Task, error) {
-	s.mu.RLock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	record, ok := s.tasks[taskID]
-	s.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("task %q not found", taskID)
 	}
+	s.lru.MoveToFront(record.elem)
 	return filterTask(record.task, historyLength, includeArtifacts), nil
 }
 
@@ -225,10 +395,80 @@ func (s *MemoryTaskStore) CancelTask(ctx context.Context, taskID string) (*a2av1
 	}
 	status := newStatus(a2av1.TaskState_TASK_STATE_CANCELLED, record.task.GetStatus().GetMessage())
 	record.task.Status = status
-	record.updatedAt = time.Now().UTC()
+	record.updatedAt = s.clock.Now().UTC()
+	s.lru.MoveToFront(record.elem)
 	return cloneTask(record.task), nil
 }
 
+// evictLocked removes least recently used terminal tasks while the store
+// exceeds maxTasks. Callers must hold s.mu.
+func (s *MemoryTaskStore) evictLocked() {
+	if s.maxTasks <= 0 {
+		return
+	}
+	for elem := s.lru.Back(); elem != nil && len(s.tasks) > s.maxTasks; {
+		prev := elem.Prev()
+		record := elem.Value.(*taskRecord)
+		if isTerminalState(record.task.GetStatus().GetState()) {
+			s.removeLocked(record)
+			s.evicted++
+		}
+		elem = prev
+	}
+}
+
+// sweep removes terminal tasks last updated before now minus the TTL.
+func (s *MemoryTaskStore) sweep(now time.Time) int {
+	cutoff := now.Add(-s.ttl)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for _, record := range s.tasks {
+		if isTerminalState(record.task.GetStatus().GetState()) && record.updatedAt.Before(cutoff) {
+			s.removeLocked(record)
+			removed++
+		}
+	}
+	s.expired += int64(removed)
+	return removed
+}
+
+func (s *MemoryTaskStore) sweepLoop() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-s.clock.After(s.sweepInterval):
+			s.sweep(now)
+		}
+	}
+}
+
+func (s *MemoryTaskStore) removeLocked(record *taskRecord) {
+	delete(s.tasks, record.task.GetId())
+	s.lru.Remove(record.elem)
+}
+
+// newTask builds a submitted task seeded from message.
+func newTask(message *a2av1.Message) *a2av1.Task {
+	taskID := uuid.NewString()
+	contextID := message.ContextId
+	if contextID == "" {
+		contextID = uuid.NewString()
+	}
+
+	message = cloneMessage(message)
+	message.TaskId = taskID
+	message.ContextId = contextID
+
+	return &a2av1.Task{
+		Id:        taskID,
+		ContextId: contextID,
+		Status:    newStatus(a2av1.TaskState_TASK_STATE_SUBMITTED, message),
+		History:   []*a2av1.Message{message},
+	}
+}
+
 func newStatus(state a2av1.TaskState, message *a2av1.Message) *a2av1.TaskStatus {
 	return &a2av1.TaskStatus{
 		State:     state,
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"github.com/jllopis/kairos/pkg/clock"
+)
+
+func newTestTask(t *testing.T, store *MemoryTaskStore, state a2av1.TaskState) string {
+	t.Helper()
+	task, err := store.CreateTask(context.Background(), &a2av1.Message{
+		MessageId: uuid.NewString(),
+		Role:      a2av1.Role_ROLE_USER,
+		Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+	})
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if state != a2av1.TaskState_TASK_STATE_SUBMITTED {
+		if err := store.UpdateStatus(context.Background(), task.Id, newStatus(state, nil)); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+	}
+	return task.Id
+}
+
+func TestMemoryTaskStore_TTLSweep(t *testing.T) {
+	store := NewMemoryTaskStore(WithTaskTTL(time.Hour))
+	defer store.Close()
+
+	done := newTestTask(t, store, a2av1.TaskState_TASK_STATE_COMPLETED)
+	failed := newTestTask(t, store, a2av1.TaskState_TASK_STATE_FAILED)
+	working := newTestTask(t, store, a2av1.TaskState_TASK_STATE_WORKING)
+	input := newTestTask(t, store, a2av1.TaskState_TASK_STATE_INPUT_REQUIRED)
+
+	if removed := store.sweep(time.Now()); removed != 0 {
+		t.Fatalf("expected fresh tasks to be kept, removed %d", removed)
+	}
+	if removed := store.sweep(time.Now().Add(2 * time.Hour)); removed != 2 {
+		t.Fatalf("expected 2 expired tasks, removed %d", removed)
+	}
+	for _, id := range []string{done, failed} {
+		if _, err := store.GetTask(context.Background(), id, 0, false); err == nil {
+			t.Fatalf("expected task %s to be expired", id)
+		}
+	}
+	for _, id := range []string{working, input} {
+		if _, err := store.GetTask(context.Background(), id, 0, false); err != nil {
+			t.Fatalf("active task %s must be kept: %v", id, err)
+		}
+	}
+	if stats := store.Stats(); stats.Total != 2 || stats.Expired != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMemoryTaskStore_TTLSweeperUsesClock(t *testing.T) {
+	fake := clock.NewFake()
+	store := NewMemoryTaskStore(WithTaskTTL(time.Hour), WithTaskClock(fake))
+	defer store.Close()
+
+	done := newTestTask(t, store, a2av1.TaskState_TASK_STATE_COMPLETED)
+	fake.BlockUntil(1)
+	fake.Advance(90 * time.Minute)
+	// The sweeper waits again once it has swept.
+	fake.BlockUntil(1)
+	if _, err := store.GetTask(context.Background(), done, 0, false); err == nil {
+		t.Fatal("expected the sweeper to expire the task")
+	}
+	if stats := store.Stats(); stats.Expired != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMemoryTaskStore_MaxTasksEvictsTerminalLRU(t *testing.T) {
+	store := NewMemoryTaskStore(WithMaxTasks(3))
+
+	oldest := newTestTask(t, store, a2av1.TaskState_TASK_STATE_COMPLETED)
+	recent := newTestTask(t, store, a2av1.TaskState_TASK_STATE_CANCELLED)
+	working := newTestTask(t, store, a2av1.TaskState_TASK_STATE_WORKING)
+
+	// Reading oldest makes recent the least recently used terminal task.
+	if _, err := store.GetTask(context.Background(), oldest, 0, false); err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	newTestTask(t, store, a2av1.TaskState_TASK_STATE_SUBMITTED)
+
+	if _, err := store.GetTask(context.Background(), recent, 0, false); err == nil {
+		t.Fatal("expected least recently used terminal task to be evicted")
+	}
+	if _, err := store.GetTask(context.Background(), oldest, 0, false); err != nil {
+		t.Fatalf("recently read task must be kept: %v", err)
+	}
+
+	stats := store.Stats()
+	if stats.Total != 3 || stats.Evicted != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.ByState[a2av1.TaskState_TASK_STATE_COMPLETED] != 1 ||
+		stats.ByState[a2av1.TaskState_TASK_STATE_WORKING] != 1 ||
+		stats.ByState[a2av1.TaskState_TASK_STATE_SUBMITTED] != 1 {
+		t.Fatalf("unexpected counts by state: %+v", stats.ByState)
+	}
+	if _, err := store.GetTask(context.Background(), working, 0, false); err != nil {
+		t.Fatalf("active task must be kept: %v", err)
+	}
+}
+
+func TestMemoryTaskStore_MaxTasksNeverEvictsActive(t *testing.T) {
+	store := NewMemoryTaskStore(WithMaxTasks(2))
+
+	for i := 0; i < 4; i++ {
+		newTestTask(t, store, a2av1.TaskState_TASK_STATE_WORKING)
+	}
+	if stats := store.Stats(); stats.Total != 4 || stats.Evicted != 0 {
+		t.Fatalf("active tasks must not be evicted: %+v", stats)
+	}
+}
+
+func TestTaskStore_AppendArtifactChunk(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	sqliteStore, err := NewSQLiteTaskStore(db)
+	if err != nil {
+		t.Fatalf("new sqlite store: %v", err)
+	}
+
+	stores := map[string]TaskStore{
+		"memory": NewMemoryTaskStore(),
+		"sqlite": sqliteStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			task, err := store.CreateTask(ctx, &a2av1.Message{
+				MessageId: uuid.NewString(),
+				Role:      a2av1.Role_ROLE_USER,
+				Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+			})
+			if err != nil {
+				t.Fatalf("create task: %v", err)
+			}
+
+			rows := []string{"id,name\n", "1,ana\n", "2,luis\n"}
+			for i, row := range rows {
+				chunk := &a2av1.Part{Part: &a2av1.Part_Text{Text: row}}
+				if err := store.AppendArtifactChunk(ctx, task.Id, "rows", chunk, i == len(rows)-1); err != nil {
+					t.Fatalf("append chunk %d: %v", i, err)
+				}
+				got, err := store.GetTask(ctx, task.Id, 0, true)
+				if err != nil {
+					t.Fatalf("get task: %v", err)
+				}
+				artifact := got.GetArtifacts()[0]
+				if len(artifact.GetParts()) != i+1 {
+					t.Fatalf("expected %d parts, got %d", i+1, len(artifact.GetParts()))
+				}
+				if streaming := isArtifactStreaming(artifact); streaming != (i < len(rows)-1) {
+					t.Fatalf("chunk %d: unexpected streaming flag %v", i, streaming)
+				}
+			}
+
+			got, err := store.GetTask(ctx, task.Id, 0, true)
+			if err != nil {
+				t.Fatalf("get task: %v", err)
+			}
+			if len(got.GetArtifacts()) != 1 {
+				t.Fatalf("expected 1 artifact, got %d", len(got.GetArtifacts()))
+			}
+			for i, part := range got.GetArtifacts()[0].GetParts() {
+				if part.GetText() != rows[i] {
+					t.Fatalf("part %d: expected %q, got %q", i, rows[i], part.GetText())
+				}
+			}
+			if got.GetArtifacts()[0].GetMetadata() != nil {
+				t.Fatalf("expected streaming metadata to be removed")
+			}
+
+			extra := &a2av1.Part{Part: &a2av1.Part_Text{Text: "3,eva\n"}}
+			if err := store.AppendArtifactChunk(ctx, task.Id, "rows", extra, false); err == nil {
+				t.Fatalf("expected error appending to a complete artifact")
+			}
+			if err := store.AppendArtifactChunk(ctx, "missing", "rows", extra, false); err == nil {
+				t.Fatalf("expected error for missing task")
+			}
+			if err := store.AppendArtifactChunk(ctx, task.Id, "", extra, false); err == nil {
+				t.Fatalf("expected error for empty artifact id")
+			}
+		})
+	}
+}
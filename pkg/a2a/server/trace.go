@@ -3,11 +3,18 @@ package server
 import (
 	"context"
 
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 )
 
+// TraceIDMetadataKey is the message metadata key that carries the trace ID
+// when tracing is enabled (see WithTracing).
+const TraceIDMetadataKey = "trace_id"
+
 func extractTraceContext(ctx context.Context) context.Context {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -16,6 +23,23 @@ func extractTraceContext(ctx context.Context) context.Context {
 	return otel.GetTextMapPropagator().Extract(ctx, metadataCarrier{md: md})
 }
 
+// traceStatus returns status with the trace ID of ctx stamped into its
+// message metadata. The stored status is left untouched.
+func (h *SimpleHandler) traceStatus(ctx context.Context, status *a2av1.TaskStatus) *a2av1.TaskStatus {
+	if !h.Tracing || status.GetMessage() == nil {
+		return status
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return status
+	}
+	stamped := proto.Clone(status).(*a2av1.TaskStatus)
+	stamped.Message.Metadata = mergeMetadata(stamped.Message.Metadata, map[string]string{
+		TraceIDMetadataKey: sc.TraceID().String(),
+	})
+	return stamped
+}
+
 type metadataCarrier struct {
 	md metadata.MD
 }
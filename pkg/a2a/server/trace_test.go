@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithTracing_StampsTraceIDFromTraceparent(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	handler := NewAgentHandler(nil,
+		WithExecutor(&stubExecutor{Output: "ok"}),
+		WithAgentCard(&a2av1.AgentCard{
+			Capabilities: &a2av1.AgentCapabilities{Streaming: boolPtr(true)},
+		}),
+		WithTracing(),
+	)
+	svc := New(handler)
+
+	stream := newStreamRecorder()
+	stream.ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-"+traceID+"-00f067aa0ba902b7-01",
+	))
+	req := &a2av1.SendMessageRequest{
+		Request: &a2av1.Message{
+			MessageId: "msg-1",
+			Role:      a2av1.Role_ROLE_USER,
+			Parts:     []*a2av1.Part{{Part: &a2av1.Part_Text{Text: "hello"}}},
+		},
+	}
+	if err := svc.SendStreamingMessage(req, stream); err != nil {
+		t.Fatalf("SendStreamingMessage error: %v", err)
+	}
+
+	var update *a2av1.TaskStatusUpdateEvent
+	for _, resp := range stream.snapshot() {
+		if resp.GetStatusUpdate() != nil {
+			update = resp.GetStatusUpdate()
+		}
+	}
+	if update == nil {
+		t.Fatalf("expected a status update")
+	}
+	got := update.GetStatus().GetMessage().GetMetadata().GetFields()[TraceIDMetadataKey].GetStringValue()
+	if got != traceID {
+		t.Fatalf("expected trace_id %q, got %q", traceID, got)
+	}
+
+	stored, err := handler.Store.GetTask(context.Background(), update.GetTaskId(), 0, false)
+	if err != nil {
+		t.Fatalf("GetTask error: %v", err)
+	}
+	if _, ok := stored.GetStatus().GetMessage().GetMetadata().GetFields()[TraceIDMetadataKey]; ok {
+		t.Fatalf("stored status should not be modified")
+	}
+}
+
+func TestTraceStatus_DisabledByDefault(t *testing.T) {
+	handler := &SimpleHandler{}
+	status := newStatus(a2av1.TaskState_TASK_STATE_WORKING, ResponseMessage("hi", "ctx", "task"))
+	if got := handler.traceStatus(context.Background(), status); got != status {
+		t.Fatalf("expected status unchanged when tracing is disabled")
+	}
+}
@@ -1,6 +1,9 @@
 package server
 
 import (
+	"log/slog"
+	"time"
+
 	a2av1 "github.com/jllopis/kairos/pkg/a2a/types"
 	"github.com/jllopis/kairos/pkg/core"
 )
@@ -44,6 +47,55 @@ func WithPushConfigStore(store PushConfigStore) HandlerOption {
 	}
 }
 
+// WithPushNotifier delivers task status updates to the webhooks registered
+// in the push config store.
+func WithPushNotifier(notifier *PushNotifier) HandlerOption {
+	return func(h *SimpleHandler) {
+		if notifier != nil {
+			h.PushNotifier = notifier
+		}
+	}
+}
+
+// WithApprovalStore enables human approvals backed by store. Use
+// NewFileApprovalStore or NewSQLiteApprovalStore for pending approvals to
+// survive restarts.
+func WithApprovalStore(store ApprovalStore) HandlerOption {
+	return func(h *SimpleHandler) {
+		if store != nil {
+			h.ApprovalStore = store
+		}
+	}
+}
+
+// WithApprovalTimeout sets how long a pending approval waits for a decision
+// before it expires and its task is rejected.
+func WithApprovalTimeout(timeout time.Duration) HandlerOption {
+	return func(h *SimpleHandler) {
+		h.ApprovalTimeout = timeout
+	}
+}
+
+// WithTracing stamps the trace ID of each request into the metadata of the
+// status messages streamed back (key "trace_id"), so clients can correlate
+// updates with the server-side trace. The trace context itself is always
+// extracted from the incoming W3C traceparent by Service.
+func WithTracing() HandlerOption {
+	return func(h *SimpleHandler) {
+		h.Tracing = true
+	}
+}
+
+// WithLogger routes the handler logs to logger, tagged with
+// component=server, instead of the framework logger installed with
+// log.SetDefault. Without it the handler does not fall back to
+// slog.Default: its logs are dropped until log.SetDefault is called.
+func WithLogger(logger *slog.Logger) HandlerOption {
+	return func(h *SimpleHandler) {
+		h.Logger = logger
+	}
+}
+
 // NewAgentHandler wires a SimpleHandler to a Kairos agent.
 func NewAgentHandler(agent core.Agent, opts ...HandlerOption) *SimpleHandler {
 	handler := &SimpleHandler{
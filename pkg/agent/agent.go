@@ -19,9 +19,12 @@ import (
 	"github.com/jllopis/kairos/pkg/governance"
 	"github.com/jllopis/kairos/pkg/guardrails"
 	"github.com/jllopis/kairos/pkg/llm"
+	klog "github.com/jllopis/kairos/pkg/log"
 	kmcp "github.com/jllopis/kairos/pkg/mcp"
+	"github.com/jllopis/kairos/pkg/mcp/pool"
 	"github.com/jllopis/kairos/pkg/memory"
 	"github.com/jllopis/kairos/pkg/planner"
+	"github.com/jllopis/kairos/pkg/resilience"
 	"github.com/jllopis/kairos/pkg/skills"
 	"github.com/jllopis/kairos/pkg/telemetry"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
@@ -45,12 +48,13 @@ type Agent struct {
 	tracer                trace.Tracer
 	model                 string
 	maxIterations         int
-	mcpClients            []*kmcp.Client
-	disableActionFallback bool
+	reasoningStrategy     ReasoningStrategy
+	disableActionFallback *bool // deprecated flag, nil when unset
 	warnOnActionFallback  bool
 	policyEngine          governance.PolicyEngine
 	toolFilter            *governance.ToolFilter // Centralized tool filtering
 	eventEmitter          core.EventEmitter
+	eventListeners        []func(core.Event)
 	agentsDoc             *governance.AgentInstructions
 	plannerGraph          *planner.Graph
 	plannerHandlers       map[string]planner.Handler
@@ -58,7 +62,36 @@ type Agent struct {
 	plannerAuditStore     planner.AuditStore
 	plannerAuditHook      func(context.Context, planner.AuditEvent)
 	approvalHook          governance.ApprovalHook
+	approvalGate          ApprovalGate
 	guardrails            *guardrails.Guardrails
+	tokenBudget           int
+	contextLimit          int
+	contextReserve        int
+	parallelToolCalls     bool
+	maxIterationsPolicy   MaxIterationsPolicy
+	responseFormat        *llm.ResponseFormat
+	maxDelegationDepth    int
+	toolBulkhead          *resilience.Bulkhead
+	memoryTopK            int
+	memoryMinScore        float32
+	log                   *slog.Logger
+
+	// lastRunMu guards the per-run results exposed for debugging.
+	lastRunMu       sync.Mutex
+	lastRunUsage    llm.Usage
+	lastRunMemories []memory.Match
+
+	// mcpMu guards the MCP clients, which ReloadMCPServers can change
+	// while runs are in flight. mcpReloadMu serializes reloads, which
+	// connect servers without holding mcpMu.
+	mcpReloadMu sync.Mutex
+	mcpMu       sync.RWMutex
+	mcpClients  []*kmcp.Client        // registered with WithMCPClients
+	mcpServers  map[string]*mcpServer // connected from config, by name
+
+	// Shared pool set with WithMCPPool; connections are leased per call.
+	mcpPool        *pool.Pool
+	mcpPoolServers []string
 }
 
 // Option configures an Agent instance.
@@ -81,22 +114,31 @@ func New(id string, llmProvider llm.Provider, opts ...Option) (*Agent, error) {
 		maxIterations: 10, // default
 	}
 
+	// Options may have connected MCP servers already; a failed New must
+	// not leave them open.
+	fail := func(err error) (*Agent, error) {
+		_ = a.Close()
+		return nil, err
+	}
 	for _, opt := range opts {
 		if err := opt(a); err != nil {
-			return nil, err
+			return fail(err)
 		}
 	}
 	if a.agentsDoc == nil {
 		cwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return fail(err)
 		}
 		doc, err := governance.LoadAGENTS(cwd)
 		if err != nil {
-			return nil, err
+			return fail(err)
 		}
 		a.agentsDoc = doc
 	}
+	if err := a.validateMCPPool(); err != nil {
+		return fail(err)
+	}
 	return a, nil
 }
 
@@ -167,10 +209,16 @@ func WithConversationMemory(cm memory.ConversationMemory) Option {
 	}
 }
 
-// WithTools assigns executable tools to the agent.
-func WithTools(tools []core.Tool) Option {
+// WithTools adds executable tools to the agent, such as the tools of a
+// connector (connectors.AsTools). It can be repeated; tools accumulate.
+func WithTools(tools ...core.Tool) Option {
 	return func(a *Agent) error {
-		a.tools = append([]core.Tool(nil), tools...)
+		for _, tool := range tools {
+			if tool == nil {
+				return errors.New("tool cannot be nil")
+			}
+		}
+		a.tools = append(a.tools, tools...)
 		return nil
 	}
 }
@@ -191,32 +239,12 @@ func WithMCPClients(clients ...*kmcp.Client) Option {
 // WithMCPServerConfigs connects MCP clients from config definitions.
 func WithMCPServerConfigs(servers map[string]config.MCPServerConfig) Option {
 	return func(a *Agent) error {
-		for name, server := range servers {
-			transport := strings.ToLower(strings.TrimSpace(server.Transport))
-			if transport == "" {
-				transport = "stdio"
-			}
-
-			opts := mcpClientOptions(server, a.policyEngine, name)
-			switch transport {
-			case "stdio":
-				if strings.TrimSpace(server.Command) == "" {
-					return fmt.Errorf("mcp server %q missing command", name)
-				}
-				client, err := kmcp.NewClientWithStdioProtocol(server.Command, server.Args, server.Env, server.ProtocolVersion, opts...)
-				if err != nil {
-					return fmt.Errorf("mcp server %q: %w", name, err)
-				}
-				a.mcpClients = append(a.mcpClients, client)
-			case "http", "streamable-http", "streamablehttp":
-				client, err := kmcp.NewClientWithStreamableHTTPProtocol(server.URL, server.ProtocolVersion, opts...)
-				if err != nil {
-					return fmt.Errorf("mcp server %q: %w", name, err)
-				}
-				a.mcpClients = append(a.mcpClients, client)
-			default:
-				return fmt.Errorf("mcp server %q has unsupported transport %q", name, server.Transport)
+		for _, name := range sortedServerNames(servers) {
+			client, err := newMCPClient(name, servers[name], a.policyEngine)
+			if err != nil {
+				return err
 			}
+			a.setMCPServer(name, &mcpServer{config: servers[name], client: client})
 		}
 		return nil
 	}
@@ -275,6 +303,21 @@ func WithPlannerAuditHook(hook func(context.Context, planner.AuditEvent)) Option
 	}
 }
 
+// WithMemoryRetrieval limits the memories injected before each Run to the k
+// most relevant to the input with a score of at least minScore. The score
+// threshold needs a backend implementing memory.Searcher (such as
+// memory.VectorMemory); other backends are only capped at k entries.
+func WithMemoryRetrieval(k int, minScore float32) Option {
+	return func(a *Agent) error {
+		if k < 1 {
+			return errors.New("memory retrieval k must be at least 1")
+		}
+		a.memoryTopK = k
+		a.memoryMinScore = minScore
+		return nil
+	}
+}
+
 // WithMemory attaches a memory backend to the agent.
 func WithMemory(memory core.Memory) Option {
 	return func(a *Agent) error {
@@ -294,7 +337,8 @@ func WithModel(model string) Option {
 	}
 }
 
-// WithMaxIterations sets the maximum number of ReAct loop iterations.
+// WithMaxIterations sets the maximum number of ReAct loop iterations. What a
+// run returns when it reaches the limit is set with WithMaxIterationsPolicy.
 func WithMaxIterations(max int) Option {
 	return func(a *Agent) error {
 		if max < 1 {
@@ -305,15 +349,88 @@ func WithMaxIterations(max int) Option {
 	}
 }
 
+// WithTokenBudget caps the tokens (prompt plus completion) a single Run may
+// consume across its ReAct iterations. When the accumulated usage reaches
+// max, the run stops before the next LLM call and returns the partial result
+// together with a CodeBudgetExceeded error. Zero disables the budget.
+func WithTokenBudget(max int) Option {
+	return func(a *Agent) error {
+		if max < 0 {
+			return errors.New("token budget cannot be negative")
+		}
+		a.tokenBudget = max
+		return nil
+	}
+}
+
+// WithToolBulkhead runs every tool call made by the agent through bulkhead,
+// capping how many run concurrently. A call rejected by a full bulkhead is
+// reported to the LLM as a tool error, like any other failure.
+func WithToolBulkhead(bulkhead *resilience.Bulkhead) Option {
+	return func(a *Agent) error {
+		a.toolBulkhead = bulkhead
+		return nil
+	}
+}
+
+// maxParallelToolCalls bounds the tool calls that WithParallelToolCalls runs
+// at once; a tool bulkhead can lower it further.
+const maxParallelToolCalls = 4
+
+// WithParallelToolCalls runs the distinct tool calls of one LLM response
+// concurrently, up to four at a time, instead of one after another. Results
+// are still sent back in the order of the calls, and the tool call started
+// and completed events are emitted from the run goroutine. Events emitted
+// by the tools themselves, such as the delegation events of sub-agent
+// tools, come from the goroutines running the calls, so an event emitter
+// must be safe for concurrent use.
+func WithParallelToolCalls(enabled bool) Option {
+	return func(a *Agent) error {
+		a.parallelToolCalls = enabled
+		return nil
+	}
+}
+
+// WithLogger routes the agent's logs to logger, tagged with
+// component=agent, instead of the framework logger installed with
+// log.SetDefault. A nil logger restores the default. Unlike slog.Default,
+// the framework logger discards everything until log.SetDefault is called,
+// so an agent without WithLogger stays quiet like the rest of the library.
+func WithLogger(logger *slog.Logger) Option {
+	return func(a *Agent) error {
+		if logger == nil {
+			a.log = nil
+			return nil
+		}
+		a.log = logger.With(slog.String(klog.ComponentKey, "agent"))
+		return nil
+	}
+}
+
+// logger returns the logger set with WithLogger or the framework logger.
+func (a *Agent) logger() *slog.Logger {
+	if a.log != nil {
+		return a.log
+	}
+	return klog.For("agent")
+}
+
 // WithDisableActionFallback disables legacy "Action:" parsing in the ReAct loop.
+//
+// Deprecated: use WithReasoningStrategy(ReasoningNativeToolCalls).
 func WithDisableActionFallback(disable bool) Option {
 	return func(a *Agent) error {
-		a.disableActionFallback = disable
+		a.disableActionFallback = &disable
 		return nil
 	}
 }
 
 // WithActionFallbackWarning enables log warnings when legacy Action parsing is used.
+// It only applies to ReasoningAuto; with ReasoningReAct, Action parsing is
+// the expected path.
+//
+// Deprecated: use WithReasoningStrategy to pick ReAct or native tool calls
+// explicitly.
 func WithActionFallbackWarning(enable bool) Option {
 	return func(a *Agent) error {
 		a.warnOnActionFallback = enable
@@ -355,6 +472,20 @@ func WithEventEmitter(emitter core.EventEmitter) Option {
 	}
 }
 
+// WithEventListener registers fn to receive the agent's semantic events:
+// task started/completed, thinking, tool call started/completed, delegation
+// and errors. Listeners are called synchronously from the run goroutine, so
+// they should return quickly. It can be combined with WithEventEmitter.
+func WithEventListener(fn func(core.Event)) Option {
+	return func(a *Agent) error {
+		if fn == nil {
+			return errors.New("event listener cannot be nil")
+		}
+		a.eventListeners = append(a.eventListeners, fn)
+		return nil
+	}
+}
+
 // WithAGENTSInstructions sets the AGENTS.md instructions explicitly.
 func WithAGENTSInstructions(doc *governance.AgentInstructions) Option {
 	return func(a *Agent) error {
@@ -387,13 +518,57 @@ func (a *Agent) Tools() []core.Tool {
 // Memory returns the attached memory backend, if any.
 func (a *Agent) Memory() core.Memory { return a.memory }
 
+// LastRunUsage returns the tokens consumed by the most recent Run, as
+// reported by the LLM provider.
+func (a *Agent) LastRunUsage() llm.Usage {
+	a.lastRunMu.Lock()
+	defer a.lastRunMu.Unlock()
+	return a.lastRunUsage
+}
+
+func (a *Agent) setLastRunUsage(ctx context.Context, usage llm.Usage) {
+	a.lastRunMu.Lock()
+	a.lastRunUsage = usage
+	a.lastRunMu.Unlock()
+	if rec := recorderFor(ctx, a); rec != nil {
+		rec.mu.Lock()
+		rec.usage = usage
+		rec.mu.Unlock()
+	}
+}
+
+// LastRunMemories returns the memories injected into the prompt by the most
+// recent Run, most relevant first. Scores are zero when the memory backend
+// does not implement memory.Searcher.
+func (a *Agent) LastRunMemories() []memory.Match {
+	a.lastRunMu.Lock()
+	defer a.lastRunMu.Unlock()
+	return append([]memory.Match(nil), a.lastRunMemories...)
+}
+
+func (a *Agent) setLastRunMemories(matches []memory.Match) {
+	a.lastRunMu.Lock()
+	a.lastRunMemories = matches
+	a.lastRunMu.Unlock()
+}
+
+// addUsage accumulates delta into total, deriving the total when the
+// provider only reports prompt and completion tokens.
+func addUsage(total *llm.Usage, delta llm.Usage) {
+	if delta.TotalTokens == 0 {
+		delta.TotalTokens = delta.PromptTokens + delta.CompletionTokens
+	}
+	total.PromptTokens += delta.PromptTokens
+	total.CompletionTokens += delta.CompletionTokens
+	total.TotalTokens += delta.TotalTokens
+}
+
 // Run executes the agent loop.
 // If a planner graph is configured, it runs the explicit planner; otherwise it uses the emergent ReAct loop.
+// Use RunDetailed to also get the usage, tool calls and events of the run.
 func (a *Agent) Run(ctx context.Context, input any) (any, error) {
-	if a.plannerGraph != nil {
-		return a.runPlanner(ctx, input)
-	}
-	return a.runEmergent(ctx, input)
+	result, err := a.RunDetailed(ctx, input)
+	return result.Output, err
 }
 
 // runEmergent executes the emergent agent loop (ReAct).
@@ -405,7 +580,7 @@ func (a *Agent) runEmergent(ctx context.Context, input any) (any, error) {
 	ctx, span := a.tracer.Start(ctx, "Agent.Run")
 	defer span.End()
 	traceID, spanID := traceIDs(span)
-	log := slog.Default()
+	log := a.logger()
 
 	inputStr, ok := input.(string)
 	if !ok {
@@ -416,11 +591,7 @@ func (a *Agent) runEmergent(ctx context.Context, input any) (any, error) {
 	span.SetAttributes(telemetry.AgentAttributes(a.id, a.role, a.model, runID, 0, a.maxIterations)...)
 
 	if err := a.checkGuardrailsInput(ctx, log, runID, traceID, spanID, inputStr); err != nil {
-		agentErrorCounter.Add(ctx, 1)
-		if task, ok := core.TaskFromContext(ctx); ok && task != nil {
-			task.Fail(err.Error())
-		}
-		return nil, err
+		return nil, a.guardrailBlocked(ctx, err)
 	}
 
 	// Track task if present
@@ -471,6 +642,7 @@ func (a *Agent) runEmergent(ctx context.Context, input any) (any, error) {
 
 	toolset := a.resolveTools(ctx, log, runID)
 	toolDefs := toolDefinitions(toolset)
+	strategy := a.effectiveReasoningStrategy()
 
 	// Add rich toolset attributes
 	localCount, mcpCount, skillCount := a.countToolsBySource(toolset)
@@ -481,6 +653,7 @@ func (a *Agent) runEmergent(ctx context.Context, input any) (any, error) {
 		slog.String("run_id", runID),
 		slog.Int("tool_count", len(toolset)),
 		slog.String("tools", strings.Join(toolNames(toolset), ", ")),
+		slog.String("reasoning_strategy", strategy.String()),
 	)
 
 	// Construct system prompt with tool instructions if tools are present
@@ -495,7 +668,7 @@ func (a *Agent) runEmergent(ctx context.Context, input any) (any, error) {
 		systemPrompt += "\n\nTools:\n"
 		systemPrompt += strings.Join(toolPromptLines(toolset), "\n")
 		systemPrompt += "\n"
-		if a.disableActionFallback {
+		if strategy == ReasoningNativeToolCalls {
 			systemPrompt += "\nWhen you need a tool, call it using the tool calling interface. When you are done, respond with the final answer."
 		} else {
 			systemPrompt += `
@@ -512,6 +685,14 @@ Final Answer: the final answer to the original input question
 		}
 	}
 
+	if instruction := a.responseFormatInstruction(); instruction != "" {
+		if systemPrompt != "" {
+			systemPrompt += "\n\n"
+		}
+		systemPrompt += instruction
+	}
+	textTools := len(toolset) > 0 && strategy != ReasoningNativeToolCalls
+
 	if systemPrompt != "" {
 		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
 	}
@@ -535,14 +716,11 @@ Final Answer: the final answer to the original input question
 		}
 	}
 
-	// TODO: Retrieve Context from Memory here
 	mem := a.resolveMemory(ctx)
 	memoryRetrieved := 0
-	if mem != nil {
-		if memoryContext := a.loadMemoryContext(ctx, mem, inputStr); memoryContext != "" {
-			messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: memoryContext})
-			memoryRetrieved = 1 // Could count items if we had access
-		}
+	if memoryContext, matches := a.loadMemoryContext(ctx, mem, inputStr); memoryContext != "" {
+		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: memoryContext})
+		memoryRetrieved = len(matches)
 	}
 	// Add memory attributes to span
 	span.SetAttributes(telemetry.MemoryAttributes(mem != nil, fmt.Sprintf("%T", mem), memoryRetrieved, false)...)
@@ -558,13 +736,44 @@ Final Answer: the final answer to the original input question
 		}
 	}
 
+	// inputIndex anchors context trimming: history before it may be dropped.
+	inputIndex := len(messages)
 	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: inputStr})
 
+	var usage llm.Usage
+	a.setLastRunUsage(ctx, usage)
+	partial := ""
+
+	// Each iteration runs under its own span. The previous one is ended at
+	// the top of the next iteration, after the loop, or on return.
+	var iterSpan trace.Span
+	endIteration := func() {
+		if iterSpan != nil {
+			iterSpan.End()
+			iterSpan = nil
+		}
+	}
+	defer endIteration()
+	loopCtx := ctx
+
 	// 2. ReAct Loop
 	for i := 0; i < a.maxIterations; i++ {
-		a.emitEvent(ctx, core.EventAgentThinking, map[string]any{
+		endIteration()
+		if a.tokenBudget > 0 && usage.TotalTokens >= a.tokenBudget {
+			return partial, a.budgetExceeded(ctx, log, runID, traceID, spanID, usage)
+		}
+		a.recordIteration(ctx, i+1)
+		var ctx context.Context
+		ctx, iterSpan = telemetry.StartIterationSpan(loopCtx, i+1, attribute.String(telemetry.AttrAgentModel, a.model))
+		thinking := map[string]any{
 			"iteration": i + 1,
-		})
+		}
+		if trimmed, trim, ok := a.fitContext(ctx, log, runID, messages, inputIndex, toolDefs); ok {
+			messages = trimmed
+			inputIndex -= trim.DroppedHistory
+			thinking["context_trimmed"] = trim.payload()
+		}
+		a.emitEvent(ctx, core.EventAgentThinking, thinking)
 		llmStart := time.Now()
 		llmCtx, llmSpan := a.tracer.Start(ctx, "Agent.LLM.Chat", trace.WithAttributes(
 			attribute.Int("agent.iteration", i+1),
@@ -577,9 +786,12 @@ Final Answer: the final answer to the original input question
 			Model:    a.model,
 			Messages: messages,
 		}
-		if len(toolDefs) > 0 {
+		if len(toolDefs) > 0 && strategy != ReasoningReAct {
 			req.Tools = toolDefs
 		}
+		if len(req.Tools) == 0 {
+			req.ResponseFormat = a.nativeResponseFormat(textTools)
+		}
 
 		resp, err := a.llm.Chat(llmCtx, req)
 		llmDurationMs := time.Since(llmStart).Seconds() * 1000
@@ -587,12 +799,17 @@ Final Answer: the final answer to the original input question
 		// Add post-call attributes including tool calls count
 		if resp != nil {
 			llmSpan.SetAttributes(telemetry.LLMAttributes(a.model, "", len(messages), len(resp.ToolCalls))...)
-			llmSpan.SetAttributes(telemetry.LLMUsageAttributes(0, 0, llmDurationMs, "")...)
+			llmSpan.SetAttributes(telemetry.LLMUsageAttributes(resp.Usage.PromptTokens, resp.Usage.CompletionTokens, llmDurationMs, "")...)
+			iterSpan.SetAttributes(telemetry.LLMUsageAttributes(resp.Usage.PromptTokens, resp.Usage.CompletionTokens, 0, "")...)
+			addUsage(&usage, resp.Usage)
+			a.setLastRunUsage(ctx, usage)
 		}
 
 		llmSpan.End()
 		llmLatencyMs.Record(ctx, llmDurationMs)
 		if err != nil {
+			telemetry.EndSpan(iterSpan, err)
+			iterSpan = nil
 			agentErrorCounter.Add(ctx, 1)
 			ke := WrapLLMError(err, a.model)
 			if em := GetErrorMetrics(); em != nil {
@@ -618,7 +835,10 @@ Final Answer: the final answer to the original input question
 		}
 
 		content := resp.Content
-		messages = append(messages, llm.Message{Role: llm.RoleAssistant, Content: content})
+		messages = append(messages, llm.Message{Role: llm.RoleAssistant, Content: content, ToolCalls: resp.ToolCalls})
+		if strings.TrimSpace(content) != "" {
+			partial = content
+		}
 
 		if len(resp.ToolCalls) > 0 {
 			logDecision(log, decisionPayload{
@@ -641,7 +861,10 @@ Final Answer: the final answer to the original input question
 			parts := strings.Split(content, "Final Answer:")
 			if len(parts) > 1 {
 				finalAnswer := strings.TrimSpace(parts[1])
-				finalAnswer = a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, finalAnswer)
+				finalAnswer, err = a.finishOutput(ctx, log, runID, traceID, spanID, messages, &usage, finalAnswer)
+				if err != nil {
+					return nil, a.guardrailBlocked(ctx, err)
+				}
 				logDecision(log, decisionPayload{
 					AgentID:       a.id,
 					RunID:         runID,
@@ -692,7 +915,10 @@ Final Answer: the final answer to the original input question
 				InputSummary:  summarizeText(inputStr),
 				OutputSummary: summarizeText(content),
 			})
-			content = a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, content)
+			content, err = a.finishOutput(ctx, log, runID, traceID, spanID, messages, &usage, content)
+			if err != nil {
+				return nil, a.guardrailBlocked(ctx, err)
+			}
 			a.storeMemory(ctx, mem, inputStr, content)
 			// Store assistant response in conversation memory
 			if a.conversationMemory != nil && hasSession {
@@ -722,7 +948,7 @@ Final Answer: the final answer to the original input question
 			return content, nil
 		}
 
-		if a.disableActionFallback && len(toolset) > 0 && strings.TrimSpace(content) != "" {
+		if strategy == ReasoningNativeToolCalls && len(toolset) > 0 && strings.TrimSpace(content) != "" {
 			logDecision(log, decisionPayload{
 				AgentID:       a.id,
 				RunID:         runID,
@@ -734,7 +960,10 @@ Final Answer: the final answer to the original input question
 				InputSummary:  summarizeText(inputStr),
 				OutputSummary: summarizeText(content),
 			})
-			content = a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, content)
+			content, err = a.finishOutput(ctx, log, runID, traceID, spanID, messages, &usage, content)
+			if err != nil {
+				return nil, a.guardrailBlocked(ctx, err)
+			}
 			a.storeMemory(ctx, mem, inputStr, content)
 			// Store assistant response in conversation memory
 			if a.conversationMemory != nil && hasSession {
@@ -767,8 +996,8 @@ Final Answer: the final answer to the original input question
 		// Check for Action
 		// Simple parsing logic for now.
 		// TODO: Make this robust (regex or structured output)
-		if !a.disableActionFallback && strings.Contains(content, "Action:") {
-			if a.warnOnActionFallback {
+		if strategy != ReasoningNativeToolCalls && strings.Contains(content, "Action:") {
+			if strategy == ReasoningAuto && a.warnOnActionFallback {
 				log.Warn("agent.action.fallback",
 					slog.String("agent_id", a.id),
 					slog.String("run_id", runID),
@@ -821,15 +1050,20 @@ Final Answer: the final answer to the original input question
 
 				var observation string
 				if foundTool != nil {
-					if decision, ok := a.evaluatePolicy(ctx, log, runID, traceID, spanID, action, ""); ok {
+					if decision, ok := a.evaluatePolicy(ctx, log, runID, traceID, spanID, action, "", actionInput); ok {
 						if !decision.IsAllowed() {
 							observation = fmt.Sprintf("Policy denied: %s", decision.Reason)
 						} else {
 							observation = ""
 						}
 					}
+					if observation == "" {
+						if decision, ok := a.awaitApproval(ctx, log, runID, action, "", actionInput); ok && !decision.IsAllowed() {
+							observation = fmt.Sprintf("Approval denied: %s", decision.Reason)
+						}
+					}
 					if observation != "" {
-						messages = append(messages, llm.Message{Role: llm.RoleUser, Content: fmt.Sprintf("Observation: %s", observation)})
+						messages = append(messages, llm.Message{Role: llm.RoleUser, Content: observationPrefix + observation})
 						continue
 					}
 					log.Info("agent.tool.found",
@@ -838,16 +1072,27 @@ Final Answer: the final answer to the original input question
 						slog.String("tool", action),
 					)
 					toolStart := time.Now()
-					toolCtx, toolSpan := a.tracer.Start(ctx, "Agent.Tool.Call", trace.WithAttributes(
+					toolCtx, toolSpan := telemetry.StartToolSpan(ctx, action,
 						attribute.String("tool.name", action),
-					))
+					)
+					a.emitEvent(ctx, core.EventAgentToolCallStarted, map[string]any{
+						"run_id":      runID,
+						"tool":        action,
+						"tool_source": a.getToolSource(foundTool),
+						"arguments":   actionInput,
+					})
 					// Tool execution
 					// We treat tool Call input as string for this basic implementation
-					res, err := foundTool.Call(toolCtx, actionInput)
-					toolSpan.End()
-					toolLatencyMs.Record(ctx, time.Since(toolStart).Seconds()*1000, metric.WithAttributes(
+					res, err := a.callTool(toolCtx, foundTool, actionInput)
+					telemetry.EndSpan(toolSpan, err)
+					toolDuration := time.Since(toolStart)
+					toolDurationMs := toolDuration.Seconds() * 1000
+					toolLatencyMs.Record(ctx, toolDurationMs, metric.WithAttributes(
 						attribute.String("tool.name", action),
 					))
+					toolMetrics.RecordToolCall(ctx, action, toolDuration, err)
+					a.emitToolCallCompleted(ctx, runID, action, "", toolDurationMs, res, err)
+					a.recordToolCall(ctx, ToolCallRecord{Name: action, Arguments: actionInput, Result: res, Err: err, Duration: toolDuration})
 					if err != nil {
 						ke := WrapToolError(err, action, "")
 						if em := GetErrorMetrics(); em != nil {
@@ -895,7 +1140,7 @@ Final Answer: the final answer to the original input question
 				}
 
 				// Append Observation
-				msg := fmt.Sprintf("Observation: %s", observation)
+				msg := observationPrefix + observation
 				// ReAct paper suggests Observation is next line, often as User or Tool output.
 				// We'll treat it as User message to prompt next thought.
 				messages = append(messages, llm.Message{Role: llm.RoleUser, Content: msg})
@@ -905,7 +1150,10 @@ Final Answer: the final answer to the original input question
 
 		// If no tools defined, just return content (single turn behavior)
 		if len(toolset) == 0 {
-			content = a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, content)
+			content, err = a.finishOutput(ctx, log, runID, traceID, spanID, messages, &usage, content)
+			if err != nil {
+				return nil, a.guardrailBlocked(ctx, err)
+			}
 			a.storeMemory(ctx, mem, inputStr, content)
 			// Store assistant response in conversation memory
 			if a.conversationMemory != nil && hasSession {
@@ -935,9 +1183,67 @@ Final Answer: the final answer to the original input question
 			return content, nil
 		}
 	}
+	endIteration()
+
+	if a.maxIterationsPolicy != MaxIterationsError {
+		log.Warn("agent.run.max_iterations",
+			slog.String("agent_id", a.id),
+			slog.String("run_id", runID),
+			slog.String("trace_id", traceID),
+			slog.String("span_id", spanID),
+			slog.Int("iterations", a.maxIterations),
+			slog.String("policy", a.maxIterationsPolicy.String()),
+		)
+		answer := partial
+		if a.maxIterationsPolicy == MaxIterationsForceFinalAnswer {
+			if a.tokenBudget > 0 && usage.TotalTokens >= a.tokenBudget {
+				return partial, a.budgetExceeded(ctx, log, runID, traceID, spanID, usage)
+			}
+			forced, err := a.forceFinalAnswer(ctx, log, runID, traceID, spanID, messages, &usage)
+			if err != nil {
+				if task, ok := core.TaskFromContext(ctx); ok && task != nil {
+					task.Fail(err.Error())
+				}
+				return nil, err
+			}
+			answer = forced
+		}
+		answer, err := a.finishOutput(ctx, log, runID, traceID, spanID, messages, &usage, answer)
+		if err != nil {
+			return nil, a.guardrailBlocked(ctx, err)
+		}
+		a.storeMemory(ctx, mem, inputStr, answer)
+		// Store assistant response in conversation memory
+		if a.conversationMemory != nil && hasSession {
+			if err := a.storeConversationMessage(ctx, sessionID, llm.RoleAssistant, answer, ""); err != nil {
+				log.Warn("agent.conversation.store_error",
+					slog.String("agent_id", a.id),
+					slog.String("session_id", sessionID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+		agentRunLatencyMs.Record(ctx, time.Since(start).Seconds()*1000)
+		log.Info("agent.run.complete",
+			slog.String("agent_id", a.id),
+			slog.String("run_id", runID),
+			slog.String("trace_id", traceID),
+			slog.String("span_id", spanID),
+			slog.Int("iterations", a.maxIterations),
+		)
+		a.emitEvent(ctx, core.EventAgentTaskCompleted, map[string]any{
+			"run_id":                runID,
+			"result":                answer,
+			"max_iterations_policy": a.maxIterationsPolicy.String(),
+		})
+		if task, ok := core.TaskFromContext(ctx); ok && task != nil {
+			task.Complete(answer)
+		}
+		return answer, nil
+	}
 
 	agentErrorCounter.Add(ctx, 1)
-	ke := WrapTimeoutError(fmt.Errorf("max iterations exceeded"), "agent-loop", a.maxIterations)
+	ke := NewMaxIterationsError(a.maxIterations)
 	if em := GetErrorMetrics(); em != nil {
 		em.RecordError(ctx, ke, "agent-loop")
 	}
@@ -947,7 +1253,7 @@ Final Answer: the final answer to the original input question
 		slog.String("trace_id", traceID),
 		slog.String("span_id", spanID),
 		slog.Int("iterations", a.maxIterations),
-		slog.String("error_code", string(kerrors.CodeTimeout)),
+		slog.String("error_code", string(kerrors.CodeInternal)),
 	)
 	a.emitEvent(ctx, core.EventAgentError, map[string]any{
 		"run_id": runID,
@@ -960,13 +1266,52 @@ Final Answer: the final answer to the original input question
 	return nil, ke
 }
 
-// Close releases MCP client resources if configured.
+// budgetExceeded records and returns the error for a run that exhausted its
+// token budget.
+func (a *Agent) budgetExceeded(ctx context.Context, log *slog.Logger, runID, traceID, spanID string, usage llm.Usage) error {
+	agentErrorCounter.Add(ctx, 1)
+	ke := NewBudgetExceededError(usage.TotalTokens, a.tokenBudget)
+	if em := GetErrorMetrics(); em != nil {
+		em.RecordError(ctx, ke, "agent-loop")
+	}
+	log.Error("agent.run.budget_exceeded",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("trace_id", traceID),
+		slog.String("span_id", spanID),
+		slog.Int("tokens_used", usage.TotalTokens),
+		slog.Int("token_budget", a.tokenBudget),
+		slog.String("error_code", string(kerrors.CodeBudgetExceeded)),
+	)
+	a.emitEvent(ctx, core.EventAgentError, map[string]any{
+		"run_id": runID,
+		"stage":  "budget",
+		"error":  "token budget exceeded",
+	})
+	if task, ok := core.TaskFromContext(ctx); ok && task != nil {
+		task.Fail("token budget exceeded")
+	}
+	return ke
+}
+
+// Close closes the MCP clients the agent owns (WithMCPClients and
+// WithMCPServerConfigs) and stops using the pool set with WithMCPPool,
+// leaving its connections open for other agents.
 func (a *Agent) Close() error {
-	if len(a.mcpClients) == 0 {
+	a.mcpReloadMu.Lock()
+	defer a.mcpReloadMu.Unlock()
+	a.mcpMu.Lock()
+	clients := a.mcpClientsLocked()
+	a.mcpClients = nil
+	a.mcpServers = nil
+	a.mcpPool = nil
+	a.mcpPoolServers = nil
+	a.mcpMu.Unlock()
+	if len(clients) == 0 {
 		return nil
 	}
 	var errs []error
-	for _, client := range a.mcpClients {
+	for _, client := range clients {
 		if err := client.Close(); err != nil {
 			errs = append(errs, err)
 		}
@@ -985,58 +1330,104 @@ func (a *Agent) resolveMemory(ctx context.Context) core.Memory {
 	return mem
 }
 
-func (a *Agent) loadMemoryContext(ctx context.Context, mem core.Memory, query string) string {
+// loadMemoryContext retrieves the memories relevant to query and formats
+// them as a system message. It also records them for LastRunMemories.
+func (a *Agent) loadMemoryContext(ctx context.Context, mem core.Memory, query string) (string, []memory.Match) {
+	a.setLastRunMemories(nil)
 	if mem == nil {
-		return ""
+		return "", nil
 	}
 
 	memStart := time.Now()
 	memCtx, memSpan := a.tracer.Start(ctx, "Agent.Memory.Retrieve")
-	result, err := mem.Retrieve(memCtx, query)
+	matches, err := a.retrieveMemories(memCtx, mem, query)
+	memSpan.SetAttributes(attribute.Int("memory.retrieved", len(matches)))
+	memSpan.End()
 	if err != nil {
-		result, err = mem.Retrieve(memCtx, nil)
-		if err != nil {
-			memSpan.End()
-			memoryLatencyMs.Record(ctx, time.Since(memStart).Seconds()*1000, metric.WithAttributes(
-				attribute.String("memory.operation", "retrieve"),
-				attribute.String("memory.outcome", "empty"),
-			))
-			return ""
-		}
+		memoryLatencyMs.Record(ctx, time.Since(memStart).Seconds()*1000, metric.WithAttributes(
+			attribute.String("memory.operation", "retrieve"),
+			attribute.String("memory.outcome", "empty"),
+		))
+		return "", nil
 	}
-	memSpan.End()
 	memoryLatencyMs.Record(ctx, time.Since(memStart).Seconds()*1000, metric.WithAttributes(
 		attribute.String("memory.operation", "retrieve"),
 		attribute.String("memory.outcome", "hit"),
 	))
+	if len(matches) == 0 {
+		return "", nil
+	}
+	a.setLastRunMemories(matches)
+	return formatMemoryContext(matches), matches
+}
 
-	switch value := result.(type) {
-	case string:
-		if strings.TrimSpace(value) == "" {
-			return ""
+// retrieveMemories searches mem for query, honouring WithMemoryRetrieval.
+func (a *Agent) retrieveMemories(ctx context.Context, mem core.Memory, query string) ([]memory.Match, error) {
+	if a.memoryTopK > 0 {
+		if searcher, ok := mem.(memory.Searcher); ok {
+			return searcher.Search(ctx, query, a.memoryTopK, a.memoryMinScore)
 		}
-		return fmt.Sprintf("Memory context:\n%s", value)
-	case []string:
-		if len(value) == 0 {
-			return ""
+	}
+
+	result, err := mem.Retrieve(ctx, query)
+	if err != nil {
+		result, err = mem.Retrieve(ctx, nil)
+		if err != nil {
+			return nil, err
 		}
-		return fmt.Sprintf("Memory context:\n- %s", strings.Join(value, "\n- "))
+	}
+	matches := memoryMatches(result)
+	if a.memoryTopK > 0 && len(matches) > a.memoryTopK {
+		matches = matches[:a.memoryTopK]
+	}
+	return matches, nil
+}
+
+// memoryMatches converts the result of core.Memory.Retrieve into matches
+// without scores, dropping blank entries.
+func memoryMatches(result any) []memory.Match {
+	var texts []string
+	switch value := result.(type) {
+	case nil:
+	case []memory.Match:
+		return value
+	case []string:
+		texts = value
 	case []any:
-		if len(value) == 0 {
-			return ""
-		}
-		parts := make([]string, 0, len(value))
 		for _, item := range value {
-			parts = append(parts, fmt.Sprint(item))
+			texts = append(texts, fmt.Sprint(item))
 		}
-		return fmt.Sprintf("Memory context:\n- %s", strings.Join(parts, "\n- "))
+	case string:
+		texts = []string{value}
 	default:
-		text := strings.TrimSpace(fmt.Sprint(value))
-		if text == "" {
-			return ""
+		texts = []string{fmt.Sprint(value)}
+	}
+
+	matches := make([]memory.Match, 0, len(texts))
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			matches = append(matches, memory.Match{Text: text})
 		}
-		return fmt.Sprintf("Memory context:\n%s", text)
 	}
+	return matches
+}
+
+// formatMemoryContext renders matches as a system message, delimiting each
+// entry so multi-line memories cannot be mistaken for instructions.
+func formatMemoryContext(matches []memory.Match) string {
+	var b strings.Builder
+	b.WriteString("Memory context (most relevant first):\n")
+	for i, match := range matches {
+		if match.Score > 0 {
+			fmt.Fprintf(&b, "[memory %d | score %.2f]\n", i+1, match.Score)
+		} else {
+			fmt.Fprintf(&b, "[memory %d]\n", i+1)
+		}
+		b.WriteString(match.Text)
+		b.WriteString("\n")
+	}
+	b.WriteString("[end of memory context]")
+	return b.String()
 }
 
 func (a *Agent) storeMemory(ctx context.Context, mem core.Memory, input, output string) {
@@ -1054,7 +1445,7 @@ func (a *Agent) storeMemory(ctx context.Context, mem core.Memory, input, output
 			em.RecordError(ctx, ke, "agent-memory")
 		}
 		agentErrorCounter.Add(ctx, 1)
-		slog.Default().Error("agent.memory.store.error",
+		a.logger().Error("agent.memory.store.error",
 			slog.String("agent_id", a.id),
 			slog.String("run_id", runIDFromContext(ctx)),
 			slog.String("trace_id", traceIDFromContext(ctx)),
@@ -1080,7 +1471,7 @@ func (a *Agent) resolveTools(ctx context.Context, log *slog.Logger, runID string
 	}
 
 	// Add MCP tools
-	for _, client := range a.mcpClients {
+	for _, client := range a.currentMCPClients() {
 		list, err := client.ListTools(ctx)
 		if err != nil {
 			log.Error("agent.mcp.list_tools.error",
@@ -1104,6 +1495,26 @@ func (a *Agent) resolveTools(ctx context.Context, log *slog.Logger, runID string
 			tools = append(tools, adapter)
 		}
 	}
+	for _, pt := range a.listPooledMCPTools(ctx, func(server string, err error) {
+		log.Error("agent.mcp.list_tools.error",
+			slog.String("agent_id", a.id),
+			slog.String("run_id", runID),
+			slog.String("server", server),
+			slog.String("error", err.Error()),
+		)
+	}) {
+		adapter, err := kmcp.NewToolAdapter(pt.tool, pt.caller)
+		if err != nil {
+			log.Error("agent.mcp.tool_adapter.error",
+				slog.String("agent_id", a.id),
+				slog.String("run_id", runID),
+				slog.String("tool", pt.tool.Name),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		tools = append(tools, adapter)
+	}
 
 	// Apply governance tool filter if configured
 	if a.toolFilter != nil {
@@ -1222,13 +1633,27 @@ func (a *Agent) countToolsBySource(tools []core.Tool) (local, mcp, skill int) {
 	return
 }
 
-// getToolSource returns the source type of a tool ("local", "mcp", or "skill").
+// callTool invokes tool through the agent's tool bulkhead, if any.
+func (a *Agent) callTool(ctx context.Context, tool core.Tool, input any) (any, error) {
+	var res any
+	err := resilience.WithBulkhead(ctx, a.toolBulkhead, func() error {
+		var err error
+		res, err = tool.Call(ctx, input)
+		return err
+	})
+	return res, err
+}
+
+// getToolSource returns the source type of a tool ("local", "mcp", "skill"
+// or "subagent").
 func (a *Agent) getToolSource(tool core.Tool) string {
 	switch tool.(type) {
 	case *skills.SkillTool:
 		return "skill"
 	case *kmcp.ToolAdapter:
 		return "mcp"
+	case *subAgentTool:
+		return "subagent"
 	default:
 		return "local"
 	}
@@ -1261,152 +1686,299 @@ func skillFromSpec(spec skills.SkillSpec) core.Skill {
 	}
 }
 
+// toolCallRun tracks one tool call through preparation, execution and
+// reporting.
+type toolCallRun struct {
+	index  int
+	call   llm.ToolCall
+	tool   core.Tool
+	args   string
+	input  any
+	source string
+	ctx    context.Context
+	span   trace.Span
+	start  time.Time
+	end    time.Time
+	result any
+	err    error
+}
+
+// handleToolCalls runs the tool calls of one LLM response and appends a
+// tool message per call, in the order of the calls. Identical calls (same
+// name and arguments) run once and share the result. Policy and approval
+// checks run first, one call at a time; with WithParallelToolCalls the
+// allowed calls then run concurrently.
 func (a *Agent) handleToolCalls(ctx context.Context, log *slog.Logger, runID, traceID, spanID string, toolset []core.Tool, calls []llm.ToolCall, messages *[]llm.Message, rationale string) {
-	for _, call := range calls {
-		toolName := call.Function.Name
+	firstOf := dedupeToolCalls(calls)
+	observations := make([]string, len(calls))
+	var runs []*toolCallRun
+	for i, call := range calls {
+		if first := firstOf[i]; first != i {
+			log.Info("agent.tool.duplicate",
+				slog.String("agent_id", a.id),
+				slog.String("run_id", runID),
+				slog.String("tool", call.Function.Name),
+				slog.String("tool_call_id", call.ID),
+				slog.String("duplicate_of", calls[first].ID),
+			)
+			continue
+		}
+		run, observation := a.prepareToolCall(ctx, log, runID, traceID, spanID, toolset, call, rationale)
+		if run == nil {
+			observations[i] = observation
+			continue
+		}
+		run.index = i
+		runs = append(runs, run)
+	}
+
+	if a.parallelToolCalls && len(runs) > 1 {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, maxParallelToolCalls)
+		for _, run := range runs {
+			wg.Add(1)
+			sem <- struct{}{}
+			// Start once a slot is free, so durations and started events do
+			// not include the time spent queued behind other calls. Starting
+			// here, not in the goroutine, keeps events on the run goroutine.
+			a.startToolCall(ctx, runID, run)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				run.result, run.err = a.callTool(run.ctx, run.tool, run.input)
+				run.end = time.Now()
+			}()
+		}
+		wg.Wait()
+		for _, run := range runs {
+			observations[run.index] = a.finishToolCall(ctx, log, runID, traceID, spanID, run)
+		}
+	} else {
+		for _, run := range runs {
+			a.startToolCall(ctx, runID, run)
+			run.result, run.err = a.callTool(run.ctx, run.tool, run.input)
+			run.end = time.Now()
+			observations[run.index] = a.finishToolCall(ctx, log, runID, traceID, spanID, run)
+		}
+	}
+
+	for i, call := range calls {
+		*messages = append(*messages, llm.Message{
+			Role:       llm.RoleTool,
+			Content:    observations[firstOf[i]],
+			ToolCallID: call.ID,
+		})
+	}
+}
+
+// dedupeToolCalls maps each call to the index of the first call with the
+// same name and arguments. Arguments are compared as JSON, so key order and
+// spacing do not matter.
+func dedupeToolCalls(calls []llm.ToolCall) []int {
+	firstOf := make([]int, len(calls))
+	seen := make(map[string]int, len(calls))
+	for i, call := range calls {
 		args := strings.TrimSpace(call.Function.Arguments)
-		logDecision(log, decisionPayload{
+		var decoded any
+		if err := json.Unmarshal([]byte(args), &decoded); err == nil {
+			if canonical, err := json.Marshal(decoded); err == nil {
+				args = string(canonical)
+			}
+		}
+		key := call.Function.Name + "\x00" + args
+		if first, ok := seen[key]; ok {
+			firstOf[i] = first
+			continue
+		}
+		seen[key] = i
+		firstOf[i] = i
+	}
+	return firstOf
+}
+
+// prepareToolCall resolves the tool of call and checks policy and approval.
+// It returns nil and the observation for the model when the call must not
+// run.
+func (a *Agent) prepareToolCall(ctx context.Context, log *slog.Logger, runID, traceID, spanID string, toolset []core.Tool, call llm.ToolCall, rationale string) (*toolCallRun, string) {
+	toolName := call.Function.Name
+	args := strings.TrimSpace(call.Function.Arguments)
+	logDecision(log, decisionPayload{
+		AgentID:       a.id,
+		RunID:         runID,
+		TraceID:       traceID,
+		SpanID:        spanID,
+		DecisionType:  "tool_call",
+		Rationale:     summarizeText(rationale),
+		OutputSummary: summarizeText(args),
+		ToolName:      toolName,
+		ToolCallID:    call.ID,
+	})
+	log.Info("agent.tool.requested",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("tool", toolName),
+		slog.String("tool_call_id", call.ID),
+		slog.String("action_input", args),
+	)
+
+	var foundTool core.Tool
+	for _, t := range toolset {
+		if t.Name() == toolName {
+			foundTool = t
+			break
+		}
+	}
+	if foundTool == nil {
+		log.Warn("agent.tool.missing",
+			slog.String("agent_id", a.id),
+			slog.String("run_id", runID),
+			slog.String("trace_id", traceID),
+			slog.String("span_id", spanID),
+			slog.String("tool", toolName),
+			slog.String("tool_call_id", call.ID),
+		)
+		return nil, fmt.Sprintf("Tool %s not found", toolName)
+	}
+	if decision, ok := a.evaluatePolicy(ctx, log, runID, traceID, spanID, toolName, call.ID, call.Function.Arguments); ok {
+		if !decision.IsAllowed() {
+			return nil, fmt.Sprintf("Policy denied: %s", decision.Reason)
+		}
+	}
+	if decision, ok := a.awaitApproval(ctx, log, runID, toolName, call.ID, args); ok && !decision.IsAllowed() {
+		return nil, fmt.Sprintf("Approval denied: %s", decision.Reason)
+	}
+	log.Info("agent.tool.found",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("tool", toolName),
+		slog.String("tool_call_id", call.ID),
+	)
+
+	var input any = args
+	if parsed := parseToolArguments(args); parsed != nil {
+		input = parsed
+	}
+	return &toolCallRun{
+		call:   call,
+		tool:   foundTool,
+		args:   args,
+		input:  input,
+		source: a.getToolSource(foundTool),
+	}, ""
+}
+
+// startToolCall opens the span of run and reports that it started.
+func (a *Agent) startToolCall(ctx context.Context, runID string, run *toolCallRun) {
+	run.start = time.Now()
+	run.ctx, run.span = telemetry.StartToolSpan(ctx, run.call.Function.Name)
+	a.emitEvent(ctx, core.EventAgentToolCallStarted, map[string]any{
+		"run_id":       runID,
+		"tool":         run.call.Function.Name,
+		"tool_call_id": run.call.ID,
+		"tool_source":  run.source,
+		"arguments":    run.args,
+	})
+}
+
+// finishToolCall records the outcome of run and returns the observation
+// for the model. The duration runs up to run.end, when the call returned,
+// not up to now: parallel calls are finished after all of them return.
+func (a *Agent) finishToolCall(ctx context.Context, log *slog.Logger, runID, traceID, spanID string, run *toolCallRun) string {
+	toolName, callID := run.call.Function.Name, run.call.ID
+	res, err := run.result, run.err
+	toolDuration := run.end.Sub(run.start)
+	toolDurationMs := toolDuration.Seconds() * 1000
+	a.emitToolCallCompleted(ctx, runID, toolName, callID, toolDurationMs, res, err)
+	a.recordToolCall(ctx, ToolCallRecord{Name: toolName, ID: callID, Arguments: run.args, Result: res, Err: err, Duration: toolDuration})
+
+	// Add rich tool call attributes
+	run.span.SetAttributes(telemetry.ToolCallAttributes(toolName, callID, run.source, toolDurationMs, err == nil)...)
+	run.span.SetAttributes(telemetry.ToolCallArgsResult(run.args, fmt.Sprintf("%v", res), 500)...)
+
+	telemetry.EndSpan(run.span, err, trace.WithTimestamp(run.end))
+	toolLatencyMs.Record(ctx, toolDurationMs, metric.WithAttributes(
+		attribute.String("tool.name", toolName),
+	))
+	toolMetrics.RecordToolCall(ctx, toolName, toolDuration, err)
+	if err != nil {
+		ke := WrapToolError(err, toolName, callID)
+		if em := GetErrorMetrics(); em != nil {
+			em.RecordError(ctx, ke, "agent-tool")
+		}
+		observation := fmt.Sprintf("Error executing tool: %v", err)
+		logDecisionOutcome(log, decisionPayload{
 			AgentID:       a.id,
 			RunID:         runID,
 			TraceID:       traceID,
 			SpanID:        spanID,
 			DecisionType:  "tool_call",
-			Rationale:     summarizeText(rationale),
-			OutputSummary: summarizeText(args),
+			OutputSummary: summarizeText(observation),
 			ToolName:      toolName,
-			ToolCallID:    call.ID,
-		})
-		log.Info("agent.tool.requested",
+			ToolCallID:    callID,
+		}, err)
+		agentErrorCounter.Add(ctx, 1)
+		log.Error("agent.tool.error",
 			slog.String("agent_id", a.id),
 			slog.String("run_id", runID),
+			slog.String("trace_id", traceID),
+			slog.String("span_id", spanID),
 			slog.String("tool", toolName),
-			slog.String("tool_call_id", call.ID),
-			slog.String("action_input", args),
+			slog.String("tool_call_id", callID),
+			slog.String("error", err.Error()),
+			slog.String("error_code", string(kerrors.CodeToolFailure)),
 		)
-
-		var foundTool core.Tool
-		for _, t := range toolset {
-			if t.Name() == toolName {
-				foundTool = t
-				break
-			}
-		}
-
-		observation := ""
-		if foundTool == nil {
-			observation = fmt.Sprintf("Tool %s not found", toolName)
-			log.Warn("agent.tool.missing",
-				slog.String("agent_id", a.id),
-				slog.String("run_id", runID),
-				slog.String("trace_id", traceID),
-				slog.String("span_id", spanID),
-				slog.String("tool", toolName),
-				slog.String("tool_call_id", call.ID),
-			)
-		} else {
-			if decision, ok := a.evaluatePolicy(ctx, log, runID, traceID, spanID, toolName, call.ID); ok {
-				if !decision.IsAllowed() {
-					observation = fmt.Sprintf("Policy denied: %s", decision.Reason)
-					*messages = append(*messages, llm.Message{
-						Role:       llm.RoleTool,
-						Content:    observation,
-						ToolCallID: call.ID,
-					})
-					continue
-				}
-			}
-			log.Info("agent.tool.found",
-				slog.String("agent_id", a.id),
-				slog.String("run_id", runID),
-				slog.String("tool", toolName),
-				slog.String("tool_call_id", call.ID),
-			)
-			toolStart := time.Now()
-
-			// Determine tool source
-			toolSource := a.getToolSource(foundTool)
-			toolCtx, toolSpan := a.tracer.Start(ctx, "Agent.Tool.Call")
-
-			var input any = args
-			if parsed := parseToolArguments(args); parsed != nil {
-				input = parsed
-			}
-			res, err := foundTool.Call(toolCtx, input)
-			toolDurationMs := time.Since(toolStart).Seconds() * 1000
-
-			// Add rich tool call attributes
-			toolSpan.SetAttributes(telemetry.ToolCallAttributes(toolName, call.ID, toolSource, toolDurationMs, err == nil)...)
-			toolSpan.SetAttributes(telemetry.ToolCallArgsResult(args, fmt.Sprintf("%v", res), 500)...)
-
-			toolSpan.End()
-			toolLatencyMs.Record(ctx, toolDurationMs, metric.WithAttributes(
-				attribute.String("tool.name", toolName),
-			))
-			if err != nil {
-				ke := WrapToolError(err, toolName, call.ID)
-				if em := GetErrorMetrics(); em != nil {
-					em.RecordError(ctx, ke, "agent-tool")
-				}
-				observation = fmt.Sprintf("Error executing tool: %v", err)
-				logDecisionOutcome(log, decisionPayload{
-					AgentID:       a.id,
-					RunID:         runID,
-					TraceID:       traceID,
-					SpanID:        spanID,
-					DecisionType:  "tool_call",
-					OutputSummary: summarizeText(observation),
-					ToolName:      toolName,
-					ToolCallID:    call.ID,
-				}, err)
-				agentErrorCounter.Add(ctx, 1)
-				log.Error("agent.tool.error",
-					slog.String("agent_id", a.id),
-					slog.String("run_id", runID),
-					slog.String("trace_id", traceID),
-					slog.String("span_id", spanID),
-					slog.String("tool", toolName),
-					slog.String("tool_call_id", call.ID),
-					slog.String("error", err.Error()),
-					slog.String("error_code", string(kerrors.CodeToolFailure)),
-				)
-				a.emitEvent(ctx, core.EventAgentError, map[string]any{
-					"run_id": runID,
-					"stage":  "tool",
-					"tool":   toolName,
-					"error":  err.Error(),
-				})
-				if task, ok := core.TaskFromContext(ctx); ok && task != nil {
-					task.Fail(err.Error())
-				}
-			} else {
-				observation = fmt.Sprintf("%v", res)
-				logDecisionOutcome(log, decisionPayload{
-					AgentID:       a.id,
-					RunID:         runID,
-					TraceID:       traceID,
-					SpanID:        spanID,
-					DecisionType:  "tool_call",
-					OutputSummary: summarizeText(observation),
-					ToolName:      toolName,
-					ToolCallID:    call.ID,
-				}, nil)
-				log.Info("agent.tool.complete",
-					slog.String("agent_id", a.id),
-					slog.String("run_id", runID),
-					slog.String("trace_id", traceID),
-					slog.String("span_id", spanID),
-					slog.String("tool", toolName),
-					slog.String("tool_call_id", call.ID),
-				)
-			}
+		a.emitEvent(ctx, core.EventAgentError, map[string]any{
+			"run_id": runID,
+			"stage":  "tool",
+			"tool":   toolName,
+			"error":  err.Error(),
+		})
+		if task, ok := core.TaskFromContext(ctx); ok && task != nil {
+			task.Fail(err.Error())
 		}
+		return observation
+	}
+
+	observation := fmt.Sprintf("%v", res)
+	logDecisionOutcome(log, decisionPayload{
+		AgentID:       a.id,
+		RunID:         runID,
+		TraceID:       traceID,
+		SpanID:        spanID,
+		DecisionType:  "tool_call",
+		OutputSummary: summarizeText(observation),
+		ToolName:      toolName,
+		ToolCallID:    callID,
+	}, nil)
+	log.Info("agent.tool.complete",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("trace_id", traceID),
+		slog.String("span_id", spanID),
+		slog.String("tool", toolName),
+		slog.String("tool_call_id", callID),
+	)
+	return observation
+}
 
-		*messages = append(*messages, llm.Message{
-			Role:       llm.RoleTool,
-			Content:    observation,
-			ToolCallID: call.ID,
-		})
+// emitToolCallCompleted reports the outcome of a tool call. The result is
+// summarized to keep events small.
+func (a *Agent) emitToolCallCompleted(ctx context.Context, runID, toolName, toolCallID string, durationMs float64, res any, err error) {
+	payload := map[string]any{
+		"run_id":      runID,
+		"tool":        toolName,
+		"duration_ms": durationMs,
+		"success":     err == nil,
+	}
+	if toolCallID != "" {
+		payload["tool_call_id"] = toolCallID
+	}
+	if err != nil {
+		payload["error"] = err.Error()
+	} else {
+		payload["result"] = summarizeText(fmt.Sprintf("%v", res))
 	}
+	a.emitEvent(ctx, core.EventAgentToolCallCompleted, payload)
 }
 
 func parseToolArguments(raw string) map[string]interface{} {
@@ -1420,13 +1992,28 @@ func parseToolArguments(raw string) map[string]interface{} {
 	return decoded
 }
 
-func (a *Agent) evaluatePolicy(ctx context.Context, log *slog.Logger, runID, traceID, spanID, toolName, toolCallID string) (governance.Decision, bool) {
-	if a.policyEngine == nil {
+func (a *Agent) evaluatePolicy(ctx context.Context, log *slog.Logger, runID, traceID, spanID, toolName, toolCallID string, arguments any) (governance.Decision, bool) {
+	remote := a.toolFilter != nil && a.toolFilter.HasRemotePolicy()
+	if a.policyEngine == nil && !remote {
 		return governance.Decision{}, false
 	}
+	decision := governance.Decision{Allowed: true, Status: governance.DecisionStatusAllow}
+	if remote {
+		// Tools were listed without arguments; the remote policy may
+		// decide differently for this concrete call.
+		decision = a.toolFilter.IsCallAllowed(ctx, toolName, arguments)
+	}
+	if a.policyEngine != nil && decision.IsAllowed() {
+		decision = a.evaluatePolicyEngine(ctx, toolName, toolCallID)
+	}
+	return a.reportPolicyDecision(ctx, log, runID, traceID, spanID, toolName, decision), true
+}
+
+func (a *Agent) evaluatePolicyEngine(ctx context.Context, toolName, toolCallID string) governance.Decision {
 	decision := a.policyEngine.Evaluate(ctx, governance.Action{
-		Type: governance.ActionTool,
-		Name: toolName,
+		Type:      governance.ActionTool,
+		Name:      toolName,
+		Principal: governance.PrincipalFromContext(ctx),
 		Metadata: map[string]string{
 			"agent_id":     a.id,
 			"tool_call_id": toolCallID,
@@ -1434,8 +2021,9 @@ func (a *Agent) evaluatePolicy(ctx context.Context, log *slog.Logger, runID, tra
 	})
 	if decision.IsPending() && a.approvalHook != nil {
 		action := governance.Action{
-			Type: governance.ActionTool,
-			Name: toolName,
+			Type:      governance.ActionTool,
+			Name:      toolName,
+			Principal: governance.PrincipalFromContext(ctx),
 			Metadata: map[string]string{
 				"agent_id":     a.id,
 				"tool_call_id": toolCallID,
@@ -1452,6 +2040,10 @@ func (a *Agent) evaluatePolicy(ctx context.Context, log *slog.Logger, runID, tra
 			decision = hookDecision
 		}
 	}
+	return decision
+}
+
+func (a *Agent) reportPolicyDecision(ctx context.Context, log *slog.Logger, runID, traceID, spanID, toolName string, decision governance.Decision) governance.Decision {
 	if decision.IsPending() && strings.TrimSpace(decision.Reason) == "" {
 		decision.Reason = "approval required"
 	}
@@ -1475,25 +2067,34 @@ func (a *Agent) evaluatePolicy(ctx context.Context, log *slog.Logger, runID, tra
 			slog.String("reason", decision.Reason),
 		)
 	}
-	return decision, true
+	return decision
 }
 
 // ToolNames returns the resolved tool names for the agent.
 func (a *Agent) ToolNames() []string {
 	ctx := context.Background()
-	tools := a.resolveTools(ctx, slog.Default(), "tool-names")
+	tools := a.resolveTools(ctx, a.logger(), "tool-names")
 	return toolNames(tools)
 }
 
-// MCPTools returns the raw MCP tool definitions discovered from configured clients.
+// MCPTools returns the raw MCP tool definitions discovered from configured
+// clients and pool servers.
 func (a *Agent) MCPTools(ctx context.Context) ([]mcpgo.Tool, error) {
-	if len(a.mcpClients) == 0 {
+	var listers []func(context.Context) ([]mcpgo.Tool, error)
+	for _, client := range a.currentMCPClients() {
+		listers = append(listers, client.ListTools)
+	}
+	p, servers := a.pooledMCPServers()
+	for _, server := range servers {
+		listers = append(listers, pooledMCPCaller{pool: p, server: server}.listTools)
+	}
+	if len(listers) == 0 {
 		return nil, nil
 	}
 	seen := make(map[string]bool)
 	out := make([]mcpgo.Tool, 0)
-	for _, client := range a.mcpClients {
-		list, err := client.ListTools(ctx)
+	for _, list := range listers {
+		list, err := list(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -1525,6 +2126,7 @@ var (
 	llmLatencyMs      metric.Float64Histogram
 	toolLatencyMs     metric.Float64Histogram
 	memoryLatencyMs   metric.Float64Histogram
+	toolMetrics       *telemetry.ToolMetrics
 )
 
 func initAgentMetrics() {
@@ -1536,6 +2138,7 @@ func initAgentMetrics() {
 		llmLatencyMs, _ = meter.Float64Histogram("kairos.agent.llm.latency_ms")
 		toolLatencyMs, _ = meter.Float64Histogram("kairos.agent.tool.latency_ms")
 		memoryLatencyMs, _ = meter.Float64Histogram("kairos.agent.memory.latency_ms")
+		toolMetrics, _ = telemetry.NewToolMetrics(context.Background())
 	})
 }
 
@@ -1559,8 +2162,24 @@ func traceIDFromContext(ctx context.Context) string {
 	return sc.TraceID().String()
 }
 
+func (a *Agent) hasEventSinks() bool {
+	return a.eventEmitter != nil || len(a.eventListeners) > 0
+}
+
+// dispatchEvent records event in the run result and delivers it to the
+// emitter and listeners.
+func (a *Agent) dispatchEvent(ctx context.Context, event core.Event) {
+	a.recordEvent(ctx, event)
+	if a.eventEmitter != nil {
+		a.eventEmitter.Emit(ctx, event)
+	}
+	for _, fn := range a.eventListeners {
+		fn(event)
+	}
+}
+
 func (a *Agent) emitEvent(ctx context.Context, eventType core.EventType, payload map[string]any) {
-	if a.eventEmitter == nil {
+	if !a.hasEventSinks() && recorderFor(ctx, a) == nil {
 		return
 	}
 	taskID := ""
@@ -1572,7 +2191,7 @@ func (a *Agent) emitEvent(ctx context.Context, eventType core.EventType, payload
 			}
 		}
 	}
-	a.eventEmitter.Emit(ctx, core.NewEvent(eventType, a.id, taskID, payload))
+	a.dispatchEvent(ctx, core.NewEvent(eventType, a.id, taskID, payload))
 }
 
 func spanIDFromContext(ctx context.Context) string {
@@ -142,6 +142,15 @@ func WrapTimeoutError(err error, operation string, maxIterations int) *errors.Ka
 	return ke
 }
 
+// NewBudgetExceededError creates the error returned when a run consumes
+// its token budget.
+func NewBudgetExceededError(used, budget int) *errors.KairosError {
+	return errors.New(errors.CodeBudgetExceeded, "token budget exceeded", nil).
+		WithContext("tokens_used", used).
+		WithContext("token_budget", budget).
+		WithRecoverable(false)
+}
+
 // WrapPlannerError wraps an explicit planner execution error with context.
 func WrapPlannerError(err error, planID string) *errors.KairosError {
 	if err == nil {
@@ -167,6 +176,22 @@ func WrapGuardrailError(result guardrails.CheckResult) *errors.KairosError {
 	return ke
 }
 
+// WrapGuardrailOutputError wraps a blocked output as a KairosError.
+func WrapGuardrailOutputError(result guardrails.FilterResult) *errors.KairosError {
+	if !result.Blocked {
+		return nil
+	}
+	ke := errors.New(errors.CodeInvalidInput, "guardrail blocked output", nil).
+		WithContext("guardrail_id", result.GuardrailID).
+		WithContext("reason", result.Reason).
+		WithRecoverable(false)
+	if len(result.Flags) > 0 {
+		ke = ke.WithContext("category", string(result.Flags[0].Category)).
+			WithContext("confidence", result.Flags[0].Score)
+	}
+	return ke
+}
+
 // NewInvalidInputError creates a new invalid input error.
 func NewInvalidInputError(msg string) *errors.KairosError {
 	return errors.New(errors.CodeInvalidInput, msg, nil).
@@ -180,3 +205,12 @@ func NewNotFoundError(resource, name string) *errors.KairosError {
 		WithContext("name", name).
 		WithRecoverable(false)
 }
+
+// NewMaxIterationsError creates the error returned when a run reaches its
+// maximum number of iterations under MaxIterationsError.
+func NewMaxIterationsError(maxIterations int) *errors.KairosError {
+	return errors.New(errors.CodeInternal, "max iterations exceeded", nil).
+		WithContext("operation", "agent-loop").
+		WithContext("max_iterations", maxIterations).
+		WithRecoverable(false)
+}
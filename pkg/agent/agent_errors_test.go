@@ -4,13 +4,19 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/config"
 	"github.com/jllopis/kairos/pkg/core"
 	"github.com/jllopis/kairos/pkg/errors"
 	"github.com/jllopis/kairos/pkg/llm"
+	mcpserver "github.com/mark3labs/mcp-go/server"
 )
 
 func TestWrapLLMError(t *testing.T) {
@@ -300,6 +306,49 @@ func TestAgentHealthChecker(t *testing.T) {
 	}
 }
 
+// newHangingPingServer starts an MCP server that connects normally but
+// never answers pings.
+func newHangingPingServer(t *testing.T) string {
+	t.Helper()
+	mcp := mcpserver.NewTestStreamableHTTPServer(mcpserver.NewMCPServer("hanging", "1.0.0"))
+	t.Cleanup(mcp.Close)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.Contains(body, []byte(`"method":"ping"`)) {
+			<-r.Context().Done()
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		mcp.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy.URL
+}
+
+func TestAgentHealthChecker_PingsMCPConcurrently(t *testing.T) {
+	a, err := New("test-agent", &llm.MockProvider{Response: "test"},
+		WithMCPServerConfigs(map[string]config.MCPServerConfig{
+			"a": {Transport: "http", URL: newHangingPingServer(t)},
+			"b": {Transport: "http", URL: newHangingPingServer(t)},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer a.Close()
+
+	checker := NewAgentHealthChecker(a)
+	checker.pingTimeout = 200 * time.Millisecond
+	start := time.Now()
+	result := checker.Check(context.Background())
+	if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Fatalf("expected the pings to run concurrently, Check took %s", elapsed)
+	}
+	if result.Status != core.HealthDegraded {
+		t.Errorf("Check().Status = %v, want %v", result.Status, core.HealthDegraded)
+	}
+}
+
 func TestAgentHealthChecker_NoLLM(t *testing.T) {
 	a := &Agent{
 		id: "test-agent",
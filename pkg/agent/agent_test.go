@@ -1,14 +1,31 @@
 package agent_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/jllopis/kairos/pkg/agent"
 	"github.com/jllopis/kairos/pkg/core"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/governance"
+	"github.com/jllopis/kairos/pkg/guardrails"
 	"github.com/jllopis/kairos/pkg/llm"
+	"github.com/jllopis/kairos/pkg/memory"
+	"github.com/jllopis/kairos/pkg/resilience"
+	"github.com/jllopis/kairos/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // MockTool implements core.Tool for testing
@@ -133,7 +150,7 @@ func TestAgent_ReActLoop(t *testing.T) {
 
 	// Create Agent
 	a, err := agent.New("test-agent", toolCallLLM,
-		agent.WithTools([]core.Tool{tool}),
+		agent.WithTools(tool),
 		agent.WithDisableActionFallback(true),
 	)
 	if err != nil {
@@ -191,6 +208,64 @@ func TestAgent_EmitsSemanticEvents(t *testing.T) {
 	}
 }
 
+// delegatingTool reports a delegation through the run's context emitter,
+// as an A2A client used by a tool would.
+type delegatingTool struct{ MockTool }
+
+func (d *delegatingTool) Call(ctx context.Context, input any) (any, error) {
+	if emitter, ok := core.EventEmitterFromContext(ctx); ok {
+		emitter.Emit(ctx, core.NewEvent(core.EventAgentDelegation, "remote", "", map[string]any{"method": "SendMessage"}))
+	}
+	return d.MockTool.Call(ctx, input)
+}
+
+func TestAgent_EventListenerReceivesToolCalls(t *testing.T) {
+	var events []core.Event
+	a, err := agent.New("listener-agent",
+		&toolCallProvider{ToolName: "Calculator", ToolArgs: `{"input":"10 + 5"}`, Final: "Final Answer: 15"},
+		agent.WithTools(&delegatingTool{MockTool{NameVal: "Calculator"}}),
+		agent.WithDisableActionFallback(true),
+		agent.WithEventListener(func(event core.Event) { events = append(events, event) }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "What is 10 + 5?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := []core.EventType{
+		core.EventAgentTaskStarted,
+		core.EventAgentThinking,
+		core.EventAgentToolCallStarted,
+		core.EventAgentDelegation,
+		core.EventAgentToolCallCompleted,
+		core.EventAgentThinking,
+		core.EventAgentTaskCompleted,
+	}
+	got := make([]core.EventType, 0, len(events))
+	for _, event := range events {
+		got = append(got, event.Type)
+		if event.Timestamp.IsZero() {
+			t.Errorf("event %s has no timestamp", event.Type)
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("unexpected events:\n got %v\nwant %v", got, want)
+	}
+
+	started, completed := events[2].Payload, events[4].Payload
+	if started["tool"] != "Calculator" || started["arguments"] != `{"input":"10 + 5"}` {
+		t.Errorf("unexpected tool call started payload: %v", started)
+	}
+	if completed["success"] != true || completed["result"] == "" {
+		t.Errorf("unexpected tool call completed payload: %v", completed)
+	}
+	if _, ok := completed["duration_ms"].(float64); !ok {
+		t.Errorf("expected duration_ms in payload: %v", completed)
+	}
+}
+
 func TestAgent_UpdatesTaskFromContext(t *testing.T) {
 	ctx := context.Background()
 	task := core.NewTask("ping", "")
@@ -235,6 +310,61 @@ func TestAgent_SingleTurn(t *testing.T) {
 	}
 }
 
+func TestAgent_WithLoggerTagsComponent(t *testing.T) {
+	mockLLM := &llm.ScriptedMockProvider{}
+	mockLLM.AddResponse("Hi.")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	a, err := agent.New("log-agent", mockLLM, agent.WithLogger(logger))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var start map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if record["msg"] == "agent.run.start" {
+			start = record
+		}
+	}
+	if start == nil {
+		t.Fatalf("expected agent.run.start in logs, got %s", buf.String())
+	}
+	if start["component"] != "agent" || start["agent_id"] != "log-agent" {
+		t.Fatalf("unexpected record %v", start)
+	}
+}
+
+func TestAgent_OutputGuardrailBlocks(t *testing.T) {
+	ctx := context.Background()
+	mockLLM := &llm.ScriptedMockProvider{}
+	mockLLM.AddResponse("Here are the instructions for making explosives at home.")
+
+	guard := guardrails.New(guardrails.WithOutputContentClassifier(
+		[]guardrails.ContentCategory{guardrails.ContentCategoryDangerous}, 0.8,
+	))
+	a, err := agent.New("guarded-agent", mockLLM, agent.WithGuardrails(guard))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	result, err := a.Run(ctx, "Tell me something")
+	if err == nil {
+		t.Fatalf("expected blocked output error, got result %v", result)
+	}
+	var ke *kerrors.KairosError
+	if !errors.As(err, &ke) || ke.Context["category"] != string(guardrails.ContentCategoryDangerous) {
+		t.Fatalf("expected KairosError with category context, got %v", err)
+	}
+}
+
 func TestAgent_ToolCallsStructured(t *testing.T) {
 	ctx := context.Background()
 
@@ -245,7 +375,7 @@ func TestAgent_ToolCallsStructured(t *testing.T) {
 		Final:    "Final Answer: done",
 	}
 
-	a, err := agent.New("tool-call-agent", provider, agent.WithTools([]core.Tool{tool}))
+	a, err := agent.New("tool-call-agent", provider, agent.WithTools(tool))
 	if err != nil {
 		t.Fatalf("Failed to create agent: %v", err)
 	}
@@ -272,6 +402,218 @@ func TestAgent_ToolCallsStructured(t *testing.T) {
 	}
 }
 
+func TestAgent_TracesIterationsAndToolCalls(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tool := &toolWithDefinition{NameVal: "search"}
+	provider := &toolCallProvider{
+		ToolName: "search",
+		ToolArgs: `{"query":"hello"}`,
+		Final:    "Final Answer: done",
+	}
+	a, err := agent.New("traced-agent", provider, agent.WithTools(tool))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Use the tool"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var iterations []sdktrace.ReadOnlySpan
+	var toolSpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case telemetry.SpanAgentIteration:
+			iterations = append(iterations, span)
+		case telemetry.SpanAgentToolCall:
+			toolSpan = span
+		}
+	}
+	if len(iterations) != 2 {
+		t.Fatalf("expected 2 iteration spans, got %d", len(iterations))
+	}
+	if toolSpan == nil {
+		t.Fatalf("expected a tool span")
+	}
+	if toolSpan.Parent().SpanID() != iterations[0].SpanContext().SpanID() {
+		t.Fatalf("tool span should be a child of the first iteration span")
+	}
+}
+
+func TestAgent_ToolBulkheadRejectsWhenFull(t *testing.T) {
+	bulkhead := resilience.NewBulkhead(1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = bulkhead.Execute(context.Background(), func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	tool := &toolWithDefinition{NameVal: "search"}
+	provider := &toolCallProvider{
+		ToolName: "search",
+		ToolArgs: `{"query":"hello"}`,
+		Final:    "Final Answer: done",
+	}
+	a, err := agent.New("bulkhead-agent", provider,
+		agent.WithTools(tool),
+		agent.WithToolBulkhead(bulkhead),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Use the tool"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if tool.LastArgs != nil {
+		t.Fatalf("expected tool not to be called while the bulkhead is full")
+	}
+	if stats := bulkhead.Stats(); stats.Rejected != 1 {
+		t.Fatalf("expected 1 rejected call, got %+v", stats)
+	}
+}
+
+// searchMemory implements memory.Searcher over fixed scored entries.
+type searchMemory struct {
+	entries   []memory.Match
+	lastLimit int
+}
+
+func (m *searchMemory) Store(context.Context, any) error { return nil }
+func (m *searchMemory) Retrieve(context.Context, any) (any, error) {
+	return nil, errors.New("use Search")
+}
+func (m *searchMemory) Search(_ context.Context, _ string, limit int, minScore float32) ([]memory.Match, error) {
+	m.lastLimit = limit
+	var out []memory.Match
+	for _, entry := range m.entries {
+		if entry.Score >= minScore && len(out) < limit {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+func TestAgent_MemoryRetrievalTopK(t *testing.T) {
+	mem := &searchMemory{entries: []memory.Match{
+		{Text: "favorite color is blue", Score: 0.9},
+		{Text: "lives in Barcelona", Score: 0.7},
+		{Text: "likes tea", Score: 0.65},
+		{Text: "unrelated note", Score: 0.2},
+	}}
+	var systemPrompt string
+	provider := &llm.MockProvider{ChatFunc: func(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+		for _, msg := range req.Messages {
+			if msg.Role == llm.RoleSystem && strings.HasPrefix(msg.Content, "Memory context") {
+				systemPrompt = msg.Content
+			}
+		}
+		return &llm.ChatResponse{Content: "Final Answer: blue"}, nil
+	}}
+
+	a, err := agent.New("memory-agent", provider,
+		agent.WithMemory(mem),
+		agent.WithMemoryRetrieval(2, 0.5),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "What is my favorite color?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if mem.lastLimit != 2 {
+		t.Fatalf("expected search limit 2, got %d", mem.lastLimit)
+	}
+	if !strings.Contains(systemPrompt, "[memory 1 | score 0.90]\nfavorite color is blue") ||
+		!strings.Contains(systemPrompt, "lives in Barcelona") ||
+		!strings.HasSuffix(systemPrompt, "[end of memory context]") {
+		t.Fatalf("unexpected memory context:\n%s", systemPrompt)
+	}
+	if strings.Contains(systemPrompt, "likes tea") {
+		t.Fatalf("expected only the top 2 memories, got:\n%s", systemPrompt)
+	}
+	got := a.LastRunMemories()
+	if len(got) != 2 || got[0].Text != "favorite color is blue" {
+		t.Fatalf("unexpected LastRunMemories: %+v", got)
+	}
+}
+
+func TestAgent_MemoryRetrievalCapsPlainMemory(t *testing.T) {
+	mem := memory.NewInMemory()
+	_ = mem.Store(context.Background(), []string{"one", "two", "three"})
+
+	a, err := agent.New("memory-agent", &llm.MockProvider{Response: "Final Answer: ok"},
+		agent.WithMemory(mem),
+		agent.WithMemoryRetrieval(2, 0.5),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	got := a.LastRunMemories()
+	if len(got) != 2 || got[0].Text != "one" || got[0].Score != 0 {
+		t.Fatalf("unexpected LastRunMemories: %+v", got)
+	}
+
+	if _, err := agent.New("memory-agent", &llm.MockProvider{}, agent.WithMemoryRetrieval(0, 0)); err == nil {
+		t.Fatalf("expected error for k < 1")
+	}
+}
+
+func TestAgent_RemotePolicyChecksToolCall(t *testing.T) {
+	var digests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input struct {
+				ArgumentsDigest string `json:"arguments_digest"`
+			} `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		digests = append(digests, req.Input.ArgumentsDigest)
+		// Listing (no arguments) is allowed; the concrete call is not.
+		if req.Input.ArgumentsDigest == "" {
+			_, _ = w.Write([]byte(`{"result": true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result": {"allow": false, "reason": "query not allowed"}}`))
+	}))
+	defer srv.Close()
+
+	tool := &toolWithDefinition{NameVal: "search"}
+	provider := &toolCallProvider{ToolName: "search", Final: "Final Answer: done"}
+	a, err := agent.New("remote-policy-agent", provider,
+		agent.WithTools(tool),
+		agent.WithToolFilter(governance.NewToolFilter(governance.WithRemotePolicy(srv.URL))),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Use the tool"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if tool.LastArgs != nil {
+		t.Fatalf("expected tool call to be blocked, got args %v", tool.LastArgs)
+	}
+	if len(digests) < 2 || digests[len(digests)-1] == "" {
+		t.Fatalf("expected a call-time check with an arguments digest, got %v", digests)
+	}
+	last := provider.LastReq.Messages[len(provider.LastReq.Messages)-1]
+	if !strings.Contains(last.Content, "query not allowed") {
+		t.Fatalf("expected policy denial observation, got %q", last.Content)
+	}
+}
+
 type captureModelProvider struct {
 	LastModel string
 }
@@ -298,3 +640,811 @@ func TestAgent_WithModel(t *testing.T) {
 		t.Fatalf("Expected model 'kairos-test-model', got '%s'", capture.LastModel)
 	}
 }
+
+// usageProvider keeps requesting a tool and reports fixed usage per call.
+type usageProvider struct {
+	CallCount int
+}
+
+func (p *usageProvider) Chat(_ context.Context, _ llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.CallCount++
+	return &llm.ChatResponse{
+		Content: fmt.Sprintf("thinking %d", p.CallCount),
+		ToolCalls: []llm.ToolCall{{
+			ID:       fmt.Sprintf("call-%d", p.CallCount),
+			Type:     llm.ToolTypeFunction,
+			Function: llm.FunctionCall{Name: "search", Arguments: `{"query":"more"}`},
+		}},
+		Usage: llm.Usage{PromptTokens: 30, CompletionTokens: 10},
+	}, nil
+}
+
+func TestAgent_TokenBudget(t *testing.T) {
+	provider := &usageProvider{}
+	a, err := agent.New("budget-agent", provider,
+		agent.WithTools(&toolWithDefinition{NameVal: "search"}),
+		agent.WithTokenBudget(100),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	result, err := a.Run(context.Background(), "Search forever")
+	var ke *kerrors.KairosError
+	if !errors.As(err, &ke) || ke.Code != kerrors.CodeBudgetExceeded {
+		t.Fatalf("expected budget exceeded error, got %v", err)
+	}
+	if provider.CallCount != 3 {
+		t.Fatalf("expected 3 LLM calls before stopping, got %d", provider.CallCount)
+	}
+	if result != "thinking 3" {
+		t.Fatalf("expected partial result, got %v", result)
+	}
+	usage := a.LastRunUsage()
+	if usage.PromptTokens != 90 || usage.CompletionTokens != 30 || usage.TotalTokens != 120 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+
+	if _, err := agent.New("bad", provider, agent.WithTokenBudget(-1)); err == nil {
+		t.Fatal("expected negative budget to be rejected")
+	}
+}
+
+func TestAgent_RunDetailed(t *testing.T) {
+	provider := &toolCallProvider{}
+	a, err := agent.New("detailed-agent", provider,
+		agent.WithTools(&toolWithDefinition{NameVal: "search"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	result, err := a.RunDetailed(context.Background(), "Find something")
+	if err != nil {
+		t.Fatalf("RunDetailed failed: %v", err)
+	}
+	if result.Output != "done" {
+		t.Fatalf("unexpected output: %v", result.Output)
+	}
+	if result.Iterations != 2 {
+		t.Fatalf("expected 2 iterations, got %d", result.Iterations)
+	}
+	if len(result.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %+v", result.ToolCalls)
+	}
+	call := result.ToolCalls[0]
+	if call.Name != "search" || call.ID != "call-1" || call.Arguments != `{"query":"hello"}` || call.Result != "ok:search" || call.Err != nil {
+		t.Fatalf("unexpected tool call record: %+v", call)
+	}
+	var types []core.EventType
+	for _, event := range result.Events {
+		types = append(types, event.Type)
+	}
+	if len(types) == 0 || types[0] != core.EventAgentTaskStarted || types[len(types)-1] != core.EventAgentTaskCompleted {
+		t.Fatalf("unexpected events: %v", types)
+	}
+}
+
+func TestAgent_RunDetailedReportsUsageOnError(t *testing.T) {
+	provider := &usageProvider{}
+	a, err := agent.New("budget-agent", provider,
+		agent.WithTools(&toolWithDefinition{NameVal: "search"}),
+		agent.WithTokenBudget(100),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	result, err := a.RunDetailed(context.Background(), "Search forever")
+	if err == nil {
+		t.Fatal("expected budget exceeded error")
+	}
+	if result.Output != "thinking 3" {
+		t.Fatalf("expected partial output, got %v", result.Output)
+	}
+	if result.Usage.TotalTokens != 120 || result.Iterations != 3 || len(result.ToolCalls) != 3 {
+		t.Fatalf("unexpected result: usage=%+v iterations=%d tool calls=%d", result.Usage, result.Iterations, len(result.ToolCalls))
+	}
+}
+
+func TestAgent_SendsRoleAndTools(t *testing.T) {
+	provider := llm.NewRecordingMockProvider("Final Answer: ok")
+	a, err := agent.New("recording-agent", provider,
+		agent.WithRole("You are a librarian."),
+		agent.WithTools(&toolWithDefinition{NameVal: "search"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Find a book"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if got := provider.LastUserMessage(); got != "Find a book" {
+		t.Fatalf("expected user input to be sent, got %q", got)
+	}
+	if system := provider.LastSystemMessage(); !strings.HasPrefix(system, "You are a librarian.") {
+		t.Fatalf("expected role in system prompt, got %q", system)
+	}
+	req, _ := provider.LastRequest()
+	if len(req.Tools) != 1 || req.Tools[0].Function.Name != "search" {
+		t.Fatalf("expected search tool definition, got %+v", req.Tools)
+	}
+}
+
+type scriptedApprovalGate struct {
+	decision governance.Decision
+	actions  []governance.Action
+}
+
+func (g *scriptedApprovalGate) RequiresApproval(toolName string) bool {
+	return toolName == "delete_records"
+}
+
+func (g *scriptedApprovalGate) Await(_ context.Context, action governance.Action) governance.Decision {
+	g.actions = append(g.actions, action)
+	return g.decision
+}
+
+func TestAgent_ApprovalGate(t *testing.T) {
+	cases := []struct {
+		name     string
+		tool     string
+		decision governance.Decision
+		runs     bool
+		awaits   int
+	}{
+		{"approved", "delete_records", governance.Decision{Allowed: true, Status: governance.DecisionStatusAllow}, true, 1},
+		{"rejected", "delete_records", governance.Decision{Status: governance.DecisionStatusDeny, Reason: "not today"}, false, 1},
+		{"not gated", "search", governance.Decision{Status: governance.DecisionStatusDeny}, true, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := &toolWithDefinition{NameVal: tc.tool}
+			gate := &scriptedApprovalGate{decision: tc.decision}
+			provider := &toolCallProvider{ToolName: tc.tool}
+			a, err := agent.New("gated-agent", provider,
+				agent.WithTools(tool),
+				agent.WithApprovalGate(gate),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create agent: %v", err)
+			}
+			if _, err := a.Run(context.Background(), "Clean up"); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if ran := tool.LastArgs != nil; ran != tc.runs {
+				t.Fatalf("expected tool run %v, got %v", tc.runs, ran)
+			}
+			if len(gate.actions) != tc.awaits {
+				t.Fatalf("expected %d approval requests, got %d", tc.awaits, len(gate.actions))
+			}
+			if tc.awaits > 0 {
+				action := gate.actions[0]
+				if action.Name != tc.tool || action.Metadata["tool_call_id"] != "call-1" || action.Metadata["arguments"] == "" {
+					t.Fatalf("unexpected approval action: %+v", action)
+				}
+			}
+			if !tc.runs {
+				last := provider.LastReq.Messages[len(provider.LastReq.Messages)-1]
+				if !strings.Contains(last.Content, "not today") {
+					t.Fatalf("expected rejection observation, got %q", last.Content)
+				}
+			}
+		})
+	}
+}
+
+// repeatingToolProvider calls the tool on every turn until it has seen
+// Calls results, recording each request.
+type repeatingToolProvider struct {
+	Calls    int
+	Requests []llm.ChatRequest
+}
+
+func (p *repeatingToolProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.Requests = append(p.Requests, req)
+	if len(p.Requests) > p.Calls {
+		return &llm.ChatResponse{Content: "Final Answer: done"}, nil
+	}
+	return &llm.ChatResponse{ToolCalls: []llm.ToolCall{{
+		ID:       fmt.Sprintf("call-%d", len(p.Requests)),
+		Type:     llm.ToolTypeFunction,
+		Function: llm.FunctionCall{Name: "fetch", Arguments: `{}`},
+	}}}, nil
+}
+
+type bigResultTool struct{ MockTool }
+
+func (t *bigResultTool) Call(context.Context, any) (any, error) {
+	return strings.Repeat("x", 4000), nil
+}
+
+func TestAgent_ContextLimitTrimsToolResults(t *testing.T) {
+	provider := &repeatingToolProvider{Calls: 4}
+	var trims []map[string]any
+	a, err := agent.New("context-agent", provider,
+		agent.WithRole("You fetch things."),
+		agent.WithTools(&bigResultTool{MockTool{NameVal: "fetch"}}),
+		agent.WithContextLimit(3000, 500),
+		agent.WithEventListener(func(event core.Event) {
+			if trim, ok := event.Payload["context_trimmed"].(map[string]any); ok {
+				trims = append(trims, trim)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Fetch everything"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(trims) == 0 {
+		t.Fatalf("expected a context_trimmed event")
+	}
+
+	last := provider.Requests[len(provider.Requests)-1].Messages
+	if last[0].Role != llm.RoleSystem || !strings.Contains(last[0].Content, "You fetch things.") {
+		t.Fatalf("expected system prompt to be kept, got %+v", last[0])
+	}
+	var trimmed, full, users int
+	for _, msg := range last {
+		switch {
+		case msg.Role == llm.RoleUser:
+			users++
+		case msg.Role == llm.RoleTool && strings.HasPrefix(msg.Content, "[tool result trimmed"):
+			trimmed++
+		case msg.Role == llm.RoleTool:
+			full++
+		}
+	}
+	if users != 1 || full != 1 || trimmed != 3 {
+		t.Fatalf("expected the latest tool result kept and 3 trimmed, got full=%d trimmed=%d users=%d", full, trimmed, users)
+	}
+}
+
+func TestAgent_ContextLimitDropsOldHistory(t *testing.T) {
+	conv := memory.NewInMemoryConversation(memory.ConversationConfig{})
+	ctx := core.WithSessionID(context.Background(), "session-1")
+	for i := 0; i < 20; i++ {
+		_ = conv.AppendMessage(ctx, "session-1", memory.ConversationMessage{
+			Role:    "user",
+			Content: fmt.Sprintf("old message %d %s", i, strings.Repeat("y", 400)),
+		})
+	}
+	provider := &repeatingToolProvider{}
+	a, err := agent.New("history-agent", provider,
+		agent.WithConversationMemory(conv),
+		agent.WithContextLimit(1000, 200),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(ctx, "latest question"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	messages := provider.Requests[0].Messages
+	if got := messages[len(messages)-1].Content; got != "latest question" {
+		t.Fatalf("expected latest user message last, got %q", got)
+	}
+	if len(messages) >= 21 || !strings.Contains(messages[len(messages)-2].Content, "old message 19") {
+		t.Fatalf("expected oldest history dropped and newest kept, got %d messages", len(messages))
+	}
+}
+
+// reactLoopProvider asks for the fetch tool through the ReAct text protocol
+// until it has seen Calls observations, recording each request.
+type reactLoopProvider struct {
+	Calls    int
+	Requests []llm.ChatRequest
+}
+
+func (p *reactLoopProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.Requests = append(p.Requests, req)
+	if len(p.Requests) > p.Calls {
+		return &llm.ChatResponse{Content: "Final Answer: done"}, nil
+	}
+	return &llm.ChatResponse{Content: "Action: fetch\nAction Input: {}"}, nil
+}
+
+func TestAgent_ContextLimitTrimsReActObservations(t *testing.T) {
+	provider := &reactLoopProvider{Calls: 4}
+	a, err := agent.New("react-context-agent", provider,
+		agent.WithTools(&bigResultTool{MockTool{NameVal: "fetch"}}),
+		agent.WithReasoningStrategy(agent.ReasoningReAct),
+		agent.WithContextLimit(3000, 500),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "Fetch everything"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	last := provider.Requests[len(provider.Requests)-1].Messages
+	var input, trimmed, full int
+	for _, msg := range last {
+		switch {
+		case msg.Role != llm.RoleUser:
+		case msg.Content == "Fetch everything":
+			input++
+		case strings.HasPrefix(msg.Content, "Observation: [tool result trimmed"):
+			trimmed++
+		case strings.HasPrefix(msg.Content, "Observation: "):
+			full++
+		}
+	}
+	if input != 1 || full != 1 || trimmed != 3 {
+		t.Fatalf("expected the input and latest observation kept and 3 trimmed, got input=%d full=%d trimmed=%d", input, full, trimmed)
+	}
+}
+
+// windowConversation truncates only when asked through TruncationStrategy,
+// so the agent loads the whole history.
+type windowConversation struct {
+	*memory.InMemoryConversation
+	window int
+}
+
+func (c windowConversation) TruncationStrategy() memory.TruncationStrategy {
+	return &memory.WindowStrategy{MaxMessages: c.window}
+}
+
+func TestAgent_ContextLimitDropsOrphanToolResults(t *testing.T) {
+	conv := windowConversation{InMemoryConversation: memory.NewInMemoryConversation(memory.ConversationConfig{}), window: 2}
+	ctx := core.WithSessionID(context.Background(), "session-1")
+	for _, msg := range []memory.ConversationMessage{
+		{Role: "user", Content: "old question " + strings.Repeat("y", 4000)},
+		{Role: "assistant", Content: "let me look"},
+		{Role: "tool", Content: "lookup result", ToolCallID: "call-old"},
+		{Role: "assistant", Content: "old answer"},
+	} {
+		_ = conv.AppendMessage(ctx, "session-1", msg)
+	}
+	provider := &repeatingToolProvider{}
+	a, err := agent.New("orphan-agent", provider,
+		agent.WithConversationMemory(conv),
+		agent.WithContextLimit(1000, 200),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := a.Run(ctx, "latest question"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, msg := range provider.Requests[0].Messages {
+		if msg.Role == llm.RoleTool {
+			t.Fatalf("expected the tool result without its call to be dropped, got %+v", msg)
+		}
+	}
+	messages := provider.Requests[0].Messages
+	if got := messages[len(messages)-2].Content; got != "old answer" {
+		t.Fatalf("expected the kept history before the input, got %q", got)
+	}
+}
+
+type batchToolProvider struct {
+	Calls    []llm.ToolCall
+	Requests []llm.ChatRequest
+}
+
+func (p *batchToolProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.Requests = append(p.Requests, req)
+	if len(p.Requests) > 1 {
+		return &llm.ChatResponse{Content: "Final Answer: done"}, nil
+	}
+	return &llm.ChatResponse{ToolCalls: p.Calls}, nil
+}
+
+type countingTool struct {
+	MockTool
+	mu    sync.Mutex
+	calls []any
+}
+
+func (t *countingTool) Call(ctx context.Context, input any) (any, error) {
+	t.mu.Lock()
+	t.calls = append(t.calls, input)
+	t.mu.Unlock()
+	return t.MockTool.Call(ctx, input)
+}
+
+func toolCall(id, name, args string) llm.ToolCall {
+	return llm.ToolCall{ID: id, Type: llm.ToolTypeFunction, Function: llm.FunctionCall{Name: name, Arguments: args}}
+}
+
+func TestAgent_DeduplicatesToolCalls(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			provider := &batchToolProvider{Calls: []llm.ToolCall{
+				toolCall("call-1", "lookup", `{"q":"a","n":1}`),
+				toolCall("call-2", "lookup", `{"n": 1, "q": "a"}`),
+				toolCall("call-3", "lookup", `{"q":"b"}`),
+			}}
+			tool := &countingTool{MockTool: MockTool{NameVal: "lookup"}}
+			a, err := agent.New("dedupe-agent", provider,
+				agent.WithTools(tool),
+				agent.WithParallelToolCalls(parallel),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create agent: %v", err)
+			}
+			if _, err := a.Run(context.Background(), "Look things up"); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if len(tool.calls) != 2 {
+				t.Fatalf("expected the duplicate call to run once, got %d calls", len(tool.calls))
+			}
+
+			messages := provider.Requests[1].Messages
+			var assistant *llm.Message
+			var results []llm.Message
+			for i, msg := range messages {
+				switch msg.Role {
+				case llm.RoleAssistant:
+					assistant = &messages[i]
+				case llm.RoleTool:
+					if assistant == nil {
+						t.Fatalf("tool result %s is not preceded by an assistant message", msg.ToolCallID)
+					}
+					results = append(results, msg)
+				}
+			}
+			if assistant == nil {
+				t.Fatal("expected an assistant message before the tool results")
+			}
+			if len(assistant.ToolCalls) != 3 {
+				t.Fatalf("expected the assistant message to carry the 3 tool calls, got %+v", assistant.ToolCalls)
+			}
+			if len(results) != 3 {
+				t.Fatalf("expected a result per tool call, got %d", len(results))
+			}
+			for i, id := range []string{"call-1", "call-2", "call-3"} {
+				if assistant.ToolCalls[i].ID != id {
+					t.Fatalf("expected tool call %d to be %s, got %s", i, id, assistant.ToolCalls[i].ID)
+				}
+				if results[i].ToolCallID != id {
+					t.Fatalf("expected result %d for %s, got %s", i, id, results[i].ToolCallID)
+				}
+			}
+			if results[0].Content != results[1].Content || results[0].Content == results[2].Content {
+				t.Fatalf("unexpected results: %+v", results)
+			}
+		})
+	}
+}
+
+// sleepingTool takes delay to answer.
+type sleepingTool struct {
+	MockTool
+	delay time.Duration
+}
+
+func (t *sleepingTool) Call(ctx context.Context, input any) (any, error) {
+	time.Sleep(t.delay)
+	return t.MockTool.Call(ctx, input)
+}
+
+func TestAgent_ParallelToolCallDurations(t *testing.T) {
+	provider := &batchToolProvider{Calls: []llm.ToolCall{
+		toolCall("call-1", "fast", `{}`),
+		toolCall("call-2", "slow", `{}`),
+	}}
+	a, err := agent.New("parallel-agent", provider,
+		agent.WithTools(
+			&sleepingTool{MockTool: MockTool{NameVal: "fast"}},
+			&sleepingTool{MockTool: MockTool{NameVal: "slow"}, delay: 200 * time.Millisecond},
+		),
+		agent.WithParallelToolCalls(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	result, err := a.RunDetailed(context.Background(), "Look things up")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	durations := map[string]time.Duration{}
+	for _, call := range result.ToolCalls {
+		durations[call.Name] = call.Duration
+	}
+	// The fast call must not be charged the time spent waiting for the
+	// slow one.
+	if durations["fast"] >= 100*time.Millisecond {
+		t.Fatalf("expected the fast call to take less than 100ms, got %s", durations["fast"])
+	}
+	if durations["slow"] < 200*time.Millisecond {
+		t.Fatalf("expected the slow call to take at least 200ms, got %s", durations["slow"])
+	}
+}
+
+// loopingToolProvider never gives a final answer while tools are offered.
+type loopingToolProvider struct {
+	Requests []llm.ChatRequest
+}
+
+func (p *loopingToolProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.Requests = append(p.Requests, req)
+	if len(req.Tools) == 0 {
+		return &llm.ChatResponse{Content: "Final Answer: best effort"}, nil
+	}
+	n := len(p.Requests)
+	return &llm.ChatResponse{
+		Content:   fmt.Sprintf("Still working, step %d", n),
+		ToolCalls: []llm.ToolCall{toolCall(fmt.Sprintf("call-%d", n), "lookup", fmt.Sprintf(`{"step":%d}`, n))},
+	}, nil
+}
+
+func TestAgent_MaxIterationsPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []agent.Option
+		want     string
+		wantCode kerrors.ErrorCode
+		requests int
+	}{
+		{name: "default", wantCode: kerrors.CodeInternal, requests: 3},
+		{name: "error", opts: []agent.Option{agent.WithMaxIterationsPolicy(agent.MaxIterationsError)}, wantCode: kerrors.CodeInternal, requests: 3},
+		{name: "return partial", opts: []agent.Option{agent.WithMaxIterationsPolicy(agent.MaxIterationsReturnPartial)}, want: "Still working, step 3", requests: 3},
+		{name: "force final answer", opts: []agent.Option{agent.WithMaxIterationsPolicy(agent.MaxIterationsForceFinalAnswer)}, want: "best effort", requests: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &loopingToolProvider{}
+			opts := append([]agent.Option{
+				agent.WithTools(&MockTool{NameVal: "lookup"}),
+				agent.WithMaxIterations(3),
+			}, tt.opts...)
+			a, err := agent.New("looping-agent", provider, opts...)
+			if err != nil {
+				t.Fatalf("Failed to create agent: %v", err)
+			}
+			result, err := a.Run(context.Background(), "Never finish")
+			if len(provider.Requests) != tt.requests {
+				t.Fatalf("expected %d LLM calls, got %d", tt.requests, len(provider.Requests))
+			}
+			if tt.wantCode != "" {
+				var ke *kerrors.KairosError
+				if !errors.As(err, &ke) || ke.Code != tt.wantCode {
+					t.Fatalf("expected %s error, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result != tt.want {
+				t.Fatalf("expected %q, got %v", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestWithMaxIterationsPolicyRejectsUnknown(t *testing.T) {
+	if _, err := agent.New("a", &repeatingToolProvider{}, agent.WithMaxIterationsPolicy(agent.MaxIterationsPolicy(99))); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
+
+// reactTextProvider answers with a ReAct action first and then a final
+// answer, recording the requests it receives.
+type reactTextProvider struct {
+	Requests []llm.ChatRequest
+}
+
+func (p *reactTextProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.Requests = append(p.Requests, req)
+	if len(p.Requests) > 1 {
+		return &llm.ChatResponse{Content: "Final Answer: done"}, nil
+	}
+	return &llm.ChatResponse{Content: "Action: lookup\nAction Input: x"}, nil
+}
+
+type toolCallingProvider struct {
+	reactTextProvider
+	supported bool
+}
+
+func (p *toolCallingProvider) SupportsToolCalling() bool { return p.supported }
+
+func TestAgent_ReasoningStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  func() (llm.Provider, *reactTextProvider)
+		opts      []agent.Option
+		wantTools bool // tool definitions sent to the model
+		wantCall  bool // "Action:" text parsed and the tool run
+	}{
+		{
+			name:      "auto unknown provider",
+			provider:  recordingProvider(nil),
+			wantTools: true,
+			wantCall:  true,
+		},
+		{
+			name:      "auto tool calling provider",
+			provider:  recordingProvider(ptr(true)),
+			wantTools: true,
+		},
+		{
+			name:     "auto provider without tool calling",
+			provider: recordingProvider(ptr(false)),
+			wantCall: true,
+		},
+		{
+			name:     "forced react",
+			provider: recordingProvider(ptr(true)),
+			opts:     []agent.Option{agent.WithReasoningStrategy(agent.ReasoningReAct)},
+			wantCall: true,
+		},
+		{
+			name:      "forced native",
+			provider:  recordingProvider(nil),
+			opts:      []agent.Option{agent.WithReasoningStrategy(agent.ReasoningNativeToolCalls)},
+			wantTools: true,
+		},
+		{
+			name:      "deprecated fallback flag",
+			provider:  recordingProvider(ptr(true)),
+			opts:      []agent.Option{agent.WithDisableActionFallback(false)},
+			wantTools: true,
+			wantCall:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, rec := tt.provider()
+			tool := &countingTool{MockTool: MockTool{NameVal: "lookup"}}
+			opts := append([]agent.Option{agent.WithTools(tool)}, tt.opts...)
+			a, err := agent.New("reasoning-agent", provider, opts...)
+			if err != nil {
+				t.Fatalf("New error: %v", err)
+			}
+			if _, err := a.Run(context.Background(), "look it up"); err != nil {
+				t.Fatalf("Run error: %v", err)
+			}
+			if got := len(rec.Requests[0].Tools) > 0; got != tt.wantTools {
+				t.Fatalf("tools sent = %v, want %v", got, tt.wantTools)
+			}
+			if got := len(tool.calls) > 0; got != tt.wantCall {
+				t.Fatalf("tool called = %v, want %v", got, tt.wantCall)
+			}
+		})
+	}
+
+	if _, err := agent.New("reasoning-agent", &reactTextProvider{}, agent.WithReasoningStrategy(agent.ReasoningStrategy(42))); err == nil {
+		t.Fatal("expected error for unknown reasoning strategy")
+	}
+}
+
+// recordingProvider builds a provider that reports tool calling support
+// when supported is not nil.
+func recordingProvider(supported *bool) func() (llm.Provider, *reactTextProvider) {
+	return func() (llm.Provider, *reactTextProvider) {
+		if supported == nil {
+			p := &reactTextProvider{}
+			return p, p
+		}
+		p := &toolCallingProvider{supported: *supported}
+		return p, &p.reactTextProvider
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// formatProvider replies with scripted contents and records the requests.
+// It enforces response formats natively when native is set.
+type formatProvider struct {
+	Replies  []string
+	Requests []llm.ChatRequest
+	native   bool
+}
+
+func (p *formatProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.Requests = append(p.Requests, req)
+	reply := p.Replies[0]
+	if len(p.Replies) > 1 {
+		p.Replies = p.Replies[1:]
+	}
+	return &llm.ChatResponse{Content: reply}, nil
+}
+
+func (p *formatProvider) SupportsResponseFormat(llm.ResponseFormatType) bool { return p.native }
+
+func TestAgent_ResponseFormatNative(t *testing.T) {
+	provider := &formatProvider{native: true, Replies: []string{"```json\n{\"ok\": true}\n```"}}
+	a, err := agent.New("json-agent", provider, agent.WithResponseFormat(agent.JSONObject))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	out, err := a.Run(context.Background(), "status?")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if out != `{"ok": true}` {
+		t.Fatalf("expected the extracted JSON, got %q", out)
+	}
+	req := provider.Requests[0]
+	if req.ResponseFormat == nil || req.ResponseFormat.Type != llm.ResponseFormatJSONObject {
+		t.Fatalf("expected a native JSON object format, got %+v", req.ResponseFormat)
+	}
+	if !strings.Contains(req.Messages[0].Content, "JSON") {
+		t.Fatalf("expected a JSON instruction in the system prompt, got %q", req.Messages[0].Content)
+	}
+}
+
+func TestAgent_ResponseFormatNotSentWithTools(t *testing.T) {
+	provider := &formatProvider{native: true, Replies: []string{`{"ok": true}`}}
+	a, err := agent.New("json-agent", provider,
+		agent.WithTools(&MockTool{NameVal: "lookup"}),
+		agent.WithReasoningStrategy(agent.ReasoningNativeToolCalls),
+		agent.WithResponseFormat(agent.JSONObject),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "status?"); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	req := provider.Requests[0]
+	if len(req.Tools) == 0 {
+		t.Fatal("expected the tools on the request")
+	}
+	if req.ResponseFormat != nil {
+		t.Fatalf("expected no native format next to tools, got %+v", req.ResponseFormat)
+	}
+}
+
+func TestAgent_ResponseFormatRepairsOnce(t *testing.T) {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"total": map[string]any{"type": "integer"}},
+		"required":   []string{"total"},
+	}
+	provider := &formatProvider{Replies: []string{`The total is {"sum": 3}`, `{"total": 3}`}}
+	a, err := agent.New("json-agent", provider, agent.WithResponseFormat(agent.JSONSchema(schema)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	out, err := a.Run(context.Background(), "add 1 and 2")
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if out != `{"total": 3}` {
+		t.Fatalf("expected the repaired JSON, got %q", out)
+	}
+	if len(provider.Requests) != 2 {
+		t.Fatalf("expected one repair call, got %d requests", len(provider.Requests))
+	}
+	if provider.Requests[0].ResponseFormat != nil {
+		t.Fatalf("format must not be sent to a provider without support")
+	}
+	repair := provider.Requests[1].Messages
+	if last := repair[len(repair)-1]; last.Role != llm.RoleUser || !strings.Contains(last.Content, "total: is required") {
+		t.Fatalf("expected the schema violation in the repair prompt, got %+v", last)
+	}
+}
+
+func TestAgent_ResponseFormatFailsAfterRepair(t *testing.T) {
+	provider := &formatProvider{Replies: []string{"no json", "still no json"}}
+	a, err := agent.New("json-agent", provider, agent.WithResponseFormat(agent.JSONObject))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	_, err = a.Run(context.Background(), "status?")
+	if code, ok := kerrors.CodeOf(err); !ok || code != kerrors.CodeLLMError {
+		t.Fatalf("expected an LLM error, got %v", err)
+	}
+	if len(provider.Requests) != 2 {
+		t.Fatalf("expected a single repair attempt, got %d requests", len(provider.Requests))
+	}
+}
+
+func TestWithResponseFormatRejectsInvalid(t *testing.T) {
+	if _, err := agent.New("a", &formatProvider{}, agent.WithResponseFormat(agent.JSONSchema("not a schema"))); err == nil {
+		t.Fatal("expected error for an invalid schema")
+	}
+	if _, err := agent.New("a", &formatProvider{}, agent.WithResponseFormat(agent.ResponseFormat{Type: "yaml"})); err == nil {
+		t.Fatal("expected error for an unknown format")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/core"
+	"github.com/jllopis/kairos/pkg/governance"
+)
+
+// ApprovalGate pauses calls to dangerous tools until a human decides on
+// them. Unlike an ApprovalHook, which resolves pending policy decisions, a
+// gate applies to every call of the tools it selects.
+type ApprovalGate interface {
+	// RequiresApproval reports whether calls to the named tool must be
+	// approved before running.
+	RequiresApproval(toolName string) bool
+	// Await blocks until the call described by action is approved,
+	// rejected or expires, or ctx is done. Only an allowed decision lets
+	// the tool run.
+	Await(ctx context.Context, action governance.Action) governance.Decision
+}
+
+// WithApprovalGate pauses the tool calls selected by gate until they are
+// approved. Rejected, expired or cancelled approvals reach the model as a
+// tool error so it can change course.
+func WithApprovalGate(gate ApprovalGate) Option {
+	return func(a *Agent) error {
+		a.approvalGate = gate
+		return nil
+	}
+}
+
+// awaitApproval passes a tool call through the approval gate. It reports
+// false when the gate does not apply to the tool.
+func (a *Agent) awaitApproval(ctx context.Context, log *slog.Logger, runID, toolName, toolCallID, arguments string) (governance.Decision, bool) {
+	if a.approvalGate == nil || !a.approvalGate.RequiresApproval(toolName) {
+		return governance.Decision{}, false
+	}
+	action := governance.Action{
+		Type:      governance.ActionTool,
+		Name:      toolName,
+		Principal: governance.PrincipalFromContext(ctx),
+		Metadata: map[string]string{
+			"agent_id":     a.id,
+			"run_id":       runID,
+			"tool_call_id": toolCallID,
+			"arguments":    arguments,
+		},
+	}
+	if task, ok := core.TaskFromContext(ctx); ok && task != nil && task.ID != "" {
+		action.Metadata["task_id"] = task.ID
+	}
+	if sessionID, ok := core.SessionID(ctx); ok && sessionID != "" {
+		action.Metadata["session_id"] = sessionID
+	}
+
+	log.Info("agent.approval.requested",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("tool", toolName),
+		slog.String("tool_call_id", toolCallID),
+	)
+	decision := a.approvalGate.Await(ctx, action)
+	if !decision.IsAllowed() && strings.TrimSpace(decision.Reason) == "" {
+		decision.Reason = "approval rejected"
+	}
+	log.Info("agent.approval.resolved",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("tool", toolName),
+		slog.String("tool_call_id", toolCallID),
+		slog.Bool("approved", decision.IsAllowed()),
+		slog.String("reason", decision.Reason),
+	)
+	return decision, true
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/llm"
+	"github.com/jllopis/kairos/pkg/memory"
+)
+
+// trimmedToolResult replaces the content of tool results dropped to fit the
+// context window. The message itself stays, so every tool call keeps its
+// result.
+const trimmedToolResult = "[tool result trimmed to fit the context window]"
+
+// observationPrefix starts the user messages that carry tool results in the
+// ReAct text protocol.
+const observationPrefix = "Observation: "
+
+// isToolResult reports whether msg carries a tool result, either as a tool
+// message or as a ReAct observation.
+func isToolResult(msg llm.Message) bool {
+	return msg.Role == llm.RoleTool || (msg.Role == llm.RoleUser && strings.HasPrefix(msg.Content, observationPrefix))
+}
+
+// trimToolResult returns the content that replaces the tool result in msg.
+func trimToolResult(msg llm.Message) string {
+	if msg.Role == llm.RoleUser {
+		return observationPrefix + trimmedToolResult
+	}
+	return trimmedToolResult
+}
+
+// WithContextLimit keeps each LLM request within maxTokens, leaving reserve
+// tokens for the response. Before every call the prompt size is estimated
+// and, when it does not fit, the oldest tool results are trimmed first and
+// then the oldest conversation history, through the truncation strategy of
+// the conversation memory when it has one. System messages and the input
+// of the run are always kept.
+func WithContextLimit(maxTokens, reserve int) Option {
+	return func(a *Agent) error {
+		a.contextLimit = maxTokens
+		a.contextReserve = reserve
+		return nil
+	}
+}
+
+// contextTrim reports what fitContext removed.
+type contextTrim struct {
+	TokensBefore      int
+	TokensAfter       int
+	TrimmedToolResult int
+	DroppedHistory    int
+}
+
+func (t contextTrim) payload() map[string]any {
+	return map[string]any{
+		"tokens_before":        t.TokensBefore,
+		"tokens_after":         t.TokensAfter,
+		"trimmed_tool_results": t.TrimmedToolResult,
+		"dropped_messages":     t.DroppedHistory,
+	}
+}
+
+// fitContext trims messages to the context limit. input is the index of
+// the run input in messages: history before it can be dropped, the input
+// and what follows it cannot. It reports false when nothing had to be
+// trimmed; otherwise the input moves back by trim.DroppedHistory.
+func (a *Agent) fitContext(ctx context.Context, log *slog.Logger, runID string, messages []llm.Message, input int, tools []llm.Tool) ([]llm.Message, contextTrim, bool) {
+	if a.contextLimit <= 0 {
+		return messages, contextTrim{}, false
+	}
+	budget := a.contextLimit - a.contextReserve - estimateToolTokens(tools)
+	total := estimateMessagesTokens(messages)
+	if total <= budget {
+		return messages, contextTrim{}, false
+	}
+	trim := contextTrim{TokensBefore: total}
+
+	// Oldest tool results go first; the latest one is what the model is
+	// working on.
+	out := append([]llm.Message(nil), messages...)
+	lastTool := -1
+	for i := len(out) - 1; i >= 0; i-- {
+		if isToolResult(out[i]) {
+			lastTool = i
+			break
+		}
+	}
+	for i := range out {
+		if total <= budget {
+			break
+		}
+		if i == input || i == lastTool || !isToolResult(out[i]) || out[i].Content == trimToolResult(out[i]) {
+			continue
+		}
+		total -= estimateMessageTokens(out[i])
+		out[i].Content = trimToolResult(out[i])
+		total += estimateMessageTokens(out[i])
+		trim.TrimmedToolResult++
+	}
+
+	// Then the conversation history before the run input.
+	if total > budget && input > 0 {
+		var history []llm.Message
+		var err error
+		history, total, err = a.trimHistory(ctx, out[:input], total, budget)
+		if err != nil {
+			log.Warn("agent.context.truncate_error",
+				slog.String("agent_id", a.id),
+				slog.String("run_id", runID),
+				slog.String("error", err.Error()),
+			)
+		}
+		trim.DroppedHistory = input - len(history)
+		out = append(history, out[input:]...)
+	}
+
+	trim.TokensAfter = total
+	log.Info("agent.context.trimmed",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.Int("tokens_before", trim.TokensBefore),
+		slog.Int("tokens_after", trim.TokensAfter),
+		slog.Int("trimmed_tool_results", trim.TrimmedToolResult),
+		slog.Int("dropped_messages", trim.DroppedHistory),
+	)
+	return out, trim, true
+}
+
+// trimHistory shrinks the messages before the run input, keeping system
+// messages, until total fits budget. The conversation memory truncation
+// strategy runs first when there is one; what still does not fit is
+// dropped oldest first. Tool results left at the start of the kept history
+// lost their tool call, so they are dropped too. It returns the kept
+// messages and the new total.
+func (a *Agent) trimHistory(ctx context.Context, history []llm.Message, total, budget int) ([]llm.Message, int, error) {
+	before := estimateMessagesTokens(history)
+	var err error
+	if strategy := a.truncationStrategy(); strategy != nil {
+		var truncated []memory.ConversationMessage
+		truncated, err = strategy.Truncate(ctx, toConversationMessages(history))
+		if err == nil {
+			history = fromConversationMessages(truncated, history)
+		}
+	}
+	total += estimateMessagesTokens(history) - before
+
+	kept := make([]llm.Message, 0, len(history))
+	dropping := true
+	for _, msg := range history {
+		if msg.Role == llm.RoleSystem {
+			kept = append(kept, msg)
+			continue
+		}
+		// A tool result whose call is gone cannot be sent alone.
+		if dropping && (total > budget || msg.Role == llm.RoleTool) {
+			total -= estimateMessageTokens(msg)
+			continue
+		}
+		dropping = false
+		kept = append(kept, msg)
+	}
+	return kept, total, err
+}
+
+// truncationStrategy returns the strategy of the conversation memory, if it
+// exposes one.
+func (a *Agent) truncationStrategy() memory.TruncationStrategy {
+	if tc, ok := a.conversationMemory.(memory.TruncatingConversation); ok {
+		return tc.TruncationStrategy()
+	}
+	return nil
+}
+
+// toConversationMessages converts history for a truncation strategy. Tool
+// calls are not part of ConversationMessage; fromConversationMessages puts
+// them back.
+func toConversationMessages(messages []llm.Message) []memory.ConversationMessage {
+	out := make([]memory.ConversationMessage, 0, len(messages))
+	for _, msg := range messages {
+		out = append(out, memory.ConversationMessage{
+			Role:       string(msg.Role),
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		})
+	}
+	return out
+}
+
+func fromConversationMessages(messages []memory.ConversationMessage, original []llm.Message) []llm.Message {
+	out := make([]llm.Message, 0, len(messages))
+	next := 0
+	for _, cm := range messages {
+		msg := llm.Message{Role: llm.Role(cm.Role), Content: cm.Content, ToolCallID: cm.ToolCallID}
+		// Strategies keep messages in order, so the surviving originals
+		// are found moving forward.
+		for j := next; j < len(original); j++ {
+			if original[j].Role == msg.Role && original[j].Content == msg.Content && original[j].ToolCallID == msg.ToolCallID {
+				msg.ToolCalls = original[j].ToolCalls
+				next = j + 1
+				break
+			}
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+// estimateMessagesTokens approximates the prompt tokens of messages with
+// the usual four characters per token, plus a small per-message overhead.
+func estimateMessagesTokens(messages []llm.Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += estimateMessageTokens(msg)
+	}
+	return total
+}
+
+func estimateMessageTokens(msg llm.Message) int {
+	chars := len(msg.Content)
+	for _, call := range msg.ToolCalls {
+		chars += len(call.Function.Name) + len(call.Function.Arguments)
+	}
+	return 4 + chars/4
+}
+
+func estimateToolTokens(tools []llm.Tool) int {
+	if len(tools) == 0 {
+		return 0
+	}
+	encoded, err := json.Marshal(tools)
+	if err != nil {
+		return 0
+	}
+	return len(encoded) / 4
+}
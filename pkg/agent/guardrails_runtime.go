@@ -42,13 +42,53 @@ func (a *Agent) checkGuardrailsInput(ctx context.Context, log *slog.Logger, runI
 	return WrapGuardrailError(result)
 }
 
-func (a *Agent) applyGuardrailsOutput(ctx context.Context, log *slog.Logger, runID, traceID, spanID, output string) string {
+// guardrailBlocked records a run stopped by a guardrail, or by the
+// response format check that runs with the output guardrails, and returns
+// err.
+func (a *Agent) guardrailBlocked(ctx context.Context, err error) error {
+	agentErrorCounter.Add(ctx, 1)
+	if task, ok := core.TaskFromContext(ctx); ok && task != nil {
+		task.Fail(err.Error())
+	}
+	return err
+}
+
+func (a *Agent) applyGuardrailsOutput(ctx context.Context, log *slog.Logger, runID, traceID, spanID, output string) (string, error) {
 	if a.guardrails == nil {
-		return output
+		return output, nil
 	}
 	result := a.guardrails.FilterOutput(ctx, output)
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(telemetry.GuardrailOutputAttributes(result.Modified, len(result.Redactions))...)
+	if result.Blocked {
+		if log != nil {
+			log.Warn("agent.guardrails.output_blocked",
+				slog.String("agent_id", a.id),
+				slog.String("run_id", runID),
+				slog.String("trace_id", traceID),
+				slog.String("span_id", spanID),
+				slog.String("guardrail", result.GuardrailID),
+				slog.String("reason", result.Reason),
+			)
+		}
+		a.emitEvent(ctx, core.EventAgentError, map[string]any{
+			"run_id":    runID,
+			"stage":     "guardrails.output",
+			"guardrail": result.GuardrailID,
+			"reason":    result.Reason,
+		})
+		return "", WrapGuardrailOutputError(result)
+	}
+	if len(result.Flags) > 0 && log != nil {
+		log.Warn("agent.guardrails.output_flagged",
+			slog.String("agent_id", a.id),
+			slog.String("run_id", runID),
+			slog.String("trace_id", traceID),
+			slog.String("span_id", spanID),
+			slog.String("category", string(result.Flags[0].Category)),
+			slog.Float64("score", result.Flags[0].Score),
+		)
+	}
 	if result.Modified && log != nil {
 		log.Info("agent.guardrails.output_filtered",
 			slog.String("agent_id", a.id),
@@ -58,7 +98,7 @@ func (a *Agent) applyGuardrailsOutput(ctx context.Context, log *slog.Logger, run
 			slog.Int("redactions", len(result.Redactions)),
 		)
 	}
-	return result.Content
+	return result.Content, nil
 }
 
 func (a *Agent) guardrailsStats() guardrails.Stats {
@@ -7,6 +7,7 @@ package agent
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jllopis/kairos/pkg/core"
@@ -18,6 +19,7 @@ type AgentHealthChecker struct {
 	lastCheck   time.Time
 	lastResult  core.HealthResult
 	minInterval time.Duration
+	pingTimeout time.Duration
 	mu          sync.RWMutex
 }
 
@@ -26,6 +28,7 @@ func NewAgentHealthChecker(agent *Agent) *AgentHealthChecker {
 	return &AgentHealthChecker{
 		agent:       agent,
 		minInterval: 5 * time.Second,
+		pingTimeout: 2 * time.Second,
 	}
 }
 
@@ -61,16 +64,28 @@ func (h *AgentHealthChecker) Check(ctx context.Context) core.HealthResult {
 		return result
 	}
 
-	// Check MCP clients health
-	mcpHealthy := true
-	for _, client := range h.agent.mcpClients {
+	// Ping the MCP clients concurrently, each with a short timeout, so one
+	// slow server does not hold up the check.
+	var wg sync.WaitGroup
+	var unhealthy atomic.Bool
+	for _, client := range h.agent.currentMCPClients() {
 		if client == nil {
-			mcpHealthy = false
-			break
+			unhealthy.Store(true)
+			continue
 		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, h.pingTimeout)
+			defer cancel()
+			if client.Health(pingCtx).Status != core.HealthHealthy {
+				unhealthy.Store(true)
+			}
+		}()
 	}
+	wg.Wait()
 
-	if !mcpHealthy {
+	if unhealthy.Load() {
 		result.Status = core.HealthDegraded
 		result.Message = "some MCP clients unavailable"
 	} else {
@@ -0,0 +1,112 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/core"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// MaxIterationsPolicy decides how a run ends when the ReAct loop reaches
+// the maximum number of iterations without a final answer.
+type MaxIterationsPolicy int
+
+const (
+	// MaxIterationsError fails the run with a CodeInternal error. It is the
+	// default.
+	MaxIterationsError MaxIterationsPolicy = iota
+	// MaxIterationsReturnPartial returns the last non-empty assistant
+	// content as the result, which may be empty.
+	MaxIterationsReturnPartial
+	// MaxIterationsForceFinalAnswer makes one last LLM call, without tools,
+	// asking the model to answer with what it has gathered so far.
+	MaxIterationsForceFinalAnswer
+)
+
+// String returns the policy name used in logs and events.
+func (p MaxIterationsPolicy) String() string {
+	switch p {
+	case MaxIterationsError:
+		return "error"
+	case MaxIterationsReturnPartial:
+		return "return_partial"
+	case MaxIterationsForceFinalAnswer:
+		return "force_final_answer"
+	default:
+		return fmt.Sprintf("MaxIterationsPolicy(%d)", int(p))
+	}
+}
+
+// forceFinalAnswerPrompt is sent by MaxIterationsForceFinalAnswer.
+const forceFinalAnswerPrompt = "You have reached the maximum number of steps. " +
+	"Do not call any more tools. Answer now with what you have so far, " +
+	"starting with \"Final Answer:\"."
+
+// WithMaxIterationsPolicy sets what happens when a run reaches the maximum
+// number of iterations (see WithMaxIterations).
+func WithMaxIterationsPolicy(policy MaxIterationsPolicy) Option {
+	return func(a *Agent) error {
+		switch policy {
+		case MaxIterationsError, MaxIterationsReturnPartial, MaxIterationsForceFinalAnswer:
+			a.maxIterationsPolicy = policy
+			return nil
+		default:
+			return fmt.Errorf("unknown max iterations policy %d", int(policy))
+		}
+	}
+}
+
+// forceFinalAnswer asks the model for an answer without tools after the
+// loop ran out of iterations. The usage of the call is added to usage.
+func (a *Agent) forceFinalAnswer(ctx context.Context, log *slog.Logger, runID, traceID, spanID string, messages []llm.Message, usage *llm.Usage) (string, error) {
+	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: forceFinalAnswerPrompt})
+	a.emitEvent(ctx, core.EventAgentThinking, map[string]any{
+		"iteration":             a.maxIterations + 1,
+		"max_iterations_policy": a.maxIterationsPolicy.String(),
+	})
+	llmStart := time.Now()
+	resp, err := a.llm.Chat(ctx, llm.ChatRequest{
+		Model:          a.model,
+		Messages:       messages,
+		ResponseFormat: a.nativeResponseFormat(false),
+	})
+	llmLatencyMs.Record(ctx, time.Since(llmStart).Seconds()*1000)
+	if resp != nil {
+		addUsage(usage, resp.Usage)
+		a.setLastRunUsage(ctx, *usage)
+	}
+	if err != nil {
+		agentErrorCounter.Add(ctx, 1)
+		ke := WrapLLMError(err, a.model)
+		if em := GetErrorMetrics(); em != nil {
+			em.RecordError(ctx, ke, "agent-llm")
+		}
+		log.Error("agent.llm.error",
+			slog.String("agent_id", a.id),
+			slog.String("run_id", runID),
+			slog.String("trace_id", traceID),
+			slog.String("span_id", spanID),
+			slog.String("error", err.Error()),
+			slog.String("error_code", string(kerrors.CodeLLMError)),
+		)
+		a.emitEvent(ctx, core.EventAgentError, map[string]any{
+			"run_id": runID,
+			"stage":  "llm",
+			"error":  err.Error(),
+		})
+		return "", ke
+	}
+	content := resp.Content
+	if _, answer, ok := strings.Cut(content, "Final Answer:"); ok {
+		content = answer
+	}
+	return strings.TrimSpace(content), nil
+}
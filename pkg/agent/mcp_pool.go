@@ -0,0 +1,124 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	kmcp "github.com/jllopis/kairos/pkg/mcp"
+	"github.com/jllopis/kairos/pkg/mcp/pool"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithMCPPool uses connections from a shared MCP pool for the named servers,
+// or for every server registered in p when none are named. The agent does
+// not hold connections: it leases one with pool.Get for each tool listing or
+// tool call and releases it when the call returns, so many agents share the
+// same connections.
+//
+// A server is served either by the pool or by WithMCPServerConfigs, not
+// both: New fails if a named server also appears in the config servers, and
+// when no servers are named the config servers take precedence.
+func WithMCPPool(p *pool.Pool, servers ...string) Option {
+	return func(a *Agent) error {
+		if p == nil {
+			return errors.New("mcp pool cannot be nil")
+		}
+		a.mcpPool = p
+		a.mcpPoolServers = append([]string(nil), servers...)
+		return nil
+	}
+}
+
+// validateMCPPool checks the servers named with WithMCPPool against the
+// pool registry and the config servers.
+func (a *Agent) validateMCPPool() error {
+	if a.mcpPool == nil {
+		return nil
+	}
+	registered := a.mcpPool.ListServers()
+	for _, server := range a.mcpPoolServers {
+		if !slices.Contains(registered, server) {
+			return fmt.Errorf("mcp pool server %q: %w", server, pool.ErrServerNotFound)
+		}
+		if _, ok := a.mcpServers[server]; ok {
+			return fmt.Errorf("mcp server %q configured both in the pool and in WithMCPServerConfigs", server)
+		}
+	}
+	return nil
+}
+
+// pooledMCPServers returns the pool and the servers the agent uses from it.
+func (a *Agent) pooledMCPServers() (*pool.Pool, []string) {
+	a.mcpMu.RLock()
+	defer a.mcpMu.RUnlock()
+	if a.mcpPool == nil {
+		return nil, nil
+	}
+	if len(a.mcpPoolServers) > 0 {
+		return a.mcpPool, append([]string(nil), a.mcpPoolServers...)
+	}
+	var servers []string
+	for _, server := range a.mcpPool.ListServers() {
+		if _, ok := a.mcpServers[server]; !ok {
+			servers = append(servers, server)
+		}
+	}
+	return a.mcpPool, servers
+}
+
+// pooledMCPCaller calls tools on a pool server, leasing a connection for
+// each call.
+type pooledMCPCaller struct {
+	pool   *pool.Pool
+	server string
+}
+
+// CallTool implements kmcp.ToolCaller.
+func (c pooledMCPCaller) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+	client, err := c.pool.Get(ctx, c.server)
+	if err != nil {
+		return nil, fmt.Errorf("mcp pool server %q: %w", c.server, err)
+	}
+	defer c.pool.Release(c.server, client)
+	return client.CallTool(ctx, name, args)
+}
+
+// listTools lists the server tools on a leased connection.
+func (c pooledMCPCaller) listTools(ctx context.Context) ([]mcpgo.Tool, error) {
+	client, err := c.pool.Get(ctx, c.server)
+	if err != nil {
+		return nil, fmt.Errorf("mcp pool server %q: %w", c.server, err)
+	}
+	defer c.pool.Release(c.server, client)
+	return client.ListTools(ctx)
+}
+
+// pooledMCPTool is a tool discovered on a pool server.
+type pooledMCPTool struct {
+	tool   mcpgo.Tool
+	caller kmcp.ToolCaller
+}
+
+// listPooledMCPTools lists the tools of every pool server the agent uses.
+// Servers that fail are reported through onError and skipped.
+func (a *Agent) listPooledMCPTools(ctx context.Context, onError func(server string, err error)) []pooledMCPTool {
+	p, servers := a.pooledMCPServers()
+	var out []pooledMCPTool
+	for _, server := range servers {
+		caller := pooledMCPCaller{pool: p, server: server}
+		list, err := caller.listTools(ctx)
+		if err != nil {
+			onError(server, err)
+			continue
+		}
+		for _, tool := range list {
+			out = append(out, pooledMCPTool{tool: tool, caller: caller})
+		}
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/config"
+	"github.com/jllopis/kairos/pkg/governance"
+	kmcp "github.com/jllopis/kairos/pkg/mcp"
+)
+
+// mcpServer is an MCP client connected from a named config entry.
+type mcpServer struct {
+	config config.MCPServerConfig
+	client *kmcp.Client
+}
+
+// ReloadMCPServers reconciles the MCP servers connected from config with
+// servers: entries that disappeared are disconnected, new entries are
+// connected and entries whose definition changed are reconnected. Servers
+// whose definition is unchanged keep their connection, and clients added
+// with WithMCPClients are never touched.
+//
+// A server that fails to connect is reported in the returned error; if it
+// replaced an existing definition, the previous connection is kept.
+//
+// ReloadMCPServers is safe to call while the agent is running, typically
+// from a config.Watch callback. Reloads are serialized, and servers are
+// connected before the agent's MCP lock is taken, so runs starting during a
+// reload are not held up by slow servers. Runs already in flight keep the
+// toolset they resolved when they started, so a tool call to a server
+// removed mid-run fails; the next run sees the new servers.
+func (a *Agent) ReloadMCPServers(servers map[string]config.MCPServerConfig) error {
+	a.mcpReloadMu.Lock()
+	defer a.mcpReloadMu.Unlock()
+
+	a.mcpMu.RLock()
+	current := make(map[string]*mcpServer, len(a.mcpServers))
+	for name, server := range a.mcpServers {
+		current[name] = server
+	}
+	a.mcpMu.RUnlock()
+
+	// Connecting can take a while; runs keep using the current servers
+	// until the new ones are swapped in.
+	log := a.logger()
+	var errs []error
+	connected := make(map[string]*mcpServer)
+	for _, name := range sortedServerNames(servers) {
+		server := servers[name]
+		if existing, ok := current[name]; ok && reflect.DeepEqual(existing.config, server) {
+			continue
+		}
+		client, err := newMCPClient(name, server, a.policyEngine)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		connected[name] = &mcpServer{config: server, client: client}
+	}
+
+	var retired []*kmcp.Client
+	a.mcpMu.Lock()
+	for _, name := range sortedServerNames(a.mcpServers) {
+		if _, ok := servers[name]; !ok {
+			retired = append(retired, a.mcpServers[name].client)
+			delete(a.mcpServers, name)
+			log.Info("agent.mcp.server.removed", slog.String("agent_id", a.id), slog.String("server", name))
+		}
+	}
+	for _, name := range sortedServerNames(connected) {
+		existing, exists := a.mcpServers[name]
+		if exists {
+			retired = append(retired, existing.client)
+		}
+		a.setMCPServer(name, connected[name])
+		log.Info("agent.mcp.server.connected",
+			slog.String("agent_id", a.id),
+			slog.String("server", name),
+			slog.Bool("replaced", exists),
+		)
+	}
+	a.mcpMu.Unlock()
+
+	for _, client := range retired {
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing mcp client: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MCPServerNames returns the names of the MCP servers connected from
+// config, sorted.
+func (a *Agent) MCPServerNames() []string {
+	a.mcpMu.RLock()
+	defer a.mcpMu.RUnlock()
+	return sortedServerNames(a.mcpServers)
+}
+
+// setMCPServer records server under name. Callers hold mcpMu or own a.
+func (a *Agent) setMCPServer(name string, server *mcpServer) {
+	if a.mcpServers == nil {
+		a.mcpServers = make(map[string]*mcpServer)
+	}
+	a.mcpServers[name] = server
+}
+
+// currentMCPClients returns a snapshot of every MCP client.
+func (a *Agent) currentMCPClients() []*kmcp.Client {
+	a.mcpMu.RLock()
+	defer a.mcpMu.RUnlock()
+	return a.mcpClientsLocked()
+}
+
+// mcpClientsLocked lists explicit clients first, then config servers by
+// name. Callers hold mcpMu.
+func (a *Agent) mcpClientsLocked() []*kmcp.Client {
+	clients := make([]*kmcp.Client, 0, len(a.mcpClients)+len(a.mcpServers))
+	clients = append(clients, a.mcpClients...)
+	for _, name := range sortedServerNames(a.mcpServers) {
+		clients = append(clients, a.mcpServers[name].client)
+	}
+	return clients
+}
+
+// newMCPClient connects the MCP server defined by server.
+func newMCPClient(name string, server config.MCPServerConfig, policyEngine governance.PolicyEngine) (*kmcp.Client, error) {
+	transport, ok := config.MCPTransport(server.Transport)
+	if !ok {
+		return nil, fmt.Errorf("mcp server %q has unsupported transport %q", name, server.Transport)
+	}
+
+	opts := mcpClientOptions(server, policyEngine, name)
+	switch transport {
+	case config.MCPTransportStdio:
+		if strings.TrimSpace(server.Command) == "" {
+			return nil, fmt.Errorf("mcp server %q missing command", name)
+		}
+		client, err := kmcp.NewClientWithStdioProtocol(server.Command, server.Args, server.Env, server.ProtocolVersion, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %q: %w", name, err)
+		}
+		return client, nil
+	default:
+		client, err := kmcp.NewClientWithStreamableHTTPProtocol(server.URL, server.ProtocolVersion, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %q: %w", name, err)
+		}
+		return client, nil
+	}
+}
+
+func sortedServerNames[V any](servers map[string]V) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,240 @@
+package agent_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/agent"
+	"github.com/jllopis/kairos/pkg/config"
+	"github.com/jllopis/kairos/pkg/llm"
+	"github.com/jllopis/kairos/pkg/mcp/pool"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+func newTestMCPServer(t *testing.T, tool string) *httptest.Server {
+	t.Helper()
+	server := mcpserver.NewMCPServer("test-"+tool, "1.0.0")
+	server.AddTool(mcpgo.NewTool(tool), func(context.Context, mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		return &mcpgo.CallToolResult{
+			Content: []mcpgo.Content{mcpgo.TextContent{Type: "text", Text: tool}},
+		}, nil
+	})
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func mcpToolNames(t *testing.T, a *agent.Agent) []string {
+	t.Helper()
+	tools, err := a.MCPTools(context.Background())
+	if err != nil {
+		t.Fatalf("MCPTools error: %v", err)
+	}
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestAgent_ReloadMCPServers(t *testing.T) {
+	alpha := newTestMCPServer(t, "alpha")
+	beta := newTestMCPServer(t, "beta")
+	gamma := newTestMCPServer(t, "gamma")
+
+	a, err := agent.New("reload-agent", llm.NewScriptedMockProvider("mock"),
+		agent.WithMCPServerConfigs(map[string]config.MCPServerConfig{
+			"a": {Transport: "http", URL: alpha.URL},
+			"b": {Transport: "http", URL: beta.URL},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer a.Close()
+
+	if got := mcpToolNames(t, a); !slices.Equal(got, []string{"alpha", "beta"}) {
+		t.Fatalf("unexpected initial tools: %v", got)
+	}
+
+	// Drop b, repoint a and add c; a bad server is reported but does not
+	// prevent the rest of the reload.
+	err = a.ReloadMCPServers(map[string]config.MCPServerConfig{
+		"a":   {Transport: "http", URL: gamma.URL},
+		"c":   {Transport: "http", URL: beta.URL},
+		"bad": {Transport: "grpc"},
+	})
+	if err == nil {
+		t.Fatal("expected error for unsupported transport")
+	}
+	if got := a.MCPServerNames(); !slices.Equal(got, []string{"a", "c"}) {
+		t.Fatalf("unexpected servers: %v", got)
+	}
+	if got := mcpToolNames(t, a); !slices.Equal(got, []string{"beta", "gamma"}) {
+		t.Fatalf("unexpected tools after reload: %v", got)
+	}
+
+	if err := a.ReloadMCPServers(nil); err != nil {
+		t.Fatalf("ReloadMCPServers error: %v", err)
+	}
+	if got := a.MCPServerNames(); len(got) != 0 {
+		t.Fatalf("expected no servers, got %v", got)
+	}
+}
+
+func TestAgent_ReloadMCPServersConnectsOutsideLock(t *testing.T) {
+	alpha := newTestMCPServer(t, "alpha")
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		alpha.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer slow.Close()
+
+	a, err := agent.New("reload-agent", llm.NewScriptedMockProvider("mock"),
+		agent.WithMCPServerConfigs(map[string]config.MCPServerConfig{
+			"a": {Transport: "http", URL: alpha.URL},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer a.Close()
+
+	reloaded := make(chan error, 1)
+	go func() {
+		reloaded <- a.ReloadMCPServers(map[string]config.MCPServerConfig{
+			"a":    {Transport: "http", URL: alpha.URL},
+			"slow": {Transport: "http", URL: slow.URL},
+		})
+	}()
+
+	// While the slow server connects, the current servers stay readable.
+	listed := make(chan []string, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		listed <- a.MCPServerNames()
+	}()
+	select {
+	case names := <-listed:
+		if !slices.Equal(names, []string{"a"}) {
+			t.Fatalf("expected the current servers during the reload, got %v", names)
+		}
+	case <-time.After(2 * time.Second):
+		close(release)
+		t.Fatal("MCPServerNames blocked while a server was connecting")
+	}
+
+	close(release)
+	if err := <-reloaded; err != nil {
+		t.Fatalf("ReloadMCPServers error: %v", err)
+	}
+	if got := a.MCPServerNames(); !slices.Equal(got, []string{"a", "slow"}) {
+		t.Fatalf("unexpected servers after reload: %v", got)
+	}
+}
+
+func TestAgent_MCPPoolSharesConnections(t *testing.T) {
+	alpha := newTestMCPServer(t, "alpha")
+	p := pool.New()
+	defer p.Close()
+	if err := p.RegisterHTTP("alpha", alpha.URL); err != nil {
+		t.Fatalf("RegisterHTTP error: %v", err)
+	}
+	// Warm the pool so the baseline has the shared connection open.
+	ctx := context.Background()
+	client, err := p.Get(ctx, "alpha")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	p.Release("alpha", client)
+	baseline := p.Stats()
+
+	owned := newTestMCPServer(t, "beta")
+	for _, id := range []string{"pool-agent-1", "pool-agent-2"} {
+		provider := &batchToolProvider{Calls: []llm.ToolCall{toolCall("call-1", "alpha", `{}`)}}
+		a, err := agent.New(id, provider,
+			agent.WithMCPPool(p),
+			agent.WithMCPServerConfigs(map[string]config.MCPServerConfig{
+				"b": {Transport: "http", URL: owned.URL},
+			}),
+		)
+		if err != nil {
+			t.Fatalf("New error: %v", err)
+		}
+		if got := p.Stats().OutstandingLeases; got != baseline.OutstandingLeases {
+			t.Fatalf("expected the agent to hold no lease while idle, got %d", got)
+		}
+		if got := mcpToolNames(t, a); !slices.Equal(got, []string{"alpha", "beta"}) {
+			t.Fatalf("unexpected tools: %v", got)
+		}
+		if _, err := a.Run(ctx, "call alpha"); err != nil {
+			t.Fatalf("Run error: %v", err)
+		}
+		messages := provider.Requests[1].Messages
+		if last := messages[len(messages)-1]; last.Role != llm.RoleTool || last.Content != "alpha" {
+			t.Fatalf("unexpected tool result: %+v", last)
+		}
+		if got := p.Stats().OutstandingLeases; got != baseline.OutstandingLeases {
+			t.Fatalf("expected leases released after the run, got %d", got)
+		}
+		if err := a.Close(); err != nil {
+			t.Fatalf("Close error: %v", err)
+		}
+	}
+
+	after := p.Stats()
+	if after.ActiveConnections != baseline.ActiveConnections || after.TotalConnections != baseline.TotalConnections {
+		t.Fatalf("expected agents to reuse the pooled connection, baseline %+v, got %+v", baseline, after)
+	}
+	// The shared connection stays usable after the agents close.
+	if _, err := client.ListTools(ctx); err != nil {
+		t.Fatalf("pooled connection was closed: %v", err)
+	}
+}
+
+func TestAgent_MCPPoolConflictsWithServerConfigs(t *testing.T) {
+	alpha := newTestMCPServer(t, "alpha")
+	p := pool.New()
+	defer p.Close()
+	if err := p.RegisterHTTP("alpha", alpha.URL); err != nil {
+		t.Fatalf("RegisterHTTP error: %v", err)
+	}
+	// The configured server records when its session is closed.
+	configured := newTestMCPServer(t, "alpha")
+	var closed atomic.Bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			closed.Store(true)
+		}
+		configured.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	_, err := agent.New("pool-agent", llm.NewScriptedMockProvider("mock"),
+		agent.WithMCPPool(p, "alpha"),
+		agent.WithMCPServerConfigs(map[string]config.MCPServerConfig{
+			"alpha": {Transport: "http", URL: proxy.URL},
+		}),
+	)
+	if err == nil {
+		t.Fatal("expected error for a server both pooled and configured")
+	}
+	if !closed.Load() {
+		t.Fatal("expected the configured server to be closed when New fails")
+	}
+}
+
+func TestAgent_MCPPoolUnknownServer(t *testing.T) {
+	p := pool.New()
+	defer p.Close()
+	if _, err := agent.New("pool-agent", llm.NewScriptedMockProvider("mock"), agent.WithMCPPool(p, "missing")); err == nil {
+		t.Fatal("expected error for unknown pool server")
+	}
+}
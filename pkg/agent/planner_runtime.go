@@ -38,7 +38,7 @@ func (a *Agent) runPlanner(ctx context.Context, input any) (any, error) {
 	ctx, span := a.tracer.Start(ctx, "Agent.Run")
 	defer span.End()
 	traceID, spanID := traceIDs(span)
-	log := slog.Default()
+	log := a.logger()
 
 	inputStr, ok := input.(string)
 	if !ok {
@@ -49,11 +49,7 @@ func (a *Agent) runPlanner(ctx context.Context, input any) (any, error) {
 	span.SetAttributes(telemetry.PlannerAttributes(a.plannerGraph.ID, runID)...)
 
 	if err := a.checkGuardrailsInput(ctx, log, runID, traceID, spanID, inputStr); err != nil {
-		agentErrorCounter.Add(ctx, 1)
-		if task, ok := core.TaskFromContext(ctx); ok && task != nil {
-			task.Fail(err.Error())
-		}
-		return nil, err
+		return nil, a.guardrailBlocked(ctx, err)
 	}
 
 	if task, ok := core.TaskFromContext(ctx); ok && task != nil {
@@ -110,12 +106,10 @@ func (a *Agent) runPlanner(ctx context.Context, input any) (any, error) {
 	memoryRetrieved := 0
 	state := planner.NewState()
 	state.Last = inputStr
-	state.Outputs["input"] = inputStr
-	if mem != nil {
-		if memoryContext := a.loadMemoryContext(ctx, mem, inputStr); memoryContext != "" {
-			state.Outputs["memory"] = memoryContext
-			memoryRetrieved = 1
-		}
+	state.SetOutput("input", inputStr)
+	if memoryContext, matches := a.loadMemoryContext(ctx, mem, inputStr); memoryContext != "" {
+		state.SetOutput("memory", memoryContext)
+		memoryRetrieved = len(matches)
 	}
 	span.SetAttributes(telemetry.MemoryAttributes(mem != nil, fmt.Sprintf("%T", mem), memoryRetrieved, false)...)
 
@@ -159,7 +153,10 @@ func (a *Agent) runPlanner(ctx context.Context, input any) (any, error) {
 
 	output := result.Last
 	outputStr := strings.TrimSpace(fmt.Sprint(output))
-	outputStr = a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, outputStr)
+	outputStr, err = a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, outputStr)
+	if err != nil {
+		return nil, a.guardrailBlocked(ctx, err)
+	}
 	a.storeMemory(ctx, mem, inputStr, outputStr)
 	if a.conversationMemory != nil && hasSession {
 		if err := a.storeConversationMessage(ctx, sessionID, llm.RoleAssistant, outputStr, ""); err != nil {
@@ -296,7 +293,7 @@ func (a *Agent) plannerLLMHandler(log *slog.Logger, runID, traceID, spanID strin
 		if systemPrompt != "" {
 			messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
 		}
-		if memContext, ok := state.Outputs["memory"].(string); ok && strings.TrimSpace(memContext) != "" {
+		if memContext, ok := state.GetString("memory"); ok && strings.TrimSpace(memContext) != "" {
 			messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: memContext})
 		}
 		messages = append(messages, llm.Message{Role: llm.RoleUser, Content: prompt})
@@ -338,7 +335,7 @@ func (a *Agent) plannerLLMHandler(log *slog.Logger, runID, traceID, spanID strin
 }
 
 func (a *Agent) callPlannerTool(ctx context.Context, log *slog.Logger, toolName string, tool core.Tool, input any, toolCallID, runID, traceID, spanID string) (any, error) {
-	decision, ok := a.evaluatePolicy(ctx, log, runID, traceID, spanID, toolName, toolCallID)
+	decision, ok := a.evaluatePolicy(ctx, log, runID, traceID, spanID, toolName, toolCallID, input)
 	if ok && !decision.IsAllowed() {
 		return nil, fmt.Errorf("policy denied: %s", decision.Reason)
 	}
@@ -351,17 +348,19 @@ func (a *Agent) callPlannerTool(ctx context.Context, log *slog.Logger, toolName
 	}
 
 	toolStart := time.Now()
-	toolCtx, toolSpan := a.tracer.Start(ctx, "Agent.Tool.Call")
-	res, err := tool.Call(toolCtx, args)
-	toolDurationMs := time.Since(toolStart).Seconds() * 1000
+	toolCtx, toolSpan := telemetry.StartToolSpan(ctx, toolName)
+	res, err := a.callTool(toolCtx, tool, args)
+	toolDuration := time.Since(toolStart)
+	toolDurationMs := toolDuration.Seconds() * 1000
 	toolSource := a.getToolSource(tool)
 	toolSpan.SetAttributes(telemetry.ToolCallAttributes(toolName, toolCallID, toolSource, toolDurationMs, err == nil)...)
 	toolSpan.SetAttributes(telemetry.ToolCallArgsResult(fmt.Sprint(args), fmt.Sprint(res), 500)...)
-	toolSpan.End()
+	telemetry.EndSpan(toolSpan, err)
 
 	toolLatencyMs.Record(ctx, toolDurationMs, metric.WithAttributes(
 		attribute.String("tool.name", toolName),
 	))
+	toolMetrics.RecordToolCall(ctx, toolName, toolDuration, err)
 
 	if err != nil {
 		ke := WrapToolError(err, toolName, toolCallID)
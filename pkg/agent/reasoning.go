@@ -0,0 +1,79 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// ReasoningStrategy selects how the agent asks the model to use tools.
+type ReasoningStrategy int
+
+const (
+	// ReasoningAuto uses NativeToolCalls when the provider reports tool
+	// calling support (llm.ToolCallingProvider) and ReAct when it reports
+	// none. For providers that do not report it, tools are offered natively
+	// and "Action:" text is parsed as a fallback. It is the default.
+	ReasoningAuto ReasoningStrategy = iota
+	// ReasoningReAct asks the model to answer in the Thought/Action/Action
+	// Input format and parses the text. Tool definitions are not sent.
+	ReasoningReAct
+	// ReasoningNativeToolCalls sends tool definitions and only runs tools
+	// the model requests through tool calls; "Action:" text is not parsed.
+	ReasoningNativeToolCalls
+)
+
+// String returns the strategy name used in logs.
+func (s ReasoningStrategy) String() string {
+	switch s {
+	case ReasoningAuto:
+		return "auto"
+	case ReasoningReAct:
+		return "react"
+	case ReasoningNativeToolCalls:
+		return "native_tool_calls"
+	default:
+		return fmt.Sprintf("ReasoningStrategy(%d)", int(s))
+	}
+}
+
+// WithReasoningStrategy sets how the agent asks the model to use tools. It
+// takes precedence over WithDisableActionFallback.
+func WithReasoningStrategy(strategy ReasoningStrategy) Option {
+	return func(a *Agent) error {
+		switch strategy {
+		case ReasoningAuto, ReasoningReAct, ReasoningNativeToolCalls:
+			a.reasoningStrategy = strategy
+			return nil
+		default:
+			return fmt.Errorf("unknown reasoning strategy %d", int(strategy))
+		}
+	}
+}
+
+// effectiveReasoningStrategy resolves ReasoningAuto against the deprecated
+// action fallback flag and the provider. It returns ReasoningAuto when
+// tools are offered natively with the text fallback.
+func (a *Agent) effectiveReasoningStrategy() ReasoningStrategy {
+	if a.reasoningStrategy != ReasoningAuto {
+		return a.reasoningStrategy
+	}
+	if a.disableActionFallback != nil {
+		// The deprecated flag keeps its meaning: true is native tool calls,
+		// false offers tools natively with the text fallback.
+		if *a.disableActionFallback {
+			return ReasoningNativeToolCalls
+		}
+		return ReasoningAuto
+	}
+	if supported, reported := llm.ToolCallingSupport(a.llm); reported {
+		if supported {
+			return ReasoningNativeToolCalls
+		}
+		return ReasoningReAct
+	}
+	return ReasoningAuto
+}
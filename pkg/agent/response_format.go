@@ -0,0 +1,185 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/llm"
+	kmcp "github.com/jllopis/kairos/pkg/mcp"
+)
+
+// ResponseFormat is the output format requested with WithResponseFormat.
+type ResponseFormat = llm.ResponseFormat
+
+// JSONObject asks for a final answer that is a JSON object.
+var JSONObject = ResponseFormat{Type: llm.ResponseFormatJSONObject}
+
+// JSONSchema asks for a final answer that conforms to schema, given in any
+// form accepted by mcp.NormalizeSchema: a map, raw JSON or an
+// *mcp.SchemaBuilder.
+func JSONSchema(schema any) ResponseFormat {
+	format := ResponseFormat{Type: llm.ResponseFormatJSONSchema, Name: "response"}
+	if normalized, err := kmcp.NormalizeSchema(schema); err == nil {
+		format.Schema = normalized
+	}
+	return format
+}
+
+// WithResponseFormat makes the final answer structured output. Providers
+// that implement llm.ResponseFormatProvider and support the format get it
+// on the request; the model is also instructed to answer with JSON. The
+// answer is then validated and, when it does not comply, the model is asked
+// once to fix it. The run fails with errors.CodeLLMError if it still does
+// not comply. The final answer is the extracted JSON, without surrounding
+// prose or code fences.
+//
+// Native formats are only requested on turns without tools, such as the
+// forced final answer and the repair: some providers (Gemini among them)
+// reject a response format next to tool declarations, and the ReAct text
+// protocol needs free-form replies. Answers given on turns with tools rely
+// on the instruction, the validation and the repair.
+func WithResponseFormat(format ResponseFormat) Option {
+	return func(a *Agent) error {
+		switch format.Type {
+		case llm.ResponseFormatText, "":
+			a.responseFormat = nil
+			return nil
+		case llm.ResponseFormatJSONObject:
+		case llm.ResponseFormatJSONSchema:
+			if format.Schema == nil {
+				return errors.New("json schema response format requires a valid schema")
+			}
+			if format.Name == "" {
+				format.Name = "response"
+			}
+		default:
+			return fmt.Errorf("unknown response format %q", format.Type)
+		}
+		a.responseFormat = &format
+		return nil
+	}
+}
+
+// responseFormatInstruction is added to the system prompt when a response
+// format is set.
+func (a *Agent) responseFormatInstruction() string {
+	if a.responseFormat == nil {
+		return ""
+	}
+	instruction := "Your final answer must be a single valid JSON object, without any other text or Markdown code fences."
+	if a.responseFormat.Type == llm.ResponseFormatJSONSchema {
+		schema, _ := json.Marshal(a.responseFormat.Schema)
+		instruction += " It must conform to this JSON Schema:\n" + string(schema)
+	}
+	return instruction
+}
+
+// nativeResponseFormat returns the format to set on a request without
+// tools, or nil when the provider cannot enforce it or tools use the ReAct
+// text protocol.
+func (a *Agent) nativeResponseFormat(textTools bool) *llm.ResponseFormat {
+	if a.responseFormat == nil || textTools {
+		return nil
+	}
+	p, ok := a.llm.(llm.ResponseFormatProvider)
+	if !ok || !p.SupportsResponseFormat(a.responseFormat.Type) {
+		return nil
+	}
+	return a.responseFormat
+}
+
+// checkResponseFormat returns the JSON in output, or why it does not match
+// the response format.
+func (a *Agent) checkResponseFormat(output string) (string, error) {
+	raw, err := llm.ExtractJSON(output)
+	if err != nil {
+		return "", err
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", err
+	}
+	if _, ok := value.(map[string]any); !ok {
+		return "", errors.New("the answer is not a JSON object")
+	}
+	if a.responseFormat.Type == llm.ResponseFormatJSONSchema {
+		if violations := kmcp.ValidateSchema(a.responseFormat.Schema, value); len(violations) > 0 {
+			problems := make([]string, 0, len(violations))
+			for _, v := range violations {
+				problems = append(problems, v.String())
+			}
+			return "", fmt.Errorf("the answer does not match the schema: %s", strings.Join(problems, "; "))
+		}
+	}
+	return string(raw), nil
+}
+
+// enforceResponseFormat validates a final answer against the response
+// format and asks the model once to repair it. messages is the
+// conversation that produced output. The usage of the repair call is added
+// to usage.
+func (a *Agent) enforceResponseFormat(ctx context.Context, log *slog.Logger, runID string, messages []llm.Message, usage *llm.Usage, output string) (string, error) {
+	if a.responseFormat == nil {
+		return output, nil
+	}
+	checked, problem := a.checkResponseFormat(output)
+	if problem == nil {
+		return checked, nil
+	}
+	log.Warn("agent.response_format.repair",
+		slog.String("agent_id", a.id),
+		slog.String("run_id", runID),
+		slog.String("format", string(a.responseFormat.Type)),
+		slog.String("error", problem.Error()),
+	)
+
+	repair := make([]llm.Message, 0, len(messages)+2)
+	repair = append(repair, messages...)
+	if last := len(repair) - 1; last < 0 || repair[last].Role != llm.RoleAssistant {
+		repair = append(repair, llm.Message{Role: llm.RoleAssistant, Content: output})
+	}
+	repair = append(repair, llm.Message{
+		Role:    llm.RoleUser,
+		Content: fmt.Sprintf("Your previous answer is not valid: %s. Reply again with only the corrected JSON.", problem),
+	})
+	llmStart := time.Now()
+	resp, err := a.llm.Chat(ctx, llm.ChatRequest{
+		Model:          a.model,
+		Messages:       repair,
+		ResponseFormat: a.nativeResponseFormat(false),
+	})
+	llmLatencyMs.Record(ctx, time.Since(llmStart).Seconds()*1000)
+	if resp != nil {
+		addUsage(usage, resp.Usage)
+		a.setLastRunUsage(ctx, *usage)
+	}
+	if err != nil {
+		return "", WrapLLMError(err, a.model)
+	}
+	checked, problem = a.checkResponseFormat(resp.Content)
+	if problem != nil {
+		return "", kerrors.New(kerrors.CodeLLMError, "response does not match the requested format", problem).
+			WithContext("format", string(a.responseFormat.Type)).
+			WithRecoverable(false)
+	}
+	return checked, nil
+}
+
+// finishOutput enforces the response format on a final answer and applies
+// the output guardrails.
+func (a *Agent) finishOutput(ctx context.Context, log *slog.Logger, runID, traceID, spanID string, messages []llm.Message, usage *llm.Usage, output string) (string, error) {
+	output, err := a.enforceResponseFormat(ctx, log, runID, messages, usage, output)
+	if err != nil {
+		return "", err
+	}
+	return a.applyGuardrailsOutput(ctx, log, runID, traceID, spanID, output)
+}
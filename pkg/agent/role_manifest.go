@@ -0,0 +1,82 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/core"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadRoleManifest reads and validates a role manifest from a YAML or JSON
+// file.
+func LoadRoleManifest(path string) (core.RoleManifest, error) {
+	if strings.TrimSpace(path) == "" {
+		return core.RoleManifest{}, errors.New("role manifest path is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return core.RoleManifest{}, err
+	}
+	manifest, err := ParseRoleManifest(data)
+	if err != nil {
+		return core.RoleManifest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// ParseRoleManifest decodes and validates a role manifest. YAML and JSON are
+// accepted; unknown fields are rejected.
+func ParseRoleManifest(data []byte) (core.RoleManifest, error) {
+	var manifest core.RoleManifest
+	if len(bytes.TrimSpace(data)) == 0 {
+		return manifest, errors.New("empty role manifest")
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return core.RoleManifest{}, fmt.Errorf("parse role manifest: %w", err)
+	}
+	if err := ValidateRoleManifest(manifest); err != nil {
+		return core.RoleManifest{}, err
+	}
+	return manifest, nil
+}
+
+// ValidateRoleManifest checks that the manifest has a role, that allowed
+// tools are unique and non-empty, and that examples are complete. All
+// problems are reported together.
+func ValidateRoleManifest(manifest core.RoleManifest) error {
+	var errs []error
+	if strings.TrimSpace(manifest.Role) == "" {
+		errs = append(errs, errors.New("role is required"))
+	}
+	seen := make(map[string]bool, len(manifest.AllowedTools))
+	for i, tool := range manifest.AllowedTools {
+		switch {
+		case strings.TrimSpace(tool) == "":
+			errs = append(errs, fmt.Errorf("allowed_tools[%d] is empty", i))
+		case seen[tool]:
+			errs = append(errs, fmt.Errorf("allowed_tools[%d]: duplicate tool %q", i, tool))
+		}
+		seen[tool] = true
+	}
+	for i, example := range manifest.Examples {
+		if strings.TrimSpace(example.Input) == "" {
+			errs = append(errs, fmt.Errorf("examples[%d].input is required", i))
+		}
+		if strings.TrimSpace(example.Output) == "" {
+			errs = append(errs, fmt.Errorf("examples[%d].output is required", i))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid role manifest: %w", errors.Join(errs...))
+	}
+	return nil
+}
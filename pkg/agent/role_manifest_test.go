@@ -0,0 +1,75 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+func TestLoadRoleManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "role-spreadsheet.yaml")
+	content := `role: Analista de hojas de cálculo
+responsibility: Resume y transforma tablas
+inputs: [csv]
+outputs: [markdown]
+allowed_tools: [read_sheet, write_sheet]
+output_format: markdown
+examples:
+  - input: Suma la columna B
+    output: "Total: 42"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	manifest, err := LoadRoleManifest(path)
+	if err != nil {
+		t.Fatalf("LoadRoleManifest error: %v", err)
+	}
+	if manifest.Role != "Analista de hojas de cálculo" || manifest.OutputFormat != "markdown" {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if len(manifest.AllowedTools) != 2 || manifest.AllowedTools[1] != "write_sheet" {
+		t.Fatalf("unexpected allowed tools: %v", manifest.AllowedTools)
+	}
+	if len(manifest.Examples) != 1 || manifest.Examples[0].Output != "Total: 42" {
+		t.Fatalf("unexpected examples: %v", manifest.Examples)
+	}
+
+	a, err := New("sheets", &llm.MockProvider{Response: "ok"}, WithRoleManifest(manifest))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if a.RoleManifest().Role != manifest.Role {
+		t.Fatalf("expected manifest to be attached")
+	}
+}
+
+func TestParseRoleManifest_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"empty", "", "empty role manifest"},
+		{"unknown field", "role: x\nallowed_tool: [a]\n", "field allowed_tool not found"},
+		{"missing role", "responsibility: x\n", "role is required"},
+		{"duplicate tool", "role: x\nallowed_tools: [a, a]\n", "duplicate tool \"a\""},
+		{"incomplete example", "role: x\nexamples:\n  - input: hi\n", "examples[0].output is required"},
+		{"json", `{"role": "x", "unknown": true}`, "field unknown not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRoleManifest([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
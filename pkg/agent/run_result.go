@@ -0,0 +1,125 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/core"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// RunResult is the outcome of a run, as returned by RunDetailed.
+type RunResult struct {
+	// Output is the final answer, the same value Run returns.
+	Output any
+	// Usage is the tokens consumed by the run, as reported by the provider.
+	Usage llm.Usage
+	// ToolCalls lists the tool calls executed, in order. Calls denied by
+	// policy or approval, or naming an unknown tool, are not included.
+	ToolCalls []ToolCallRecord
+	// Iterations is the number of ReAct iterations started. It is 0 for
+	// runs driven by a planner graph.
+	Iterations int
+	// Events holds the events emitted by the agent during the run, whether
+	// or not an emitter or listener is configured.
+	Events []core.Event
+}
+
+// ToolCallRecord describes a tool call executed during a run.
+type ToolCallRecord struct {
+	Name string
+	// ID is the provider tool call id; it is empty for calls made through
+	// the ReAct text protocol.
+	ID        string
+	Arguments string
+	Result    any
+	Err       error
+	Duration  time.Duration
+}
+
+// RunDetailed executes the agent like Run and reports what the run did.
+// On error the result is still returned, so the usage and tool calls of
+// failed runs can be accounted for; Output then holds whatever Run returns
+// along with the error, such as the partial answer of a run that exceeded
+// its token budget.
+func (a *Agent) RunDetailed(ctx context.Context, input any) (*RunResult, error) {
+	rec := &runRecorder{agent: a}
+	ctx = context.WithValue(ctx, runRecorderKey{}, rec)
+	if a.hasEventSinks() {
+		ctx = core.WithEventEmitter(ctx, core.EventEmitterFunc(a.dispatchEvent))
+	}
+
+	var output any
+	var err error
+	if a.plannerGraph != nil {
+		output, err = a.runPlanner(ctx, input)
+	} else {
+		output, err = a.runEmergent(ctx, input)
+	}
+	result := rec.result()
+	result.Output = output
+	return result, err
+}
+
+// runRecorder collects the RunResult of a run. It only records for the
+// agent that started the run, so sub-agents sharing the context do not
+// overwrite the usage or iterations of their parent.
+type runRecorder struct {
+	agent *Agent
+
+	mu         sync.Mutex
+	usage      llm.Usage
+	toolCalls  []ToolCallRecord
+	iterations int
+	events     []core.Event
+}
+
+type runRecorderKey struct{}
+
+// recorderFor returns the recorder of the run of a in ctx, or nil.
+func recorderFor(ctx context.Context, a *Agent) *runRecorder {
+	rec, _ := ctx.Value(runRecorderKey{}).(*runRecorder)
+	if rec == nil || rec.agent != a {
+		return nil
+	}
+	return rec
+}
+
+func (r *runRecorder) result() *RunResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &RunResult{
+		Usage:      r.usage,
+		ToolCalls:  append([]ToolCallRecord(nil), r.toolCalls...),
+		Iterations: r.iterations,
+		Events:     append([]core.Event(nil), r.events...),
+	}
+}
+
+func (a *Agent) recordIteration(ctx context.Context, iteration int) {
+	if rec := recorderFor(ctx, a); rec != nil {
+		rec.mu.Lock()
+		rec.iterations = iteration
+		rec.mu.Unlock()
+	}
+}
+
+func (a *Agent) recordToolCall(ctx context.Context, call ToolCallRecord) {
+	if rec := recorderFor(ctx, a); rec != nil {
+		rec.mu.Lock()
+		rec.toolCalls = append(rec.toolCalls, call)
+		rec.mu.Unlock()
+	}
+}
+
+func (a *Agent) recordEvent(ctx context.Context, event core.Event) {
+	if rec := recorderFor(ctx, a); rec != nil {
+		rec.mu.Lock()
+		rec.events = append(rec.events, event)
+		rec.mu.Unlock()
+	}
+}
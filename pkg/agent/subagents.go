@@ -0,0 +1,189 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/core"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// DefaultMaxDelegationDepth bounds how many sub-agent hops a run may make.
+const DefaultMaxDelegationDepth = 3
+
+// SubAgentToolPrefix prefixes the name of the tool exposing each sub-agent.
+const SubAgentToolPrefix = "delegate_to_"
+
+var (
+	// ErrDelegationDepthExceeded is returned by a delegation tool when the
+	// run already made the maximum number of sub-agent hops.
+	ErrDelegationDepthExceeded = errors.New("delegation depth exceeded")
+	// ErrDelegationCycle is returned by a delegation tool when the target
+	// agent is already part of the delegation chain.
+	ErrDelegationCycle = errors.New("delegation cycle detected")
+)
+
+var subAgentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// WithSubAgents exposes each agent to the LLM as a tool named
+// "delegate_to_<name>". Calling the tool runs the sub-agent with the given
+// task and returns its output as the observation.
+//
+// Each hop emits an agent.delegation event. Delegation chains are limited by
+// WithMaxDelegationDepth, and a hop to an agent already in the chain fails,
+// so agents can safely reference each other.
+func WithSubAgents(agents map[string]*Agent) Option {
+	return func(a *Agent) error {
+		names := make([]string, 0, len(agents))
+		for name := range agents {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			sub := agents[name]
+			if sub == nil {
+				return fmt.Errorf("sub-agent %q cannot be nil", name)
+			}
+			if !subAgentNamePattern.MatchString(name) {
+				return fmt.Errorf("sub-agent name %q must contain only letters, digits, '_' or '-'", name)
+			}
+			a.tools = append(a.tools, &subAgentTool{parent: a, name: name, agent: sub})
+		}
+		return nil
+	}
+}
+
+// WithMaxDelegationDepth sets how many nested sub-agent hops a run may make
+// (default DefaultMaxDelegationDepth). The limit of the agent where the run
+// started applies to the whole chain.
+func WithMaxDelegationDepth(depth int) Option {
+	return func(a *Agent) error {
+		if depth < 1 {
+			return errors.New("max delegation depth must be >= 1")
+		}
+		a.maxDelegationDepth = depth
+		return nil
+	}
+}
+
+// delegationChain records the agents involved in a delegation.
+type delegationChain struct {
+	agents   []string
+	maxDepth int
+}
+
+type delegationKey struct{}
+
+func delegationFromContext(ctx context.Context) (delegationChain, bool) {
+	chain, ok := ctx.Value(delegationKey{}).(delegationChain)
+	return chain, ok
+}
+
+// subAgentTool runs a sub-agent as a tool.
+type subAgentTool struct {
+	parent *Agent
+	name   string
+	agent  *Agent
+}
+
+func (t *subAgentTool) Name() string {
+	return SubAgentToolPrefix + t.name
+}
+
+func (t *subAgentTool) ToolDefinition() llm.Tool {
+	description := fmt.Sprintf("Delegate a task to the %q agent and return its answer.", t.name)
+	if role := strings.TrimSpace(t.agent.Role()); role != "" {
+		description += " Role: " + role + "."
+	}
+	return llm.Tool{
+		Type: llm.ToolTypeFunction,
+		Function: llm.FunctionDef{
+			Name:        t.Name(),
+			Description: description,
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"task": map[string]any{
+						"type":        "string",
+						"description": "Self-contained description of the task for the agent",
+					},
+				},
+				"required": []string{"task"},
+			},
+		},
+	}
+}
+
+func (t *subAgentTool) Call(ctx context.Context, input any) (any, error) {
+	task, err := subAgentTask(input)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, ok := delegationFromContext(ctx)
+	if !ok {
+		chain = delegationChain{agents: []string{t.parent.id}, maxDepth: t.parent.delegationDepth()}
+	}
+	depth := len(chain.agents)
+	if depth > chain.maxDepth {
+		return nil, fmt.Errorf("%w: %s -> %s (max %d)", ErrDelegationDepthExceeded, strings.Join(chain.agents, " -> "), t.agent.id, chain.maxDepth)
+	}
+	if slices.Contains(chain.agents, t.agent.id) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrDelegationCycle, strings.Join(chain.agents, " -> "), t.agent.id)
+	}
+
+	t.parent.emitEvent(ctx, core.EventAgentDelegation, map[string]any{
+		"target":       t.name,
+		"target_agent": t.agent.id,
+		"depth":        depth,
+		"task":         summarizeText(task),
+	})
+
+	next := delegationChain{
+		agents:   append(slices.Clone(chain.agents), t.agent.id),
+		maxDepth: chain.maxDepth,
+	}
+	subCtx := context.WithValue(ctx, delegationKey{}, next)
+	// The sub-agent works on its own task; it must not complete the
+	// caller's.
+	subCtx = core.WithTask(subCtx, nil)
+	return t.agent.Run(subCtx, task)
+}
+
+func (a *Agent) delegationDepth() int {
+	if a.maxDelegationDepth > 0 {
+		return a.maxDelegationDepth
+	}
+	return DefaultMaxDelegationDepth
+}
+
+// subAgentTask extracts the task from the tool input, which is either the
+// parsed arguments or their raw string.
+func subAgentTask(input any) (string, error) {
+	var task string
+	switch v := input.(type) {
+	case map[string]any:
+		task, _ = v["task"].(string)
+	case string:
+		var args struct {
+			Task string `json:"task"`
+		}
+		if err := json.Unmarshal([]byte(v), &args); err == nil && args.Task != "" {
+			task = args.Task
+		} else {
+			task = v
+		}
+	}
+	if strings.TrimSpace(task) == "" {
+		return "", errors.New("delegation requires a non-empty task")
+	}
+	return task, nil
+}
@@ -0,0 +1,115 @@
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jllopis/kairos/pkg/agent"
+	"github.com/jllopis/kairos/pkg/core"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// lastToolMessage returns the content of the last tool message sent to p.
+func lastToolMessage(p *toolCallProvider) string {
+	for i := len(p.LastReq.Messages) - 1; i >= 0; i-- {
+		if msg := p.LastReq.Messages[i]; msg.Role == llm.RoleTool {
+			return msg.Content
+		}
+	}
+	return ""
+}
+
+func TestAgent_DelegatesToSubAgent(t *testing.T) {
+	researcher, err := agent.New("researcher", &llm.MockProvider{Response: "Final Answer: Madrid"},
+		agent.WithRole("Research assistant"),
+	)
+	if err != nil {
+		t.Fatalf("New researcher: %v", err)
+	}
+	parentLLM := &toolCallProvider{
+		ToolName: "delegate_to_research",
+		ToolArgs: `{"task":"Capital of Spain?"}`,
+		Final:    "Final Answer: It is Madrid",
+	}
+	var events []core.Event
+	orchestrator, err := agent.New("orchestrator", parentLLM,
+		agent.WithSubAgents(map[string]*agent.Agent{"research": researcher}),
+		agent.WithDisableActionFallback(true),
+		agent.WithEventListener(func(e core.Event) { events = append(events, e) }),
+	)
+	if err != nil {
+		t.Fatalf("New orchestrator: %v", err)
+	}
+
+	names := orchestrator.ToolNames()
+	if len(names) != 1 || names[0] != "delegate_to_research" {
+		t.Fatalf("unexpected tools: %v", names)
+	}
+	result, err := orchestrator.Run(context.Background(), "Where is the capital of Spain?")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result != "It is Madrid" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if got := lastToolMessage(parentLLM); got != "Madrid" {
+		t.Fatalf("expected sub-agent output as observation, got %q", got)
+	}
+
+	var delegation *core.Event
+	for i := range events {
+		if events[i].Type == core.EventAgentDelegation {
+			delegation = &events[i]
+		}
+	}
+	if delegation == nil {
+		t.Fatal("expected delegation event")
+	}
+	if delegation.Payload["target_agent"] != "researcher" || delegation.Payload["depth"] != 1 {
+		t.Fatalf("unexpected delegation payload: %v", delegation.Payload)
+	}
+}
+
+func TestAgent_DelegationDepthLimit(t *testing.T) {
+	leaf, err := agent.New("leaf", &llm.MockProvider{Response: "Final Answer: leaf"})
+	if err != nil {
+		t.Fatalf("New leaf: %v", err)
+	}
+	middleLLM := &toolCallProvider{ToolName: "delegate_to_leaf", ToolArgs: `{"task":"go deeper"}`}
+	middle, err := agent.New("middle", middleLLM,
+		agent.WithSubAgents(map[string]*agent.Agent{"leaf": leaf}),
+		agent.WithDisableActionFallback(true),
+	)
+	if err != nil {
+		t.Fatalf("New middle: %v", err)
+	}
+	rootLLM := &toolCallProvider{ToolName: "delegate_to_middle", ToolArgs: `{"task":"go deep"}`}
+	root, err := agent.New("root", rootLLM,
+		agent.WithSubAgents(map[string]*agent.Agent{"middle": middle}),
+		agent.WithMaxDelegationDepth(1),
+		agent.WithDisableActionFallback(true),
+	)
+	if err != nil {
+		t.Fatalf("New root: %v", err)
+	}
+
+	if _, err := root.Run(context.Background(), "start"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := lastToolMessage(middleLLM); !strings.Contains(got, agent.ErrDelegationDepthExceeded.Error()) {
+		t.Fatalf("expected depth error for the second hop, got %q", got)
+	}
+}
+
+func TestWithSubAgents_RejectsInvalidNames(t *testing.T) {
+	sub, err := agent.New("sub", &llm.MockProvider{Response: "ok"})
+	if err != nil {
+		t.Fatalf("New sub: %v", err)
+	}
+	if _, err := agent.New("parent", &llm.MockProvider{Response: "ok"},
+		agent.WithSubAgents(map[string]*agent.Agent{"bad name": sub}),
+	); err == nil {
+		t.Fatal("expected error for invalid sub-agent name")
+	}
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeAfterFiresOnAdvance(t *testing.T) {
+	fake := NewFake()
+	start := fake.Now()
+	ch := fake.After(time.Second)
+
+	fake.Advance(999 * time.Millisecond)
+	select {
+	case <-ch:
+		t.Fatal("fired before the duration elapsed")
+	default:
+	}
+
+	fake.Advance(time.Millisecond)
+	select {
+	case at := <-ch:
+		if want := start.Add(time.Second); !at.Equal(want) {
+			t.Fatalf("fired at %v, want %v", at, want)
+		}
+	default:
+		t.Fatal("did not fire once the duration elapsed")
+	}
+	if got := fake.Since(start); got != time.Second {
+		t.Fatalf("Since = %v, want 1s", got)
+	}
+	if fake.Waiters() != 0 {
+		t.Fatalf("expected no pending waiters, got %d", fake.Waiters())
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	fake := NewFake()
+	done := make(chan struct{})
+	go func() {
+		<-fake.After(time.Minute)
+		close(done)
+	}()
+	fake.BlockUntil(1)
+	fake.Set(fake.Now().Add(time.Hour))
+	<-done
+}
+
+func TestOrReal(t *testing.T) {
+	if _, ok := OrReal(nil).(realClock); !ok {
+		t.Fatal("expected the real clock for nil")
+	}
+	fake := NewFake()
+	if OrReal(fake) != Clock(fake) {
+		t.Fatal("expected the given clock")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env"
@@ -144,15 +145,20 @@ type GuardrailsConfig struct {
 
 // PolicyRuleConfig defines a single policy rule.
 type PolicyRuleConfig struct {
-	ID     string `koanf:"id"`
-	Effect string `koanf:"effect"`
-	Type   string `koanf:"type"`
-	Name   string `koanf:"name"`
-	Reason string `koanf:"reason"`
+	ID        string `koanf:"id"`
+	Effect    string `koanf:"effect"`
+	Type      string `koanf:"type"`
+	Name      string `koanf:"name"`
+	Principal string `koanf:"principal"`
+	Reason    string `koanf:"reason"`
 }
 
 // Global k instance
-var k = koanf.New(".")
+var (
+	// loadMu serializes loads, which share k.
+	loadMu sync.Mutex
+	k      = koanf.New(".")
+)
 
 // Load resolves configuration from defaults, files, and environment variables.
 func Load(path string) (*Config, error) {
@@ -188,7 +194,42 @@ func LoadWithCLI(args []string) (*Config, error) {
 	return loadWithOverrides(path, profile, overrides)
 }
 
+// Provenance maps each effective config key, flattened with dots (e.g.
+// "llm.model" or "mcp.servers.fs.command"), to the layer that set its
+// value: "default", "file:<path>", "profile:<path>", "env:<VAR>" or "cli".
+type Provenance map[string]string
+
+// LoadWithProvenance is LoadWithCLI that also reports which layer each key
+// of the effective config came from.
+func LoadWithProvenance(args []string) (*Config, Provenance, error) {
+	path, profile, overrides, err := parseCLIOverrides(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	prov := Provenance{}
+	cfg, err := load(path, profile, overrides, prov)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, prov, nil
+}
+
 func loadWithOverrides(path, profile string, overrides map[string]any) (*Config, error) {
+	return load(path, profile, overrides, nil)
+}
+
+// load merges, from lowest to highest precedence: defaults, the config
+// file, its profile file, KAIROS_* environment variables and CLI overrides.
+// Maps are merged deeply, so mcp.servers from the base file and the profile
+// combine. The profile defaults to KAIROS_PROFILE. When prov is not nil it
+// is filled with the layer of each key.
+func load(path, profile string, overrides map[string]any, prov Provenance) (*Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	// Start from a clean state so keys removed from a file do not survive a
+	// reload.
+	k = koanf.New(".")
+
 	// Defaults
 	k.Set("log.level", "info")
 	k.Set("log.format", "text")
@@ -235,6 +276,7 @@ func loadWithOverrides(path, profile string, overrides map[string]any) (*Config,
 	k.Set("guardrails.pii_mode", "mask")
 	k.Set("guardrails.pii_types", []string{})
 	k.Set("guardrails.fail_open", false)
+	prov.record(k.Keys(), "default")
 
 	// 1. Load from file
 	configPath := path
@@ -242,33 +284,51 @@ func loadWithOverrides(path, profile string, overrides map[string]any) (*Config,
 		configPath = defaultConfigPath()
 	}
 	if configPath != "" {
-		if err := loadFromFile(configPath); err != nil {
+		keys, err := loadFromFile(configPath)
+		if err != nil {
 			return nil, err
 		}
+		prov.record(keys, "file:"+configPath)
 	}
 
 	// 2. Load profile-specific override file (config.dev.yaml, config.prod.yaml, etc.)
+	if profile == "" {
+		profile = strings.TrimSpace(os.Getenv("KAIROS_PROFILE"))
+	}
 	if profile != "" && configPath != "" {
 		profilePath := profileConfigPath(configPath, profile)
 		if profilePath != "" {
-			if err := loadFromFile(profilePath); err != nil {
+			keys, err := loadFromFile(profilePath)
+			if err != nil {
 				return nil, err
 			}
+			prov.record(keys, "profile:"+profilePath)
 		}
 	}
 
 	// 3. Load from ENV (KAIROS_LLM_PROVIDER -> llm.provider)
-	if err := k.Load(env.Provider("KAIROS_", ".", func(s string) string {
+	envLayer := koanf.New(".")
+	if err := envLayer.Load(env.Provider("KAIROS_", ".", func(s string) string {
 		return strings.Replace(strings.ToLower(
 			strings.TrimPrefix(s, "KAIROS_")), "_", ".", -1)
 	}), nil); err != nil {
 		return nil, err
 	}
+	if err := k.Merge(envLayer); err != nil {
+		return nil, err
+	}
+	for _, key := range envLayer.Keys() {
+		prov.record([]string{key}, "env:KAIROS_"+strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+	}
 
+	// 4. CLI overrides
 	if len(overrides) > 0 {
+		cliLayer := koanf.New(".")
 		for key, value := range overrides {
 			_ = k.Set(key, value)
+			_ = cliLayer.Set(key, value)
 		}
+		prov.record(cliLayer.Keys(), "cli")
 	}
 
 	normalizeMCPServers()
@@ -279,10 +339,37 @@ func loadWithOverrides(path, profile string, overrides map[string]any) (*Config,
 	if err := k.Unmarshal("", &cfg); err != nil {
 		return nil, err
 	}
+	if err := Validate(&cfg); err != nil {
+		return nil, err
+	}
+	prov.prune()
 
 	return &cfg, nil
 }
 
+func (p Provenance) record(keys []string, source string) {
+	if p == nil {
+		return
+	}
+	for _, key := range keys {
+		// mcpServers is normalized to mcp.servers after loading.
+		if rest, ok := strings.CutPrefix(key, "mcpServers."); ok {
+			key = "mcp.servers." + rest
+		}
+		p[key] = source
+	}
+}
+
+// prune drops keys that are not part of the effective config, such as
+// leaves replaced by a map from a later layer.
+func (p Provenance) prune() {
+	for key := range p {
+		if !k.Exists(key) {
+			delete(p, key)
+		}
+	}
+}
+
 func parseCLIOverrides(args []string) (string, string, map[string]any, error) {
 	overrides := make(map[string]any)
 	var path, profile string
@@ -401,26 +488,29 @@ func (c *Config) AgentConfigFor(id string) AgentConfig {
 	return cfg
 }
 
-func loadFromFile(path string) error {
+// loadFromFile merges the file at path into k and returns the keys it set.
+// Missing files are ignored.
+func loadFromFile(path string) ([]string, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 	if info.IsDir() {
-		return nil
+		return nil, nil
 	}
 
-	switch strings.ToLower(filepath.Ext(path)) {
-	case ".json":
-		return k.Load(file.Provider(path), yaml.Parser())
-	case ".yaml", ".yml":
-		return k.Load(file.Provider(path), yaml.Parser())
-	default:
-		return k.Load(file.Provider(path), yaml.Parser())
+	// JSON is a subset of YAML, so a single parser handles every format.
+	layer := koanf.New(".")
+	if err := layer.Load(file.Provider(path), yaml.Parser()); err != nil {
+		return nil, err
+	}
+	if err := k.Merge(layer); err != nil {
+		return nil, err
 	}
+	return layer.Keys(), nil
 }
 
 func defaultConfigPath() string {
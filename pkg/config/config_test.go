@@ -303,3 +303,56 @@ func TestProfileConfigPath(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadWithProvenance(t *testing.T) {
+	tmpDir := t.TempDir()
+	basePath := filepath.Join(tmpDir, "settings.json")
+	base := `{
+  "llm": {"provider": "ollama", "model": "base-model"},
+  "mcp": {"servers": {"fs": {"command": "mcp-fs"}}}
+}`
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatalf("failed to write base config: %v", err)
+	}
+	prodPath := filepath.Join(tmpDir, "settings.prod.json")
+	prod := `{
+  "llm": {"model": "prod-model"},
+  "mcp": {"servers": {"search": {"transport": "http", "url": "http://search/mcp"}}}
+}`
+	if err := os.WriteFile(prodPath, []byte(prod), 0644); err != nil {
+		t.Fatalf("failed to write prod config: %v", err)
+	}
+	t.Setenv("KAIROS_PROFILE", "prod")
+
+	cfg, prov, err := LoadWithProvenance([]string{
+		"--config", basePath,
+		"--set", "llm.provider=openai",
+	})
+	if err != nil {
+		t.Fatalf("LoadWithProvenance failed: %v", err)
+	}
+
+	if cfg.LLM.Model != "prod-model" || cfg.LLM.Provider != "openai" {
+		t.Errorf("unexpected llm config: %+v", cfg.LLM)
+	}
+	if _, ok := cfg.MCP.Servers["fs"]; !ok {
+		t.Errorf("expected base server fs to be kept, got %+v", cfg.MCP.Servers)
+	}
+	if _, ok := cfg.MCP.Servers["search"]; !ok {
+		t.Errorf("expected profile server search to be merged, got %+v", cfg.MCP.Servers)
+	}
+
+	want := map[string]string{
+		"log.level":              "default",
+		"llm.provider":           "cli",
+		"llm.model":              "profile:" + prodPath,
+		"mcp.servers.fs.command": "file:" + basePath,
+		"mcp.servers.search.url": "profile:" + prodPath,
+		"memory.embedder_model":  "default",
+	}
+	for key, source := range want {
+		if prov[key] != source {
+			t.Errorf("provenance of %s: got %q, want %q", key, prov[key], source)
+		}
+	}
+}
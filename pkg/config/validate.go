@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError describes an invalid configuration value.
+type FieldError struct {
+	// Path is the JSON path of the field, e.g. "mcp.servers.fs.command".
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError lists every problem found by Validate, so all of them
+// can be fixed in one pass.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config (%d problems):", len(e.Fields))
+	for _, field := range e.Fields {
+		b.WriteString("\n  - ")
+		b.WriteString(field.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the individual field errors.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = field
+	}
+	return errs
+}
+
+// MCP transports returned by MCPTransport.
+const (
+	MCPTransportStdio = "stdio"
+	MCPTransportHTTP  = "http"
+)
+
+// mcpTransportAliases maps every accepted transport name to its transport.
+var mcpTransportAliases = map[string]string{
+	"":                MCPTransportStdio,
+	"stdio":           MCPTransportStdio,
+	"http":            MCPTransportHTTP,
+	"streamable-http": MCPTransportHTTP,
+	"streamablehttp":  MCPTransportHTTP,
+}
+
+// MCPTransport resolves the transport configured for an MCP server to
+// MCPTransportStdio or MCPTransportHTTP. Names are case-insensitive, empty
+// means stdio and "streamable-http"/"streamablehttp" are aliases of http.
+// ok is false for unknown transports.
+func MCPTransport(name string) (transport string, ok bool) {
+	transport, ok = mcpTransportAliases[strings.ToLower(strings.TrimSpace(name))]
+	return transport, ok
+}
+
+var (
+	validMemoryBackends = []string{"vector", "inmemory"}
+	validPolicyEffects  = []string{"allow", "deny", "pending"}
+	validPolicyTypes    = []string{"tool", "agent", "mcp"}
+)
+
+// Validate checks cross-field constraints that the loader cannot express
+// through defaults. It returns a *ValidationError listing every problem, or
+// nil if cfg is valid.
+func Validate(cfg *Config) error {
+	if cfg == nil {
+		return nil
+	}
+	var v validator
+
+	names := make([]string, 0, len(cfg.MCP.Servers))
+	for name := range cfg.MCP.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		server := cfg.MCP.Servers[name]
+		path := "mcp.servers." + name
+		transport, ok := MCPTransport(server.Transport)
+		switch {
+		case !ok:
+			v.add(path+".transport", fmt.Sprintf("unsupported transport %q (want %s or %s)", server.Transport, MCPTransportStdio, MCPTransportHTTP))
+		case transport == MCPTransportStdio:
+			v.require(path+".command", server.Command, "is required for stdio transport")
+		default:
+			v.require(path+".url", server.URL, "is required for http transport")
+		}
+		v.nonNegative(path+".timeout_seconds", server.TimeoutSeconds)
+		v.nonNegative(path+".retry_count", server.RetryCount)
+		v.nonNegative(path+".retry_backoff_ms", server.RetryBackoffMs)
+		v.nonNegative(path+".cache_ttl_seconds", server.CacheTTLSeconds)
+	}
+
+	if cfg.Memory.Enabled {
+		switch provider := strings.ToLower(strings.TrimSpace(cfg.Memory.Provider)); provider {
+		case "", "vector":
+			v.require("memory.qdrant_addr", cfg.Memory.QdrantAddr, "is required when memory is enabled")
+			v.require("memory.embedder_model", cfg.Memory.EmbedderModel, "is required when memory is enabled")
+		case "inmemory":
+		default:
+			v.add("memory.provider", fmt.Sprintf("unknown provider %q (want %s)", cfg.Memory.Provider, strings.Join(validMemoryBackends, " or ")))
+		}
+	}
+
+	for i, rule := range cfg.Governance.Policies {
+		path := fmt.Sprintf("governance.policies[%d]", i)
+		if strings.TrimSpace(rule.Effect) != "" && !oneOf(rule.Effect, validPolicyEffects) {
+			v.add(path+".effect", fmt.Sprintf("invalid effect %q (want %s)", rule.Effect, strings.Join(validPolicyEffects, ", ")))
+		}
+		if strings.TrimSpace(rule.Type) != "" && !oneOf(rule.Type, validPolicyTypes) {
+			v.add(path+".type", fmt.Sprintf("invalid type %q (want %s)", rule.Type, strings.Join(validPolicyTypes, ", ")))
+		}
+	}
+
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(path, message string) {
+	v.fields = append(v.fields, FieldError{Path: path, Message: message})
+}
+
+func (v *validator) require(path, value, message string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(path, message)
+	}
+}
+
+func (v *validator) nonNegative(path string, value *int) {
+	if value != nil && *value < 0 {
+		v.add(path, "must be >= 0")
+	}
+}
+
+func oneOf(value string, allowed []string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, candidate := range allowed {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := &Config{}
+	cfg.MCP.Servers = map[string]MCPServerConfig{
+		"remote": {Transport: "http"},
+		"local":  {Transport: "stdio"},
+		"odd":    {Transport: "grpc"},
+	}
+	cfg.Memory.Enabled = true
+	cfg.Memory.EmbedderModel = "nomic-embed-text"
+	cfg.Governance.Policies = []PolicyRuleConfig{
+		{ID: "p1", Effect: "block"},
+		{ID: "p2", Effect: "allow", Type: "network"},
+	}
+
+	err := Validate(cfg)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	want := []string{
+		"mcp.servers.local.command",
+		"mcp.servers.odd.transport",
+		"mcp.servers.remote.url",
+		"memory.qdrant_addr",
+		"governance.policies[0].effect",
+		"governance.policies[1].type",
+	}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("expected %d problems, got %v", len(want), verr.Fields)
+	}
+	for i, path := range want {
+		if verr.Fields[i].Path != path {
+			t.Fatalf("problem %d: expected %s, got %s", i, path, verr.Fields[i].Path)
+		}
+	}
+	if !strings.Contains(err.Error(), "invalid config (6 problems)") {
+		t.Fatalf("unexpected message: %s", err)
+	}
+}
+
+func TestValidateAcceptsValidConfig(t *testing.T) {
+	cfg := &Config{}
+	cfg.MCP.Servers = map[string]MCPServerConfig{
+		"fs":     {Command: "mcp-fs"},
+		"remote": {Transport: "HTTP", URL: "http://localhost:8080"},
+	}
+	cfg.Memory.Enabled = true
+	cfg.Memory.Provider = "inmemory"
+	cfg.Governance.Policies = []PolicyRuleConfig{{ID: "p1", Effect: "deny", Type: "tool"}}
+
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}
+
+func TestValidateAcceptsTransportAliasesAndEmptyEffect(t *testing.T) {
+	cfg := &Config{}
+	cfg.MCP.Servers = map[string]MCPServerConfig{
+		"dashed":  {Transport: "streamable-http", URL: "http://localhost:8080"},
+		"compact": {Transport: "StreamableHTTP", URL: "http://localhost:8081"},
+	}
+	cfg.Governance.Policies = []PolicyRuleConfig{{ID: "p1", Name: "tools"}}
+
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cfg.MCP.Servers["dashed"] = MCPServerConfig{Transport: "streamable-http"}
+	err := Validate(cfg)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Path != "mcp.servers.dashed.url" {
+		t.Fatalf("expected a missing url for the alias, got %v", err)
+	}
+}
+
+func TestMCPTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"", MCPTransportStdio, true},
+		{" STDIO ", MCPTransportStdio, true},
+		{"http", MCPTransportHTTP, true},
+		{"streamable-http", MCPTransportHTTP, true},
+		{"streamablehttp", MCPTransportHTTP, true},
+		{"grpc", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := MCPTransport(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("MCPTransport(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLoadWithCLIValidates(t *testing.T) {
+	resetKoanf(t)
+	t.Cleanup(func() { resetKoanf(t) })
+	_, err := LoadWithCLI([]string{
+		`--set`, `mcp.servers={"demo":{"transport":"http"}}`,
+	})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if verr.Fields[0].Path != "mcp.servers.demo.url" {
+		t.Fatalf("unexpected problem: %v", verr.Fields[0])
+	}
+}
@@ -5,11 +5,16 @@ package config
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
+	klog "github.com/jllopis/kairos/pkg/log"
 )
 
 // Watcher monitors configuration files for changes and triggers reload.
@@ -17,6 +22,7 @@ type Watcher struct {
 	mu          sync.RWMutex
 	paths       []string
 	interval    time.Duration
+	debounce    time.Duration
 	lastModTime map[string]time.Time
 	config      *Config
 	listeners   []func(*Config)
@@ -37,6 +43,16 @@ func WithWatchInterval(d time.Duration) WatcherOption {
 	}
 }
 
+// WithWatchDebounce sets how long Watch waits after the last file event
+// before reloading.
+func WithWatchDebounce(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.debounce = d
+		}
+	}
+}
+
 // WithWatchLogger sets the logger for the watcher.
 func WithWatchLogger(logger *slog.Logger) WatcherOption {
 	return func(w *Watcher) {
@@ -50,11 +66,12 @@ func NewWatcher(paths []string, opts ...WatcherOption) (*Watcher, error) {
 	w := &Watcher{
 		paths:       paths,
 		interval:    1 * time.Second,
+		debounce:    200 * time.Millisecond,
 		lastModTime: make(map[string]time.Time),
 		listeners:   make([]func(*Config), 0),
 		stopCh:      make(chan struct{}),
 		doneCh:      make(chan struct{}),
-		logger:      slog.Default(),
+		logger:      klog.For("config"),
 	}
 
 	for _, opt := range opts {
@@ -123,6 +140,70 @@ func (w *Watcher) watch(ctx context.Context) {
 	}
 }
 
+// Watch loads the config at path and reloads it whenever the file changes,
+// using filesystem notifications instead of polling. Bursts of events (an
+// editor saving a file usually produces several) are debounced, and each
+// reload is validated: onChange is only called with configs that load
+// successfully, while failures are logged and the previous config is kept.
+//
+// Callbacks run on the watcher goroutine, one at a time, so a slow callback
+// delays the next reload. Call Stop on the returned Watcher to release it.
+func Watch(path string, onChange func(*Config), opts ...WatcherOption) (*Watcher, error) {
+	if path == "" {
+		return nil, errors.New("config: watch path is required")
+	}
+	w, err := NewWatcher([]string{path}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("config: watch %s: %w", path, err)
+	}
+	// Watch the directory: editors often replace the file on save, which
+	// drops a watch placed on the file itself.
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("config: watch %s: %w", path, err)
+	}
+	if onChange != nil {
+		w.OnChange(onChange)
+	}
+	go w.watchEvents(fsw, filepath.Clean(path))
+	return w, nil
+}
+
+func (w *Watcher) watchEvents(fsw *fsnotify.Watcher, path string) {
+	defer close(w.doneCh)
+	defer fsw.Close()
+
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			timer.Reset(w.debounce)
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("config watch error", "error", err)
+		case <-timer.C:
+			w.reload()
+		}
+	}
+}
+
 func (w *Watcher) checkForChanges() bool {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -208,3 +208,63 @@ func TestWatchConfigWithProfiles(t *testing.T) {
 		t.Errorf("expected model 'base', got %q", cfg.LLM.Model)
 	}
 }
+
+func TestWatchReloadsOnValidChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+	write(`mcp:
+  servers:
+    fs:
+      command: mcp-fs
+`)
+
+	changes := make(chan *Config, 4)
+	watcher, err := Watch(configPath, func(cfg *Config) {
+		changes <- cfg
+	}, WithWatchDebounce(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to watch config: %v", err)
+	}
+	defer watcher.Stop()
+
+	// Invalid: http server without url. The callback must not fire.
+	write(`mcp:
+  servers:
+    fs:
+      command: mcp-fs
+    remote:
+      transport: http
+`)
+	select {
+	case cfg := <-changes:
+		t.Fatalf("unexpected reload with invalid config: %+v", cfg.MCP.Servers)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	write(`mcp:
+  servers:
+    remote:
+      transport: http
+      url: http://localhost:8080/mcp
+`)
+	select {
+	case cfg := <-changes:
+		if _, ok := cfg.MCP.Servers["fs"]; ok {
+			t.Errorf("expected removed server fs to be dropped, got %+v", cfg.MCP.Servers)
+		}
+		if cfg.MCP.Servers["remote"].URL != "http://localhost:8080/mcp" {
+			t.Errorf("unexpected servers: %+v", cfg.MCP.Servers)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for config change notification")
+	}
+	if got := watcher.Config().MCP.Servers["remote"].URL; got == "" {
+		t.Errorf("expected watcher config to be updated")
+	}
+}
@@ -0,0 +1,810 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/core"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// DefaultCSVMaxRows caps the rows returned by a CSV query unless changed with
+// WithCSVMaxRows.
+const DefaultCSVMaxRows = 100
+
+// CSV column types inferred from the data.
+const (
+	CSVTypeInteger = "integer"
+	CSVTypeNumber  = "number"
+	CSVTypeBoolean = "boolean"
+	CSVTypeString  = "string"
+)
+
+// CSVConnector exposes the CSV files of a directory as queryable sheets
+// through the list_sheets, get_schema and query tools.
+type CSVConnector struct {
+	dir        string
+	sheets     map[string]*CSVSheet
+	toolPrefix string
+	maxRows    int
+	comma      rune
+}
+
+// CSVSheet is a loaded CSV file. Name is the file name without extension.
+type CSVSheet struct {
+	Name    string
+	Path    string
+	Columns []CSVColumn
+	Rows    [][]string
+}
+
+// CSVColumn is a column of a sheet with its inferred type.
+type CSVColumn struct {
+	Name string
+	Type string
+}
+
+// CSVOption configures the CSVConnector.
+type CSVOption func(*CSVConnector)
+
+// WithCSVToolPrefix adds a prefix to generated tool names.
+func WithCSVToolPrefix(prefix string) CSVOption {
+	return func(c *CSVConnector) {
+		c.toolPrefix = prefix
+	}
+}
+
+// WithCSVMaxRows caps the rows a query returns, whatever limit it asks for.
+func WithCSVMaxRows(n int) CSVOption {
+	return func(c *CSVConnector) {
+		if n > 0 {
+			c.maxRows = n
+		}
+	}
+}
+
+// WithCSVDelimiter sets the field delimiter (comma by default).
+func WithCSVDelimiter(r rune) CSVOption {
+	return func(c *CSVConnector) {
+		c.comma = r
+	}
+}
+
+// NewCSVConnector loads every .csv file in dir. The first row of each file is
+// the header; column types are inferred from the values.
+func NewCSVConnector(dir string, opts ...CSVOption) (*CSVConnector, error) {
+	c := &CSVConnector{
+		dir:     dir,
+		sheets:  make(map[string]*CSVSheet),
+		maxRows: DefaultCSVMaxRows,
+		comma:   ',',
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSV files: %w", err)
+	}
+	for _, path := range paths {
+		sheet, err := c.loadSheet(path)
+		if err != nil {
+			return nil, err
+		}
+		c.sheets[sheet.Name] = sheet
+	}
+
+	return c, nil
+}
+
+// loadSheet reads and types a CSV file.
+func (c *CSVConnector) loadSheet(path string) (*CSVSheet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comma = c.comma
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: missing header row", path)
+	}
+
+	sheet := &CSVSheet{
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path: path,
+		Rows: records[1:],
+	}
+	seen := make(map[string]bool)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%s: empty name for column %d", path, i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s: duplicate column %q", path, name)
+		}
+		seen[name] = true
+		sheet.Columns = append(sheet.Columns, CSVColumn{Name: name, Type: inferCSVType(sheet.Rows, i)})
+	}
+
+	return sheet, nil
+}
+
+// inferCSVType returns the narrowest type that fits every non-empty value of
+// column col.
+func inferCSVType(rows [][]string, col int) string {
+	isInt, isNumber, isBool, any := true, true, true, false
+	for _, row := range rows {
+		value := strings.TrimSpace(row[col])
+		if value == "" {
+			continue
+		}
+		any = true
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			isNumber = false
+		}
+		if _, err := strconv.ParseBool(value); err != nil {
+			isBool = false
+		}
+	}
+	switch {
+	case !any:
+		return CSVTypeString
+	case isInt:
+		return CSVTypeInteger
+	case isNumber:
+		return CSVTypeNumber
+	case isBool:
+		return CSVTypeBoolean
+	default:
+		return CSVTypeString
+	}
+}
+
+// Sheets returns the loaded sheets.
+func (c *CSVConnector) Sheets() map[string]*CSVSheet {
+	return c.sheets
+}
+
+// Tools generates the list_sheets, get_schema and query tools.
+func (c *CSVConnector) Tools() []core.Tool {
+	return coreToolsFromDefinitions(c.toolDefinitions(), c)
+}
+
+func (c *CSVConnector) toolName(name string) string {
+	if c.toolPrefix != "" {
+		return c.toolPrefix + "_" + name
+	}
+	return name
+}
+
+func (c *CSVConnector) toolDefinitions() []llm.Tool {
+	names := c.sheetNames()
+	sheetParam := map[string]interface{}{
+		"type":        "string",
+		"description": "Sheet name (CSV file name without extension)",
+		"enum":        names,
+	}
+
+	return []llm.Tool{
+		{
+			Type: llm.ToolTypeFunction,
+			Function: llm.FunctionDef{
+				Name:        c.toolName("list_sheets"),
+				Description: "List the available CSV sheets with their row counts",
+				Parameters: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{},
+				},
+			},
+		},
+		{
+			Type: llm.ToolTypeFunction,
+			Function: llm.FunctionDef{
+				Name:        c.toolName("get_schema"),
+				Description: "Get the columns and inferred types of a CSV sheet",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"sheet": sheetParam,
+					},
+					"required": []string{"sheet"},
+				},
+			},
+		},
+		{
+			Type: llm.ToolTypeFunction,
+			Function: llm.FunctionDef{
+				Name: c.toolName("query"),
+				Description: fmt.Sprintf("Query a CSV sheet: select columns, filter rows, group and aggregate, order and limit. "+
+					"At most %d rows are returned.", c.maxRows),
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"sheet": sheetParam,
+						"select": map[string]interface{}{
+							"type":        "array",
+							"description": "Columns to return (all when empty). Not allowed with aggregates",
+							"items":       map[string]interface{}{"type": "string"},
+						},
+						"filters": map[string]interface{}{
+							"type":        "array",
+							"description": "Conditions that every row must meet",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"column": map[string]interface{}{"type": "string"},
+									"op": map[string]interface{}{
+										"type": "string",
+										"enum": []string{"eq", "ne", "gt", "gte", "lt", "lte", "contains"},
+									},
+									"value": map[string]interface{}{"description": "Value to compare with"},
+								},
+								"required": []string{"column", "op", "value"},
+							},
+						},
+						"group_by": map[string]interface{}{
+							"type":        "array",
+							"description": "Columns to group by before aggregating",
+							"items":       map[string]interface{}{"type": "string"},
+						},
+						"aggregates": map[string]interface{}{
+							"type":        "array",
+							"description": "Aggregations over the rows of each group",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"column": map[string]interface{}{
+										"type":        "string",
+										"description": "Column to aggregate (optional for count)",
+									},
+									"op": map[string]interface{}{
+										"type": "string",
+										"enum": []string{"count", "sum", "avg", "min", "max"},
+									},
+									"as": map[string]interface{}{
+										"type":        "string",
+										"description": "Output column name (defaults to op_column)",
+									},
+								},
+								"required": []string{"op"},
+							},
+						},
+						"order_by": map[string]interface{}{
+							"type":        "string",
+							"description": "Output column to order by",
+						},
+						"order_desc": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Order descending",
+							"default":     false,
+						},
+						"limit": map[string]interface{}{
+							"type":        "integer",
+							"description": fmt.Sprintf("Maximum number of rows to return (at most %d)", c.maxRows),
+							"default":     c.maxRows,
+						},
+					},
+					"required": []string{"sheet"},
+				},
+			},
+		},
+	}
+}
+
+func (c *CSVConnector) sheetNames() []string {
+	names := make([]string, 0, len(c.sheets))
+	for name := range c.sheets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute runs a CSV tool.
+func (c *CSVConnector) Execute(ctx context.Context, toolName string, args map[string]interface{}) (interface{}, error) {
+	name := toolName
+	if c.toolPrefix != "" && strings.HasPrefix(toolName, c.toolPrefix+"_") {
+		name = strings.TrimPrefix(toolName, c.toolPrefix+"_")
+	}
+
+	switch name {
+	case "list_sheets":
+		return c.executeListSheets(), nil
+	case "get_schema":
+		sheet, err := c.sheetArg(args)
+		if err != nil {
+			return nil, err
+		}
+		return c.executeGetSchema(sheet), nil
+	case "query":
+		sheet, err := c.sheetArg(args)
+		if err != nil {
+			return nil, err
+		}
+		return c.executeQuery(ctx, sheet, args)
+	default:
+		return nil, fmt.Errorf("unknown operation: %s", toolName)
+	}
+}
+
+func (c *CSVConnector) sheetArg(args map[string]interface{}) (*CSVSheet, error) {
+	name, _ := args["sheet"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("sheet is required")
+	}
+	sheet, ok := c.sheets[name]
+	if !ok {
+		return nil, fmt.Errorf("sheet not found: %s", name)
+	}
+	return sheet, nil
+}
+
+func (c *CSVConnector) executeListSheets() interface{} {
+	sheets := make([]map[string]interface{}, 0, len(c.sheets))
+	for _, name := range c.sheetNames() {
+		sheets = append(sheets, map[string]interface{}{
+			"name": name,
+			"rows": len(c.sheets[name].Rows),
+		})
+	}
+	return map[string]interface{}{"sheets": sheets}
+}
+
+func (c *CSVConnector) executeGetSchema(sheet *CSVSheet) interface{} {
+	columns := make([]map[string]interface{}, 0, len(sheet.Columns))
+	for _, col := range sheet.Columns {
+		columns = append(columns, map[string]interface{}{
+			"name": col.Name,
+			"type": col.Type,
+		})
+	}
+	return map[string]interface{}{
+		"sheet":   sheet.Name,
+		"rows":    len(sheet.Rows),
+		"columns": columns,
+	}
+}
+
+// csvFilter is a parsed query filter.
+type csvFilter struct {
+	index  int
+	column CSVColumn
+	op     string
+	value  interface{}
+}
+
+// csvAggregate is a parsed query aggregate.
+type csvAggregate struct {
+	index int // -1 for count of rows
+	op    string
+	name  string
+}
+
+// executeQuery filters, groups, aggregates, orders and limits the rows of
+// sheet. The result holds the output columns, the rows as typed values, the
+// number of rows before the limit and whether the limit cut it.
+func (c *CSVConnector) executeQuery(ctx context.Context, sheet *CSVSheet, args map[string]interface{}) (interface{}, error) {
+	filters, err := parseCSVFilters(sheet, args["filters"])
+	if err != nil {
+		return nil, err
+	}
+	groupBy, err := columnIndexes(sheet, args["group_by"], "group_by")
+	if err != nil {
+		return nil, err
+	}
+	aggregates, err := parseCSVAggregates(sheet, args["aggregates"])
+	if err != nil {
+		return nil, err
+	}
+	selected, err := columnIndexes(sheet, args["select"], "select")
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) > 0 && (len(aggregates) > 0 || len(groupBy) > 0) {
+		return nil, fmt.Errorf("select cannot be combined with group_by or aggregates")
+	}
+
+	var matched [][]string
+	for i, row := range sheet.Rows {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		ok, err := matchCSVFilters(row, filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, row)
+		}
+	}
+
+	var columns []string
+	var rows [][]interface{}
+	if len(groupBy) > 0 || len(aggregates) > 0 {
+		columns, rows = aggregateCSVRows(sheet, matched, groupBy, aggregates)
+	} else {
+		if len(selected) == 0 {
+			for i := range sheet.Columns {
+				selected = append(selected, i)
+			}
+		}
+		for _, i := range selected {
+			columns = append(columns, sheet.Columns[i].Name)
+		}
+		rows = make([][]interface{}, 0, len(matched))
+		for _, row := range matched {
+			out := make([]interface{}, len(selected))
+			for j, i := range selected {
+				out[j] = typedCSVValue(row[i], sheet.Columns[i].Type)
+			}
+			rows = append(rows, out)
+		}
+	}
+
+	if orderBy, _ := args["order_by"].(string); orderBy != "" {
+		idx := -1
+		for i, name := range columns {
+			if name == orderBy {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("order_by: unknown output column %q", orderBy)
+		}
+		desc, _ := args["order_desc"].(bool)
+		sort.SliceStable(rows, func(i, j int) bool {
+			if desc {
+				return lessCSVValue(rows[j][idx], rows[i][idx])
+			}
+			return lessCSVValue(rows[i][idx], rows[j][idx])
+		})
+	}
+
+	limit := c.maxRows
+	if l, ok := args["limit"].(float64); ok && l > 0 && int(l) < limit {
+		limit = int(l)
+	}
+	total := len(rows)
+	truncated := total > limit
+	if truncated {
+		rows = rows[:limit]
+	}
+
+	return map[string]interface{}{
+		"columns":   columns,
+		"rows":      rows,
+		"row_count": total,
+		"truncated": truncated,
+	}, nil
+}
+
+func columnIndex(sheet *CSVSheet, name string) (int, bool) {
+	for i, col := range sheet.Columns {
+		if col.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// columnIndexes resolves a list of column names.
+func columnIndexes(sheet *CSVSheet, raw interface{}, field string) ([]int, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of column names", field)
+	}
+	indexes := make([]int, 0, len(list))
+	for _, item := range list {
+		name, _ := item.(string)
+		idx, ok := columnIndex(sheet, name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown column %q", field, name)
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+func parseCSVFilters(sheet *CSVSheet, raw interface{}) ([]csvFilter, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("filters must be a list")
+	}
+	filters := make([]csvFilter, 0, len(list))
+	for _, item := range list {
+		spec, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("filters: each filter must be an object")
+		}
+		name, _ := spec["column"].(string)
+		idx, ok := columnIndex(sheet, name)
+		if !ok {
+			return nil, fmt.Errorf("filters: unknown column %q", name)
+		}
+		op, _ := spec["op"].(string)
+		switch op {
+		case "eq", "ne", "gt", "gte", "lt", "lte", "contains":
+		default:
+			return nil, fmt.Errorf("filters: unsupported op %q", op)
+		}
+		filters = append(filters, csvFilter{index: idx, column: sheet.Columns[idx], op: op, value: spec["value"]})
+	}
+	return filters, nil
+}
+
+func parseCSVAggregates(sheet *CSVSheet, raw interface{}) ([]csvAggregate, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("aggregates must be a list")
+	}
+	aggregates := make([]csvAggregate, 0, len(list))
+	for _, item := range list {
+		spec, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("aggregates: each aggregate must be an object")
+		}
+		op, _ := spec["op"].(string)
+		name, _ := spec["column"].(string)
+		agg := csvAggregate{index: -1, op: op}
+		if name != "" {
+			idx, ok := columnIndex(sheet, name)
+			if !ok {
+				return nil, fmt.Errorf("aggregates: unknown column %q", name)
+			}
+			agg.index = idx
+		}
+		switch op {
+		case "count":
+		case "sum", "avg", "min", "max":
+			if agg.index < 0 {
+				return nil, fmt.Errorf("aggregates: %s needs a column", op)
+			}
+			if t := sheet.Columns[agg.index].Type; t != CSVTypeInteger && t != CSVTypeNumber {
+				return nil, fmt.Errorf("aggregates: cannot %s non-numeric column %q (%s)", op, name, t)
+			}
+		default:
+			return nil, fmt.Errorf("aggregates: unsupported op %q", op)
+		}
+		agg.name, _ = spec["as"].(string)
+		if agg.name == "" {
+			agg.name = op
+			if name != "" {
+				agg.name = op + "_" + name
+			}
+		}
+		aggregates = append(aggregates, agg)
+	}
+	return aggregates, nil
+}
+
+func matchCSVFilters(row []string, filters []csvFilter) (bool, error) {
+	for _, f := range filters {
+		ok, err := matchCSVFilter(strings.TrimSpace(row[f.index]), f)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchCSVFilter(cell string, f csvFilter) (bool, error) {
+	want := strings.TrimSpace(fmt.Sprint(f.value))
+	if f.op == "contains" {
+		return strings.Contains(strings.ToLower(cell), strings.ToLower(want)), nil
+	}
+
+	var cmp int
+	if f.column.Type == CSVTypeInteger || f.column.Type == CSVTypeNumber {
+		target, err := strconv.ParseFloat(want, 64)
+		if err != nil {
+			return false, fmt.Errorf("filters: value %q for numeric column %q is not a number", want, f.column.Name)
+		}
+		if cell == "" {
+			return f.op == "ne", nil
+		}
+		value, _ := strconv.ParseFloat(cell, 64)
+		switch {
+		case value < target:
+			cmp = -1
+		case value > target:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(cell, want)
+	}
+
+	switch f.op {
+	case "eq":
+		return cmp == 0, nil
+	case "ne":
+		return cmp != 0, nil
+	case "gt":
+		return cmp > 0, nil
+	case "gte":
+		return cmp >= 0, nil
+	case "lt":
+		return cmp < 0, nil
+	default: // lte
+		return cmp <= 0, nil
+	}
+}
+
+// aggregateCSVRows groups rows by the groupBy columns, in order of first
+// appearance, and computes the aggregates of each group.
+func aggregateCSVRows(sheet *CSVSheet, rows [][]string, groupBy []int, aggregates []csvAggregate) ([]string, [][]interface{}) {
+	columns := make([]string, 0, len(groupBy)+len(aggregates))
+	for _, i := range groupBy {
+		columns = append(columns, sheet.Columns[i].Name)
+	}
+	for _, agg := range aggregates {
+		columns = append(columns, agg.name)
+	}
+
+	type group struct {
+		key  []string
+		rows [][]string
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	for _, row := range rows {
+		key := make([]string, len(groupBy))
+		for j, i := range groupBy {
+			key[j] = strings.TrimSpace(row[i])
+		}
+		k := strings.Join(key, "\x00")
+		g, ok := byKey[k]
+		if !ok {
+			g = &group{key: key}
+			byKey[k] = g
+			groups = append(groups, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+	if len(groupBy) == 0 && len(groups) == 0 {
+		groups = append(groups, &group{})
+	}
+
+	out := make([][]interface{}, 0, len(groups))
+	for _, g := range groups {
+		row := make([]interface{}, 0, len(columns))
+		for j, i := range groupBy {
+			row = append(row, typedCSVValue(g.key[j], sheet.Columns[i].Type))
+		}
+		for _, agg := range aggregates {
+			row = append(row, computeCSVAggregate(g.rows, agg))
+		}
+		out = append(out, row)
+	}
+	return columns, out
+}
+
+func computeCSVAggregate(rows [][]string, agg csvAggregate) interface{} {
+	if agg.op == "count" {
+		if agg.index < 0 {
+			return len(rows)
+		}
+		n := 0
+		for _, row := range rows {
+			if strings.TrimSpace(row[agg.index]) != "" {
+				n++
+			}
+		}
+		return n
+	}
+
+	var sum float64
+	n := 0
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, row := range rows {
+		cell := strings.TrimSpace(row[agg.index])
+		if cell == "" {
+			continue
+		}
+		value, _ := strconv.ParseFloat(cell, 64)
+		sum += value
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+		n++
+	}
+	if n == 0 {
+		if agg.op == "sum" {
+			return 0.0
+		}
+		return nil
+	}
+	switch agg.op {
+	case "sum":
+		return sum
+	case "avg":
+		return sum / float64(n)
+	case "min":
+		return min
+	default: // max
+		return max
+	}
+}
+
+// typedCSVValue converts a cell to the Go value of its column type; empty
+// cells are nil.
+func typedCSVValue(cell, typ string) interface{} {
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return nil
+	}
+	switch typ {
+	case CSVTypeInteger:
+		if v, err := strconv.ParseInt(cell, 10, 64); err == nil {
+			return v
+		}
+	case CSVTypeNumber:
+		if v, err := strconv.ParseFloat(cell, 64); err == nil {
+			return v
+		}
+	case CSVTypeBoolean:
+		if v, err := strconv.ParseBool(cell); err == nil {
+			return v
+		}
+	}
+	return cell
+}
+
+// lessCSVValue orders typed values; nil sorts first.
+func lessCSVValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	af, aNum := csvNumber(a)
+	bf, bNum := csvNumber(b)
+	if aNum && bNum {
+		return af < bf
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func csvNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const salesCSV = `region,product,units,price,paid
+north,apple,10,1.5,true
+south,apple,4,1.5,false
+north,pear,7,2.25,true
+east,pear,,2.25,true
+south,plum,3,0.75,true
+`
+
+func newTestCSVConnector(t *testing.T, opts ...CSVOption) *CSVConnector {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sales.csv"), []byte(salesCSV), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	c, err := NewCSVConnector(dir, opts...)
+	if err != nil {
+		t.Fatalf("NewCSVConnector: %v", err)
+	}
+	return c
+}
+
+// TestCSVConnectorInfersTypes tests loading sheets and inferring column types.
+func TestCSVConnectorInfersTypes(t *testing.T) {
+	c := newTestCSVConnector(t)
+
+	sheet, ok := c.Sheets()["sales"]
+	if !ok {
+		t.Fatalf("expected sheet sales, got %v", c.Sheets())
+	}
+	want := map[string]string{
+		"region":  CSVTypeString,
+		"product": CSVTypeString,
+		"units":   CSVTypeInteger,
+		"price":   CSVTypeNumber,
+		"paid":    CSVTypeBoolean,
+	}
+	for _, col := range sheet.Columns {
+		if want[col.Name] != col.Type {
+			t.Errorf("column %s: expected %s, got %s", col.Name, want[col.Name], col.Type)
+		}
+	}
+	if len(sheet.Rows) != 5 {
+		t.Errorf("expected 5 rows, got %d", len(sheet.Rows))
+	}
+}
+
+// TestCSVToolGeneration tests tool names and prefixing.
+func TestCSVToolGeneration(t *testing.T) {
+	c := newTestCSVConnector(t, WithCSVToolPrefix("data"))
+
+	var names []string
+	for _, tool := range c.Tools() {
+		names = append(names, tool.Name())
+	}
+	if got := strings.Join(names, ","); got != "data_list_sheets,data_get_schema,data_query" {
+		t.Errorf("unexpected tools: %s", got)
+	}
+
+	result, err := c.Execute(context.Background(), "data_list_sheets", nil)
+	if err != nil {
+		t.Fatalf("list_sheets: %v", err)
+	}
+	sheets := result.(map[string]interface{})["sheets"].([]map[string]interface{})
+	if len(sheets) != 1 || sheets[0]["name"] != "sales" || sheets[0]["rows"] != 5 {
+		t.Errorf("unexpected sheets: %v", sheets)
+	}
+}
+
+// TestCSVQueryFilterAndSelect tests filtering, projection and ordering.
+func TestCSVQueryFilterAndSelect(t *testing.T) {
+	c := newTestCSVConnector(t)
+
+	result, err := c.Execute(context.Background(), "query", map[string]interface{}{
+		"sheet":  "sales",
+		"select": []interface{}{"product", "units"},
+		"filters": []interface{}{
+			map[string]interface{}{"column": "units", "op": "gte", "value": float64(4)},
+		},
+		"order_by":   "units",
+		"order_desc": true,
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	out := result.(map[string]interface{})
+	rows := out["rows"].([][]interface{})
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %v", rows)
+	}
+	if rows[0][0] != "apple" || rows[0][1] != int64(10) || rows[2][1] != int64(4) {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
+
+// TestCSVQueryAggregate tests grouping and aggregation.
+func TestCSVQueryAggregate(t *testing.T) {
+	c := newTestCSVConnector(t)
+
+	result, err := c.Execute(context.Background(), "query", map[string]interface{}{
+		"sheet":    "sales",
+		"group_by": []interface{}{"product"},
+		"aggregates": []interface{}{
+			map[string]interface{}{"op": "count"},
+			map[string]interface{}{"op": "sum", "column": "units", "as": "total"},
+		},
+		"order_by": "product",
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	out := result.(map[string]interface{})
+	if got := strings.Join(out["columns"].([]string), ","); got != "product,count,total" {
+		t.Errorf("unexpected columns: %s", got)
+	}
+	rows := out["rows"].([][]interface{})
+	if len(rows) != 3 || rows[0][0] != "apple" || rows[0][1] != 2 || rows[0][2] != 14.0 {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if rows[1][0] != "pear" || rows[1][2] != 7.0 {
+		t.Errorf("expected empty units to be skipped, got %v", rows[1])
+	}
+
+	_, err = c.Execute(context.Background(), "query", map[string]interface{}{
+		"sheet":      "sales",
+		"aggregates": []interface{}{map[string]interface{}{"op": "avg", "column": "region"}},
+	})
+	if err == nil {
+		t.Error("expected error aggregating a non-numeric column")
+	}
+}
+
+// TestCSVQueryLimit tests that results are capped by the max rows.
+func TestCSVQueryLimit(t *testing.T) {
+	c := newTestCSVConnector(t, WithCSVMaxRows(2))
+
+	result, err := c.Execute(context.Background(), "query", map[string]interface{}{
+		"sheet": "sales",
+		"limit": float64(50),
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	out := result.(map[string]interface{})
+	if len(out["rows"].([][]interface{})) != 2 || out["row_count"] != 5 || out["truncated"] != true {
+		t.Errorf("unexpected result: %v", out)
+	}
+}
+
+// TestCSVConnectorErrors tests invalid files and arguments.
+func TestCSVConnectorErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.csv"), []byte("a,a\n1,2\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if _, err := NewCSVConnector(dir); err == nil {
+		t.Error("expected error for duplicate columns")
+	}
+
+	c := newTestCSVConnector(t)
+	if _, err := c.Execute(context.Background(), "get_schema", map[string]interface{}{"sheet": "missing"}); err == nil {
+		t.Error("expected error for unknown sheet")
+	}
+	if _, err := c.Execute(context.Background(), "query", map[string]interface{}{
+		"sheet":   "sales",
+		"filters": []interface{}{map[string]interface{}{"column": "nope", "op": "eq", "value": "x"}},
+	}); err == nil {
+		t.Error("expected error for unknown filter column")
+	}
+}
@@ -23,6 +23,7 @@ type GraphQLConnector struct {
 	schema     *GraphQLSchema
 	headers    map[string]string
 	toolPrefix string
+	shaper     responseShaper
 }
 
 // GraphQLSchema represents the introspected GraphQL schema.
@@ -111,6 +112,24 @@ func WithGraphQLToolPrefix(prefix string) GraphQLOption {
 	}
 }
 
+// WithGraphQLResponseProjection keeps only jsonPaths of the data returned
+// for field (the query or mutation name, without tool prefix). Paths are
+// relative to the data object, e.g. "user.name" for the user field; see
+// WithResponseProjection for the syntax.
+func WithGraphQLResponseProjection(field string, jsonPaths []string) GraphQLOption {
+	return func(c *GraphQLConnector) {
+		c.shaper.setProjection(field, jsonPaths)
+	}
+}
+
+// WithGraphQLMaxResponseBytes truncates responses longer than n bytes,
+// ending them with a marker that tells the agent the response was cut.
+func WithGraphQLMaxResponseBytes(n int) GraphQLOption {
+	return func(c *GraphQLConnector) {
+		c.shaper.maxBytes = n
+	}
+}
+
 // NewGraphQLConnector creates a GraphQL connector from an endpoint.
 // It performs introspection to discover the schema.
 func NewGraphQLConnector(endpoint string, opts ...GraphQLOption) (*GraphQLConnector, error) {
@@ -453,7 +472,11 @@ func (c *GraphQLConnector) Execute(ctx context.Context, toolName string, args ma
 	query := c.buildQuery(fieldName, args, opType)
 
 	// Execute
-	return c.executeQuery(ctx, query, args)
+	data, err := c.executeQuery(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.shaper.truncate(c.shaper.project(fieldName, data)), nil
 }
 
 // getOperationType determines if a field is a query or mutation.
@@ -293,6 +293,31 @@ func TestGraphQLExecute(t *testing.T) {
 	}
 }
 
+func TestGraphQLResponseProjection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"user": map[string]interface{}{"id": "123", "name": "John Doe", "email": "john@example.com"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	c := NewGraphQLConnectorFromSchema(server.URL, mockGraphQLSchema,
+		WithGraphQLToolPrefix("api"),
+		WithGraphQLResponseProjection("user", []string{"user.name"}),
+	)
+
+	result, err := c.Execute(context.Background(), "api_user", map[string]interface{}{"id": "123"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	user := result.(map[string]interface{})["user"].(map[string]interface{})
+	if len(user) != 1 || user["name"] != "John Doe" {
+		t.Errorf("Expected only the user name, got %v", user)
+	}
+}
+
 func TestGraphQLExecuteMutation(t *testing.T) {
 	// Create a mock GraphQL server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -16,6 +16,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -31,6 +32,8 @@ type GRPCConnector struct {
 	services   map[string]*GRPCService
 	opts       []grpc.DialOption
 	toolPrefix string
+	files      []protoreflect.FileDescriptor
+	ownsConn   bool
 }
 
 // GRPCService represents a gRPC service discovered via reflection.
@@ -75,6 +78,14 @@ func WithGRPCToolPrefix(prefix string) GRPCOption {
 	}
 }
 
+// WithGRPCFiles registers the services declared in files, for servers
+// without reflection. Generated code exposes them as File_<name>_proto.
+func WithGRPCFiles(files ...protoreflect.FileDescriptor) GRPCOption {
+	return func(c *GRPCConnector) {
+		c.files = append(c.files, files...)
+	}
+}
+
 // NewGRPCConnector creates a gRPC connector using server reflection.
 func NewGRPCConnector(target string, opts ...GRPCOption) (*GRPCConnector, error) {
 	c := &GRPCConnector{
@@ -101,6 +112,7 @@ func NewGRPCConnector(target string, opts ...GRPCOption) (*GRPCConnector, error)
 		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
 	}
 	c.conn = conn
+	c.ownsConn = true
 
 	// Perform reflection to discover services
 	if err := c.reflect(ctx); err != nil {
@@ -111,6 +123,42 @@ func NewGRPCConnector(target string, opts ...GRPCOption) (*GRPCConnector, error)
 	return c, nil
 }
 
+// NewGRPCConnectorFromConn creates a connector on an existing connection,
+// which the caller keeps owning. With reflection the services are discovered
+// from the server; services given with WithGRPCFiles are added either way.
+func NewGRPCConnectorFromConn(conn *grpc.ClientConn, reflection bool, opts ...GRPCOption) (*GRPCConnector, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("nil gRPC connection")
+	}
+
+	c := &GRPCConnector{
+		target:   conn.Target(),
+		conn:     conn,
+		services: make(map[string]*GRPCService),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if reflection {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := c.reflect(ctx); err != nil {
+			return nil, fmt.Errorf("reflection failed: %w", err)
+		}
+	}
+
+	for _, fd := range c.files {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			c.addService(services.Get(i))
+		}
+	}
+
+	return c, nil
+}
+
 // NewGRPCConnectorFromServices creates a connector from pre-defined services.
 // Useful for testing or when reflection is not available.
 func NewGRPCConnectorFromServices(target string, services map[string]*GRPCService, opts ...GRPCOption) *GRPCConnector {
@@ -156,6 +204,10 @@ func (c *GRPCConnector) reflect(ctx context.Context) error {
 		return fmt.Errorf("unexpected response type")
 	}
 
+	// The server sends each file once per stream, so files shared between
+	// services (e.g. google/protobuf/timestamp.proto) are kept here.
+	resolver := &protoregistry.Files{}
+
 	// For each service, get its file descriptor
 	for _, svc := range listResp.GetService() {
 		serviceName := svc.GetName()
@@ -185,7 +237,7 @@ func (c *GRPCConnector) reflect(ctx context.Context) error {
 		}
 
 		// Parse file descriptors
-		if err := c.parseFileDescriptors(serviceName, fdResp.GetFileDescriptorProto()); err != nil {
+		if err := c.parseFileDescriptors(resolver, serviceName, fdResp.GetFileDescriptorProto()); err != nil {
 			continue
 		}
 	}
@@ -193,8 +245,9 @@ func (c *GRPCConnector) reflect(ctx context.Context) error {
 	return nil
 }
 
-// parseFileDescriptors parses the file descriptor protos and extracts service info.
-func (c *GRPCConnector) parseFileDescriptors(serviceName string, fdProtos [][]byte) error {
+// parseFileDescriptors parses the file descriptor protos into resolver and
+// extracts service info.
+func (c *GRPCConnector) parseFileDescriptors(resolver *protoregistry.Files, serviceName string, fdProtos [][]byte) error {
 	// Build a file descriptor set
 	var files []*descriptorpb.FileDescriptorProto
 	for _, fdBytes := range fdProtos {
@@ -205,17 +258,25 @@ func (c *GRPCConnector) parseFileDescriptors(serviceName string, fdProtos [][]by
 		files = append(files, &fd)
 	}
 
-	// Create a resolver for dependencies
-	resolver := &protoregistry.Files{}
-
-	// Register all files
-	for _, fdProto := range files {
-		fd, err := protodesc.NewFile(fdProto, resolver)
-		if err != nil {
-			// Try to continue with other files
-			continue
+	// Register all files. Files may come before their dependencies, so
+	// retry until no more can be built; the rest are skipped.
+	for len(files) > 0 {
+		var pending []*descriptorpb.FileDescriptorProto
+		for _, fdProto := range files {
+			if _, err := resolver.FindFileByPath(fdProto.GetName()); err == nil {
+				continue
+			}
+			fd, err := protodesc.NewFile(fdProto, resolver)
+			if err != nil {
+				pending = append(pending, fdProto)
+				continue
+			}
+			resolver.RegisterFile(fd)
 		}
-		resolver.RegisterFile(fd)
+		if len(pending) == len(files) {
+			break
+		}
+		files = pending
 	}
 
 	// Find our service
@@ -229,10 +290,17 @@ func (c *GRPCConnector) parseFileDescriptors(serviceName string, fdProtos [][]by
 		return fmt.Errorf("not a service descriptor")
 	}
 
-	// Extract methods
+	c.addService(serviceDesc)
+	return nil
+}
+
+// addService registers a service and its methods.
+func (c *GRPCConnector) addService(serviceDesc protoreflect.ServiceDescriptor) {
+	serviceName := string(serviceDesc.FullName())
 	svc := &GRPCService{
 		Name:        string(serviceDesc.Name()),
 		FullName:    serviceName,
+		FileDesc:    serviceDesc.ParentFile(),
 		ServiceDesc: serviceDesc,
 	}
 
@@ -249,7 +317,6 @@ func (c *GRPCConnector) parseFileDescriptors(serviceName string, fdProtos [][]by
 	}
 
 	c.services[serviceName] = svc
-	return nil
 }
 
 // Tools generates core tools from discovered gRPC services.
@@ -403,6 +470,9 @@ func (c *GRPCConnector) kindToJSONSchema(field protoreflect.FieldDescriptor) map
 		}
 
 	case protoreflect.MessageKind:
+		if schema, ok := wellKnownJSONSchema(field.Message().FullName()); ok {
+			return schema
+		}
 		// Nested message - recursively convert
 		return c.messageToJSONSchema(field.Message())
 
@@ -442,6 +512,38 @@ func (c *GRPCConnector) Execute(ctx context.Context, toolName string, args map[s
 	return c.messageToMap(outputMsg), nil
 }
 
+// ExecuteJSON calls a gRPC method with protojson-encoded arguments and
+// returns the response marshaled with protojson, as a string. Unknown
+// argument fields are ignored.
+func (c *GRPCConnector) ExecuteJSON(ctx context.Context, toolName, argsJSON string) (any, error) {
+	_, method, err := c.findMethod(toolName)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.conn == nil {
+		return nil, fmt.Errorf("not connected to gRPC server")
+	}
+
+	inputMsg := dynamicpb.NewMessage(method.InputType)
+	if argsJSON != "" {
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(argsJSON), inputMsg); err != nil {
+			return nil, fmt.Errorf("invalid JSON arguments: %w", err)
+		}
+	}
+
+	outputMsg := dynamicpb.NewMessage(method.OutputType)
+	if err := c.conn.Invoke(ctx, method.FullName, inputMsg, outputMsg); err != nil {
+		return nil, fmt.Errorf("gRPC call failed: %w", err)
+	}
+
+	respJSON, err := protojson.Marshal(outputMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	return string(respJSON), nil
+}
+
 // findMethod finds the service and method for a tool name.
 func (c *GRPCConnector) findMethod(toolName string) (*GRPCService, *GRPCMethod, error) {
 	// Remove prefix if present
@@ -561,6 +663,19 @@ func (c *GRPCConnector) toProtoValue(field protoreflect.FieldDescriptor, value i
 		}
 
 	case protoreflect.MessageKind:
+		if isWellKnownType(field.Message().FullName()) {
+			// Well-known types take their canonical JSON form, e.g. an
+			// RFC 3339 string for a Timestamp or any object for a Struct.
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			wkt := dynamicpb.NewMessage(field.Message())
+			if err := protojson.Unmarshal(raw, wkt); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return protoreflect.ValueOfMessage(wkt), nil
+		}
 		if m, ok := value.(map[string]interface{}); ok {
 			nestedMsg := dynamicpb.NewMessage(field.Message())
 			if err := c.populateMessage(nestedMsg, m); err != nil {
@@ -618,6 +733,14 @@ func (c *GRPCConnector) protoValueToGo(field protoreflect.FieldDescriptor, value
 func (c *GRPCConnector) scalarToGo(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
 	switch field.Kind() {
 	case protoreflect.MessageKind:
+		if isWellKnownType(field.Message().FullName()) {
+			if raw, err := protojson.Marshal(value.Message().Interface()); err == nil {
+				var v interface{}
+				if err := json.Unmarshal(raw, &v); err == nil {
+					return v
+				}
+			}
+		}
 		if msg, ok := value.Interface().(*dynamicpb.Message); ok {
 			return c.messageToMap(msg)
 		}
@@ -629,9 +752,10 @@ func (c *GRPCConnector) scalarToGo(field protoreflect.FieldDescriptor, value pro
 	}
 }
 
-// Close closes the gRPC connection.
+// Close closes the gRPC connection opened by NewGRPCConnector. Connections
+// passed to NewGRPCConnectorFromConn are left open.
 func (c *GRPCConnector) Close() error {
-	if c.conn != nil {
+	if c.conn != nil && c.ownsConn {
 		return c.conn.Close()
 	}
 	return nil
@@ -649,6 +773,45 @@ func (c *GRPCConnector) Target() string {
 
 // Helper functions
 
+// wellKnownJSONSchema returns the JSON Schema of the canonical JSON form of
+// the protobuf well-known types.
+func wellKnownJSONSchema(name protoreflect.FullName) (map[string]interface{}, bool) {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return map[string]interface{}{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return map[string]interface{}{"type": "string", "description": "Duration in seconds with an s suffix, e.g. 1.5s"}, true
+	case "google.protobuf.FieldMask":
+		return map[string]interface{}{"type": "string", "description": "Comma-separated field paths"}, true
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return map[string]interface{}{"type": "object"}, true
+	case "google.protobuf.ListValue":
+		return map[string]interface{}{"type": "array", "items": map[string]interface{}{}}, true
+	case "google.protobuf.Value":
+		return map[string]interface{}{}, true
+	case "google.protobuf.BoolValue":
+		return map[string]interface{}{"type": "boolean"}, true
+	case "google.protobuf.StringValue":
+		return map[string]interface{}{"type": "string"}, true
+	case "google.protobuf.BytesValue":
+		return map[string]interface{}{"type": "string", "format": "byte"}, true
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return map[string]interface{}{"type": "integer"}, true
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return map[string]interface{}{"type": "number"}, true
+	default:
+		return nil, false
+	}
+}
+
+// isWellKnownType reports whether name is a well-known type with a special
+// JSON form.
+func isWellKnownType(name protoreflect.FullName) bool {
+	_, ok := wellKnownJSONSchema(name)
+	return ok
+}
+
 func toSnakeCase(s string) string {
 	var result strings.Builder
 	for i, r := range s {
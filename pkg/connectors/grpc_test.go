@@ -4,9 +4,24 @@
 package connectors
 
 import (
+	"context"
+	"encoding/json"
+	"net"
 	"testing"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // TestGRPCConnectorFromServices tests creating a connector from pre-defined services.
@@ -260,3 +275,188 @@ func TestGRPCExecuteWithoutConnection(t *testing.T) {
 		t.Error("Expected error when not connected")
 	}
 }
+
+// newEchoFile builds a proto file with an Echo service whose messages use
+// well-known types.
+func newEchoFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	message := func(name string) *descriptorpb.DescriptorProto {
+		field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+			f := &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(name),
+				JsonName: proto.String(name),
+				Number:   proto.Int32(number),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     typ.Enum(),
+			}
+			if typeName != "" {
+				f.TypeName = proto.String(typeName)
+			}
+			return f
+		}
+		return &descriptorpb.DescriptorProto{
+			Name: proto.String(name),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("text", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("at", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+				field("meta", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct"),
+				field("count", 4, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+			},
+		}
+	}
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("kairostest/echo.proto"),
+		Package:    proto.String("kairostest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto", "google/protobuf/struct.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("EchoRequest"),
+			message("EchoResponse"),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Echo"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Echo"),
+				InputType:  proto.String(".kairostest.EchoRequest"),
+				OutputType: proto.String(".kairostest.EchoResponse"),
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build file: %v", err)
+	}
+	return fd
+}
+
+// startEchoServer serves the Echo service of fd, which returns its request,
+// with reflection enabled.
+func startEchoServer(t *testing.T, fd protoreflect.FileDescriptor) *grpc.ClientConn {
+	t.Helper()
+
+	method := fd.Services().Get(0).Methods().Get(0)
+	srv := grpc.NewServer()
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "kairostest.Echo",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Echo",
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				in := dynamicpb.NewMessage(method.Input())
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				out := dynamicpb.NewMessage(method.Output())
+				in.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+					out.Set(method.Output().Fields().ByNumber(f.Number()), v)
+					return true
+				})
+				return out, nil
+			},
+		}},
+	}, struct{}{})
+
+	files := &protoregistry.Files{}
+	for _, f := range []protoreflect.FileDescriptor{
+		fd,
+		timestamppb.File_google_protobuf_timestamp_proto,
+		structpb.File_google_protobuf_struct_proto,
+	} {
+		if err := files.RegisterFile(f); err != nil {
+			t.Fatalf("register file: %v", err)
+		}
+	}
+	reflectionServer := reflection.NewServer(reflection.ServerOptions{Services: srv, DescriptorResolver: files})
+	grpc_reflection_v1alpha.RegisterServerReflectionServer(srv, reflectionServer)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestGRPCConnectorFromConnReflection tests discovery and calls through an
+// existing connection, including well-known types.
+func TestGRPCConnectorFromConnReflection(t *testing.T) {
+	conn := startEchoServer(t, newEchoFile(t))
+
+	c, err := NewGRPCConnectorFromConn(conn, true)
+	if err != nil {
+		t.Fatalf("NewGRPCConnectorFromConn: %v", err)
+	}
+
+	defs := c.toolDefinitions()
+	if len(defs) != 1 || defs[0].Function.Name != "echo_echo" {
+		t.Fatalf("unexpected tools: %+v", defs)
+	}
+	props := defs[0].Function.Parameters.(map[string]interface{})["properties"].(map[string]interface{})
+	if at := props["at"].(map[string]interface{}); at["format"] != "date-time" {
+		t.Errorf("expected timestamp as date-time string, got %v", at)
+	}
+	if meta := props["meta"].(map[string]interface{}); meta["type"] != "object" {
+		t.Errorf("expected struct as object, got %v", meta)
+	}
+
+	result, err := c.Execute(context.Background(), "echo_echo", map[string]interface{}{
+		"text": "hi",
+		"at":   "2026-01-02T03:04:05Z",
+		"meta": map[string]interface{}{"team": "core"},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	out := result.(map[string]interface{})
+	if out["text"] != "hi" || out["at"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("unexpected result: %v", out)
+	}
+	if meta, _ := out["meta"].(map[string]interface{}); meta["team"] != "core" {
+		t.Errorf("unexpected meta: %v", out["meta"])
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := c.Execute(context.Background(), "echo_echo", nil); err != nil {
+		t.Errorf("expected caller-owned connection to stay open, got %v", err)
+	}
+}
+
+// TestGRPCConnectorExecuteJSON tests protojson calls on services registered
+// without reflection.
+func TestGRPCConnectorExecuteJSON(t *testing.T) {
+	fd := newEchoFile(t)
+	conn := startEchoServer(t, fd)
+
+	c, err := NewGRPCConnectorFromConn(conn, false, WithGRPCFiles(fd))
+	if err != nil {
+		t.Fatalf("NewGRPCConnectorFromConn: %v", err)
+	}
+
+	raw, err := c.ExecuteJSON(context.Background(), "echo_echo", `{"text":"hi","count":3,"unknown":true}`)
+	if err != nil {
+		t.Fatalf("ExecuteJSON: %v", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(raw.(string)), &out); err != nil {
+		t.Fatalf("decode response %s: %v", raw, err)
+	}
+	if out["text"] != "hi" || out["count"] != float64(3) {
+		t.Errorf("unexpected response: %s", raw)
+	}
+
+	if _, err := c.ExecuteJSON(context.Background(), "echo_echo", `{"count":"many"}`); err == nil {
+		t.Error("expected error for invalid request")
+	}
+}
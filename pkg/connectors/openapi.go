@@ -16,7 +16,9 @@ import (
 	"time"
 
 	"github.com/jllopis/kairos/pkg/core"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
 	"github.com/jllopis/kairos/pkg/llm"
+	"github.com/jllopis/kairos/pkg/resilience"
 	"gopkg.in/yaml.v3"
 )
 
@@ -108,6 +110,15 @@ type OpenAPIConnector struct {
 	httpClient *http.Client
 	tools      []llm.Tool
 	handlers   map[string]ToolHandler
+	pagination map[string]*Pagination
+	maxPages   int
+	shaper     responseShaper
+
+	retry          *resilience.RetryConfig
+	breaker        *resilience.CircuitBreaker
+	retryAll       bool
+	requestTimeout time.Duration
+	fallback       resilience.FallbackStrategy
 }
 
 // AuthConfig defines authentication options.
@@ -182,6 +193,25 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithResponseProjection keeps only jsonPaths of the responses of operation
+// (the tool name, usually its operationId). Paths are dot-separated keys such
+// as "owner.name"; keys applied to an array apply to each element, so
+// []string{"id", "name"} on a list keeps those fields of every item. "*"
+// selects every element explicitly.
+func WithResponseProjection(operation string, jsonPaths []string) Option {
+	return func(c *OpenAPIConnector) {
+		c.shaper.setProjection(operation, jsonPaths)
+	}
+}
+
+// WithMaxResponseBytes truncates responses longer than n bytes, ending them
+// with a marker that tells the agent the response was cut.
+func WithMaxResponseBytes(n int) Option {
+	return func(c *OpenAPIConnector) {
+		c.shaper.maxBytes = n
+	}
+}
+
 // NewFromFile creates an OpenAPIConnector from a file path.
 func NewFromFile(path string, opts ...Option) (*OpenAPIConnector, error) {
 	data, err := os.ReadFile(path)
@@ -243,6 +273,7 @@ func NewFromBytes(data []byte, opts ...Option) (*OpenAPIConnector, error) {
 		spec:       &spec,
 		httpClient: http.DefaultClient,
 		handlers:   make(map[string]ToolHandler),
+		pagination: make(map[string]*Pagination),
 	}
 
 	// Set base URL from spec if available
@@ -274,7 +305,11 @@ func (c *OpenAPIConnector) Execute(ctx context.Context, name string, args map[st
 	if !ok {
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
-	return handler(ctx, args)
+	result, err := handler(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.shaper.truncate(result), nil
 }
 
 // ExecuteJSON runs a tool with JSON string arguments.
@@ -326,6 +361,12 @@ func (c *OpenAPIConnector) addOperation(path, method string, op *Operation) {
 		desc = fmt.Sprintf("%s %s", method, path)
 	}
 
+	pagination := detectPagination(op)
+	if pagination != nil {
+		c.pagination[name] = pagination
+		desc += fmt.Sprintf(" Results are paginated by %q; when the result has next_page, call again adding those arguments to get more.", pagination.Param)
+	}
+
 	// Build parameters schema
 	properties := make(map[string]interface{})
 	required := []string{}
@@ -372,7 +413,7 @@ func (c *OpenAPIConnector) addOperation(path, method string, op *Operation) {
 	c.tools = append(c.tools, tool)
 
 	// Create the handler
-	c.handlers[name] = c.createHandler(path, method, op)
+	c.handlers[name] = c.createHandler(name, path, method, op, pagination)
 }
 
 // paramToSchema converts a parameter to a JSON Schema map.
@@ -437,108 +478,131 @@ func (c *OpenAPIConnector) schemaToMap(schema *Schema) map[string]interface{} {
 	return result
 }
 
-// createHandler creates an HTTP handler for an operation.
-func (c *OpenAPIConnector) createHandler(path, method string, op *Operation) ToolHandler {
+// createHandler creates an HTTP handler for an operation. Paginated
+// operations return the next page arguments or follow the pages, see
+// paginate. Responses are projected as set by WithResponseProjection and
+// requests made under the policy set by WithHTTPResilience.
+func (c *OpenAPIConnector) createHandler(name, path, method string, op *Operation, pagination *Pagination) ToolHandler {
+	fetch := func(ctx context.Context, args map[string]interface{}) ([]byte, http.Header, error) {
+		return c.resilientRequest(ctx, method, func(ctx context.Context) ([]byte, http.Header, error) {
+			return c.doRequest(ctx, name, path, method, op, args)
+		})
+	}
 	return func(ctx context.Context, args map[string]interface{}) (any, error) {
-		// Build URL with path parameters
-		finalPath := path
-		queryParams := url.Values{}
-		headers := http.Header{}
-		var bodyData []byte
-
-		for _, param := range op.Parameters {
-			value, ok := args[param.Name]
-			if !ok {
-				continue
-			}
-			strValue := fmt.Sprintf("%v", value)
-
-			switch param.In {
-			case "path":
-				finalPath = strings.ReplaceAll(finalPath, "{"+param.Name+"}", strValue)
-			case "query":
-				queryParams.Set(param.Name, strValue)
-			case "header":
-				headers.Set(param.Name, strValue)
-			}
+		respBody, header, err := fetch(ctx, args)
+		if err != nil {
+			return c.withFallback(ctx, nil, err)
 		}
+		if pagination == nil {
+			return string(c.shaper.projectBody(name, respBody)), nil
+		}
+		result, err := c.paginate(ctx, name, pagination, args, respBody, header, fetch)
+		return c.withFallback(ctx, result, err)
+	}
+}
 
-		// Handle body
-		if op.RequestBody != nil {
-			// Check if there's a 'body' argument or extract body fields
-			if body, ok := args["body"]; ok {
-				bodyData, _ = json.Marshal(body)
-			} else {
-				// Extract body fields from args
-				bodyArgs := make(map[string]interface{})
-				for key, value := range args {
-					isParam := false
-					for _, param := range op.Parameters {
-						if param.Name == key {
-							isParam = true
-							break
-						}
-					}
-					if !isParam {
-						bodyArgs[key] = value
+// doRequest performs the HTTP request of an operation and returns the
+// response body and headers. Failed calls return a *errors.KairosError.
+func (c *OpenAPIConnector) doRequest(ctx context.Context, name, path, method string, op *Operation, args map[string]interface{}) ([]byte, http.Header, error) {
+	// Build URL with path parameters
+	finalPath := path
+	queryParams := url.Values{}
+	headers := http.Header{}
+	var bodyData []byte
+
+	for _, param := range op.Parameters {
+		value, ok := args[param.Name]
+		if !ok {
+			continue
+		}
+		strValue := fmt.Sprintf("%v", value)
+
+		switch param.In {
+		case "path":
+			finalPath = strings.ReplaceAll(finalPath, "{"+param.Name+"}", strValue)
+		case "query":
+			queryParams.Set(param.Name, strValue)
+		case "header":
+			headers.Set(param.Name, strValue)
+		}
+	}
+
+	// Handle body
+	if op.RequestBody != nil {
+		// Check if there's a 'body' argument or extract body fields
+		if body, ok := args["body"]; ok {
+			bodyData, _ = json.Marshal(body)
+		} else {
+			// Extract body fields from args
+			bodyArgs := make(map[string]interface{})
+			for key, value := range args {
+				isParam := false
+				for _, param := range op.Parameters {
+					if param.Name == key {
+						isParam = true
+						break
 					}
 				}
-				if len(bodyArgs) > 0 {
-					bodyData, _ = json.Marshal(bodyArgs)
+				if !isParam {
+					bodyArgs[key] = value
 				}
 			}
+			if len(bodyArgs) > 0 {
+				bodyData, _ = json.Marshal(bodyArgs)
+			}
 		}
+	}
 
-		// Build final URL
-		finalURL := c.baseURL + finalPath
-		if len(queryParams) > 0 {
-			finalURL += "?" + queryParams.Encode()
-		}
+	// Build final URL
+	finalURL := c.baseURL + finalPath
+	if len(queryParams) > 0 {
+		finalURL += "?" + queryParams.Encode()
+	}
 
-		// Create request
-		var bodyReader io.Reader
-		if bodyData != nil {
-			bodyReader = strings.NewReader(string(bodyData))
-		}
+	// Create request
+	var bodyReader io.Reader
+	if bodyData != nil {
+		bodyReader = strings.NewReader(string(bodyData))
+	}
 
-		req, err := http.NewRequestWithContext(ctx, method, finalURL, bodyReader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
+	req, err := http.NewRequestWithContext(ctx, method, finalURL, bodyReader)
+	if err != nil {
+		return nil, nil, kerrors.New(kerrors.CodeInvalidInput, "failed to create request", err).
+			WithContext("operation", name)
+	}
 
-		// Set headers
-		for key, values := range headers {
-			for _, v := range values {
-				req.Header.Add(key, v)
-			}
-		}
-		if bodyData != nil {
-			req.Header.Set("Content-Type", "application/json")
+	// Set headers
+	for key, values := range headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
 		}
+	}
+	if bodyData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-		// Apply authentication
-		c.applyAuth(req)
-
-		// Execute request
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("request failed: %w", err)
-		}
-		defer resp.Body.Close()
+	// Apply authentication
+	c.applyAuth(req)
 
-		// Read response
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
-		}
+	// Execute request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, httpTransportError(ctx, name, err)
+	}
+	defer resp.Body.Close()
 
-		// Check for errors
-		if resp.StatusCode >= 400 {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-		}
+	// Read response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, httpTransportError(ctx, name, fmt.Errorf("failed to read response: %w", err))
+	}
 
-		return string(respBody), nil
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, nil, httpStatusError(name, resp.StatusCode, respBody)
 	}
+
+	return respBody, resp.Header, nil
 }
 
 // applyAuth applies authentication to a request.
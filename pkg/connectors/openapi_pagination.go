@@ -0,0 +1,340 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pagination styles inferred from the query parameters of an operation.
+const (
+	PaginationCursor = "cursor"
+	PaginationPage   = "page"
+	PaginationOffset = "offset"
+)
+
+// Pagination describes how an operation pages its results.
+type Pagination struct {
+	Style     string // PaginationCursor, PaginationPage or PaginationOffset
+	Param     string // query parameter that selects the page
+	SizeParam string // query parameter with the page size, if any
+}
+
+// Normalized (lower case, without "_" and "-") names of the query parameters
+// that select a page or its size.
+var (
+	cursorParamNames = map[string]bool{
+		"cursor": true, "nexttoken": true, "pagetoken": true, "nextpagetoken": true,
+		"continuationtoken": true, "startingafter": true, "after": true, "marker": true,
+	}
+	pageParamNames   = map[string]bool{"page": true, "pagenumber": true, "pagenum": true}
+	offsetParamNames = map[string]bool{"offset": true, "skip": true, "start": true}
+	sizeParamNames   = map[string]bool{
+		"limit": true, "pagesize": true, "perpage": true, "size": true,
+		"count": true, "maxresults": true, "top": true,
+	}
+
+	// Response fields holding the next cursor, the total of items and the
+	// total of pages.
+	nextCursorFields = []string{"nextcursor", "nexttoken", "nextpagetoken", "continuationtoken", "cursor"}
+	totalFields      = []string{"total", "totalcount", "totalitems", "totalresults", "count"}
+	totalPageFields  = []string{"totalpages", "pages", "lastpage", "pagecount"}
+
+	// Response fields holding the items of a page.
+	itemFields = []string{"data", "items", "results", "records", "entries", "values", "content"}
+
+	// Objects where APIs usually nest pagination metadata.
+	metaFields = []string{"meta", "pagination", "paging", "page", "links", "pageinfo"}
+)
+
+// WithAutoPaginate makes Execute follow the pages of paginated operations,
+// up to maxPages requests, and return the concatenated items.
+func WithAutoPaginate(maxPages int) Option {
+	return func(c *OpenAPIConnector) {
+		c.maxPages = maxPages
+	}
+}
+
+// Pagination returns how the named tool pages its results, or nil if its
+// operation has no pagination parameters.
+func (c *OpenAPIConnector) Pagination(name string) *Pagination {
+	return c.pagination[name]
+}
+
+// detectPagination infers the pagination style of op from its query
+// parameters. Cursor parameters win over page numbers, and those over
+// offsets.
+func detectPagination(op *Operation) *Pagination {
+	var cursor, page, offset, size string
+	for _, param := range op.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		name := normalizeFieldName(param.Name)
+		switch {
+		case cursorParamNames[name] && cursor == "":
+			cursor = param.Name
+		case pageParamNames[name] && page == "":
+			page = param.Name
+		case offsetParamNames[name] && offset == "":
+			offset = param.Name
+		case sizeParamNames[name] && size == "":
+			size = param.Name
+		}
+	}
+
+	switch {
+	case cursor != "":
+		return &Pagination{Style: PaginationCursor, Param: cursor, SizeParam: size}
+	case page != "":
+		return &Pagination{Style: PaginationPage, Param: page, SizeParam: size}
+	case offset != "":
+		return &Pagination{Style: PaginationOffset, Param: offset, SizeParam: size}
+	default:
+		return nil
+	}
+}
+
+// paginate turns the first response of a paginated operation into the tool
+// result. Without auto-pagination the decoded response is returned with the
+// arguments of the next page, if any; with it, pages are fetched until none
+// is left or maxPages is reached and their items concatenated. Pages are
+// projected after finding the next one, so projections need not keep the
+// pagination fields.
+func (c *OpenAPIConnector) paginate(ctx context.Context, name string, p *Pagination, args map[string]interface{}, body []byte, header http.Header, fetch func(context.Context, map[string]interface{}) ([]byte, http.Header, error)) (any, error) {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body), nil
+	}
+
+	next := p.nextPage(args, header, decoded)
+	decoded = c.shaper.project(name, decoded)
+	items := pageItems(decoded)
+	if c.maxPages <= 1 || items == nil {
+		if next == nil {
+			return string(c.shaper.projectBody(name, body)), nil
+		}
+		return map[string]interface{}{"result": decoded, "next_page": next}, nil
+	}
+
+	all := append([]interface{}{}, items...)
+	pages := 1
+	for next != nil && pages < c.maxPages {
+		pageArgs := make(map[string]interface{}, len(args)+1)
+		for k, v := range args {
+			pageArgs[k] = v
+		}
+		for k, v := range next {
+			pageArgs[k] = v
+		}
+
+		body, header, err := fetch(ctx, pageArgs)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", pages+1, err)
+		}
+		var page any
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("page %d: invalid JSON response: %w", pages+1, err)
+		}
+		pages++
+		following := p.nextPage(pageArgs, header, page)
+		all = append(all, pageItems(c.shaper.project(name, page))...)
+		if following != nil && fmt.Sprint(following) == fmt.Sprint(next) {
+			// The API returned the same cursor again; stop rather than loop.
+			following = nil
+		}
+		args, next = pageArgs, following
+	}
+
+	result := map[string]interface{}{"items": all, "pages": pages}
+	if next != nil {
+		result["next_page"] = next
+	}
+	return result, nil
+}
+
+// nextPage returns the arguments that select the page after the one in
+// body, or nil if it was the last one. A Link header with rel="next" wins
+// over the body.
+func (p *Pagination) nextPage(args map[string]interface{}, header http.Header, body any) map[string]interface{} {
+	if value, ok := linkNextParam(header, p.Param); ok {
+		return map[string]interface{}{p.Param: value}
+	}
+
+	items := pageItems(body)
+	obj, _ := body.(map[string]interface{})
+	if hasMore, ok := lookupField(obj, []string{"hasmore", "hasnextpage", "hasnext"}).(bool); ok && !hasMore {
+		return nil
+	}
+
+	switch p.Style {
+	case PaginationCursor:
+		if token := lookupField(obj, nextCursorFields); token != nil && token != "" && fmt.Sprint(token) != fmt.Sprint(args[p.Param]) {
+			return map[string]interface{}{p.Param: token}
+		}
+		if next, ok := lookupField(obj, []string{"next"}).(string); ok {
+			if value, ok := urlParam(next, p.Param); ok {
+				return map[string]interface{}{p.Param: value}
+			}
+		}
+		// Stripe-style: continue after the ID of the last item.
+		if name := normalizeFieldName(p.Param); (name == "startingafter" || name == "after") && len(items) > 0 {
+			if last, ok := items[len(items)-1].(map[string]interface{}); ok && last["id"] != nil {
+				if hasMore, _ := lookupField(obj, []string{"hasmore"}).(bool); hasMore {
+					return map[string]interface{}{p.Param: last["id"]}
+				}
+			}
+		}
+		return nil
+
+	case PaginationPage:
+		if len(items) == 0 || p.shortPage(args, len(items)) {
+			return nil
+		}
+		current := int64(1)
+		if n, ok := argInt(args[p.Param]); ok {
+			current = n
+		}
+		if total, ok := argInt(lookupField(obj, totalPageFields)); ok && current >= total {
+			return nil
+		}
+		return map[string]interface{}{p.Param: current + 1}
+
+	case PaginationOffset:
+		if len(items) == 0 || p.shortPage(args, len(items)) {
+			return nil
+		}
+		offset, _ := argInt(args[p.Param])
+		next := offset + int64(len(items))
+		if total, ok := argInt(lookupField(obj, totalFields)); ok && next >= total {
+			return nil
+		}
+		return map[string]interface{}{p.Param: next}
+	}
+	return nil
+}
+
+// shortPage reports whether a page came with fewer items than requested,
+// which marks the last page.
+func (p *Pagination) shortPage(args map[string]interface{}, items int) bool {
+	if p.SizeParam == "" {
+		return false
+	}
+	size, ok := argInt(args[p.SizeParam])
+	return ok && int64(items) < size
+}
+
+// pageItems returns the items of a page: the body itself if it is an array,
+// else its first array field among the usual names, else its only array
+// field. It returns nil if none is found.
+func pageItems(body any) []interface{} {
+	switch v := body.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		for _, name := range itemFields {
+			for key, value := range v {
+				if items, ok := value.([]interface{}); ok && normalizeFieldName(key) == name {
+					return items
+				}
+			}
+		}
+		var found []interface{}
+		for _, value := range v {
+			if items, ok := value.([]interface{}); ok {
+				if found != nil {
+					return nil
+				}
+				found = items
+			}
+		}
+		return found
+	}
+	return nil
+}
+
+// lookupField returns the first of names (normalized) found in obj or in
+// one of its metadata objects.
+func lookupField(obj map[string]interface{}, names []string) any {
+	if obj == nil {
+		return nil
+	}
+	for _, name := range names {
+		for key, value := range obj {
+			if normalizeFieldName(key) == name {
+				if _, nested := value.(map[string]interface{}); !nested {
+					return value
+				}
+			}
+		}
+	}
+	for _, meta := range metaFields {
+		for key, value := range obj {
+			if nested, ok := value.(map[string]interface{}); ok && normalizeFieldName(key) == meta {
+				if found := lookupField(nested, names); found != nil {
+					return found
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// linkNextParam returns param from the rel="next" URL of a Link header.
+func linkNextParam(header http.Header, param string) (string, bool) {
+	for _, link := range header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			segments := strings.Split(part, ";")
+			if len(segments) < 2 {
+				continue
+			}
+			isNext := false
+			for _, attr := range segments[1:] {
+				attr = strings.ReplaceAll(strings.TrimSpace(attr), " ", "")
+				if attr == `rel="next"` || attr == "rel=next" {
+					isNext = true
+				}
+			}
+			if !isNext {
+				continue
+			}
+			target := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			if value, ok := urlParam(target, param); ok {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// urlParam returns the query parameter param of a URL.
+func urlParam(rawURL, param string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	values := u.Query()
+	if !values.Has(param) {
+		return "", false
+	}
+	return values.Get(param), true
+}
+
+func argInt(v any) (int64, bool) {
+	if s, ok := v.(string); ok {
+		n, err := strconv.ParseInt(s, 10, 64)
+		return n, err == nil
+	}
+	return toInt64(v)
+}
+
+func normalizeFieldName(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const paginatedOpenAPISpec = `
+openapi: "3.0.0"
+info:
+  title: Paginated API
+  version: "1.0.0"
+paths:
+  /events:
+    get:
+      operationId: listEvents
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: page
+          in: query
+          schema:
+            type: integer
+        - name: per_page
+          in: query
+          schema:
+            type: integer
+  /orders:
+    get:
+      operationId: listOrders
+      parameters:
+        - name: offset
+          in: query
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          schema:
+            type: integer
+  /users:
+    get:
+      operationId: listUsers
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+`
+
+// newPaginatedServer serves seven items per collection: events by cursor,
+// pets by page and orders by offset.
+func newPaginatedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	const total = 7
+	items := func(from, n int) []int {
+		var out []int
+		for i := from; i < from+n && i < total; i++ {
+			out = append(out, i)
+		}
+		return out
+	}
+	query := func(r *http.Request, name string, def int) int {
+		if v, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil {
+			return v
+		}
+		return def
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/events":
+			from := query(r, "cursor", 0)
+			page := items(from, 3)
+			resp := map[string]interface{}{"data": page}
+			if next := from + len(page); next < total {
+				resp["meta"] = map[string]interface{}{"next_cursor": strconv.Itoa(next)}
+			}
+			json.NewEncoder(w).Encode(resp)
+		case "/pets":
+			page, size := query(r, "page", 1), query(r, "per_page", 3)
+			json.NewEncoder(w).Encode(items((page-1)*size, size))
+		case "/orders":
+			offset, limit := query(r, "offset", 0), query(r, "limit", 3)
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": items(offset, limit), "total": total})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDetectPagination(t *testing.T) {
+	connector, err := NewFromBytes([]byte(paginatedOpenAPISpec))
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+
+	cases := map[string]*Pagination{
+		"listEvents": {Style: PaginationCursor, Param: "cursor", SizeParam: "limit"},
+		"listPets":   {Style: PaginationPage, Param: "page", SizeParam: "per_page"},
+		"listOrders": {Style: PaginationOffset, Param: "offset", SizeParam: "limit"},
+		"listUsers":  nil,
+	}
+	for name, want := range cases {
+		got := connector.Pagination(name)
+		if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+			t.Errorf("%s: expected %+v, got %+v", name, want, got)
+		}
+	}
+}
+
+func TestPaginationNextPage(t *testing.T) {
+	server := newPaginatedServer(t)
+	connector, err := NewFromBytes([]byte(paginatedOpenAPISpec), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		tool string
+		args map[string]interface{}
+		next map[string]interface{}
+	}{
+		{"listEvents", map[string]interface{}{"limit": 3}, map[string]interface{}{"cursor": "3"}},
+		{"listPets", map[string]interface{}{"per_page": 3}, map[string]interface{}{"page": int64(2)}},
+		{"listOrders", map[string]interface{}{"limit": 3}, map[string]interface{}{"offset": int64(3)}},
+	}
+	for _, tc := range cases {
+		result, err := connector.Execute(ctx, tc.tool, tc.args)
+		if err != nil {
+			t.Fatalf("%s failed: %v", tc.tool, err)
+		}
+		out, ok := result.(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s: expected result with next_page, got %v", tc.tool, result)
+		}
+		if fmt.Sprint(out["next_page"]) != fmt.Sprint(tc.next) {
+			t.Errorf("%s: expected next_page %v, got %v", tc.tool, tc.next, out["next_page"])
+		}
+	}
+
+	// The last page is returned as is.
+	result, err := connector.Execute(ctx, "listPets", map[string]interface{}{"page": 3, "per_page": 3})
+	if err != nil {
+		t.Fatalf("listPets failed: %v", err)
+	}
+	if s, ok := result.(string); !ok || strings.TrimSpace(s) != "[6]" {
+		t.Errorf("expected raw last page, got %v", result)
+	}
+}
+
+func TestAutoPaginate(t *testing.T) {
+	server := newPaginatedServer(t)
+	connector, err := NewFromBytes([]byte(paginatedOpenAPISpec), WithBaseURL(server.URL), WithAutoPaginate(10))
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, tool := range []string{"listEvents", "listPets", "listOrders"} {
+		result, err := connector.Execute(ctx, tool, map[string]interface{}{"limit": 3, "per_page": 3})
+		if err != nil {
+			t.Fatalf("%s failed: %v", tool, err)
+		}
+		out := result.(map[string]interface{})
+		if got := fmt.Sprint(out["items"]); got != "[0 1 2 3 4 5 6]" {
+			t.Errorf("%s: expected all items, got %s", tool, got)
+		}
+		if out["pages"] != 3 || out["next_page"] != nil {
+			t.Errorf("%s: unexpected pages %v, next_page %v", tool, out["pages"], out["next_page"])
+		}
+	}
+
+	// maxPages bounds the requests and leaves the next page in the result.
+	connector, _ = NewFromBytes([]byte(paginatedOpenAPISpec), WithBaseURL(server.URL), WithAutoPaginate(2))
+	result, err := connector.Execute(ctx, "listEvents", map[string]interface{}{"limit": 3})
+	if err != nil {
+		t.Fatalf("listEvents failed: %v", err)
+	}
+	out := result.(map[string]interface{})
+	if len(out["items"].([]interface{})) != 6 || fmt.Sprint(out["next_page"]) != "map[cursor:6]" {
+		t.Errorf("unexpected bounded result: %v", out)
+	}
+}
+
+func TestPaginationLinkHeader(t *testing.T) {
+	p := &Pagination{Style: PaginationCursor, Param: "cursor"}
+	header := http.Header{}
+	header.Set("Link", `<https://api.example.com/events?cursor=abc>; rel="next", <https://api.example.com/events>; rel="first"`)
+
+	next := p.nextPage(nil, header, []interface{}{})
+	if next["cursor"] != "abc" {
+		t.Errorf("expected cursor from Link header, got %v", next)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/resilience"
+)
+
+// WithHTTPResilience retries failed calls with retry and guards them with
+// breaker, which may be nil. Server errors (5xx), 429 responses, timeouts and
+// transport errors are retried; other 4xx are not, and do not count as
+// breaker failures. Only idempotent methods (GET, HEAD, OPTIONS, PUT and
+// DELETE) are retried unless WithRetryNonIdempotent is set.
+func WithHTTPResilience(retry resilience.RetryConfig, breaker *resilience.CircuitBreaker) Option {
+	return func(c *OpenAPIConnector) {
+		c.retry = &retry
+		c.breaker = breaker
+	}
+}
+
+// WithRetryNonIdempotent also retries POST and PATCH operations. Use it only
+// when the API makes them safe to repeat, e.g. with idempotency keys.
+func WithRetryNonIdempotent() Option {
+	return func(c *OpenAPIConnector) {
+		c.retryAll = true
+	}
+}
+
+// WithRequestTimeout bounds each HTTP attempt; an exceeded timeout fails the
+// attempt with errors.CodeTimeout.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *OpenAPIConnector) {
+		c.requestTimeout = d
+	}
+}
+
+// WithHTTPFallback returns the result of fallback when a call fails because
+// the upstream is unavailable: after the retries of a recoverable error or
+// while the circuit breaker is open. Other errors are returned as is.
+func WithHTTPFallback(fallback resilience.FallbackStrategy) Option {
+	return func(c *OpenAPIConnector) {
+		c.fallback = fallback
+	}
+}
+
+// idempotentMethods are retried by default.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// resilientRequest runs request under the configured timeout, retry and
+// circuit breaker.
+func (c *OpenAPIConnector) resilientRequest(ctx context.Context, method string, request func(context.Context) ([]byte, http.Header, error)) ([]byte, http.Header, error) {
+	var body []byte
+	var header http.Header
+	attempt := func() error {
+		attemptCtx := ctx
+		if c.requestTimeout > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+			defer cancel()
+		}
+		var err error
+		body, header, err = request(attemptCtx)
+		return err
+	}
+
+	call := attempt
+	if c.retry != nil && (c.retryAll || idempotentMethods[method]) {
+		retry := *c.retry
+		call = func() error {
+			return retry.Do(ctx, attempt)
+		}
+	}
+
+	if c.breaker == nil {
+		err := call()
+		return body, header, err
+	}
+
+	// Client errors say nothing about the health of the upstream, so they
+	// are kept from the breaker.
+	var callErr error
+	if err := c.breaker.Call(ctx, func() error {
+		callErr = call()
+		if callErr != nil && !kerrors.IsRecoverable(callErr) {
+			return nil
+		}
+		return callErr
+	}); err != nil && callErr == nil {
+		return nil, nil, err
+	}
+	return body, header, callErr
+}
+
+// withFallback replaces a recoverable error with the configured fallback.
+func (c *OpenAPIConnector) withFallback(ctx context.Context, result any, err error) (any, error) {
+	if err == nil || c.fallback == nil || !kerrors.IsRecoverable(err) {
+		return result, err
+	}
+	return c.fallback.Execute(ctx, err)
+}
+
+// httpStatusError types an API error response. Server errors and 429 are
+// recoverable.
+func httpStatusError(operation string, status int, body []byte) error {
+	code := kerrors.CodeToolFailure
+	recoverable := status >= 500
+	switch {
+	case status == http.StatusTooManyRequests:
+		code, recoverable = kerrors.CodeRateLimit, true
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		code = kerrors.CodeUnauthorized
+	case status == http.StatusNotFound:
+		code = kerrors.CodeNotFound
+	case status < 500:
+		code = kerrors.CodeInvalidInput
+	}
+	return kerrors.New(code, fmt.Sprintf("API error (status %d): %s", status, string(body)), nil).
+		WithContext("operation", operation).
+		WithContext("status", status).
+		WithRecoverable(recoverable)
+}
+
+// httpTransportError types a failed HTTP round trip. Timeouts and network
+// errors are recoverable; a canceled caller context is not.
+func httpTransportError(ctx context.Context, operation string, err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return kerrors.New(kerrors.CodeTimeout, "request timed out", err).
+			WithContext("operation", operation).
+			WithRecoverable(true)
+	case ctx.Err() != nil:
+		return kerrors.New(kerrors.CodeContextLost, "request canceled", err).
+			WithContext("operation", operation).
+			WithRecoverable(false)
+	default:
+		return kerrors.New(kerrors.CodeToolFailure, "request failed", err).
+			WithContext("operation", operation).
+			WithRecoverable(true)
+	}
+}
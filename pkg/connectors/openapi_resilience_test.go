@@ -0,0 +1,124 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/resilience"
+)
+
+func fastRetry() resilience.RetryConfig {
+	return resilience.DefaultRetryConfig().
+		WithInitialDelay(time.Millisecond).
+		WithMaxDelay(5 * time.Millisecond)
+}
+
+// newFlakyServer fails the first failures calls with status and then
+// answers with an empty JSON object.
+func newFlakyServer(t *testing.T, status, failures int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= int32(failures) {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestHTTPResilienceRetriesIdempotent(t *testing.T) {
+	server, calls := newFlakyServer(t, http.StatusServiceUnavailable, 2)
+	connector, err := NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL), WithHTTPResilience(fastRetry(), nil))
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+
+	result, err := connector.Execute(context.Background(), "listUsers", nil)
+	if err != nil {
+		t.Fatalf("listUsers failed: %v", err)
+	}
+	if result != "{}" || atomic.LoadInt32(calls) != 3 {
+		t.Errorf("expected success on third call, got %v after %d calls", result, atomic.LoadInt32(calls))
+	}
+}
+
+func TestHTTPResilienceNonIdempotent(t *testing.T) {
+	args := map[string]interface{}{"name": "Ann", "email": "ann@example.com"}
+
+	server, calls := newFlakyServer(t, http.StatusInternalServerError, 1)
+	connector, _ := NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL), WithHTTPResilience(fastRetry(), nil))
+	_, err := connector.Execute(context.Background(), "createUser", args)
+	if code, _ := kerrors.CodeOf(err); code != kerrors.CodeToolFailure || atomic.LoadInt32(calls) != 1 {
+		t.Errorf("expected a single failed POST, got %v after %d calls", err, atomic.LoadInt32(calls))
+	}
+
+	server, calls = newFlakyServer(t, http.StatusInternalServerError, 1)
+	connector, _ = NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL),
+		WithHTTPResilience(fastRetry(), nil), WithRetryNonIdempotent())
+	if _, err := connector.Execute(context.Background(), "createUser", args); err != nil || atomic.LoadInt32(calls) != 2 {
+		t.Errorf("expected retried POST to succeed, got %v after %d calls", err, atomic.LoadInt32(calls))
+	}
+}
+
+func TestHTTPResilienceClientErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, http.StatusNotFound, 10)
+	connector, _ := NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL), WithHTTPResilience(fastRetry(), nil))
+
+	_, err := connector.Execute(context.Background(), "getUser", map[string]interface{}{"id": "x"})
+	ke := kerrors.AsKairosError(err)
+	if ke.Code != kerrors.CodeNotFound || ke.Recoverable || ke.Context["status"] != http.StatusNotFound {
+		t.Errorf("unexpected error: %#v", ke)
+	}
+	if atomic.LoadInt32(calls) != 1 {
+		t.Errorf("expected no retries on 404, got %d calls", atomic.LoadInt32(calls))
+	}
+}
+
+func TestHTTPResilienceBreakerFallback(t *testing.T) {
+	server, calls := newFlakyServer(t, http.StatusBadGateway, 100)
+	breaker := resilience.NewCircuitBreaker(resilience.CircuitBreakerConfig{FailureThreshold: 2, Timeout: time.Minute})
+	connector, _ := NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL),
+		WithHTTPResilience(fastRetry().WithMaxAttempts(1), breaker),
+		WithHTTPFallback(&resilience.StaticFallback{Value: "cached users"}),
+	)
+
+	for i := 0; i < 3; i++ {
+		result, err := connector.Execute(context.Background(), "listUsers", nil)
+		if err != nil || result != "cached users" {
+			t.Fatalf("call %d: expected fallback, got %v, %v", i, result, err)
+		}
+	}
+	if breaker.State() != resilience.StateOpen {
+		t.Errorf("expected open breaker, got %s", breaker.State())
+	}
+	if atomic.LoadInt32(calls) != 2 {
+		t.Errorf("expected the open breaker to skip the upstream, got %d calls", atomic.LoadInt32(calls))
+	}
+}
+
+func TestHTTPRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	connector, _ := NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL), WithRequestTimeout(20*time.Millisecond))
+	_, err := connector.Execute(context.Background(), "listUsers", nil)
+	if code, _ := kerrors.CodeOf(err); code != kerrors.CodeTimeout || !kerrors.IsRecoverable(err) {
+		t.Errorf("expected recoverable timeout, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// responseShaper shrinks connector responses before the agent sees them:
+// it keeps only the configured JSON paths of an operation's response and
+// caps the size of any response.
+type responseShaper struct {
+	projections map[string][][]string
+	maxBytes    int
+}
+
+// setProjection keeps only paths in the responses of operation. Paths are
+// dot-separated keys ("owner.name"); "*" or "[]" selects every element of
+// an array or object, and keys applied to an array apply to each element.
+// A leading "$" is ignored.
+func (s *responseShaper) setProjection(operation string, paths []string) {
+	if s.projections == nil {
+		s.projections = make(map[string][][]string)
+	}
+	parsed := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+		var segments []string
+		for _, segment := range strings.Split(path, ".") {
+			// "items[]" is "items" followed by "[]".
+			for strings.HasSuffix(segment, "[]") && segment != "[]" {
+				segments = append(segments, strings.TrimSuffix(segment, "[]"))
+				segment = "[]"
+			}
+			if segment != "" {
+				segments = append(segments, segment)
+			}
+		}
+		parsed = append(parsed, segments)
+	}
+	s.projections[operation] = parsed
+}
+
+// projectable reports whether operation has a projection.
+func (s *responseShaper) projectable(operation string) bool {
+	_, ok := s.projections[operation]
+	return ok
+}
+
+// project applies the projection of operation to a decoded JSON value.
+func (s *responseShaper) project(operation string, v any) any {
+	paths, ok := s.projections[operation]
+	if !ok {
+		return v
+	}
+	projected, ok := projectJSON(v, paths)
+	if !ok {
+		return nil
+	}
+	return projected
+}
+
+// projectBody applies the projection of operation to a JSON body. Bodies
+// that are not JSON are returned unchanged.
+func (s *responseShaper) projectBody(operation string, body []byte) []byte {
+	if !s.projectable(operation) {
+		return body
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return body
+	}
+	projected, err := json.Marshal(s.project(operation, decoded))
+	if err != nil {
+		return body
+	}
+	return projected
+}
+
+// truncate caps result at maxBytes. Strings are cut as is and other values
+// encoded as JSON first; cut results end with a marker saying so.
+func (s *responseShaper) truncate(result any) any {
+	if s.maxBytes <= 0 || result == nil {
+		return result
+	}
+	text, ok := result.(string)
+	if !ok {
+		encoded, err := json.Marshal(result)
+		if err != nil || len(encoded) <= s.maxBytes {
+			return result
+		}
+		text = string(encoded)
+	}
+	if len(text) <= s.maxBytes {
+		return text
+	}
+	cut := s.maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + fmt.Sprintf("\n[truncated: response is %d bytes, showing the first %d]", len(text), cut)
+}
+
+// projectJSON keeps the parts of v selected by paths. It reports false when
+// no path matches.
+func projectJSON(v any, paths [][]string) (any, bool) {
+	for _, path := range paths {
+		if len(path) == 0 {
+			return v, true
+		}
+	}
+
+	switch value := v.(type) {
+	case []interface{}:
+		rest := make([][]string, 0, len(paths))
+		for _, path := range paths {
+			if path[0] == "*" || path[0] == "[]" {
+				path = path[1:]
+			}
+			rest = append(rest, path)
+		}
+		out := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			if projected, ok := projectJSON(item, rest); ok {
+				out = append(out, projected)
+			}
+		}
+		return out, len(out) > 0 || len(value) == 0
+
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for key, item := range value {
+			var rest [][]string
+			for _, path := range paths {
+				if path[0] == key || path[0] == "*" || path[0] == "[]" {
+					rest = append(rest, path[1:])
+				}
+			}
+			if len(rest) == 0 {
+				continue
+			}
+			if projected, ok := projectJSON(item, rest); ok {
+				out[key] = projected
+			}
+		}
+		return out, len(out) > 0
+	}
+
+	return nil, false
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseProjection(t *testing.T) {
+	var body any
+	json.Unmarshal([]byte(`{
+		"data": [
+			{"id": 1, "name": "Max", "owner": {"name": "Ann", "email": "ann@example.com"}},
+			{"id": 2, "name": "Luna", "tags": ["cat"]}
+		],
+		"meta": {"total": 2}
+	}`), &body)
+
+	cases := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"data.id"}, `{"data":[{"id":1},{"id":2}]}`},
+		{[]string{"$.data[].name", "meta.total"}, `{"data":[{"name":"Max"},{"name":"Luna"}],"meta":{"total":2}}`},
+		{[]string{"data.*.owner.name"}, `{"data":[{"owner":{"name":"Ann"}}]}`},
+		{[]string{"missing"}, `null`},
+	}
+	for _, tc := range cases {
+		var s responseShaper
+		s.setProjection("op", tc.paths)
+		got, _ := json.Marshal(s.project("op", body))
+		if string(got) != tc.want {
+			t.Errorf("%v: expected %s, got %s", tc.paths, tc.want, got)
+		}
+	}
+
+	var s responseShaper
+	if got := string(s.projectBody("other", []byte(`{"a":1}`))); got != `{"a":1}` {
+		t.Errorf("expected operations without projection unchanged, got %s", got)
+	}
+}
+
+func TestResponseTruncate(t *testing.T) {
+	s := responseShaper{maxBytes: 10}
+
+	if got := s.truncate("short"); got != "short" {
+		t.Errorf("expected short response unchanged, got %v", got)
+	}
+	got, ok := s.truncate(strings.Repeat("é", 20)).(string)
+	if !ok || !strings.HasPrefix(got, strings.Repeat("é", 5)+"\n[truncated: response is 40 bytes") {
+		t.Errorf("unexpected truncated string: %q", got)
+	}
+	got, ok = s.truncate(map[string]interface{}{"key": "a long value"}).(string)
+	if !ok || !strings.HasPrefix(got, `{"key":"a `) || !strings.Contains(got, "[truncated:") {
+		t.Errorf("unexpected truncated value: %q", got)
+	}
+}
+
+func TestOpenAPIResponseShaping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]string{
+			{"id": "1", "name": "Alice", "bio": strings.Repeat("x", 100)},
+			{"id": "2", "name": "Bob", "bio": strings.Repeat("y", 100)},
+		})
+	}))
+	defer server.Close()
+
+	connector, err := NewFromBytes([]byte(testOpenAPISpec),
+		WithBaseURL(server.URL),
+		WithResponseProjection("listUsers", []string{"id", "name"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	result, err := connector.Execute(context.Background(), "listUsers", nil)
+	if err != nil {
+		t.Fatalf("listUsers failed: %v", err)
+	}
+	if result != `[{"id":"1","name":"Alice"},{"id":"2","name":"Bob"}]` {
+		t.Errorf("unexpected projected result: %v", result)
+	}
+
+	connector, _ = NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL), WithMaxResponseBytes(64))
+	result, err = connector.Execute(context.Background(), "listUsers", nil)
+	if err != nil {
+		t.Fatalf("listUsers failed: %v", err)
+	}
+	if s := result.(string); !strings.Contains(s, "[truncated:") || len(s) > 128 {
+		t.Errorf("expected truncated result, got %q", s)
+	}
+}
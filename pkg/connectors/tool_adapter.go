@@ -20,12 +20,48 @@ type Connector interface {
 	Execute(ctx context.Context, toolName string, args map[string]any) (any, error)
 }
 
+// JSONExecutor is implemented by connectors that accept tool arguments as
+// the raw JSON produced by the LLM.
+type JSONExecutor interface {
+	ExecuteJSON(ctx context.Context, toolName, argsJSON string) (any, error)
+}
+
+// AsTools returns the operations of connector as tools ready for
+// agent.WithTools. A tool call is routed to the connector operation with the
+// same name: connectors implementing JSONExecutor (such as OpenAPIConnector)
+// receive the arguments as JSON through ExecuteJSON, others through Execute.
+func AsTools(connector Connector) []core.Tool {
+	if connector == nil {
+		return nil
+	}
+	jsonExec, _ := connector.(JSONExecutor)
+	source := connector.Tools()
+	tools := make([]core.Tool, 0, len(source))
+	for _, tool := range source {
+		if tool == nil || strings.TrimSpace(tool.Name()) == "" {
+			continue
+		}
+		def := tool.ToolDefinition()
+		if def.Type == "" {
+			def.Type = llm.ToolTypeFunction
+		}
+		tools = append(tools, &toolAdapter{
+			name:         tool.Name(),
+			definition:   def,
+			executor:     connector,
+			jsonExecutor: jsonExec,
+		})
+	}
+	return tools
+}
+
 type toolAdapter struct {
 	name       string
 	definition llm.Tool
 	executor   interface {
 		Execute(ctx context.Context, toolName string, args map[string]any) (any, error)
 	}
+	jsonExecutor JSONExecutor
 }
 
 func (t *toolAdapter) Name() string {
@@ -44,6 +80,13 @@ func (t *toolAdapter) Call(ctx context.Context, input any) (any, error) {
 	if err != nil {
 		return nil, err
 	}
+	if t.jsonExecutor != nil {
+		encoded, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("connector tool args: %w", err)
+		}
+		return t.jsonExecutor.ExecuteJSON(ctx, t.name, string(encoded))
+	}
 	return t.executor.Execute(ctx, t.name, args)
 }
 
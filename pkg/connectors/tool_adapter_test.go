@@ -0,0 +1,96 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jllopis/kairos/pkg/agent"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// toolCallingProvider asks for one tool call and then answers with the
+// observation it received.
+type toolCallingProvider struct {
+	call  llm.FunctionCall
+	calls int
+}
+
+func (p *toolCallingProvider) Chat(_ context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.calls++
+	if p.calls == 1 {
+		return &llm.ChatResponse{ToolCalls: []llm.ToolCall{{
+			ID:       "call-1",
+			Type:     llm.ToolTypeFunction,
+			Function: p.call,
+		}}}, nil
+	}
+	last := req.Messages[len(req.Messages)-1]
+	return &llm.ChatResponse{Content: "Final Answer: " + last.Content}, nil
+}
+
+// recordingJSONConnector records which execution path a call took.
+type recordingJSONConnector struct {
+	*OpenAPIConnector
+	jsonArgs []string
+}
+
+func (c *recordingJSONConnector) ExecuteJSON(ctx context.Context, name, argsJSON string) (any, error) {
+	c.jsonArgs = append(c.jsonArgs, argsJSON)
+	return c.OpenAPIConnector.ExecuteJSON(ctx, name, argsJSON)
+}
+
+func TestAsToolsRoutesAgentToolCalls(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]string{"id": "1", "name": "Alice"})
+	}))
+	defer server.Close()
+
+	openapi, err := NewFromBytes([]byte(testOpenAPISpec), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	connector := &recordingJSONConnector{OpenAPIConnector: openapi}
+
+	tools := AsTools(connector)
+	if len(tools) != len(openapi.Tools()) {
+		t.Fatalf("expected %d tools, got %d", len(openapi.Tools()), len(tools))
+	}
+
+	provider := &toolCallingProvider{call: llm.FunctionCall{Name: "getUser", Arguments: `{"id":"1"}`}}
+	a, err := agent.New("api-agent", provider,
+		agent.WithTools(tools...),
+		agent.WithDisableActionFallback(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	result, err := a.Run(context.Background(), "Who is user 1?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(paths) != 1 || paths[0] != "GET /users/1" {
+		t.Fatalf("unexpected requests: %v", paths)
+	}
+	if len(connector.jsonArgs) != 1 || connector.jsonArgs[0] != `{"id":"1"}` {
+		t.Fatalf("expected call through ExecuteJSON, got %v", connector.jsonArgs)
+	}
+	if !strings.Contains(result.(string), "Alice") {
+		t.Fatalf("expected connector response in result, got %v", result)
+	}
+}
+
+func TestAsToolsNilConnector(t *testing.T) {
+	if tools := AsTools(nil); tools != nil {
+		t.Fatalf("expected no tools, got %v", tools)
+	}
+}
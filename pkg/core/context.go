@@ -11,6 +11,7 @@ type runIDKey struct{}
 type memoryKey struct{}
 type taskKey struct{}
 type sessionIDKey struct{}
+type eventEmitterKey struct{}
 
 // WithRunID attaches a run id to the context.
 func WithRunID(ctx context.Context, id string) context.Context {
@@ -54,6 +55,19 @@ func TaskFromContext(ctx context.Context) (*Task, bool) {
 	return task, ok
 }
 
+// WithEventEmitter attaches an event emitter to the context, so components
+// called during a run (such as A2A clients used by tools) can report events
+// to the caller's listeners.
+func WithEventEmitter(ctx context.Context, emitter EventEmitter) context.Context {
+	return context.WithValue(ctx, eventEmitterKey{}, emitter)
+}
+
+// EventEmitterFromContext returns the event emitter if present.
+func EventEmitterFromContext(ctx context.Context) (EventEmitter, bool) {
+	emitter, ok := ctx.Value(eventEmitterKey{}).(EventEmitter)
+	return emitter, ok && emitter != nil
+}
+
 // WithSessionID attaches a conversation session id to the context.
 func WithSessionID(ctx context.Context, sessionID string) context.Context {
 	return context.WithValue(ctx, sessionIDKey{}, sessionID)
@@ -9,11 +9,13 @@ import (
 type EventType string
 
 const (
-	EventAgentThinking      EventType = "agent.thinking"
-	EventAgentTaskStarted   EventType = "agent.task.started"
-	EventAgentTaskCompleted EventType = "agent.task.completed"
-	EventAgentDelegation    EventType = "agent.delegation"
-	EventAgentError         EventType = "agent.error"
+	EventAgentThinking          EventType = "agent.thinking"
+	EventAgentTaskStarted       EventType = "agent.task.started"
+	EventAgentTaskCompleted     EventType = "agent.task.completed"
+	EventAgentToolCallStarted   EventType = "agent.tool_call.started"
+	EventAgentToolCallCompleted EventType = "agent.tool_call.completed"
+	EventAgentDelegation        EventType = "agent.delegation"
+	EventAgentError             EventType = "agent.error"
 )
 
 // Event captures a semantic streaming/logging event.
@@ -30,6 +32,14 @@ type EventEmitter interface {
 	Emit(ctx context.Context, event Event)
 }
 
+// EventEmitterFunc adapts a function to EventEmitter.
+type EventEmitterFunc func(ctx context.Context, event Event)
+
+// Emit implements EventEmitter.
+func (f EventEmitterFunc) Emit(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
 // NoopEventEmitter is a default no-op implementation.
 type NoopEventEmitter struct{}
 
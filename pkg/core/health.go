@@ -22,6 +22,25 @@ const (
 	HealthUnhealthy HealthStatus = "UNHEALTHY"
 )
 
+// Criticality tells how a checker's failure affects the overall status.
+type Criticality int
+
+const (
+	// Critical checkers make the overall status Unhealthy when they fail.
+	Critical Criticality = iota
+
+	// Optional checkers degrade the overall status at most.
+	Optional
+)
+
+// String returns "critical" or "optional".
+func (c Criticality) String() string {
+	if c == Optional {
+		return "optional"
+	}
+	return "critical"
+}
+
 // HealthResult represents the result of a health check.
 type HealthResult struct {
 	Status    HealthStatus
@@ -40,8 +59,10 @@ type HealthChecker interface {
 
 // HealthCheckProvider provides health check results for multiple components.
 type HealthCheckProvider interface {
-	// RegisterChecker registers a health checker for a component.
-	RegisterChecker(name string, checker HealthChecker)
+	// RegisterChecker registers a health checker for a component. The
+	// optional criticality (Critical by default) decides whether its
+	// failure makes the overall status Unhealthy or only Degraded.
+	RegisterChecker(name string, checker HealthChecker, criticality ...Criticality)
 
 	// CheckAll checks the health of all registered components.
 	// Returns individual results and overall status.
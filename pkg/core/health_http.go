@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Package core provides core interfaces for Kairos.
+// See docs/ERROR_HANDLING.md for health check integration.
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// healthResponse is the JSON body written by the probe handlers.
+type healthResponse struct {
+	Status     HealthStatus          `json:"status"`
+	Components []componentHealthJSON `json:"components,omitempty"`
+}
+
+type componentHealthJSON struct {
+	Name        string       `json:"name"`
+	Status      HealthStatus `json:"status"`
+	Criticality string       `json:"criticality"`
+	Message     string       `json:"message,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// LivenessHandler returns an http.Handler for liveness probes (e.g.
+// /healthz). It answers 200 as long as the process can serve requests and
+// does not run the checkers, so a failing dependency never gets the process
+// restarted.
+func (p *DefaultHealthCheckProvider) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, http.StatusOK, healthResponse{Status: HealthHealthy})
+	})
+}
+
+// ReadinessHandler returns an http.Handler for readiness probes (e.g.
+// /readyz). It runs CheckAll and answers 200 when the overall status is
+// Healthy or Degraded and 503 when it is Unhealthy, with the per-component
+// results as JSON.
+func (p *DefaultHealthCheckProvider) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results, overall := p.CheckAll(r.Context())
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Component < results[j].Component
+		})
+
+		resp := healthResponse{Status: overall}
+		for _, result := range results {
+			component := componentHealthJSON{
+				Name:        result.Component,
+				Status:      result.Status,
+				Criticality: p.Criticality(result.Component).String(),
+				Message:     result.Message,
+			}
+			if result.Error != nil {
+				component.Error = result.Error.Error()
+			}
+			resp.Components = append(resp.Components, component)
+		}
+
+		code := http.StatusOK
+		if overall == HealthUnhealthy {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealth(w, code, resp)
+	})
+}
+
+func writeHealth(w http.ResponseWriter, code int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
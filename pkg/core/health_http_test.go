@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serveProbe(t *testing.T, h http.Handler) (int, healthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var resp healthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func TestReadinessHandler(t *testing.T) {
+	provider := NewDefaultHealthCheckProvider(10 * time.Second)
+	provider.RegisterChecker("llm", NewSimpleHealthChecker(HealthHealthy, "ok"))
+	provider.RegisterChecker("cache", NewSimpleHealthChecker(HealthUnhealthy, "down"), Optional)
+
+	code, resp := serveProbe(t, provider.ReadinessHandler())
+	if code != http.StatusOK || resp.Status != HealthDegraded {
+		t.Fatalf("expected 200 and Degraded, got %d %v", code, resp.Status)
+	}
+	if len(resp.Components) != 2 || resp.Components[0].Name != "cache" || resp.Components[0].Criticality != "optional" {
+		t.Fatalf("unexpected components: %+v", resp.Components)
+	}
+
+	provider.RegisterChecker("llm", NewSimpleHealthChecker(HealthUnhealthy, "down"))
+	code, resp = serveProbe(t, provider.ReadinessHandler())
+	if code != http.StatusServiceUnavailable || resp.Status != HealthUnhealthy {
+		t.Fatalf("expected 503 and Unhealthy, got %d %v", code, resp.Status)
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	provider := NewDefaultHealthCheckProvider(10 * time.Second)
+	provider.RegisterChecker("llm", NewSimpleHealthChecker(HealthUnhealthy, "down"))
+
+	code, resp := serveProbe(t, provider.LivenessHandler())
+	if code != http.StatusOK || resp.Status != HealthHealthy {
+		t.Fatalf("expected 200 and Healthy, got %d %v", code, resp.Status)
+	}
+}
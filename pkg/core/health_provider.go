@@ -12,10 +12,11 @@ import (
 
 // DefaultHealthCheckProvider implements HealthCheckProvider.
 type DefaultHealthCheckProvider struct {
-	checkers map[string]HealthChecker
-	mu       sync.RWMutex
-	cache    map[string]HealthResult
-	cacheTTL time.Duration
+	checkers    map[string]HealthChecker
+	criticality map[string]Criticality
+	mu          sync.RWMutex
+	cache       map[string]HealthResult
+	cacheTTL    time.Duration
 }
 
 // NewDefaultHealthCheckProvider creates a new health check provider.
@@ -24,17 +25,30 @@ func NewDefaultHealthCheckProvider(cacheTTL time.Duration) *DefaultHealthCheckPr
 		cacheTTL = 10 * time.Second
 	}
 	return &DefaultHealthCheckProvider{
-		checkers: make(map[string]HealthChecker),
-		cache:    make(map[string]HealthResult),
-		cacheTTL: cacheTTL,
+		checkers:    make(map[string]HealthChecker),
+		criticality: make(map[string]Criticality),
+		cache:       make(map[string]HealthResult),
+		cacheTTL:    cacheTTL,
 	}
 }
 
-// RegisterChecker registers a health checker for a component.
-func (p *DefaultHealthCheckProvider) RegisterChecker(name string, checker HealthChecker) {
+// RegisterChecker registers a health checker for a component. Checkers are
+// Critical unless Optional is passed.
+func (p *DefaultHealthCheckProvider) RegisterChecker(name string, checker HealthChecker, criticality ...Criticality) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.checkers[name] = checker
+	p.criticality[name] = Critical
+	if len(criticality) > 0 {
+		p.criticality[name] = criticality[0]
+	}
+}
+
+// Criticality returns the criticality a component was registered with.
+func (p *DefaultHealthCheckProvider) Criticality(name string) Criticality {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.criticality[name]
 }
 
 // Check checks the health of a specific component.
@@ -51,7 +65,9 @@ func (p *DefaultHealthCheckProvider) Check(ctx context.Context, name string) (He
 }
 
 // CheckAll checks the health of all registered components.
-// Returns individual results and overall status (Healthy only if all Healthy).
+// Returns individual results and overall status: Healthy only if all are
+// Healthy, Unhealthy only if a Critical checker is Unhealthy, and Degraded
+// otherwise. This overall status is what ReadinessHandler reports.
 func (p *DefaultHealthCheckProvider) CheckAll(ctx context.Context) ([]HealthResult, HealthStatus) {
 	p.mu.RLock()
 	checkerCount := len(p.checkers)
@@ -74,7 +90,11 @@ func (p *DefaultHealthCheckProvider) CheckAll(ctx context.Context) ([]HealthResu
 		case HealthDegraded:
 			degradedCount++
 		case HealthUnhealthy:
-			unhealthyCount++
+			if p.Criticality(name) == Optional {
+				degradedCount++
+			} else {
+				unhealthyCount++
+			}
 		}
 	}
 
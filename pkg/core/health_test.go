@@ -133,6 +133,26 @@ func TestDefaultHealthCheckProviderHealthy(t *testing.T) {
 	}
 }
 
+func TestDefaultHealthCheckProviderOptionalUnhealthy(t *testing.T) {
+	provider := NewDefaultHealthCheckProvider(10 * time.Second)
+
+	provider.RegisterChecker("llm", NewSimpleHealthChecker(HealthHealthy, "ok"), Critical)
+	provider.RegisterChecker("cache", NewSimpleHealthChecker(HealthUnhealthy, "down"), Optional)
+
+	// An Optional failure only degrades the overall status
+	if _, overallStatus := provider.CheckAll(context.Background()); overallStatus != HealthDegraded {
+		t.Errorf("expected Degraded overall, got %v", overallStatus)
+	}
+	if provider.Criticality("cache") != Optional || provider.Criticality("llm") != Critical {
+		t.Errorf("unexpected criticality: cache=%v llm=%v", provider.Criticality("cache"), provider.Criticality("llm"))
+	}
+
+	provider.RegisterChecker("llm", NewSimpleHealthChecker(HealthUnhealthy, "down"))
+	if _, overallStatus := provider.CheckAll(context.Background()); overallStatus != HealthUnhealthy {
+		t.Errorf("expected Unhealthy overall, got %v", overallStatus)
+	}
+}
+
 func TestCheckSpecific(t *testing.T) {
 	provider := NewDefaultHealthCheckProvider(10 * time.Second)
 	provider.RegisterChecker("service", NewSimpleHealthChecker(HealthHealthy, "ok"))
@@ -2,11 +2,23 @@ package core
 
 // RoleManifest captures semantic role metadata for an agent.
 type RoleManifest struct {
-	Role           string
-	Responsibility string
-	Inputs         []string
-	Outputs        []string
-	Constraints    map[string]any
+	Role           string         `json:"role" yaml:"role"`
+	Responsibility string         `json:"responsibility,omitempty" yaml:"responsibility,omitempty"`
+	Inputs         []string       `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Outputs        []string       `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Constraints    map[string]any `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	// AllowedTools lists the tools the role is expected to use.
+	AllowedTools []string `json:"allowed_tools,omitempty" yaml:"allowed_tools,omitempty"`
+	// OutputFormat hints how answers should be shaped (e.g. "markdown", "csv").
+	OutputFormat string `json:"output_format,omitempty" yaml:"output_format,omitempty"`
+	// Examples show sample exchanges for the role.
+	Examples []RoleExample `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// RoleExample is a sample input/output pair for a role.
+type RoleExample struct {
+	Input  string `json:"input" yaml:"input"`
+	Output string `json:"output" yaml:"output"`
 }
 
 // RoleManifestProvider exposes role metadata for an agent.
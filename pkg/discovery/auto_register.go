@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jllopis/kairos/pkg/config"
+	klog "github.com/jllopis/kairos/pkg/log"
 )
 
 const defaultHeartbeat = 10 * time.Second
@@ -23,7 +24,7 @@ func StartAutoRegister(ctx context.Context, provider *RegistryProvider, endpoint
 		interval = defaultHeartbeat
 	}
 	ctx, cancel := context.WithCancel(ctx)
-	logger := slog.Default()
+	logger := klog.For("discovery")
 
 	register := func() {
 		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -5,7 +5,10 @@ package errors
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+
+	"google.golang.org/grpc/status"
 )
 
 // ErrorCode classifies Kairos errors for monitoring and recovery.
@@ -41,10 +44,15 @@ const (
 
 	// CodeLLMError indicates an LLM provider error.
 	CodeLLMError ErrorCode = "LLM_ERROR"
+
+	// CodeBudgetExceeded indicates a configured resource budget (e.g. tokens)
+	// was exhausted.
+	CodeBudgetExceeded ErrorCode = "BUDGET_EXCEEDED"
 )
 
 // KairosError is a typed error with rich context for observability.
-// It implements the error interface and can be unwrapped with errors.As().
+// It works with the standard errors package: errors.As finds it anywhere in
+// a chain, errors.Is matches it by Code and Unwrap exposes the cause.
 type KairosError struct {
 	Code        ErrorCode
 	Message     string
@@ -53,6 +61,9 @@ type KairosError struct {
 	Attributes  map[string]string
 	Recoverable bool
 	StatusCode  int // For A2A/gRPC responses
+
+	// grpcStatus is the status an error was decoded from (see FromStatus).
+	grpcStatus *status.Status
 }
 
 // Error implements the error interface.
@@ -68,6 +79,20 @@ func (e *KairosError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is a KairosError with the same Code, so callers
+// can match a class of errors with a template:
+//
+//	errors.Is(err, &KairosError{Code: CodeTimeout})
+//
+// A target without Code matches only itself.
+func (e *KairosError) Is(target error) bool {
+	t, ok := target.(*KairosError)
+	if !ok || t.Code == "" {
+		return false
+	}
+	return e.Code == t.Code
+}
+
 // MarshalJSON implements json.Marshaler for structured logging.
 func (e *KairosError) MarshalJSON() ([]byte, error) {
 	type Alias KairosError
@@ -126,18 +151,36 @@ func (e *KairosError) WithRecoverable(recoverable bool) *KairosError {
 }
 
 // AsKairosError attempts to convert an error to a KairosError.
-// Returns the error as KairosError if it is one, or wraps it otherwise.
+// Returns the first KairosError in the chain, or wraps err otherwise.
 func AsKairosError(err error) *KairosError {
 	if err == nil {
 		return nil
 	}
-	if ke, ok := err.(*KairosError); ok {
+	var ke *KairosError
+	if stderrors.As(err, &ke) {
 		return ke
 	}
 	// Wrap unknown error as internal
 	return New(CodeInternal, "wrapped error", err)
 }
 
+// CodeOf returns the Code of the first KairosError in the chain of err.
+// The boolean is false when there is none.
+func CodeOf(err error) (ErrorCode, bool) {
+	var ke *KairosError
+	if !stderrors.As(err, &ke) {
+		return "", false
+	}
+	return ke.Code, true
+}
+
+// IsRecoverable reports whether the first KairosError in the chain of err is
+// marked as recoverable. Errors without a KairosError are not recoverable.
+func IsRecoverable(err error) bool {
+	var ke *KairosError
+	return stderrors.As(err, &ke) && ke.Recoverable
+}
+
 // RecoverableString returns "true" or "false" as a string for observability.
 func (e *KairosError) RecoverableString() string {
 	if e.Recoverable {
@@ -157,7 +200,7 @@ func codeToStatusCode(code ErrorCode) int {
 		return 400 // INVALID_ARGUMENT
 	case CodeTimeout:
 		return 408 // DEADLINE_EXCEEDED
-	case CodeRateLimit:
+	case CodeRateLimit, CodeBudgetExceeded:
 		return 429 // RESOURCE_EXHAUSTED
 	default:
 		return 500 // INTERNAL
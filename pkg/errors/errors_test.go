@@ -6,6 +6,7 @@ package errors
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -168,6 +169,7 @@ func TestStatusCode(t *testing.T) {
 		{CodeInvalidInput, 400},
 		{CodeTimeout, 408},
 		{CodeRateLimit, 429},
+		{CodeBudgetExceeded, 429},
 		{CodeInternal, 500},
 	}
 
@@ -180,3 +182,64 @@ func TestStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorsIsAndAs(t *testing.T) {
+	cause := errors.New("connection reset")
+	ke := New(CodeTimeout, "llm call timed out", cause)
+	wrapped := fmt.Errorf("run failed: %w", ke)
+
+	if !errors.Is(wrapped, cause) {
+		t.Errorf("expected errors.Is to find the cause")
+	}
+	if !errors.Is(wrapped, &KairosError{Code: CodeTimeout}) {
+		t.Errorf("expected errors.Is to match by code")
+	}
+	if errors.Is(wrapped, &KairosError{Code: CodeNotFound}) {
+		t.Errorf("expected errors.Is not to match a different code")
+	}
+	if errors.Is(wrapped, &KairosError{}) {
+		t.Errorf("expected a template without code not to match")
+	}
+
+	var got *KairosError
+	if !errors.As(wrapped, &got) || got != ke {
+		t.Errorf("expected errors.As to find the KairosError")
+	}
+	if got := AsKairosError(wrapped); got != ke {
+		t.Errorf("expected AsKairosError to walk the chain")
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	wrapped := fmt.Errorf("outer: %w", New(CodeRateLimit, "slow down", nil))
+	if code, ok := CodeOf(wrapped); !ok || code != CodeRateLimit {
+		t.Errorf("expected %v, got %v (ok=%v)", CodeRateLimit, code, ok)
+	}
+	if code, ok := CodeOf(errors.New("plain")); ok || code != "" {
+		t.Errorf("expected no code for plain error, got %v", code)
+	}
+	if _, ok := CodeOf(nil); ok {
+		t.Errorf("expected no code for nil error")
+	}
+}
+
+func TestIsRecoverable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("plain"), false},
+		{"recoverable", New(CodeToolFailure, "failed", nil).WithRecoverable(true), true},
+		{"not recoverable", New(CodeInvalidInput, "bad", nil), false},
+		{"wrapped recoverable", fmt.Errorf("retry: %w", New(CodeTimeout, "slow", nil).WithRecoverable(true)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRecoverable(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorInfoDomain is the errdetails.ErrorInfo domain used for Kairos errors.
+const ErrorInfoDomain = "kairos"
+
+const (
+	recoverableKey   = "recoverable"
+	contextKeyPrefix = "context."
+)
+
+// GRPCCode maps an ErrorCode to its gRPC code.
+func GRPCCode(code ErrorCode) codes.Code {
+	switch code {
+	case CodeInvalidInput:
+		return codes.InvalidArgument
+	case CodeNotFound:
+		return codes.NotFound
+	case CodeUnauthorized:
+		return codes.Unauthenticated
+	case CodeTimeout:
+		return codes.DeadlineExceeded
+	case CodeRateLimit, CodeBudgetExceeded:
+		return codes.ResourceExhausted
+	case CodeToolFailure:
+		return codes.FailedPrecondition
+	case CodeLLMError:
+		return codes.Unavailable
+	case CodeMemoryError:
+		return codes.DataLoss
+	case CodeContextLost:
+		return codes.Canceled
+	case CodeInternal:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// CodeFromGRPC maps a gRPC code to the closest ErrorCode.
+func CodeFromGRPC(code codes.Code) ErrorCode {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return CodeInvalidInput
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return CodeUnauthorized
+	case codes.DeadlineExceeded:
+		return CodeTimeout
+	case codes.ResourceExhausted:
+		return CodeRateLimit
+	case codes.FailedPrecondition:
+		return CodeToolFailure
+	case codes.Unavailable:
+		return CodeLLMError
+	case codes.DataLoss:
+		return CodeMemoryError
+	case codes.Canceled:
+		return CodeContextLost
+	default:
+		return CodeInternal
+	}
+}
+
+// recoverableGRPC reports whether a call failing with code is worth retrying
+// when the status carries no Kairos details.
+func recoverableGRPC(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToStatus converts err into a gRPC status. A KairosError in the chain keeps
+// its Code, Recoverable flag, Attributes and Context in an
+// errdetails.ErrorInfo detail; errors that already carry a status and
+// context errors keep their code. It returns nil for a nil error.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	var ke *KairosError
+	if !stderrors.As(err, &ke) {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+		return status.FromContextError(err)
+	}
+	if ke.grpcStatus != nil {
+		return ke.grpcStatus
+	}
+
+	st := status.New(GRPCCode(ke.Code), ke.Message)
+	info := &errdetails.ErrorInfo{
+		Reason:   string(ke.Code),
+		Domain:   ErrorInfoDomain,
+		Metadata: map[string]string{recoverableKey: strconv.FormatBool(ke.Recoverable)},
+	}
+	for k, v := range ke.Attributes {
+		info.Metadata[k] = v
+	}
+	for k, v := range ke.Context {
+		info.Metadata[contextKeyPrefix+k] = fmt.Sprintf("%v", v)
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// FromStatus converts a gRPC status into a KairosError. Statuses produced by
+// ToStatus round-trip their Code, Recoverable flag, Attributes and Context
+// (as strings); other statuses get the closest Code and are recoverable
+// only for transient gRPC codes. It returns nil for a nil or OK status.
+func FromStatus(st *status.Status) *KairosError {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	ke := New(CodeFromGRPC(st.Code()), st.Message(), nil).
+		WithRecoverable(recoverableGRPC(st.Code()))
+	ke.grpcStatus = st
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ErrorInfoDomain {
+			continue
+		}
+		ke.Code = ErrorCode(info.GetReason())
+		ke.StatusCode = codeToStatusCode(ke.Code)
+		for k, v := range info.GetMetadata() {
+			switch {
+			case k == recoverableKey:
+				ke.Recoverable, _ = strconv.ParseBool(v)
+			case strings.HasPrefix(k, contextKeyPrefix):
+				ke.Context[strings.TrimPrefix(k, contextKeyPrefix)] = v
+			default:
+				ke.Attributes[k] = v
+			}
+		}
+		break
+	}
+	return ke
+}
+
+// FromGRPCError converts an error returned by a gRPC call into a
+// KairosError. Errors without a gRPC status (and nil) are returned as they
+// are. The result still reports the original status through GRPCStatus, so
+// status.Code and status.FromError keep working.
+func FromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ke *KairosError
+	if stderrors.As(err, &ke) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if ke := FromStatus(st); ke != nil {
+		return ke
+	}
+	return err
+}
+
+// GRPCStatus implements the interface used by status.FromError and
+// status.Code, returning the same status as ToStatus.
+func (e *KairosError) GRPCStatus() *status.Status {
+	return ToStatus(e)
+}
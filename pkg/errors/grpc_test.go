@@ -0,0 +1,115 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusFromStatus_RoundTrip(t *testing.T) {
+	ke := New(CodeBudgetExceeded, "token budget exhausted", nil).
+		WithRecoverable(false).
+		WithAttribute("budget", "1000").
+		WithContext("run_id", "run-1")
+
+	st := ToStatus(fmt.Errorf("outer: %w", ke))
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", st.Code())
+	}
+	if st.Message() != "token budget exhausted" {
+		t.Fatalf("unexpected message %q", st.Message())
+	}
+
+	// Simulate the wire: only the proto survives.
+	got := FromStatus(status.FromProto(st.Proto()))
+	if got.Code != CodeBudgetExceeded {
+		t.Errorf("expected %v, got %v", CodeBudgetExceeded, got.Code)
+	}
+	if got.Recoverable {
+		t.Errorf("expected not recoverable")
+	}
+	if got.StatusCode != 429 {
+		t.Errorf("expected status code 429, got %d", got.StatusCode)
+	}
+	if got.Attributes["budget"] != "1000" {
+		t.Errorf("expected attribute budget=1000, got %v", got.Attributes)
+	}
+	if got.Context["run_id"] != "run-1" {
+		t.Errorf("expected context run_id=run-1, got %v", got.Context)
+	}
+}
+
+func TestFromStatus_WithoutDetails(t *testing.T) {
+	tests := []struct {
+		code        codes.Code
+		want        ErrorCode
+		recoverable bool
+	}{
+		{codes.Unavailable, CodeLLMError, true},
+		{codes.DeadlineExceeded, CodeTimeout, true},
+		{codes.NotFound, CodeNotFound, false},
+		{codes.PermissionDenied, CodeUnauthorized, false},
+		{codes.InvalidArgument, CodeInvalidInput, false},
+		{codes.Unknown, CodeInternal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			ke := FromStatus(status.New(tt.code, "boom"))
+			if ke.Code != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, ke.Code)
+			}
+			if ke.Recoverable != tt.recoverable {
+				t.Errorf("expected recoverable=%v, got %v", tt.recoverable, ke.Recoverable)
+			}
+			// The original gRPC code is preserved.
+			if status.Code(ke) != tt.code {
+				t.Errorf("expected status.Code %v, got %v", tt.code, status.Code(ke))
+			}
+		})
+	}
+	if FromStatus(nil) != nil || FromStatus(status.New(codes.OK, "")) != nil {
+		t.Errorf("expected nil for nil and OK statuses")
+	}
+}
+
+func TestToStatus_NonKairosErrors(t *testing.T) {
+	if ToStatus(nil) != nil {
+		t.Errorf("expected nil status for nil error")
+	}
+	if st := ToStatus(status.Error(codes.NotFound, "missing")); st.Code() != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", st.Code())
+	}
+	if st := ToStatus(errors.New("plain")); st.Code() != codes.Unknown {
+		t.Errorf("expected Unknown, got %v", st.Code())
+	}
+}
+
+func TestKairosError_GRPCStatus(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", New(CodeNotFound, "task not found", nil))
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", status.Code(err))
+	}
+}
+
+func TestFromGRPCError(t *testing.T) {
+	if FromGRPCError(nil) != nil {
+		t.Errorf("expected nil")
+	}
+	plain := errors.New("plain")
+	if FromGRPCError(plain) != plain {
+		t.Errorf("expected errors without status to be returned unchanged")
+	}
+	err := FromGRPCError(status.Error(codes.Unavailable, "try again"))
+	if code, ok := CodeOf(err); !ok || code != CodeLLMError {
+		t.Errorf("expected %v, got %v", CodeLLMError, code)
+	}
+	if !IsRecoverable(err) {
+		t.Errorf("expected Unavailable to be recoverable")
+	}
+}
@@ -19,9 +19,12 @@ const (
 
 // Action describes a decision target for policy evaluation.
 type Action struct {
-	Type     ActionType
-	Name     string
-	Metadata map[string]string
+	Type ActionType
+	Name string
+	// Principal is the identity the action is performed for. When empty,
+	// RuleSet uses PrincipalFromContext.
+	Principal string
+	Metadata  map[string]string
 }
 
 // Decision captures the outcome of a policy evaluation.
@@ -48,7 +51,10 @@ type Rule struct {
 	Effect string // allow, deny, or pending
 	Type   ActionType
 	Name   string // glob pattern, optional
-	Reason string
+	// Principal restricts the rule to matching principals (glob pattern,
+	// optional). A rule with a principal never matches anonymous actions.
+	Principal string
+	Reason    string
 }
 
 // DecisionStatus captures the policy outcome.
@@ -75,7 +81,11 @@ func NewRuleSet(rules []Rule) *RuleSet {
 }
 
 // Evaluate checks rules in order and returns the first match.
-func (r *RuleSet) Evaluate(_ context.Context, action Action) Decision {
+func (r *RuleSet) Evaluate(ctx context.Context, action Action) Decision {
+	principal := action.Principal
+	if principal == "" {
+		principal = PrincipalFromContext(ctx)
+	}
 	for _, rule := range r.Rules {
 		if rule.Type != "" && rule.Type != action.Type {
 			continue
@@ -83,6 +93,9 @@ func (r *RuleSet) Evaluate(_ context.Context, action Action) Decision {
 		if rule.Name != "" && !matchPattern(rule.Name, action.Name) {
 			continue
 		}
+		if rule.Principal != "" && (principal == "" || !matchPattern(rule.Principal, principal)) {
+			continue
+		}
 		decision := Decision{Reason: rule.Reason, RuleID: rule.ID}
 		switch strings.ToLower(rule.Effect) {
 		case "deny":
@@ -141,11 +154,12 @@ func RuleSetFromConfig(cfg config.GovernanceConfig) *RuleSet {
 			rule.ID = "rule"
 		}
 		rules = append(rules, Rule{
-			ID:     rule.ID,
-			Effect: rule.Effect,
-			Type:   ActionType(strings.ToLower(rule.Type)),
-			Name:   rule.Name,
-			Reason: rule.Reason,
+			ID:        rule.ID,
+			Effect:    rule.Effect,
+			Type:      ActionType(strings.ToLower(rule.Type)),
+			Name:      rule.Name,
+			Principal: rule.Principal,
+			Reason:    rule.Reason,
 		})
 	}
 	return NewRuleSet(rules)
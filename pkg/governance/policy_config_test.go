@@ -28,3 +28,18 @@ func TestRuleSetFromConfig(t *testing.T) {
 		t.Fatalf("unexpected rule id: %s", decision.RuleID)
 	}
 }
+
+func TestRuleSetFromConfigPrincipal(t *testing.T) {
+	engine := RuleSetFromConfig(config.GovernanceConfig{
+		Policies: []config.PolicyRuleConfig{
+			{ID: "deny-team-a", Effect: "deny", Type: "tool", Name: "write_*", Principal: "team-a"},
+		},
+	})
+	action := Action{Type: ActionTool, Name: "write_file"}
+	if decision := engine.Evaluate(WithPrincipal(context.Background(), "team-a"), action); decision.Allowed {
+		t.Fatalf("expected team-a to be denied")
+	}
+	if decision := engine.Evaluate(WithPrincipal(context.Background(), "team-b"), action); !decision.Allowed {
+		t.Fatalf("expected team-b to be allowed")
+	}
+}
@@ -24,3 +24,26 @@ func TestRuleSetEvaluate(t *testing.T) {
 		t.Fatalf("unexpected reason: %s", decision.Reason)
 	}
 }
+
+func TestRuleSetEvaluatePrincipal(t *testing.T) {
+	engine := NewRuleSet([]Rule{
+		{ID: "deny-team-a-writes", Effect: "deny", Type: ActionTool, Name: "write_file", Principal: "team-a", Reason: "read-only team"},
+	})
+	action := Action{Type: ActionTool, Name: "write_file"}
+
+	if decision := engine.Evaluate(WithPrincipal(context.Background(), "team-a"), action); decision.IsAllowed() {
+		t.Fatalf("expected team-a to be denied")
+	}
+	if decision := engine.Evaluate(WithPrincipal(context.Background(), "team-b"), action); !decision.IsAllowed() {
+		t.Fatalf("expected team-b to be allowed")
+	}
+	if decision := engine.Evaluate(context.Background(), action); !decision.IsAllowed() {
+		t.Fatalf("expected principal rule to skip anonymous actions")
+	}
+
+	// An explicit principal wins over the context.
+	action.Principal = "team-a"
+	if decision := engine.Evaluate(WithPrincipal(context.Background(), "team-b"), action); decision.RuleID != "deny-team-a-writes" {
+		t.Fatalf("expected action principal to be matched, got %+v", decision)
+	}
+}
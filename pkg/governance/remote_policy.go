@@ -0,0 +1,271 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRemotePolicyTTL is how long remote decisions are cached.
+	DefaultRemotePolicyTTL = 30 * time.Second
+	// DefaultRemotePolicyTimeout bounds each call to the policy service.
+	DefaultRemotePolicyTimeout = 2 * time.Second
+)
+
+// RemotePolicy asks an HTTP policy service (OPA-style) whether a tool call is
+// allowed and caches the answers for a short TTL.
+//
+// The service receives a POST with the body
+//
+//	{"input": {"tool": "...", "arguments_digest": "sha256:...", "principal": "..."}}
+//
+// and answers either {"result": true|false} or
+// {"result": {"allow": true|false, "reason": "..."}}. When the service cannot
+// be reached or answers badly, the fallback decision applies and is not
+// cached.
+type RemotePolicy struct {
+	endpoint string
+	client   *http.Client
+	headers  map[string]string
+	ttl      time.Duration
+	failOpen bool
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[remotePolicyKey]remotePolicyEntry
+}
+
+// RemotePolicyOption configures a RemotePolicy.
+type RemotePolicyOption func(*RemotePolicy)
+
+// WithRemotePolicyTTL sets how long decisions are cached. Zero disables the
+// cache.
+func WithRemotePolicyTTL(ttl time.Duration) RemotePolicyOption {
+	return func(p *RemotePolicy) {
+		if ttl >= 0 {
+			p.ttl = ttl
+		}
+	}
+}
+
+// WithRemotePolicyFailOpen allows tool calls when the policy service is
+// unavailable. By default they are denied (fail-closed).
+func WithRemotePolicyFailOpen(failOpen bool) RemotePolicyOption {
+	return func(p *RemotePolicy) {
+		p.failOpen = failOpen
+	}
+}
+
+// WithRemotePolicyHTTPClient sets the HTTP client used to reach the service.
+func WithRemotePolicyHTTPClient(client *http.Client) RemotePolicyOption {
+	return func(p *RemotePolicy) {
+		if client != nil {
+			p.client = client
+		}
+	}
+}
+
+// WithRemotePolicyHeaders adds headers (e.g. Authorization) to every request.
+func WithRemotePolicyHeaders(headers map[string]string) RemotePolicyOption {
+	return func(p *RemotePolicy) {
+		for k, v := range headers {
+			p.headers[k] = v
+		}
+	}
+}
+
+// NewRemotePolicy creates a RemotePolicy for the given endpoint.
+func NewRemotePolicy(endpoint string, opts ...RemotePolicyOption) *RemotePolicy {
+	p := &RemotePolicy{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: DefaultRemotePolicyTimeout},
+		headers:  make(map[string]string),
+		ttl:      DefaultRemotePolicyTTL,
+		now:      time.Now,
+		cache:    make(map[remotePolicyKey]remotePolicyEntry),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type remotePolicyKey struct {
+	principal string
+	tool      string
+	digest    string
+}
+
+type remotePolicyEntry struct {
+	decision Decision
+	expires  time.Time
+}
+
+type remotePolicyInput struct {
+	Tool            string `json:"tool"`
+	ArgumentsDigest string `json:"arguments_digest,omitempty"`
+	Principal       string `json:"principal,omitempty"`
+}
+
+// Check returns the service decision for a tool call. args may be nil when
+// only the tool name is known (e.g. when listing tools).
+func (p *RemotePolicy) Check(ctx context.Context, toolName string, args any) Decision {
+	key := remotePolicyKey{
+		principal: PrincipalFromContext(ctx),
+		tool:      toolName,
+		digest:    argumentsDigest(args),
+	}
+	if decision, ok := p.cached(key); ok {
+		return decision
+	}
+	decision, err := p.query(ctx, key)
+	if err != nil {
+		return p.fallback(err)
+	}
+	p.store(key, decision)
+	return decision
+}
+
+func (p *RemotePolicy) cached(key remotePolicyKey) (Decision, bool) {
+	if p.ttl == 0 {
+		return Decision{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.cache[key]
+	if !ok {
+		return Decision{}, false
+	}
+	if !p.now().Before(entry.expires) {
+		delete(p.cache, key)
+		return Decision{}, false
+	}
+	return entry.decision, true
+}
+
+func (p *RemotePolicy) store(key remotePolicyKey, decision Decision) {
+	if p.ttl == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache[key] = remotePolicyEntry{decision: decision, expires: p.now().Add(p.ttl)}
+}
+
+func (p *RemotePolicy) query(ctx context.Context, key remotePolicyKey) (Decision, error) {
+	body, err := json.Marshal(map[string]remotePolicyInput{"input": {
+		Tool:            key.tool,
+		ArgumentsDigest: key.digest,
+		Principal:       key.principal,
+	}})
+	if err != nil {
+		return Decision{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Decision{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return Decision{}, fmt.Errorf("policy service returned %s", resp.Status)
+	}
+
+	var payload struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return Decision{}, fmt.Errorf("decode policy response: %w", err)
+	}
+	var allowed bool
+	var reason string
+	if err := json.Unmarshal(payload.Result, &allowed); err != nil {
+		var result struct {
+			Allow  *bool  `json:"allow"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal(payload.Result, &result); err != nil || result.Allow == nil {
+			return Decision{}, fmt.Errorf("policy response has no boolean result")
+		}
+		allowed, reason = *result.Allow, result.Reason
+	}
+	if allowed {
+		return Decision{Allowed: true, Status: DecisionStatusAllow, Reason: reason}, nil
+	}
+	if reason == "" {
+		reason = "denied by remote policy"
+	}
+	return Decision{Allowed: false, Status: DecisionStatusDeny, Reason: reason}, nil
+}
+
+func (p *RemotePolicy) fallback(err error) Decision {
+	if p.failOpen {
+		return Decision{Allowed: true, Status: DecisionStatusAllow, Reason: "remote policy unavailable (fail-open): " + err.Error()}
+	}
+	return Decision{Allowed: false, Status: DecisionStatusDeny, Reason: "remote policy unavailable: " + err.Error()}
+}
+
+// argumentsDigest returns a stable digest of the tool arguments, so the
+// service can tell calls apart without receiving their content.
+func argumentsDigest(args any) string {
+	var data []byte
+	switch v := args.(type) {
+	case nil:
+		return ""
+	case string:
+		if v == "" {
+			return ""
+		}
+		// Re-encode JSON so equivalent arguments share a digest.
+		var decoded any
+		if err := json.Unmarshal([]byte(v), &decoded); err == nil {
+			data, _ = json.Marshal(decoded)
+		} else {
+			data = []byte(v)
+		}
+	case []byte:
+		data = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprint(v))
+		} else {
+			data = encoded
+		}
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+type principalKey struct{}
+
+// WithPrincipal records the identity on whose behalf tools are called. It is
+// matched by Rule.Principal and sent to remote policy services.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal set with WithPrincipal.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
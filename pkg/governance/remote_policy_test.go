@@ -0,0 +1,126 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type policyRequest struct {
+	Input remotePolicyInput `json:"input"`
+}
+
+func newPolicyServer(t *testing.T, decide func(policyRequest) string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req policyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(decide(req)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestToolFilter_RemotePolicy(t *testing.T) {
+	var last policyRequest
+	srv, calls := newPolicyServer(t, func(req policyRequest) string {
+		last = req
+		switch req.Input.Tool {
+		case "read_file":
+			return `{"result": true}`
+		case "delete_file":
+			return `{"result": {"allow": false, "reason": "destructive tool"}}`
+		default:
+			return `{"result": false}`
+		}
+	})
+	filter := NewToolFilter(
+		WithDenylist([]string{"shell"}),
+		WithRemotePolicy(srv.URL),
+	)
+	ctx := WithPrincipal(context.Background(), "alice")
+
+	if !filter.IsCallAllowed(ctx, "read_file", `{"path": "a.txt"}`).IsAllowed() {
+		t.Fatalf("expected read_file to be allowed")
+	}
+	if last.Input.Principal != "alice" {
+		t.Fatalf("expected principal alice, got %q", last.Input.Principal)
+	}
+	if !strings.HasPrefix(last.Input.ArgumentsDigest, "sha256:") {
+		t.Fatalf("expected arguments digest, got %q", last.Input.ArgumentsDigest)
+	}
+
+	decision := filter.IsAllowed(ctx, "delete_file")
+	if decision.IsAllowed() || decision.Reason != "destructive tool" {
+		t.Fatalf("expected remote deny with reason, got %+v", decision)
+	}
+
+	// Cached: equivalent arguments do not reach the service again.
+	before := calls.Load()
+	if !filter.IsCallAllowed(ctx, "read_file", map[string]any{"path": "a.txt"}).IsAllowed() {
+		t.Fatalf("expected cached allow")
+	}
+	if calls.Load() != before {
+		t.Fatalf("expected cached decision, service called %d times", calls.Load()-before)
+	}
+
+	// Local rules short-circuit the remote call.
+	if filter.IsAllowed(ctx, "shell").IsAllowed() {
+		t.Fatalf("expected denylisted tool to be denied")
+	}
+	if calls.Load() != before {
+		t.Fatalf("expected no remote call for denylisted tool")
+	}
+}
+
+func TestRemotePolicy_CacheExpires(t *testing.T) {
+	srv, calls := newPolicyServer(t, func(policyRequest) string { return `{"result": true}` })
+	policy := NewRemotePolicy(srv.URL, WithRemotePolicyTTL(time.Minute))
+	now := time.Now()
+	policy.now = func() time.Time { return now }
+
+	policy.Check(context.Background(), "tool", nil)
+	policy.Check(context.Background(), "tool", nil)
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 call, got %d", calls.Load())
+	}
+	now = now.Add(2 * time.Minute)
+	policy.Check(context.Background(), "tool", nil)
+	if calls.Load() != 2 {
+		t.Fatalf("expected expired entry to be refreshed, got %d calls", calls.Load())
+	}
+}
+
+func TestRemotePolicy_Unavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	closed := NewToolFilter(WithRemotePolicy(srv.URL))
+	if closed.IsAllowed(context.Background(), "tool").IsAllowed() {
+		t.Fatalf("expected fail-closed deny")
+	}
+	open := NewToolFilter(WithRemotePolicy(srv.URL, WithRemotePolicyFailOpen(true)))
+	if !open.IsAllowed(context.Background(), "tool").IsAllowed() {
+		t.Fatalf("expected fail-open allow")
+	}
+	unreachable := NewToolFilter(WithRemotePolicy("http://127.0.0.1:1"))
+	if unreachable.IsAllowed(context.Background(), "tool").IsAllowed() {
+		t.Fatalf("expected deny for unreachable service")
+	}
+}
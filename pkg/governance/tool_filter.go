@@ -15,6 +15,7 @@ type ToolFilter struct {
 	allowlist    map[string]bool
 	denylist     map[string]bool
 	policyEngine PolicyEngine
+	remote       *RemotePolicy
 }
 
 // ToolFilterOption configures a ToolFilter.
@@ -63,13 +64,34 @@ func WithPolicyEngine(engine PolicyEngine) ToolFilterOption {
 	}
 }
 
+// WithRemotePolicy consults an HTTP policy service after the local rules.
+// See RemotePolicy for the protocol.
+func WithRemotePolicy(endpoint string, opts ...RemotePolicyOption) ToolFilterOption {
+	return func(tf *ToolFilter) {
+		tf.remote = NewRemotePolicy(endpoint, opts...)
+	}
+}
+
+// HasRemotePolicy reports whether the filter consults a remote policy
+// service, whose decision may depend on the call arguments.
+func (tf *ToolFilter) HasRemotePolicy() bool {
+	return tf.remote != nil
+}
+
 // IsAllowed checks if a tool name is permitted by the filter.
 // Evaluation order:
 // 1. If denylist contains tool → deny
 // 2. If allowlist is non-empty and doesn't contain tool → deny
-// 3. If policy engine exists, evaluate → respect decision
-// 4. Otherwise → allow
+// 3. If policy engine exists and does not allow → respect decision
+// 4. If remote policy exists, evaluate → respect decision
+// 5. Otherwise → policy engine decision, or allow
 func (tf *ToolFilter) IsAllowed(ctx context.Context, toolName string) Decision {
+	return tf.IsCallAllowed(ctx, toolName, nil)
+}
+
+// IsCallAllowed is like IsAllowed for a concrete call. The remote policy
+// receives a digest of args and the principal from the context.
+func (tf *ToolFilter) IsCallAllowed(ctx context.Context, toolName string, args any) Decision {
 	// Check denylist first (explicit denies take precedence)
 	if tf.matchesList(toolName, tf.denylist) {
 		return Decision{
@@ -91,10 +113,18 @@ func (tf *ToolFilter) IsAllowed(ctx context.Context, toolName string) Decision {
 	// Check policy engine if available
 	if tf.policyEngine != nil {
 		action := Action{
-			Type: ActionTool,
-			Name: toolName,
+			Type:      ActionTool,
+			Name:      toolName,
+			Principal: PrincipalFromContext(ctx),
+		}
+		decision := tf.policyEngine.Evaluate(ctx, action)
+		if tf.remote == nil || !decision.IsAllowed() {
+			return decision
 		}
-		return tf.policyEngine.Evaluate(ctx, action)
+	}
+
+	if tf.remote != nil {
+		return tf.remote.Check(ctx, toolName, args)
 	}
 
 	// Default: allow
@@ -107,7 +137,7 @@ func (tf *ToolFilter) IsAllowed(ctx context.Context, toolName string) Decision {
 // FilterTools returns only the tools that pass the filter.
 // toolNames is a slice of tool names, returns filtered names.
 func (tf *ToolFilter) FilterTools(ctx context.Context, toolNames []string) []string {
-	if len(tf.allowlist) == 0 && len(tf.denylist) == 0 && tf.policyEngine == nil {
+	if len(tf.allowlist) == 0 && len(tf.denylist) == 0 && tf.policyEngine == nil && tf.remote == nil {
 		return toolNames // No filtering configured
 	}
 
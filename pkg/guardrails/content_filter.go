@@ -6,6 +6,7 @@ package guardrails
 import (
 	"context"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -13,18 +14,18 @@ import (
 type ContentCategory string
 
 const (
-	ContentCategoryProfanity  ContentCategory = "profanity"
-	ContentCategoryViolence   ContentCategory = "violence"
-	ContentCategoryHate       ContentCategory = "hate"
-	ContentCategorySexual     ContentCategory = "sexual"
-	ContentCategoryDangerous  ContentCategory = "dangerous"
-	ContentCategorySelfHarm   ContentCategory = "self_harm"
-	ContentCategoryIllegal    ContentCategory = "illegal"
-	ContentCategoryMedical    ContentCategory = "medical_advice"
-	ContentCategoryFinancial  ContentCategory = "financial_advice"
-	ContentCategoryMalware    ContentCategory = "malware"
-	ContentCategoryPhishing   ContentCategory = "phishing"
-	ContentCategorySpam       ContentCategory = "spam"
+	ContentCategoryProfanity ContentCategory = "profanity"
+	ContentCategoryViolence  ContentCategory = "violence"
+	ContentCategoryHate      ContentCategory = "hate"
+	ContentCategorySexual    ContentCategory = "sexual"
+	ContentCategoryDangerous ContentCategory = "dangerous"
+	ContentCategorySelfHarm  ContentCategory = "self_harm"
+	ContentCategoryIllegal   ContentCategory = "illegal"
+	ContentCategoryMedical   ContentCategory = "medical_advice"
+	ContentCategoryFinancial ContentCategory = "financial_advice"
+	ContentCategoryMalware   ContentCategory = "malware"
+	ContentCategoryPhishing  ContentCategory = "phishing"
+	ContentCategorySpam      ContentCategory = "spam"
 )
 
 // contentPattern defines patterns for a content category.
@@ -34,11 +35,35 @@ type contentPattern struct {
 	keywords []string
 }
 
+// BlockAction determines what a content rule does when it matches.
+type BlockAction int
+
+const (
+	// BlockActionBlock rejects the content.
+	BlockActionBlock BlockAction = iota
+	// BlockActionFlag reports the match without blocking.
+	BlockActionFlag
+	// BlockActionRedact masks matches in outputs. On input it flags,
+	// since input checkers cannot modify content.
+	BlockActionRedact
+)
+
+// customRule is a user-defined rule evaluated after built-in categories.
+type customRule struct {
+	name    string
+	pattern *regexp.Regexp
+	action  BlockAction
+}
+
 // ContentFilter blocks or flags content based on category patterns.
+// Built-in categories are evaluated first, in the order they were enabled,
+// followed by custom rules in registration order.
 type ContentFilter struct {
-	categories       map[ContentCategory]contentPattern
+	categories        map[ContentCategory]contentPattern
 	enabledCategories map[ContentCategory]bool
-	blockMode        bool // true = block, false = flag only
+	order             []ContentCategory
+	rules             []customRule
+	blockMode         bool // true = block, false = flag only
 }
 
 // ContentFilterOption configures the content filter.
@@ -116,9 +141,9 @@ var defaultContentPatterns = map[ContentCategory]struct {
 // NewContentFilter creates a new content filter.
 func NewContentFilter(categories ...ContentCategory) *ContentFilter {
 	f := &ContentFilter{
-		categories:       make(map[ContentCategory]contentPattern),
+		categories:        make(map[ContentCategory]contentPattern),
 		enabledCategories: make(map[ContentCategory]bool),
-		blockMode:        true,
+		blockMode:         true,
 	}
 
 	// Compile patterns for requested categories
@@ -134,18 +159,32 @@ func NewContentFilter(categories ...ContentCategory) *ContentFilter {
 					cp.patterns = append(cp.patterns, re)
 				}
 			}
-			f.categories[cat] = cp
-			f.enabledCategories[cat] = true
+			f.addCategory(cat, cp)
 		}
 	}
 
 	return f
 }
 
+// addCategory registers or replaces a category, keeping evaluation order.
+func (f *ContentFilter) addCategory(cat ContentCategory, cp contentPattern) {
+	if _, ok := f.categories[cat]; !ok {
+		f.order = append(f.order, cat)
+	}
+	f.categories[cat] = cp
+	f.enabledCategories[cat] = true
+}
+
 // WithAllContentCategories enables all default content categories.
 func WithAllContentCategories() ContentFilterOption {
 	return func(f *ContentFilter) {
-		for cat, def := range defaultContentPatterns {
+		cats := make([]ContentCategory, 0, len(defaultContentPatterns))
+		for cat := range defaultContentPatterns {
+			cats = append(cats, cat)
+		}
+		sort.Slice(cats, func(i, j int) bool { return cats[i] < cats[j] })
+		for _, cat := range cats {
+			def := defaultContentPatterns[cat]
 			cp := contentPattern{
 				category: cat,
 				patterns: make([]*regexp.Regexp, 0, len(def.patterns)),
@@ -156,8 +195,7 @@ func WithAllContentCategories() ContentFilterOption {
 					cp.patterns = append(cp.patterns, re)
 				}
 			}
-			f.categories[cat] = cp
-			f.enabledCategories[cat] = true
+			f.addCategory(cat, cp)
 		}
 	}
 }
@@ -169,8 +207,7 @@ func WithCustomContentPattern(category ContentCategory, pattern string) ContentF
 			cp := f.categories[category]
 			cp.category = category
 			cp.patterns = append(cp.patterns, re)
-			f.categories[category] = cp
-			f.enabledCategories[category] = true
+			f.addCategory(category, cp)
 		}
 	}
 }
@@ -181,8 +218,37 @@ func WithContentKeywords(category ContentCategory, keywords ...string) ContentFi
 		cp := f.categories[category]
 		cp.category = category
 		cp.keywords = append(cp.keywords, keywords...)
-		f.categories[category] = cp
-		f.enabledCategories[category] = true
+		f.addCategory(category, cp)
+	}
+}
+
+// WithCustomRule adds a named regex rule. Matches are reported with
+// "content policy violation: <name>" as the reason. Nil patterns are ignored.
+func WithCustomRule(name string, pattern *regexp.Regexp, action BlockAction) ContentFilterOption {
+	return func(f *ContentFilter) {
+		if pattern == nil || strings.TrimSpace(name) == "" {
+			return
+		}
+		f.rules = append(f.rules, customRule{name: name, pattern: pattern, action: action})
+	}
+}
+
+// WithDenylist blocks content containing any of the given terms, matched
+// case-insensitively on word boundaries. Matches are reported under the
+// "denylist" rule name.
+func WithDenylist(terms []string) ContentFilterOption {
+	return func(f *ContentFilter) {
+		quoted := make([]string, 0, len(terms))
+		for _, term := range terms {
+			if term = strings.TrimSpace(term); term != "" {
+				quoted = append(quoted, regexp.QuoteMeta(term))
+			}
+		}
+		if len(quoted) == 0 {
+			return
+		}
+		re := regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+		f.rules = append(f.rules, customRule{name: "denylist", pattern: re, action: BlockActionBlock})
 	}
 }
 
@@ -198,7 +264,7 @@ func (f *ContentFilter) ID() string {
 	return "content-filter"
 }
 
-// CheckInput analyzes input for disallowed content categories.
+// CheckInput analyzes input for disallowed content categories and custom rules.
 func (f *ContentFilter) CheckInput(ctx context.Context, input string) CheckResult {
 	if input == "" {
 		return CheckResult{Blocked: false}
@@ -206,7 +272,7 @@ func (f *ContentFilter) CheckInput(ctx context.Context, input string) CheckResul
 
 	normalized := strings.ToLower(input)
 
-	for cat, cp := range f.categories {
+	for _, cat := range f.order {
 		if !f.enabledCategories[cat] {
 			continue
 		}
@@ -217,36 +283,28 @@ func (f *ContentFilter) CheckInput(ctx context.Context, input string) CheckResul
 		default:
 		}
 
-		// Check patterns
-		for _, pattern := range cp.patterns {
-			if pattern.MatchString(normalized) {
-				return CheckResult{
-					Blocked:     f.blockMode,
-					Reason:      "content policy violation: " + string(cat),
-					GuardrailID: f.ID(),
-					Confidence:  0.9,
-					Metadata: map[string]any{
-						"category": string(cat),
-						"type":     "pattern",
-					},
-				}
-			}
+		if result, ok := f.matchCategory(cat, normalized); ok {
+			return result
 		}
+	}
 
-		// Check keywords
-		for _, keyword := range cp.keywords {
-			if strings.Contains(normalized, strings.ToLower(keyword)) {
-				return CheckResult{
-					Blocked:     f.blockMode,
-					Reason:      "content policy violation: " + string(cat),
-					GuardrailID: f.ID(),
-					Confidence:  0.8,
-					Metadata: map[string]any{
-						"category": string(cat),
-						"type":     "keyword",
-						"keyword":  keyword,
-					},
-				}
+	for _, rule := range f.rules {
+		select {
+		case <-ctx.Done():
+			return CheckResult{Blocked: false}
+		default:
+		}
+
+		if match := rule.pattern.FindString(input); match != "" {
+			return CheckResult{
+				Blocked:     rule.action == BlockActionBlock,
+				Reason:      "content policy violation: " + rule.name,
+				GuardrailID: f.ID(),
+				Confidence:  1.0,
+				Metadata: map[string]any{
+					"rule": rule.name,
+					"type": "custom",
+				},
 			}
 		}
 	}
@@ -254,6 +312,111 @@ func (f *ContentFilter) CheckInput(ctx context.Context, input string) CheckResul
 	return CheckResult{Blocked: false}
 }
 
+// matchCategory checks normalized text against a built-in category.
+func (f *ContentFilter) matchCategory(cat ContentCategory, normalized string) (CheckResult, bool) {
+	cp := f.categories[cat]
+
+	// Check patterns
+	for _, pattern := range cp.patterns {
+		if pattern.MatchString(normalized) {
+			return CheckResult{
+				Blocked:     f.blockMode,
+				Reason:      "content policy violation: " + string(cat),
+				GuardrailID: f.ID(),
+				Confidence:  0.9,
+				Metadata: map[string]any{
+					"category": string(cat),
+					"type":     "pattern",
+				},
+			}, true
+		}
+	}
+
+	// Check keywords
+	for _, keyword := range cp.keywords {
+		if strings.Contains(normalized, strings.ToLower(keyword)) {
+			return CheckResult{
+				Blocked:     f.blockMode,
+				Reason:      "content policy violation: " + string(cat),
+				GuardrailID: f.ID(),
+				Confidence:  0.8,
+				Metadata: map[string]any{
+					"category": string(cat),
+					"type":     "keyword",
+					"keyword":  keyword,
+				},
+			}, true
+		}
+	}
+	return CheckResult{}, false
+}
+
+// FilterOutput applies categories and custom rules to LLM output. Blocking
+// matches stop evaluation, flagged matches are listed in Flags, and redact
+// rules replace matches with "[REDACTED:<rule>]".
+func (f *ContentFilter) FilterOutput(ctx context.Context, output string) FilterResult {
+	result := FilterResult{Content: output}
+	if output == "" {
+		return result
+	}
+
+	normalized := strings.ToLower(output)
+	for _, cat := range f.order {
+		if !f.enabledCategories[cat] {
+			continue
+		}
+		if ctx.Err() != nil {
+			return result
+		}
+		check, ok := f.matchCategory(cat, normalized)
+		if !ok {
+			continue
+		}
+		result.Flags = append(result.Flags, CategoryScore{Category: cat, Score: check.Confidence})
+		if check.Blocked {
+			return f.blockOutput(result, check.Reason)
+		}
+	}
+
+	for _, rule := range f.rules {
+		if ctx.Err() != nil {
+			return result
+		}
+		matches := rule.pattern.FindAllStringIndex(result.Content, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		result.Flags = append(result.Flags, CategoryScore{Category: ContentCategory(rule.name), Score: 1.0})
+		switch rule.action {
+		case BlockActionBlock:
+			return f.blockOutput(result, "content policy violation: "+rule.name)
+		case BlockActionRedact:
+			replacement := "[REDACTED:" + rule.name + "]"
+			for i := len(matches) - 1; i >= 0; i-- {
+				m := matches[i]
+				result.Redactions = append(result.Redactions, Redaction{
+					Type:        rule.name,
+					Replacement: replacement,
+					Position:    m[0],
+					Confidence:  1.0,
+				})
+				result.Content = result.Content[:m[0]] + replacement + result.Content[m[1]:]
+			}
+			result.Modified = true
+		}
+	}
+	return result
+}
+
+func (f *ContentFilter) blockOutput(result FilterResult, reason string) FilterResult {
+	result.Blocked = true
+	result.Reason = reason
+	result.GuardrailID = f.ID()
+	result.Modified = true
+	result.Content = DefaultBlockedMessage
+	return result
+}
+
 // WithContentFilter returns an option that adds content filtering.
 func WithContentFilter(categories ...ContentCategory) Option {
 	return func(g *Guardrails) {
@@ -272,3 +435,15 @@ func WithContentFilterOptions(categories []ContentCategory, opts ...ContentFilte
 		g.inputCheckers = append(g.inputCheckers, filter)
 	}
 }
+
+// WithContentOutputFilter returns an option that applies the content filter
+// (including custom rules) to LLM outputs.
+func WithContentOutputFilter(categories []ContentCategory, opts ...ContentFilterOption) Option {
+	return func(g *Guardrails) {
+		filter := NewContentFilter(categories...)
+		for _, opt := range opts {
+			opt(filter)
+		}
+		g.outputFilters = append(g.outputFilters, filter)
+	}
+}
@@ -26,6 +26,9 @@
 //
 //	// After LLM response
 //	output := guard.FilterOutput(ctx, llmResponse)
+//	if output.Blocked {
+//	    return output.Reason
+//	}
 package guardrails
 
 import (
@@ -33,6 +36,9 @@ import (
 	"sync"
 )
 
+// DefaultBlockedMessage replaces outputs blocked by an output filter.
+const DefaultBlockedMessage = "[response withheld by content policy]"
+
 // CheckResult represents the outcome of a guardrail check.
 type CheckResult struct {
 	// Blocked indicates the content should not proceed.
@@ -61,6 +67,20 @@ type FilterResult struct {
 
 	// Redactions lists what was removed or masked.
 	Redactions []Redaction
+
+	// Blocked indicates the output must not be returned to the user.
+	// Content then holds a safe replacement message.
+	Blocked bool
+
+	// Reason explains why the output was blocked (empty if not blocked).
+	Reason string
+
+	// GuardrailID identifies which filter blocked the output.
+	GuardrailID string
+
+	// Flags lists content categories detected in the output, whether or
+	// not they caused a block.
+	Flags []CategoryScore
 }
 
 // Redaction describes a single content modification.
@@ -76,6 +96,10 @@ type Redaction struct {
 
 	// Position is the character offset in original content.
 	Position int
+
+	// Confidence is the detection confidence (0.0-1.0). Pattern matches
+	// report 1.0; heuristic detectors such as names report lower values.
+	Confidence float64
 }
 
 // InputChecker validates content before it reaches the LLM.
@@ -187,7 +211,8 @@ func (g *Guardrails) CheckInput(ctx context.Context, input string) CheckResult {
 }
 
 // FilterOutput runs all output filters in sequence.
-// Each filter receives the output of the previous one.
+// Each filter receives the output of the previous one. A filter that blocks
+// the output stops the chain.
 func (g *Guardrails) FilterOutput(ctx context.Context, output string) FilterResult {
 	g.mu.RLock()
 	filters := g.outputFilters
@@ -207,6 +232,15 @@ func (g *Guardrails) FilterOutput(ctx context.Context, output string) FilterResu
 		}
 
 		filterResult := filter.FilterOutput(ctx, result.Content)
+		result.Flags = append(result.Flags, filterResult.Flags...)
+		if filterResult.Blocked {
+			result.Content = filterResult.Content
+			result.Modified = true
+			result.Blocked = true
+			result.Reason = filterResult.Reason
+			result.GuardrailID = filter.ID()
+			return result
+		}
 		if filterResult.Modified {
 			result.Content = filterResult.Content
 			result.Modified = true
@@ -5,7 +5,12 @@ package guardrails
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"github.com/jllopis/kairos/pkg/llm"
 )
 
 func TestGuardrailsBasic(t *testing.T) {
@@ -358,3 +363,325 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestPIIFilterSpanishIDs(t *testing.T) {
+	filter := NewPIIFilter(PIIFilterMask)
+	doc := "Solicitud de alta. DNI: 12345678Z, NIE del cónyuge: X-1234567-L, " +
+		"nº de afiliación a la Seguridad Social 28/12345678/40."
+
+	result := filter.FilterOutput(context.Background(), doc)
+	if contains(result.Content, "12345678Z") || contains(result.Content, "1234567") {
+		t.Fatalf("expected national IDs to be masked, got %q", result.Content)
+	}
+	if !contains(result.Content, "[NATIONAL_ID]") || !contains(result.Content, "[SSN]") {
+		t.Fatalf("expected [NATIONAL_ID] and [SSN] masks, got %q", result.Content)
+	}
+
+	counts := map[string]int{}
+	for _, r := range result.Redactions {
+		counts[r.Type]++
+		if r.Confidence != 1.0 {
+			t.Errorf("expected confidence 1.0 for %s, got %v", r.Type, r.Confidence)
+		}
+	}
+	if counts[string(PIITypeNationalID)] != 2 || counts[string(PIITypeSSN)] != 1 {
+		t.Fatalf("unexpected redactions: %+v", result.Redactions)
+	}
+
+	// A DNI with a wrong control letter is not a valid ID.
+	invalid := filter.FilterOutput(context.Background(), "Referencia interna 87654321A")
+	if invalid.Modified {
+		t.Fatalf("expected invalid DNI to be left untouched, got %q", invalid.Content)
+	}
+}
+
+func TestPIIFilterLocales(t *testing.T) {
+	filter := NewPIIFilter(PIIFilterMask, WithPIILocales(PIILocaleUS))
+	result := filter.FilterOutput(context.Background(), "DNI 12345678Z, SSN 123-45-6789")
+	if !contains(result.Content, "12345678Z") {
+		t.Fatalf("expected Spanish DNI to be ignored for US locale, got %q", result.Content)
+	}
+	if !contains(result.Content, "[SSN]") {
+		t.Fatalf("expected US SSN to be masked, got %q", result.Content)
+	}
+}
+
+func TestPIIFilterNames(t *testing.T) {
+	doc := "Estimada Sra. María José Fernández de la Torre: le escribe Don Carlos Ruiz. " +
+		"Paciente: Lucía Gómez. Ayer Pedro Martín firmó el contrato en Madrid."
+
+	// Names are opt-in.
+	plain := NewPIIFilter(PIIFilterMask).FilterOutput(context.Background(), doc)
+	if contains(plain.Content, "[NAME]") {
+		t.Fatalf("expected names to be disabled by default, got %q", plain.Content)
+	}
+
+	filter := NewPIIFilter(PIIFilterMask, WithIncludePII(PIITypeName))
+	result := filter.FilterOutput(context.Background(), doc)
+	for _, name := range []string{"María José Fernández de la Torre", "Carlos Ruiz", "Lucía Gómez"} {
+		if contains(result.Content, name) {
+			t.Errorf("expected %q to be masked, got %q", name, result.Content)
+		}
+	}
+	// Bare names score below the default threshold.
+	if !contains(result.Content, "Pedro Martín") {
+		t.Errorf("expected low-confidence name to be kept, got %q", result.Content)
+	}
+	if !contains(result.Content, "Sra. [NAME]") || !contains(result.Content, "Madrid") {
+		t.Errorf("expected honorific and place to be preserved, got %q", result.Content)
+	}
+	for _, r := range result.Redactions {
+		if r.Type == string(PIITypeName) && (r.Confidence < DefaultPIIConfidenceThreshold || r.Confidence >= 1.0) {
+			t.Errorf("unexpected name confidence %v", r.Confidence)
+		}
+	}
+
+	lenient := NewPIIFilter(PIIFilterMask, WithIncludePII(PIITypeName), WithPIIConfidenceThreshold(0.6))
+	lenientResult := lenient.FilterOutput(context.Background(), doc)
+	if contains(lenientResult.Content, "Pedro Martín") {
+		t.Errorf("expected bare name to be masked with lower threshold, got %q", lenientResult.Content)
+	}
+
+	check := lenient.CheckInput(context.Background(), "Me llamo Ana Ruiz")
+	if !check.Blocked || check.Confidence != 0.85 {
+		t.Errorf("expected name input to be blocked with confidence 0.85, got %+v", check)
+	}
+}
+
+func TestOutputContentClassifier(t *testing.T) {
+	g := New(WithOutputContentClassifier(
+		[]ContentCategory{ContentCategoryDangerous, ContentCategorySelfHarm}, 0.8,
+	))
+
+	safe := g.FilterOutput(context.Background(), "The capital of Spain is Madrid.")
+	if safe.Blocked || safe.Modified {
+		t.Fatalf("expected safe output to pass, got %+v", safe)
+	}
+
+	unsafe := g.FilterOutput(context.Background(), "Sure, here are instructions for making explosives.")
+	if !unsafe.Blocked {
+		t.Fatal("expected unsafe output to be blocked")
+	}
+	if unsafe.GuardrailID != "output-content-classifier" {
+		t.Errorf("unexpected guardrail id %q", unsafe.GuardrailID)
+	}
+	if contains(unsafe.Content, "explosives") {
+		t.Errorf("blocked content leaked: %q", unsafe.Content)
+	}
+	if len(unsafe.Flags) != 1 || unsafe.Flags[0].Category != ContentCategoryDangerous {
+		t.Errorf("unexpected flags: %+v", unsafe.Flags)
+	}
+}
+
+func TestOutputContentClassifierFlagOnly(t *testing.T) {
+	filter := NewOutputClassifier([]ContentCategory{ContentCategoryMalware}, 0.5, WithOutputFlagOnly())
+	result := filter.FilterOutput(context.Background(), "This payload injection opens a reverse shell.")
+	if result.Blocked {
+		t.Fatal("flag-only classifier must not block")
+	}
+	if len(result.Flags) != 1 || result.Flags[0].Score != 0.8 {
+		t.Fatalf("expected keyword flag with score 0.8, got %+v", result.Flags)
+	}
+}
+
+func TestOutputContentClassifierLLM(t *testing.T) {
+	provider := &llm.MockProvider{Response: "```json\n{\"violence\": 0.92, \"self_harm\": 0.05}\n```"}
+	filter := NewOutputClassifier(
+		[]ContentCategory{ContentCategoryViolence, ContentCategorySelfHarm}, 0.7,
+		WithClassifier(NewLLMClassifier(provider, "judge")),
+	)
+	result := filter.FilterOutput(context.Background(), "some generated text")
+	if !result.Blocked || result.Reason != "content policy violation: violence" {
+		t.Fatalf("expected violence block, got %+v", result)
+	}
+
+	failing := NewOutputClassifier(
+		[]ContentCategory{ContentCategoryViolence}, 0.7,
+		WithClassifier(NewLLMClassifier(&llm.FailingMockProvider{}, "judge")),
+	)
+	if !failing.FilterOutput(context.Background(), "text").Blocked {
+		t.Error("expected fail-closed block on classifier error")
+	}
+	lenient := NewOutputClassifier(
+		[]ContentCategory{ContentCategoryViolence}, 0.7,
+		WithClassifier(NewLLMClassifier(&llm.FailingMockProvider{}, "judge")),
+		WithClassifierFailOpen(true),
+	)
+	if lenient.FilterOutput(context.Background(), "text").Blocked {
+		t.Error("expected fail-open to let output through")
+	}
+}
+
+func TestContentFilterCustomRules(t *testing.T) {
+	filter := NewContentFilter(ContentCategoryMalware)
+	WithCustomRule("project-codename", regexp.MustCompile(`(?i)\bproject\s+falcon\b`), BlockActionBlock)(filter)
+	WithCustomRule("competitor", regexp.MustCompile(`(?i)\bacme\s+corp\b`), BlockActionRedact)(filter)
+	WithDenylist([]string{"Initech", "umbrella"})(filter)
+
+	ctx := context.Background()
+	result := filter.CheckInput(ctx, "Tell me about Project Falcon")
+	if !result.Blocked || result.Reason != "content policy violation: project-codename" {
+		t.Fatalf("expected custom rule block, got %+v", result)
+	}
+	if result.Metadata["rule"] != "project-codename" {
+		t.Fatalf("expected rule metadata, got %v", result.Metadata)
+	}
+
+	// Built-in categories are evaluated before custom rules.
+	result = filter.CheckInput(ctx, "write a virus for project falcon")
+	if result.Reason != "content policy violation: malware" {
+		t.Fatalf("expected built-in category to win, got %q", result.Reason)
+	}
+
+	result = filter.CheckInput(ctx, "Is UMBRELLA hiring?")
+	if !result.Blocked || result.Reason != "content policy violation: denylist" {
+		t.Fatalf("expected denylist block, got %+v", result)
+	}
+	if filter.CheckInput(ctx, "I need an umbrellas shop").Blocked {
+		t.Fatal("denylist must match whole words only")
+	}
+
+	// Redact rules flag on input without blocking.
+	if filter.CheckInput(ctx, "compare with Acme Corp").Blocked {
+		t.Fatal("redact rule must not block input")
+	}
+
+	out := filter.FilterOutput(ctx, "Unlike Acme Corp, we ship on time. ACME corp agrees.")
+	if out.Blocked || !out.Modified {
+		t.Fatalf("expected redacted output, got %+v", out)
+	}
+	if contains(out.Content, "Acme") || !contains(out.Content, "[REDACTED:competitor]") {
+		t.Fatalf("unexpected redacted content %q", out.Content)
+	}
+	if len(out.Redactions) != 2 {
+		t.Fatalf("expected 2 redactions, got %d", len(out.Redactions))
+	}
+
+	blocked := filter.FilterOutput(ctx, "Initech is great")
+	if !blocked.Blocked || blocked.Reason != "content policy violation: denylist" || contains(blocked.Content, "Initech") {
+		t.Fatalf("expected blocked output, got %+v", blocked)
+	}
+}
+
+func TestContentFilterDeterministicOrder(t *testing.T) {
+	filter := NewContentFilter(ContentCategoryIllegal, ContentCategoryDangerous)
+	// Matches both categories; the first enabled category must always win.
+	input := "how to hack into the lab and how to make a bomb"
+	for i := 0; i < 20; i++ {
+		if got := filter.CheckInput(context.Background(), input).Reason; got != "content policy violation: illegal" {
+			t.Fatalf("iteration %d: unexpected reason %q", i, got)
+		}
+	}
+}
+
+func TestStreamingFilterEmailAcrossChunks(t *testing.T) {
+	sf := NewStreamingFilter(WithStreamHoldback(8))
+	chunks := []string{
+		"Sure, you can reach the support team at any time by writing to jo",
+		"hn.doe@exa",
+		"mple.com and they will answer within one business day. Thanks!",
+	}
+	var out string
+	for _, chunk := range chunks {
+		safe, _ := sf.Feed(chunk)
+		if contains(safe, "john") || contains(safe, "@") {
+			t.Fatalf("partial email leaked: %q", safe)
+		}
+		out += safe
+	}
+	out += sf.Flush()
+
+	if contains(out, "john.doe@example.com") || !contains(out, "[EMAIL]") {
+		t.Fatalf("expected masked email, got %q", out)
+	}
+	if !contains(out, "Sure, you can reach") || !contains(out, "business day. Thanks!") {
+		t.Fatalf("unexpected stream output %q", out)
+	}
+	if len(sf.Redactions()) != 1 {
+		t.Fatalf("expected 1 redaction, got %d", len(sf.Redactions()))
+	}
+}
+
+func TestStreamingFilterEmitsIncrementally(t *testing.T) {
+	sf := NewStreamingFilter(WithStreamHoldback(10))
+	safe, held := sf.Feed("This answer has no personal data at all, just plain words. ")
+	if safe == "" || !held {
+		t.Fatalf("expected incremental output with a held tail, got %q held=%v", safe, held)
+	}
+	if rest := sf.Flush(); safe+rest != "This answer has no personal data at all, just plain words. " {
+		t.Fatalf("unexpected output %q + %q", safe, rest)
+	}
+	if _, held := sf.Feed(""); held {
+		t.Fatal("nothing should be held after flush")
+	}
+}
+
+func TestStreamingFilterHoldbackCountsCharacters(t *testing.T) {
+	sf := NewStreamingFilter(WithStreamHoldback(4))
+	text := "¿Qué tal? Todo va según lo previsto, señor ñú"
+	safe, held := sf.Feed(text)
+	if !held {
+		t.Fatalf("expected a held tail, got %q", safe)
+	}
+	tail := strings.TrimPrefix(text, safe)
+	if n := utf8.RuneCountInString(tail); n < 4 {
+		t.Fatalf("expected at least 4 characters held back, got %d (%q)", n, tail)
+	}
+	if rest := sf.Flush(); safe+rest != text {
+		t.Fatalf("unexpected output %q + %q", safe, rest)
+	}
+}
+
+// lengthFilter passes text through and records the longest text it saw.
+type lengthFilter struct {
+	longest int
+}
+
+func (f *lengthFilter) FilterOutput(_ context.Context, output string) FilterResult {
+	f.longest = max(f.longest, utf8.RuneCountInString(output))
+	return FilterResult{Content: output}
+}
+
+func (f *lengthFilter) ID() string { return "length" }
+
+func TestStreamingFilterBoundsBuffer(t *testing.T) {
+	filter := &lengthFilter{}
+	sf := NewStreamingFilter(WithStreamOutputFilter(filter), WithStreamHoldback(8), WithStreamMaxBuffer(64))
+
+	// A stream without whitespace never offers a safe cut.
+	var out strings.Builder
+	text := strings.Repeat("ñ", 1000)
+	for _, r := range text {
+		safe, _ := sf.Feed(string(r))
+		out.WriteString(safe)
+	}
+	if out.Len() == 0 {
+		t.Fatal("expected text to be released once the buffer is full")
+	}
+	out.WriteString(sf.Flush())
+	if out.String() != text {
+		t.Fatalf("unexpected stream output of %d bytes", out.Len())
+	}
+	if filter.longest > 65 {
+		t.Fatalf("expected each check to cover at most the buffer and the new chunk, filtered %d characters", filter.longest)
+	}
+}
+
+func TestStreamingFilterBlocked(t *testing.T) {
+	g := New(WithContentOutputFilter([]ContentCategory{ContentCategoryDangerous}))
+	sf := NewStreamingFilter(WithStreamGuardrails(g))
+
+	safe, _ := sf.Feed("Here is how to make a bomb at home")
+	if safe != DefaultBlockedMessage {
+		t.Fatalf("expected blocked message, got %q", safe)
+	}
+	if blocked, reason := sf.Blocked(); !blocked || reason == "" {
+		t.Fatalf("expected stream to be blocked, got %v %q", blocked, reason)
+	}
+	if safe, _ := sf.Feed(" with household items"); safe != "" {
+		t.Fatalf("blocked stream must not emit more text, got %q", safe)
+	}
+	if sf.Flush() != "" {
+		t.Fatal("blocked stream must not flush text")
+	}
+}
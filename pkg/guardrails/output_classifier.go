@@ -0,0 +1,265 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package guardrails
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// CategoryScore is the score a classifier assigns to a content category.
+type CategoryScore struct {
+	// Category is the content category that was scored.
+	Category ContentCategory
+
+	// Score is the likelihood (0.0-1.0) that the text belongs to Category.
+	Score float64
+}
+
+// ContentClassifier scores text against content categories.
+// Implementations may be pattern-based, ML models, or LLM judges.
+type ContentClassifier interface {
+	// Classify returns a score for each requested category. Categories the
+	// classifier does not support may be omitted.
+	Classify(ctx context.Context, text string, categories []ContentCategory) ([]CategoryScore, error)
+}
+
+// ContentClassifierFunc adapts a function to the ContentClassifier interface.
+type ContentClassifierFunc func(ctx context.Context, text string, categories []ContentCategory) ([]CategoryScore, error)
+
+// Classify calls f(ctx, text, categories).
+func (f ContentClassifierFunc) Classify(ctx context.Context, text string, categories []ContentCategory) ([]CategoryScore, error) {
+	return f(ctx, text, categories)
+}
+
+// PatternClassifier scores text using the built-in content patterns.
+// Pattern matches score 0.9 and keyword matches 0.8, matching ContentFilter.
+type PatternClassifier struct {
+	patterns map[ContentCategory]contentPattern
+}
+
+// NewPatternClassifier creates a classifier backed by the default content patterns.
+func NewPatternClassifier() *PatternClassifier {
+	c := &PatternClassifier{patterns: make(map[ContentCategory]contentPattern, len(defaultContentPatterns))}
+	for cat, def := range defaultContentPatterns {
+		cp := contentPattern{category: cat, keywords: def.keywords}
+		for _, p := range def.patterns {
+			if re, err := regexp.Compile(p); err == nil {
+				cp.patterns = append(cp.patterns, re)
+			}
+		}
+		c.patterns[cat] = cp
+	}
+	return c
+}
+
+// Classify implements ContentClassifier.
+func (c *PatternClassifier) Classify(ctx context.Context, text string, categories []ContentCategory) ([]CategoryScore, error) {
+	normalized := strings.ToLower(text)
+	scores := make([]CategoryScore, 0, len(categories))
+	for _, cat := range categories {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cp, ok := c.patterns[cat]
+		if !ok {
+			continue
+		}
+		score := 0.0
+		for _, pattern := range cp.patterns {
+			if pattern.MatchString(normalized) {
+				score = 0.9
+				break
+			}
+		}
+		if score == 0 {
+			for _, keyword := range cp.keywords {
+				if strings.Contains(normalized, strings.ToLower(keyword)) {
+					score = 0.8
+					break
+				}
+			}
+		}
+		scores = append(scores, CategoryScore{Category: cat, Score: score})
+	}
+	return scores, nil
+}
+
+// LLMClassifier asks an LLM to score text against content categories.
+// The model is expected to answer with a JSON object mapping each category
+// to a score between 0 and 1.
+type LLMClassifier struct {
+	provider llm.Provider
+	model    string
+}
+
+// NewLLMClassifier creates a classifier that uses provider as a judge.
+func NewLLMClassifier(provider llm.Provider, model string) *LLMClassifier {
+	return &LLMClassifier{provider: provider, model: model}
+}
+
+const llmClassifierPrompt = `You are a content safety classifier. Score the text provided by the user
+for each of these categories: %s.
+Respond only with a JSON object mapping each category to a score between 0 and 1,
+for example {"violence": 0.1, "self_harm": 0.0}.`
+
+// Classify implements ContentClassifier.
+func (c *LLMClassifier) Classify(ctx context.Context, text string, categories []ContentCategory) ([]CategoryScore, error) {
+	if c.provider == nil {
+		return nil, fmt.Errorf("llm classifier: provider is nil")
+	}
+	names := make([]string, 0, len(categories))
+	for _, cat := range categories {
+		names = append(names, string(cat))
+	}
+	resp, err := c.provider.Chat(ctx, llm.ChatRequest{
+		Model: c.model,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: fmt.Sprintf(llmClassifierPrompt, strings.Join(names, ", "))},
+			{Role: llm.RoleUser, Content: text},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("llm classifier: %w", err)
+	}
+
+	raw, err := llm.ExtractJSON(resp.Content)
+	if err != nil {
+		return nil, fmt.Errorf("llm classifier: invalid response: %w", err)
+	}
+	var parsed map[string]float64
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("llm classifier: invalid response: %w", err)
+	}
+
+	scores := make([]CategoryScore, 0, len(categories))
+	for _, cat := range categories {
+		if score, ok := parsed[string(cat)]; ok {
+			scores = append(scores, CategoryScore{Category: cat, Score: score})
+		}
+	}
+	return scores, nil
+}
+
+// OutputClassifier is an OutputFilter that blocks or flags LLM outputs
+// classified into unsafe content categories.
+type OutputClassifier struct {
+	classifier     ContentClassifier
+	categories     []ContentCategory
+	threshold      float64
+	flagOnly       bool
+	failOpen       bool
+	blockedMessage string
+}
+
+// OutputClassifierOption configures an OutputClassifier.
+type OutputClassifierOption func(*OutputClassifier)
+
+// WithClassifier replaces the default pattern classifier, e.g. with an
+// LLMClassifier or a hosted moderation model.
+func WithClassifier(classifier ContentClassifier) OutputClassifierOption {
+	return func(c *OutputClassifier) {
+		if classifier != nil {
+			c.classifier = classifier
+		}
+	}
+}
+
+// WithOutputFlagOnly reports categories above the threshold in
+// FilterResult.Flags without blocking the output.
+func WithOutputFlagOnly() OutputClassifierOption {
+	return func(c *OutputClassifier) {
+		c.flagOnly = true
+	}
+}
+
+// WithClassifierFailOpen lets outputs through when the classifier fails.
+// Default is fail-closed.
+func WithClassifierFailOpen(failOpen bool) OutputClassifierOption {
+	return func(c *OutputClassifier) {
+		c.failOpen = failOpen
+	}
+}
+
+// WithBlockedMessage sets the content returned in place of a blocked output.
+func WithBlockedMessage(message string) OutputClassifierOption {
+	return func(c *OutputClassifier) {
+		c.blockedMessage = message
+	}
+}
+
+// NewOutputClassifier creates an output guard for the given categories.
+// Outputs scoring at or above threshold in any category are blocked.
+func NewOutputClassifier(categories []ContentCategory, threshold float64, opts ...OutputClassifierOption) *OutputClassifier {
+	c := &OutputClassifier{
+		classifier:     NewPatternClassifier(),
+		categories:     append([]ContentCategory(nil), categories...),
+		threshold:      threshold,
+		blockedMessage: DefaultBlockedMessage,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ID returns the guardrail identifier.
+func (c *OutputClassifier) ID() string {
+	return "output-content-classifier"
+}
+
+// FilterOutput classifies output and blocks or flags unsafe content.
+func (c *OutputClassifier) FilterOutput(ctx context.Context, output string) FilterResult {
+	result := FilterResult{Content: output}
+	if strings.TrimSpace(output) == "" || len(c.categories) == 0 {
+		return result
+	}
+
+	scores, err := c.classifier.Classify(ctx, output, c.categories)
+	if err != nil {
+		if c.failOpen {
+			return result
+		}
+		return c.block(result, "output classification failed: "+err.Error())
+	}
+
+	for _, score := range scores {
+		if score.Score >= c.threshold {
+			result.Flags = append(result.Flags, score)
+		}
+	}
+	if len(result.Flags) == 0 {
+		return result
+	}
+	sort.SliceStable(result.Flags, func(i, j int) bool {
+		return result.Flags[i].Score > result.Flags[j].Score
+	})
+	if c.flagOnly {
+		return result
+	}
+	return c.block(result, "content policy violation: "+string(result.Flags[0].Category))
+}
+
+func (c *OutputClassifier) block(result FilterResult, reason string) FilterResult {
+	result.Blocked = true
+	result.Reason = reason
+	result.GuardrailID = c.ID()
+	result.Modified = true
+	result.Content = c.blockedMessage
+	return result
+}
+
+// WithOutputContentClassifier returns an option that classifies outputs
+// against categories, blocking those scoring at or above threshold.
+func WithOutputContentClassifier(categories []ContentCategory, threshold float64, opts ...OutputClassifierOption) Option {
+	return func(g *Guardrails) {
+		g.outputFilters = append(g.outputFilters, NewOutputClassifier(categories, threshold, opts...))
+	}
+}
@@ -34,13 +34,58 @@ const (
 	PIITypeAddress     PIIType = "address"
 	PIITypePassport    PIIType = "passport"
 	PIITypeDateOfBirth PIIType = "date_of_birth"
+	PIITypeNationalID  PIIType = "national_id"
 )
 
+// PIILocale identifies the country whose formats a detector recognizes.
+type PIILocale string
+
+const (
+	// PIILocaleUS covers United States formats (e.g., SSN).
+	PIILocaleUS PIILocale = "us"
+	// PIILocaleES covers Spanish formats (DNI, NIE, Seguridad Social).
+	PIILocaleES PIILocale = "es"
+)
+
+// DefaultPIIConfidenceThreshold is the minimum confidence a detection needs
+// to be reported. Pattern matches always score 1.0; only fuzzy detectors
+// such as names produce lower scores.
+const DefaultPIIConfidenceThreshold = 0.8
+
 // piiPattern defines a pattern for detecting a type of PII.
 type piiPattern struct {
 	piiType PIIType
 	pattern *regexp.Regexp
 	mask    string
+	// locale restricts the pattern to a country format (empty = universal).
+	locale PIILocale
+	// validate rejects regex matches that fail a checksum or format check.
+	validate func(match string) bool
+	// detect replaces pattern for detectors that score their own matches.
+	detect func(text string) []piiMatch
+}
+
+// piiMatch is a detected span with its confidence.
+type piiMatch struct {
+	start      int
+	end        int
+	confidence float64
+}
+
+// matches returns the spans detected by the pattern in text.
+func (p piiPattern) matches(text string) []piiMatch {
+	if p.detect != nil {
+		return p.detect(text)
+	}
+	indexes := p.pattern.FindAllStringIndex(text, -1)
+	out := make([]piiMatch, 0, len(indexes))
+	for _, idx := range indexes {
+		if p.validate != nil && !p.validate(text[idx[0]:idx[1]]) {
+			continue
+		}
+		out = append(out, piiMatch{start: idx[0], end: idx[1], confidence: 1.0})
+	}
+	return out
 }
 
 // PIIFilter detects and filters Personally Identifiable Information.
@@ -48,6 +93,8 @@ type PIIFilter struct {
 	mode       PIIFilterMode
 	patterns   []piiPattern
 	enabledPII map[PIIType]bool
+	locales    map[PIILocale]bool // nil means all locales
+	threshold  float64
 }
 
 // PIIFilterOption configures the PII filter.
@@ -56,33 +103,42 @@ type PIIFilterOption func(*PIIFilter)
 // Default PII patterns (conservative, high-precision)
 // Order matters - more specific patterns should come first
 var defaultPIIPatterns = []struct {
-	piiType PIIType
-	pattern string
-	mask    string
+	piiType  PIIType
+	pattern  string
+	mask     string
+	locale   PIILocale
+	validate func(string) bool
 }{
 	// Credit card numbers (check before phone due to overlap)
-	{PIITypeCreditCard, `\b[0-9]{4}[-\s]?[0-9]{4}[-\s]?[0-9]{4}[-\s]?[0-9]{4}\b`, "[CREDIT_CARD]"},
-	{PIITypeCreditCard, `\b(?:4[0-9]{12}(?:[0-9]{3})?|5[1-5][0-9]{14}|3[47][0-9]{13}|6(?:011|5[0-9]{2})[0-9]{12})\b`, "[CREDIT_CARD]"},
+	{PIITypeCreditCard, `\b[0-9]{4}[-\s]?[0-9]{4}[-\s]?[0-9]{4}[-\s]?[0-9]{4}\b`, "[CREDIT_CARD]", "", nil},
+	{PIITypeCreditCard, `\b(?:4[0-9]{12}(?:[0-9]{3})?|5[1-5][0-9]{14}|3[47][0-9]{13}|6(?:011|5[0-9]{2})[0-9]{12})\b`, "[CREDIT_CARD]", "", nil},
+
+	// Spanish national IDs (DNI/NIE), validated by their control letter
+	{PIITypeNationalID, `\b[0-9]{8}[-\s]?[A-Za-z]\b`, "[NATIONAL_ID]", PIILocaleES, validSpanishDNI},
+	{PIITypeNationalID, `\b[XYZxyz][-\s]?[0-9]{7}[-\s]?[A-Za-z]\b`, "[NATIONAL_ID]", PIILocaleES, validSpanishNIE},
+
+	// Spanish Social Security affiliation number (NSS), validated mod 97
+	{PIITypeSSN, `\b[0-9]{2}[-/\s]?[0-9]{8}[-/\s]?[0-9]{2}\b`, "[SSN]", PIILocaleES, validSpanishNSS},
 
 	// Social Security Numbers (US) - check before phone
-	{PIITypeSSN, `\b[0-9]{3}[-\s]?[0-9]{2}[-\s]?[0-9]{4}\b`, "[SSN]"},
+	{PIITypeSSN, `\b[0-9]{3}[-\s]?[0-9]{2}[-\s]?[0-9]{4}\b`, "[SSN]", PIILocaleUS, nil},
 
 	// Email addresses
-	{PIITypeEmail, `[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`, "[EMAIL]"},
+	{PIITypeEmail, `[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`, "[EMAIL]", "", nil},
 
 	// Phone numbers (various formats)
-	{PIITypePhone, `\+?1?[-.\s]?\(?[0-9]{3}\)?[-.\s]?[0-9]{3}[-.\s]?[0-9]{4}`, "[PHONE]"},
-	{PIITypePhone, `\+[0-9]{1,3}[-.\s]?[0-9]{6,14}`, "[PHONE]"},
+	{PIITypePhone, `\+?1?[-.\s]?\(?[0-9]{3}\)?[-.\s]?[0-9]{3}[-.\s]?[0-9]{4}`, "[PHONE]", "", nil},
+	{PIITypePhone, `\+[0-9]{1,3}[-.\s]?[0-9]{6,14}`, "[PHONE]", "", nil},
 
 	// IP addresses (IPv4)
-	{PIITypeIPAddress, `\b(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\b`, "[IP_ADDRESS]"},
+	{PIITypeIPAddress, `\b(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\b`, "[IP_ADDRESS]", "", nil},
 
 	// Dates that could be DOB (various formats)
-	{PIITypeDateOfBirth, `\b(?:0?[1-9]|1[0-2])[/-](?:0?[1-9]|[12][0-9]|3[01])[/-](?:19|20)[0-9]{2}\b`, "[DATE]"},
-	{PIITypeDateOfBirth, `\b(?:19|20)[0-9]{2}[/-](?:0?[1-9]|1[0-2])[/-](?:0?[1-9]|[12][0-9]|3[01])\b`, "[DATE]"},
+	{PIITypeDateOfBirth, `\b(?:0?[1-9]|1[0-2])[/-](?:0?[1-9]|[12][0-9]|3[01])[/-](?:19|20)[0-9]{2}\b`, "[DATE]", "", nil},
+	{PIITypeDateOfBirth, `\b(?:19|20)[0-9]{2}[/-](?:0?[1-9]|1[0-2])[/-](?:0?[1-9]|[12][0-9]|3[01])\b`, "[DATE]", "", nil},
 
 	// Passport numbers (generic pattern)
-	{PIITypePassport, `\b[A-Z]{1,2}[0-9]{6,9}\b`, "[PASSPORT]"},
+	{PIITypePassport, `\b[A-Z]{1,2}[0-9]{6,9}\b`, "[PASSPORT]", "", nil},
 }
 
 // NewPIIFilter creates a new PII filter.
@@ -91,6 +147,7 @@ func NewPIIFilter(mode PIIFilterMode, opts ...PIIFilterOption) *PIIFilter {
 		mode:       mode,
 		patterns:   make([]piiPattern, 0),
 		enabledPII: make(map[PIIType]bool),
+		threshold:  DefaultPIIConfidenceThreshold,
 	}
 
 	// Enable all PII types by default
@@ -102,13 +159,23 @@ func NewPIIFilter(mode PIIFilterMode, opts ...PIIFilterOption) *PIIFilter {
 	for _, p := range defaultPIIPatterns {
 		if re, err := regexp.Compile(p.pattern); err == nil {
 			f.patterns = append(f.patterns, piiPattern{
-				piiType: p.piiType,
-				pattern: re,
-				mask:    p.mask,
+				piiType:  p.piiType,
+				pattern:  re,
+				mask:     p.mask,
+				locale:   p.locale,
+				validate: p.validate,
 			})
 		}
 	}
 
+	// Name detection is heuristic, so it is registered but stays disabled
+	// until requested via WithPIITypes or WithIncludePII.
+	f.patterns = append(f.patterns, piiPattern{
+		piiType: PIITypeName,
+		mask:    "[NAME]",
+		detect:  detectNames,
+	})
+
 	for _, opt := range opts {
 		opt(f)
 	}
@@ -139,6 +206,37 @@ func WithExcludePII(types ...PIIType) PIIFilterOption {
 	}
 }
 
+// WithIncludePII enables additional PII types on top of the defaults,
+// e.g. WithIncludePII(PIITypeName) to also detect person names.
+func WithIncludePII(types ...PIIType) PIIFilterOption {
+	return func(f *PIIFilter) {
+		for _, t := range types {
+			f.enabledPII[t] = true
+		}
+	}
+}
+
+// WithPIILocales restricts locale-specific detectors (national IDs, social
+// security numbers) to the given locales. Universal detectors such as email
+// or IP addresses are unaffected. By default all locales are enabled.
+func WithPIILocales(locales ...PIILocale) PIIFilterOption {
+	return func(f *PIIFilter) {
+		f.locales = make(map[PIILocale]bool, len(locales))
+		for _, l := range locales {
+			f.locales[l] = true
+		}
+	}
+}
+
+// WithPIIConfidenceThreshold sets the minimum confidence (0.0-1.0) a
+// detection needs to be filtered. It mainly gates fuzzy detectors such as
+// names; pattern-based detections always score 1.0.
+func WithPIIConfidenceThreshold(threshold float64) PIIFilterOption {
+	return func(f *PIIFilter) {
+		f.threshold = threshold
+	}
+}
+
 // WithCustomPIIPattern adds a custom PII pattern.
 func WithCustomPIIPattern(piiType PIIType, pattern, mask string) PIIFilterOption {
 	return func(f *PIIFilter) {
@@ -158,6 +256,29 @@ func (f *PIIFilter) ID() string {
 	return "pii-filter"
 }
 
+// enabled reports whether the pattern should run with the current options.
+func (f *PIIFilter) enabled(p piiPattern) bool {
+	if !f.enabledPII[p.piiType] {
+		return false
+	}
+	if p.locale != "" && f.locales != nil && !f.locales[p.locale] {
+		return false
+	}
+	return true
+}
+
+// detect returns the matches of p above the confidence threshold.
+func (f *PIIFilter) detect(p piiPattern, text string) []piiMatch {
+	matches := p.matches(text)
+	out := matches[:0]
+	for _, m := range matches {
+		if m.confidence >= f.threshold {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
 // FilterOutput processes output and masks/removes PII.
 func (f *PIIFilter) FilterOutput(ctx context.Context, output string) FilterResult {
 	if output == "" {
@@ -172,7 +293,7 @@ func (f *PIIFilter) FilterOutput(ctx context.Context, output string) FilterResul
 
 	// Process each enabled pattern
 	for _, p := range f.patterns {
-		if !f.enabledPII[p.piiType] {
+		if !f.enabled(p) {
 			continue
 		}
 
@@ -182,7 +303,7 @@ func (f *PIIFilter) FilterOutput(ctx context.Context, output string) FilterResul
 		default:
 		}
 
-		matches := p.pattern.FindAllStringIndex(result.Content, -1)
+		matches := f.detect(p, result.Content)
 		if len(matches) == 0 {
 			continue
 		}
@@ -190,17 +311,18 @@ func (f *PIIFilter) FilterOutput(ctx context.Context, output string) FilterResul
 		// Process matches in reverse order to preserve positions
 		for i := len(matches) - 1; i >= 0; i-- {
 			match := matches[i]
-			original := result.Content[match[0]:match[1]]
+			original := result.Content[match.start:match.end]
 			replacement := f.getReplacement(p, original)
 
 			result.Redactions = append(result.Redactions, Redaction{
 				Type:        string(p.piiType),
 				Original:    "", // Don't expose PII in redaction log
 				Replacement: replacement,
-				Position:    match[0],
+				Position:    match.start,
+				Confidence:  match.confidence,
 			})
 
-			result.Content = result.Content[:match[0]] + replacement + result.Content[match[1]:]
+			result.Content = result.Content[:match.start] + replacement + result.Content[match.end:]
 			result.Modified = true
 		}
 	}
@@ -244,7 +366,7 @@ func (f *PIIFilter) CheckInput(ctx context.Context, input string) CheckResult {
 	}
 
 	for _, p := range f.patterns {
-		if !f.enabledPII[p.piiType] {
+		if !f.enabled(p) {
 			continue
 		}
 
@@ -254,12 +376,12 @@ func (f *PIIFilter) CheckInput(ctx context.Context, input string) CheckResult {
 		default:
 		}
 
-		if p.pattern.MatchString(input) {
+		if matches := f.detect(p, input); len(matches) > 0 {
 			return CheckResult{
 				Blocked:     true,
 				Reason:      "PII detected in input: " + string(p.piiType),
 				GuardrailID: f.ID(),
-				Confidence:  1.0,
+				Confidence:  matches[0].confidence,
 				Metadata: map[string]any{
 					"pii_type": string(p.piiType),
 				},
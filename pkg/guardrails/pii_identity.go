@@ -0,0 +1,180 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package guardrails
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// dniLetters maps (number mod 23) to the DNI/NIE control letter.
+const dniLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+// validSpanishDNI checks the control letter of a DNI ("12345678Z").
+func validSpanishDNI(match string) bool {
+	id := normalizeID(match)
+	if len(id) != 9 {
+		return false
+	}
+	return dniControlMatches(id[:8], id[8])
+}
+
+// validSpanishNIE checks the control letter of a NIE ("X1234567L").
+func validSpanishNIE(match string) bool {
+	id := normalizeID(match)
+	if len(id) != 9 {
+		return false
+	}
+	prefix := strings.IndexByte("XYZ", id[0])
+	if prefix < 0 {
+		return false
+	}
+	return dniControlMatches(strconv.Itoa(prefix)+id[1:8], id[8])
+}
+
+func dniControlMatches(digits string, letter byte) bool {
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return false
+	}
+	return dniLetters[n%23] == letter
+}
+
+// validSpanishNSS checks a Social Security affiliation number
+// (2-digit province, 8-digit sequence, 2-digit mod 97 control).
+func validSpanishNSS(match string) bool {
+	id := normalizeID(match)
+	if len(id) != 12 {
+		return false
+	}
+	province, err := strconv.Atoi(id[:2])
+	if err != nil {
+		return false
+	}
+	seq, err := strconv.Atoi(id[2:10])
+	if err != nil {
+		return false
+	}
+	control, err := strconv.Atoi(id[10:])
+	if err != nil {
+		return false
+	}
+	base := seq + province*100000000
+	if seq < 10000000 {
+		base = seq + province*10000000
+	}
+	return base%97 == control
+}
+
+func normalizeID(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '/', ' ', '\t':
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, value)
+}
+
+// namePart matches a capitalized word, optionally joined by Spanish/European
+// particles ("de", "del", "de la", "y", "van", "von").
+const namePart = `\p{Lu}[\p{Ll}'’-]+(?:\s+(?:(?:de|del|de la|de los|y|van|von)\s+)?\p{Lu}[\p{Ll}'’-]+){0,3}`
+
+// nameRules are heuristic name detectors. Each captures the name in group 1
+// and assigns a confidence reflecting how strong the surrounding cue is.
+var nameRules = []struct {
+	pattern    *regexp.Regexp
+	confidence float64
+}{
+	// Honorifics: "Sr. García", "Dña. María López", "Dr. Smith"
+	{regexp.MustCompile(`(?:^|[^\p{L}])(?:Sr\.|Sra\.|Srta\.|Dña\.|Don|Doña|Dr\.|Dra\.|Mr\.|Mrs\.|Ms\.|Dr)\s+(` + namePart + `)`), 0.9},
+	// Labeled fields: "Nombre: Ana Ruiz", "Titular: ...", "Name: ..."
+	{regexp.MustCompile(`(?i:nombre|apellidos|titular|paciente|cliente|name|full name|holder|patient)\s*:\s*(` + namePart + `)`), 0.9},
+	// Self introductions: "me llamo Ana", "my name is John Smith"
+	{regexp.MustCompile(`(?i:me llamo|mi nombre es|my name is)\s+(` + namePart + `)`), 0.85},
+	// Bare capitalized sequences starting with a known first name
+	{regexp.MustCompile(`(?:^|[^\p{L}])(` + namePart + `)`), 0.7},
+}
+
+// commonFirstNames backs the low-confidence bare-name rule.
+var commonFirstNames = map[string]bool{
+	"antonio": true, "manuel": true, "josé": true, "jose": true, "francisco": true,
+	"david": true, "juan": true, "javier": true, "daniel": true, "carlos": true,
+	"jesús": true, "jesus": true, "alejandro": true, "miguel": true, "rafael": true,
+	"pedro": true, "pablo": true, "luis": true, "jorge": true, "sergio": true,
+	"maría": true, "maria": true, "carmen": true, "ana": true, "isabel": true,
+	"laura": true, "lucía": true, "lucia": true, "cristina": true, "marta": true,
+	"elena": true, "sara": true, "paula": true, "raquel": true, "pilar": true,
+	"john": true, "james": true, "robert": true, "michael": true, "william": true,
+	"mary": true, "patricia": true, "jennifer": true, "linda": true, "elizabeth": true,
+}
+
+// detectNames finds likely person names. Overlapping detections keep the
+// one with the highest confidence.
+func detectNames(text string) []piiMatch {
+	var found []piiMatch
+	for i, rule := range nameRules {
+		bare := i == len(nameRules)-1
+		for _, idx := range rule.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := idx[2], idx[3]
+			if start < 0 {
+				continue
+			}
+			if bare {
+				if start = firstNameOffset(text, start, end); start < 0 {
+					continue
+				}
+			}
+			found = append(found, piiMatch{start: start, end: end, confidence: rule.confidence})
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].confidence != found[j].confidence {
+			return found[i].confidence > found[j].confidence
+		}
+		return found[i].start < found[j].start
+	})
+	kept := make([]piiMatch, 0, len(found))
+	for _, m := range found {
+		overlaps := false
+		for _, k := range kept {
+			if m.start < k.end && k.start < m.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, m)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].start < kept[j].start })
+	return kept
+}
+
+// firstNameOffset returns the offset of the first known first name in
+// text[start:end] that is followed by at least one more capitalized word,
+// skipping leading capitalized words such as sentence starts ("Ayer Pedro
+// Martín"). It returns -1 when there is none.
+func firstNameOffset(text string, start, end int) int {
+	span := text[start:end]
+	offset := 0
+	for {
+		sep := strings.IndexFunc(span[offset:], unicode.IsSpace)
+		if sep < 0 {
+			return -1
+		}
+		if commonFirstNames[strings.ToLower(span[offset:offset+sep])] {
+			return start + offset
+		}
+		rest := span[offset+sep:]
+		offset += sep + len(rest) - len(strings.TrimLeftFunc(rest, unicode.IsSpace))
+	}
+}
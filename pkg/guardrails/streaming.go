@@ -0,0 +1,248 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package guardrails
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultStreamHoldback is the number of trailing characters a
+// StreamingFilter keeps buffered so that PII split across chunks (an email
+// or phone number cut in two deltas) is detected before being emitted.
+const DefaultStreamHoldback = 48
+
+// DefaultStreamMaxBuffer is the number of characters a StreamingFilter
+// buffers at most while it waits for a safe place to cut the stream.
+const DefaultStreamMaxBuffer = 4096
+
+// StreamingFilter applies output filters to streamed text incrementally.
+//
+// Feed each delta as it arrives and forward the returned safe text; call
+// Flush when the stream ends to release the buffered tail:
+//
+//	sf := guardrails.NewStreamingFilter()
+//	for chunk := range chunks {
+//	    if safe, _ := sf.Feed(chunk.Content); safe != "" {
+//	        send(safe)
+//	    }
+//	}
+//	send(sf.Flush())
+//
+// Only a small window of text is held back, so masking works without
+// buffering the entire answer. Each Feed filters the held window plus the
+// new chunk; text that finds no safe cut is released once the buffer
+// reaches its maximum, so the work per chunk stays bounded.
+type StreamingFilter struct {
+	mu         sync.Mutex
+	ctx        context.Context
+	filter     func(ctx context.Context, text string) FilterResult
+	holdback   int
+	maxBuffer  int
+	pending    string
+	emitted    int
+	redactions []Redaction
+	blocked    bool
+	reason     string
+}
+
+// StreamingFilterOption configures a StreamingFilter.
+type StreamingFilterOption func(*StreamingFilter)
+
+// WithStreamGuardrails filters the stream with all output filters of g.
+func WithStreamGuardrails(g *Guardrails) StreamingFilterOption {
+	return func(s *StreamingFilter) {
+		if g != nil {
+			s.filter = g.FilterOutput
+		}
+	}
+}
+
+// WithStreamOutputFilter filters the stream with a single output filter.
+func WithStreamOutputFilter(filter OutputFilter) StreamingFilterOption {
+	return func(s *StreamingFilter) {
+		if filter != nil {
+			s.filter = filter.FilterOutput
+		}
+	}
+}
+
+// WithStreamHoldback sets how many trailing characters are held back.
+// It should exceed the longest PII value expected to span chunks.
+func WithStreamHoldback(chars int) StreamingFilterOption {
+	return func(s *StreamingFilter) {
+		if chars >= 0 {
+			s.holdback = chars
+		}
+	}
+}
+
+// WithStreamMaxBuffer sets how many characters may be buffered while no
+// safe cut is found. Past it, the text before the holdback window is
+// filtered and emitted on its own, even if a detection straddles the cut.
+func WithStreamMaxBuffer(chars int) StreamingFilterOption {
+	return func(s *StreamingFilter) {
+		if chars > 0 {
+			s.maxBuffer = chars
+		}
+	}
+}
+
+// WithStreamContext sets the context passed to the underlying filters.
+func WithStreamContext(ctx context.Context) StreamingFilterOption {
+	return func(s *StreamingFilter) {
+		if ctx != nil {
+			s.ctx = ctx
+		}
+	}
+}
+
+// NewStreamingFilter creates a streaming filter. By default it masks PII
+// with NewPIIFilter(PIIFilterMask).
+func NewStreamingFilter(opts ...StreamingFilterOption) *StreamingFilter {
+	s := &StreamingFilter{
+		ctx:       context.Background(),
+		filter:    NewPIIFilter(PIIFilterMask).FilterOutput,
+		holdback:  DefaultStreamHoldback,
+		maxBuffer: DefaultStreamMaxBuffer,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Feed adds a chunk to the stream. It returns the text that is safe to emit
+// now and whether part of the input is still held back waiting for more
+// context. Once the stream is blocked, Feed returns no further text.
+func (s *StreamingFilter) Feed(chunk string) (safe string, held bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.blocked {
+		return "", false
+	}
+	s.pending += chunk
+
+	full := s.filter(s.ctx, s.pending)
+	if full.Blocked {
+		return s.block(full), false
+	}
+
+	limit := s.holdbackStart()
+	cuts := s.cutCandidates(limit)
+	for _, cut := range cuts {
+		prefix := s.filter(s.ctx, s.pending[:cut])
+		if prefix.Blocked {
+			return s.block(prefix), false
+		}
+		// A detection straddling the cut changes the filtered prefix; only
+		// emit when the prefix filters exactly as it does in full context.
+		if !strings.HasPrefix(full.Content, prefix.Content) {
+			continue
+		}
+		return s.emit(prefix, cut), s.pending != ""
+	}
+
+	// No safe cut: once the buffer is full, release the text before the
+	// holdback window anyway, preferring a cut on whitespace.
+	if limit > 0 && utf8.RuneCountInString(s.pending) > s.maxBuffer {
+		cut := limit
+		if len(cuts) > 0 {
+			cut = cuts[0]
+		}
+		prefix := s.filter(s.ctx, s.pending[:cut])
+		if prefix.Blocked {
+			return s.block(prefix), false
+		}
+		return s.emit(prefix, cut), s.pending != ""
+	}
+	return "", s.pending != ""
+}
+
+// Flush filters and returns any buffered text. Call it when the stream ends.
+func (s *StreamingFilter) Flush() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.blocked || s.pending == "" {
+		return ""
+	}
+	result := s.filter(s.ctx, s.pending)
+	s.pending = ""
+	if result.Blocked {
+		return s.block(result)
+	}
+	s.record(result)
+	return result.Content
+}
+
+// Redactions returns the redactions applied so far. Positions are offsets
+// in the emitted (filtered) stream.
+func (s *StreamingFilter) Redactions() []Redaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Redaction(nil), s.redactions...)
+}
+
+// Blocked reports whether an output filter blocked the stream, and why.
+func (s *StreamingFilter) Blocked() (bool, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blocked, s.reason
+}
+
+// holdbackStart returns the byte offset where the last holdback characters
+// of pending start.
+func (s *StreamingFilter) holdbackStart() int {
+	i := len(s.pending)
+	for n := 0; n < s.holdback && i > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(s.pending[:i])
+		i -= size
+	}
+	return i
+}
+
+// cutCandidates returns positions up to limit where pending may be split,
+// latest first. Cuts fall on whitespace.
+func (s *StreamingFilter) cutCandidates(limit int) []int {
+	const maxCandidates = 8
+	var cuts []int
+	for i := limit; i > 0 && len(cuts) < maxCandidates; i-- {
+		if i < len(s.pending) && isSpaceByte(s.pending[i-1]) && !isSpaceByte(s.pending[i]) {
+			cuts = append(cuts, i)
+		}
+	}
+	return cuts
+}
+
+// emit records result, the filtered text of pending up to cut, and drops
+// that text from pending.
+func (s *StreamingFilter) emit(result FilterResult, cut int) string {
+	s.record(result)
+	s.pending = s.pending[cut:]
+	return result.Content
+}
+
+func (s *StreamingFilter) record(result FilterResult) {
+	for _, r := range result.Redactions {
+		r.Position += s.emitted
+		s.redactions = append(s.redactions, r)
+	}
+	s.emitted += len(result.Content)
+}
+
+func (s *StreamingFilter) block(result FilterResult) string {
+	s.blocked = true
+	s.reason = result.Reason
+	s.pending = ""
+	return result.Content
+}
+
+func isSpaceByte(b byte) bool {
+	return b < 0x80 && unicode.IsSpace(rune(b))
+}
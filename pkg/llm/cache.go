@@ -0,0 +1,339 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	klog "github.com/jllopis/kairos/pkg/log"
+)
+
+var logger = klog.For("llm")
+
+// ResponseCache stores chat responses by request key.
+type ResponseCache interface {
+	// Get returns the cached response for key, if any.
+	Get(ctx context.Context, key string) (*ChatResponse, bool, error)
+	// Set stores resp under key.
+	Set(ctx context.Context, key string, resp *ChatResponse) error
+}
+
+// CachingProvider wraps a Provider and serves repeated identical requests
+// from a ResponseCache, skipping the inner call.
+//
+// Requests are keyed by a SHA-256 hash of model, messages, tools and
+// temperature. Requests with a temperature above zero are not cached
+// unless WithCacheNonDeterministic(true) is set. Cache failures are logged
+// and never fail the request.
+type CachingProvider struct {
+	inner            Provider
+	cache            ResponseCache
+	nonDeterministic bool
+}
+
+// CachingOption configures a CachingProvider.
+type CachingOption func(*CachingProvider)
+
+// WithCacheNonDeterministic enables caching of requests with a temperature
+// above zero, whose responses would otherwise vary between calls.
+func WithCacheNonDeterministic(enabled bool) CachingOption {
+	return func(p *CachingProvider) {
+		p.nonDeterministic = enabled
+	}
+}
+
+// NewCachingProvider wraps inner with cache.
+func NewCachingProvider(inner Provider, cache ResponseCache, opts ...CachingOption) *CachingProvider {
+	p := &CachingProvider{inner: inner, cache: cache}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// SupportsToolCalling implements ToolCallingProvider for the inner provider.
+func (p *CachingProvider) SupportsToolCalling() bool {
+	supported, _ := ToolCallingSupport(p.inner)
+	return supported
+}
+
+func (p *CachingProvider) reportsToolCalling() bool {
+	_, reported := ToolCallingSupport(p.inner)
+	return reported
+}
+
+// SupportsResponseFormat implements ResponseFormatProvider for the inner
+// provider.
+func (p *CachingProvider) SupportsResponseFormat(format ResponseFormatType) bool {
+	return responseFormatSupported(p.inner, format)
+}
+
+// Chat returns the cached response for req or calls the inner provider and
+// caches its response.
+func (p *CachingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	key, ok := p.keyFor(req)
+	if !ok {
+		return p.inner.Chat(ctx, req)
+	}
+	if resp, hit := p.lookup(ctx, key); hit {
+		return resp, nil
+	}
+	resp, err := p.inner.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.store(ctx, key, resp)
+	return resp, nil
+}
+
+// ChatStream serves a cache hit as a single final chunk. On a miss it
+// streams from the inner provider (or calls Chat if it cannot stream) and
+// caches the accumulated response once the stream completes.
+func (p *CachingProvider) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	key, cacheable := p.keyFor(req)
+	if cacheable {
+		if resp, hit := p.lookup(ctx, key); hit {
+			return singleChunk(resp), nil
+		}
+	}
+
+	streaming, ok := p.inner.(StreamingProvider)
+	if !ok {
+		resp, err := p.inner.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if cacheable {
+			p.store(ctx, key, resp)
+		}
+		return singleChunk(resp), nil
+	}
+
+	in, err := streaming.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !cacheable {
+		return in, nil
+	}
+	out := make(chan StreamChunk, cap(in))
+	go func() {
+		defer close(out)
+		acc := &ChatResponse{}
+		failed := false
+		for chunk := range in {
+			acc.Content += chunk.Content
+			if chunk.Error != nil {
+				failed = true
+			}
+			if chunk.Done {
+				acc.ToolCalls = chunk.ToolCalls
+				if chunk.Usage != nil {
+					acc.Usage = *chunk.Usage
+				}
+				if !failed {
+					p.store(ctx, key, acc)
+				}
+			}
+			out <- chunk
+		}
+	}()
+	return out, nil
+}
+
+func (p *CachingProvider) keyFor(req ChatRequest) (string, bool) {
+	if req.Temperature > 0 && !p.nonDeterministic {
+		return "", false
+	}
+	key, err := CacheKey(req)
+	if err != nil {
+		logger.Warn("llm.cache.key_failed", slog.String("error", err.Error()))
+		return "", false
+	}
+	return key, true
+}
+
+func (p *CachingProvider) lookup(ctx context.Context, key string) (*ChatResponse, bool) {
+	resp, ok, err := p.cache.Get(ctx, key)
+	if err != nil {
+		logger.Warn("llm.cache.get_failed", slog.String("key", key), slog.String("error", err.Error()))
+		return nil, false
+	}
+	return resp, ok
+}
+
+func (p *CachingProvider) store(ctx context.Context, key string, resp *ChatResponse) {
+	if err := p.cache.Set(ctx, key, resp); err != nil {
+		logger.Warn("llm.cache.set_failed", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
+
+func singleChunk(resp *ChatResponse) <-chan StreamChunk {
+	ch := make(chan StreamChunk, 1)
+	usage := resp.Usage
+	ch <- StreamChunk{Content: resp.Content, ToolCalls: resp.ToolCalls, Done: true, Usage: &usage}
+	close(ch)
+	return ch
+}
+
+// CacheKey returns the content-addressable key of req: the hex SHA-256 of
+// its model, messages, tools, temperature and response format.
+func CacheKey(req ChatRequest) (string, error) {
+	payload, err := json.Marshal(struct {
+		Model          string          `json:"model"`
+		Messages       []Message       `json:"messages"`
+		Tools          []Tool          `json:"tools,omitempty"`
+		Temperature    float64         `json:"temperature"`
+		ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	}{req.Model, req.Messages, req.Tools, req.Temperature, req.ResponseFormat})
+	if err != nil {
+		return "", fmt.Errorf("encode cache key: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// cloneResponse copies resp so cached entries are not shared with callers.
+func cloneResponse(resp *ChatResponse) *ChatResponse {
+	out := *resp
+	out.ToolCalls = slices.Clone(resp.ToolCalls)
+	return &out
+}
+
+// MemoryResponseCache is an in-memory LRU ResponseCache.
+type MemoryResponseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key  string
+	resp *ChatResponse
+}
+
+// NewMemoryResponseCache creates an LRU cache holding up to maxEntries
+// responses (0 = unbounded).
+func NewMemoryResponseCache(maxEntries int) *MemoryResponseCache {
+	return &MemoryResponseCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements ResponseCache.
+func (c *MemoryResponseCache) Get(_ context.Context, key string) (*ChatResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return cloneResponse(elem.Value.(*memoryCacheEntry).resp), true, nil
+}
+
+// Set implements ResponseCache.
+func (c *MemoryResponseCache) Set(_ context.Context, key string, resp *ChatResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*memoryCacheEntry).resp = cloneResponse(resp)
+		c.order.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{key: key, resp: cloneResponse(resp)})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached responses.
+func (c *MemoryResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// FileResponseCache is a ResponseCache that stores each response as a JSON
+// file named after its key, so it can be shared across runs (for example,
+// to replay LLM calls in CI).
+type FileResponseCache struct {
+	dir string
+}
+
+// NewFileResponseCache creates a file cache in dir, creating it if needed.
+func NewFileResponseCache(dir string) (*FileResponseCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &FileResponseCache{dir: dir}, nil
+}
+
+// Get implements ResponseCache.
+func (c *FileResponseCache) Get(_ context.Context, key string) (*ChatResponse, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var resp ChatResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false, fmt.Errorf("decode cached response %s: %w", key, err)
+	}
+	return &resp, true, nil
+}
+
+// Set implements ResponseCache. Files are written atomically.
+func (c *FileResponseCache) Set(_ context.Context, key string, resp *ChatResponse) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("encode cached response: %w", err)
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *FileResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Ensure CachingProvider implements StreamingProvider.
+var _ StreamingProvider = (*CachingProvider)(nil)
+
+// Ensure the caches implement ResponseCache.
+var (
+	_ ResponseCache = (*MemoryResponseCache)(nil)
+	_ ResponseCache = (*FileResponseCache)(nil)
+)
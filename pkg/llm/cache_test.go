@@ -0,0 +1,169 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+func countingProvider(calls *int) *MockProvider {
+	return &MockProvider{ChatFunc: func(_ context.Context, req ChatRequest) (*ChatResponse, error) {
+		*calls++
+		return &ChatResponse{Content: "answer to " + req.Messages[len(req.Messages)-1].Content}, nil
+	}}
+}
+
+func TestCachingProviderServesRepeatedRequests(t *testing.T) {
+	var calls int
+	provider := NewCachingProvider(countingProvider(&calls), NewMemoryResponseCache(10))
+	req := ChatRequest{Model: "m", Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+
+	for i := 0; i < 3; i++ {
+		resp, err := provider.Chat(context.Background(), req)
+		if err != nil {
+			t.Fatalf("chat: %v", err)
+		}
+		if resp.Content != "answer to hi" {
+			t.Fatalf("unexpected content: %q", resp.Content)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 inner call, got %d", calls)
+	}
+
+	req.Messages = []Message{{Role: RoleUser, Content: "bye"}}
+	if _, err := provider.Chat(context.Background(), req); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("different messages must miss the cache, got %d calls", calls)
+	}
+}
+
+func TestCachingProviderTemperature(t *testing.T) {
+	req := ChatRequest{Model: "m", Temperature: 0.7, Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+
+	var calls int
+	provider := NewCachingProvider(countingProvider(&calls), NewMemoryResponseCache(0))
+	provider.Chat(context.Background(), req)
+	provider.Chat(context.Background(), req)
+	if calls != 2 {
+		t.Fatalf("non-deterministic requests must bypass the cache, got %d calls", calls)
+	}
+
+	calls = 0
+	provider = NewCachingProvider(countingProvider(&calls), NewMemoryResponseCache(0), WithCacheNonDeterministic(true))
+	provider.Chat(context.Background(), req)
+	provider.Chat(context.Background(), req)
+	if calls != 1 {
+		t.Fatalf("expected caching with WithCacheNonDeterministic, got %d calls", calls)
+	}
+}
+
+func TestCacheKeyCoversRequestFields(t *testing.T) {
+	base := ChatRequest{Model: "m", Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+	baseKey, _ := CacheKey(base)
+
+	variants := []ChatRequest{
+		{Model: "other", Messages: base.Messages},
+		{Model: "m", Messages: []Message{{Role: RoleSystem, Content: "hi"}}},
+		{Model: "m", Messages: base.Messages, Tools: []Tool{{Type: ToolTypeFunction, Function: FunctionDef{Name: "t"}}}},
+		{Model: "m", Messages: base.Messages, Temperature: 0.1},
+		{Model: "m", Messages: base.Messages, ResponseFormat: &ResponseFormat{Type: ResponseFormatJSONObject}},
+	}
+	for i, req := range variants {
+		if key, _ := CacheKey(req); key == baseKey {
+			t.Fatalf("variant %d must produce a different key", i)
+		}
+	}
+	if again, _ := CacheKey(base); again != baseKey {
+		t.Fatal("cache key must be stable")
+	}
+}
+
+func TestCachingProviderSeparatesResponseFormats(t *testing.T) {
+	var calls int
+	provider := NewCachingProvider(countingProvider(&calls), NewMemoryResponseCache(10))
+	text := ChatRequest{Model: "m", Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+	jsonMode := text
+	jsonMode.ResponseFormat = &ResponseFormat{Type: ResponseFormatJSONObject}
+
+	for _, req := range []ChatRequest{text, jsonMode, text, jsonMode} {
+		if _, err := provider.Chat(context.Background(), req); err != nil {
+			t.Fatalf("chat: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected one inner call per response format, got %d", calls)
+	}
+}
+
+func TestMemoryResponseCacheLRU(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryResponseCache(2)
+	cache.Set(ctx, "a", &ChatResponse{Content: "a"})
+	cache.Set(ctx, "b", &ChatResponse{Content: "b"})
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", &ChatResponse{Content: "c"})
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+	if resp, ok, _ := cache.Get(ctx, "a"); !ok || resp.Content != "a" {
+		t.Fatal("expected recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestFileResponseCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cache, err := NewFileResponseCache(dir)
+	if err != nil {
+		t.Fatalf("new file cache: %v", err)
+	}
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("expected clean miss, got %v %v", ok, err)
+	}
+	want := &ChatResponse{
+		Content:   "cached",
+		ToolCalls: []ToolCall{{ID: "1", Type: ToolTypeFunction, Function: FunctionCall{Name: "t", Arguments: "{}"}}},
+		Usage:     Usage{TotalTokens: 5},
+	}
+	if err := cache.Set(ctx, "k", want); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	reopened, _ := NewFileResponseCache(dir)
+	got, ok, err := reopened.Get(ctx, "k")
+	if err != nil || !ok {
+		t.Fatalf("get: %v %v", ok, err)
+	}
+	if got.Content != want.Content || len(got.ToolCalls) != 1 || got.Usage.TotalTokens != 5 {
+		t.Fatalf("unexpected cached response: %+v", got)
+	}
+}
+
+func TestCachingProviderStream(t *testing.T) {
+	var calls int
+	provider := NewCachingProvider(countingProvider(&calls), NewMemoryResponseCache(0))
+	req := ChatRequest{Model: "m", Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+
+	for i := 0; i < 2; i++ {
+		stream, err := provider.ChatStream(context.Background(), req)
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+		var content string
+		for chunk := range stream {
+			content += chunk.Content
+		}
+		if content != "answer to hi" {
+			t.Fatalf("unexpected content: %q", content)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 inner call, got %d", calls)
+	}
+}
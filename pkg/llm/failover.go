@@ -0,0 +1,275 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+)
+
+// ErrNoProviders is returned by a FailoverProvider created without
+// providers.
+var ErrNoProviders = errors.New("failover provider has no providers")
+
+// FailoverProvider tries a chain of providers in order, moving to the next
+// one when a call fails with a retryable error (rate limit, timeout or a
+// 5xx-class failure). Non-retryable errors, such as invalid input or
+// authentication failures, are returned immediately.
+//
+// It complements resilience.CircuitBreaker: the breaker protects a single
+// backend, failover switches to a different one.
+type FailoverProvider struct {
+	providers  []Provider
+	retryable  func(error) bool
+	onFailover func(from, to int, err error)
+	active     atomic.Int32
+	failovers  atomic.Int64
+}
+
+// FailoverOption configures a FailoverProvider.
+type FailoverOption func(*FailoverProvider)
+
+// WithFailoverClassifier replaces the function that decides whether an
+// error moves the call to the next provider.
+func WithFailoverClassifier(fn func(error) bool) FailoverOption {
+	return func(p *FailoverProvider) {
+		if fn != nil {
+			p.retryable = fn
+		}
+	}
+}
+
+// WithFailoverHook registers fn to be called each time a call moves from
+// provider index from to index to because of err.
+func WithFailoverHook(fn func(from, to int, err error)) FailoverOption {
+	return func(p *FailoverProvider) {
+		p.onFailover = fn
+	}
+}
+
+// NewFailoverProvider creates a provider that fails over across providers
+// in the given order.
+func NewFailoverProvider(providers ...Provider) *FailoverProvider {
+	return NewFailoverProviderWithOptions(providers)
+}
+
+// NewFailoverProviderWithOptions is NewFailoverProvider with options.
+func NewFailoverProviderWithOptions(providers []Provider, opts ...FailoverOption) *FailoverProvider {
+	p := &FailoverProvider{
+		providers: providers,
+		retryable: IsFailoverError,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	p.active.Store(-1)
+	return p
+}
+
+// ActiveIndex returns the index of the provider that served the last
+// successful call, or -1 if none has succeeded yet.
+func (p *FailoverProvider) ActiveIndex() int {
+	return int(p.active.Load())
+}
+
+// Failovers returns how many times a call moved to the next provider.
+func (p *FailoverProvider) Failovers() int64 {
+	return p.failovers.Load()
+}
+
+// SupportsToolCalling implements ToolCallingProvider: any provider may
+// serve a call, so it reports true only when all of them support tool
+// calls.
+func (p *FailoverProvider) SupportsToolCalling() bool {
+	for _, provider := range p.providers {
+		if supported, _ := ToolCallingSupport(provider); !supported {
+			return false
+		}
+	}
+	return len(p.providers) > 0
+}
+
+// reportsToolCalling is true when every provider reports its tool calling
+// support.
+func (p *FailoverProvider) reportsToolCalling() bool {
+	for _, provider := range p.providers {
+		if _, reported := ToolCallingSupport(provider); !reported {
+			return false
+		}
+	}
+	return len(p.providers) > 0
+}
+
+// SupportsResponseFormat implements ResponseFormatProvider, reporting true
+// only when every provider enforces format natively.
+func (p *FailoverProvider) SupportsResponseFormat(format ResponseFormatType) bool {
+	for _, provider := range p.providers {
+		if !responseFormatSupported(provider, format) {
+			return false
+		}
+	}
+	return len(p.providers) > 0
+}
+
+// Chat implements Provider.
+func (p *FailoverProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		resp, err := provider.Chat(ctx, req)
+		if err == nil {
+			p.served(i)
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("provider %d: %w", i, err))
+		if !p.next(ctx, i, err) {
+			return nil, err
+		}
+	}
+	return nil, p.exhausted(errs)
+}
+
+// ChatStream implements StreamingProvider. Failover happens only before
+// any content is delivered: when opening the stream fails, or when its
+// first chunk is a retryable error. Providers that cannot stream are
+// called with Chat and their response is delivered as a single chunk.
+func (p *FailoverProvider) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		stream, first, err := openStream(ctx, provider, req)
+		if err == nil {
+			p.served(i)
+			return prepend(first, stream), nil
+		}
+		errs = append(errs, fmt.Errorf("provider %d: %w", i, err))
+		if !p.next(ctx, i, err) {
+			return nil, err
+		}
+	}
+	return nil, p.exhausted(errs)
+}
+
+// openStream starts a stream on provider and reads its first chunk so an
+// immediate error can trigger failover.
+func openStream(ctx context.Context, provider Provider, req ChatRequest) (<-chan StreamChunk, *StreamChunk, error) {
+	streaming, ok := provider.(StreamingProvider)
+	if !ok {
+		resp, err := provider.Chat(ctx, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		return singleChunk(resp), nil, nil
+	}
+	stream, err := streaming.ChatStream(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	first, ok := <-stream
+	if !ok {
+		return stream, nil, nil
+	}
+	if first.Error != nil {
+		// Drain the rest so the provider goroutine can exit.
+		go func() {
+			for range stream {
+			}
+		}()
+		return nil, nil, first.Error
+	}
+	return stream, &first, nil
+}
+
+func prepend(first *StreamChunk, rest <-chan StreamChunk) <-chan StreamChunk {
+	if first == nil {
+		return rest
+	}
+	out := make(chan StreamChunk, cap(rest)+1)
+	go func() {
+		defer close(out)
+		out <- *first
+		for chunk := range rest {
+			out <- chunk
+		}
+	}()
+	return out
+}
+
+func (p *FailoverProvider) served(index int) {
+	p.active.Store(int32(index))
+}
+
+// next reports whether the call should move on after provider index
+// failed with err, notifying the hook when it does.
+func (p *FailoverProvider) next(ctx context.Context, index int, err error) bool {
+	if ctx.Err() != nil || !p.retryable(err) {
+		return false
+	}
+	if index+1 >= len(p.providers) {
+		return true
+	}
+	p.failovers.Add(1)
+	logger.Warn("llm.failover",
+		slog.Int("from", index),
+		slog.Int("to", index+1),
+		slog.String("error", err.Error()),
+	)
+	if p.onFailover != nil {
+		p.onFailover(index, index+1, err)
+	}
+	return true
+}
+
+func (p *FailoverProvider) exhausted(errs []error) error {
+	if len(errs) == 0 {
+		return ErrNoProviders
+	}
+	return kerrors.New(kerrors.CodeLLMError, "all providers failed", errors.Join(errs...)).
+		WithContext("providers", len(p.providers)).
+		WithRecoverable(true)
+}
+
+// IsFailoverError is the default failover classifier. KairosErrors fail
+// over on rate limits, timeouts and codes that map to a 5xx status, or when
+// marked recoverable. Errors exposing an HTTP status (StatusCode or
+// HTTPStatusCode methods) fail over on 429 and 5xx. Cancellation never
+// fails over; other errors (network failures, unknown provider errors) do.
+func IsFailoverError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var ke *kerrors.KairosError
+	if errors.As(err, &ke) {
+		switch {
+		case ke.Code == kerrors.CodeRateLimit, ke.Code == kerrors.CodeTimeout:
+			return true
+		case ke.StatusCode >= http.StatusInternalServerError:
+			return true
+		default:
+			return ke.Recoverable
+		}
+	}
+	if status, ok := httpStatus(err); ok {
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+func httpStatus(err error) (int, bool) {
+	var withStatus interface{ StatusCode() int }
+	if errors.As(err, &withStatus) {
+		return withStatus.StatusCode(), true
+	}
+	var withHTTPStatus interface{ HTTPStatusCode() int }
+	if errors.As(err, &withHTTPStatus) {
+		return withHTTPStatus.HTTPStatusCode(), true
+	}
+	return 0, false
+}
+
+// Ensure FailoverProvider implements StreamingProvider.
+var _ StreamingProvider = (*FailoverProvider)(nil)
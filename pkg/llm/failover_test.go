@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+)
+
+func TestFailoverProviderFallsBackOnRetryableErrors(t *testing.T) {
+	rateLimited := &MockProvider{Err: kerrors.New(kerrors.CodeRateLimit, "slow down", nil)}
+	down := &MockProvider{Err: kerrors.New(kerrors.CodeLLMError, "bad gateway", nil)}
+	healthy := &MockProvider{Response: "ok"}
+
+	var hops [][2]int
+	provider := NewFailoverProviderWithOptions([]Provider{rateLimited, down, healthy},
+		WithFailoverHook(func(from, to int, _ error) { hops = append(hops, [2]int{from, to}) }))
+	if provider.ActiveIndex() != -1 {
+		t.Fatalf("expected no active provider before the first call")
+	}
+
+	resp, err := provider.Chat(context.Background(), ChatRequest{})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if resp.Content != "ok" || provider.ActiveIndex() != 2 {
+		t.Fatalf("expected provider 2 to serve, got %q from %d", resp.Content, provider.ActiveIndex())
+	}
+	if len(hops) != 2 || provider.Failovers() != 2 {
+		t.Fatalf("unexpected failovers: %v (%d)", hops, provider.Failovers())
+	}
+}
+
+func TestFailoverProviderStopsOnNonRetryableError(t *testing.T) {
+	invalid := kerrors.New(kerrors.CodeInvalidInput, "bad request", nil)
+	var calledSecond bool
+	second := &MockProvider{ChatFunc: func(context.Context, ChatRequest) (*ChatResponse, error) {
+		calledSecond = true
+		return &ChatResponse{}, nil
+	}}
+	provider := NewFailoverProvider(&MockProvider{Err: invalid}, second)
+
+	if _, err := provider.Chat(context.Background(), ChatRequest{}); !errors.Is(err, invalid) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+	if calledSecond {
+		t.Fatal("non-retryable errors must not fail over")
+	}
+}
+
+func TestFailoverProviderAllFail(t *testing.T) {
+	provider := NewFailoverProvider(
+		&MockProvider{Err: errors.New("connection refused")},
+		&MockProvider{Err: kerrors.New(kerrors.CodeTimeout, "timeout", nil)},
+	)
+	_, err := provider.Chat(context.Background(), ChatRequest{})
+	var ke *kerrors.KairosError
+	if !errors.As(err, &ke) || ke.Code != kerrors.CodeLLMError {
+		t.Fatalf("expected LLM error, got %v", err)
+	}
+	if _, err := NewFailoverProvider().Chat(context.Background(), ChatRequest{}); !errors.Is(err, ErrNoProviders) {
+		t.Fatalf("expected ErrNoProviders, got %v", err)
+	}
+}
+
+type failingStream struct{ err error }
+
+func (f failingStream) Chat(context.Context, ChatRequest) (*ChatResponse, error) {
+	return nil, f.err
+}
+
+func (f failingStream) ChatStream(context.Context, ChatRequest) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 1)
+	ch <- StreamChunk{Error: f.err}
+	close(ch)
+	return ch, nil
+}
+
+func TestFailoverProviderStream(t *testing.T) {
+	provider := NewFailoverProvider(
+		failingStream{err: kerrors.New(kerrors.CodeRateLimit, "slow down", nil)},
+		&MockProvider{Response: "streamed"},
+	)
+	stream, err := provider.ChatStream(context.Background(), ChatRequest{})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	var content string
+	for chunk := range stream {
+		if chunk.Error != nil {
+			t.Fatalf("unexpected chunk error: %v", chunk.Error)
+		}
+		content += chunk.Content
+	}
+	if content != "streamed" || provider.ActiveIndex() != 1 {
+		t.Fatalf("expected provider 1 to stream, got %q from %d", content, provider.ActiveIndex())
+	}
+}
+
+type statusError int
+
+func (e statusError) Error() string   { return "http error" }
+func (e statusError) StatusCode() int { return int(e) }
+
+func TestIsFailoverError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{kerrors.New(kerrors.CodeRateLimit, "", nil), true},
+		{kerrors.New(kerrors.CodeTimeout, "", nil), true},
+		{kerrors.New(kerrors.CodeInternal, "", nil), true},
+		{kerrors.New(kerrors.CodeUnauthorized, "", nil), false},
+		{kerrors.New(kerrors.CodeNotFound, "", nil).WithRecoverable(true), true},
+		{statusError(429), true},
+		{statusError(503), true},
+		{statusError(400), false},
+		{errors.New("dial tcp: connection refused"), true},
+	}
+	for i, tc := range cases {
+		if got := IsFailoverError(tc.err); got != tc.want {
+			t.Errorf("case %d (%v): got %v, want %v", i, tc.err, got, tc.want)
+		}
+	}
+}
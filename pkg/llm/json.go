@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoJSON is returned by ExtractJSON when the text holds no valid JSON
+// object or array.
+var ErrNoJSON = errors.New("no JSON object or array found")
+
+// ExtractJSON returns the first JSON object or array in a model response.
+// Markdown code fences (```json or bare ```) are searched first, in order;
+// then the whole text. Within each, the first '{' or '[' that starts a
+// complete, valid value wins, so surrounding prose, several JSON blocks or
+// stray brackets do not break the extraction.
+func ExtractJSON(text string) (json.RawMessage, error) {
+	candidates := append(fencedBlocks(text), text)
+	var firstErr error
+	for _, candidate := range candidates {
+		raw, err := firstJSONValue(candidate)
+		if raw != nil {
+			return raw, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoJSON, firstErr)
+	}
+	return nil, ErrNoJSON
+}
+
+// fencedBlocks returns the contents of the Markdown code fences in text.
+// Fences tagged with a language other than json are skipped.
+func fencedBlocks(text string) []string {
+	var blocks []string
+	for {
+		start := strings.Index(text, "```")
+		if start < 0 {
+			return blocks
+		}
+		rest := text[start+3:]
+		header, body, ok := strings.Cut(rest, "\n")
+		if !ok {
+			return blocks
+		}
+		end := strings.Index(body, "```")
+		if end < 0 {
+			return blocks
+		}
+		if lang := strings.ToLower(strings.TrimSpace(header)); lang == "" || lang == "json" {
+			blocks = append(blocks, body[:end])
+		}
+		text = body[end+3:]
+	}
+}
+
+// firstJSONValue decodes the first complete object or array in text. When
+// none decodes it returns the error of the first failed attempt, or nil if
+// text has no '{' or '['.
+func firstJSONValue(text string) (json.RawMessage, error) {
+	var firstErr error
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' && text[i] != '[' {
+			continue
+		}
+		var raw json.RawMessage
+		decoder := json.NewDecoder(strings.NewReader(text[i:]))
+		if err := decoder.Decode(&raw); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		return bytes.Clone(raw), nil
+	}
+	return nil, firstErr
+}
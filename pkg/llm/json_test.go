@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain object", `{"a": 1}`, `{"a": 1}`},
+		{"prose around", `Here you go: {"a": 1}. Anything else?`, `{"a": 1}`},
+		{"fenced", "Sure:\n```json\n{\"a\": 1}\n```\nDone {x}", `{"a": 1}`},
+		{"bare fence", "```\n[1, 2]\n```", `[1, 2]`},
+		{"other language fence", "```python\nprint(\"hi\")\n```\n{\"a\": 1}", `{"a": 1}`},
+		{"top-level array", `Rows: [{"a": 1}, {"a": 2}]`, `[{"a": 1}, {"a": 2}]`},
+		{"several blocks", `{"a": 1} and then {"b": 2}`, `{"a": 1}`},
+		{"stray brackets", `[note] see {"a": "}"} later`, `{"a": "}"}`},
+		{"broken then valid", `{"a": oops} retry: {"a": 2}`, `{"a": 2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSON(tt.text)
+			if err != nil {
+				t.Fatalf("ExtractJSON error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractJSON_None(t *testing.T) {
+	for _, text := range []string{"", "no json here", `{"a": `} {
+		if _, err := ExtractJSON(text); !errors.Is(err, ErrNoJSON) {
+			t.Fatalf("ExtractJSON(%q) error = %v, want ErrNoJSON", text, err)
+		}
+	}
+}
@@ -43,9 +43,9 @@ func TestOllamaProviderChatStream(t *testing.T) {
 
 		// Stream chunks
 		chunks := []ollamaStreamEvent{
-			{Model: "llama3", Message: Message{Role: RoleAssistant, Content: "Hello"}, Done: false},
-			{Model: "llama3", Message: Message{Role: RoleAssistant, Content: " world"}, Done: false},
-			{Model: "llama3", Message: Message{Role: RoleAssistant, Content: "!"}, Done: false},
+			{Model: "llama3", Message: ollamaMessage{Role: RoleAssistant, Content: "Hello"}, Done: false},
+			{Model: "llama3", Message: ollamaMessage{Role: RoleAssistant, Content: " world"}, Done: false},
+			{Model: "llama3", Message: ollamaMessage{Role: RoleAssistant, Content: "!"}, Done: false},
 			{Model: "llama3", Done: true, PromptEvalCount: 10, EvalCount: 5},
 		}
 
@@ -102,3 +102,180 @@ func TestOllamaProviderChatStream(t *testing.T) {
 		t.Errorf("Expected 15 total tokens, got %d", usage.TotalTokens)
 	}
 }
+
+func TestOllamaProviderChatToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if tools, _ := req["tools"].([]any); len(tools) != 1 {
+			t.Errorf("expected one tool in request, got %v", req["tools"])
+		}
+		msgs := req["messages"].([]any)
+		assistant := msgs[1].(map[string]any)
+		call := assistant["tool_calls"].([]any)[0].(map[string]any)
+		if _, ok := call["function"].(map[string]any)["arguments"].(map[string]any); !ok {
+			t.Errorf("expected tool call arguments as an object, got %v", call)
+		}
+		if tool := msgs[2].(map[string]any); tool["tool_name"] != "get_weather" {
+			t.Errorf("expected tool result tagged with tool name, got %v", tool)
+		}
+
+		w.Write([]byte(`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}}]},"done":true,"prompt_eval_count":7,"eval_count":3}`))
+	}))
+	defer server.Close()
+
+	provider := NewOllama(server.URL)
+	resp, err := provider.Chat(context.Background(), ChatRequest{
+		Model: "llama3.1",
+		Messages: []Message{
+			{Role: RoleUser, Content: "Weather?"},
+			{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "c1", Type: ToolTypeFunction, Function: FunctionCall{Name: "get_weather", Arguments: `{"city":"Madrid"}`}}}},
+			{Role: RoleTool, ToolCallID: "c1", Content: "sunny"},
+		},
+		Tools: []Tool{{Type: ToolTypeFunction, Function: FunctionDef{Name: "get_weather", Parameters: map[string]any{"type": "object"}}}},
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("expected one tool call, got %+v", resp.ToolCalls)
+	}
+	call := resp.ToolCalls[0]
+	if call.ID != "call_0" || call.Function.Name != "get_weather" || call.Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+	if resp.Usage.TotalTokens != 10 {
+		t.Errorf("expected 10 total tokens, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestOllamaProviderFallsBackWithoutToolSupport(t *testing.T) {
+	var withTools, withoutTools int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Tools) > 0 {
+			withTools++
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"registry.ollama.ai/library/gemma:2b does not support tools"}`))
+			return
+		}
+		withoutTools++
+		w.Write([]byte(`{"message":{"role":"assistant","content":"Action: get_weather"},"done":true}`))
+	}))
+	defer server.Close()
+
+	provider := NewOllama(server.URL)
+	req := ChatRequest{
+		Model:    "gemma:2b",
+		Messages: []Message{{Role: RoleUser, Content: "Weather?"}},
+		Tools:    []Tool{{Type: ToolTypeFunction, Function: FunctionDef{Name: "get_weather"}}},
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := provider.Chat(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+		if resp.Content != "Action: get_weather" {
+			t.Errorf("unexpected content: %q", resp.Content)
+		}
+	}
+	if withTools != 1 || withoutTools != 2 {
+		t.Errorf("expected tools to be dropped after the first rejection, got %d with and %d without", withTools, withoutTools)
+	}
+}
+
+func TestRecordingMockProvider(t *testing.T) {
+	mock := NewRecordingMockProvider("first").AddResponse("second")
+	messages := []Message{
+		{Role: RoleSystem, Content: "be brief"},
+		{Role: RoleUser, Content: "hi"},
+	}
+	if _, err := mock.Chat(context.Background(), ChatRequest{Model: "m", Messages: messages}); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	messages[1].Content = "mutated"
+	messages = append(messages, Message{Role: RoleUser, Content: "again"})
+	resp, err := mock.Chat(context.Background(), ChatRequest{Model: "m", Messages: messages})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if resp.Content != "second" {
+		t.Errorf("Expected scripted 'second', got %q", resp.Content)
+	}
+
+	requests := mock.Requests()
+	if len(requests) != 2 || mock.CallCount() != 2 {
+		t.Fatalf("Expected 2 recorded requests, got %d", len(requests))
+	}
+	if requests[0].Messages[1].Content != "hi" {
+		t.Errorf("Recorded request was mutated: %q", requests[0].Messages[1].Content)
+	}
+	if got := mock.LastUserMessage(); got != "again" {
+		t.Errorf("Expected last user message 'again', got %q", got)
+	}
+	if got := mock.LastSystemMessage(); got != "be brief" {
+		t.Errorf("Expected system message 'be brief', got %q", got)
+	}
+	if _, err := mock.Chat(context.Background(), ChatRequest{}); err == nil {
+		t.Error("Expected error once scripted responses are exhausted")
+	}
+
+	mock.Reset()
+	if _, ok := mock.LastRequest(); ok {
+		t.Error("Expected no requests after Reset")
+	}
+}
+
+// capableProvider reports fixed capabilities.
+type capableProvider struct {
+	MockProvider
+	tools  bool
+	format bool
+}
+
+func (p *capableProvider) SupportsToolCalling() bool { return p.tools }
+
+func (p *capableProvider) SupportsResponseFormat(ResponseFormatType) bool { return p.format }
+
+func TestWrappersReportInnerCapabilities(t *testing.T) {
+	capable := &capableProvider{tools: true, format: true}
+	limited := &capableProvider{}
+	silent := &MockProvider{}
+
+	tests := []struct {
+		name     string
+		provider Provider
+		tools    bool
+		reported bool
+		format   bool
+	}{
+		{"cache", NewCachingProvider(capable, NewMemoryResponseCache(1)), true, true, true},
+		{"cache without support", NewCachingProvider(limited, NewMemoryResponseCache(1)), false, true, false},
+		{"cache of a silent provider", NewCachingProvider(silent, NewMemoryResponseCache(1)), false, false, false},
+		{"rate limit", NewRateLimitedProvider(capable, nil), true, true, true},
+		{"rate limit of a silent provider", NewRateLimitedProvider(silent, nil), false, false, false},
+		{"failover", NewFailoverProvider(capable, NewCachingProvider(capable, NewMemoryResponseCache(1))), true, true, true},
+		{"failover with a limited provider", NewFailoverProvider(capable, limited), false, true, false},
+		{"failover with a silent provider", NewFailoverProvider(capable, silent), false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, reported := ToolCallingSupport(tt.provider)
+			if tools != tt.tools || reported != tt.reported {
+				t.Fatalf("ToolCallingSupport = %v, %v; want %v, %v", tools, reported, tt.tools, tt.reported)
+			}
+			rp, ok := tt.provider.(ResponseFormatProvider)
+			if !ok {
+				t.Fatal("expected the wrapper to implement ResponseFormatProvider")
+			}
+			if got := rp.SupportsResponseFormat(ResponseFormatJSONSchema); got != tt.format {
+				t.Fatalf("SupportsResponseFormat = %v, want %v", got, tt.format)
+			}
+		})
+	}
+}
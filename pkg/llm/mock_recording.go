@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// RecordingMockProvider is a scripted mock provider that records every
+// request it receives, so tests can assert on the prompts, tools and model
+// an agent actually sent.
+type RecordingMockProvider struct {
+	mu       sync.Mutex
+	requests []ChatRequest
+	scripted *ScriptedMockProvider
+}
+
+// NewRecordingMockProvider creates a recording provider that returns the
+// given responses in order, like ScriptedMockProvider.
+func NewRecordingMockProvider(responses ...string) *RecordingMockProvider {
+	return &RecordingMockProvider{
+		scripted: NewScriptedMockProvider("", responses...),
+	}
+}
+
+// AddResponse appends a response to the queue.
+func (r *RecordingMockProvider) AddResponse(response string) *RecordingMockProvider {
+	r.scripted.AddResponse(response)
+	return r
+}
+
+// Chat records req and returns the next scripted response.
+func (r *RecordingMockProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	// Callers such as the agent loop keep appending to the same slices.
+	req.Messages = slices.Clone(req.Messages)
+	req.Tools = slices.Clone(req.Tools)
+
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	r.mu.Unlock()
+
+	return r.scripted.Chat(ctx, req)
+}
+
+// Requests returns all recorded requests in call order.
+func (r *RecordingMockProvider) Requests() []ChatRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.requests)
+}
+
+// LastRequest returns the most recent request.
+func (r *RecordingMockProvider) LastRequest() (ChatRequest, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) == 0 {
+		return ChatRequest{}, false
+	}
+	return r.requests[len(r.requests)-1], true
+}
+
+// LastUserMessage returns the content of the last user message in the most
+// recent request, or an empty string.
+func (r *RecordingMockProvider) LastUserMessage() string {
+	return r.lastMessage(RoleUser)
+}
+
+// LastSystemMessage returns the content of the last system message in the
+// most recent request, or an empty string.
+func (r *RecordingMockProvider) LastSystemMessage() string {
+	return r.lastMessage(RoleSystem)
+}
+
+// CallCount returns the number of Chat calls made.
+func (r *RecordingMockProvider) CallCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// Reset clears the recorded requests. Queued responses are kept.
+func (r *RecordingMockProvider) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+func (r *RecordingMockProvider) lastMessage(role Role) string {
+	req, ok := r.LastRequest()
+	if !ok {
+		return ""
+	}
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == role {
+			return req.Messages[i].Content
+		}
+	}
+	return ""
+}
@@ -8,13 +8,23 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
 // OllamaProvider implements the Provider interface for Ollama.
+//
+// Tools in ChatRequest are sent as native Ollama tools and tool calls in
+// the response are returned in ChatResponse.ToolCalls. When a model reports
+// that it does not support tools, the request is retried without them (and
+// later requests for that model skip them), so agents fall back to parsing
+// actions from the text response.
 type OllamaProvider struct {
 	baseURL string
 	client  *http.Client
+	// noTools records models that rejected native tools.
+	noTools sync.Map
 }
 
 // NewOllama creates a new OllamaProvider.
@@ -30,56 +40,47 @@ func NewOllama(baseURL string) *OllamaProvider {
 
 type ollamaRequest struct {
 	Model    string                 `json:"model"`
-	Messages []Message              `json:"messages"`
+	Messages []ollamaMessage        `json:"messages"`
 	Stream   bool                   `json:"stream"`
 	Tools    []Tool                 `json:"tools,omitempty"`
 	Options  map[string]interface{} `json:"options,omitempty"`
 }
 
-type ollamaResponse struct {
-	Message         Message `json:"message"`
-	Done            bool    `json:"done"`
-	TotalDuration   int64   `json:"total_duration"` // nanos
-	EvalCount       int     `json:"eval_count"`
-	PromptEvalCount int     `json:"prompt_eval_count"`
+// ollamaMessage is the Ollama wire format of a Message: tool call arguments
+// are JSON objects and tool results carry the tool name.
+type ollamaMessage struct {
+	Role      Role             `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
 }
 
-// Chat sends a chat request to Ollama and maps the response to ChatResponse.
-func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
-	oReq := ollamaRequest{
-		Model:    req.Model,
-		Messages: req.Messages,
-		Stream:   false,
-		Tools:    req.Tools,
-	}
-
-	if req.Temperature != 0 {
-		oReq.Options = map[string]interface{}{
-			"temperature": req.Temperature,
-		}
-	}
+type ollamaToolCall struct {
+	ID       string             `json:"id,omitempty"`
+	Function ollamaFunctionCall `json:"function"`
+}
 
-	body, err := json.Marshal(oReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal ollama request: %w", err)
-	}
+type ollamaFunctionCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
+type ollamaResponse struct {
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	TotalDuration   int64         `json:"total_duration"` // nanos
+	EvalCount       int           `json:"eval_count"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+}
 
-	resp, err := p.client.Do(httpReq)
+// Chat sends a chat request to Ollama and maps the response to ChatResponse.
+func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	resp, err := p.post(ctx, req, false)
 	if err != nil {
-		return nil, fmt.Errorf("ollama api call failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ollama api returned status: %d", resp.StatusCode)
-	}
-
 	var oResp ollamaResponse
 	if err := json.NewDecoder(resp.Body).Decode(&oResp); err != nil {
 		return nil, fmt.Errorf("failed to decode ollama response: %w", err)
@@ -87,7 +88,7 @@ func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRespon
 
 	return &ChatResponse{
 		Content:   oResp.Message.Content,
-		ToolCalls: oResp.Message.ToolCalls,
+		ToolCalls: fromOllamaToolCalls(oResp.Message.ToolCalls, 0),
 		Usage: Usage{
 			PromptTokens:     oResp.PromptEvalCount,
 			CompletionTokens: oResp.EvalCount,
@@ -96,13 +97,17 @@ func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRespon
 	}, nil
 }
 
-// ChatStream implements StreamingProvider for streaming responses.
-func (p *OllamaProvider) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+// post sends req to /api/chat and returns the successful response. If the
+// model rejects native tools, it is remembered and the request is retried
+// without tools.
+func (p *OllamaProvider) post(ctx context.Context, req ChatRequest, stream bool) (*http.Response, error) {
 	oReq := ollamaRequest{
 		Model:    req.Model,
-		Messages: req.Messages,
-		Stream:   true, // Enable streaming
-		Tools:    req.Tools,
+		Messages: toOllamaMessages(req.Messages),
+		Stream:   stream,
+	}
+	if _, skip := p.noTools.Load(req.Model); !skip {
+		oReq.Tools = req.Tools
 	}
 
 	if req.Temperature != 0 {
@@ -111,6 +116,32 @@ func (p *OllamaProvider) ChatStream(ctx context.Context, req ChatRequest) (<-cha
 		}
 	}
 
+	resp, err := p.send(ctx, oReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(oReq.Tools) > 0 && isToolsUnsupported(resp.StatusCode, respBody) {
+		p.noTools.Store(req.Model, true)
+		oReq.Tools = nil
+		resp, err = p.send(ctx, oReq)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		respBody, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	return nil, fmt.Errorf("ollama api returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+func (p *OllamaProvider) send(ctx context.Context, oReq ollamaRequest) (*http.Response, error) {
 	body, err := json.Marshal(oReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal ollama request: %w", err)
@@ -126,11 +157,76 @@ func (p *OllamaProvider) ChatStream(ctx context.Context, req ChatRequest) (<-cha
 	if err != nil {
 		return nil, fmt.Errorf("ollama api call failed: %w", err)
 	}
+	return resp, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		defer resp.Body.Close()
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("ollama api returned status %d: %s", resp.StatusCode, string(respBody))
+// isToolsUnsupported reports whether Ollama rejected the request because
+// the model has no tool support ("... does not support tools").
+func isToolsUnsupported(status int, body []byte) bool {
+	return status == http.StatusBadRequest && strings.Contains(string(body), "does not support tools")
+}
+
+// toOllamaMessages converts messages to the Ollama wire format. Tool
+// results are tagged with the name of the call they answer.
+func toOllamaMessages(msgs []Message) []ollamaMessage {
+	names := make(map[string]string)
+	out := make([]ollamaMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		om := ollamaMessage{Role: msg.Role, Content: msg.Content}
+		for _, tc := range msg.ToolCalls {
+			names[tc.ID] = tc.Function.Name
+			args := json.RawMessage(tc.Function.Arguments)
+			if !json.Valid(args) {
+				args = json.RawMessage("{}")
+			}
+			om.ToolCalls = append(om.ToolCalls, ollamaToolCall{
+				ID:       tc.ID,
+				Function: ollamaFunctionCall{Name: tc.Function.Name, Arguments: args},
+			})
+		}
+		if msg.Role == RoleTool {
+			om.ToolName = names[msg.ToolCallID]
+		}
+		out = append(out, om)
+	}
+	return out
+}
+
+// fromOllamaToolCalls converts Ollama tool calls, whose arguments are JSON
+// objects, to ToolCalls with JSON string arguments. Ollama may omit call
+// IDs, so missing ones are derived from the call position plus offset.
+func fromOllamaToolCalls(calls []ollamaToolCall, offset int) []ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]ToolCall, 0, len(calls))
+	for i, call := range calls {
+		args := string(call.Function.Arguments)
+		var quoted string
+		if json.Unmarshal(call.Function.Arguments, &quoted) == nil {
+			args = quoted
+		}
+		if strings.TrimSpace(args) == "" || args == "null" {
+			args = "{}"
+		}
+		id := call.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", offset+i)
+		}
+		out = append(out, ToolCall{
+			ID:       id,
+			Type:     ToolTypeFunction,
+			Function: FunctionCall{Name: call.Function.Name, Arguments: args},
+		})
+	}
+	return out
+}
+
+// ChatStream implements StreamingProvider for streaming responses.
+func (p *OllamaProvider) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	resp, err := p.post(ctx, req, true)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create output channel
@@ -176,7 +272,7 @@ func (p *OllamaProvider) ChatStream(ctx context.Context, req ChatRequest) (<-cha
 
 			// Tool calls (Ollama sends complete tool calls, not deltas)
 			if len(event.Message.ToolCalls) > 0 {
-				accumulatedToolCalls = event.Message.ToolCalls
+				accumulatedToolCalls = append(accumulatedToolCalls, fromOllamaToolCalls(event.Message.ToolCalls, len(accumulatedToolCalls))...)
 			}
 
 			// Check if stream is done
@@ -206,15 +302,15 @@ func (p *OllamaProvider) ChatStream(ctx context.Context, req ChatRequest) (<-cha
 
 // ollamaStreamEvent represents a streaming response from Ollama (NDJSON format).
 type ollamaStreamEvent struct {
-	Model           string  `json:"model"`
-	CreatedAt       string  `json:"created_at"`
-	Message         Message `json:"message"`
-	Done            bool    `json:"done"`
-	TotalDuration   int64   `json:"total_duration,omitempty"`
-	LoadDuration    int64   `json:"load_duration,omitempty"`
-	PromptEvalCount int     `json:"prompt_eval_count,omitempty"`
-	EvalCount       int     `json:"eval_count,omitempty"`
-	EvalDuration    int64   `json:"eval_duration,omitempty"`
+	Model           string        `json:"model"`
+	CreatedAt       string        `json:"created_at"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	TotalDuration   int64         `json:"total_duration,omitempty"`
+	LoadDuration    int64         `json:"load_duration,omitempty"`
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+	EvalCount       int           `json:"eval_count,omitempty"`
+	EvalDuration    int64         `json:"eval_duration,omitempty"`
 }
 
 // Ensure OllamaProvider implements StreamingProvider.
@@ -61,12 +61,36 @@ type Message struct {
 	ToolCallID string     `json:"tool_call_id,omitempty"` // Used for tool role messages
 }
 
+// ResponseFormatType selects the shape of a model response.
+type ResponseFormatType string
+
+// ResponseFormatType values.
+const (
+	// ResponseFormatText is free-form text, the default.
+	ResponseFormatText ResponseFormatType = "text"
+	// ResponseFormatJSONObject is any valid JSON object.
+	ResponseFormatJSONObject ResponseFormatType = "json_object"
+	// ResponseFormatJSONSchema is JSON that conforms to a schema.
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema"
+)
+
+// ResponseFormat asks the model for structured output. Providers apply it
+// only when they implement ResponseFormatProvider and support the type.
+type ResponseFormat struct {
+	Type ResponseFormatType `json:"type"`
+	// Name identifies the schema; some providers require one.
+	Name string `json:"name,omitempty"`
+	// Schema is the JSON Schema of a ResponseFormatJSONSchema response.
+	Schema map[string]any `json:"schema,omitempty"`
+}
+
 // ChatRequest encapsulates the input for the LLM.
 type ChatRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Tools       []Tool    `json:"tools,omitempty"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	Tools          []Tool          `json:"tools,omitempty"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 }
 
 // ChatResponse encapsulates the output from the LLM.
@@ -98,6 +122,51 @@ type StreamingProvider interface {
 	ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error)
 }
 
+// ToolCallingProvider is implemented by providers that report whether the
+// model accepts tool definitions and answers with structured tool calls.
+// Agents use it to pick a reasoning strategy automatically.
+type ToolCallingProvider interface {
+	Provider
+	// SupportsToolCalling reports whether native tool calls are supported.
+	SupportsToolCalling() bool
+}
+
+// ToolCallingSupport reports whether p supports native tool calls, and
+// whether p reports it at all. Providers that do not implement
+// ToolCallingProvider report nothing, and so do the wrappers in this package
+// (CachingProvider, RateLimitedProvider, FailoverProvider) around them.
+func ToolCallingSupport(p Provider) (supported, reported bool) {
+	tp, ok := p.(ToolCallingProvider)
+	if !ok {
+		return false, false
+	}
+	if w, ok := p.(toolCallingWrapper); ok && !w.reportsToolCalling() {
+		return false, false
+	}
+	return tp.SupportsToolCalling(), true
+}
+
+// toolCallingWrapper is implemented by wrappers whose ToolCallingProvider
+// answer comes from the providers they wrap.
+type toolCallingWrapper interface {
+	reportsToolCalling() bool
+}
+
+// responseFormatSupported reports whether p enforces format natively.
+func responseFormatSupported(p Provider, format ResponseFormatType) bool {
+	rp, ok := p.(ResponseFormatProvider)
+	return ok && rp.SupportsResponseFormat(format)
+}
+
+// ResponseFormatProvider is implemented by providers that can constrain the
+// response to a ResponseFormat natively. Agents fall back to instructing the
+// model and validating its output otherwise.
+type ResponseFormatProvider interface {
+	Provider
+	// SupportsResponseFormat reports whether format is enforced natively.
+	SupportsResponseFormat(format ResponseFormatType) bool
+}
+
 // StreamChunk represents a chunk of streaming response.
 type StreamChunk struct {
 	// Content is the text delta for this chunk.
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/jllopis/kairos/pkg/resilience"
+)
+
+// RateLimitedProvider wraps a Provider so that every call first waits for a
+// token from a resilience.RateLimiter. Share one limiter between providers
+// that hit the same API quota.
+type RateLimitedProvider struct {
+	inner   Provider
+	limiter *resilience.RateLimiter
+}
+
+// NewRateLimitedProvider wraps inner with limiter. A nil limiter does not
+// limit.
+func NewRateLimitedProvider(inner Provider, limiter *resilience.RateLimiter) *RateLimitedProvider {
+	return &RateLimitedProvider{inner: inner, limiter: limiter}
+}
+
+// Limiter returns the limiter used by the provider, e.g. to read its stats.
+func (p *RateLimitedProvider) Limiter() *resilience.RateLimiter {
+	return p.limiter
+}
+
+// SupportsToolCalling implements ToolCallingProvider for the inner provider.
+func (p *RateLimitedProvider) SupportsToolCalling() bool {
+	supported, _ := ToolCallingSupport(p.inner)
+	return supported
+}
+
+func (p *RateLimitedProvider) reportsToolCalling() bool {
+	_, reported := ToolCallingSupport(p.inner)
+	return reported
+}
+
+// SupportsResponseFormat implements ResponseFormatProvider for the inner
+// provider.
+func (p *RateLimitedProvider) SupportsResponseFormat(format ResponseFormatType) bool {
+	return responseFormatSupported(p.inner, format)
+}
+
+// Chat waits for a token and calls the inner provider. If the context ends
+// first it returns errors.CodeRateLimit without calling it.
+func (p *RateLimitedProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.inner.Chat(ctx, req)
+}
+
+// ChatStream waits for a token and streams from the inner provider, or
+// calls Chat if it cannot stream.
+func (p *RateLimitedProvider) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	if streaming, ok := p.inner.(StreamingProvider); ok {
+		return streaming.ChatStream(ctx, req)
+	}
+	resp, err := p.inner.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return singleChunk(resp), nil
+}
+
+func (p *RateLimitedProvider) wait(ctx context.Context) error {
+	if p.limiter == nil {
+		return nil
+	}
+	return p.limiter.Wait(ctx)
+}
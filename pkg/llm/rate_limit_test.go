@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/resilience"
+)
+
+func TestRateLimitedProvider(t *testing.T) {
+	calls := 0
+	inner := &MockProvider{ChatFunc: func(context.Context, ChatRequest) (*ChatResponse, error) {
+		calls++
+		return &ChatResponse{Content: "ok"}, nil
+	}}
+	provider := NewRateLimitedProvider(inner, resilience.NewRateLimiter(0.5, 1))
+
+	resp, err := provider.Chat(context.Background(), ChatRequest{})
+	if err != nil || resp.Content != "ok" {
+		t.Fatalf("expected first call to pass, got %v, %v", resp, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := provider.Chat(ctx, ChatRequest{}); err == nil {
+		t.Fatalf("expected second call to be rate limited")
+	} else if code, _ := kerrors.CodeOf(err); code != kerrors.CodeRateLimit {
+		t.Fatalf("expected CodeRateLimit, got %v", err)
+	}
+	if _, err := provider.ChatStream(ctx, ChatRequest{}); err == nil {
+		t.Fatalf("expected stream to be rate limited")
+	}
+	if calls != 1 {
+		t.Fatalf("expected inner provider to be called once, got %d", calls)
+	}
+	if stats := provider.Limiter().Stats(); stats.Allowed != 1 || stats.Rejected != 2 {
+		t.Fatalf("unexpected limiter stats %+v", stats)
+	}
+}
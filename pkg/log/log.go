@@ -0,0 +1,96 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package log is the single logging entry point for Kairos libraries.
+//
+// Library code obtains component-scoped loggers with For and never writes to
+// stdout/stderr directly. Nothing is logged until the application installs a
+// logger with SetDefault, which controls level, format and destination for
+// the whole framework:
+//
+//	log.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+//
+//	logger := log.For("mcp")
+//	logger.Info("mcp.reconnect.ok", slog.String("server", name))
+//
+// Loggers returned by For follow later SetDefault calls, so they can be
+// stored in package variables.
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// ComponentKey is the attribute key that identifies the emitting component.
+const ComponentKey = "component"
+
+var current atomic.Pointer[slog.Logger]
+
+func init() {
+	current.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetDefault installs logger as the destination for all Kairos logs.
+// A nil logger restores the default no-op logger.
+func SetDefault(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	current.Store(logger)
+}
+
+// Default returns the logger installed with SetDefault (a no-op logger by
+// default).
+func Default() *slog.Logger {
+	return current.Load()
+}
+
+// For returns a logger for component. Records carry a "component"
+// attribute and are routed to whatever logger is installed at the time they
+// are emitted.
+func For(component string) *slog.Logger {
+	return slog.New(&handler{attrs: []slog.Attr{slog.String(ComponentKey, component)}})
+}
+
+// handler resolves the installed logger on every call so loggers created
+// before SetDefault pick up the new destination.
+type handler struct {
+	attrs []slog.Attr
+	// steps replays WithAttrs/WithGroup calls, in order, on the target.
+	steps []func(slog.Handler) slog.Handler
+}
+
+func (h *handler) target() slog.Handler {
+	next := current.Load().Handler()
+	if len(h.attrs) > 0 {
+		next = next.WithAttrs(h.attrs)
+	}
+	for _, step := range h.steps {
+		next = step(next)
+	}
+	return next
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return current.Load().Handler().Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	return h.target().Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *handler) with(step func(slog.Handler) slog.Handler) slog.Handler {
+	steps := make([]func(slog.Handler) slog.Handler, len(h.steps), len(h.steps)+1)
+	copy(steps, h.steps)
+	return &handler{attrs: h.attrs, steps: append(steps, step)}
+}
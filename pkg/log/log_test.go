@@ -0,0 +1,40 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDefaultIsNoop(t *testing.T) {
+	SetDefault(nil)
+	if For("mcp").Enabled(t.Context(), slog.LevelError) {
+		t.Fatal("default logger must be disabled")
+	}
+}
+
+func TestForFollowsSetDefault(t *testing.T) {
+	// Created before SetDefault, like package-level loggers.
+	logger := For("mcp").With(slog.String("server", "fs"))
+
+	var buf bytes.Buffer
+	SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	defer SetDefault(nil)
+
+	logger.Debug("hidden")
+	logger.WithGroup("call").Info("mcp.call", slog.String("tool", "ls"))
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Fatalf("debug record must respect the installed level: %q", out)
+	}
+	for _, want := range []string{"component=mcp", "server=fs", "call.tool=ls", "msg=mcp.call"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in %q", want, out)
+		}
+	}
+}
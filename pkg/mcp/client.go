@@ -3,13 +3,19 @@ package mcp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/core"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
 	"github.com/jllopis/kairos/pkg/governance"
+	"github.com/jllopis/kairos/pkg/resilience"
+	"github.com/jllopis/kairos/pkg/telemetry"
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -35,6 +41,24 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithToolTimeout sets deadlines for individual tools, keyed by tool name.
+// A tool listed here gets its timeout for the whole call, retries and
+// backoff included, instead of the per-request timeout; other tools keep
+// the WithTimeout value. Non-positive durations are ignored.
+func WithToolTimeout(timeouts map[string]time.Duration) ClientOption {
+	return func(c *Client) {
+		for name, timeout := range timeouts {
+			if timeout <= 0 {
+				continue
+			}
+			if c.toolTimeouts == nil {
+				c.toolTimeouts = make(map[string]time.Duration)
+			}
+			c.toolTimeouts[name] = timeout
+		}
+	}
+}
+
 // WithRetry configures retry count and backoff.
 func WithRetry(retries int, backoff time.Duration) ClientOption {
 	return func(c *Client) {
@@ -56,6 +80,16 @@ func WithToolCacheTTL(ttl time.Duration) ClientOption {
 	}
 }
 
+// WithToolChangeHandler calls handler with the refetched tool list whenever
+// the server sends notifications/tools/list_changed. The cached list is
+// invalidated on every notification, with or without a handler; the cache
+// TTL still applies to servers that never notify.
+func WithToolChangeHandler(handler func([]mcp.Tool)) ClientOption {
+	return func(c *Client) {
+		c.toolChangeHandler = handler
+	}
+}
+
 // WithPolicyEngine enables policy evaluation on MCP calls.
 func WithPolicyEngine(engine governance.PolicyEngine) ClientOption {
 	return func(c *Client) {
@@ -63,6 +97,34 @@ func WithPolicyEngine(engine governance.PolicyEngine) ClientOption {
 	}
 }
 
+// WithToolMetrics overrides where tool call metrics, retries included, are
+// recorded. By default calls are recorded with component "mcp".
+func WithToolMetrics(metrics *telemetry.ToolMetrics) ClientOption {
+	return func(c *Client) {
+		if metrics != nil {
+			c.toolMetrics = metrics
+		}
+	}
+}
+
+// WithRateLimit makes every request to the server, retries included, wait
+// for a token from limiter. The wait is not counted against the request
+// timeout.
+func WithRateLimit(limiter *resilience.RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithBulkhead runs every CallTool, retries included, through bulkhead so
+// that clients sharing it cap their concurrent tool calls. Calls rejected by
+// a full bulkhead fail with errors.CodeRateLimit.
+func WithBulkhead(bulkhead *resilience.Bulkhead) ClientOption {
+	return func(c *Client) {
+		c.bulkhead = bulkhead
+	}
+}
+
 // WithServerName assigns a logical server name for policy decisions.
 func WithServerName(name string) ClientOption {
 	return func(c *Client) {
@@ -78,12 +140,37 @@ type Client struct {
 	backoff    time.Duration
 	cacheTTL   time.Duration
 
+	toolTimeouts map[string]time.Duration
+
 	mu          sync.Mutex
 	toolsCache  []mcp.Tool
 	cacheExpiry time.Time
 
+	toolChangeHandler func([]mcp.Tool)
+
 	policyEngine governance.PolicyEngine
 	serverName   string
+	toolMetrics  *telemetry.ToolMetrics
+	limiter      *resilience.RateLimiter
+	bulkhead     *resilience.Bulkhead
+
+	inFlight    atomic.Int64
+	interrupted atomic.Int64
+	stats       clientStats
+
+	// Progress routing for CallToolStream, keyed by progress token.
+	progressSeq atomic.Int64
+	progressMu  sync.Mutex
+	progress    map[string]*progressStream
+
+	// Reconnection state; mcpClient is swapped under connMu.
+	reconnect    *ReconnectConfig
+	dial         func(ctx context.Context) (client.MCPClient, error)
+	connMu       sync.RWMutex
+	reconnecting bool
+	closed       bool
+	done         chan struct{}
+	closeOnce    sync.Once
 }
 
 // NewClient creates a new Client with the given MCP client implementation.
@@ -94,13 +181,30 @@ func NewClient(c client.MCPClient, opts ...ClientOption) *Client {
 		maxRetries: defaultRetries,
 		backoff:    defaultBackoff,
 		cacheTTL:   defaultCacheTTL,
+		done:       make(chan struct{}),
 	}
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.toolMetrics == nil {
+		client.toolMetrics = defaultToolMetrics()
+	}
+	client.watchNotifications(c)
 	return client
 }
 
+var (
+	toolMetricsOnce sync.Once
+	mcpToolMetrics  *telemetry.ToolMetrics
+)
+
+func defaultToolMetrics() *telemetry.ToolMetrics {
+	toolMetricsOnce.Do(func() {
+		mcpToolMetrics, _ = telemetry.NewToolMetrics(context.Background(), telemetry.WithToolMetricsComponent("mcp"))
+	})
+	return mcpToolMetrics
+}
+
 // NewClientWithStdio creates a new MCP client that connects via Stdio with environment variables.
 func NewClientWithStdio(command string, args []string, env map[string]string, opts ...ClientOption) (*Client, error) {
 	return NewClientWithStdioProtocol(command, args, env, mcp.LATEST_PROTOCOL_VERSION, opts...)
@@ -156,17 +260,32 @@ func NewClientWithStreamableHTTPProtocol(baseURL, protocolVersion string, opts .
 		protocolVersion = mcp.LATEST_PROTOCOL_VERSION
 	}
 
-	httpClient, err := client.NewStreamableHttpClient(normalized)
+	dial := func(ctx context.Context) (client.MCPClient, error) {
+		return dialStreamableHTTP(ctx, normalized, protocolVersion)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), initializeTimeout)
+	defer cancel()
+	httpClient, err := dial(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := httpClient.Start(context.Background()); err != nil {
+	c := NewClient(httpClient, opts...)
+	c.dial = dial
+	return c, nil
+}
+
+// dialStreamableHTTP connects to url and runs the initialize handshake.
+func dialStreamableHTTP(ctx context.Context, url, protocolVersion string) (client.MCPClient, error) {
+	httpClient, err := client.NewStreamableHttpClient(url)
+	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	if err := httpClient.Start(context.Background()); err != nil {
+		return nil, err
+	}
 
 	initRequest := mcp.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = protocolVersion
@@ -176,10 +295,10 @@ func NewClientWithStreamableHTTPProtocol(baseURL, protocolVersion string, opts .
 	}
 
 	if _, err := httpClient.Initialize(ctx, initRequest); err != nil {
+		_ = httpClient.Close()
 		return nil, err
 	}
-
-	return NewClient(httpClient, opts...), nil
+	return httpClient, nil
 }
 
 // ListTools retrieves the list of tools available on the server.
@@ -188,10 +307,18 @@ func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
 		return nil, err
 	}
 	if cached := c.cachedTools(); cached != nil {
+		c.stats.recordCache(true)
 		return cached, nil
 	}
+	c.stats.recordCache(false)
+	if err := c.checkReconnecting(); err != nil {
+		return nil, err
+	}
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
 	req := mcp.ListToolsRequest{}
 	resp, err := c.listToolsWithRetry(ctx, req)
+	c.noteInterrupted(ctx, err)
 	if err != nil {
 		return nil, err
 	}
@@ -201,22 +328,111 @@ func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
 
 // CallTool executes a tool on the server.
 func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	if err := c.evaluatePolicy(ctx, governance.ActionMCP, c.serverName); err != nil {
-		return nil, err
-	}
-	if err := c.evaluatePolicy(ctx, governance.ActionTool, name); err != nil {
+	if err := c.checkToolCall(ctx, name); err != nil {
 		return nil, err
 	}
 	req := mcp.CallToolRequest{}
 	req.Params.Name = name
 	req.Params.Arguments = args
+	return c.callTool(ctx, req)
+}
+
+// checkToolCall applies policy to a call of the named tool and fails fast
+// while reconnecting.
+func (c *Client) checkToolCall(ctx context.Context, name string) error {
+	if err := c.evaluatePolicy(ctx, governance.ActionMCP, c.serverName); err != nil {
+		return err
+	}
+	if err := c.evaluatePolicy(ctx, governance.ActionTool, name); err != nil {
+		return err
+	}
+	return c.checkReconnecting()
+}
+
+// callTool sends req through the bulkhead and retries, recording metrics.
+func (c *Client) callTool(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	name := req.Params.Name
+
+	start := time.Now()
+	var resp *mcp.CallToolResult
+	err := resilience.WithBulkhead(ctx, c.bulkhead, func() error {
+		var callErr error
+		resp, callErr = c.callToolWithRetry(ctx, req)
+		return callErr
+	})
+	c.noteInterrupted(ctx, err)
+	callErr := err
+	if callErr == nil && resp != nil && resp.IsError {
+		callErr = errors.New("tool returned an error result")
+	}
+	latency := time.Since(start)
+	c.stats.recordCall(name, latency, callErr != nil)
+	c.toolMetrics.RecordToolCall(ctx, name, latency, callErr)
+	return resp, err
+}
+
+// Health performs a cheap liveness check (a ping round-trip) against the
+// server and reports it as a core.HealthResult. It does not consult policy
+// or the tool cache, so it reflects the connection itself.
+func (c *Client) Health(ctx context.Context) core.HealthResult {
+	name := c.serverName
+	if name == "" {
+		name = "default"
+	}
+	result := core.HealthResult{
+		Component: "mcp:" + name,
+		LastCheck: time.Now(),
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if c.Reconnecting() {
+		result.Status = core.HealthDegraded
+		result.Message = "MCP connection lost, reconnecting"
+		return result
+	}
+
+	start := time.Now()
+	if err := c.conn().Ping(ctx); err != nil {
+		c.handleConnError(err)
+		result.Status = core.HealthUnhealthy
+		result.Message = "MCP ping failed: " + err.Error()
+		result.Error = err
+		return result
+	}
+	result.Status = core.HealthHealthy
+	result.Message = fmt.Sprintf("MCP server responsive (%s)", time.Since(start).Round(time.Millisecond))
+	return result
+}
 
-	return c.callToolWithRetry(ctx, req)
+// InFlight returns the number of requests currently waiting on the server.
+func (c *Client) InFlight() int {
+	return int(c.inFlight.Load())
 }
 
-// Close closes the client connection.
+// Interrupted returns how many requests were aborted because their context
+// ended while waiting on the server.
+func (c *Client) Interrupted() int64 {
+	return c.interrupted.Load()
+}
+
+func (c *Client) noteInterrupted(ctx context.Context, err error) {
+	if err != nil && ctx.Err() != nil {
+		c.interrupted.Add(1)
+	}
+}
+
+// Close closes the client connection and stops any reconnection.
 func (c *Client) Close() error {
-	return c.mcpClient.Close()
+	c.closeOnce.Do(func() { close(c.done) })
+	c.connMu.Lock()
+	c.closed = true
+	conn := c.mcpClient
+	c.connMu.Unlock()
+	return conn.Close()
 }
 
 func (c *Client) evaluatePolicy(ctx context.Context, actionType governance.ActionType, name string) error {
@@ -227,21 +443,14 @@ func (c *Client) evaluatePolicy(ctx context.Context, actionType governance.Actio
 		return nil
 	}
 	decision := c.policyEngine.Evaluate(ctx, governance.Action{
-		Type: actionType,
-		Name: name,
+		Type:      actionType,
+		Name:      name,
+		Principal: governance.PrincipalFromContext(ctx),
 	})
 	if decision.IsAllowed() {
 		return nil
 	}
-	reason := strings.TrimSpace(decision.Reason)
-	if reason == "" {
-		if decision.IsPending() {
-			reason = "approval required"
-		} else {
-			reason = "blocked by policy"
-		}
-	}
-	return errors.New(reason)
+	return errors.New(policyReason(decision))
 }
 
 func (c *Client) cachedTools() []mcp.Tool {
@@ -270,58 +479,97 @@ func (c *Client) storeTools(tools []mcp.Tool) {
 }
 
 func (c *Client) listToolsWithRetry(ctx context.Context, req mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
-	var lastErr error
-	attempts := c.maxRetries + 1
-	for i := 0; i < attempts; i++ {
-		reqCtx, cancel := c.withTimeout(ctx)
-		res, err := c.mcpClient.ListTools(reqCtx, req)
-		cancel()
-		if err == nil {
-			return res, nil
-		}
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			return nil, err
-		}
-		lastErr = err
-		if i == attempts-1 {
-			break
+	return withRetry(ctx, c, c.timeout, func(ctx context.Context, conn client.MCPClient) (*mcp.ListToolsResult, error) {
+		return conn.ListTools(ctx, req)
+	})
+}
+
+func (c *Client) callToolWithRetry(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	attempts := 0
+	call := func(ctx context.Context, conn client.MCPClient) (*mcp.CallToolResult, error) {
+		if attempts > 0 {
+			c.stats.recordToolRetry(req.Params.Name)
+			c.toolMetrics.RecordToolRetry(ctx, req.Params.Name)
 		}
-		if err := c.sleepBackoff(ctx, i); err != nil {
-			return nil, err
+		attempts++
+		return conn.CallTool(ctx, req)
+	}
+	timeout, ok := c.toolTimeouts[req.Params.Name]
+	if !ok {
+		return withRetry(ctx, c, c.timeout, call)
+	}
+	// The tool timeout is the budget of the whole call, so attempts share
+	// its deadline instead of getting one each.
+	toolCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := withRetry(toolCtx, c, 0, call)
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		if _, typed := kerrors.CodeOf(err); !typed {
+			err = c.timeoutError(err, timeout).WithContext("tool", req.Params.Name)
 		}
 	}
-	return nil, lastErr
+	return resp, err
 }
 
-func (c *Client) callToolWithRetry(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// withRetry runs call against the current connection with the client rate
+// limit, per-attempt timeout and retries. A timeout of 0 leaves attempts
+// bounded by ctx alone. Connection losses start a reconnection instead of
+// being retried, and attempts that time out fail with errors.CodeTimeout.
+func withRetry[T any](ctx context.Context, c *Client, timeout time.Duration, call func(ctx context.Context, conn client.MCPClient) (T, error)) (T, error) {
+	var zero T
 	var lastErr error
 	attempts := c.maxRetries + 1
 	for i := 0; i < attempts; i++ {
-		reqCtx, cancel := c.withTimeout(ctx)
-		res, err := c.mcpClient.CallTool(reqCtx, req)
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return zero, err
+			}
+		}
+		reqCtx, cancel := contextWithTimeout(ctx, timeout)
+		res, err := call(reqCtx, c.conn())
 		cancel()
 		if err == nil {
 			return res, nil
 		}
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return zero, c.timeoutError(err, timeout)
+		}
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			return nil, err
+			return zero, err
+		}
+		if c.handleConnError(err) {
+			return zero, c.reconnectError(err)
 		}
 		lastErr = err
 		if i == attempts-1 {
 			break
 		}
 		if err := c.sleepBackoff(ctx, i); err != nil {
-			return nil, err
+			return zero, err
 		}
+		c.stats.recordRetry()
 	}
-	return nil, lastErr
+	return zero, lastErr
 }
 
 func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
-	if c.timeout <= 0 {
+	return contextWithTimeout(ctx, c.timeout)
+}
+
+func contextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
 		return context.WithCancel(ctx)
 	}
-	return context.WithTimeout(ctx, c.timeout)
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutError types a request that exceeded timeout. It is recoverable so
+// that callers' retry logic can try again.
+func (c *Client) timeoutError(cause error, timeout time.Duration) *kerrors.KairosError {
+	return kerrors.New(kerrors.CodeTimeout, "mcp request timed out", cause).
+		WithContext("server", c.serverName).
+		WithContext("timeout", timeout.String()).
+		WithRecoverable(true)
 }
 
 func (c *Client) sleepBackoff(ctx context.Context, attempt int) error {
@@ -2,8 +2,15 @@ package mcp
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/jllopis/kairos/pkg/core"
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/resilience"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 )
@@ -41,3 +48,253 @@ func TestClient_StreamableHTTP_ListTools(t *testing.T) {
 		t.Fatalf("Expected successful tool result, got %+v", result)
 	}
 }
+
+func TestClient_RateLimit(t *testing.T) {
+	server := mcpserver.NewMCPServer("test-http", "1.0.0")
+	server.AddTool(mcpgo.NewTool("ping"), func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		return &mcpgo.CallToolResult{
+			Content: []mcpgo.Content{mcpgo.TextContent{Type: "text", Text: "ok"}},
+		}, nil
+	})
+
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+	defer httpServer.Close()
+
+	limiter := resilience.NewRateLimiter(0.5, 1)
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION,
+		WithRateLimit(limiter),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CallTool(context.Background(), "ping", nil); err != nil {
+		t.Fatalf("first CallTool error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.CallTool(ctx, "ping", nil)
+	if code, ok := kerrors.CodeOf(err); !ok || code != kerrors.CodeRateLimit {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if stats := limiter.Stats(); stats.Allowed != 1 || stats.Rejected != 1 {
+		t.Fatalf("unexpected limiter stats %+v", stats)
+	}
+}
+
+func TestClient_Health(t *testing.T) {
+	server := mcpserver.NewMCPServer("test-http", "1.0.0")
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION,
+		WithServerName("test"), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	result := client.Health(context.Background())
+	if result.Status != core.HealthHealthy || result.Component != "mcp:test" {
+		t.Fatalf("expected healthy mcp:test, got %+v", result)
+	}
+
+	httpServer.Close()
+	result = client.Health(context.Background())
+	if result.Status != core.HealthUnhealthy || result.Error == nil {
+		t.Fatalf("expected unhealthy after server shutdown, got %+v", result)
+	}
+}
+
+func TestClient_ReconnectAfterServerRestart(t *testing.T) {
+	start := func(addr string) *httptest.Server {
+		server := mcpserver.NewMCPServer("test-http", "1.0.0")
+		server.AddTool(mcpgo.NewTool("ping"), func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+			return &mcpgo.CallToolResult{
+				Content: []mcpgo.Content{mcpgo.TextContent{Type: "text", Text: "ok"}},
+			}, nil
+		})
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		httpServer := httptest.NewUnstartedServer(mcpserver.NewStreamableHTTPServer(server))
+		httpServer.Listener = listener
+		httpServer.Start()
+		return httpServer
+	}
+	httpServer := start("127.0.0.1:0")
+	addr := httpServer.Listener.Addr().String()
+
+	reconnected := make(chan error, 1)
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL+"/mcp", mcpgo.LATEST_PROTOCOL_VERSION,
+		WithRetry(0, 0),
+		WithReconnect(ReconnectConfig{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     50 * time.Millisecond,
+			MaxAttempts:    -1,
+			OnReconnect:    func(_ int, err error) { reconnected <- err },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CallTool(context.Background(), "ping", nil); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+
+	// Restart the server on the same address.
+	httpServer.Close()
+	_, err = client.CallTool(context.Background(), "ping", nil)
+	var ke *kerrors.KairosError
+	if !errors.As(err, &ke) || !ke.Recoverable || !errors.Is(err, ErrReconnecting) {
+		t.Fatalf("expected recoverable reconnect error, got %v", err)
+	}
+	if !client.Reconnecting() {
+		t.Fatal("expected client to be reconnecting")
+	}
+	httpServer = start(addr)
+	defer httpServer.Close()
+
+	select {
+	case err := <-reconnected:
+		if err != nil {
+			t.Fatalf("reconnect failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not reconnect")
+	}
+	if _, err := client.CallTool(context.Background(), "ping", nil); err != nil {
+		t.Fatalf("CallTool after reconnect error: %v", err)
+	}
+}
+
+func TestClient_ToolTimeout(t *testing.T) {
+	server := mcpserver.NewMCPServer("test-http", "1.0.0")
+	slow := func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(150 * time.Millisecond):
+		}
+		return &mcpgo.CallToolResult{
+			Content: []mcpgo.Content{mcpgo.TextContent{Type: "text", Text: "ok"}},
+		}, nil
+	}
+	server.AddTool(mcpgo.NewTool("summarize"), slow)
+	server.AddTool(mcpgo.NewTool("list_sheets"), slow)
+
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+	defer httpServer.Close()
+
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION,
+		WithTimeout(time.Second),
+		WithToolTimeout(map[string]time.Duration{"list_sheets": 50 * time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CallTool(context.Background(), "summarize", nil); err != nil {
+		t.Fatalf("summarize should fit the global timeout: %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.CallTool(context.Background(), "list_sheets", nil)
+	if code, ok := kerrors.CodeOf(err); !ok || code != kerrors.CodeTimeout {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if !kerrors.IsRecoverable(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a recoverable deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 140*time.Millisecond {
+		t.Fatalf("tool timeout not applied, call took %s", elapsed)
+	}
+}
+
+func TestClient_ToolTimeoutExtendsGlobal(t *testing.T) {
+	server := mcpserver.NewMCPServer("test-http", "1.0.0")
+	server.AddTool(mcpgo.NewTool("summarize"), func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		time.Sleep(100 * time.Millisecond)
+		return &mcpgo.CallToolResult{
+			Content: []mcpgo.Content{mcpgo.TextContent{Type: "text", Text: "ok"}},
+		}, nil
+	})
+
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+	defer httpServer.Close()
+
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION,
+		WithTimeout(30*time.Millisecond),
+		WithToolTimeout(map[string]time.Duration{"summarize": time.Second}),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CallTool(context.Background(), "summarize", nil); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+}
+
+func TestClient_Stats(t *testing.T) {
+	server := mcpserver.NewMCPServer("test-http", "1.0.0")
+	var flakyCalls int
+	server.AddTool(mcpgo.NewTool("read_sheet"), func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		flakyCalls++
+		if flakyCalls == 1 {
+			return nil, errors.New("sheet locked")
+		}
+		return &mcpgo.CallToolResult{
+			Content: []mcpgo.Content{mcpgo.TextContent{Type: "text", Text: "ok"}},
+		}, nil
+	})
+	server.AddTool(mcpgo.NewTool("broken"), func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		return mcpgo.NewToolResultError("no such sheet"), nil
+	})
+
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+	defer httpServer.Close()
+
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION,
+		WithRetry(1, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListTools(context.Background()); err != nil {
+			t.Fatalf("ListTools error: %v", err)
+		}
+	}
+	if _, err := client.CallTool(context.Background(), "read_sheet", nil); err != nil {
+		t.Fatalf("read_sheet error: %v", err)
+	}
+	if _, err := client.CallTool(context.Background(), "broken", nil); err != nil {
+		t.Fatalf("broken error: %v", err)
+	}
+
+	stats := client.Stats()
+	if stats.ToolCalls != 2 || stats.ToolErrors != 1 || stats.Retries != 1 {
+		t.Fatalf("unexpected call stats %+v", stats)
+	}
+	if stats.CacheHits != 1 || stats.CacheMisses != 1 {
+		t.Fatalf("unexpected cache stats %+v", stats)
+	}
+	if stats.LastCallLatency <= 0 {
+		t.Fatalf("expected a last call latency, got %s", stats.LastCallLatency)
+	}
+	if got := stats.Tools["read_sheet"]; got.Calls != 1 || got.Errors != 0 || got.Retries != 1 {
+		t.Fatalf("unexpected read_sheet stats %+v", got)
+	}
+	if got := stats.Tools["broken"]; got.Calls != 1 || got.Errors != 1 || got.Retries != 0 {
+		t.Fatalf("unexpected broken stats %+v", got)
+	}
+}
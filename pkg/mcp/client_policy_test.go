@@ -0,0 +1,25 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jllopis/kairos/pkg/governance"
+)
+
+func TestClientPolicyMatchesPrincipal(t *testing.T) {
+	engine := governance.NewRuleSet([]governance.Rule{
+		{ID: "deny-team-a", Effect: "deny", Type: governance.ActionTool, Name: "write_file", Principal: "team-a", Reason: "read-only team"},
+	})
+	c := &Client{policyEngine: engine}
+
+	ctxA := governance.WithPrincipal(context.Background(), "team-a")
+	if _, err := c.CallTool(ctxA, "write_file", nil); err == nil || err.Error() != "read-only team" {
+		t.Fatalf("expected team-a call to be denied, got %v", err)
+	}
+
+	ctxB := governance.WithPrincipal(context.Background(), "team-b")
+	if err := c.evaluatePolicy(ctxB, governance.ActionTool, "write_file"); err != nil {
+		t.Fatalf("expected team-b call to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/jllopis/kairos/pkg/governance"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListResources retrieves the resources the server exposes.
+func (c *Client) ListResources(ctx context.Context) ([]mcp.Resource, error) {
+	if err := c.beginRequest(ctx); err != nil {
+		return nil, err
+	}
+	defer c.inFlight.Add(-1)
+	resp, err := withRetry(ctx, c, c.timeout, func(ctx context.Context, conn client.MCPClient) (*mcp.ListResourcesResult, error) {
+		return conn.ListResources(ctx, mcp.ListResourcesRequest{})
+	})
+	c.noteInterrupted(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Resources, nil
+}
+
+// ReadResource reads the contents of the resource at uri. Each content is
+// an mcp.TextResourceContents or an mcp.BlobResourceContents.
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	if err := c.beginRequest(ctx); err != nil {
+		return nil, err
+	}
+	defer c.inFlight.Add(-1)
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	resp, err := withRetry(ctx, c, c.timeout, func(ctx context.Context, conn client.MCPClient) (*mcp.ReadResourceResult, error) {
+		return conn.ReadResource(ctx, req)
+	})
+	c.noteInterrupted(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Contents, nil
+}
+
+// ListPrompts retrieves the prompt templates the server exposes.
+func (c *Client) ListPrompts(ctx context.Context) ([]mcp.Prompt, error) {
+	if err := c.beginRequest(ctx); err != nil {
+		return nil, err
+	}
+	defer c.inFlight.Add(-1)
+	resp, err := withRetry(ctx, c, c.timeout, func(ctx context.Context, conn client.MCPClient) (*mcp.ListPromptsResult, error) {
+		return conn.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	})
+	c.noteInterrupted(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Prompts, nil
+}
+
+// GetPrompt renders the prompt name with args.
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	if err := c.beginRequest(ctx); err != nil {
+		return nil, err
+	}
+	defer c.inFlight.Add(-1)
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	resp, err := withRetry(ctx, c, c.timeout, func(ctx context.Context, conn client.MCPClient) (*mcp.GetPromptResult, error) {
+		return conn.GetPrompt(ctx, req)
+	})
+	c.noteInterrupted(ctx, err)
+	return resp, err
+}
+
+// beginRequest checks policy and the connection state for a resource or
+// prompt request and counts it as in flight. The caller must decrement
+// inFlight when it returns nil.
+func (c *Client) beginRequest(ctx context.Context) error {
+	if err := c.evaluatePolicy(ctx, governance.ActionMCP, c.serverName); err != nil {
+		return err
+	}
+	if err := c.checkReconnecting(); err != nil {
+		return err
+	}
+	c.inFlight.Add(1)
+	return nil
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"sync"
+	"time"
+)
+
+// ClientStats summarizes the activity of a Client since it was created.
+type ClientStats struct {
+	// ToolCalls and ToolErrors count CallTool requests sent to the server;
+	// calls denied by policy are not included. An error result from the
+	// tool counts as an error.
+	ToolCalls  int64
+	ToolErrors int64
+	// Retries counts requests sent again after a failed attempt, tool
+	// calls and tool listings alike.
+	Retries int64
+	// CacheHits and CacheMisses count ListTools calls answered from the
+	// tool cache and from the server.
+	CacheHits   int64
+	CacheMisses int64
+	// LastCallLatency is the duration of the most recent tool call.
+	LastCallLatency time.Duration
+	// Tools breaks tool calls down by tool name.
+	Tools map[string]ToolStats
+}
+
+// ToolStats summarizes the calls to a single tool.
+type ToolStats struct {
+	Calls           int64
+	Errors          int64
+	Retries         int64
+	LastCallLatency time.Duration
+}
+
+// clientStats holds the counters behind Client.Stats.
+type clientStats struct {
+	mu          sync.Mutex
+	retries     int64
+	cacheHits   int64
+	cacheMisses int64
+	lastLatency time.Duration
+	tools       map[string]ToolStats
+}
+
+// Stats returns a snapshot of the client counters.
+func (c *Client) Stats() ClientStats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	out := ClientStats{
+		Retries:         c.stats.retries,
+		CacheHits:       c.stats.cacheHits,
+		CacheMisses:     c.stats.cacheMisses,
+		LastCallLatency: c.stats.lastLatency,
+		Tools:           make(map[string]ToolStats, len(c.stats.tools)),
+	}
+	for name, tool := range c.stats.tools {
+		out.ToolCalls += tool.Calls
+		out.ToolErrors += tool.Errors
+		out.Tools[name] = tool
+	}
+	return out
+}
+
+func (s *clientStats) recordCall(name string, latency time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tools == nil {
+		s.tools = make(map[string]ToolStats)
+	}
+	tool := s.tools[name]
+	tool.Calls++
+	if failed {
+		tool.Errors++
+	}
+	tool.LastCallLatency = latency
+	s.tools[name] = tool
+	s.lastLatency = latency
+}
+
+func (s *clientStats) recordRetry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+}
+
+func (s *clientStats) recordToolRetry(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tools == nil {
+		s.tools = make(map[string]ToolStats)
+	}
+	tool := s.tools[name]
+	tool.Retries++
+	s.tools[name] = tool
+}
+
+func (s *clientStats) recordCache(hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
@@ -29,17 +29,24 @@
 //	// Release when done
 //	pool.Release("filesystem", client1)
 //	pool.Release("filesystem", client2)
+//
+// Connections are leased to the context passed to Get: when that context is
+// cancelled before Release is called, the lease is released automatically.
+// If a request was still in flight on the connection, the connection is
+// discarded instead of being returned for reuse.
 package pool
 
 import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/jllopis/kairos/pkg/mcp"
+	"github.com/jllopis/kairos/pkg/resilience"
 )
 
 var (
@@ -89,6 +96,11 @@ type ServerConfig struct {
 
 	// ClientOptions are applied to each client created for this server.
 	ClientOptions []mcp.ClientOption
+
+	// Bulkhead, when set, caps concurrent tool calls across all connections
+	// to this server. Servers registered without one get the pool default
+	// (see WithBulkhead).
+	Bulkhead *resilience.Bulkhead
 }
 
 // pooledClient wraps an MCP client with reference counting.
@@ -97,6 +109,22 @@ type pooledClient struct {
 	refCount int32
 	server   string
 	created  time.Time
+
+	// discarded is set (under Pool.mu) when the connection was removed from
+	// the pool while still referenced; it is closed on its last release.
+	discarded bool
+}
+
+// lease records a single Get until it is released, either by Release or by
+// cancellation of the context it was acquired with.
+type lease struct {
+	pc       *pooledClient
+	acquired time.Time
+	stop     func() bool
+
+	// interrupted snapshots pc.client.Interrupted() at acquisition, to tell
+	// whether a request was aborted by the cancellation.
+	interrupted int64
 }
 
 // Pool manages shared MCP connections across multiple agents.
@@ -104,12 +132,15 @@ type Pool struct {
 	mu      sync.RWMutex
 	servers map[string]*ServerConfig
 	clients map[string][]*pooledClient
+	leases  map[*pooledClient][]*lease
 	closed  atomic.Bool
 
 	// Configuration
 	maxPerServer        int
 	healthCheckInterval time.Duration
 	idleTimeout         time.Duration
+	bulkheadConcurrent  int
+	bulkheadQueue       int
 
 	// Lifecycle
 	ctx    context.Context
@@ -122,6 +153,8 @@ type Pool struct {
 	connectionErrors   atomic.Int64
 	healthChecksPassed atomic.Int64
 	healthChecksFailed atomic.Int64
+	autoReleased       atomic.Int64
+	discarded          atomic.Int64
 }
 
 // PoolOption configures the connection pool.
@@ -154,12 +187,25 @@ func WithIdleTimeout(timeout time.Duration) PoolOption {
 	}
 }
 
+// WithBulkhead gives each registered server its own bulkhead allowing
+// maxConcurrent tool calls in flight and maxQueue waiting, shared by all
+// connections to that server. A ServerConfig.Bulkhead takes precedence.
+func WithBulkhead(maxConcurrent, maxQueue int) PoolOption {
+	return func(p *Pool) {
+		if maxConcurrent > 0 {
+			p.bulkheadConcurrent = maxConcurrent
+			p.bulkheadQueue = maxQueue
+		}
+	}
+}
+
 // New creates a new MCP connection pool.
 func New(opts ...PoolOption) *Pool {
 	ctx, cancel := context.WithCancel(context.Background())
 	p := &Pool{
 		servers:             make(map[string]*ServerConfig),
 		clients:             make(map[string][]*pooledClient),
+		leases:              make(map[*pooledClient][]*lease),
 		maxPerServer:        10,
 		healthCheckInterval: 30 * time.Second,
 		idleTimeout:         5 * time.Minute,
@@ -197,6 +243,7 @@ func (p *Pool) RegisterStdio(name, command string, args []string, opts ...mcp.Cl
 		Command:       command,
 		Args:          args,
 		ClientOptions: opts,
+		Bulkhead:      p.defaultBulkhead(),
 	}
 
 	return nil
@@ -220,6 +267,7 @@ func (p *Pool) RegisterHTTP(name, url string, opts ...mcp.ClientOption) error {
 		Type:          ServerTypeHTTP,
 		URL:           url,
 		ClientOptions: opts,
+		Bulkhead:      p.defaultBulkhead(),
 	}
 
 	return nil
@@ -244,10 +292,22 @@ func (p *Pool) Register(config ServerConfig) error {
 		return ErrPoolClosed
 	}
 
+	if config.Bulkhead == nil {
+		config.Bulkhead = p.defaultBulkhead()
+	}
 	p.servers[config.Name] = &config
 	return nil
 }
 
+// defaultBulkhead returns a new bulkhead configured by WithBulkhead, or nil
+// when the option was not used.
+func (p *Pool) defaultBulkhead() *resilience.Bulkhead {
+	if p.bulkheadConcurrent <= 0 {
+		return nil
+	}
+	return resilience.NewBulkhead(p.bulkheadConcurrent, p.bulkheadQueue)
+}
+
 // Unregister removes a server from the pool and closes all its connections.
 func (p *Pool) Unregister(name string) error {
 	p.mu.Lock()
@@ -262,6 +322,7 @@ func (p *Pool) Unregister(name string) error {
 	// Close all connections for this server
 	if clients, ok := p.clients[name]; ok {
 		for _, pc := range clients {
+			p.dropLeasesLocked(pc)
 			_ = pc.client.Close()
 			p.activeConnections.Add(-1)
 		}
@@ -273,6 +334,10 @@ func (p *Pool) Unregister(name string) error {
 
 // Get retrieves a client connection for the specified server.
 // If no idle connection is available, a new one is created.
+//
+// The connection is leased to ctx: if ctx is cancelled before Release is
+// called, the lease is released automatically, and the connection is
+// discarded if a request was in flight at that moment.
 func (p *Pool) Get(ctx context.Context, serverName string) (*mcp.Client, error) {
 	if p.closed.Load() {
 		return nil, ErrPoolClosed
@@ -290,6 +355,7 @@ func (p *Pool) Get(ctx context.Context, serverName string) (*mcp.Client, error)
 	for _, pc := range clients {
 		// Simple approach: always increment ref count for existing client
 		atomic.AddInt32(&pc.refCount, 1)
+		p.leaseLocked(ctx, pc)
 		p.mu.Unlock()
 		return pc.client, nil
 	}
@@ -321,6 +387,7 @@ func (p *Pool) Get(ctx context.Context, serverName string) (*mcp.Client, error)
 
 	p.mu.Lock()
 	p.clients[serverName] = append(p.clients[serverName], pc)
+	p.leaseLocked(ctx, pc)
 	p.mu.Unlock()
 
 	p.totalConnections.Add(1)
@@ -331,17 +398,104 @@ func (p *Pool) Get(ctx context.Context, serverName string) (*mcp.Client, error)
 
 // Release decrements the reference count for a connection.
 // The connection is not immediately closed but may be reused.
+// Releasing a connection whose lease was already released automatically
+// (because its context was cancelled) is a no-op.
 func (p *Pool) Release(serverName string, client *mcp.Client) {
-	p.mu.RLock()
-	clients := p.clients[serverName]
-	p.mu.RUnlock()
+	p.mu.Lock()
+	var l *lease
+	for pc, leases := range p.leases {
+		if pc.server == serverName && pc.client == client && len(leases) > 0 {
+			l = leases[0]
+			p.removeLeaseLocked(l)
+			break
+		}
+	}
+	if l != nil {
+		p.releaseLocked(l.pc)
+	}
+	p.mu.Unlock()
 
-	for _, pc := range clients {
-		if pc.client == client {
-			atomic.AddInt32(&pc.refCount, -1)
-			return
+	if l != nil && l.stop != nil {
+		l.stop()
+	}
+}
+
+// leaseLocked records a lease for pc tied to ctx. Must hold p.mu.
+func (p *Pool) leaseLocked(ctx context.Context, pc *pooledClient) {
+	l := &lease{pc: pc, acquired: time.Now(), interrupted: pc.client.Interrupted()}
+	if ctx.Done() != nil {
+		l.stop = context.AfterFunc(ctx, func() { p.expire(l) })
+	}
+	p.leases[pc] = append(p.leases[pc], l)
+}
+
+// expire releases a lease whose context was cancelled before Release.
+func (p *Pool) expire(l *lease) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.removeLeaseLocked(l) {
+		return // already released
+	}
+	p.autoReleased.Add(1)
+	inFlight := l.pc.client.InFlight() > 0 || l.pc.client.Interrupted() > l.interrupted
+	if inFlight && !l.pc.discarded {
+		// The cancelled call may have left the connection mid-request.
+		p.discardLocked(l.pc)
+	}
+	p.releaseLocked(l.pc)
+}
+
+// removeLeaseLocked removes l from the outstanding leases, reporting
+// whether it was still outstanding. Must hold p.mu.
+func (p *Pool) removeLeaseLocked(l *lease) bool {
+	leases := p.leases[l.pc]
+	for i, other := range leases {
+		if other == l {
+			leases = append(leases[:i], leases[i+1:]...)
+			if len(leases) == 0 {
+				delete(p.leases, l.pc)
+			} else {
+				p.leases[l.pc] = leases
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// dropLeasesLocked forgets all leases of pc, e.g. when it is closed.
+// Must hold p.mu.
+func (p *Pool) dropLeasesLocked(pc *pooledClient) {
+	for _, l := range p.leases[pc] {
+		if l.stop != nil {
+			l.stop()
+		}
+	}
+	delete(p.leases, pc)
+}
+
+// releaseLocked drops one reference to pc, closing it if it was discarded
+// and this was the last reference. Must hold p.mu.
+func (p *Pool) releaseLocked(pc *pooledClient) {
+	if atomic.AddInt32(&pc.refCount, -1) == 0 && pc.discarded {
+		_ = pc.client.Close()
+		p.activeConnections.Add(-1)
+	}
+}
+
+// discardLocked removes pc from the pool so it is no longer handed out.
+// It is closed once every holder has released it. Must hold p.mu.
+func (p *Pool) discardLocked(pc *pooledClient) {
+	clients := p.clients[pc.server]
+	for i, c := range clients {
+		if c == pc {
+			p.clients[pc.server] = append(clients[:i], clients[i+1:]...)
+			break
 		}
 	}
+	pc.discarded = true
+	p.discarded.Add(1)
 }
 
 // Close shuts down the pool and all connections.
@@ -364,9 +518,18 @@ func (p *Pool) Close() error {
 			}
 		}
 	}
+	for pc := range p.leases {
+		if pc.discarded {
+			if err := pc.client.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("closing %s: %w", pc.server, err))
+			}
+		}
+		p.dropLeasesLocked(pc)
+	}
 
 	p.clients = nil
 	p.servers = nil
+	p.leases = nil
 
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -382,8 +545,31 @@ func (p *Pool) Stats() PoolStats {
 	for _, clients := range p.clients {
 		clientCount += len(clients)
 	}
+	var inFlight, rejected int
+	for _, config := range p.servers {
+		if config.Bulkhead != nil {
+			stats := config.Bulkhead.Stats()
+			inFlight += stats.InFlight
+			rejected += int(stats.Rejected)
+		}
+	}
+	outstanding := 0
+	var oldest time.Time
+	for _, leases := range p.leases {
+		outstanding += len(leases)
+		for _, l := range leases {
+			if oldest.IsZero() || l.acquired.Before(oldest) {
+				oldest = l.acquired
+			}
+		}
+	}
 	p.mu.RUnlock()
 
+	var oldestAge time.Duration
+	if !oldest.IsZero() {
+		oldestAge = time.Since(oldest)
+	}
+
 	return PoolStats{
 		RegisteredServers:  serverCount,
 		ActiveConnections:  int(p.activeConnections.Load()),
@@ -391,6 +577,12 @@ func (p *Pool) Stats() PoolStats {
 		ConnectionErrors:   int(p.connectionErrors.Load()),
 		HealthChecksPassed: int(p.healthChecksPassed.Load()),
 		HealthChecksFailed: int(p.healthChecksFailed.Load()),
+		OutstandingLeases:  outstanding,
+		OldestLeaseAge:     oldestAge,
+		AutoReleased:       int(p.autoReleased.Load()),
+		Discarded:          int(p.discarded.Load()),
+		BulkheadInFlight:   inFlight,
+		BulkheadRejected:   rejected,
 	}
 }
 
@@ -402,6 +594,24 @@ type PoolStats struct {
 	ConnectionErrors   int
 	HealthChecksPassed int
 	HealthChecksFailed int
+
+	// OutstandingLeases counts connections acquired with Get and not yet
+	// released. A value that keeps growing, or a large OldestLeaseAge,
+	// points at callers that never call Release.
+	OutstandingLeases int
+	// OldestLeaseAge is the age of the oldest outstanding lease.
+	OldestLeaseAge time.Duration
+	// AutoReleased counts leases released because their context was
+	// cancelled before Release was called.
+	AutoReleased int
+	// Discarded counts connections dropped because a request was in
+	// flight when their lease's context was cancelled.
+	Discarded int
+	// BulkheadInFlight is the number of tool calls running through server
+	// bulkheads now.
+	BulkheadInFlight int
+	// BulkheadRejected counts tool calls turned away by server bulkheads.
+	BulkheadRejected int
 }
 
 // ListServers returns the names of all registered servers.
@@ -429,11 +639,15 @@ func (p *Pool) ServerInfo(name string) (ServerConfig, bool) {
 }
 
 func (p *Pool) createClient(ctx context.Context, config *ServerConfig) (*mcp.Client, error) {
+	opts := config.ClientOptions
+	if config.Bulkhead != nil {
+		opts = append(slices.Clip(opts), mcp.WithBulkhead(config.Bulkhead))
+	}
 	switch config.Type {
 	case ServerTypeStdio:
-		return mcp.NewClientWithStdio(config.Command, config.Args, config.Env, config.ClientOptions...)
+		return mcp.NewClientWithStdio(config.Command, config.Args, config.Env, opts...)
 	case ServerTypeHTTP:
-		return mcp.NewClientWithStreamableHTTP(config.URL, config.ClientOptions...)
+		return mcp.NewClientWithStreamableHTTP(config.URL, opts...)
 	default:
 		return nil, fmt.Errorf("unknown server type: %d", config.Type)
 	}
@@ -5,9 +5,16 @@ package pool
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/jllopis/kairos/pkg/mcp"
+	"github.com/jllopis/kairos/pkg/resilience"
+	"github.com/mark3labs/mcp-go/client"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestNewPool(t *testing.T) {
@@ -257,3 +264,174 @@ func TestConcurrentRegister(t *testing.T) {
 		t.Errorf("expected at most 26 servers, got %d", len(p.ListServers()))
 	}
 }
+
+// fakeMCPClient blocks CallTool until its context ends and records Close.
+type fakeMCPClient struct {
+	client.MCPClient
+	closed atomic.Bool
+}
+
+func (f *fakeMCPClient) CallTool(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *fakeMCPClient) Close() error {
+	f.closed.Store(true)
+	return nil
+}
+
+// addFakeClient registers a server backed by an already connected fake client.
+func addFakeClient(t *testing.T, p *Pool, name string) *fakeMCPClient {
+	t.Helper()
+	if err := p.RegisterHTTP(name, "http://localhost:8080/mcp"); err != nil {
+		t.Fatalf("RegisterHTTP failed: %v", err)
+	}
+	fake := &fakeMCPClient{}
+	p.mu.Lock()
+	p.clients[name] = append(p.clients[name], &pooledClient{
+		client:  mcp.NewClient(fake, mcp.WithRetry(0, 0)),
+		server:  name,
+		created: time.Now(),
+	})
+	p.mu.Unlock()
+	p.activeConnections.Add(1)
+	return fake
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGetReleasesOnContextCancel(t *testing.T) {
+	p := New()
+	defer p.Close()
+	fake := addFakeClient(t, p, "srv")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := p.Get(ctx, "srv")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := p.Stats().OutstandingLeases; got != 1 {
+		t.Fatalf("expected 1 outstanding lease, got %d", got)
+	}
+
+	cancel()
+	waitFor(t, func() bool { return p.Stats().OutstandingLeases == 0 })
+
+	stats := p.Stats()
+	if stats.AutoReleased != 1 || stats.Discarded != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if fake.closed.Load() {
+		t.Fatal("idle connection must be kept for reuse")
+	}
+
+	// A late Release after the automatic one must not underflow the refcount.
+	p.Release("srv", c)
+	p.mu.RLock()
+	refs := atomic.LoadInt32(&p.clients["srv"][0].refCount)
+	p.mu.RUnlock()
+	if refs != 0 {
+		t.Fatalf("expected refCount 0, got %d", refs)
+	}
+}
+
+func TestGetDiscardsInFlightOnCancel(t *testing.T) {
+	p := New()
+	defer p.Close()
+	fake := addFakeClient(t, p, "srv")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := p.Get(ctx, "srv")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.CallTool(ctx, "slow", nil)
+	}()
+	waitFor(t, func() bool { return c.InFlight() == 1 })
+
+	cancel()
+	<-done
+	waitFor(t, func() bool { return p.Stats().OutstandingLeases == 0 })
+
+	stats := p.Stats()
+	if stats.Discarded != 1 || stats.ActiveConnections != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if !fake.closed.Load() {
+		t.Fatal("expected in-flight connection to be closed")
+	}
+	p.mu.RLock()
+	remaining := len(p.clients["srv"])
+	p.mu.RUnlock()
+	if remaining != 0 {
+		t.Fatalf("discarded connection must not be reused, %d left", remaining)
+	}
+}
+
+func TestStatsReportsUnreleasedLeases(t *testing.T) {
+	p := New()
+	defer p.Close()
+	addFakeClient(t, p, "srv")
+
+	c1, _ := p.Get(context.Background(), "srv")
+	_, _ = p.Get(context.Background(), "srv")
+
+	stats := p.Stats()
+	if stats.OutstandingLeases != 2 || stats.OldestLeaseAge <= 0 {
+		t.Fatalf("expected 2 outstanding leases, got %+v", stats)
+	}
+
+	p.Release("srv", c1)
+	if got := p.Stats().OutstandingLeases; got != 1 {
+		t.Fatalf("expected 1 outstanding lease, got %d", got)
+	}
+}
+
+func TestPoolBulkhead(t *testing.T) {
+	p := New(WithBulkhead(1, 0))
+	defer p.Close()
+	if err := p.RegisterHTTP("srv", "http://localhost:8080/mcp"); err != nil {
+		t.Fatalf("RegisterHTTP failed: %v", err)
+	}
+	config, _ := p.ServerInfo("srv")
+	if config.Bulkhead == nil {
+		t.Fatal("expected a default bulkhead for the server")
+	}
+
+	// Two connections to the same server share its bulkhead.
+	c1 := mcp.NewClient(&fakeMCPClient{}, mcp.WithRetry(0, 0), mcp.WithBulkhead(config.Bulkhead))
+	c2 := mcp.NewClient(&fakeMCPClient{}, mcp.WithRetry(0, 0), mcp.WithBulkhead(config.Bulkhead))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c1.CallTool(ctx, "slow", nil)
+	}()
+	waitFor(t, func() bool { return p.Stats().BulkheadInFlight == 1 })
+
+	if _, err := c2.CallTool(context.Background(), "slow", nil); !errors.Is(err, resilience.ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull, got %v", err)
+	}
+	cancel()
+	<-done
+
+	stats := p.Stats()
+	if stats.BulkheadInFlight != 0 || stats.BulkheadRejected != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
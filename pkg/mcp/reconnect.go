@@ -0,0 +1,220 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"syscall"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	klog "github.com/jllopis/kairos/pkg/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var logger = klog.For("mcp")
+
+// ErrReconnecting is wrapped by errors returned while the client is
+// re-establishing a lost connection.
+var ErrReconnecting = errors.New("mcp client reconnecting")
+
+const (
+	defaultReconnectInitialBackoff = 500 * time.Millisecond
+	defaultReconnectMaxBackoff     = 30 * time.Second
+	defaultReconnectAttempts       = 10
+	initializeTimeout              = 10 * time.Second
+)
+
+// ReconnectConfig controls automatic reconnection after connection loss.
+type ReconnectConfig struct {
+	// MaxAttempts bounds reconnection attempts per outage (0 = default 10,
+	// negative = unlimited).
+	MaxAttempts int
+	// InitialBackoff is the wait before the first attempt (default 500ms).
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff (default 30s).
+	MaxBackoff time.Duration
+	// OnReconnect, if set, is called after each outage ends, with the
+	// number of attempts used and the final error (nil on success).
+	OnReconnect func(attempts int, err error)
+}
+
+// WithReconnect enables automatic reconnection for Streamable HTTP clients.
+// When the connection is lost (e.g. the server restarts), the client
+// re-runs the initialize handshake with backoff and re-fetches tools.
+// Calls made while reconnecting fail with a recoverable KairosError
+// wrapping ErrReconnecting so retry logic can re-issue them.
+// It has no effect on stdio clients.
+func WithReconnect(cfg ReconnectConfig) ClientOption {
+	return func(c *Client) {
+		if cfg.MaxAttempts == 0 {
+			cfg.MaxAttempts = defaultReconnectAttempts
+		}
+		if cfg.InitialBackoff <= 0 {
+			cfg.InitialBackoff = defaultReconnectInitialBackoff
+		}
+		if cfg.MaxBackoff <= 0 {
+			cfg.MaxBackoff = defaultReconnectMaxBackoff
+		}
+		c.reconnect = &cfg
+	}
+}
+
+// Reconnecting reports whether the client is re-establishing its connection.
+func (c *Client) Reconnecting() bool {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.reconnecting
+}
+
+// conn returns the current underlying connection.
+func (c *Client) conn() client.MCPClient {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.mcpClient
+}
+
+// checkReconnecting fails fast while a reconnection is in progress.
+func (c *Client) checkReconnecting() error {
+	if c.Reconnecting() {
+		return c.reconnectError(nil)
+	}
+	return nil
+}
+
+// handleConnError starts a reconnection when err indicates a lost
+// connection and reports whether the caller should fail with a recoverable
+// error instead of retrying on the same connection.
+func (c *Client) handleConnError(err error) bool {
+	if c.reconnect == nil || c.dial == nil || !isConnectionLost(err) {
+		return false
+	}
+	c.connMu.Lock()
+	if c.closed {
+		c.connMu.Unlock()
+		return false
+	}
+	if !c.reconnecting {
+		c.reconnecting = true
+		logger.Warn("mcp.connection.lost",
+			slog.String("server", c.serverName),
+			slog.String("error", err.Error()),
+		)
+		go c.reconnectLoop()
+	}
+	c.connMu.Unlock()
+	return true
+}
+
+func (c *Client) reconnectError(cause error) error {
+	err := ErrReconnecting
+	if cause != nil {
+		err = fmt.Errorf("%w: %w", ErrReconnecting, cause)
+	}
+	return kerrors.New(kerrors.CodeToolFailure, "mcp connection lost", err).
+		WithContext("server", c.serverName).
+		WithRecoverable(true)
+}
+
+func (c *Client) reconnectLoop() {
+	cfg := c.reconnect
+	backoff := cfg.InitialBackoff
+	var lastErr error
+	attempt := 0
+	for cfg.MaxAttempts < 0 || attempt < cfg.MaxAttempts {
+		attempt++
+		timer := time.NewTimer(backoff)
+		select {
+		case <-c.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), initializeTimeout)
+		conn, err := c.dial(ctx)
+		cancel()
+		if err == nil {
+			c.swapConn(conn)
+			logger.Info("mcp.reconnect.ok",
+				slog.String("server", c.serverName),
+				slog.Int("attempts", attempt),
+			)
+			if cfg.OnReconnect != nil {
+				cfg.OnReconnect(attempt, nil)
+			}
+			return
+		}
+		lastErr = err
+		backoff *= 2
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+
+	// Give up for this outage; the next connection error starts over.
+	c.connMu.Lock()
+	c.reconnecting = false
+	c.connMu.Unlock()
+	logger.Error("mcp.reconnect.failed",
+		slog.String("server", c.serverName),
+		slog.Int("attempts", attempt),
+		slog.Any("error", lastErr),
+	)
+	if cfg.OnReconnect != nil {
+		cfg.OnReconnect(attempt, lastErr)
+	}
+}
+
+// swapConn installs a freshly initialized connection and refreshes the
+// tool cache from it.
+func (c *Client) swapConn(conn client.MCPClient) {
+	c.connMu.Lock()
+	if c.closed {
+		c.connMu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	old := c.mcpClient
+	c.watchNotifications(conn)
+	c.mcpClient = conn
+	c.reconnecting = false
+	c.connMu.Unlock()
+	_ = old.Close()
+
+	c.mu.Lock()
+	c.toolsCache = nil
+	c.cacheExpiry = time.Time{}
+	c.mu.Unlock()
+
+	ctx, cancel := c.withTimeout(context.Background())
+	defer cancel()
+	if res, err := conn.ListTools(ctx, mcp.ListToolsRequest{}); err == nil {
+		c.storeTools(res.Tools)
+	}
+}
+
+// isConnectionLost reports whether err means the transport or session is
+// gone, as opposed to a protocol or tool error.
+func isConnectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, transport.ErrSessionTerminated) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,367 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaBuilder builds the JSON schema of a tool input object, so tools can
+// be registered without hand-written JSON:
+//
+//	schema := mcp.ObjectSchema().
+//		String("query", "Text to search", mcp.Required()).
+//		Integer("limit", "Maximum results", mcp.Minimum(1), mcp.Maximum(100))
+type SchemaBuilder struct {
+	properties map[string]any
+	required   []string
+	strict     bool
+}
+
+// ObjectSchema starts an object schema with no properties.
+func ObjectSchema() *SchemaBuilder {
+	return &SchemaBuilder{properties: map[string]any{}}
+}
+
+// PropertyOption refines a property added to a SchemaBuilder.
+type PropertyOption func(*schemaProperty)
+
+type schemaProperty struct {
+	schema   map[string]any
+	required bool
+}
+
+// Required marks the property as required.
+func Required() PropertyOption {
+	return func(p *schemaProperty) { p.required = true }
+}
+
+// Enum restricts the property to values.
+func Enum(values ...any) PropertyOption {
+	return func(p *schemaProperty) { p.schema["enum"] = values }
+}
+
+// Default documents the value used when the property is omitted. It is not
+// applied to the arguments.
+func Default(value any) PropertyOption {
+	return func(p *schemaProperty) { p.schema["default"] = value }
+}
+
+// Minimum sets the inclusive lower bound of a number or integer property.
+func Minimum(v float64) PropertyOption {
+	return func(p *schemaProperty) { p.schema["minimum"] = v }
+}
+
+// Maximum sets the inclusive upper bound of a number or integer property.
+func Maximum(v float64) PropertyOption {
+	return func(p *schemaProperty) { p.schema["maximum"] = v }
+}
+
+// MinLength sets the minimum length of a string property.
+func MinLength(n int) PropertyOption {
+	return func(p *schemaProperty) { p.schema["minLength"] = n }
+}
+
+// MaxLength sets the maximum length of a string property.
+func MaxLength(n int) PropertyOption {
+	return func(p *schemaProperty) { p.schema["maxLength"] = n }
+}
+
+// Pattern sets the regular expression a string property must match.
+func Pattern(expr string) PropertyOption {
+	return func(p *schemaProperty) { p.schema["pattern"] = expr }
+}
+
+// String adds a string property.
+func (b *SchemaBuilder) String(name, description string, opts ...PropertyOption) *SchemaBuilder {
+	return b.Property(name, map[string]any{"type": "string", "description": description}, opts...)
+}
+
+// Integer adds an integer property. JSON numbers still reach the handler as
+// float64.
+func (b *SchemaBuilder) Integer(name, description string, opts ...PropertyOption) *SchemaBuilder {
+	return b.Property(name, map[string]any{"type": "integer", "description": description}, opts...)
+}
+
+// Number adds a number property.
+func (b *SchemaBuilder) Number(name, description string, opts ...PropertyOption) *SchemaBuilder {
+	return b.Property(name, map[string]any{"type": "number", "description": description}, opts...)
+}
+
+// Boolean adds a boolean property.
+func (b *SchemaBuilder) Boolean(name, description string, opts ...PropertyOption) *SchemaBuilder {
+	return b.Property(name, map[string]any{"type": "boolean", "description": description}, opts...)
+}
+
+// Array adds an array property whose items have itemType (e.g. "string").
+func (b *SchemaBuilder) Array(name, description, itemType string, opts ...PropertyOption) *SchemaBuilder {
+	return b.Property(name, map[string]any{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]any{"type": itemType},
+	}, opts...)
+}
+
+// Object adds a nested object property described by nested.
+func (b *SchemaBuilder) Object(name, description string, nested *SchemaBuilder, opts ...PropertyOption) *SchemaBuilder {
+	schema := nested.Build()
+	schema["description"] = description
+	return b.Property(name, schema, opts...)
+}
+
+// Property adds a property with an arbitrary schema.
+func (b *SchemaBuilder) Property(name string, schema map[string]any, opts ...PropertyOption) *SchemaBuilder {
+	p := &schemaProperty{schema: schema}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if desc, ok := p.schema["description"].(string); ok && desc == "" {
+		delete(p.schema, "description")
+	}
+	b.properties[name] = p.schema
+	if p.required {
+		b.required = append(b.required, name)
+	}
+	return b
+}
+
+// Strict rejects properties not declared in the schema.
+func (b *SchemaBuilder) Strict() *SchemaBuilder {
+	b.strict = true
+	return b
+}
+
+// Build returns the schema as a JSON-compatible map.
+func (b *SchemaBuilder) Build() map[string]any {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": b.properties,
+	}
+	if len(b.required) > 0 {
+		schema["required"] = append([]string(nil), b.required...)
+	}
+	if b.strict {
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+// NormalizeSchema converts a schema in any form accepted by RegisterTool,
+// such as a SchemaBuilder or raw JSON, into a map.
+func NormalizeSchema(schema interface{}) (map[string]any, error) {
+	return toSchemaMap(schema)
+}
+
+// ValidateSchema checks a decoded JSON value against schema and returns the
+// violations. It supports the keywords the SchemaBuilder produces.
+func ValidateSchema(schema map[string]any, value any) []SchemaViolation {
+	return validateSchema(schema, value, "")
+}
+
+// toSchemaMap converts the schema accepted by RegisterTool into a map.
+// A nil schema is an object with any properties.
+func toSchemaMap(schema interface{}) (map[string]any, error) {
+	var raw []byte
+	switch s := schema.(type) {
+	case nil:
+		return map[string]any{"type": "object"}, nil
+	case *SchemaBuilder:
+		return toSchemaMap(s.Build())
+	case map[string]any:
+		// Round-trip to normalize nested values such as []string.
+		encoded, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		raw = encoded
+	case json.RawMessage:
+		raw = s
+	case []byte:
+		raw = s
+	case string:
+		raw = []byte(s)
+	default:
+		encoded, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		raw = encoded
+	}
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, fmt.Errorf("schema must be a JSON object")
+	}
+	if _, ok := out["type"]; !ok {
+		out["type"] = "object"
+	}
+	return out, nil
+}
+
+// SchemaViolation describes an argument that does not match the input
+// schema of a tool.
+type SchemaViolation struct {
+	// Path is the dotted path of the argument, e.g. "filter.limit"; empty
+	// for the arguments object itself.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// validateSchema checks value against schema. It supports the keywords the
+// SchemaBuilder produces: type, properties, required, additionalProperties,
+// items, enum, minimum, maximum, minLength, maxLength and pattern.
+func validateSchema(schema map[string]any, value any, path string) []SchemaViolation {
+	var out []SchemaViolation
+	fail := func(format string, args ...any) {
+		out = append(out, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if matchesType(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("must be %s", strings.Join(types, " or "))
+			return out
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(enum, value) {
+		fail("must be one of %v", enum)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for _, name := range stringList(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				out = append(out, SchemaViolation{Path: joinPath(path, name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if sub, ok := properties[name].(map[string]any); ok {
+				out = append(out, validateSchema(sub, v[name], joinPath(path, name))...)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					out = append(out, SchemaViolation{Path: joinPath(path, name), Message: "is not allowed"})
+				}
+			case map[string]any:
+				out = append(out, validateSchema(extra, v[name], joinPath(path, name))...)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				out = append(out, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			fail("must be >= %v", min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			fail("must be <= %v", max)
+		}
+	case string:
+		length := len([]rune(v))
+		if min, ok := schema["minLength"].(float64); ok && float64(length) < min {
+			fail("must be at least %v characters", min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && float64(length) > max {
+			fail("must be at most %v characters", max)
+		}
+		if expr, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(expr); err == nil && !re.MatchString(v) {
+				fail("must match %q", expr)
+			}
+		}
+	}
+	return out
+}
+
+func schemaTypes(t any) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	default:
+		return stringList(t)
+	}
+}
+
+func stringList(v any) []string {
+	items, _ := v.([]any)
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func matchesType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func inEnum(enum []any, value any) bool {
+	encoded, _ := json.Marshal(value)
+	for _, candidate := range enum {
+		if c, _ := json.Marshal(candidate); string(c) == string(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
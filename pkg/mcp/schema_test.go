@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServerRegisterToolSchema(t *testing.T) {
+	s := NewServer("test-schema", "1.0.0")
+	calls := 0
+	s.RegisterTool("search", "Search documents", ObjectSchema().
+		String("query", "Text to search", Required(), MinLength(1)).
+		Integer("limit", "Maximum results", Minimum(1), Maximum(100)).
+		String("order", "Sort order", Enum("asc", "desc")).
+		Strict(),
+		func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+			calls++
+			return mcpgo.NewToolResultText("ok"), nil
+		})
+
+	httpServer := httptest.NewServer(s.StreamableHTTPServer())
+	defer httpServer.Close()
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	tools, err := client.ListTools(ctx)
+	if err != nil || len(tools) != 1 {
+		t.Fatalf("ListTools: %+v, %v", tools, err)
+	}
+	schema := tools[0].InputSchema
+	if schema.Type != "object" || len(schema.Required) != 1 || schema.Required[0] != "query" || schema.Properties["limit"] == nil {
+		t.Fatalf("schema not advertised: %+v", schema)
+	}
+
+	result, err := client.CallTool(ctx, "search", map[string]interface{}{"limit": 2.5, "order": "random", "extra": true})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if !result.IsError || calls != 0 {
+		t.Fatalf("expected invalid arguments error, got %+v", result)
+	}
+	text := result.Content[0].(mcpgo.TextContent).Text
+	for _, want := range []string{"query: is required", "limit: must be integer", "order: must be one of", "extra: is not allowed"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %q", want, text)
+		}
+	}
+	structured, ok := result.StructuredContent.(map[string]any)
+	if !ok || structured["error"] != "invalid_arguments" {
+		t.Fatalf("expected structured error, got %#v", result.StructuredContent)
+	}
+
+	result, err = client.CallTool(ctx, "search", map[string]interface{}{"query": "kairos", "limit": 10})
+	if err != nil || result.IsError || calls != 1 {
+		t.Fatalf("expected valid call to run, got %+v, %v", result, err)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema, err := toSchemaMap(`{
+		"type": "object",
+		"properties": {
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"filter": {"type": "object", "properties": {"id": {"type": "string", "pattern": "^[a-z]+$"}}, "required": ["id"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("toSchemaMap: %v", err)
+	}
+	violations := validateSchema(schema, map[string]any{
+		"tags":   []any{"a", 1.0},
+		"filter": map[string]any{"id": "ABC"},
+	}, "")
+	got := make([]string, len(violations))
+	for i, v := range violations {
+		got[i] = v.String()
+	}
+	want := []string{`filter.id: must match "^[a-z]+$"`, "tags[1]: must be string"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if v := validateSchema(schema, map[string]any{"filter": map[string]any{"id": "abc"}}, ""); len(v) != 0 {
+		t.Fatalf("expected no violations, got %v", v)
+	}
+}
@@ -2,6 +2,15 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -10,6 +19,9 @@ import (
 // Server wraps the mcp-go server to provide Kairos-specific functionality.
 type Server struct {
 	mcpServer *server.MCPServer
+
+	mu          sync.RWMutex
+	middlewares []ToolMiddleware
 }
 
 // NewServer creates a new MCP server.
@@ -19,20 +31,77 @@ func NewServer(name, version string) *Server {
 	}
 }
 
-// RegisterTool registers a tool with the server and dispatches to handler.
-// The schema argument is reserved for future validation support.
-func (s *Server) RegisterTool(name, description string, schema interface{}, handler func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error)) {
-	tool := mcp.NewTool(name, mcp.WithDescription(description))
+// RegisterTool registers a tool with the server and dispatches to handler
+// through the middlewares added with Use.
+//
+// The schema describes the input arguments and is advertised in ListTools.
+// It may be a *SchemaBuilder, a map, JSON (json.RawMessage, []byte or
+// string) or any value that marshals to a JSON schema; nil accepts any
+// object. Arguments that do not match it get an error result listing the
+// violations, and handler is not called. RegisterTool panics if schema is
+// not a JSON object.
+func (s *Server) RegisterTool(name, description string, schema interface{}, handler ToolHandler) {
+	inputSchema, err := toSchemaMap(schema)
+	if err != nil {
+		panic(fmt.Sprintf("mcp: invalid input schema for tool %q: %v", name, err))
+	}
+	rawSchema, err := json.Marshal(inputSchema)
+	if err != nil {
+		panic(fmt.Sprintf("mcp: invalid input schema for tool %q: %v", name, err))
+	}
+	tool := mcp.NewToolWithRawSchema(name, description, rawSchema)
+	validated := validateArgs(inputSchema, handler)
 
 	s.mcpServer.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := request.Params.Arguments.(map[string]interface{})
-		return handler(ctx, args)
+		return s.chain(validated)(withToolName(ctx, name), args)
 	})
 }
 
-// ServeStdio starts the server on Stdio.
-func (s *Server) ServeStdio() error {
-	return server.ServeStdio(s.mcpServer)
+// validateArgs wraps handler so that it only runs with arguments that match
+// schema.
+func validateArgs(schema map[string]any, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		var value any = args
+		if args == nil {
+			value = map[string]any{}
+		}
+		violations := validateSchema(schema, value, "")
+		if len(violations) == 0 {
+			return handler(ctx, args)
+		}
+		messages := make([]string, len(violations))
+		for i, v := range violations {
+			messages[i] = v.String()
+		}
+		result := mcp.NewToolResultError("invalid arguments: " + strings.Join(messages, "; "))
+		result.StructuredContent = map[string]any{
+			"error":      "invalid_arguments",
+			"violations": violations,
+		}
+		return result, nil
+	}
+}
+
+// ServeStdio serves the MCP stdio protocol over os.Stdin and os.Stdout, as
+// expected by clients that launch the server as a subprocess. It returns nil
+// when stdin is closed, ctx is cancelled or the process gets SIGINT or
+// SIGTERM. Tools must not write to stdout; logs belong on stderr.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.ServeStdioIO(ctx, os.Stdin, os.Stdout)
+}
+
+// ServeStdioIO serves the MCP stdio protocol over in and out, e.g. pipes
+// of an embedding process. It returns nil when in is closed or ctx is
+// cancelled.
+func (s *Server) ServeStdioIO(ctx context.Context, in io.Reader, out io.Writer) error {
+	err := server.NewStdioServer(s.mcpServer).Listen(ctx, in, out)
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
 }
 
 // StreamableHTTPServer returns a Streamable HTTP server for custom routing.
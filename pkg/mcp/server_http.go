@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// HTTPServer is a Server serving Streamable HTTP in the background, as
+// returned by StartStreamableHTTP. Tools can still be registered on it
+// after it starts.
+type HTTPServer struct {
+	*Server
+
+	listener   net.Listener
+	httpServer *http.Server
+	done       chan error
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// StartStreamableHTTP starts serving Streamable HTTP on addr and returns
+// once the listener is open, so clients can connect right away. If addr is
+// empty, it defaults to "localhost:8080"; use "localhost:0" to pick a free
+// port and read it back with BaseURL. The server answers on every path.
+// Call Shutdown to stop it.
+func (s *Server) StartStreamableHTTP(addr string, opts ...server.StreamableHTTPOption) (*HTTPServer, error) {
+	if addr == "" {
+		addr = "localhost:8080"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	h := &HTTPServer{
+		Server:     s,
+		listener:   listener,
+		httpServer: &http.Server{Handler: server.NewStreamableHTTPServer(s.mcpServer, opts...)},
+		done:       make(chan error, 1),
+	}
+	go func() {
+		err := h.httpServer.Serve(listener)
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		h.done <- err
+	}()
+	return h, nil
+}
+
+// BaseURL returns the URL clients connect to, e.g. "http://127.0.0.1:8080".
+func (h *HTTPServer) BaseURL() string {
+	return "http://" + h.listener.Addr().String()
+}
+
+// Shutdown stops the server. It closes the listener at once and waits for
+// in-flight requests until ctx is done; connections still open then, such
+// as notification streams, are closed and ctx's error is returned. Later
+// calls wait for the first one and return its result.
+func (h *HTTPServer) Shutdown(ctx context.Context) error {
+	h.shutdownOnce.Do(func() {
+		err := h.httpServer.Shutdown(ctx)
+		if err != nil {
+			h.httpServer.Close()
+		}
+		if serveErr := <-h.done; err == nil {
+			err = serveErr
+		}
+		h.shutdownErr = err
+	})
+	return h.shutdownErr
+}
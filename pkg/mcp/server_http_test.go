@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServer_StartStreamableHTTP(t *testing.T) {
+	server := NewServer("test-http", "1.0.0")
+	httpServer, err := server.StartStreamableHTTP("localhost:0")
+	if err != nil {
+		t.Fatalf("StartStreamableHTTP error: %v", err)
+	}
+	httpServer.RegisterTool("echo", "Echo a message", ObjectSchema().String("message", "Message", Required()),
+		func(_ context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+			return mcpgo.NewToolResultText(args["message"].(string)), nil
+		})
+
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.BaseURL(), mcpgo.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	result, err := client.CallTool(context.Background(), "echo", map[string]interface{}{"message": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if text := result.Content[0].(mcpgo.TextContent).Text; text != "hi" {
+		t.Fatalf("expected echo, got %q", text)
+	}
+	client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	// A second Shutdown returns at once with the same result.
+	if err := httpServer.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown error: %v", err)
+	}
+	addr := strings.TrimPrefix(httpServer.BaseURL(), "http://")
+	if conn, err := net.Dial("tcp", addr); err == nil {
+		conn.Close()
+		t.Fatalf("expected listener on %s to be closed", addr)
+	}
+}
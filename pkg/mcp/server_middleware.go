@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/governance"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolHandler handles a call to a tool registered with Server.RegisterTool.
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error)
+
+// ToolMiddleware wraps a ToolHandler, e.g. for authorization, rate limiting
+// or logging. ToolNameFromContext returns the name of the called tool.
+type ToolMiddleware func(next ToolHandler) ToolHandler
+
+type toolNameKey struct{}
+
+func withToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, name)
+}
+
+// ToolNameFromContext returns the name of the tool being called, inside a
+// ToolMiddleware or ToolHandler.
+func ToolNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(toolNameKey{}).(string)
+	return name
+}
+
+// Use adds middlewares that wrap every tool handler, including the ones
+// already registered. The first middleware added is the outermost.
+func (s *Server) Use(middlewares ...ToolMiddleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, mw := range middlewares {
+		if mw != nil {
+			s.middlewares = append(s.middlewares, mw)
+		}
+	}
+}
+
+func (s *Server) chain(handler ToolHandler) ToolHandler {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](handler)
+	}
+	return handler
+}
+
+// PolicyMiddleware denies the tool calls that engine does not allow. Calls
+// are evaluated as governance.ActionTool with the principal of the context
+// (see governance.WithPrincipal); a denied call returns an MCP error result
+// with the policy reason instead of running the tool.
+func PolicyMiddleware(engine governance.PolicyEngine) ToolMiddleware {
+	return func(next ToolHandler) ToolHandler {
+		if engine == nil {
+			return next
+		}
+		return func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			decision := engine.Evaluate(ctx, governance.Action{
+				Type:      governance.ActionTool,
+				Name:      ToolNameFromContext(ctx),
+				Principal: governance.PrincipalFromContext(ctx),
+			})
+			if !decision.IsAllowed() {
+				return mcp.NewToolResultError(policyReason(decision)), nil
+			}
+			return next(ctx, args)
+		}
+	}
+}
+
+// policyReason returns the reason of a decision that did not allow an
+// action, with a default when the policy gave none.
+func policyReason(decision governance.Decision) string {
+	if reason := strings.TrimSpace(decision.Reason); reason != "" {
+		return reason
+	}
+	if decision.IsPending() {
+		return "approval required"
+	}
+	return "blocked by policy"
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jllopis/kairos/pkg/governance"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServerPolicyMiddleware(t *testing.T) {
+	s := NewServer("test-policy", "1.0.0")
+	var order []string
+	called := map[string]int{}
+	for _, name := range []string{"read_file", "write_file"} {
+		s.RegisterTool(name, name, nil, func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+			called[ToolNameFromContext(ctx)]++
+			return mcpgo.NewToolResultText("ok"), nil
+		})
+	}
+	// Middlewares added after registration apply too; the first is outermost.
+	s.Use(func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+			order = append(order, "principal")
+			principal, _ := args["as"].(string)
+			return next(governance.WithPrincipal(ctx, principal), args)
+		}
+	})
+	s.Use(PolicyMiddleware(governance.NewRuleSet([]governance.Rule{
+		{ID: "deny-writes", Effect: "deny", Type: governance.ActionTool, Name: "write_file", Principal: "guest", Reason: "guests are read-only"},
+	})))
+
+	httpServer := httptest.NewServer(s.StreamableHTTPServer())
+	defer httpServer.Close()
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	result, err := client.CallTool(ctx, "write_file", map[string]interface{}{"as": "guest"})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if !result.IsError || len(result.Content) == 0 || !strings.Contains(result.Content[0].(mcpgo.TextContent).Text, "guests are read-only") {
+		t.Fatalf("expected policy error result, got %+v", result)
+	}
+	if called["write_file"] != 0 {
+		t.Fatalf("denied tool must not run")
+	}
+
+	for _, call := range []struct{ tool, as string }{{"write_file", "admin"}, {"read_file", "guest"}} {
+		result, err := client.CallTool(ctx, call.tool, map[string]interface{}{"as": call.as})
+		if err != nil || result.IsError {
+			t.Fatalf("expected %s as %s to be allowed, got %+v, %v", call.tool, call.as, result, err)
+		}
+	}
+	if called["write_file"] != 1 || called["read_file"] != 1 || len(order) != 3 {
+		t.Fatalf("unexpected calls %v, middleware runs %d", called, len(order))
+	}
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceReader returns the current contents of a resource.
+type ResourceReader func(ctx context.Context) ([]byte, error)
+
+// PromptBuilder renders the messages of a prompt from its arguments.
+type PromptBuilder func(args map[string]string) []mcp.PromptMessage
+
+// RegisterResource exposes the contents returned by reader at uri, e.g.
+// "docs://kairos/README.md". Resources are listed by ListResources and
+// read on demand; the server advertises the resources capability once one
+// is registered. Contents are sent as text when mimeType is textual or the
+// data is valid UTF-8, and base64-encoded otherwise.
+func (s *Server) RegisterResource(uri, mimeType string, reader ResourceReader) {
+	resource := mcp.NewResource(uri, uri, mcp.WithMIMEType(mimeType))
+	s.mcpServer.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		data, err := reader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("read resource %s: %w", uri, err)
+		}
+		if isTextMIME(mimeType) || (mimeType == "" && utf8.Valid(data)) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}}, nil
+		}
+		return []mcp.ResourceContents{mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}}, nil
+	})
+}
+
+// RegisterPrompt exposes a prompt template named name. The arguments are
+// advertised by ListPrompts; a GetPrompt call missing a required argument
+// fails without calling builder.
+func (s *Server) RegisterPrompt(name, description string, builder PromptBuilder, arguments ...mcp.PromptArgument) {
+	prompt := mcp.NewPrompt(name, mcp.WithPromptDescription(description))
+	prompt.Arguments = arguments
+	s.mcpServer.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+		for _, arg := range arguments {
+			if _, ok := args[arg.Name]; arg.Required && !ok {
+				return nil, fmt.Errorf("prompt %s: missing required argument %q", name, arg.Name)
+			}
+		}
+		if args == nil {
+			args = map[string]string{}
+		}
+		return mcp.NewGetPromptResult(description, builder(args)), nil
+	})
+}
+
+func isTextMIME(mimeType string) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	mimeType = strings.TrimSpace(mimeType)
+	switch {
+	case strings.HasPrefix(mimeType, "text/"):
+		return true
+	case mimeType == "application/json", mimeType == "application/xml",
+		mimeType == "application/yaml", mimeType == "application/x-yaml",
+		strings.HasSuffix(mimeType, "+json"), strings.HasSuffix(mimeType, "+xml"):
+		return true
+	default:
+		return false
+	}
+}
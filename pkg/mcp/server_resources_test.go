@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServerResourcesAndPrompts(t *testing.T) {
+	s := NewServer("test-resources", "1.0.0")
+	s.RegisterResource("docs://kairos/README.md", "text/markdown", func(ctx context.Context) ([]byte, error) {
+		return []byte("# Kairos"), nil
+	})
+	s.RegisterResource("docs://kairos/logo.png", "image/png", func(ctx context.Context) ([]byte, error) {
+		return []byte{0x89, 'P', 'N', 'G'}, nil
+	})
+	s.RegisterPrompt("summarize", "Summarize a document", func(args map[string]string) []mcpgo.PromptMessage {
+		return []mcpgo.PromptMessage{
+			mcpgo.NewPromptMessage(mcpgo.RoleUser, mcpgo.NewTextContent("Summarize "+args["uri"])),
+		}
+	}, mcpgo.PromptArgument{Name: "uri", Description: "Document URI", Required: true})
+
+	httpServer := httptest.NewServer(s.StreamableHTTPServer())
+	defer httpServer.Close()
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	resources, err := client.ListResources(ctx)
+	if err != nil || len(resources) != 2 {
+		t.Fatalf("ListResources: %+v, %v", resources, err)
+	}
+	contents, err := client.ReadResource(ctx, "docs://kairos/README.md")
+	if err != nil {
+		t.Fatalf("ReadResource error: %v", err)
+	}
+	if text, ok := contents[0].(mcpgo.TextResourceContents); !ok || text.Text != "# Kairos" || text.MIMEType != "text/markdown" {
+		t.Fatalf("unexpected text contents %+v", contents)
+	}
+	contents, err = client.ReadResource(ctx, "docs://kairos/logo.png")
+	if err != nil {
+		t.Fatalf("ReadResource error: %v", err)
+	}
+	blob, ok := contents[0].(mcpgo.BlobResourceContents)
+	if data, _ := base64.StdEncoding.DecodeString(blob.Blob); !ok || string(data) != "\x89PNG" {
+		t.Fatalf("unexpected blob contents %+v", contents)
+	}
+	if _, err := client.ReadResource(ctx, "docs://missing"); err == nil {
+		t.Fatal("expected error for unknown resource")
+	}
+
+	prompts, err := client.ListPrompts(ctx)
+	if err != nil || len(prompts) != 1 || len(prompts[0].Arguments) != 1 || !prompts[0].Arguments[0].Required {
+		t.Fatalf("ListPrompts: %+v, %v", prompts, err)
+	}
+	prompt, err := client.GetPrompt(ctx, "summarize", map[string]string{"uri": "docs://kairos/README.md"})
+	if err != nil {
+		t.Fatalf("GetPrompt error: %v", err)
+	}
+	if text, ok := prompt.Messages[0].Content.(mcpgo.TextContent); !ok || text.Text != "Summarize docs://kairos/README.md" {
+		t.Fatalf("unexpected prompt %+v", prompt)
+	}
+	if _, err := client.GetPrompt(ctx, "summarize", nil); err == nil {
+		t.Fatal("expected error for missing required argument")
+	}
+}
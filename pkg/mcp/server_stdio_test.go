@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+const serverStdioHelperEnv = "KAIROS_MCP_SERVER_STDIO_HELPER"
+
+func TestHelperServerStdio(t *testing.T) {
+	if os.Getenv(serverStdioHelperEnv) != "1" {
+		return
+	}
+	s := NewServer("kairos-stdio", "1.0.0")
+	s.RegisterTool("greet", "Greet someone", ObjectSchema().String("name", "Who to greet", Required()),
+		func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+			return mcpgo.NewToolResultText("hello " + args["name"].(string)), nil
+		})
+	if err := s.ServeStdio(context.Background()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestServerServeStdio(t *testing.T) {
+	t.Setenv(serverStdioHelperEnv, "1")
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	client, err := NewClientWithStdioProtocol(exe, []string{"-test.run", "TestHelperServerStdio"}, nil, mcpgo.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		t.Fatalf("NewClientWithStdioProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	tools, err := client.ListTools(ctx)
+	if err != nil || len(tools) != 1 || tools[0].Name != "greet" || tools[0].InputSchema.Properties["name"] == nil {
+		t.Fatalf("unexpected tools %+v, %v", tools, err)
+	}
+	result, err := client.CallTool(ctx, "greet", map[string]interface{}{"name": "kairos"})
+	if err != nil || result.IsError || result.Content[0].(mcpgo.TextContent).Text != "hello kairos" {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+	result, err = client.CallTool(ctx, "greet", nil)
+	if err != nil || !result.IsError {
+		t.Fatalf("expected invalid arguments error, got %+v, %v", result, err)
+	}
+}
+
+func TestServerServeStdioIOStopsOnCancel(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer("kairos-stdio", "1.0.0").ServeStdioIO(ctx, in, io.Discard) }()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil on cancel, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeStdioIO did not stop on cancel")
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// watchNotifications subscribes to the notifications of conn. Only mcp-go
+// clients are subscribed; other MCPClient implementations, such as test
+// doubles, are left alone.
+func (c *Client) watchNotifications(conn client.MCPClient) {
+	if mc, ok := conn.(*client.Client); ok {
+		mc.OnNotification(c.handleNotification)
+	}
+}
+
+// handleNotification reacts to server notifications. Progress goes to the
+// CallToolStream call that requested it. A tools list change drops the
+// cached tools and, with a WithToolChangeHandler handler, refetches them in
+// the background: the notification arrives on the transport goroutine,
+// which must stay free to read the response.
+func (c *Client) handleNotification(notification mcp.JSONRPCNotification) {
+	if notification.Method == methodNotificationProgress {
+		c.handleProgress(notification)
+		return
+	}
+	if notification.Method != mcp.MethodNotificationToolsListChanged {
+		return
+	}
+	c.invalidateTools()
+	if c.toolChangeHandler == nil {
+		return
+	}
+	go c.refetchTools()
+}
+
+func (c *Client) invalidateTools() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.toolsCache = nil
+	c.cacheExpiry = time.Time{}
+}
+
+func (c *Client) refetchTools() {
+	if c.checkReconnecting() != nil {
+		return
+	}
+	ctx := context.Background()
+	resp, err := c.listToolsWithRetry(ctx, mcp.ListToolsRequest{})
+	c.noteInterrupted(ctx, err)
+	if err != nil {
+		return
+	}
+	c.storeTools(resp.Tools)
+	c.toolChangeHandler(resp.Tools)
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+const toolChangesHelperEnv = "KAIROS_MCP_TOOL_CHANGES_HELPER"
+
+func TestHelperToolChangesServer(t *testing.T) {
+	if os.Getenv(toolChangesHelperEnv) != "1" {
+		return
+	}
+	s := NewServer("test-changes", "1.0.0")
+	ok := func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+		return mcpgo.NewToolResultText("ok"), nil
+	}
+	s.RegisterTool("install", "Registers another tool", nil, func(ctx context.Context, args map[string]interface{}) (*mcpgo.CallToolResult, error) {
+		s.RegisterTool("installed", "Added at runtime", nil, ok)
+		return mcpgo.NewToolResultText("installed"), nil
+	})
+	if err := s.ServeStdio(context.Background()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestClientToolListChanged(t *testing.T) {
+	t.Setenv(toolChangesHelperEnv, "1")
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	changes := make(chan []mcpgo.Tool, 1)
+	client, err := NewClientWithStdioProtocol(exe, []string{"-test.run", "TestHelperToolChangesServer"}, nil, mcpgo.LATEST_PROTOCOL_VERSION,
+		WithToolCacheTTL(time.Hour),
+		WithToolChangeHandler(func(tools []mcpgo.Tool) { changes <- tools }),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithStdioProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if tools, err := client.ListTools(ctx); err != nil || len(tools) != 1 {
+		t.Fatalf("ListTools: %+v, %v", tools, err)
+	}
+	if _, err := client.CallTool(ctx, "install", nil); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	select {
+	case tools := <-changes:
+		if len(tools) != 2 {
+			t.Fatalf("expected refetched tools, got %+v", tools)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool change handler not called")
+	}
+	// The cache holds the refetched list despite the long TTL.
+	if tools, err := client.ListTools(ctx); err != nil || len(tools) != 2 {
+		t.Fatalf("expected cache to be refreshed, got %+v, %v", tools, err)
+	}
+}
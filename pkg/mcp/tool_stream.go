@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// methodNotificationProgress is the MCP progress notification method.
+const methodNotificationProgress = "notifications/progress"
+
+// ToolChunk is a piece of the output of a tool call made with
+// CallToolStream. Progress chunks carry Text and the progress counters; the
+// last chunk has Done set and carries the call result or error.
+type ToolChunk struct {
+	// Text is the progress message sent by the server.
+	Text string
+	// Progress and Total are the progress counters; Total is 0 when the
+	// server does not know it.
+	Progress float64
+	Total    float64
+	// Data holds the notification fields beyond the standard ones, for
+	// servers that attach structured partial output.
+	Data map[string]any
+
+	Done   bool
+	Result *mcp.CallToolResult
+	Err    error
+}
+
+// CallToolStream executes a tool on the server and streams its progress.
+// It asks the server for progress notifications and delivers each one as a
+// chunk as it arrives, followed by a final chunk with the result. Servers
+// that send no progress produce a single final chunk.
+//
+// Policy and reconnection errors are returned directly; errors of the call
+// itself arrive in the final chunk. The channel is closed after the final
+// chunk, or early when ctx is done.
+func (c *Client) CallToolStream(ctx context.Context, name string, args map[string]interface{}) (<-chan ToolChunk, error) {
+	if err := c.checkToolCall(ctx, name); err != nil {
+		return nil, err
+	}
+	token := fmt.Sprintf("kairos-%d", c.progressSeq.Add(1))
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	req.Params.Meta = &mcp.Meta{ProgressToken: token}
+
+	stream := &progressStream{notify: make(chan struct{}, 1)}
+	c.progressMu.Lock()
+	if c.progress == nil {
+		c.progress = make(map[string]*progressStream)
+	}
+	c.progress[token] = stream
+	c.progressMu.Unlock()
+
+	type callResult struct {
+		resp *mcp.CallToolResult
+		err  error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		resp, err := c.callTool(ctx, req)
+		done <- callResult{resp, err}
+	}()
+
+	out := make(chan ToolChunk)
+	go func() {
+		defer close(out)
+		defer func() {
+			c.progressMu.Lock()
+			delete(c.progress, token)
+			c.progressMu.Unlock()
+		}()
+		send := func(chunk ToolChunk) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- chunk:
+				return true
+			}
+		}
+		for {
+			select {
+			case <-stream.notify:
+				for _, chunk := range stream.drain() {
+					if !send(chunk) {
+						return
+					}
+				}
+			case res := <-done:
+				// Progress sent before the response is delivered first.
+				for _, chunk := range stream.drain() {
+					if !send(chunk) {
+						return
+					}
+				}
+				send(ToolChunk{Done: true, Result: res.resp, Err: res.err})
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// progressStream queues the progress chunks of one call. Notifications
+// arrive on the transport goroutine, which must not block on a slow reader.
+type progressStream struct {
+	mu      sync.Mutex
+	pending []ToolChunk
+	notify  chan struct{}
+}
+
+func (s *progressStream) push(chunk ToolChunk) {
+	s.mu.Lock()
+	s.pending = append(s.pending, chunk)
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *progressStream) drain() []ToolChunk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chunks := s.pending
+	s.pending = nil
+	return chunks
+}
+
+// handleProgress routes a progress notification to the CallToolStream
+// call that owns its token.
+func (c *Client) handleProgress(notification mcp.JSONRPCNotification) {
+	fields := notification.Params.AdditionalFields
+	token := fmt.Sprint(fields["progressToken"])
+	c.progressMu.Lock()
+	stream := c.progress[token]
+	c.progressMu.Unlock()
+	if stream == nil {
+		return
+	}
+	chunk := ToolChunk{}
+	for key, value := range fields {
+		switch key {
+		case "progressToken":
+		case "progress":
+			chunk.Progress, _ = value.(float64)
+		case "total":
+			chunk.Total, _ = value.(float64)
+		case "message":
+			chunk.Text, _ = value.(string)
+		default:
+			if chunk.Data == nil {
+				chunk.Data = make(map[string]any)
+			}
+			chunk.Data[key] = value
+		}
+	}
+	stream.push(chunk)
+}
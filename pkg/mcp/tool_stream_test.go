@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+func TestClient_CallToolStream(t *testing.T) {
+	server := mcpserver.NewMCPServer("test-http", "1.0.0")
+	server.AddTool(mcpgo.NewTool("read_file"), func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		token := req.Params.Meta.ProgressToken
+		for i, line := range []string{"line 1", "line 2"} {
+			err := mcpserver.ServerFromContext(ctx).SendNotificationToClient(ctx, methodNotificationProgress, map[string]any{
+				"progressToken": token,
+				"progress":      i + 1,
+				"total":         2,
+				"message":       line,
+				"offset":        i,
+			})
+			if err != nil {
+				return nil, err
+			}
+			// The server drops notifications still queued when the
+			// response is written, so give each one time to go out.
+			time.Sleep(20 * time.Millisecond)
+		}
+		return mcpgo.NewToolResultText("done"), nil
+	})
+	server.AddTool(mcpgo.NewTool("ping"), func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		return mcpgo.NewToolResultText("pong"), nil
+	})
+
+	httpServer := mcpserver.NewTestStreamableHTTPServer(server)
+	defer httpServer.Close()
+
+	client, err := NewClientWithStreamableHTTPProtocol(httpServer.URL, mcpgo.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		t.Fatalf("NewClientWithStreamableHTTPProtocol error: %v", err)
+	}
+	defer client.Close()
+
+	chunks, err := client.CallToolStream(context.Background(), "read_file", nil)
+	if err != nil {
+		t.Fatalf("CallToolStream error: %v", err)
+	}
+	var got []ToolChunk
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 2 progress chunks and a final one, got %+v", got)
+	}
+	if got[0].Text != "line 1" || got[1].Text != "line 2" || got[1].Progress != 2 || got[1].Total != 2 {
+		t.Fatalf("unexpected progress chunks %+v", got[:2])
+	}
+	if got[1].Data["offset"] != float64(1) {
+		t.Fatalf("expected structured data, got %+v", got[1].Data)
+	}
+	final := got[2]
+	if !final.Done || final.Err != nil || final.Result == nil || final.Result.IsError {
+		t.Fatalf("unexpected final chunk %+v", final)
+	}
+
+	chunks, err = client.CallToolStream(context.Background(), "ping", nil)
+	if err != nil {
+		t.Fatalf("CallToolStream error: %v", err)
+	}
+	got = nil
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	if len(got) != 1 || !got[0].Done || got[0].Result == nil {
+		t.Fatalf("expected a single final chunk, got %+v", got)
+	}
+	if stats := client.Stats(); stats.ToolCalls != 2 {
+		t.Fatalf("expected stream calls in stats, got %+v", stats)
+	}
+}
@@ -47,6 +47,13 @@ type TruncationStrategy interface {
 	Truncate(ctx context.Context, messages []ConversationMessage) ([]ConversationMessage, error)
 }
 
+// TruncatingConversation is implemented by conversation memories that
+// apply a TruncationStrategy, so callers trimming a prompt can reuse it.
+type TruncatingConversation interface {
+	// TruncationStrategy returns the configured strategy, or nil.
+	TruncationStrategy() TruncationStrategy
+}
+
 // WindowStrategy keeps only the last N messages.
 type WindowStrategy struct {
 	MaxMessages int
@@ -202,3 +202,8 @@ func (f *FileConversation) ListSessions() ([]string, error) {
 	sort.Strings(sessions)
 	return sessions, nil
 }
+
+// TruncationStrategy returns the strategy applied when loading messages.
+func (f *FileConversation) TruncationStrategy() TruncationStrategy {
+	return f.config.TruncationStrategy
+}
@@ -130,3 +130,8 @@ func (m *InMemoryConversation) MessageCount(sessionID string) int {
 	defer m.mu.RUnlock()
 	return len(m.sessions[sessionID])
 }
+
+// TruncationStrategy returns the strategy applied when loading messages.
+func (m *InMemoryConversation) TruncationStrategy() TruncationStrategy {
+	return m.config.TruncationStrategy
+}
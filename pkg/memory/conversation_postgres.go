@@ -322,3 +322,8 @@ func (p *PostgresConversation) queryMessages(ctx context.Context, query string,
 func (p *PostgresConversation) Close() error {
 	return p.db.Close()
 }
+
+// TruncationStrategy returns the strategy applied when loading messages.
+func (p *PostgresConversation) TruncationStrategy() TruncationStrategy {
+	return p.config.TruncationStrategy
+}
@@ -2,25 +2,74 @@ package memory
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
 )
 
-// FileStore persists entries as JSON lines in a file.
+// FileStore persists entries as JSON lines in a file. The file is
+// append-only; an in-memory index of the entries, rebuilt from the file when
+// the store is opened, serves Retrieve, Search and Get. Changes made to the
+// file by other processes after opening are not seen.
 type FileStore struct {
 	path string
+
+	mu      sync.RWMutex
+	loaded  bool
+	entries []fileEntry
+	df      map[string]int // entries containing each term
+}
+
+var _ Searcher = (*FileStore)(nil)
+
+// fileEntry is an indexed line of the file.
+type fileEntry struct {
+	raw   json.RawMessage
+	text  string
+	key   string
+	terms map[string]int
 }
 
-// NewFileStore creates a file-backed memory store.
+// NewFileStore creates a file-backed memory store. The index is built on
+// first use; use OpenFileStore to build it eagerly and report read errors.
 func NewFileStore(path string) *FileStore {
 	return &FileStore{path: path}
 }
 
+// OpenFileStore creates a file-backed memory store and indexes the entries
+// already in the file.
+func OpenFileStore(path string) (*FileStore, error) {
+	f := NewFileStore(path)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.loadLocked(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
 // Store appends a JSON-encoded entry to the file.
 func (f *FileStore) Store(_ context.Context, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.loadLocked(); err != nil {
+		return err
+	}
+
 	dir := filepath.Dir(f.path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
@@ -32,53 +81,259 @@ func (f *FileStore) Store(_ context.Context, data any) error {
 	}
 	defer file.Close()
 
-	enc := json.NewEncoder(file)
-	return enc.Encode(data)
+	if _, err := file.Write(append(raw, '\n')); err != nil {
+		return err
+	}
+	f.indexLocked(raw)
+	return nil
 }
 
 // Retrieve returns the most recent match from the file.
 // If query is nil, it returns the last stored entry.
 // If query is a func(any) bool, it returns the last matching entry.
-func (f *FileStore) Retrieve(_ context.Context, query any) (any, error) {
-	file, err := os.Open(f.path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
+// If query is a string, it returns the texts of the (up to five) entries
+// that best match it, as Search does.
+func (f *FileStore) Retrieve(ctx context.Context, query any) (any, error) {
+	var match func(any) bool
+	switch q := query.(type) {
+	case nil:
+		match = func(any) bool { return true }
+	case func(any) bool:
+		match = q
+	case string:
+		matches, err := f.Search(ctx, q, 5, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
 			return nil, ErrNotFound
 		}
+		texts := make([]string, len(matches))
+		for i, m := range matches {
+			texts[i] = m.Text
+		}
+		return texts, nil
+	default:
+		return nil, errors.New("memory: unsupported query type")
+	}
+
+	entries, err := f.snapshot()
+	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	for i := len(entries) - 1; i >= 0; i-- {
+		var entry any
+		if err := json.Unmarshal(entries[i].raw, &entry); err != nil {
+			return nil, err
+		}
+		if match(entry) {
+			return entry, nil
+		}
+	}
+	return nil, ErrNotFound
+}
 
-	var (
-		last any
-		hit  bool
-	)
+// Get returns the most recent entry whose "key" field (or, failing that,
+// "id" field) equals key. Entries that are not JSON objects have no key.
+func (f *FileStore) Get(_ context.Context, key string) (any, error) {
+	entries, err := f.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].key != key || key == "" {
+			continue
+		}
+		var entry any
+		if err := json.Unmarshal(entries[i].raw, &entry); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	}
+	return nil, ErrNotFound
+}
 
-	var match func(any) bool
-	if query == nil {
-		match = func(any) bool { return true }
-	} else if fn, ok := query.(func(any) bool); ok {
-		match = fn
-	} else {
-		return nil, errors.New("memory: unsupported query type")
+// Search implements Searcher with a TF-IDF scorer over the text of the
+// entries (the string itself, or the string values of a JSON object). Scores
+// are cosine similarities between 0 and 1; ties favour newer entries.
+func (f *FileStore) Search(_ context.Context, query string, limit int, minScore float32) ([]Match, error) {
+	f.mu.Lock()
+	if err := f.loadLocked(); err != nil {
+		f.mu.Unlock()
+		return nil, err
 	}
+	f.mu.Unlock()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	queryTerms := termFrequencies(query)
+	if len(queryTerms) == 0 || limit <= 0 {
+		return nil, nil
+	}
+	n := float64(len(f.entries))
+	idf := func(term string) float64 {
+		return math.Log(1 + n/float64(1+f.df[term]))
+	}
+
+	var queryNorm float64
+	for term, tf := range queryTerms {
+		w := float64(tf) * idf(term)
+		queryNorm += w * w
+	}
+
+	type scored struct {
+		index int
+		score float64
+	}
+	var hits []scored
+	for i, entry := range f.entries {
+		var dot, norm float64
+		for term, tf := range entry.terms {
+			w := float64(tf) * idf(term)
+			norm += w * w
+			if qtf, ok := queryTerms[term]; ok {
+				dot += w * float64(qtf) * idf(term)
+			}
+		}
+		if dot == 0 {
+			continue
+		}
+		score := dot / (math.Sqrt(norm) * math.Sqrt(queryNorm))
+		if float32(score) >= minScore {
+			hits = append(hits, scored{index: i, score: score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].index > hits[j].index
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	matches := make([]Match, len(hits))
+	for i, h := range hits {
+		matches[i] = Match{Text: f.entries[h.index].text, Score: float32(h.score)}
+	}
+	return matches, nil
+}
+
+// snapshot returns the indexed entries, loading them if needed.
+func (f *FileStore) snapshot() ([]fileEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.loadLocked(); err != nil {
+		return nil, err
+	}
+	return f.entries, nil
+}
+
+// loadLocked builds the index from the file once. Must be called under the
+// write lock.
+func (f *FileStore) loadLocked() error {
+	if f.loaded {
+		return nil
+	}
+	f.entries = nil
+	f.df = make(map[string]int)
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			f.loaded = true
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
 	for scanner.Scan() {
-		var entry any
-		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
-			return nil, err
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
 		}
-		if match(entry) {
-			last = entry
-			hit = true
+		if !json.Valid(raw) {
+			return fmt.Errorf("memory: invalid JSON on line %d of %s", line, f.path)
 		}
+		f.indexLocked(append(json.RawMessage(nil), raw...))
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return err
+	}
+	f.loaded = true
+	return nil
+}
+
+// indexLocked adds raw to the index. Must be called under the write lock.
+func (f *FileStore) indexLocked(raw json.RawMessage) {
+	var value any
+	_ = json.Unmarshal(raw, &value)
+	entry := fileEntry{raw: raw, text: entryText(value), key: entryKey(value)}
+	entry.terms = termFrequencies(entry.text)
+	for term := range entry.terms {
+		f.df[term]++
 	}
-	if !hit {
-		return nil, ErrNotFound
+	f.entries = append(f.entries, entry)
+}
+
+// entryText returns the searchable text of a decoded entry.
+func entryText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			if text := entryText(v[k]); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := entryText(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
+
+// entryKey returns the "key" (or "id") string field of an object entry.
+func entryKey(value any) string {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if key, ok := obj["key"].(string); ok {
+		return key
+	}
+	id, _ := obj["id"].(string)
+	return id
+}
+
+// termFrequencies lower-cases text and counts its letter/digit runs.
+func termFrequencies(text string) map[string]int {
+	terms := make(map[string]int)
+	for _, term := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		terms[term]++
 	}
-	return last, nil
+	return terms
 }
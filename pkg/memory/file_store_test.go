@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -60,3 +61,82 @@ func TestFileStoreUnsupportedQuery(t *testing.T) {
 		t.Fatal("expected error for unsupported query type")
 	}
 }
+
+func TestFileStoreSearch(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(filepath.Join(t.TempDir(), "memory.jsonl"))
+
+	_ = store.Store(ctx, "the user prefers Go for backend services")
+	_ = store.Store(ctx, map[string]any{"text": "favourite colour is blue", "n": 3})
+	_ = store.Store(ctx, "deploys run on Kubernetes")
+
+	matches, err := store.Search(ctx, "which colour?", 5, 0)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Text != "favourite colour is blue" {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+	if matches[0].Score <= 0 || matches[0].Score > 1 {
+		t.Fatalf("expected score in (0, 1], got %v", matches[0].Score)
+	}
+
+	if matches, _ := store.Search(ctx, "Go backend", 1, 0); len(matches) != 1 || matches[0].Text != "the user prefers Go for backend services" {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+	if matches, _ := store.Search(ctx, "colour", 5, 0.99); len(matches) != 0 {
+		t.Fatalf("expected minScore to filter, got %+v", matches)
+	}
+
+	got, err := store.Retrieve(ctx, "kubernetes")
+	if err != nil {
+		t.Fatalf("retrieve failed: %v", err)
+	}
+	if texts := got.([]string); len(texts) != 1 || texts[0] != "deploys run on Kubernetes" {
+		t.Fatalf("unexpected texts %v", texts)
+	}
+	if _, err := store.Retrieve(ctx, "nothing here"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileStoreGetAndReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	store := NewFileStore(path)
+
+	_ = store.Store(ctx, map[string]any{"key": "lang", "value": "es"})
+	_ = store.Store(ctx, map[string]any{"id": "tz", "value": "Europe/Madrid"})
+	_ = store.Store(ctx, map[string]any{"key": "lang", "value": "en"})
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	got, err := reopened.Get(ctx, "lang")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if got.(map[string]any)["value"] != "en" {
+		t.Fatalf("expected latest value, got %v", got)
+	}
+	if got, err := reopened.Get(ctx, "tz"); err != nil || got.(map[string]any)["value"] != "Europe/Madrid" {
+		t.Fatalf("expected id lookup, got %v, %v", got, err)
+	}
+	if _, err := reopened.Get(ctx, "missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if matches, _ := reopened.Search(ctx, "madrid", 5, 0); len(matches) != 1 {
+		t.Fatalf("expected rebuilt index to be searchable, got %+v", matches)
+	}
+}
+
+func TestOpenFileStoreInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	if err := os.WriteFile(path, []byte("\"ok\"\n{broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileStore(path); err == nil {
+		t.Fatal("expected error for invalid line")
+	}
+}
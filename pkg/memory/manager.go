@@ -3,20 +3,44 @@ package memory
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// NamespacePayloadKey is the payload field holding a VectorMemory namespace.
+const NamespacePayloadKey = "namespace"
+
 // VectorMemory implements the core.Memory interface using a vector store and embedder.
 type VectorMemory struct {
 	store      VectorStore
 	embedder   Embedder
 	collection string
+	namespace  string
+
+	// Retention policy (see WithRetention).
+	maxPoints  int
+	maxAge     time.Duration
+	pruneEvery int
+	writes     atomic.Int64
+	now        func() time.Time
+}
+
+// VectorMemoryOption configures a VectorMemory.
+type VectorMemoryOption func(*VectorMemory)
+
+// WithNamespace isolates a VectorMemory inside a shared collection: entries
+// are stored with ns in their payload and every search only returns entries
+// of ns. Use one namespace per tenant or agent.
+func WithNamespace(ns string) VectorMemoryOption {
+	return func(vm *VectorMemory) {
+		vm.namespace = ns
+	}
 }
 
 // NewVectorMemory creates a new VectorMemory instance.
-func NewVectorMemory(ctx context.Context, store VectorStore, embedder Embedder, collection string) (*VectorMemory, error) {
+func NewVectorMemory(ctx context.Context, store VectorStore, embedder Embedder, collection string, opts ...VectorMemoryOption) (*VectorMemory, error) {
 	// Ensure collection exists
 	// For Qdrant, typically 1536 for OpenAI, 768 for many OSS models, 1024 for others.
 	// We need to know the dimension.
@@ -28,14 +52,26 @@ func NewVectorMemory(ctx context.Context, store VectorStore, embedder Embedder,
 	// However, CreateCollection requires dimension.
 
 	// Let's just store the params.
-	return &VectorMemory{
+	vm := &VectorMemory{
 		store:      store,
 		embedder:   embedder,
 		collection: collection,
-	}, nil
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(vm)
+	}
+	return vm, nil
 }
 
-// Initialize ensures the collection exists with the correct dimension.
+// Namespace returns the namespace set with WithNamespace, if any.
+func (vm *VectorMemory) Namespace() string {
+	return vm.namespace
+}
+
+// Initialize ensures the collection exists with the correct dimension and,
+// for a namespaced memory, that the namespace payload field is indexed when
+// the store supports it (see PayloadIndexer).
 func (vm *VectorMemory) Initialize(ctx context.Context) error {
 	// Embed a sample text to get dimensions
 	vec, err := vm.embedder.Embed(ctx, "hello")
@@ -65,16 +101,33 @@ func (vm *VectorMemory) Initialize(ctx context.Context) error {
 		// Actually, let's treat it as non-fatal if "already exists" but we can't distinguish easily without parsing error string which is brittle.
 		// Plan: Try to search. If search works, collection exists.
 		_, searchErr := vm.store.Search(ctx, vm.collection, vec, 1, 0.0)
-		if searchErr == nil {
-			return nil // Collection exists
+		if searchErr != nil {
+			return err // Return the creation error
 		}
-		return err // Return the creation error
+		// Collection exists
+	}
+	return vm.ensureNamespaceIndex(ctx)
+}
+
+func (vm *VectorMemory) ensureNamespaceIndex(ctx context.Context) error {
+	if vm.namespace == "" {
+		return nil
+	}
+	indexer, ok := vm.store.(PayloadIndexer)
+	if !ok {
+		return nil
+	}
+	if err := indexer.CreatePayloadIndex(ctx, vm.collection, NamespacePayloadKey); err != nil {
+		return fmt.Errorf("failed to index namespace field: %w", err)
 	}
 	return nil
 }
 
 // Store saves data into the vector memory.
 // Expects data to be a string or a struct with "Text" field.
+// With WithRetention it prunes the memory after the first write and then
+// every DefaultPruneEvery writes; a pruning error is returned even though
+// the entry was stored.
 func (vm *VectorMemory) Store(ctx context.Context, data any) error {
 	text, ok := data.(string)
 	if !ok {
@@ -87,20 +140,29 @@ func (vm *VectorMemory) Store(ctx context.Context, data any) error {
 	}
 
 	id := uuid.New().String()
+	now := vm.now().Unix()
 	point := Point{
 		ID:     id,
 		Vector: vector,
 		Payload: map[string]interface{}{
 			"text":      text,
-			"timestamp": time.Now().Unix(),
+			"timestamp": now,
 		},
-		Timestamp: time.Now().Unix(),
+		Timestamp: now,
+	}
+	if vm.namespace != "" {
+		point.Payload[NamespacePayloadKey] = vm.namespace
 	}
 
 	if err := vm.store.Upsert(ctx, vm.collection, []Point{point}); err != nil {
 		return fmt.Errorf("failed to store point: %w", err)
 	}
 
+	if vm.hasRetention() && vm.pruneDue() {
+		if _, err := vm.Prune(ctx); err != nil {
+			return fmt.Errorf("failed to prune memory: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -112,24 +174,56 @@ func (vm *VectorMemory) Retrieve(ctx context.Context, query any) (any, error) {
 		return nil, fmt.Errorf("VectorMemory currently only supports string queries")
 	}
 
-	vector, err := vm.embedder.Embed(ctx, text)
+	// Limit 5, threshold can be tuneable.
+	results, err := vm.Search(ctx, text, 5, 0.6)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return list of strings
+	var matches []string
+	for _, r := range results {
+		matches = append(matches, r.Text)
+	}
+
+	return matches, nil
+}
+
+// Search implements Searcher, returning up to limit stored texts whose
+// similarity to query is at least minScore. A namespaced memory filters in
+// the store when it implements FilteredVectorStore; otherwise entries of
+// other namespaces are dropped from the results, which may then hold fewer
+// than limit entries. With WithRetention, the returned entries get their
+// last_accessed payload field updated.
+func (vm *VectorMemory) Search(ctx context.Context, query string, limit int, minScore float32) ([]Match, error) {
+	vector, err := vm.embedder.Embed(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
 
-	// Limit 5, threshold can be tuneable.
-	results, err := vm.store.Search(ctx, vm.collection, vector, 5, 0.6)
+	var results []SearchResult
+	if filtered, ok := vm.store.(FilteredVectorStore); ok && vm.namespace != "" {
+		results, err = filtered.SearchWithFilter(ctx, vm.collection, vector, limit, minScore, vm.namespaceFilter())
+	} else {
+		results, err = vm.store.Search(ctx, vm.collection, vector, limit, minScore)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
 
-	// Return list of strings
-	var matches []string
+	matches := make([]Match, 0, len(results))
+	var ids []string
 	for _, r := range results {
+		if vm.namespace != "" && r.Point.Payload[NamespacePayloadKey] != vm.namespace {
+			continue
+		}
 		if val, ok := r.Point.Payload["text"].(string); ok {
-			matches = append(matches, val)
+			matches = append(matches, Match{Text: val, Score: r.Score})
+			ids = append(ids, r.ID)
 		}
 	}
-
+	if vm.hasRetention() {
+		vm.touch(ctx, ids)
+	}
 	return matches, nil
 }
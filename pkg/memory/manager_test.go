@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeEmbedder maps every text to the same vector so searches match all points.
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(context.Context, string) ([]float32, error) {
+	return []float32{1, 0}, nil
+}
+
+// fakeVectorStore keeps points in memory and scores them all at 1.
+type fakeVectorStore struct {
+	points  []Point
+	indexed []string
+}
+
+func (s *fakeVectorStore) Upsert(_ context.Context, _ string, points []Point) error {
+	s.points = append(s.points, points...)
+	return nil
+}
+
+func (s *fakeVectorStore) Search(_ context.Context, _ string, _ []float32, limit int, _ float32) ([]SearchResult, error) {
+	return s.search(limit, nil), nil
+}
+
+func (s *fakeVectorStore) CreateCollection(context.Context, string, uint64) error { return nil }
+
+func (s *fakeVectorStore) search(limit int, filter map[string]string) []SearchResult {
+	var out []SearchResult
+	for _, p := range s.points {
+		match := true
+		for k, v := range filter {
+			if p.Payload[k] != v {
+				match = false
+			}
+		}
+		if match && len(out) < limit {
+			out = append(out, SearchResult{ID: p.ID, Score: 1, Point: p})
+		}
+	}
+	return out
+}
+
+// fakeFilteredStore adds filtering and payload indexes to fakeVectorStore.
+type fakeFilteredStore struct {
+	fakeVectorStore
+	filtered int
+}
+
+func (s *fakeFilteredStore) SearchWithFilter(_ context.Context, _ string, _ []float32, limit int, _ float32, filter map[string]string) ([]SearchResult, error) {
+	s.filtered++
+	return s.search(limit, filter), nil
+}
+
+func (s *fakeFilteredStore) CreatePayloadIndex(_ context.Context, _ string, field string) error {
+	s.indexed = append(s.indexed, field)
+	return nil
+}
+
+func TestVectorMemory_NamespaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range map[string]VectorStore{
+		"filtered": &fakeFilteredStore{},
+		"plain":    &fakeVectorStore{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			memA, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared", WithNamespace("agent-a"))
+			memB, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared", WithNamespace("agent-b"))
+			if err := memA.Initialize(ctx); err != nil {
+				t.Fatalf("Initialize error: %v", err)
+			}
+			_ = memA.Store(ctx, "secret of a")
+			_ = memB.Store(ctx, "secret of b")
+
+			matches, err := memA.Search(ctx, "secret", 5, 0)
+			if err != nil {
+				t.Fatalf("Search error: %v", err)
+			}
+			if len(matches) != 1 || matches[0].Text != "secret of a" {
+				t.Fatalf("expected only agent-a memories, got %+v", matches)
+			}
+			got, _ := memB.Retrieve(ctx, "secret")
+			if texts := got.([]string); len(texts) != 1 || texts[0] != "secret of b" {
+				t.Fatalf("expected only agent-b memories, got %v", texts)
+			}
+		})
+	}
+}
+
+func TestVectorMemory_InitializeIndexesNamespace(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeFilteredStore{}
+
+	plain, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared")
+	if err := plain.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if len(store.indexed) != 0 {
+		t.Fatalf("expected no index without namespace, got %v", store.indexed)
+	}
+
+	namespaced, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared", WithNamespace("tenant"))
+	if err := namespaced.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if len(store.indexed) != 1 || store.indexed[0] != NamespacePayloadKey {
+		t.Fatalf("expected namespace index, got %v", store.indexed)
+	}
+	if _, err := namespaced.Search(ctx, "q", 1, 0); err != nil || store.filtered != 1 {
+		t.Fatalf("expected a filtered search, got %d (err %v)", store.filtered, err)
+	}
+}
@@ -4,6 +4,7 @@ package qdrant
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/jllopis/kairos/pkg/memory"
 	pb "github.com/qdrant/go-client/qdrant"
@@ -11,7 +12,8 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// Store implements memory.VectorStore using Qdrant gRPC APIs.
+// Store implements memory.VectorStore, memory.FilteredVectorStore,
+// memory.PayloadIndexer and memory.RetentionStore using Qdrant gRPC APIs.
 type Store struct {
 	client      pb.PointsClient
 	collections pb.CollectionsClient // Add collections client
@@ -53,22 +55,7 @@ func (s *Store) CreateCollection(ctx context.Context, name string, vectorSize ui
 func (s *Store) Upsert(ctx context.Context, collection string, points []memory.Point) error {
 	qPoints := make([]*pb.PointStruct, len(points))
 	for i, p := range points {
-		// Convert payload map[string]interface{} to map[string]*pb.Value
-		payload := make(map[string]*pb.Value)
-		for k, v := range p.Payload {
-			// This is a simplified conversion. Real implementation needs robust type checking.
-			switch val := v.(type) {
-			case string:
-				payload[k] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: val}}
-			case int:
-				payload[k] = &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: int64(val)}}
-			case int64:
-				payload[k] = &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: val}}
-			case float64:
-				payload[k] = &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: val}}
-				// Add more types as needed
-			}
-		}
+		payload := toQdrantPayload(p.Payload)
 
 		qPoints[i] = &pb.PointStruct{
 			Id: &pb.PointId{
@@ -94,11 +81,49 @@ func (s *Store) Upsert(ctx context.Context, collection string, points []memory.P
 	return nil
 }
 
+// CreatePayloadIndex creates a keyword index on a payload field, which
+// Qdrant uses to filter searches efficiently. Qdrant accepts re-creating an
+// existing index.
+func (s *Store) CreatePayloadIndex(ctx context.Context, collection, field string) error {
+	_, err := s.client.CreateFieldIndex(ctx, &pb.CreateFieldIndexCollection{
+		CollectionName: collection,
+		FieldName:      field,
+		FieldType:      pb.FieldType_FieldTypeKeyword.Enum(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create payload index: %w", err)
+	}
+	return nil
+}
+
 // Search performs a similarity search over a collection.
 func (s *Store) Search(ctx context.Context, collection string, vector []float32, limit int, scoreThreshold float32) ([]memory.SearchResult, error) {
+	return s.search(ctx, collection, vector, limit, scoreThreshold, nil)
+}
+
+// SearchWithFilter performs a similarity search restricted to points whose
+// payload fields equal every value in filter.
+func (s *Store) SearchWithFilter(ctx context.Context, collection string, vector []float32, limit int, scoreThreshold float32, filter map[string]string) ([]memory.SearchResult, error) {
+	return s.search(ctx, collection, vector, limit, scoreThreshold, keywordFilter(filter))
+}
+
+// keywordFilter builds a Qdrant filter requiring each field to match its value.
+func keywordFilter(fields map[string]string) *pb.Filter {
+	if len(fields) == 0 {
+		return nil
+	}
+	filter := &pb.Filter{}
+	for field, value := range fields {
+		filter.Must = append(filter.Must, pb.NewMatchKeyword(field, value))
+	}
+	return filter
+}
+
+func (s *Store) search(ctx context.Context, collection string, vector []float32, limit int, scoreThreshold float32, filter *pb.Filter) ([]memory.SearchResult, error) {
 	resp, err := s.client.Search(ctx, &pb.SearchPoints{
 		CollectionName: collection,
 		Vector:         vector,
+		Filter:         filter,
 		Limit:          uint64(limit),
 		ScoreThreshold: &scoreThreshold,
 		WithPayload:    &pb.WithPayloadSelector{SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true}},
@@ -109,29 +134,8 @@ func (s *Store) Search(ctx context.Context, collection string, vector []float32,
 
 	results := make([]memory.SearchResult, len(resp.Result))
 	for i, r := range resp.Result {
-		// Convert back payload
-		payload := make(map[string]interface{})
-		for k, v := range r.Payload {
-			if v.GetKind() != nil {
-				// Simplified payload extraction
-				switch knd := v.GetKind().(type) {
-				case *pb.Value_StringValue:
-					payload[k] = knd.StringValue
-				case *pb.Value_IntegerValue:
-					payload[k] = knd.IntegerValue
-				case *pb.Value_DoubleValue:
-					payload[k] = knd.DoubleValue
-				}
-			}
-		}
-
-		var id string
-		if r.Id.GetUuid() != "" {
-			id = r.Id.GetUuid()
-		} else {
-			// Handle uint64 IDs if necessary or just convert to string
-			id = fmt.Sprintf("%d", r.Id.GetNum())
-		}
+		payload := fromQdrantPayload(r.Payload)
+		id := pointIDString(r.Id)
 
 		results[i] = memory.SearchResult{
 			ID:    id,
@@ -146,3 +150,131 @@ func (s *Store) Search(ctx context.Context, collection string, vector []float32,
 
 	return results, nil
 }
+
+// ListPoints returns the points of a collection matching filter, without
+// their vectors, paging through Qdrant's scroll API.
+func (s *Store) ListPoints(ctx context.Context, collection string, filter map[string]string) ([]memory.Point, error) {
+	const pageSize = 256
+	limit := uint32(pageSize)
+	req := &pb.ScrollPoints{
+		CollectionName: collection,
+		Filter:         keywordFilter(filter),
+		Limit:          &limit,
+		WithPayload:    pb.NewWithPayload(true),
+		WithVectors:    pb.NewWithVectors(false),
+	}
+
+	var points []memory.Point
+	for {
+		resp, err := s.client.Scroll(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll points: %w", err)
+		}
+		for _, r := range resp.Result {
+			payload := fromQdrantPayload(r.Payload)
+			point := memory.Point{ID: pointIDString(r.Id), Payload: payload}
+			if ts, ok := payload["timestamp"].(int64); ok {
+				point.Timestamp = ts
+			}
+			points = append(points, point)
+		}
+		if resp.NextPageOffset == nil {
+			return points, nil
+		}
+		req.Offset = resp.NextPageOffset
+	}
+}
+
+// DeletePoints removes points from a collection by ID.
+func (s *Store) DeletePoints(ctx context.Context, collection string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.client.Delete(ctx, &pb.DeletePoints{
+		CollectionName: collection,
+		Points:         pb.NewPointsSelectorIDs(qdrantPointIDs(ids)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete points: %w", err)
+	}
+	return nil
+}
+
+// SetPayload merges payload into the payload of the given points.
+func (s *Store) SetPayload(ctx context.Context, collection string, ids []string, payload map[string]interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.client.SetPayload(ctx, &pb.SetPayloadPoints{
+		CollectionName: collection,
+		Payload:        toQdrantPayload(payload),
+		PointsSelector: pb.NewPointsSelectorIDs(qdrantPointIDs(ids)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set payload: %w", err)
+	}
+	return nil
+}
+
+// toQdrantPayload converts a payload map into Qdrant values. This is a
+// simplified conversion: only strings, integers and floats are kept.
+func toQdrantPayload(in map[string]interface{}) map[string]*pb.Value {
+	payload := make(map[string]*pb.Value)
+	for k, v := range in {
+		switch val := v.(type) {
+		case string:
+			payload[k] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: val}}
+		case int:
+			payload[k] = &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: int64(val)}}
+		case int64:
+			payload[k] = &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: val}}
+		case float64:
+			payload[k] = &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: val}}
+			// Add more types as needed
+		}
+	}
+	return payload
+}
+
+// fromQdrantPayload converts Qdrant values back into a payload map.
+func fromQdrantPayload(in map[string]*pb.Value) map[string]interface{} {
+	payload := make(map[string]interface{})
+	for k, v := range in {
+		switch knd := v.GetKind().(type) {
+		case *pb.Value_StringValue:
+			payload[k] = knd.StringValue
+		case *pb.Value_IntegerValue:
+			payload[k] = knd.IntegerValue
+		case *pb.Value_DoubleValue:
+			payload[k] = knd.DoubleValue
+		}
+	}
+	return payload
+}
+
+// pointIDString returns a UUID point ID, or a numeric one as a string.
+func pointIDString(id *pb.PointId) string {
+	if id.GetUuid() != "" {
+		return id.GetUuid()
+	}
+	return strconv.FormatUint(id.GetNum(), 10)
+}
+
+// qdrantPointIDs is the inverse of pointIDString.
+func qdrantPointIDs(ids []string) []*pb.PointId {
+	out := make([]*pb.PointId, len(ids))
+	for i, id := range ids {
+		if num, err := strconv.ParseUint(id, 10, 64); err == nil {
+			out[i] = pb.NewIDNum(num)
+		} else {
+			out[i] = pb.NewIDUUID(id)
+		}
+	}
+	return out
+}
+
+var (
+	_ memory.FilteredVectorStore = (*Store)(nil)
+	_ memory.PayloadIndexer      = (*Store)(nil)
+	_ memory.RetentionStore      = (*Store)(nil)
+)
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LastAccessedPayloadKey is the payload field holding when a VectorMemory
+// entry was last returned by a search, as Unix seconds.
+const LastAccessedPayloadKey = "last_accessed"
+
+// ErrRetentionUnsupported is returned by Prune when the vector store does not
+// implement RetentionStore.
+var ErrRetentionUnsupported = errors.New("memory: vector store does not support retention")
+
+// DefaultPruneEvery is how many Store calls a VectorMemory with a retention
+// policy handles per prune.
+const DefaultPruneEvery = 100
+
+// WithRetention bounds a VectorMemory: entries not accessed for maxAge are
+// removed, and beyond maxPoints the least recently accessed are removed
+// first. Zero disables either limit. Pruning runs on Prune and from Store,
+// after the first write and then every DefaultPruneEvery writes (see
+// WithPruneEvery), and needs a store implementing RetentionStore. A
+// namespaced memory only prunes its own namespace.
+func WithRetention(maxPoints int, maxAge time.Duration) VectorMemoryOption {
+	return func(vm *VectorMemory) {
+		vm.maxPoints = maxPoints
+		vm.maxAge = maxAge
+	}
+}
+
+// WithPruneEvery makes Store prune every n writes instead of every
+// DefaultPruneEvery. Between prunes the memory can exceed maxPoints by up to
+// n-1 entries.
+func WithPruneEvery(n int) VectorMemoryOption {
+	return func(vm *VectorMemory) {
+		if n > 0 {
+			vm.pruneEvery = n
+		}
+	}
+}
+
+func (vm *VectorMemory) hasRetention() bool {
+	return vm.maxPoints > 0 || vm.maxAge > 0
+}
+
+// pruneDue counts a write and reports whether Store should prune after it.
+func (vm *VectorMemory) pruneDue() bool {
+	every := int64(vm.pruneEvery)
+	if every <= 0 {
+		every = DefaultPruneEvery
+	}
+	return (vm.writes.Add(1)-1)%every == 0
+}
+
+// Prune removes the entries exceeding the retention policy and returns how
+// many were removed. It is a no-op without WithRetention.
+func (vm *VectorMemory) Prune(ctx context.Context) (int, error) {
+	if !vm.hasRetention() {
+		return 0, nil
+	}
+	store, ok := vm.store.(RetentionStore)
+	if !ok {
+		return 0, ErrRetentionUnsupported
+	}
+
+	points, err := store.ListPoints(ctx, vm.collection, vm.namespaceFilter())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list points: %w", err)
+	}
+
+	// Most recently used first; entries never searched count from creation.
+	sort.SliceStable(points, func(i, j int) bool {
+		return lastUsed(points[i]) > lastUsed(points[j])
+	})
+	keep := len(points)
+	if vm.maxPoints > 0 && keep > vm.maxPoints {
+		keep = vm.maxPoints
+	}
+	if vm.maxAge > 0 {
+		cutoff := vm.now().Add(-vm.maxAge).Unix()
+		for keep > 0 && lastUsed(points[keep-1]) < cutoff {
+			keep--
+		}
+	}
+	if keep == len(points) {
+		return 0, nil
+	}
+
+	ids := make([]string, 0, len(points)-keep)
+	for _, p := range points[keep:] {
+		ids = append(ids, p.ID)
+	}
+	if err := store.DeletePoints(ctx, vm.collection, ids); err != nil {
+		return 0, fmt.Errorf("failed to delete points: %w", err)
+	}
+	return len(ids), nil
+}
+
+// touch records that the given entries were just retrieved. It is best
+// effort: a failure only makes the entries look older to Prune.
+func (vm *VectorMemory) touch(ctx context.Context, ids []string) {
+	store, ok := vm.store.(RetentionStore)
+	if !ok || len(ids) == 0 {
+		return
+	}
+	_ = store.SetPayload(ctx, vm.collection, ids, map[string]interface{}{
+		LastAccessedPayloadKey: vm.now().Unix(),
+	})
+}
+
+func (vm *VectorMemory) namespaceFilter() map[string]string {
+	if vm.namespace == "" {
+		return nil
+	}
+	return map[string]string{NamespacePayloadKey: vm.namespace}
+}
+
+// lastUsed returns when p was last accessed, or stored if never accessed, as
+// Unix seconds.
+func lastUsed(p Point) int64 {
+	if ts, ok := payloadInt(p.Payload[LastAccessedPayloadKey]); ok {
+		return ts
+	}
+	if ts, ok := payloadInt(p.Payload["timestamp"]); ok {
+		return ts
+	}
+	return p.Timestamp
+}
+
+func payloadInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// retentionStore adds the RetentionStore operations to fakeFilteredStore.
+type retentionStore struct {
+	fakeFilteredStore
+}
+
+func (s *retentionStore) ListPoints(_ context.Context, _ string, filter map[string]string) ([]Point, error) {
+	var out []Point
+	for _, r := range s.search(len(s.points), filter) {
+		out = append(out, r.Point)
+	}
+	return out, nil
+}
+
+func (s *retentionStore) DeletePoints(_ context.Context, _ string, ids []string) error {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	kept := s.points[:0]
+	for _, p := range s.points {
+		if !drop[p.ID] {
+			kept = append(kept, p)
+		}
+	}
+	s.points = kept
+	return nil
+}
+
+func (s *retentionStore) SetPayload(_ context.Context, _ string, ids []string, payload map[string]interface{}) error {
+	for _, id := range ids {
+		for i := range s.points {
+			if s.points[i].ID == id {
+				for k, v := range payload {
+					s.points[i].Payload[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *retentionStore) texts() []string {
+	var out []string
+	for _, p := range s.points {
+		out = append(out, p.Payload["text"].(string))
+	}
+	return out
+}
+
+// testClock is a manually advanced clock.
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time          { return c.now }
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestVectorMemory_RetentionMaxPointsEvictsLeastRecentlyAccessed(t *testing.T) {
+	ctx := context.Background()
+	store := &retentionStore{}
+	vm, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared", WithRetention(2, 0), WithPruneEvery(1))
+	clock := &testClock{now: time.Unix(0, 0)}
+	vm.now = clock.Now
+
+	_ = vm.Store(ctx, "first")
+	clock.Advance(time.Minute)
+	_ = vm.Store(ctx, "second")
+	clock.Advance(time.Minute)
+	// Reading "first" makes "second" the least recently accessed.
+	if _, err := vm.Search(ctx, "first", 1, 0); err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := vm.Store(ctx, "third"); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+
+	got := store.texts()
+	if len(got) != 2 || got[0] != "first" || got[1] != "third" {
+		t.Fatalf("expected first and third to be kept, got %v", got)
+	}
+}
+
+// countingRetentionStore counts the ListPoints calls made by Prune.
+type countingRetentionStore struct {
+	retentionStore
+	lists int
+}
+
+func (s *countingRetentionStore) ListPoints(ctx context.Context, collection string, filter map[string]string) ([]Point, error) {
+	s.lists++
+	return s.retentionStore.ListPoints(ctx, collection, filter)
+}
+
+func TestVectorMemory_StorePrunesEveryNWrites(t *testing.T) {
+	ctx := context.Background()
+	store := &countingRetentionStore{}
+	vm, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared", WithRetention(2, 0), WithPruneEvery(3))
+
+	for i := 0; i < 6; i++ {
+		if err := vm.Store(ctx, fmt.Sprintf("entry %d", i)); err != nil {
+			t.Fatalf("Store error: %v", err)
+		}
+	}
+	// Writes 1 and 4 prune; the others only store.
+	if store.lists != 2 {
+		t.Fatalf("expected 2 prunes for 6 writes, got %d", store.lists)
+	}
+	if got := store.texts(); len(got) != 4 {
+		t.Fatalf("expected the entries since the last prune to be kept, got %v", got)
+	}
+}
+
+func TestVectorMemory_PruneMaxAge(t *testing.T) {
+	ctx := context.Background()
+	store := &retentionStore{}
+	vm, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared",
+		WithNamespace("a"), WithRetention(0, 90*time.Second))
+	other, _ := NewVectorMemory(ctx, store, fakeEmbedder{}, "shared", WithNamespace("b"))
+	clock := &testClock{now: time.Unix(0, 0)}
+	vm.now = clock.Now
+	other.now = clock.Now
+
+	_ = other.Store(ctx, "other tenant")
+	_ = vm.Store(ctx, "old")
+	clock.Advance(time.Minute)
+	_ = vm.Store(ctx, "new") // both within maxAge
+
+	// One minute later "old" is 2m old and "new" 1m; the other tenant is
+	// not ours to prune.
+	clock.Advance(time.Minute)
+	pruned, err := vm.Prune(ctx)
+	if err != nil || pruned != 1 {
+		t.Fatalf("expected 1 pruned entry, got %d (err %v)", pruned, err)
+	}
+	got := store.texts()
+	if len(got) != 2 || got[0] != "other tenant" || got[1] != "new" {
+		t.Fatalf("unexpected entries after prune: %v", got)
+	}
+}
+
+func TestVectorMemory_PruneUnsupported(t *testing.T) {
+	ctx := context.Background()
+	vm, _ := NewVectorMemory(ctx, &fakeVectorStore{}, fakeEmbedder{}, "shared")
+	if n, err := vm.Prune(ctx); n != 0 || err != nil {
+		t.Fatalf("expected no-op without retention, got %d, %v", n, err)
+	}
+
+	vm, _ = NewVectorMemory(ctx, &fakeVectorStore{}, fakeEmbedder{}, "shared", WithRetention(1, 0))
+	if _, err := vm.Prune(ctx); !errors.Is(err, ErrRetentionUnsupported) {
+		t.Fatalf("expected ErrRetentionUnsupported, got %v", err)
+	}
+	if err := vm.Store(ctx, "entry"); !errors.Is(err, ErrRetentionUnsupported) {
+		t.Fatalf("expected Store to report the pruning error, got %v", err)
+	}
+}
@@ -32,3 +32,48 @@ type Embedder interface {
 	// Embed converts a text string into a vector.
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
+
+// Match is an entry returned by a Searcher together with its relevance score.
+type Match struct {
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+}
+
+// Searcher is implemented by memories that can rank their entries against a
+// query. The agent uses it to honour WithMemoryRetrieval.
+type Searcher interface {
+	// Search returns up to limit entries scoring at least minScore, most
+	// relevant first.
+	Search(ctx context.Context, query string, limit int, minScore float32) ([]Match, error)
+}
+
+// FilteredVectorStore is implemented by vector stores that can restrict a
+// search to points whose payload fields equal the given values.
+type FilteredVectorStore interface {
+	VectorStore
+	// SearchWithFilter is Search limited to points matching every
+	// field/value pair of filter.
+	SearchWithFilter(ctx context.Context, collection string, vector []float32, limit int, scoreThreshold float32, filter map[string]string) ([]SearchResult, error)
+}
+
+// PayloadIndexer is implemented by vector stores that can index a payload
+// field to make filtering on it efficient.
+type PayloadIndexer interface {
+	// CreatePayloadIndex creates a keyword index on field. It succeeds if
+	// the index already exists.
+	CreatePayloadIndex(ctx context.Context, collection, field string) error
+}
+
+// RetentionStore is implemented by vector stores that support the
+// maintenance operations needed by WithRetention.
+type RetentionStore interface {
+	VectorStore
+	// ListPoints returns the points of collection whose payload matches
+	// every field/value pair of filter (all points for an empty filter).
+	// Vectors may be omitted.
+	ListPoints(ctx context.Context, collection string, filter map[string]string) ([]Point, error)
+	// DeletePoints removes the points with the given IDs.
+	DeletePoints(ctx context.Context, collection string, ids []string) error
+	// SetPayload merges payload into the payload of the given points.
+	SetPayload(ctx context.Context, collection string, ids []string, payload map[string]interface{}) error
+}
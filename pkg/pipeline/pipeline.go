@@ -0,0 +1,172 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pipeline chains request-processing stages around an agent run.
+//
+// A typical pipeline guards the input, runs the agent, filters the output
+// and parses the final answer:
+//
+//	p := pipeline.New(
+//	    pipeline.InputGuard(guard),
+//	    pipeline.AgentStage(myAgent),
+//	    pipeline.OutputGuard(guard),
+//	    pipeline.JSONParser(),
+//	).WithEventEmitter(emitter)
+//
+//	out, err := p.Run(ctx, "user question")
+//
+// Each stage receives the output of the previous one. The pipeline stops at
+// the first stage that returns an error; guardrail rejections surface as
+// *BlockedError.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/core"
+)
+
+// Stage is a single step of a pipeline.
+type Stage interface {
+	// Name identifies the stage in events and errors.
+	Name() string
+	// Process transforms the input of the stage into its output.
+	Process(ctx context.Context, in any) (any, error)
+}
+
+// NewStage creates a Stage from a function.
+func NewStage(name string, fn func(ctx context.Context, in any) (any, error)) Stage {
+	return stageFunc{name: name, fn: fn}
+}
+
+type stageFunc struct {
+	name string
+	fn   func(ctx context.Context, in any) (any, error)
+}
+
+func (s stageFunc) Name() string { return s.name }
+
+func (s stageFunc) Process(ctx context.Context, in any) (any, error) {
+	return s.fn(ctx, in)
+}
+
+// BlockedError reports that a guard stage rejected the content.
+type BlockedError struct {
+	Stage       string
+	GuardrailID string
+	Reason      string
+}
+
+// Error implements error.
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("pipeline stage %q blocked by %s: %s", e.Stage, e.GuardrailID, e.Reason)
+}
+
+// StageError wraps an error returned by a stage.
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+// Error implements error.
+func (e *StageError) Error() string {
+	return fmt.Sprintf("pipeline stage %q: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the underlying stage error.
+func (e *StageError) Unwrap() error { return e.Err }
+
+// Pipeline runs stages sequentially, short-circuiting on the first error.
+type Pipeline struct {
+	name    string
+	stages  []Stage
+	emitter core.EventEmitter
+}
+
+// New creates a pipeline from the given stages. Nil stages are ignored.
+func New(stages ...Stage) *Pipeline {
+	p := &Pipeline{name: "pipeline", emitter: core.NoopEventEmitter{}}
+	for _, stage := range stages {
+		if stage != nil {
+			p.stages = append(p.stages, stage)
+		}
+	}
+	return p
+}
+
+// WithName sets the name reported as the event source.
+func (p *Pipeline) WithName(name string) *Pipeline {
+	if name != "" {
+		p.name = name
+	}
+	return p
+}
+
+// WithEventEmitter emits a started/completed/error event for every stage.
+func (p *Pipeline) WithEventEmitter(emitter core.EventEmitter) *Pipeline {
+	if emitter != nil {
+		p.emitter = emitter
+	}
+	return p
+}
+
+// Stages returns the stage names in execution order.
+func (p *Pipeline) Stages() []string {
+	names := make([]string, 0, len(p.stages))
+	for _, stage := range p.stages {
+		names = append(names, stage.Name())
+	}
+	return names
+}
+
+// Run feeds input through every stage and returns the last output.
+// Errors are wrapped in *StageError unless they are already a *BlockedError.
+func (p *Pipeline) Run(ctx context.Context, input any) (any, error) {
+	current := input
+	for i, stage := range p.stages {
+		if err := ctx.Err(); err != nil {
+			return nil, &StageError{Stage: stage.Name(), Err: err}
+		}
+		p.emit(ctx, core.EventAgentTaskStarted, stage, i, nil)
+		started := time.Now()
+		out, err := stage.Process(ctx, current)
+		if err != nil {
+			p.emit(ctx, core.EventAgentError, stage, i, map[string]any{
+				"error":       err.Error(),
+				"duration_ms": time.Since(started).Milliseconds(),
+			})
+			var blocked *BlockedError
+			if errors.As(err, &blocked) {
+				return nil, err
+			}
+			return nil, &StageError{Stage: stage.Name(), Err: err}
+		}
+		p.emit(ctx, core.EventAgentTaskCompleted, stage, i, map[string]any{
+			"duration_ms": time.Since(started).Milliseconds(),
+		})
+		current = out
+	}
+	return current, nil
+}
+
+func (p *Pipeline) emit(ctx context.Context, eventType core.EventType, stage Stage, index int, extra map[string]any) {
+	payload := map[string]any{
+		"pipeline":    p.name,
+		"stage":       stage.Name(),
+		"stage_index": index,
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	if runID, ok := core.RunID(ctx); ok {
+		payload["run_id"] = runID
+	}
+	taskID := ""
+	if task, ok := core.TaskFromContext(ctx); ok && task != nil {
+		taskID = task.ID
+	}
+	p.emitter.Emit(ctx, core.NewEvent(eventType, p.name, taskID, payload))
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jllopis/kairos/pkg/core"
+	"github.com/jllopis/kairos/pkg/guardrails"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// InputGuard checks the input with guardrails and blocks rejected content.
+func InputGuard(g *guardrails.Guardrails) Stage {
+	return NewStage("input_guard", func(ctx context.Context, in any) (any, error) {
+		text, err := asText("input_guard", in)
+		if err != nil {
+			return nil, err
+		}
+		if g == nil {
+			return text, nil
+		}
+		result := g.CheckInput(ctx, text)
+		if result.Blocked {
+			return nil, &BlockedError{Stage: "input_guard", GuardrailID: result.GuardrailID, Reason: result.Reason}
+		}
+		return text, nil
+	})
+}
+
+// AgentStage runs the agent with the stage input.
+func AgentStage(agent core.Agent) Stage {
+	return NewStage("agent", func(ctx context.Context, in any) (any, error) {
+		if agent == nil {
+			return nil, fmt.Errorf("agent is nil")
+		}
+		return agent.Run(ctx, in)
+	})
+}
+
+// OutputGuard filters the output with guardrails. Blocked outputs stop the
+// pipeline; redactions are applied to the returned text.
+func OutputGuard(g *guardrails.Guardrails) Stage {
+	return NewStage("output_guard", func(ctx context.Context, in any) (any, error) {
+		text, err := asText("output_guard", in)
+		if err != nil {
+			return nil, err
+		}
+		if g == nil {
+			return text, nil
+		}
+		result := g.FilterOutput(ctx, text)
+		if result.Blocked {
+			return nil, &BlockedError{Stage: "output_guard", GuardrailID: result.GuardrailID, Reason: result.Reason}
+		}
+		return result.Content, nil
+	})
+}
+
+// Parser converts the final text into a structured value.
+func Parser(name string, parse func(ctx context.Context, text string) (any, error)) Stage {
+	return NewStage(name, func(ctx context.Context, in any) (any, error) {
+		text, err := asText(name, in)
+		if err != nil {
+			return nil, err
+		}
+		return parse(ctx, text)
+	})
+}
+
+// JSONParser decodes the text as JSON into a generic value. Surrounding
+// prose and Markdown code fences are tolerated.
+func JSONParser() Stage {
+	return Parser("json_parser", func(_ context.Context, text string) (any, error) {
+		var out any
+		if err := json.Unmarshal(extractJSON(text), &out); err != nil {
+			return nil, fmt.Errorf("parse json output: %w", err)
+		}
+		return out, nil
+	})
+}
+
+// JSONInto decodes the text as JSON into values created by newTarget,
+// e.g. JSONInto(func() any { return &Answer{} }).
+func JSONInto(newTarget func() any) Stage {
+	return Parser("json_parser", func(_ context.Context, text string) (any, error) {
+		target := newTarget()
+		if err := json.Unmarshal(extractJSON(text), target); err != nil {
+			return nil, fmt.Errorf("parse json output: %w", err)
+		}
+		return target, nil
+	})
+}
+
+func asText(stage string, in any) (string, error) {
+	switch v := in.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case nil:
+		return "", fmt.Errorf("stage %q: nil input", stage)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// extractJSON returns the first JSON object or array in text, or the
+// trimmed text itself so that the decoder reports why it is not JSON.
+func extractJSON(text string) []byte {
+	if raw, err := llm.ExtractJSON(text); err == nil {
+		return raw
+	}
+	return []byte(strings.TrimSpace(text))
+}
@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GraphBuilder assembles a Graph programmatically, for example from a plan
+// generated at runtime. Errors are collected and reported by Build, which
+// applies the same validation as ParseJSON and ParseYAML.
+//
+//	graph, err := planner.NewGraphBuilder().
+//		SetID("sales").
+//		AddNode(planner.Node{ID: "intent", Type: "classify"}).
+//		AddNode(planner.Node{ID: "report", Type: "tool", Tool: "sales_report"}).
+//		AddEdge(planner.Edge{From: "intent", To: "report"}).
+//		SetStart("intent").
+//		Build()
+type GraphBuilder struct {
+	graph Graph
+	errs  []error
+}
+
+// NewGraphBuilder returns an empty builder.
+func NewGraphBuilder() *GraphBuilder {
+	return &GraphBuilder{graph: Graph{Nodes: make(map[string]Node)}}
+}
+
+// SetID sets the graph ID.
+func (b *GraphBuilder) SetID(id string) *GraphBuilder {
+	b.graph.ID = id
+	return b
+}
+
+// AddNode adds a node. The node ID is required and must be unique.
+func (b *GraphBuilder) AddNode(node Node) *GraphBuilder {
+	switch {
+	case node.ID == "":
+		b.errs = append(b.errs, fmt.Errorf("node id is required"))
+	case b.hasNode(node.ID):
+		b.errs = append(b.errs, fmt.Errorf("duplicate node %q", node.ID))
+	default:
+		b.graph.Nodes[node.ID] = node
+	}
+	return b
+}
+
+// AddEdge adds a transition between two nodes.
+func (b *GraphBuilder) AddEdge(edge Edge) *GraphBuilder {
+	b.graph.Edges = append(b.graph.Edges, edge)
+	return b
+}
+
+// SetStart sets the start node. Without it, the start is the only node
+// with no incoming edges.
+func (b *GraphBuilder) SetStart(id string) *GraphBuilder {
+	b.graph.Start = id
+	return b
+}
+
+// AllowCycles permits loops in the graph.
+func (b *GraphBuilder) AllowCycles() *GraphBuilder {
+	b.graph.AllowCycles = true
+	return b
+}
+
+// Build validates and returns the graph. The builder must not be reused
+// after Build.
+func (b *GraphBuilder) Build() (*Graph, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	graph := b.graph
+	if err := graph.Validate(); err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
+func (b *GraphBuilder) hasNode(id string) bool {
+	_, ok := b.graph.Nodes[id]
+	return ok
+}
@@ -0,0 +1,88 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphBuilderMatchesParsedGraph(t *testing.T) {
+	built, err := NewGraphBuilder().
+		SetID("sales").
+		AddNode(Node{ID: "intent", Type: "classify"}).
+		AddNode(Node{ID: "report", Type: "tool", Tool: "sales_report"}).
+		AddNode(Node{ID: "fallback", Type: "noop"}).
+		AddEdge(Edge{From: "intent", To: "report", When: `intent == "sales"`}).
+		AddEdge(Edge{From: "intent", To: "fallback"}).
+		SetStart("intent").
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	parsed, err := ParseJSON([]byte(`{
+  "id": "sales",
+  "start": "intent",
+  "nodes": {
+    "intent": {"type": "classify"},
+    "report": {"type": "tool", "tool": "sales_report"},
+    "fallback": {"type": "noop"}
+  },
+  "edges": [
+    {"from": "intent", "to": "report", "when": "intent == \"sales\""},
+    {"from": "intent", "to": "fallback"}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if !built.Equal(parsed) {
+		t.Fatalf("built graph differs from parsed graph: %v", Diff(built, parsed))
+	}
+}
+
+func TestGraphBuilderRejectsMalformedGraphs(t *testing.T) {
+	cases := map[string]*GraphBuilder{
+		"duplicate node": NewGraphBuilder().
+			AddNode(Node{ID: "a", Type: "noop"}).
+			AddNode(Node{ID: "a", Type: "noop"}),
+		"dangling edge": NewGraphBuilder().
+			AddNode(Node{ID: "a", Type: "noop"}).
+			AddEdge(Edge{From: "a", To: "missing"}),
+		"unknown start": NewGraphBuilder().
+			AddNode(Node{ID: "a", Type: "noop"}).
+			SetStart("missing"),
+		"cycle": NewGraphBuilder().
+			AddNode(Node{ID: "a", Type: "noop"}).
+			AddNode(Node{ID: "b", Type: "noop"}).
+			AddEdge(Edge{From: "a", To: "b"}).
+			AddEdge(Edge{From: "b", To: "a"}).
+			SetStart("a"),
+	}
+	for name, builder := range cases {
+		if _, err := builder.Build(); err == nil {
+			t.Fatalf("%s: expected build error", name)
+		}
+	}
+}
+
+func TestGraphBuilderAllowCycles(t *testing.T) {
+	builder := func() *GraphBuilder {
+		return NewGraphBuilder().
+			AddNode(Node{ID: "draft", Type: "noop"}).
+			AddNode(Node{ID: "review", Type: "noop"}).
+			AddEdge(Edge{From: "draft", To: "review"}).
+			AddEdge(Edge{From: "review", To: "draft", When: `review == "rejected"`}).
+			SetStart("draft")
+	}
+	_, err := builder().Build()
+	if err == nil || !strings.Contains(err.Error(), "draft -> review -> draft") {
+		t.Fatalf("expected cycle path in error, got %v", err)
+	}
+	graph, err := builder().AllowCycles().Build()
+	if err != nil {
+		t.Fatalf("build with cycles: %v", err)
+	}
+	if !graph.AllowCycles {
+		t.Fatal("expected AllowCycles to be set")
+	}
+}
@@ -36,3 +36,57 @@ func TestEvaluateConditionAdvanced(t *testing.T) {
 		}
 	}
 }
+
+func TestEvaluateWhen(t *testing.T) {
+	state := NewState()
+	state.Last = "done"
+	state.Outputs["intent"] = "sales_by_region"
+	state.Outputs["score"] = 3
+	state.Outputs["node1"] = map[string]any{
+		"status": "ok",
+		"tags":   []any{"eu", "retail"},
+	}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{`intent == "sales_by_region"`, true},
+		{`intent != 'sales_by_region'`, false},
+		{`intent in ["sales_by_region", "sales_by_product"]`, true},
+		{`intent not in ["sales_by_region"]`, false},
+		{`"eu" in node1.tags`, true},
+		{`"status" in node1`, true},
+		{`score == 3 && node1.status == "ok"`, true},
+		{`score == 4 or last == "done"`, true},
+		{`!(node1.status == "ok")`, false},
+		{`not missing.path`, true},
+		{`missing == null`, true},
+		{`node1.status`, true},
+	}
+
+	for _, tc := range cases {
+		got, err := evaluateWhen(tc.expr, state)
+		if err != nil {
+			t.Fatalf("when %q error: %v", tc.expr, err)
+		}
+		if got != tc.want {
+			t.Fatalf("when %q expected %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestCompileWhenRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		`intent ==`,
+		`intent = "x"`,
+		`(intent == "x"`,
+		`intent in [other]`,
+		`"unterminated`,
+		`len(intent) > 2`,
+	} {
+		if _, err := compileWhen(expr); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
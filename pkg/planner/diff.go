@@ -0,0 +1,208 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package planner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+)
+
+// ChangeKind classifies a structural difference between two graphs.
+type ChangeKind string
+
+const (
+	// ChangeAdded marks an element present only in the second graph.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved marks an element present only in the first graph.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified marks an element present in both graphs with different content.
+	ChangeModified ChangeKind = "changed"
+)
+
+// ChangeTarget identifies which part of the graph a change refers to.
+type ChangeTarget string
+
+const (
+	// TargetGraph refers to graph-level fields (id, start, allow_cycles).
+	TargetGraph ChangeTarget = "graph"
+	// TargetNode refers to a node.
+	TargetNode ChangeTarget = "node"
+	// TargetEdge refers to an edge.
+	TargetEdge ChangeTarget = "edge"
+)
+
+// GraphChange describes a single structural difference between two graphs.
+type GraphChange struct {
+	Kind   ChangeKind   `json:"kind"`
+	Target ChangeTarget `json:"target"`
+	// ID is the node ID, the edge key ("from->to") or the graph field name.
+	ID string `json:"id"`
+	// Field names the modified attribute for ChangeModified entries.
+	Field  string `json:"field,omitempty"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// String renders the change as a single human readable line.
+func (c GraphChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s %s", c.Target, c.ID)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s %s", c.Target, c.ID)
+	default:
+		return fmt.Sprintf("~ %s %s %s: %v -> %v", c.Target, c.ID, c.Field, formatChangeValue(c.Before), formatChangeValue(c.After))
+	}
+}
+
+// Equal reports whether both graphs are structurally equivalent.
+// Node inputs are compared by their JSON encoding, so graphs parsed from
+// YAML and JSON with the same content are considered equal.
+func (g *Graph) Equal(other *Graph) bool {
+	return len(Diff(g, other)) == 0
+}
+
+// Diff reports the nodes and edges added, removed or changed from a to b.
+// Changes are returned in a deterministic order: graph fields, then nodes
+// sorted by ID, then edges sorted by key.
+func Diff(a, b *Graph) []GraphChange {
+	if a == nil {
+		a = &Graph{}
+	}
+	if b == nil {
+		b = &Graph{}
+	}
+
+	var changes []GraphChange
+	if a.ID != b.ID {
+		changes = append(changes, GraphChange{Kind: ChangeModified, Target: TargetGraph, ID: "graph", Field: "id", Before: a.ID, After: b.ID})
+	}
+	if a.Start != b.Start {
+		changes = append(changes, GraphChange{Kind: ChangeModified, Target: TargetGraph, ID: "graph", Field: "start", Before: a.Start, After: b.Start})
+	}
+	if a.AllowCycles != b.AllowCycles {
+		changes = append(changes, GraphChange{Kind: ChangeModified, Target: TargetGraph, ID: "graph", Field: "allow_cycles", Before: a.AllowCycles, After: b.AllowCycles})
+	}
+
+	for _, id := range unionKeys(a.Nodes, b.Nodes) {
+		before, inA := a.Nodes[id]
+		after, inB := b.Nodes[id]
+		switch {
+		case !inA:
+			changes = append(changes, GraphChange{Kind: ChangeAdded, Target: TargetNode, ID: id, After: after})
+		case !inB:
+			changes = append(changes, GraphChange{Kind: ChangeRemoved, Target: TargetNode, ID: id, Before: before})
+		default:
+			changes = append(changes, diffNode(id, before, after)...)
+		}
+	}
+
+	edgesA := indexEdges(a.Edges)
+	edgesB := indexEdges(b.Edges)
+	for _, key := range unionKeys(edgesA, edgesB) {
+		before, inA := edgesA[key]
+		after, inB := edgesB[key]
+		switch {
+		case !inA:
+			changes = append(changes, GraphChange{Kind: ChangeAdded, Target: TargetEdge, ID: key, After: after})
+		case !inB:
+			changes = append(changes, GraphChange{Kind: ChangeRemoved, Target: TargetEdge, ID: key, Before: before})
+		case before.Condition != after.Condition:
+			changes = append(changes, GraphChange{Kind: ChangeModified, Target: TargetEdge, ID: key, Field: "condition", Before: before.Condition, After: after.Condition})
+		case before.When != after.When:
+			changes = append(changes, GraphChange{Kind: ChangeModified, Target: TargetEdge, ID: key, Field: "when", Before: before.When, After: after.When})
+		}
+	}
+	return changes
+}
+
+func diffNode(id string, before, after Node) []GraphChange {
+	var changes []GraphChange
+	modified := func(field string, from, to any) {
+		changes = append(changes, GraphChange{Kind: ChangeModified, Target: TargetNode, ID: id, Field: field, Before: from, After: to})
+	}
+	if before.Type != after.Type {
+		modified("type", before.Type, after.Type)
+	}
+	if before.Tool != after.Tool {
+		modified("tool", before.Tool, after.Tool)
+	}
+	if !equalValue(before.Input, after.Input) {
+		modified("input", before.Input, after.Input)
+	}
+	if !maps.Equal(before.Metadata, after.Metadata) && (len(before.Metadata) > 0 || len(after.Metadata) > 0) {
+		modified("metadata", before.Metadata, after.Metadata)
+	}
+	if !equalValue(before.Retry, after.Retry) {
+		modified("retry", before.Retry, after.Retry)
+	}
+	if before.Timeout != after.Timeout {
+		modified("timeout", before.Timeout, after.Timeout)
+	}
+	return changes
+}
+
+// indexEdges keys edges by "from->to". Parallel edges between the same pair
+// of nodes get an ordinal suffix ("from->to#1") in declaration order.
+func indexEdges(edges []Edge) map[string]Edge {
+	out := make(map[string]Edge, len(edges))
+	seen := make(map[string]int, len(edges))
+	for _, edge := range edges {
+		key := edge.From + "->" + edge.To
+		if n := seen[key]; n > 0 {
+			seen[key] = n + 1
+			key = fmt.Sprintf("%s#%d", key, n)
+		} else {
+			seen[key] = 1
+		}
+		out[key] = edge
+	}
+	return out
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func equalValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}
+	return bytes.Equal(left, right)
+}
+
+func formatChangeValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		if typed == "" {
+			return `""`
+		}
+		return typed
+	default:
+		if payload, err := json.Marshal(typed); err == nil {
+			return string(payload)
+		}
+		return fmt.Sprint(typed)
+	}
+}
@@ -0,0 +1,111 @@
+package planner
+
+import "testing"
+
+func TestGraphEqualAcrossFormats(t *testing.T) {
+	fromJSON, err := ParseJSON([]byte(`{
+  "id": "g",
+  "start": "n1",
+  "nodes": {
+    "n1": { "type": "tool", "tool": "search", "input": {"query": "x", "limit": 5} },
+    "n2": { "type": "noop" }
+  },
+  "edges": [ { "from": "n1", "to": "n2" } ]
+}`))
+	if err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	fromYAML, err := ParseYAML([]byte(`
+id: g
+start: n1
+nodes:
+  n1:
+    type: tool
+    tool: search
+    input:
+      limit: 5
+      query: x
+  n2:
+    type: noop
+edges:
+  - from: n1
+    to: n2
+`))
+	if err != nil {
+		t.Fatalf("parse yaml: %v", err)
+	}
+	if !fromJSON.Equal(fromYAML) {
+		t.Fatalf("expected graphs to be equal, diff: %v", Diff(fromJSON, fromYAML))
+	}
+}
+
+func TestDiffReportsChanges(t *testing.T) {
+	a := &Graph{
+		ID:    "g",
+		Start: "n1",
+		Nodes: map[string]Node{
+			"n1": {ID: "n1", Type: "noop"},
+			"n2": {ID: "n2", Type: "noop"},
+			"n3": {ID: "n3", Type: "noop"},
+		},
+		Edges: []Edge{
+			{From: "n1", To: "n2", Condition: "last==ok"},
+			{From: "n2", To: "n3"},
+		},
+	}
+	b := &Graph{
+		ID:    "g",
+		Start: "n1",
+		Nodes: map[string]Node{
+			"n1": {ID: "n1", Type: "tool", Tool: "search"},
+			"n2": {ID: "n2", Type: "noop"},
+			"n4": {ID: "n4", Type: "noop"},
+		},
+		Edges: []Edge{
+			{From: "n1", To: "n2", Condition: "default"},
+			{From: "n2", To: "n4"},
+		},
+	}
+
+	changes := Diff(a, b)
+	want := []struct {
+		kind   ChangeKind
+		target ChangeTarget
+		id     string
+		field  string
+	}{
+		{ChangeModified, TargetNode, "n1", "type"},
+		{ChangeModified, TargetNode, "n1", "tool"},
+		{ChangeRemoved, TargetNode, "n3", ""},
+		{ChangeAdded, TargetNode, "n4", ""},
+		{ChangeModified, TargetEdge, "n1->n2", "condition"},
+		{ChangeRemoved, TargetEdge, "n2->n3", ""},
+		{ChangeAdded, TargetEdge, "n2->n4", ""},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %v", len(want), len(changes), changes)
+	}
+	for i, w := range want {
+		got := changes[i]
+		if got.Kind != w.kind || got.Target != w.target || got.ID != w.id || got.Field != w.field {
+			t.Fatalf("change %d: expected %v %v %q %q, got %+v", i, w.kind, w.target, w.id, w.field, got)
+		}
+	}
+	if a.Equal(b) {
+		t.Fatalf("expected graphs to differ")
+	}
+}
+
+func TestDiffParallelEdges(t *testing.T) {
+	a := &Graph{Edges: []Edge{
+		{From: "a", To: "b", Condition: "last==x"},
+		{From: "a", To: "b", Condition: "default"},
+	}}
+	b := &Graph{Edges: []Edge{
+		{From: "a", To: "b", Condition: "last==x"},
+	}}
+	changes := Diff(a, b)
+	if len(changes) != 1 || changes[0].Kind != ChangeRemoved || changes[0].ID != "a->b#1" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+}
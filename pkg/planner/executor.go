@@ -2,46 +2,114 @@ package planner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	klog "github.com/jllopis/kairos/pkg/log"
+	"github.com/jllopis/kairos/pkg/resilience"
 	"github.com/jllopis/kairos/pkg/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+var logger = klog.For("planner")
+
 // Handler executes a node and can update state.
 type Handler func(ctx context.Context, node Node, state *State) (any, error)
 
-// State holds outputs produced during graph execution.
-type State struct {
-	Last    any
-	Outputs map[string]any
-}
+// DefaultMaxSteps is the number of nodes an Execute call may run when
+// WithMaxSteps is not set.
+const DefaultMaxSteps = 1000
 
-// NewState creates an initialized execution state.
-func NewState() *State {
-	return &State{Outputs: make(map[string]any)}
-}
+// maxStepsPathTail is how many trailing nodes of the path are included in
+// the max steps error message; the full path is in the error context.
+const maxStepsPathTail = 10
+
+// ErrMaxStepsExceeded is wrapped by the error returned when a run exceeds
+// the executor step limit.
+var ErrMaxStepsExceeded = errors.New("planner: max steps exceeded")
+
+// NodeTypeParallel is the built-in node type that runs the targets of its
+// outgoing edges concurrently and joins their outputs.
+const NodeTypeParallel = "parallel"
 
 // Executor runs a graph using node handlers.
 type Executor struct {
-	Handlers     map[string]Handler
-	HandlersByID map[string]Handler
-	AuditStore   AuditStore
-	AuditHook    func(ctx context.Context, event AuditEvent)
-	RunID        string
-	tracer       trace.Tracer
+	Handlers       map[string]Handler
+	HandlersByID   map[string]Handler
+	AuditStore     AuditStore
+	AuditHook      func(ctx context.Context, event AuditEvent)
+	RunID          string
+	maxConcurrency int
+	maxSteps       int
+	defaultRetry   *resilience.RetryConfig
+	defaultTimeout time.Duration
+	tracer         trace.Tracer
+	// auditMu serializes audit events emitted by parallel branches.
+	auditMu sync.Mutex
+}
+
+// ExecutorOption configures an Executor.
+type ExecutorOption func(*Executor)
+
+// WithMaxConcurrency bounds how many branches of a parallel node run at once
+// (0 = all branches at once).
+func WithMaxConcurrency(n int) ExecutorOption {
+	return func(e *Executor) {
+		if n >= 0 {
+			e.maxConcurrency = n
+		}
+	}
+}
+
+// WithMaxSteps bounds how many nodes a single Execute call may run, so
+// graphs with loops cannot run forever (default 1000).
+func WithMaxSteps(n int) ExecutorOption {
+	return func(e *Executor) {
+		if n > 0 {
+			e.maxSteps = n
+		}
+	}
+}
+
+// WithDefaultRetry retries every node handler with cfg unless the node
+// declares its own retry policy, which is applied on top of cfg.
+func WithDefaultRetry(cfg resilience.RetryConfig) ExecutorOption {
+	return func(e *Executor) {
+		e.defaultRetry = &cfg
+	}
+}
+
+// WithDefaultTimeout bounds each handler attempt of nodes that do not
+// declare a timeout (0 = no timeout).
+func WithDefaultTimeout(d time.Duration) ExecutorOption {
+	return func(e *Executor) {
+		if d >= 0 {
+			e.defaultTimeout = d
+		}
+	}
 }
 
 // NewExecutor creates an executor with provided handlers.
-func NewExecutor(handlers map[string]Handler) *Executor {
-	return &Executor{
+func NewExecutor(handlers map[string]Handler, opts ...ExecutorOption) *Executor {
+	e := &Executor{
 		Handlers: handlers,
 		tracer:   otel.Tracer("kairos/planner"),
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+	return e
 }
 
 // AuditEvent captures node execution details for observability.
@@ -84,100 +152,327 @@ func (e *Executor) Execute(ctx context.Context, graph *Graph, state *State) (*St
 		return nil, err
 	}
 
-	visited := make(map[string]bool)
+	steps := &stepGuard{graphID: graph.ID, limit: e.stepLimit()}
 	currentID := startID
 	for currentID != "" {
-		if visited[currentID] {
-			return nil, fmt.Errorf("cycle detected at node %q", currentID)
+		if err := steps.visit(currentID); err != nil {
+			return nil, err
 		}
-		visited[currentID] = true
 
 		node, ok := graph.Nodes[currentID]
 		if !ok {
 			return nil, fmt.Errorf("node %q not found", currentID)
 		}
 
-		handler := e.Handlers[node.Type]
-		if e.HandlersByID != nil {
-			if byID, ok := e.HandlersByID[node.ID]; ok && byID != nil {
-				handler = byID
+		if node.Type == NodeTypeParallel && e.handlerFor(node) == nil {
+			join, err := e.executeParallel(ctx, execCtx, graph, node, state, steps)
+			if err != nil {
+				return nil, err
 			}
-		}
-		if handler == nil {
-			return nil, fmt.Errorf("no handler for node type %q", node.Type)
+			if join == "" {
+				break
+			}
+			currentID = join
+			continue
 		}
 
-		started := time.Now().UTC()
-		if err := e.emitAudit(ctx, AuditEvent{
-			GraphID:   graph.ID,
-			RunID:     e.RunID,
-			NodeID:    node.ID,
-			NodeType:  node.Type,
-			Status:    "started",
-			StartedAt: started,
-		}); err != nil {
+		output, attempts, err := e.executeNode(ctx, execCtx, graph, node, state)
+		if err != nil {
 			return nil, err
 		}
+		state.recordAttempts(node.ID, attempts)
+		state.setOutput(node.ID, output, node.ID)
+		state.Last = output
 
-		nodeCtx, span := e.tracer.Start(execCtx, "Planner.Node",
-			trace.WithAttributes(
-				attribute.String("node.id", node.ID),
-				attribute.String("node.type", node.Type),
-			),
-		)
-		span.SetAttributes(telemetry.PlannerNodeAttributes(node.ID, node.Type, "started", graph.ID, e.RunID)...)
-		if node.Input != nil {
-			span.SetAttributes(telemetry.PlannerNodeIO(fmt.Sprint(node.Input), "", 200)...)
-		}
-		output, err := handler(nodeCtx, node, state)
+		next, err := selectNextNode(currentID, adjacency[currentID], graph, state)
 		if err != nil {
-			span.RecordError(err)
-			span.SetAttributes(telemetry.PlannerNodeAttributes(node.ID, node.Type, "failed", graph.ID, e.RunID)...)
-			span.SetAttributes(telemetry.PlannerNodeIO("", fmt.Sprint(output), 200)...)
-			span.End()
-			if auditErr := e.emitAudit(ctx, AuditEvent{
-				GraphID:    graph.ID,
-				RunID:      e.RunID,
-				NodeID:     node.ID,
-				NodeType:   node.Type,
-				Status:     "failed",
-				Error:      err.Error(),
-				StartedAt:  started,
-				FinishedAt: time.Now().UTC(),
-			}); auditErr != nil {
-				return nil, auditErr
-			}
-			return nil, fmt.Errorf("node %q failed: %w", node.ID, err)
+			return nil, err
 		}
-		span.SetAttributes(telemetry.PlannerNodeAttributes(node.ID, node.Type, "completed", graph.ID, e.RunID)...)
+		if next == "" {
+			break
+		}
+		currentID = next
+	}
+
+	return state, nil
+}
+
+func (e *Executor) stepLimit() int {
+	if e.maxSteps > 0 {
+		return e.maxSteps
+	}
+	return DefaultMaxSteps
+}
+
+// stepGuard counts executed nodes and records the path for debugging.
+type stepGuard struct {
+	graphID string
+	limit   int
+	path    []string
+}
+
+func (g *stepGuard) visit(id string) error {
+	g.path = append(g.path, id)
+	if len(g.path) <= g.limit {
+		return nil
+	}
+	tail := g.path
+	if len(tail) > maxStepsPathTail {
+		tail = tail[len(tail)-maxStepsPathTail:]
+	}
+	cause := fmt.Errorf("%w: %d steps, last path %s", ErrMaxStepsExceeded, g.limit, strings.Join(tail, " -> "))
+	return kerrors.New(kerrors.CodeInternal, "max steps exceeded", cause).
+		WithContext("graph_id", g.graphID).
+		WithContext("max_steps", g.limit).
+		WithContext("path", slices.Clone(g.path))
+}
+
+func (e *Executor) handlerFor(node Node) Handler {
+	if e.HandlersByID != nil {
+		if byID, ok := e.HandlersByID[node.ID]; ok && byID != nil {
+			return byID
+		}
+	}
+	return e.Handlers[node.Type]
+}
+
+// executeNode runs a single node handler with tracing and audit events,
+// applying the node retry and timeout policy. It returns the output and the
+// number of handler attempts.
+func (e *Executor) executeNode(ctx, execCtx context.Context, graph *Graph, node Node, state *State) (any, int, error) {
+	handler := e.handlerFor(node)
+	if handler == nil {
+		return nil, 0, fmt.Errorf("no handler for node type %q", node.Type)
+	}
+
+	started := time.Now().UTC()
+	if err := e.emitAudit(ctx, AuditEvent{
+		GraphID:   graph.ID,
+		RunID:     e.RunID,
+		NodeID:    node.ID,
+		NodeType:  node.Type,
+		Status:    "started",
+		StartedAt: started,
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	nodeCtx, span := e.tracer.Start(execCtx, "Planner.Node",
+		trace.WithAttributes(
+			attribute.String("node.id", node.ID),
+			attribute.String("node.type", node.Type),
+		),
+	)
+	span.SetAttributes(telemetry.PlannerNodeAttributes(node.ID, node.Type, "started", graph.ID, e.RunID)...)
+	if node.Input != nil {
+		span.SetAttributes(telemetry.PlannerNodeIO(fmt.Sprint(node.Input), "", 200)...)
+	}
+	state.node = node.ID
+	output, attempts, err := e.invoke(nodeCtx, handler, node, state)
+	state.node = ""
+	span.SetAttributes(attribute.Int("node.attempts", attempts))
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttributes(telemetry.PlannerNodeAttributes(node.ID, node.Type, "failed", graph.ID, e.RunID)...)
 		span.SetAttributes(telemetry.PlannerNodeIO("", fmt.Sprint(output), 200)...)
 		span.End()
-		if err := e.emitAudit(ctx, AuditEvent{
+		if auditErr := e.emitAudit(ctx, AuditEvent{
 			GraphID:    graph.ID,
 			RunID:      e.RunID,
 			NodeID:     node.ID,
 			NodeType:   node.Type,
-			Status:     "completed",
-			Output:     output,
+			Status:     "failed",
+			Error:      err.Error(),
 			StartedAt:  started,
 			FinishedAt: time.Now().UTC(),
-		}); err != nil {
-			return nil, err
+		}); auditErr != nil {
+			return nil, attempts, auditErr
 		}
-		state.Outputs[node.ID] = output
-		state.Last = output
+		return nil, attempts, fmt.Errorf("node %q failed: %w", node.ID, err)
+	}
+	span.SetAttributes(telemetry.PlannerNodeAttributes(node.ID, node.Type, "completed", graph.ID, e.RunID)...)
+	span.SetAttributes(telemetry.PlannerNodeIO("", fmt.Sprint(output), 200)...)
+	span.End()
+	if err := e.emitAudit(ctx, AuditEvent{
+		GraphID:    graph.ID,
+		RunID:      e.RunID,
+		NodeID:     node.ID,
+		NodeType:   node.Type,
+		Status:     "completed",
+		Output:     output,
+		StartedAt:  started,
+		FinishedAt: time.Now().UTC(),
+	}); err != nil {
+		return nil, attempts, err
+	}
+	return output, attempts, nil
+}
 
-		next, err := selectNextNode(currentID, adjacency[currentID], graph, state)
-		if err != nil {
-			return nil, err
+// invoke calls handler under the node retry and timeout policy and reports
+// how many attempts were made.
+func (e *Executor) invoke(ctx context.Context, handler Handler, node Node, state *State) (any, int, error) {
+	retry, timeout := e.policyFor(node)
+	attempts := 0
+	call := func() (any, error) {
+		attempts++
+		if timeout <= 0 {
+			return handler(ctx, node, state)
 		}
-		if next == "" {
-			break
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return resilience.WithTimeoutResult(attemptCtx, resilience.TimeoutConfig{Duration: timeout}, func() (any, error) {
+			return handler(attemptCtx, node, state)
+		})
+	}
+	if retry == nil {
+		output, err := call()
+		return output, attempts, err
+	}
+	output, err := retry.DoWithResult(ctx, call)
+	return output, attempts, err
+}
+
+// policyFor resolves the retry and timeout for node: node settings
+// override the executor defaults. Durations were checked by Validate.
+func (e *Executor) policyFor(node Node) (*resilience.RetryConfig, time.Duration) {
+	timeout := e.defaultTimeout
+	if node.Timeout != "" {
+		if d, err := time.ParseDuration(node.Timeout); err == nil {
+			timeout = d
 		}
-		currentID = next
 	}
+	retry := e.defaultRetry
+	if node.Retry != nil {
+		cfg := resilience.DefaultRetryConfig()
+		if e.defaultRetry != nil {
+			cfg = *e.defaultRetry
+		}
+		if node.Retry.Max > 0 {
+			cfg.MaxAttempts = node.Retry.Max
+		}
+		if node.Retry.Backoff != "" {
+			if d, err := time.ParseDuration(node.Retry.Backoff); err == nil {
+				cfg.InitialDelay = d
+			}
+		}
+		retry = &cfg
+	}
+	return retry, timeout
+}
 
-	return state, nil
+// executeParallel runs every target of node's outgoing edges concurrently.
+// Branches are single nodes that must converge on the same join node (or
+// end the graph). Each branch sees a snapshot of state; their outputs are
+// stored in state.Outputs under the branch IDs, and under node.ID as a map
+// keyed by branch ID. Values a branch writes with SetOutput are merged back
+// in branch order. The first branch error cancels the others and is
+// returned. It returns the join node ID, or "" if the branches end the graph.
+func (e *Executor) executeParallel(ctx, execCtx context.Context, graph *Graph, node Node, state *State, steps *stepGuard) (string, error) {
+	branches, join, err := parallelBranches(graph, node.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, id := range branches {
+		if err := steps.visit(id); err != nil {
+			return "", err
+		}
+	}
+
+	branchCtx, cancel := context.WithCancel(execCtx)
+	defer cancel()
+
+	limit := e.maxConcurrency
+	if limit <= 0 || limit > len(branches) {
+		limit = len(branches)
+	}
+	sem := make(chan struct{}, limit)
+	outputs := make([]any, len(branches))
+	attempts := make([]int, len(branches))
+	snapshots := make([]*State, len(branches))
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, id := range branches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-branchCtx.Done():
+				return
+			}
+			snapshot := &State{
+				Last:       state.Last,
+				Outputs:    maps.Clone(state.Outputs),
+				Provenance: make(map[string]string),
+			}
+			snapshots[i] = snapshot
+			output, n, err := e.executeNode(ctx, branchCtx, graph, graph.Nodes[id], snapshot)
+			attempts[i] = n
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			outputs[i] = output
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return "", fmt.Errorf("parallel node %q: %w", node.ID, firstErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	joined := make(map[string]any, len(branches))
+	for i, id := range branches {
+		for key, source := range snapshots[i].Provenance {
+			state.setOutput(key, snapshots[i].Outputs[key], source)
+		}
+		state.setOutput(id, outputs[i], id)
+		state.recordAttempts(id, attempts[i])
+		joined[id] = outputs[i]
+	}
+	state.setOutput(node.ID, joined, node.ID)
+	state.Last = joined
+	return join, nil
+}
+
+// parallelBranches returns the branch targets of a parallel node and the
+// node they join on.
+func parallelBranches(graph *Graph, parallelID string) ([]string, string, error) {
+	var branches []string
+	for _, edge := range edgesFrom(graph, parallelID) {
+		branches = append(branches, edge.To)
+	}
+	if len(branches) == 0 {
+		return nil, "", fmt.Errorf("parallel node %q has no outgoing edges", parallelID)
+	}
+	join := ""
+	for i, id := range branches {
+		if graph.Nodes[id].Type == NodeTypeParallel {
+			return nil, "", fmt.Errorf("parallel node %q: nested parallel node %q is not supported", parallelID, id)
+		}
+		out := edgesFrom(graph, id)
+		if len(out) > 1 {
+			return nil, "", fmt.Errorf("parallel node %q: branch %q must have at most one outgoing edge", parallelID, id)
+		}
+		next := ""
+		if len(out) == 1 {
+			next = out[0].To
+		}
+		if i > 0 && next != join {
+			return nil, "", fmt.Errorf("parallel node %q: branches must join on the same node", parallelID)
+		}
+		join = next
+	}
+	return branches, join, nil
 }
 
 func resolveStartNode(graph *Graph) (string, error) {
@@ -236,6 +531,16 @@ func selectNextNode(currentID string, candidates []string, graph *Graph, state *
 
 	var fallback string
 	for _, edge := range edges {
+		if when := strings.TrimSpace(edge.When); when != "" {
+			ok, err := evaluateWhen(when, state)
+			if err != nil {
+				return "", fmt.Errorf("edge when %q on %q: %w", when, currentID, err)
+			}
+			if ok {
+				return edge.To, nil
+			}
+			continue
+		}
 		cond := strings.TrimSpace(edge.Condition)
 		if cond == "" || cond == "default" || cond == "always" {
 			if fallback == "" {
@@ -356,6 +661,14 @@ func resolveMapValue(value any, key string) (any, bool) {
 }
 
 func (e *Executor) emitAudit(ctx context.Context, event AuditEvent) error {
+	e.auditMu.Lock()
+	defer e.auditMu.Unlock()
+	logger.DebugContext(ctx, "planner.node."+event.Status,
+		slog.String("graph_id", event.GraphID),
+		slog.String("run_id", event.RunID),
+		slog.String("node_id", event.NodeID),
+		slog.String("node_type", event.NodeType),
+	)
 	if e.AuditStore != nil {
 		if err := e.AuditStore.Record(ctx, event); err != nil {
 			return fmt.Errorf("audit store: %w", err)
@@ -2,7 +2,14 @@ package planner
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+	"github.com/jllopis/kairos/pkg/resilience"
 )
 
 func TestExecutorSinglePath(t *testing.T) {
@@ -69,6 +76,41 @@ func TestExecutorBranching(t *testing.T) {
 	}
 }
 
+func TestExecutorWhenBranching(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-when",
+		Start: "intent",
+		Nodes: map[string]Node{
+			"intent":   {Type: "noop", Input: "sales_by_region"},
+			"product":  {Type: "noop", Input: "product"},
+			"region":   {Type: "noop", Input: "region"},
+			"fallback": {Type: "noop", Input: "fallback"},
+		},
+		Edges: []Edge{
+			{From: "intent", To: "product", When: `intent == "sales_by_product"`},
+			{From: "intent", To: "region", When: `intent in ["sales_by_region", "sales_by_country"]`},
+			{From: "intent", To: "fallback"},
+		},
+	}
+
+	exec := NewExecutor(map[string]Handler{
+		"noop": func(_ context.Context, node Node, _ *State) (any, error) {
+			return node.Input, nil
+		},
+	})
+
+	state, err := exec.Execute(context.Background(), graph, nil)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if state.Last != "region" {
+		t.Fatalf("unexpected last output: %v", state.Last)
+	}
+	if _, ok := state.Outputs["product"]; ok {
+		t.Fatal("unmatched branch must not run")
+	}
+}
+
 func TestExecutorAuditHook(t *testing.T) {
 	graph := &Graph{
 		ID:    "graph-audit",
@@ -135,3 +177,320 @@ func TestExecutorHandlersByIDOverridesType(t *testing.T) {
 		t.Fatalf("expected type handler output, got: %v", state.Outputs["n2"])
 	}
 }
+
+func TestExecutorParallelFanOut(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-parallel",
+		Start: "plan",
+		Nodes: map[string]Node{
+			"plan":        {Type: "noop", Input: "plan"},
+			"fan":         {Type: NodeTypeParallel},
+			"knowledge":   {Type: "slow", Input: "kb"},
+			"spreadsheet": {Type: "slow", Input: "sheet"},
+			"synthesize":  {Type: "join"},
+		},
+		Edges: []Edge{
+			{From: "plan", To: "fan"},
+			{From: "fan", To: "knowledge"},
+			{From: "fan", To: "spreadsheet"},
+			{From: "knowledge", To: "synthesize"},
+			{From: "spreadsheet", To: "synthesize"},
+		},
+	}
+
+	var running, peak atomic.Int32
+	exec := NewExecutor(map[string]Handler{
+		"noop": func(_ context.Context, node Node, _ *State) (any, error) {
+			return node.Input, nil
+		},
+		"slow": func(_ context.Context, node Node, _ *State) (any, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return node.Input, nil
+		},
+		"join": func(_ context.Context, _ Node, state *State) (any, error) {
+			return fmt.Sprintf("%v+%v", state.Outputs["knowledge"], state.Outputs["spreadsheet"]), nil
+		},
+	}, WithMaxConcurrency(2))
+
+	state, err := exec.Execute(context.Background(), graph, nil)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if state.Last != "kb+sheet" {
+		t.Fatalf("unexpected last output: %v", state.Last)
+	}
+	joined, ok := state.Outputs["fan"].(map[string]any)
+	if !ok || joined["knowledge"] != "kb" || joined["spreadsheet"] != "sheet" {
+		t.Fatalf("unexpected joined output: %v", state.Outputs["fan"])
+	}
+	if peak.Load() != 2 {
+		t.Fatalf("expected branches to run concurrently, peak=%d", peak.Load())
+	}
+}
+
+func TestExecutorParallelMaxConcurrency(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-parallel-limit",
+		Start: "fan",
+		Nodes: map[string]Node{
+			"fan": {Type: NodeTypeParallel},
+			"a":   {Type: "slow"},
+			"b":   {Type: "slow"},
+			"c":   {Type: "slow"},
+		},
+		Edges: []Edge{
+			{From: "fan", To: "a"},
+			{From: "fan", To: "b"},
+			{From: "fan", To: "c"},
+		},
+	}
+
+	var running, peak atomic.Int32
+	exec := NewExecutor(map[string]Handler{
+		"slow": func(_ context.Context, node Node, _ *State) (any, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+			time.Sleep(10 * time.Millisecond)
+			return node.ID, nil
+		},
+	}, WithMaxConcurrency(1))
+
+	if _, err := exec.Execute(context.Background(), graph, nil); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if peak.Load() != 1 {
+		t.Fatalf("expected at most 1 concurrent branch, peak=%d", peak.Load())
+	}
+}
+
+func TestExecutorParallelErrorCancelsSiblings(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-parallel-error",
+		Start: "fan",
+		Nodes: map[string]Node{
+			"fan":  {Type: NodeTypeParallel},
+			"fail": {Type: "fail"},
+			"wait": {Type: "wait"},
+			"next": {Type: "wait"},
+		},
+		Edges: []Edge{
+			{From: "fan", To: "fail"},
+			{From: "fan", To: "wait"},
+			{From: "fail", To: "next"},
+			{From: "wait", To: "next"},
+		},
+	}
+
+	boom := errors.New("boom")
+	cancelled := make(chan struct{})
+	exec := NewExecutor(map[string]Handler{
+		"fail": func(_ context.Context, _ Node, _ *State) (any, error) {
+			return nil, boom
+		},
+		"wait": func(ctx context.Context, _ Node, _ *State) (any, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	})
+
+	_, err := exec.Execute(context.Background(), graph, nil)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected branch error, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("sibling branch was not cancelled")
+	}
+}
+
+func TestExecutorParallelBranchesMustJoin(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-parallel-invalid",
+		Start: "fan",
+		Nodes: map[string]Node{
+			"fan": {Type: NodeTypeParallel},
+			"a":   {Type: "noop"},
+			"b":   {Type: "noop"},
+			"x":   {Type: "noop"},
+			"y":   {Type: "noop"},
+		},
+		Edges: []Edge{
+			{From: "fan", To: "a"},
+			{From: "fan", To: "b"},
+			{From: "a", To: "x"},
+			{From: "b", To: "y"},
+		},
+	}
+	exec := NewExecutor(map[string]Handler{
+		"noop": func(_ context.Context, node Node, _ *State) (any, error) {
+			return node.ID, nil
+		},
+	})
+	if _, err := exec.Execute(context.Background(), graph, nil); err == nil {
+		t.Fatal("expected error for branches that do not join")
+	}
+}
+
+func TestExecutorNodeRetry(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-retry",
+		Start: "flaky",
+		Nodes: map[string]Node{
+			"flaky":  {Type: "flaky", Retry: &RetryPolicy{Max: 3, Backoff: "1ms"}},
+			"stable": {Type: "noop"},
+		},
+		Edges: []Edge{{From: "flaky", To: "stable"}},
+	}
+	var calls atomic.Int32
+	exec := NewExecutor(map[string]Handler{
+		"flaky": func(_ context.Context, _ Node, _ *State) (any, error) {
+			if calls.Add(1) < 3 {
+				return nil, errors.New("transient")
+			}
+			return "ok", nil
+		},
+		"noop": func(_ context.Context, node Node, _ *State) (any, error) {
+			return node.ID, nil
+		},
+	})
+
+	state, err := exec.Execute(context.Background(), graph, nil)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if state.Attempts["flaky"] != 3 || state.Attempts["stable"] != 1 {
+		t.Fatalf("unexpected attempts: %v", state.Attempts)
+	}
+}
+
+func TestExecutorNodeTimeoutOverridesDefault(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-timeout",
+		Start: "slow",
+		Nodes: map[string]Node{
+			"slow": {Type: "slow", Timeout: "20ms", Retry: &RetryPolicy{Max: 2, Backoff: "1ms"}},
+		},
+	}
+	var calls atomic.Int32
+	exec := NewExecutor(map[string]Handler{
+		"slow": func(ctx context.Context, _ Node, _ *State) (any, error) {
+			calls.Add(1)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return "late", nil
+			}
+		},
+	}, WithDefaultTimeout(time.Minute), WithDefaultRetry(resilience.RetryConfig{MaxAttempts: 5}))
+
+	start := time.Now()
+	if _, err := exec.Execute(context.Background(), graph, nil); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("node timeout not applied, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected node retry max to override default, got %d attempts", got)
+	}
+}
+
+func TestExecutorDefaultRetry(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-default-retry",
+		Start: "flaky",
+		Nodes: map[string]Node{"flaky": {Type: "flaky"}},
+	}
+	var calls atomic.Int32
+	exec := NewExecutor(map[string]Handler{
+		"flaky": func(_ context.Context, _ Node, _ *State) (any, error) {
+			calls.Add(1)
+			return nil, errors.New("always fails")
+		},
+	}, WithDefaultRetry(resilience.RetryConfig{MaxAttempts: 2, InitialDelay: time.Millisecond}))
+
+	if _, err := exec.Execute(context.Background(), graph, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestExecutorLoopWithAllowCycles(t *testing.T) {
+	graph, err := NewGraphBuilder().
+		AddNode(Node{ID: "count", Type: "count"}).
+		AddNode(Node{ID: "done", Type: "noop"}).
+		AddEdge(Edge{From: "count", To: "count", When: "count != 3"}).
+		AddEdge(Edge{From: "count", To: "done"}).
+		SetStart("count").
+		AllowCycles().
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	exec := NewExecutor(map[string]Handler{
+		"count": func(_ context.Context, node Node, state *State) (any, error) {
+			n, _ := state.Outputs[node.ID].(int)
+			return n + 1, nil
+		},
+		"noop": func(_ context.Context, node Node, _ *State) (any, error) {
+			return node.ID, nil
+		},
+	})
+	state, err := exec.Execute(context.Background(), graph, nil)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if state.Outputs["count"] != 3 || state.Last != "done" {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+}
+
+func TestExecutorMaxSteps(t *testing.T) {
+	graph := &Graph{
+		ID:          "graph-loop",
+		Start:       "a",
+		AllowCycles: true,
+		Nodes: map[string]Node{
+			"a": {Type: "noop"},
+			"b": {Type: "noop"},
+		},
+		Edges: []Edge{
+			{From: "a", To: "b"},
+			{From: "b", To: "a"},
+		},
+	}
+	exec := NewExecutor(map[string]Handler{
+		"noop": func(_ context.Context, node Node, _ *State) (any, error) {
+			return node.ID, nil
+		},
+	}, WithMaxSteps(5))
+
+	_, err := exec.Execute(context.Background(), graph, nil)
+	if !errors.Is(err, ErrMaxStepsExceeded) {
+		t.Fatalf("expected max steps error, got %v", err)
+	}
+	var kerr *kerrors.KairosError
+	if !errors.As(err, &kerr) || kerr.Code != kerrors.CodeInternal {
+		t.Fatalf("expected internal KairosError, got %v", err)
+	}
+	path, _ := kerr.Context["path"].([]string)
+	if want := []string{"a", "b", "a", "b", "a", "b"}; fmt.Sprint(path) != fmt.Sprint(want) {
+		t.Fatalf("unexpected path: %v", path)
+	}
+}
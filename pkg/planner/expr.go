@@ -0,0 +1,463 @@
+package planner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Edge "when" expressions select which outgoing edges are followed.
+//
+// Grammar:
+//
+//	expr    = or
+//	or      = and { ("||" | "or") and }
+//	and     = unary { ("&&" | "and") unary }
+//	unary   = ("!" | "not") unary | compare
+//	compare = operand [ ("==" | "!=") operand | ["not"] "in" operand ]
+//	operand = literal | path | list | "(" expr ")"
+//	list    = "[" [ literal { "," literal } ] "]"
+//	literal = string | number | "true" | "false" | "null"
+//	path    = ident { "." ident }
+//
+// A path resolves against State.Outputs (node ID, then map keys); "last"
+// refers to State.Last. Missing paths evaluate to null. Expressions cannot
+// call functions or mutate state.
+
+// whenExpr is a compiled edge expression.
+type whenExpr interface {
+	eval(state *State) any
+	// constant reports whether the expression references no state.
+	constant() bool
+}
+
+// compileWhen parses an edge expression.
+func compileWhen(src string) (whenExpr, error) {
+	tokens, err := tokenizeWhen(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &whenParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return expr, nil
+}
+
+// evaluateWhen compiles and evaluates src against state.
+func evaluateWhen(src string, state *State) (bool, error) {
+	expr, err := compileWhen(src)
+	if err != nil {
+		return false, err
+	}
+	return truthy(expr.eval(state)), nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type whenToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenizeWhen(src string) ([]whenToken, error) {
+	var tokens []whenToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			quote := r
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != quote; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, whenToken{kind: tokString, text: sb.String(), pos: i})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, whenToken{kind: tokNumber, text: string(runes[i:j]), pos: i})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '-') {
+				j++
+			}
+			tokens = append(tokens, whenToken{kind: tokIdent, text: string(runes[i:j]), pos: i})
+			i = j
+		default:
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				if two == "==" || two == "!=" || two == "&&" || two == "||" {
+					tokens = append(tokens, whenToken{kind: tokOp, text: two, pos: i})
+					i += 2
+					continue
+				}
+			}
+			if strings.ContainsRune("!()[],.", r) {
+				tokens = append(tokens, whenToken{kind: tokOp, text: string(r), pos: i})
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+	return append(tokens, whenToken{kind: tokEOF, pos: len(runes)}), nil
+}
+
+type whenParser struct {
+	tokens []whenToken
+	pos    int
+}
+
+func (p *whenParser) peek() whenToken {
+	return p.tokens[p.pos]
+}
+
+func (p *whenParser) next() whenToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator or keyword text.
+func (p *whenParser) accept(texts ...string) bool {
+	tok := p.peek()
+	if tok.kind != tokOp && tok.kind != tokIdent {
+		return false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *whenParser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at position %d", text, tok.pos)
+	}
+	return nil
+}
+
+func (p *whenParser) parseOr() (whenExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicExpr{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseAnd() (whenExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicExpr{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseUnary() (whenExpr, error) {
+	if p.accept("!", "not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{inner: inner}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *whenParser) parseCompare() (whenExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.accept("=="):
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &equalExpr{left: left, right: right}, nil
+	case p.accept("!="):
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{inner: &equalExpr{left: left, right: right}}, nil
+	case p.accept("in"):
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &inExpr{item: left, set: right}, nil
+	case p.peek().kind == tokIdent && p.peek().text == "not" && p.tokens[p.pos+1].text == "in":
+		p.pos += 2
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{inner: &inExpr{item: left, set: right}}, nil
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseOperand() (whenExpr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return literalExpr{value: tok.text}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return literalExpr{value: n}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return literalExpr{value: true}, nil
+		case "false":
+			return literalExpr{value: false}, nil
+		case "null":
+			return literalExpr{value: nil}, nil
+		case "and", "or", "not", "in":
+			return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+		}
+		path := []string{tok.text}
+		for p.accept(".") {
+			part := p.next()
+			if part.kind != tokIdent {
+				return nil, fmt.Errorf("expected identifier after '.' at position %d", part.pos)
+			}
+			path = append(path, part.text)
+		}
+		return pathExpr{path: path}, nil
+	case tokOp:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			list := listExpr{}
+			if p.accept("]") {
+				return list, nil
+			}
+			for {
+				item, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := item.(literalExpr); !ok {
+					return nil, fmt.Errorf("list items must be literals at position %d", tok.pos)
+				}
+				list.items = append(list.items, item)
+				if p.accept("]") {
+					return list, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+type literalExpr struct{ value any }
+
+func (e literalExpr) eval(*State) any { return e.value }
+func (e literalExpr) constant() bool  { return true }
+
+type pathExpr struct{ path []string }
+
+func (e pathExpr) eval(state *State) any {
+	if e.path[0] == "last" && len(e.path) == 1 {
+		return state.Last
+	}
+	value, ok := resolveOutputPath(strings.Join(e.path, "."), state)
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+func (e pathExpr) constant() bool { return false }
+
+type listExpr struct{ items []whenExpr }
+
+func (e listExpr) eval(state *State) any {
+	out := make([]any, 0, len(e.items))
+	for _, item := range e.items {
+		out = append(out, item.eval(state))
+	}
+	return out
+}
+
+func (e listExpr) constant() bool { return true }
+
+type logicExpr struct {
+	and         bool
+	left, right whenExpr
+}
+
+func (e *logicExpr) eval(state *State) any {
+	if e.and {
+		return truthy(e.left.eval(state)) && truthy(e.right.eval(state))
+	}
+	return truthy(e.left.eval(state)) || truthy(e.right.eval(state))
+}
+
+func (e *logicExpr) constant() bool { return e.left.constant() && e.right.constant() }
+
+type notExpr struct{ inner whenExpr }
+
+func (e *notExpr) eval(state *State) any { return !truthy(e.inner.eval(state)) }
+func (e *notExpr) constant() bool        { return e.inner.constant() }
+
+type equalExpr struct{ left, right whenExpr }
+
+func (e *equalExpr) eval(state *State) any {
+	return valuesEqual(e.left.eval(state), e.right.eval(state))
+}
+
+func (e *equalExpr) constant() bool { return e.left.constant() && e.right.constant() }
+
+type inExpr struct{ item, set whenExpr }
+
+func (e *inExpr) eval(state *State) any {
+	item := e.item.eval(state)
+	switch set := e.set.eval(state).(type) {
+	case []any:
+		for _, candidate := range set {
+			if valuesEqual(item, candidate) {
+				return true
+			}
+		}
+	case []string:
+		for _, candidate := range set {
+			if valuesEqual(item, candidate) {
+				return true
+			}
+		}
+	case map[string]any:
+		_, ok := set[fmt.Sprint(item)]
+		return ok
+	case map[string]string:
+		_, ok := set[fmt.Sprint(item)]
+		return ok
+	case string:
+		return item != nil && strings.Contains(set, fmt.Sprint(item))
+	}
+	return false
+}
+
+func (e *inExpr) constant() bool { return e.item.constant() && e.set.constant() }
+
+// valuesEqual compares numbers numerically and everything else by its
+// string form, so outputs typed as int or string match literals naturally.
+func valuesEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	default:
+		if f, ok := toFloat(v); ok {
+			return f != 0
+		}
+		return true
+	}
+}
@@ -1,30 +1,59 @@
 // Package planner defines the explicit planning graph model and executor.
 package planner
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
 
 // Graph defines a deterministic execution graph for the explicit planner.
+//
+// Graphs must be acyclic unless AllowCycles is set for intentional loops.
 type Graph struct {
-	ID    string          `json:"id" yaml:"id"`
-	Start string          `json:"start" yaml:"start"`
-	Nodes map[string]Node `json:"nodes" yaml:"nodes"`
-	Edges []Edge          `json:"edges" yaml:"edges"`
+	ID          string          `json:"id" yaml:"id"`
+	Start       string          `json:"start" yaml:"start"`
+	Nodes       map[string]Node `json:"nodes" yaml:"nodes"`
+	Edges       []Edge          `json:"edges" yaml:"edges"`
+	AllowCycles bool            `json:"allow_cycles,omitempty" yaml:"allow_cycles,omitempty"`
 }
 
 // Node represents a step in the graph.
+//
+// Retry and Timeout override the executor defaults for this node; Timeout
+// is a Go duration string ("10s") applied to each handler attempt.
 type Node struct {
 	ID       string            `json:"id" yaml:"id"`
 	Type     string            `json:"type" yaml:"type"`
 	Tool     string            `json:"tool,omitempty" yaml:"tool,omitempty"`
 	Input    any               `json:"input,omitempty" yaml:"input,omitempty"`
 	Metadata map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Retry    *RetryPolicy      `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Timeout  string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// RetryPolicy configures retries of a node handler.
+type RetryPolicy struct {
+	// Max is the maximum number of attempts, including the first one.
+	Max int `json:"max,omitempty" yaml:"max,omitempty"`
+	// Backoff is the initial delay between attempts as a Go duration
+	// string ("500ms"); later delays grow exponentially.
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
 }
 
 // Edge defines a transition between nodes.
+//
+// When holds an optional expression evaluated against the execution state
+// (for example `intent == "sales_by_region"`); see expr.go for the grammar.
+// Condition is the legacy prefix syntax (`last==`, `output.<path>==`).
+// An edge sets at most one of them; edges with neither act as the default.
 type Edge struct {
 	From      string `json:"from" yaml:"from"`
 	To        string `json:"to" yaml:"to"`
 	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	When      string `json:"when,omitempty" yaml:"when,omitempty"`
 }
 
 // Validate ensures the graph is well-formed for execution.
@@ -47,6 +76,9 @@ func (g *Graph) Validate() error {
 		if node.Type == "" {
 			return fmt.Errorf("node %q missing type", node.ID)
 		}
+		if err := node.validatePolicy(); err != nil {
+			return fmt.Errorf("node %q: %w", node.ID, err)
+		}
 	}
 
 	for _, edge := range g.Edges {
@@ -60,5 +92,114 @@ func (g *Graph) Validate() error {
 			return fmt.Errorf("edge to %q not found", edge.To)
 		}
 	}
+	if _, err := resolveStartNode(g); err != nil {
+		return err
+	}
+	if !g.AllowCycles {
+		if cycle := g.findCycle(); cycle != nil {
+			return fmt.Errorf("graph has a cycle: %s (set allow_cycles to permit loops)", strings.Join(cycle, " -> "))
+		}
+	}
+	return g.validateWhen()
+}
+
+// findCycle returns the node path of a cycle, or nil if the graph is
+// acyclic. Nodes are visited in sorted order so the result is stable.
+func (g *Graph) findCycle() []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	adj := make(map[string][]string, len(g.Nodes))
+	for _, edge := range g.Edges {
+		adj[edge.From] = append(adj[edge.From], edge.To)
+	}
+	marks := make(map[string]int, len(g.Nodes))
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		marks[id] = inProgress
+		path = append(path, id)
+		for _, next := range adj[id] {
+			switch marks[next] {
+			case inProgress:
+				start := slices.Index(path, next)
+				return append(slices.Clone(path[start:]), next)
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		marks[id] = done
+		return nil
+	}
+	for _, id := range slices.Sorted(maps.Keys(g.Nodes)) {
+		if marks[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+func (n Node) validatePolicy() error {
+	if n.Timeout != "" {
+		d, err := time.ParseDuration(n.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", n.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeout must be positive")
+		}
+	}
+	if n.Retry != nil {
+		if n.Retry.Max < 0 {
+			return fmt.Errorf("retry max must not be negative")
+		}
+		if n.Retry.Backoff != "" {
+			d, err := time.ParseDuration(n.Retry.Backoff)
+			if err != nil {
+				return fmt.Errorf("invalid retry backoff %q: %w", n.Retry.Backoff, err)
+			}
+			if d < 0 {
+				return fmt.Errorf("retry backoff must not be negative")
+			}
+		}
+	}
+	return nil
+}
+
+// validateWhen compiles edge expressions and ensures every node with
+// outgoing edges has at least one edge that can be taken.
+func (g *Graph) validateWhen() error {
+	satisfiable := make(map[string]bool)
+	for _, edge := range g.Edges {
+		when := strings.TrimSpace(edge.When)
+		if when == "" {
+			satisfiable[edge.From] = true
+			continue
+		}
+		if strings.TrimSpace(edge.Condition) != "" {
+			return fmt.Errorf("edge %s->%s sets both when and condition", edge.From, edge.To)
+		}
+		expr, err := compileWhen(when)
+		if err != nil {
+			return fmt.Errorf("edge %s->%s when %q: %w", edge.From, edge.To, when, err)
+		}
+		if !expr.constant() || truthy(expr.eval(&State{})) {
+			satisfiable[edge.From] = true
+		} else if _, ok := satisfiable[edge.From]; !ok {
+			satisfiable[edge.From] = false
+		}
+	}
+	for id, ok := range satisfiable {
+		if !ok {
+			return fmt.Errorf("node %q has no satisfiable outgoing edge", id)
+		}
+	}
 	return nil
 }
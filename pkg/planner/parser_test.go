@@ -88,3 +88,105 @@ func TestMarshalRoundTrip(t *testing.T) {
 		t.Fatalf("yaml round-trip mismatch: %q", parsedYAML.ID)
 	}
 }
+
+func TestParseValidatesWhen(t *testing.T) {
+	valid := []byte(`
+id: routing
+start: intent
+nodes:
+  intent: { type: noop }
+  sales: { type: noop }
+  fallback: { type: noop }
+edges:
+  - from: intent
+    to: sales
+    when: intent == "sales_by_region"
+  - from: intent
+    to: fallback
+`)
+	graph, err := ParseYAML(valid)
+	if err != nil {
+		t.Fatalf("parse yaml: %v", err)
+	}
+	if graph.Edges[0].When != `intent == "sales_by_region"` {
+		t.Fatalf("unexpected when: %q", graph.Edges[0].When)
+	}
+
+	cases := map[string]string{
+		"syntax": `
+nodes:
+  a: { type: noop }
+  b: { type: noop }
+edges:
+  - { from: a, to: b, when: "a ==" }
+`,
+		"unsatisfiable": `
+nodes:
+  a: { type: noop }
+  b: { type: noop }
+edges:
+  - { from: a, to: b, when: "false" }
+  - { from: a, to: b, when: "1 == 2" }
+`,
+		"both": `
+nodes:
+  a: { type: noop }
+  b: { type: noop }
+edges:
+  - { from: a, to: b, when: "a == 1", condition: "last==1" }
+`,
+	}
+	for name, payload := range cases {
+		if _, err := ParseYAML([]byte(payload)); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestParseNodePolicy(t *testing.T) {
+	graph, err := ParseYAML([]byte(`
+nodes:
+  knowledge:
+    type: knowledge
+    retry: { max: 3, backoff: 500ms }
+    timeout: 10s
+`))
+	if err != nil {
+		t.Fatalf("parse yaml: %v", err)
+	}
+	node := graph.Nodes["knowledge"]
+	if node.Retry == nil || node.Retry.Max != 3 || node.Retry.Backoff != "500ms" || node.Timeout != "10s" {
+		t.Fatalf("unexpected node policy: %+v", node)
+	}
+
+	for _, payload := range []string{
+		`nodes: { a: { type: noop, timeout: soon } }`,
+		`nodes: { a: { type: noop, retry: { backoff: "-1s" } } }`,
+		`nodes: { a: { type: noop, retry: { max: -1 } } }`,
+	} {
+		if _, err := ParseYAML([]byte(payload)); err == nil {
+			t.Fatalf("expected %q to be rejected", payload)
+		}
+	}
+}
+
+func TestParseRejectsCyclesUnlessAllowed(t *testing.T) {
+	cyclic := `
+start: a
+nodes:
+  a: { type: noop }
+  b: { type: noop }
+edges:
+  - { from: a, to: b }
+  - { from: b, to: a }
+`
+	if _, err := ParseYAML([]byte(cyclic)); err == nil {
+		t.Fatal("expected yaml cycle to be rejected")
+	}
+	if _, err := ParseJSON([]byte(`{"start":"a","nodes":{"a":{"type":"noop"},"b":{"type":"noop"}},"edges":[{"from":"a","to":"b"},{"from":"b","to":"a"}]}`)); err == nil {
+		t.Fatal("expected json cycle to be rejected")
+	}
+	if _, err := ParseYAML([]byte("allow_cycles: true\n" + cyclic)); err != nil {
+		t.Fatalf("expected allowed cycle to parse: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+)
+
+// State holds outputs produced during graph execution.
+//
+// Attempts records how many handler invocations each node needed, which
+// is useful to debug retry policies. Provenance maps each output key to the
+// node that wrote it; keys written outside a node handler (such as the
+// initial input) have an empty source.
+//
+// Outputs stays exported for compatibility, but handlers should prefer the
+// typed accessors (GetString, GetInt, GetMap) over type assertions, and
+// SetOutput over writing the map directly so provenance is recorded.
+type State struct {
+	Last       any
+	Outputs    map[string]any
+	Attempts   map[string]int
+	Provenance map[string]string
+
+	// node is the ID of the node whose handler is running.
+	node string
+}
+
+// NewState creates an initialized execution state.
+func NewState() *State {
+	return &State{
+		Outputs:    make(map[string]any),
+		Attempts:   make(map[string]int),
+		Provenance: make(map[string]string),
+	}
+}
+
+// SetOutput stores value under key and records the running node as its
+// source.
+func (s *State) SetOutput(key string, value any) {
+	s.setOutput(key, value, s.node)
+}
+
+// Source returns the ID of the node that wrote key.
+func (s *State) Source(key string) (string, bool) {
+	source, ok := s.Provenance[key]
+	return source, ok
+}
+
+// Get returns the raw output stored under key.
+func (s *State) Get(key string) (any, bool) {
+	value, ok := s.Outputs[key]
+	return value, ok
+}
+
+// GetString returns the output under key if it is a string.
+func (s *State) GetString(key string) (string, bool) {
+	value, ok := s.Outputs[key].(string)
+	return value, ok
+}
+
+// GetInt returns the output under key if it is an integer, including
+// integral floats produced by JSON decoding.
+func (s *State) GetInt(key string) (int, bool) {
+	switch value := s.Outputs[key].(type) {
+	case int:
+		return value, true
+	case int32:
+		return int(value), true
+	case int64:
+		return int(value), true
+	case float64:
+		if value == math.Trunc(value) {
+			return int(value), true
+		}
+	}
+	return 0, false
+}
+
+// GetMap returns the output under key if it is a map with string keys.
+func (s *State) GetMap(key string) (map[string]any, bool) {
+	switch value := s.Outputs[key].(type) {
+	case map[string]any:
+		return value, true
+	case map[string]string:
+		out := make(map[string]any, len(value))
+		for k, v := range value {
+			out[k] = v
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// MustString returns the string output under key. It panics with a
+// message naming the key when it is missing or not a string; use it only
+// for values a previous node is guaranteed to produce.
+func (s *State) MustString(key string) string {
+	value, ok := s.Outputs[key]
+	if !ok {
+		panic(fmt.Sprintf("planner: state key %q is not set", key))
+	}
+	str, ok := value.(string)
+	if !ok {
+		panic(fmt.Sprintf("planner: state key %q is %T, not string", key, value))
+	}
+	return str
+}
+
+func (s *State) setOutput(key string, value any, source string) {
+	if s.Outputs == nil {
+		s.Outputs = make(map[string]any)
+	}
+	if s.Provenance == nil {
+		s.Provenance = make(map[string]string)
+	}
+	s.Outputs[key] = value
+	s.Provenance[key] = source
+}
+
+func (s *State) recordAttempts(nodeID string, attempts int) {
+	if s.Attempts == nil {
+		s.Attempts = make(map[string]int)
+	}
+	s.Attempts[nodeID] = attempts
+}
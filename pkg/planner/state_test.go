@@ -0,0 +1,105 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStateTypedAccessors(t *testing.T) {
+	state := NewState()
+	state.SetOutput("query", "sales by region")
+	state.SetOutput("limit", float64(10))
+	state.SetOutput("ratio", 0.5)
+	state.SetOutput("filters", map[string]string{"region": "EMEA"})
+
+	if got, ok := state.GetString("query"); !ok || got != "sales by region" {
+		t.Fatalf("GetString: %q %v", got, ok)
+	}
+	if _, ok := state.GetString("limit"); ok {
+		t.Fatal("GetString must reject non-string values")
+	}
+	if got, ok := state.GetInt("limit"); !ok || got != 10 {
+		t.Fatalf("GetInt: %d %v", got, ok)
+	}
+	if _, ok := state.GetInt("ratio"); ok {
+		t.Fatal("GetInt must reject fractional values")
+	}
+	if got, ok := state.GetMap("filters"); !ok || got["region"] != "EMEA" {
+		t.Fatalf("GetMap: %v %v", got, ok)
+	}
+	if _, ok := state.GetMap("query"); ok {
+		t.Fatal("GetMap must reject non-map values")
+	}
+	if source, ok := state.Source("query"); !ok || source != "" {
+		t.Fatalf("expected empty source outside handlers, got %q %v", source, ok)
+	}
+}
+
+func TestStateMustStringPanics(t *testing.T) {
+	state := NewState()
+	state.SetOutput("count", 3)
+
+	for key, want := range map[string]string{
+		"missing": `"missing" is not set`,
+		"count":   `"count" is int, not string`,
+	} {
+		func() {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.Contains(msg, want) {
+					t.Fatalf("MustString(%q) panic = %q, want %q", key, msg, want)
+				}
+			}()
+			state.MustString(key)
+		}()
+	}
+}
+
+func TestStateProvenance(t *testing.T) {
+	graph := &Graph{
+		ID:    "graph-provenance",
+		Start: "fan",
+		Nodes: map[string]Node{
+			"fan":    {Type: NodeTypeParallel},
+			"region": {Type: "set", Input: "region"},
+			"amount": {Type: "set", Input: "amount"},
+			"report": {Type: "set", Input: "report"},
+		},
+		Edges: []Edge{
+			{From: "fan", To: "region"},
+			{From: "fan", To: "amount"},
+			{From: "region", To: "report"},
+			{From: "amount", To: "report"},
+		},
+	}
+	exec := NewExecutor(map[string]Handler{
+		"set": func(_ context.Context, node Node, state *State) (any, error) {
+			state.SetOutput(node.Input.(string)+"_value", node.ID)
+			return node.ID, nil
+		},
+	})
+	state := NewState()
+	state.SetOutput("input", "hello")
+	state, err := exec.Execute(context.Background(), graph, state)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	want := map[string]string{
+		"input":        "",
+		"region_value": "region",
+		"amount_value": "amount",
+		"report_value": "report",
+		"report":       "report",
+		"fan":          "fan",
+	}
+	for key, source := range want {
+		if got, ok := state.Source(key); !ok || got != source {
+			t.Fatalf("source of %q = %q (%v), want %q", key, got, ok, source)
+		}
+	}
+	if got, _ := state.GetString("amount_value"); got != "amount" {
+		t.Fatalf("branch output not merged: %q", got)
+	}
+}
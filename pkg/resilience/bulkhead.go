@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// Package resilience provides retry and circuit breaker patterns for Kairos.
+// See docs/ERROR_HANDLING.md for strategy and examples.
+package resilience
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+
+	"github.com/jllopis/kairos/pkg/errors"
+)
+
+// ErrBulkheadFull is the cause of the error returned by Bulkhead.Execute when
+// every slot is busy and the queue is full.
+var ErrBulkheadFull = stderrors.New("bulkhead full")
+
+// Bulkhead caps the number of concurrent calls to a resource. Up to
+// maxConcurrent calls run at once, up to maxQueue more wait for a slot and
+// the rest are rejected immediately.
+type Bulkhead struct {
+	slots    chan struct{}
+	maxQueue int
+
+	mu        sync.Mutex
+	inFlight  int
+	queued    int
+	completed int64
+	rejected  int64
+}
+
+// BulkheadStats is a snapshot of a Bulkhead for telemetry.
+type BulkheadStats struct {
+	// MaxConcurrent is the number of calls allowed to run at once.
+	MaxConcurrent int
+	// MaxQueue is the number of calls allowed to wait for a slot.
+	MaxQueue int
+	// InFlight is the number of calls running now.
+	InFlight int
+	// Queued is the number of calls waiting for a slot now.
+	Queued int
+	// Completed counts calls that ran to completion, successfully or not.
+	Completed int64
+	// Rejected counts calls turned away because the queue was full or
+	// abandoned while waiting.
+	Rejected int64
+}
+
+// NewBulkhead creates a bulkhead allowing maxConcurrent calls in flight and
+// maxQueue waiting callers. A maxConcurrent below 1 is treated as 1 and a
+// negative maxQueue as 0 (reject as soon as all slots are busy).
+func NewBulkhead(maxConcurrent, maxQueue int) *Bulkhead {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxQueue < 0 {
+		maxQueue = 0
+	}
+	return &Bulkhead{
+		slots:    make(chan struct{}, maxConcurrent),
+		maxQueue: maxQueue,
+	}
+}
+
+// Execute runs fn once a slot is free. When all slots are busy and the queue
+// is full it returns errors.CodeRateLimit wrapping ErrBulkheadFull without
+// calling fn; when ctx ends while queued it returns errors.CodeRateLimit
+// wrapping the context error. Both are recoverable.
+func (b *Bulkhead) Execute(ctx context.Context, fn func() error) error {
+	if err := b.acquire(ctx); err != nil {
+		return err
+	}
+	defer b.release()
+	return fn()
+}
+
+// Stats returns a snapshot of the bulkhead counters.
+func (b *Bulkhead) Stats() BulkheadStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BulkheadStats{
+		MaxConcurrent: cap(b.slots),
+		MaxQueue:      b.maxQueue,
+		InFlight:      b.inFlight,
+		Queued:        b.queued,
+		Completed:     b.completed,
+		Rejected:      b.rejected,
+	}
+}
+
+func (b *Bulkhead) acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		b.mu.Lock()
+		b.rejected++
+		b.mu.Unlock()
+		return b.rejectError("bulkhead wait aborted", err)
+	}
+
+	b.mu.Lock()
+	select {
+	case b.slots <- struct{}{}:
+		b.inFlight++
+		b.mu.Unlock()
+		return nil
+	default:
+	}
+	if b.queued >= b.maxQueue {
+		b.rejected++
+		b.mu.Unlock()
+		return b.rejectError("bulkhead full", ErrBulkheadFull)
+	}
+	b.queued++
+	b.mu.Unlock()
+
+	select {
+	case b.slots <- struct{}{}:
+		b.mu.Lock()
+		b.queued--
+		b.inFlight++
+		b.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.queued--
+		b.rejected++
+		b.mu.Unlock()
+		return b.rejectError("bulkhead wait aborted", ctx.Err())
+	}
+}
+
+func (b *Bulkhead) release() {
+	b.mu.Lock()
+	b.inFlight--
+	b.completed++
+	b.mu.Unlock()
+	<-b.slots
+}
+
+func (b *Bulkhead) rejectError(msg string, cause error) error {
+	return errors.New(errors.CodeRateLimit, msg, cause).
+		WithContext("max_concurrent", cap(b.slots)).
+		WithContext("max_queue", b.maxQueue).
+		WithRecoverable(true)
+}
+
+// WithBulkhead runs fn through bulkhead. A nil bulkhead runs fn directly.
+func WithBulkhead(ctx context.Context, bulkhead *Bulkhead, fn func() error) error {
+	if bulkhead == nil {
+		return fn()
+	}
+	return bulkhead.Execute(ctx, fn)
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+)
+
+// fillBulkhead starts n calls that block until release is closed and waits
+// until they are all in flight or queued.
+func fillBulkhead(t *testing.T, b *Bulkhead, n int, release chan struct{}) *sync.WaitGroup {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = b.Execute(context.Background(), func() error {
+				<-release
+				return nil
+			})
+		}()
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		stats := b.Stats()
+		if stats.InFlight+stats.Queued == n {
+			return &wg
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("calls did not reach the bulkhead: %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBulkhead_LimitsConcurrency(t *testing.T) {
+	b := NewBulkhead(2, 1)
+	release := make(chan struct{})
+	wg := fillBulkhead(t, b, 3, release)
+
+	stats := b.Stats()
+	if stats.InFlight != 2 || stats.Queued != 1 {
+		t.Fatalf("expected 2 in flight and 1 queued, got %+v", stats)
+	}
+
+	called := false
+	err := b.Execute(context.Background(), func() error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Fatalf("expected rejected call not to run")
+	}
+	if !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull, got %v", err)
+	}
+	if code, ok := kerrors.CodeOf(err); !ok || code != kerrors.CodeRateLimit {
+		t.Fatalf("expected CodeRateLimit, got %v", err)
+	}
+	if !kerrors.IsRecoverable(err) {
+		t.Fatalf("expected rejection to be recoverable")
+	}
+
+	close(release)
+	wg.Wait()
+	stats = b.Stats()
+	if stats.InFlight != 0 || stats.Queued != 0 || stats.Completed != 3 || stats.Rejected != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestBulkhead_QueuedCallHonorsContext(t *testing.T) {
+	b := NewBulkhead(1, 1)
+	release := make(chan struct{})
+	wg := fillBulkhead(t, b, 1, release)
+	defer func() {
+		close(release)
+		wg.Wait()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := b.Execute(ctx, func() error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if code, ok := kerrors.CodeOf(err); !ok || code != kerrors.CodeRateLimit {
+		t.Fatalf("expected CodeRateLimit, got %v", err)
+	}
+	if stats := b.Stats(); stats.Queued != 0 || stats.Rejected != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestBulkhead_PropagatesError(t *testing.T) {
+	b := NewBulkhead(0, -1)
+	boom := errors.New("boom")
+	if err := WithBulkhead(context.Background(), b, func() error { return boom }); err != boom {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+	stats := b.Stats()
+	if stats.MaxConcurrent != 1 || stats.MaxQueue != 0 || stats.Completed != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if err := WithBulkhead(context.Background(), nil, func() error { return nil }); err != nil {
+		t.Fatalf("expected nil bulkhead to run fn, got %v", err)
+	}
+}
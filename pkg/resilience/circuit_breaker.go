@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/clock"
 	"github.com/jllopis/kairos/pkg/errors"
 )
 
@@ -38,6 +39,9 @@ type CircuitBreakerConfig struct {
 
 	// Name is the circuit breaker identifier for logging/metrics.
 	Name string
+
+	// Clock measures Timeout. Defaults to the real clock.
+	Clock clock.Clock
 }
 
 // CircuitBreaker prevents cascading failures using the circuit breaker pattern.
@@ -64,6 +68,7 @@ func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
 	if config.Name == "" {
 		config.Name = "circuit_breaker"
 	}
+	config.Clock = clock.OrReal(config.Clock)
 
 	return &CircuitBreaker{
 		config: config,
@@ -95,7 +100,7 @@ func (cb *CircuitBreaker) Call(ctx context.Context, fn func() error) error {
 	defer cb.mu.Unlock()
 	if err != nil {
 		cb.failures++
-		cb.lastFailTime = time.Now()
+		cb.lastFailTime = cb.config.Clock.Now()
 
 		// Transition to open if threshold reached
 		if cb.failures >= cb.config.FailureThreshold && cb.state == StateClosed {
@@ -127,7 +132,7 @@ func (cb *CircuitBreaker) Call(ctx context.Context, fn func() error) error {
 func (cb *CircuitBreaker) checkStateLocked() {
 	if cb.state == StateOpen {
 		// Check if we should try half-open
-		if time.Since(cb.lastFailTime) > cb.config.Timeout {
+		if cb.config.Clock.Since(cb.lastFailTime) > cb.config.Timeout {
 			cb.state = StateHalfOpen
 			cb.successes = 0
 			cb.failures = 0
@@ -156,5 +161,5 @@ func (cb *CircuitBreaker) Open() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.state = StateOpen
-	cb.lastFailTime = time.Now()
+	cb.lastFailTime = cb.config.Clock.Now()
 }
@@ -5,13 +5,16 @@ package resilience
 
 import (
 	"context"
+	stderrors "errors"
+	"slices"
 
 	"github.com/jllopis/kairos/pkg/errors"
 )
 
 // FallbackStrategy defines a fallback behavior when primary operation fails.
 type FallbackStrategy interface {
-	// Execute runs the fallback operation.
+	// Execute runs the fallback operation. primaryErr is the error that
+	// triggered it, so strategies can act on why the primary failed.
 	Execute(ctx context.Context, primaryErr error) (interface{}, error)
 }
 
@@ -60,7 +63,11 @@ func (c *CachedFallback) Execute(ctx context.Context, primaryErr error) (interfa
 	return c.Cache, nil
 }
 
-// ChainedFallback tries multiple fallbacks in sequence.
+// ChainedFallback tries multiple fallbacks in sequence. Every fallback
+// receives the primary error, so ConditionalFallback entries route on why
+// the primary failed rather than on how the previous fallback failed.
+// ConditionalFallback entries that do not match are skipped. When all of
+// them fail, the last error is returned.
 type ChainedFallback struct {
 	Fallbacks []FallbackStrategy
 }
@@ -70,7 +77,10 @@ func (c *ChainedFallback) Execute(ctx context.Context, primaryErr error) (interf
 	var lastErr error = primaryErr
 
 	for _, fallback := range c.Fallbacks {
-		value, err := fallback.Execute(ctx, lastErr)
+		if cond, ok := fallback.(*ConditionalFallback); ok && !cond.matches(primaryErr) {
+			continue
+		}
+		value, err := fallback.Execute(ctx, primaryErr)
 		if err == nil {
 			return value, nil
 		}
@@ -80,6 +90,45 @@ func (c *ChainedFallback) Execute(ctx context.Context, primaryErr error) (interf
 	return nil, lastErr
 }
 
+// ConditionalFallback runs Strategy only when When matches the primary
+// error; otherwise it returns the primary error unchanged. A nil When always
+// matches.
+type ConditionalFallback struct {
+	When     func(error) bool
+	Strategy FallbackStrategy
+}
+
+// Execute implements FallbackStrategy.
+func (c *ConditionalFallback) Execute(ctx context.Context, primaryErr error) (interface{}, error) {
+	if !c.matches(primaryErr) {
+		return nil, primaryErr
+	}
+	return c.Strategy.Execute(ctx, primaryErr)
+}
+
+func (c *ConditionalFallback) matches(err error) bool {
+	return c.When == nil || c.When(err)
+}
+
+// OnCode returns a ConditionalFallback condition matching errors whose code
+// (see errors.CodeOf) is one of codes.
+func OnCode(codes ...errors.ErrorCode) func(error) bool {
+	return func(err error) bool {
+		code, ok := errors.CodeOf(err)
+		return ok && slices.Contains(codes, code)
+	}
+}
+
+// IsTimeout reports whether err was caused by an exceeded deadline: it
+// carries errors.CodeTimeout (as returned by WithTimeout) or wraps
+// context.DeadlineExceeded.
+func IsTimeout(err error) bool {
+	if code, ok := errors.CodeOf(err); ok && code == errors.CodeTimeout {
+		return true
+	}
+	return stderrors.Is(err, context.DeadlineExceeded)
+}
+
 // WithFallback executes fn, and on error, uses the fallback strategy.
 func WithFallback(ctx context.Context, fn func() (interface{}, error), fallback FallbackStrategy) (interface{}, error) {
 	value, err := fn()
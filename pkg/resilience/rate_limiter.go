@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// Package resilience provides retry and circuit breaker patterns for Kairos.
+// See docs/ERROR_HANDLING.md for strategy and examples.
+package resilience
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jllopis/kairos/pkg/errors"
+)
+
+// RateLimiter is a token bucket: it holds up to burst tokens, refilled at
+// rps tokens per second, and each call consumes one.
+type RateLimiter struct {
+	rps   float64
+	burst float64
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	allowed  int64
+	waits    int64
+	rejected int64
+}
+
+// RateLimiterStats is a snapshot of a RateLimiter for telemetry.
+type RateLimiterStats struct {
+	// Tokens is the number of tokens available now. It is negative while
+	// callers are queued in Wait.
+	Tokens float64
+	// Allowed counts calls that obtained a token.
+	Allowed int64
+	// Waits counts Wait calls that had to block for a token.
+	Waits int64
+	// Rejected counts Allow calls denied and Wait calls abandoned.
+	Rejected int64
+}
+
+// NewRateLimiter creates a limiter allowing rps calls per second on average
+// with bursts of up to burst calls. The bucket starts full. A burst below 1
+// is treated as 1; an rps of zero or less disables limiting.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rps:    rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow consumes a token if one is available and reports whether it did.
+// It never blocks.
+func (rl *RateLimiter) Allow() bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.rps <= 0 {
+		rl.allowed++
+		return true
+	}
+	rl.refillLocked(time.Now())
+	if rl.tokens < 1 {
+		rl.rejected++
+		return false
+	}
+	rl.tokens--
+	rl.allowed++
+	return true
+}
+
+// Wait blocks until a token is available or ctx is done. Callers are served
+// in arrival order. When ctx ends first, or its deadline is too close for
+// the token to arrive, Wait returns errors.CodeRateLimit wrapping the
+// context error.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return rl.waitError(err)
+	}
+
+	rl.mu.Lock()
+	if rl.rps <= 0 {
+		rl.allowed++
+		rl.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	rl.refillLocked(now)
+	rl.tokens--
+	if rl.tokens >= 0 {
+		rl.allowed++
+		rl.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(math.Ceil(-rl.tokens / rl.rps * float64(time.Second)))
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		rl.tokens++
+		rl.rejected++
+		rl.mu.Unlock()
+		return rl.waitError(context.DeadlineExceeded)
+	}
+	rl.waits++
+	rl.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		rl.mu.Lock()
+		rl.allowed++
+		rl.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		// Give the reserved token back to the callers queued behind us.
+		rl.mu.Lock()
+		rl.refillLocked(time.Now())
+		rl.tokens = math.Min(rl.tokens+1, rl.burst)
+		rl.rejected++
+		rl.mu.Unlock()
+		return rl.waitError(ctx.Err())
+	}
+}
+
+// Tokens returns the number of tokens available now.
+func (rl *RateLimiter) Tokens() float64 {
+	return rl.Stats().Tokens
+}
+
+// Stats returns a snapshot of the limiter counters.
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.rps > 0 {
+		rl.refillLocked(time.Now())
+	}
+	return RateLimiterStats{
+		Tokens:   rl.tokens,
+		Allowed:  rl.allowed,
+		Waits:    rl.waits,
+		Rejected: rl.rejected,
+	}
+}
+
+// refillLocked adds the tokens earned since the last refill.
+// Must be called under lock.
+func (rl *RateLimiter) refillLocked(now time.Time) {
+	elapsed := now.Sub(rl.last).Seconds()
+	rl.last = now
+	if elapsed <= 0 {
+		return
+	}
+	rl.tokens = math.Min(rl.tokens+elapsed*rl.rps, rl.burst)
+}
+
+func (rl *RateLimiter) waitError(cause error) error {
+	return errors.New(errors.CodeRateLimit, "rate limit wait aborted", cause).
+		WithContext("rps", rl.rps).
+		WithRecoverable(true)
+}
+
+// WithRateLimit waits for a token from limiter and then runs fn. A nil
+// limiter runs fn directly.
+func WithRateLimit(ctx context.Context, limiter *RateLimiter, fn func() error) error {
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return fn()
+}
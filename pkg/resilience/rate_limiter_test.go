@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kerrors "github.com/jllopis/kairos/pkg/errors"
+)
+
+func TestRateLimiter_AllowBurst(t *testing.T) {
+	rl := NewRateLimiter(1, 3)
+	for i := 0; i < 3; i++ {
+		if !rl.Allow() {
+			t.Fatalf("expected call %d within burst to be allowed", i+1)
+		}
+	}
+	if rl.Allow() {
+		t.Fatalf("expected call beyond burst to be rejected")
+	}
+	stats := rl.Stats()
+	if stats.Allowed != 3 || stats.Rejected != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.Tokens >= 1 {
+		t.Fatalf("expected bucket to be empty, got %v tokens", stats.Tokens)
+	}
+}
+
+func TestRateLimiter_WaitRefills(t *testing.T) {
+	rl := NewRateLimiter(50, 1) // one token every 20ms
+	ctx := context.Background()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("first wait error: %v", err)
+	}
+	start := time.Now()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("second wait error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected second wait to block, took %v", elapsed)
+	}
+	if stats := rl.Stats(); stats.Waits != 1 || stats.Allowed != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiter_WaitHonorsContext(t *testing.T) {
+	rl := NewRateLimiter(0.5, 1) // one token every 2s
+	if !rl.Allow() {
+		t.Fatalf("expected first call to be allowed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := rl.Wait(ctx)
+	if err == nil {
+		t.Fatalf("expected wait to fail")
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("expected wait to give up early")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error in chain, got %v", err)
+	}
+	if code, ok := kerrors.CodeOf(err); !ok || code != kerrors.CodeRateLimit {
+		t.Fatalf("expected CodeRateLimit, got %v", code)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := rl.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+	if stats := rl.Stats(); stats.Rejected != 2 {
+		t.Fatalf("expected 2 rejected waits, got %+v", stats)
+	}
+	// The abandoned reservation is returned to the bucket.
+	if tokens := rl.Tokens(); tokens < 0 {
+		t.Fatalf("expected reservation to be released, got %v tokens", tokens)
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	rl := NewRateLimiter(0, 1)
+	for i := 0; i < 100; i++ {
+		if !rl.Allow() {
+			t.Fatalf("expected unlimited limiter to allow every call")
+		}
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	calls := 0
+	fn := func() error {
+		calls++
+		return nil
+	}
+	if err := WithRateLimit(context.Background(), nil, fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rl := NewRateLimiter(0.5, 1)
+	if err := WithRateLimit(context.Background(), rl, fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := WithRateLimit(ctx, rl, fn); err == nil {
+		t.Fatalf("expected rate limit error")
+	}
+	if calls != 2 {
+		t.Fatalf("expected fn to run twice, got %d", calls)
+	}
+}
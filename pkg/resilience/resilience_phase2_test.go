@@ -6,6 +6,7 @@ package resilience
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -152,6 +153,60 @@ func TestChainedFallback(t *testing.T) {
 	}
 }
 
+func TestConditionalFallback_RoutesByCode(t *testing.T) {
+	fallback := &ChainedFallback{
+		Fallbacks: []FallbackStrategy{
+			&ConditionalFallback{When: IsTimeout, Strategy: &CachedFallback{Cache: "cached"}},
+			&ConditionalFallback{
+				When:     OnCode(kerrors.CodeInvalidInput),
+				Strategy: &ErrorFallback{Message: "invalid request"},
+			},
+			&ConditionalFallback{
+				When:     OnCode(kerrors.CodeLLMError, kerrors.CodeRateLimit),
+				Strategy: &StaticFallback{Value: "default"},
+			},
+		},
+	}
+
+	timeoutErr := WithTimeout(context.Background(), TimeoutConfig{Duration: time.Millisecond}, func() error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	if value, err := fallback.Execute(context.Background(), timeoutErr); err != nil || value != "cached" {
+		t.Fatalf("expected cached value on timeout, got %v, %v", value, err)
+	}
+
+	// The invalid input error surfaces; later fallbacks do not mask it.
+	invalid := kerrors.New(kerrors.CodeInvalidInput, "bad input", nil)
+	value, err := fallback.Execute(context.Background(), invalid)
+	if ke := kerrors.AsKairosError(err); value != nil || ke == nil || ke.Message != "invalid request" || !errors.Is(err, invalid) {
+		t.Fatalf("expected error fallback wrapping the invalid input, got %v, %v", value, err)
+	}
+
+	wrapped := fmt.Errorf("chat: %w", kerrors.New(kerrors.CodeLLMError, "unavailable", nil))
+	if value, err := fallback.Execute(context.Background(), wrapped); err != nil || value != "default" {
+		t.Fatalf("expected default on LLM error, got %v, %v", value, err)
+	}
+
+	// No condition matches: the primary error is returned unchanged.
+	unmatched := errors.New("boom")
+	if _, err := fallback.Execute(context.Background(), unmatched); err != unmatched {
+		t.Fatalf("expected primary error, got %v", err)
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	if !IsTimeout(fmt.Errorf("call: %w", context.DeadlineExceeded)) {
+		t.Errorf("expected wrapped deadline to be a timeout")
+	}
+	if !IsTimeout(kerrors.New(kerrors.CodeTimeout, "slow", nil)) {
+		t.Errorf("expected CodeTimeout to be a timeout")
+	}
+	if IsTimeout(context.Canceled) || IsTimeout(kerrors.New(kerrors.CodeToolFailure, "failed", nil)) {
+		t.Errorf("expected cancellation and failures not to be timeouts")
+	}
+}
+
 func TestWithFallback(t *testing.T) {
 	fallback := &StaticFallback{Value: "default"}
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/clock"
 	kerrors "github.com/jllopis/kairos/pkg/errors"
 )
 
@@ -167,11 +168,13 @@ func TestCircuitBreakerOpen(t *testing.T) {
 }
 
 func TestCircuitBreakerHalfOpen(t *testing.T) {
+	fake := clock.NewFake()
 	cb := NewCircuitBreaker(CircuitBreakerConfig{
 		FailureThreshold: 1,
 		SuccessThreshold: 2,
 		Timeout:          100 * time.Millisecond,
 		Name:             "test",
+		Clock:            fake,
 	})
 
 	// Open the circuit
@@ -180,8 +183,14 @@ func TestCircuitBreakerHalfOpen(t *testing.T) {
 		t.Fatalf("expected circuit to be open")
 	}
 
+	// Still open until the timeout elapses
+	fake.Advance(100 * time.Millisecond)
+	if err := cb.Call(context.Background(), func() error { return nil }); err == nil {
+		t.Fatalf("expected circuit to stay open before the timeout")
+	}
+
 	// Wait for timeout to transition to half-open
-	time.Sleep(150 * time.Millisecond)
+	fake.Advance(50 * time.Millisecond)
 	_ = cb.Call(context.Background(), func() error { return nil })
 
 	if cb.State() != StateHalfOpen {
@@ -243,3 +252,44 @@ func TestKairosErrorRecoverable(t *testing.T) {
 		t.Errorf("expected 2 attempts, got %d", attempts)
 	}
 }
+
+func TestRetryBackoffUsesClock(t *testing.T) {
+	fake := clock.NewFake()
+	config := RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: time.Second,
+		MaxDelay:     time.Minute,
+		Multiplier:   2,
+	}.WithClock(fake)
+
+	attempts := make(chan int, 3)
+	done := make(chan error, 1)
+	go func() {
+		n := 0
+		done <- config.Do(context.Background(), func() error {
+			n++
+			attempts <- n
+			if n < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+	}()
+
+	<-attempts
+	// Backoff is InitialDelay * Multiplier^attempt: 2s, then 4s.
+	for _, delay := range []time.Duration{2 * time.Second, 4 * time.Second} {
+		fake.BlockUntil(1)
+		fake.Advance(delay - time.Millisecond)
+		select {
+		case <-attempts:
+			t.Fatalf("retried before the %v backoff elapsed", delay)
+		default:
+		}
+		fake.Advance(time.Millisecond)
+		<-attempts
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
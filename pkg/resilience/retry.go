@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/clock"
 	"github.com/jllopis/kairos/pkg/errors"
 )
 
@@ -33,6 +34,9 @@ type RetryConfig struct {
 	// Jitter adds randomness to backoff to prevent thundering herd.
 	// Value between 0 and 1; 0.1 means ±10% jitter.
 	Jitter float64
+
+	// Clock waits out the backoff delays. If nil, the real clock is used.
+	Clock clock.Clock
 }
 
 // DefaultRetryConfig returns a sensible default retry configuration.
@@ -71,6 +75,12 @@ func (rc RetryConfig) WithIsRecoverable(fn func(error) bool) RetryConfig {
 	return rc
 }
 
+// WithClock returns a new config with Clock set.
+func (rc RetryConfig) WithClock(c clock.Clock) RetryConfig {
+	rc.Clock = c
+	return rc
+}
+
 // Do executes fn with retry logic, returning the last error if all attempts fail.
 func (rc RetryConfig) Do(ctx context.Context, fn func() error) error {
 	if rc.MaxAttempts < 1 {
@@ -79,6 +89,7 @@ func (rc RetryConfig) Do(ctx context.Context, fn func() error) error {
 	if rc.IsRecoverable == nil {
 		rc.IsRecoverable = isRecoverableDefault
 	}
+	rc.Clock = clock.OrReal(rc.Clock)
 
 	var lastErr error
 	for attempt := 0; attempt < rc.MaxAttempts; attempt++ {
@@ -90,7 +101,7 @@ func (rc RetryConfig) Do(ctx context.Context, fn func() error) error {
 				return errors.New(errors.CodeContextLost, "context canceled during retry", ctx.Err()).
 					WithContext("attempt", attempt).
 					WithContext("max_attempts", rc.MaxAttempts)
-			case <-time.After(delay):
+			case <-rc.Clock.After(delay):
 				// Proceed to retry
 			}
 		}
@@ -157,8 +168,8 @@ func isRecoverableDefault(err error) bool {
 	}
 
 	// Check if it's a KairosError with explicit recoverable flag
-	if ke, ok := err.(*errors.KairosError); ok {
-		return ke.Recoverable
+	if _, ok := errors.CodeOf(err); ok {
+		return errors.IsRecoverable(err)
 	}
 
 	// Default: all generic errors are considered recoverable for backward compatibility
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	klog "github.com/jllopis/kairos/pkg/log"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -39,7 +40,7 @@ func (r *LocalRuntime) SetApprovalSweepTimeout(timeout time.Duration) {
 
 func (r *LocalRuntime) startApprovalSweeper() {
 	if r.approvalSweepInterval <= 0 || len(r.approvalExpirers) == 0 {
-		log := slog.Default()
+		log := klog.For("runtime")
 		log.Info("runtime.approval.sweeper.disabled",
 			slog.Duration("interval", r.approvalSweepInterval),
 			slog.Int("expirers", len(r.approvalExpirers)),
@@ -58,7 +59,7 @@ func (r *LocalRuntime) startApprovalSweeper() {
 		defer close(done)
 		ticker := time.NewTicker(r.approvalSweepInterval)
 		defer ticker.Stop()
-		log := slog.Default()
+		log := klog.For("runtime")
 		log.Info("runtime.approval.sweeper.start",
 			slog.Duration("interval", r.approvalSweepInterval),
 			slog.Int("expirers", len(r.approvalExpirers)),
@@ -9,6 +9,7 @@ import (
 
 	"github.com/jllopis/kairos/pkg/config"
 	"github.com/jllopis/kairos/pkg/core"
+	klog "github.com/jllopis/kairos/pkg/log"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -75,7 +76,7 @@ func (r *LocalRuntime) Run(ctx context.Context, agent core.Agent, input any) (an
 	if r.tracer == nil {
 		r.tracer = otel.Tracer("kairos/runtime")
 	}
-	log := slog.Default()
+	log := klog.For("runtime")
 	log.Info("runtime.run.start",
 		slog.String("agent_id", agent.ID()),
 		slog.String("run_id", runID),
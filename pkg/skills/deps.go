@@ -0,0 +1,69 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package skills
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrMissingDependency is returned when a skill requires a skill that
+	// was not loaded.
+	ErrMissingDependency = errors.New("missing skill dependency")
+	// ErrDependencyCycle is returned when skills require each other.
+	ErrDependencyCycle = errors.New("skill dependency cycle")
+)
+
+// SortByDependencies orders skills so that every skill comes after the
+// skills it requires. Unrelated skills keep their relative order.
+func SortByDependencies(specs []SkillSpec) ([]SkillSpec, error) {
+	byName := make(map[string]int, len(specs))
+	for i, spec := range specs {
+		if _, ok := byName[spec.Name]; ok {
+			return nil, fmt.Errorf("duplicate skill %q", spec.Name)
+		}
+		byName[spec.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(specs))
+	out := make([]SkillSpec, 0, len(specs))
+	var path []string
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, strings.Join(path, " -> "), specs[i].Name)
+		}
+		state[i] = visiting
+		path = append(path, specs[i].Name)
+		for _, dep := range specs[i].Requires {
+			j, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("%w: %q requires %q", ErrMissingDependency, specs[i].Name, dep)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = done
+		out = append(out, specs[i])
+		return nil
+	}
+	for i := range specs {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
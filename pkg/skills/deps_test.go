@@ -0,0 +1,98 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package skills
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSkill(t *testing.T, root, name, requires, body string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := "---\nname: " + name + "\ndescription: Skill " + name + ".\n"
+	if requires != "" {
+		content += "requires: [" + requires + "]\n"
+	}
+	content += "---\n\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestLoadToolsFromDir_DependencyChain(t *testing.T) {
+	root := t.TempDir()
+	writeSkill(t, root, "a", "b", "Instructions for a.")
+	writeSkill(t, root, "b", "c", "Instructions for b.")
+	writeSkill(t, root, "c", "", "Instructions for c.")
+
+	tools, err := LoadToolsFromDir(root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var order []string
+	for _, tool := range tools {
+		order = append(order, tool.Name())
+	}
+	if got := strings.Join(order, ","); got != "c,b,a" {
+		t.Fatalf("expected dependency order c,b,a, got %s", got)
+	}
+
+	result, err := tools[2].Call(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	resp := result.(*SkillResponse)
+	if resp.Instructions != "Instructions for a." {
+		t.Fatalf("unexpected instructions: %q", resp.Instructions)
+	}
+	if len(resp.Dependencies) != 2 || resp.Dependencies[0].Name != "c" || resp.Dependencies[1].Name != "b" {
+		t.Fatalf("expected dependencies c, b; got %+v", resp.Dependencies)
+	}
+	if resp.Dependencies[0].Instructions != "Instructions for c." {
+		t.Fatalf("unexpected dependency instructions: %q", resp.Dependencies[0].Instructions)
+	}
+	for _, tool := range tools {
+		if !tool.IsActivated() {
+			t.Fatalf("expected %s to be activated", tool.Name())
+		}
+	}
+}
+
+func TestLoadDir_DependencyErrors(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		root := t.TempDir()
+		writeSkill(t, root, "a", "ghost", "a")
+		if _, err := LoadDir(root); !errors.Is(err, ErrMissingDependency) {
+			t.Fatalf("expected ErrMissingDependency, got %v", err)
+		}
+	})
+	t.Run("cycle", func(t *testing.T) {
+		root := t.TempDir()
+		writeSkill(t, root, "a", "b", "a")
+		writeSkill(t, root, "b", "c", "b")
+		writeSkill(t, root, "c", "a", "c")
+		_, err := LoadDir(root)
+		if !errors.Is(err, ErrDependencyCycle) {
+			t.Fatalf("expected ErrDependencyCycle, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+			t.Fatalf("expected cycle path in error, got %v", err)
+		}
+	})
+	t.Run("self", func(t *testing.T) {
+		root := t.TempDir()
+		writeSkill(t, root, "a", "a", "a")
+		if _, err := LoadDir(root); err == nil {
+			t.Fatalf("expected error for self dependency")
+		}
+	})
+}
@@ -20,6 +20,7 @@ type SkillSpec struct {
 	Compatibility string
 	Metadata      map[string]string
 	AllowedTools  []string
+	Requires      []string
 	Body          string
 	Path          string
 	Dir           string
@@ -54,7 +55,7 @@ func LoadDir(root string) ([]SkillSpec, error) {
 		}
 		out = append(out, skill)
 	}
-	return out, nil
+	return SortByDependencies(out)
 }
 
 // LoadFile parses a single SKILL.md file.
@@ -84,6 +85,7 @@ func LoadFile(path string) (SkillSpec, error) {
 		Compatibility: parsed.Compatibility,
 		Metadata:      parsed.Metadata,
 		AllowedTools:  allowed,
+		Requires:      dedupe(parsed.Requires),
 		Body:          strings.TrimSpace(body),
 		Path:          path,
 		Dir:           dir,
@@ -101,6 +103,7 @@ type frontmatter struct {
 	Compatibility string            `yaml:"compatibility"`
 	Metadata      map[string]string `yaml:"metadata"`
 	AllowedTools  any               `yaml:"allowed-tools"`
+	Requires      []string          `yaml:"requires"`
 }
 
 func splitFrontmatter(content string) (string, string, error) {
@@ -143,6 +146,14 @@ func validate(spec SkillSpec) error {
 	if compat != "" && utf8.RuneCountInString(compat) > maxCompatLen {
 		return fmt.Errorf("compatibility exceeds %d characters", maxCompatLen)
 	}
+	for _, dep := range spec.Requires {
+		if !namePattern.MatchString(dep) {
+			return fmt.Errorf("requires: invalid skill name %q", dep)
+		}
+		if dep == name {
+			return fmt.Errorf("requires: skill %q cannot require itself", name)
+		}
+	}
 	return nil
 }
 
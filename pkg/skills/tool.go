@@ -20,6 +20,7 @@ import (
 type SkillTool struct {
 	spec      SkillSpec
 	activated bool
+	requires  []*SkillTool
 }
 
 // NewSkillTool creates a SkillTool from a SkillSpec.
@@ -84,10 +85,33 @@ type SkillResponse struct {
 	Name         string   `json:"name"`
 	Instructions string   `json:"instructions"`
 	Resources    []string `json:"resources,omitempty"`
+	// Dependencies holds the instructions of the required skills,
+	// transitively, with each skill after the skills it requires.
+	Dependencies []*SkillResponse `json:"dependencies,omitempty"`
 }
 
-// activate returns the skill body (instructions) for the LLM.
+// activate returns the skill body (instructions) for the LLM, together with
+// the instructions of the skills it requires, which are activated too.
 func (s *SkillTool) activate() (*SkillResponse, error) {
+	resp := s.response()
+	seen := map[*SkillTool]bool{s: true}
+	var collect func(tool *SkillTool)
+	collect = func(tool *SkillTool) {
+		for _, dep := range tool.requires {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			collect(dep)
+			dep.activated = true
+			resp.Dependencies = append(resp.Dependencies, dep.response())
+		}
+	}
+	collect(s)
+	return resp, nil
+}
+
+func (s *SkillTool) response() *SkillResponse {
 	resources, _ := s.listResources()
 	resourceList, _ := resources.([]string)
 
@@ -95,7 +119,7 @@ func (s *SkillTool) activate() (*SkillResponse, error) {
 		Name:         s.spec.Name,
 		Instructions: s.spec.Body,
 		Resources:    resourceList,
-	}, nil
+	}
 }
 
 // loadResource loads a specific resource file from the skill directory.
@@ -186,7 +210,9 @@ func (s *SkillTool) Spec() SkillSpec {
 	return s.spec
 }
 
-// LoadToolsFromDir loads skills from a directory and returns them as SkillTools.
+// LoadToolsFromDir loads skills from a directory and returns them as
+// SkillTools in dependency order. Activating a skill also returns the
+// instructions of the skills it requires.
 func LoadToolsFromDir(root string) ([]*SkillTool, error) {
 	specs, err := LoadDir(root)
 	if err != nil {
@@ -194,8 +220,16 @@ func LoadToolsFromDir(root string) ([]*SkillTool, error) {
 	}
 
 	tools := make([]*SkillTool, len(specs))
+	byName := make(map[string]*SkillTool, len(specs))
 	for i, spec := range specs {
 		tools[i] = NewSkillTool(spec)
+		byName[spec.Name] = tools[i]
+	}
+	// LoadDir already checked that every dependency exists.
+	for _, tool := range tools {
+		for _, dep := range tool.spec.Requires {
+			tool.requires = append(tool.requires, byName[dep])
+		}
 	}
 
 	return tools, nil
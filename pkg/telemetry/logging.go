@@ -9,14 +9,17 @@ import (
 	"log/slog"
 	"strings"
 
+	klog "github.com/jllopis/kairos/pkg/log"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // ConfigureSlog sets the global slog logger with trace-aware attributes.
+// It is also installed as the Kairos library logger (see package log).
 func ConfigureSlog(output io.Writer, level, format string) *slog.Logger {
 	handler := newSlogHandler(output, level, format)
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
+	klog.SetDefault(logger)
 	return logger
 }
 
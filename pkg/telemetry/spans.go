@@ -0,0 +1,53 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span names used by the agent loop helpers.
+const (
+	SpanAgentIteration = "Agent.Iteration"
+	SpanAgentToolCall  = "Agent.Tool.Call"
+)
+
+type iterationKey struct{}
+
+func agentTracer() trace.Tracer {
+	return otel.Tracer("kairos/agent")
+}
+
+// StartIterationSpan starts a span for reasoning iteration n (1-based) as a
+// child of the span in ctx. Tool spans started from the returned context
+// nest under it and inherit the iteration number.
+func StartIterationSpan(ctx context.Context, n int, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append([]attribute.KeyValue{attribute.Int(AttrAgentIteration, n)}, attrs...)
+	ctx, span := agentTracer().Start(ctx, SpanAgentIteration, trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, iterationKey{}, n), span
+}
+
+// StartToolSpan starts a span for a call to toolName as a child of the span
+// in ctx, tagged with the current iteration when there is one.
+func StartToolSpan(ctx context.Context, toolName string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	base := []attribute.KeyValue{attribute.String(AttrToolName, toolName)}
+	if n, ok := ctx.Value(iterationKey{}).(int); ok {
+		base = append(base, attribute.Int(AttrAgentIteration, n))
+	}
+	return agentTracer().Start(ctx, SpanAgentToolCall, trace.WithAttributes(append(base, attrs...)...))
+}
+
+// EndSpan records err on span, if any, and ends it with opts.
+func EndSpan(span trace.Span, err error, opts ...trace.SpanEndOption) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(opts...)
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestIterationAndToolSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "Agent.Run")
+	iterCtx, iterSpan := StartIterationSpan(ctx, 2)
+	_, toolSpan := StartToolSpan(iterCtx, "search")
+	EndSpan(toolSpan, errors.New("boom"))
+	EndSpan(iterSpan, nil)
+	root.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	tool, iter := spans[0], spans[1]
+	if tool.Name() != SpanAgentToolCall || iter.Name() != SpanAgentIteration {
+		t.Fatalf("unexpected span names: %q, %q", tool.Name(), iter.Name())
+	}
+	if iter.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("iteration span is not a child of the run span")
+	}
+	if tool.Parent().SpanID() != iter.SpanContext().SpanID() {
+		t.Fatalf("tool span is not a child of the iteration span")
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range tool.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs[AttrToolName].AsString() != "search" {
+		t.Fatalf("expected tool name attribute, got %v", attrs[AttrToolName])
+	}
+	if attrs[AttrAgentIteration].AsInt64() != 2 {
+		t.Fatalf("expected iteration 2 on tool span, got %v", attrs[AttrAgentIteration])
+	}
+	if tool.Status().Code != codes.Error || len(tool.Events()) == 0 {
+		t.Fatalf("expected error status and recorded error, got %v", tool.Status())
+	}
+	if iter.Status().Code == codes.Error {
+		t.Fatalf("iteration span should not be marked as error")
+	}
+}
+
+func TestStartToolSpan_WithoutIteration(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	_, span := StartToolSpan(context.Background(), "search")
+	EndSpan(span, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == AttrAgentIteration {
+			t.Fatalf("unexpected iteration attribute outside an iteration")
+		}
+	}
+}
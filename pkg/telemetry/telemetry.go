@@ -6,10 +6,11 @@ package telemetry
 import (
 	"context"
 	"encoding/base64"
+	stderrors "errors"
 	"fmt"
-	"log/slog"
 	"time"
 
+	klog "github.com/jllopis/kairos/pkg/log"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
@@ -198,7 +199,8 @@ func RecordError(span trace.Span, err error) {
 	span.RecordError(err)
 
 	// Extract KairosError for rich context
-	if ke, ok := err.(*errors.KairosError); ok {
+	var ke *errors.KairosError
+	if stderrors.As(err, &ke) {
 		// Add error code and recoverable flag as attributes
 		span.SetAttributes(
 			attribute.String("error.code", string(ke.Code)),
@@ -216,7 +218,7 @@ func RecordError(span trace.Span, err error) {
 		}
 
 		// Log structured error data
-		slog.Error("KairosError recorded",
+		klog.For("telemetry").Error("KairosError recorded",
 			"code", ke.Code,
 			"message", ke.Message,
 			"recoverable", ke.Recoverable,
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Tool call outcomes recorded in the "outcome" attribute.
+const (
+	ToolOutcomeSuccess = "success"
+	ToolOutcomeError   = "error"
+)
+
+// ToolMetrics tracks tool execution latency and success rate.
+//
+// It records the histogram kairos.tool.duration (milliseconds) and the
+// counter kairos.tool.calls, both labeled with tool.name, outcome and
+// component, and the counter kairos.tool.retries, labeled with tool.name
+// and component.
+type ToolMetrics struct {
+	duration  metric.Float64Histogram
+	calls     metric.Int64Counter
+	retries   metric.Int64Counter
+	component string
+}
+
+// ToolMetricsOption configures ToolMetrics.
+type ToolMetricsOption func(*ToolMetrics)
+
+// WithToolMetricsComponent sets the component attribute (default "agent"),
+// so tool calls measured at several layers can be told apart.
+func WithToolMetricsComponent(component string) ToolMetricsOption {
+	return func(tm *ToolMetrics) {
+		if component != "" {
+			tm.component = component
+		}
+	}
+}
+
+// NewToolMetrics creates a tool metrics tracker with OTEL meters.
+func NewToolMetrics(ctx context.Context, opts ...ToolMetricsOption) (*ToolMetrics, error) {
+	meter := otel.Meter("kairos/tools")
+
+	duration, err := meter.Float64Histogram(
+		"kairos.tool.duration",
+		metric.WithDescription("Tool call duration by tool and outcome"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, err := meter.Int64Counter(
+		"kairos.tool.calls",
+		metric.WithDescription("Tool calls by tool and outcome"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	retries, err := meter.Int64Counter(
+		"kairos.tool.retries",
+		metric.WithDescription("Tool call attempts repeated after a failure"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tm := &ToolMetrics{
+		duration:  duration,
+		calls:     calls,
+		retries:   retries,
+		component: "agent",
+	}
+	for _, opt := range opts {
+		opt(tm)
+	}
+	return tm, nil
+}
+
+// RecordToolCall records one tool call. A nil err counts as a success.
+func (tm *ToolMetrics) RecordToolCall(ctx context.Context, toolName string, duration time.Duration, err error) {
+	if tm == nil {
+		return
+	}
+	outcome := ToolOutcomeSuccess
+	if err != nil {
+		outcome = ToolOutcomeError
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("tool.name", toolName),
+		attribute.String("outcome", outcome),
+		attribute.String("component", tm.component),
+	)
+	tm.duration.Record(ctx, float64(duration)/float64(time.Millisecond), attrs)
+	tm.calls.Add(ctx, 1, attrs)
+}
+
+// RecordToolRetry records that a call to toolName is being retried.
+func (tm *ToolMetrics) RecordToolRetry(ctx context.Context, toolName string) {
+	if tm == nil {
+		return
+	}
+	tm.retries.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("tool.name", toolName),
+		attribute.String("component", tm.component),
+	))
+}
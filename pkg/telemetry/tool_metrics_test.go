@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestToolMetrics_RecordToolCall(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	ctx := context.Background()
+	tm, err := NewToolMetrics(ctx, WithToolMetricsComponent("mcp"))
+	if err != nil {
+		t.Fatalf("NewToolMetrics error: %v", err)
+	}
+	tm.RecordToolCall(ctx, "search", 20*time.Millisecond, nil)
+	tm.RecordToolCall(ctx, "search", 40*time.Millisecond, nil)
+	tm.RecordToolCall(ctx, "search", 5*time.Millisecond, errors.New("boom"))
+	tm.RecordToolRetry(ctx, "search")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	calls := map[string]int64{}
+	var retries int64
+	var durationCount uint64
+	var durationSum float64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				if m.Name == "kairos.tool.retries" {
+					for _, dp := range data.DataPoints {
+						retries += dp.Value
+					}
+					continue
+				}
+				if m.Name != "kairos.tool.calls" {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					outcome, _ := dp.Attributes.Value(attribute.Key("outcome"))
+					component, _ := dp.Attributes.Value(attribute.Key("component"))
+					if component.AsString() != "mcp" {
+						t.Errorf("unexpected component %q", component.AsString())
+					}
+					calls[outcome.AsString()] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				if m.Name != "kairos.tool.duration" {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					durationCount += dp.Count
+					durationSum += dp.Sum
+				}
+			}
+		}
+	}
+	if calls[ToolOutcomeSuccess] != 2 || calls[ToolOutcomeError] != 1 {
+		t.Fatalf("unexpected call counts: %v", calls)
+	}
+	if retries != 1 {
+		t.Fatalf("expected 1 retry, got %d", retries)
+	}
+	if durationCount != 3 || durationSum != 65 {
+		t.Fatalf("expected 3 durations summing 65ms, got %d / %v", durationCount, durationSum)
+	}
+
+	// Nil metrics should not panic
+	var nilMetrics *ToolMetrics
+	nilMetrics.RecordToolCall(ctx, "search", time.Millisecond, nil)
+	nilMetrics.RecordToolRetry(ctx, "search")
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jllopis/kairos/pkg/core"
 	"github.com/jllopis/kairos/pkg/llm"
 )
 
@@ -437,3 +438,108 @@ func FormatToolCalls(calls []llm.ToolCall) string {
 	}
 	return fmt.Sprintf("[%s]", strings.Join(names, ", "))
 }
+
+// Event assertions work off the events an agent emits, collected with
+// agent.WithEventListener(collector.Collect).
+
+// AssertToolCalled asserts the agent started a call to the named tool.
+func AssertToolCalled(t testing.TB, collector *EventCollector, name string) bool {
+	t.Helper()
+	for _, call := range toolCallsFrom(collector) {
+		if call.name == name {
+			return true
+		}
+	}
+	t.Errorf("expected tool %q to be called\nactual tool calls:\n%s", name, formatEventToolCalls(toolCallsFrom(collector)))
+	return false
+}
+
+// AssertToolCalledWith asserts the agent started a call to the named tool
+// whose arguments, as sent by the model, satisfy argMatcher.
+func AssertToolCalledWith(t testing.TB, collector *EventCollector, name string, argMatcher StringMatcher) bool {
+	t.Helper()
+	calls := toolCallsFrom(collector)
+	for _, call := range calls {
+		if call.name == name && argMatcher.Match(call.args) {
+			return true
+		}
+	}
+	t.Errorf("expected tool %q to be called with arguments that %s\nactual tool calls:\n%s",
+		name, argMatcher.Description(), formatEventToolCalls(calls))
+	return false
+}
+
+// AssertEventOrder asserts the given event types were emitted in this
+// order. Other events may appear in between.
+func AssertEventOrder(t testing.TB, collector *EventCollector, types ...core.EventType) bool {
+	t.Helper()
+	actual := collector.EventTypes()
+	next := 0
+	for _, typ := range actual {
+		if next < len(types) && typ == types[next] {
+			next++
+		}
+	}
+	if next == len(types) {
+		return true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "events out of order: %q not found after %d matched events\nexpected:\n", types[next], next)
+	for i, typ := range types {
+		mark := "  "
+		if i < next {
+			mark = "✓ "
+		} else if i == next {
+			mark = "✗ "
+		}
+		fmt.Fprintf(&b, "  %s%s\n", mark, typ)
+	}
+	b.WriteString("actual:\n")
+	if len(actual) == 0 {
+		b.WriteString("    (none)\n")
+	}
+	for _, typ := range actual {
+		fmt.Fprintf(&b, "    %s\n", typ)
+	}
+	t.Error(strings.TrimSuffix(b.String(), "\n"))
+	return false
+}
+
+// eventToolCall is a tool call read from an agent.tool_call.started event.
+type eventToolCall struct {
+	name string
+	args string
+}
+
+func toolCallsFrom(collector *EventCollector) []eventToolCall {
+	var calls []eventToolCall
+	for _, ev := range collector.Events() {
+		if ev.Type != core.EventAgentToolCallStarted {
+			continue
+		}
+		name, _ := ev.Payload["tool"].(string)
+		var args string
+		switch v := ev.Payload["arguments"].(type) {
+		case string:
+			args = v
+		case nil:
+		default:
+			data, _ := json.Marshal(v)
+			args = string(data)
+		}
+		calls = append(calls, eventToolCall{name: name, args: args})
+	}
+	return calls
+}
+
+func formatEventToolCalls(calls []eventToolCall) string {
+	if len(calls) == 0 {
+		return "    (none)"
+	}
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = fmt.Sprintf("    %d. %s(%s)", i+1, call.name, call.args)
+	}
+	return strings.Join(lines, "\n")
+}
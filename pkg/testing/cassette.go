@@ -0,0 +1,285 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// RecordEnv is the environment variable that switches recording providers
+// to record mode when set to "1" or "true".
+const RecordEnv = "KAIROS_RECORD"
+
+// ErrUnmatchedRequest is returned in replay mode when a request has no
+// recorded interaction left that matches it.
+var ErrUnmatchedRequest = errors.New("no recorded interaction matches request")
+
+// RecordMode selects whether a RecordingProvider calls the real provider or
+// replays a cassette.
+type RecordMode int
+
+const (
+	// ModeReplay answers from the cassette and never calls the inner provider.
+	ModeReplay RecordMode = iota
+	// ModeRecord calls the inner provider and writes every successful
+	// interaction to the cassette, replacing its previous contents.
+	ModeRecord
+)
+
+// Cassette is the on-disk format of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response it received.
+type Interaction struct {
+	Request  llm.ChatRequest  `json:"request"`
+	Response llm.ChatResponse `json:"response"`
+}
+
+// RecordingProvider records the responses of a real provider to a cassette
+// file and replays them deterministically, so full agent runs can be tested
+// without a live LLM.
+//
+// Requests are matched on their normalized messages and tool names: content
+// is trimmed, tool call IDs are ignored and tool call arguments are compared
+// as JSON values. Identical requests replay their recorded responses in
+// order.
+type RecordingProvider struct {
+	mu      sync.Mutex
+	inner   llm.Provider
+	path    string
+	mode    RecordMode
+	loaded  bool
+	loadErr error
+	records []Interaction
+	used    []bool
+}
+
+// NewRecordingProvider creates a provider backed by the cassette at
+// cassettePath. It replays by default and records when RecordEnv is set;
+// use WithMode to choose explicitly. inner is only called in record mode
+// and may be nil for replay-only tests.
+func NewRecordingProvider(inner llm.Provider, cassettePath string) *RecordingProvider {
+	mode := ModeReplay
+	switch strings.ToLower(os.Getenv(RecordEnv)) {
+	case "1", "true":
+		mode = ModeRecord
+	}
+	return &RecordingProvider{inner: inner, path: cassettePath, mode: mode}
+}
+
+// WithMode sets the record mode.
+func (p *RecordingProvider) WithMode(mode RecordMode) *RecordingProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mode = mode
+	return p
+}
+
+// Mode returns the record mode.
+func (p *RecordingProvider) Mode() RecordMode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mode
+}
+
+// Chat implements llm.Provider.
+func (p *RecordingProvider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.mu.Lock()
+	mode := p.mode
+	p.mu.Unlock()
+	if mode == ModeRecord {
+		return p.record(ctx, req)
+	}
+	return p.replay(req)
+}
+
+// record calls the inner provider and saves the interaction.
+func (p *RecordingProvider) record(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	if p.inner == nil {
+		return nil, errors.New("recording provider: record mode needs an inner provider")
+	}
+	// Callers such as the agent loop keep appending to the same slices.
+	req.Messages = slices.Clone(req.Messages)
+	req.Tools = slices.Clone(req.Tools)
+
+	resp, err := p.inner.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.records = append(p.records, Interaction{Request: req, Response: *resp})
+	if err := p.saveLocked(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay answers req from the cassette.
+func (p *RecordingProvider) replay(req llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.loadLocked(); err != nil {
+		return nil, err
+	}
+	key, err := requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+	for i, rec := range p.records {
+		if p.used[i] {
+			continue
+		}
+		recKey, err := requestKey(rec.Request)
+		if err != nil {
+			return nil, err
+		}
+		if recKey == key {
+			p.used[i] = true
+			resp := rec.Response
+			return &resp, nil
+		}
+	}
+	return nil, fmt.Errorf("%w in %s (last message: %q); set %s=1 to re-record",
+		ErrUnmatchedRequest, p.path, lastMessage(req), RecordEnv)
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (p *RecordingProvider) Interactions() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.records)
+}
+
+// Unused returns the recorded interactions that were not replayed. A
+// non-empty result after a run usually means the agent made fewer calls
+// than when the cassette was recorded.
+func (p *RecordingProvider) Unused() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []Interaction
+	for i, rec := range p.records {
+		if i < len(p.used) && !p.used[i] {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// loadLocked reads the cassette once. Must hold p.mu.
+func (p *RecordingProvider) loadLocked() error {
+	if p.loaded {
+		return p.loadErr
+	}
+	p.loaded = true
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		p.loadErr = fmt.Errorf("recording provider: reading cassette (set %s=1 to record): %w", RecordEnv, err)
+		return p.loadErr
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		p.loadErr = fmt.Errorf("recording provider: decoding cassette %s: %w", p.path, err)
+		return p.loadErr
+	}
+	p.records = cassette.Interactions
+	p.used = make([]bool, len(p.records))
+	return nil
+}
+
+// saveLocked writes every recorded interaction to the cassette. Must hold p.mu.
+func (p *RecordingProvider) saveLocked() error {
+	data, err := json.MarshalIndent(Cassette{Interactions: p.records}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("recording provider: encoding cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return fmt.Errorf("recording provider: %w", err)
+	}
+	if err := os.WriteFile(p.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("recording provider: writing cassette: %w", err)
+	}
+	return nil
+}
+
+// normalizedRequest is the part of a request used for matching.
+type normalizedRequest struct {
+	Messages       []normalizedMessage `json:"messages"`
+	Tools          []string            `json:"tools,omitempty"`
+	ResponseFormat *llm.ResponseFormat `json:"response_format,omitempty"`
+}
+
+type normalizedMessage struct {
+	Role      llm.Role             `json:"role"`
+	Content   string               `json:"content"`
+	ToolCalls []normalizedToolCall `json:"tool_calls,omitempty"`
+}
+
+type normalizedToolCall struct {
+	Name      string `json:"name"`
+	Arguments any    `json:"arguments"`
+}
+
+// requestKey returns the matching key of req.
+func requestKey(req llm.ChatRequest) (string, error) {
+	n := normalizedRequest{
+		Messages:       make([]normalizedMessage, 0, len(req.Messages)),
+		ResponseFormat: req.ResponseFormat,
+	}
+	for _, msg := range req.Messages {
+		nm := normalizedMessage{Role: msg.Role, Content: strings.TrimSpace(msg.Content)}
+		for _, call := range msg.ToolCalls {
+			nm.ToolCalls = append(nm.ToolCalls, normalizedToolCall{
+				Name:      call.Function.Name,
+				Arguments: normalizeArguments(call.Function.Arguments),
+			})
+		}
+		n.Messages = append(n.Messages, nm)
+	}
+	for _, tool := range req.Tools {
+		n.Tools = append(n.Tools, tool.Function.Name)
+	}
+	slices.Sort(n.Tools)
+	// Maps marshal with sorted keys, so equal arguments give equal keys.
+	data, err := json.Marshal(n)
+	if err != nil {
+		return "", fmt.Errorf("recording provider: normalizing request: %w", err)
+	}
+	return string(data), nil
+}
+
+// normalizeArguments decodes JSON arguments so formatting and key order do
+// not affect matching. Invalid JSON is compared as trimmed text.
+func normalizeArguments(args string) any {
+	var v any
+	if err := json.Unmarshal([]byte(args), &v); err != nil {
+		return strings.TrimSpace(args)
+	}
+	return v
+}
+
+// lastMessage summarizes the last message of req for error messages.
+func lastMessage(req llm.ChatRequest) string {
+	if len(req.Messages) == 0 {
+		return ""
+	}
+	content := strings.TrimSpace(req.Messages[len(req.Messages)-1].Content)
+	if len(content) > 80 {
+		content = content[:80] + "..."
+	}
+	return content
+}
@@ -6,9 +6,13 @@ package testing
 import (
 	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jllopis/kairos/pkg/agent"
 	"github.com/jllopis/kairos/pkg/core"
 	"github.com/jllopis/kairos/pkg/llm"
 )
@@ -327,3 +331,182 @@ func TestResponseAssertions(t *testing.T) {
 		HasToolCallCount(1).
 		HasToolCallNamed("search")
 }
+
+// echoTool implements core.Tool for agent runs.
+type echoTool struct{}
+
+func (echoTool) Name() string { return "echo" }
+func (echoTool) Call(_ context.Context, input any) (any, error) {
+	return input, nil
+}
+func (echoTool) ToolDefinition() llm.Tool {
+	return NewToolDefinition("echo").WithParameter("text", "string", "text to echo", true).Build()
+}
+
+func TestRecordingProviderRecordAndReplay(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassettes", "echo.json")
+	run := func(provider llm.Provider, input string) (any, error) {
+		t.Helper()
+		a, err := agent.New("echo-agent", provider,
+			agent.WithTools(echoTool{}),
+			agent.WithReasoningStrategy(agent.ReasoningNativeToolCalls),
+		)
+		if err != nil {
+			t.Fatalf("New error: %v", err)
+		}
+		return a.Run(context.Background(), input)
+	}
+
+	live := NewScenarioProvider().
+		AddToolCallResponse(NewToolCall("echo").WithID("call-1").WithArg("text", "hi").Build()).
+		AddResponse("Final Answer: hi")
+	recorder := NewRecordingProvider(live, cassette).WithMode(ModeRecord)
+	want, err := run(recorder, "say hi")
+	if err != nil {
+		t.Fatalf("record run error: %v", err)
+	}
+	if got := len(recorder.Interactions()); got != 2 {
+		t.Fatalf("expected 2 recorded interactions, got %d", got)
+	}
+
+	replayer := NewRecordingProvider(nil, cassette).WithMode(ModeReplay)
+	got, err := run(replayer, "say hi")
+	if err != nil {
+		t.Fatalf("replay run error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("replay returned %v, recorded %v", got, want)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Fatalf("expected every interaction replayed, %d left", len(unused))
+	}
+
+	_, err = run(NewRecordingProvider(nil, cassette).WithMode(ModeReplay), "say bye")
+	if !errors.Is(err, ErrUnmatchedRequest) {
+		t.Fatalf("expected ErrUnmatchedRequest, got %v", err)
+	}
+}
+
+func TestRecordingProviderMatchesNormalizedRequests(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	call := func(args string) llm.ChatRequest {
+		return llm.ChatRequest{Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: "weather?"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "a", Function: llm.FunctionCall{Name: "search", Arguments: args}}}},
+		}}
+	}
+	recorder := NewRecordingProvider(NewScenarioProvider().AddResponse("sunny"), cassette).WithMode(ModeRecord)
+	if _, err := recorder.Chat(context.Background(), call(`{"q":"weather","n":1}`)); err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+
+	replayer := NewRecordingProvider(nil, cassette).WithMode(ModeReplay)
+	req := call(`{"n": 1, "q": "weather"}`)
+	req.Messages[0].Content = "  weather?\n"
+	resp, err := replayer.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("replay error: %v", err)
+	}
+	if resp.Content != "sunny" {
+		t.Fatalf("unexpected response %q", resp.Content)
+	}
+	// Each interaction replays once.
+	if _, err := replayer.Chat(context.Background(), req); !errors.Is(err, ErrUnmatchedRequest) {
+		t.Fatalf("expected ErrUnmatchedRequest, got %v", err)
+	}
+	if _, err := NewRecordingProvider(nil, filepath.Join(t.TempDir(), "missing.json")).WithMode(ModeReplay).Chat(context.Background(), req); err == nil {
+		t.Fatal("expected error for a missing cassette")
+	}
+}
+
+func TestRecordingProviderMatchesResponseFormat(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	text := llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "weather?"}}}
+	jsonMode := text
+	jsonMode.ResponseFormat = &llm.ResponseFormat{Type: llm.ResponseFormatJSONObject}
+
+	scenario := NewScenarioProvider().AddResponse("sunny").AddResponse(`{"weather":"sunny"}`)
+	recorder := NewRecordingProvider(scenario, cassette).WithMode(ModeRecord)
+	for _, req := range []llm.ChatRequest{text, jsonMode} {
+		if _, err := recorder.Chat(context.Background(), req); err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+	}
+
+	replayer := NewRecordingProvider(nil, cassette).WithMode(ModeReplay)
+	resp, err := replayer.Chat(context.Background(), jsonMode)
+	if err != nil {
+		t.Fatalf("replay error: %v", err)
+	}
+	if resp.Content != `{"weather":"sunny"}` {
+		t.Fatalf("JSON request replayed the text response %q", resp.Content)
+	}
+	resp, err = replayer.Chat(context.Background(), text)
+	if err != nil {
+		t.Fatalf("replay error: %v", err)
+	}
+	if resp.Content != "sunny" {
+		t.Fatalf("text request replayed %q", resp.Content)
+	}
+}
+
+// failureRecorder captures assertion failures instead of failing the test.
+type failureRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *failureRecorder) Helper() {}
+func (r *failureRecorder) Error(args ...any) {
+	r.failures = append(r.failures, fmt.Sprint(args...))
+}
+func (r *failureRecorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestToolCallAssertions(t *testing.T) {
+	collector := NewEventCollector()
+	provider := NewScenarioProvider().
+		AddToolCallResponse(NewToolCall("echo").WithArg("text", "hi").Build()).
+		AddResponse("Final Answer: hi")
+	a, err := agent.New("echo-agent", provider,
+		agent.WithTools(echoTool{}),
+		agent.WithReasoningStrategy(agent.ReasoningNativeToolCalls),
+		agent.WithEventListener(collector.Collect),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if _, err := a.Run(context.Background(), "say hi"); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	AssertToolCalled(t, collector, "echo")
+	AssertToolCalledWith(t, collector, "echo", Contains(`"text":"hi"`))
+	AssertEventOrder(t, collector,
+		core.EventAgentTaskStarted,
+		core.EventAgentToolCallStarted,
+		core.EventAgentToolCallCompleted,
+		core.EventAgentTaskCompleted,
+	)
+
+	rec := &failureRecorder{TB: t}
+	if AssertToolCalled(rec, collector, "search") {
+		t.Fatal("expected AssertToolCalled to fail")
+	}
+	if AssertToolCalledWith(rec, collector, "echo", Contains("bye")) {
+		t.Fatal("expected AssertToolCalledWith to fail")
+	}
+	if AssertEventOrder(rec, collector, core.EventAgentTaskCompleted, core.EventAgentToolCallStarted) {
+		t.Fatal("expected AssertEventOrder to fail")
+	}
+	if len(rec.failures) != 3 {
+		t.Fatalf("expected 3 failures, got %d", len(rec.failures))
+	}
+	if !strings.Contains(rec.failures[0], `1. echo({"text":"hi"})`) {
+		t.Errorf("failure should list the actual tool calls:\n%s", rec.failures[0])
+	}
+	if !strings.Contains(rec.failures[2], "✗ agent.tool_call.started") || !strings.Contains(rec.failures[2], "actual:") {
+		t.Errorf("failure should show the expected and actual sequences:\n%s", rec.failures[2])
+	}
+}
@@ -72,6 +72,11 @@ func NewWithAPIKey(apiKey string, opts ...Option) *Provider {
 	return New(opts...)
 }
 
+// SupportsToolCalling implements llm.ToolCallingProvider.
+func (p *Provider) SupportsToolCalling() bool {
+	return true
+}
+
 // Chat implements llm.Provider.
 func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
 	model := req.Model
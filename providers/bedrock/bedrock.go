@@ -0,0 +1,386 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bedrock provides an Amazon Bedrock provider for Kairos.
+//
+// It uses the Bedrock Runtime Converse API, which exposes Anthropic Claude,
+// Amazon Titan/Nova and other Bedrock models through a single request
+// format, including tool use for models that support it. Credentials and
+// region are resolved with the standard AWS chain (environment, shared
+// config/credentials files, SSO, IAM roles).
+package bedrock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+// DefaultModel is the model used when neither the request nor WithModel
+// sets one.
+const DefaultModel = "anthropic.claude-3-5-haiku-20241022-v1:0"
+
+// runtimeClient is the subset of the Bedrock Runtime client used by the
+// provider.
+type runtimeClient interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+	ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error)
+}
+
+// Provider implements llm.Provider for Amazon Bedrock.
+type Provider struct {
+	client    runtimeClient
+	model     string
+	maxTokens int32
+	region    string
+}
+
+// Option configures the Provider.
+type Option func(*Provider)
+
+// WithModel sets the default model ID or inference profile ARN.
+func WithModel(model string) Option {
+	return func(p *Provider) {
+		p.model = model
+	}
+}
+
+// WithMaxTokens sets the maximum tokens for responses.
+func WithMaxTokens(tokens int32) Option {
+	return func(p *Provider) {
+		p.maxTokens = tokens
+	}
+}
+
+// WithRegion overrides the region resolved from the AWS configuration.
+func WithRegion(region string) Option {
+	return func(p *Provider) {
+		p.region = region
+	}
+}
+
+// WithClient uses an existing Bedrock Runtime client instead of loading the
+// default AWS configuration.
+func WithClient(client *bedrockruntime.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// New creates a new Bedrock provider. Unless WithClient is given, the AWS
+// configuration is loaded from the default credential chain.
+func New(ctx context.Context, opts ...Option) (*Provider, error) {
+	p := &Provider{
+		model:     DefaultModel,
+		maxTokens: 4096,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.client == nil {
+		var loadOpts []func(*config.LoadOptions) error
+		if p.region != "" {
+			loadOpts = append(loadOpts, config.WithRegion(p.region))
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("load aws config: %w", err)
+		}
+		p.client = bedrockruntime.NewFromConfig(cfg)
+	}
+	return p, nil
+}
+
+// SupportsToolCalling implements llm.ToolCallingProvider.
+func (p *Provider) SupportsToolCalling() bool {
+	return true
+}
+
+// Chat implements llm.Provider.
+func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	system, messages, err := convertMessages(req.Messages)
+	if err != nil {
+		return nil, err
+	}
+	input := &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(p.modelFor(req)),
+		Messages:        messages,
+		System:          system,
+		InferenceConfig: p.inferenceConfig(req),
+		ToolConfig:      convertTools(req.Tools),
+	}
+
+	out, err := p.client.Converse(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock converse failed: %w", err)
+	}
+	return convertResponse(out)
+}
+
+// ChatStream implements llm.StreamingProvider for streaming responses.
+func (p *Provider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	system, messages, err := convertMessages(req.Messages)
+	if err != nil {
+		return nil, err
+	}
+	input := &bedrockruntime.ConverseStreamInput{
+		ModelId:         aws.String(p.modelFor(req)),
+		Messages:        messages,
+		System:          system,
+		InferenceConfig: p.inferenceConfig(req),
+		ToolConfig:      convertTools(req.Tools),
+	}
+
+	out, err := p.client.ConverseStream(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock converse stream failed: %w", err)
+	}
+	stream := out.GetStream()
+
+	chunks := make(chan llm.StreamChunk, 100)
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+		if !processStream(ctx, stream.Events(), chunks) {
+			return
+		}
+		if err := stream.Err(); err != nil {
+			chunks <- llm.StreamChunk{Error: err}
+		}
+	}()
+	return chunks, nil
+}
+
+func (p *Provider) modelFor(req llm.ChatRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return p.model
+}
+
+func (p *Provider) inferenceConfig(req llm.ChatRequest) *types.InferenceConfiguration {
+	cfg := &types.InferenceConfiguration{}
+	if p.maxTokens > 0 {
+		cfg.MaxTokens = aws.Int32(p.maxTokens)
+	}
+	if req.Temperature > 0 {
+		cfg.Temperature = aws.Float32(float32(req.Temperature))
+	}
+	return cfg
+}
+
+// convertMessages splits system prompts from the conversation and converts
+// the rest to Bedrock messages. Consecutive messages with the same Bedrock
+// role are merged because the Converse API requires alternating roles
+// (for example, several tool results become one user message).
+func convertMessages(msgs []llm.Message) ([]types.SystemContentBlock, []types.Message, error) {
+	var system []types.SystemContentBlock
+	var out []types.Message
+	for _, msg := range msgs {
+		if msg.Role == llm.RoleSystem {
+			if msg.Content != "" {
+				system = append(system, &types.SystemContentBlockMemberText{Value: msg.Content})
+			}
+			continue
+		}
+		role, blocks, err := convertMessage(msg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1].Role == role {
+			out[n-1].Content = append(out[n-1].Content, blocks...)
+			continue
+		}
+		out = append(out, types.Message{Role: role, Content: blocks})
+	}
+	return system, out, nil
+}
+
+// convertMessage converts a Kairos message to a Bedrock role and content.
+func convertMessage(msg llm.Message) (types.ConversationRole, []types.ContentBlock, error) {
+	switch msg.Role {
+	case llm.RoleAssistant:
+		var blocks []types.ContentBlock
+		if msg.Content != "" {
+			blocks = append(blocks, &types.ContentBlockMemberText{Value: msg.Content})
+		}
+		for _, tc := range msg.ToolCalls {
+			input := map[string]any{}
+			if tc.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(tc.Function.Arguments), &input); err != nil {
+					return "", nil, fmt.Errorf("tool call %q arguments: %w", tc.Function.Name, err)
+				}
+			}
+			blocks = append(blocks, &types.ContentBlockMemberToolUse{Value: types.ToolUseBlock{
+				ToolUseId: aws.String(tc.ID),
+				Name:      aws.String(tc.Function.Name),
+				Input:     document.NewLazyDocument(input),
+			}})
+		}
+		return types.ConversationRoleAssistant, blocks, nil
+	case llm.RoleTool:
+		// Tool results are sent back as user content.
+		return types.ConversationRoleUser, []types.ContentBlock{
+			&types.ContentBlockMemberToolResult{Value: types.ToolResultBlock{
+				ToolUseId: aws.String(msg.ToolCallID),
+				Content: []types.ToolResultContentBlock{
+					&types.ToolResultContentBlockMemberText{Value: msg.Content},
+				},
+			}},
+		}, nil
+	default:
+		if msg.Content == "" {
+			return types.ConversationRoleUser, nil, nil
+		}
+		return types.ConversationRoleUser, []types.ContentBlock{
+			&types.ContentBlockMemberText{Value: msg.Content},
+		}, nil
+	}
+}
+
+// convertTools converts Kairos tools to a Bedrock tool configuration.
+func convertTools(tools []llm.Tool) *types.ToolConfiguration {
+	if len(tools) == 0 {
+		return nil
+	}
+	cfg := &types.ToolConfiguration{Tools: make([]types.Tool, 0, len(tools))}
+	for _, tool := range tools {
+		schema := tool.Function.Parameters
+		if schema == nil {
+			schema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		spec := types.ToolSpecification{
+			Name:        aws.String(tool.Function.Name),
+			InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(schema)},
+		}
+		if tool.Function.Description != "" {
+			spec.Description = aws.String(tool.Function.Description)
+		}
+		cfg.Tools = append(cfg.Tools, &types.ToolMemberToolSpec{Value: spec})
+	}
+	return cfg
+}
+
+// convertResponse converts a Converse response to Kairos format.
+func convertResponse(out *bedrockruntime.ConverseOutput) (*llm.ChatResponse, error) {
+	resp := &llm.ChatResponse{Usage: convertUsage(out.Usage)}
+	msg, ok := out.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return resp, nil
+	}
+	for _, block := range msg.Value.Content {
+		switch b := block.(type) {
+		case *types.ContentBlockMemberText:
+			resp.Content += b.Value
+		case *types.ContentBlockMemberToolUse:
+			args := "{}"
+			if b.Value.Input != nil {
+				raw, err := b.Value.Input.MarshalSmithyDocument()
+				if err != nil {
+					return nil, fmt.Errorf("tool use %q input: %w", aws.ToString(b.Value.Name), err)
+				}
+				args = string(raw)
+			}
+			resp.ToolCalls = append(resp.ToolCalls, llm.ToolCall{
+				ID:   aws.ToString(b.Value.ToolUseId),
+				Type: llm.ToolTypeFunction,
+				Function: llm.FunctionCall{
+					Name:      aws.ToString(b.Value.Name),
+					Arguments: args,
+				},
+			})
+		}
+	}
+	return resp, nil
+}
+
+func convertUsage(usage *types.TokenUsage) llm.Usage {
+	if usage == nil {
+		return llm.Usage{}
+	}
+	return llm.Usage{
+		PromptTokens:     int(aws.ToInt32(usage.InputTokens)),
+		CompletionTokens: int(aws.ToInt32(usage.OutputTokens)),
+		TotalTokens:      int(aws.ToInt32(usage.TotalTokens)),
+	}
+}
+
+// processStream translates Converse stream events into chunks. Tool calls
+// are accumulated and emitted on the final chunk, which also carries usage
+// when the metadata event arrives. It returns false if ctx was cancelled.
+func processStream(ctx context.Context, events <-chan types.ConverseStreamOutput, chunks chan<- llm.StreamChunk) bool {
+	var (
+		toolCalls []llm.ToolCall
+		current   *llm.ToolCall
+		stopped   bool
+	)
+	send := func(chunk llm.StreamChunk) bool {
+		select {
+		case chunks <- chunk:
+			return true
+		case <-ctx.Done():
+			chunks <- llm.StreamChunk{Error: ctx.Err()}
+			return false
+		}
+	}
+
+	for event := range events {
+		switch e := event.(type) {
+		case *types.ConverseStreamOutputMemberContentBlockStart:
+			if start, ok := e.Value.Start.(*types.ContentBlockStartMemberToolUse); ok {
+				current = &llm.ToolCall{
+					ID:       aws.ToString(start.Value.ToolUseId),
+					Type:     llm.ToolTypeFunction,
+					Function: llm.FunctionCall{Name: aws.ToString(start.Value.Name)},
+				}
+			}
+		case *types.ConverseStreamOutputMemberContentBlockDelta:
+			switch delta := e.Value.Delta.(type) {
+			case *types.ContentBlockDeltaMemberText:
+				if !send(llm.StreamChunk{Content: delta.Value}) {
+					return false
+				}
+			case *types.ContentBlockDeltaMemberToolUse:
+				if current != nil {
+					current.Function.Arguments += aws.ToString(delta.Value.Input)
+				}
+			}
+		case *types.ConverseStreamOutputMemberContentBlockStop:
+			if current != nil {
+				if current.Function.Arguments == "" {
+					current.Function.Arguments = "{}"
+				}
+				toolCalls = append(toolCalls, *current)
+				current = nil
+			}
+		case *types.ConverseStreamOutputMemberMessageStop:
+			stopped = true
+		case *types.ConverseStreamOutputMemberMetadata:
+			usage := convertUsage(e.Value.Usage)
+			if !send(llm.StreamChunk{Done: true, ToolCalls: toolCalls, Usage: &usage}) {
+				return false
+			}
+			return true
+		}
+	}
+	if stopped {
+		return send(llm.StreamChunk{Done: true, ToolCalls: toolCalls})
+	}
+	return true
+}
+
+// Ensure Provider implements llm.Provider.
+var _ llm.Provider = (*Provider)(nil)
+
+// Ensure Provider implements llm.StreamingProvider.
+var _ llm.StreamingProvider = (*Provider)(nil)
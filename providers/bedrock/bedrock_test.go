@@ -0,0 +1,189 @@
+// Copyright 2026 © The Kairos Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package bedrock
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/jllopis/kairos/pkg/llm"
+)
+
+type fakeClient struct {
+	input  *bedrockruntime.ConverseInput
+	output *bedrockruntime.ConverseOutput
+}
+
+func (f *fakeClient) Converse(_ context.Context, params *bedrockruntime.ConverseInput, _ ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+	f.input = params
+	return f.output, nil
+}
+
+func (f *fakeClient) ConverseStream(context.Context, *bedrockruntime.ConverseStreamInput, ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error) {
+	return nil, nil
+}
+
+func TestProviderImplementsInterface(t *testing.T) {
+	var _ llm.Provider = (*Provider)(nil)
+	var _ llm.StreamingProvider = (*Provider)(nil)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	p, err := New(context.Background(),
+		WithClient(bedrockruntime.New(bedrockruntime.Options{Region: "eu-west-1"})),
+		WithModel("amazon.titan-text-express-v1"),
+		WithMaxTokens(512),
+	)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if p.model != "amazon.titan-text-express-v1" || p.maxTokens != 512 {
+		t.Fatalf("unexpected provider config: %+v", p)
+	}
+}
+
+func TestChatConvertsRequestAndResponse(t *testing.T) {
+	client := &fakeClient{output: &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{Value: types.Message{
+			Role: types.ConversationRoleAssistant,
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{Value: "Checking the weather."},
+				&types.ContentBlockMemberToolUse{Value: types.ToolUseBlock{
+					ToolUseId: aws.String("tool-2"),
+					Name:      aws.String("get_weather"),
+					Input:     document.NewLazyDocument(map[string]any{"city": "Madrid"}),
+				}},
+			},
+		}},
+		Usage: &types.TokenUsage{
+			InputTokens:  aws.Int32(12),
+			OutputTokens: aws.Int32(8),
+			TotalTokens:  aws.Int32(20),
+		},
+	}}
+	p := &Provider{client: client, model: DefaultModel, maxTokens: 256}
+
+	resp, err := p.Chat(context.Background(), llm.ChatRequest{
+		Temperature: 0.2,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: "Be brief."},
+			{Role: llm.RoleUser, Content: "Weather in Madrid and Paris?"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+				{ID: "tool-1", Type: llm.ToolTypeFunction, Function: llm.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+			}},
+			{Role: llm.RoleTool, ToolCallID: "tool-1", Content: "sunny"},
+		},
+		Tools: []llm.Tool{{
+			Type: llm.ToolTypeFunction,
+			Function: llm.FunctionDef{
+				Name:        "get_weather",
+				Description: "Current weather",
+				Parameters:  map[string]any{"type": "object"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+
+	in := client.input
+	if aws.ToString(in.ModelId) != DefaultModel {
+		t.Errorf("unexpected model: %s", aws.ToString(in.ModelId))
+	}
+	if len(in.System) != 1 || len(in.Messages) != 3 {
+		t.Fatalf("unexpected conversation: %d system, %d messages", len(in.System), len(in.Messages))
+	}
+	if in.Messages[2].Role != types.ConversationRoleUser {
+		t.Errorf("tool result must be sent as user content, got %s", in.Messages[2].Role)
+	}
+	if aws.ToInt32(in.InferenceConfig.MaxTokens) != 256 || aws.ToFloat32(in.InferenceConfig.Temperature) != 0.2 {
+		t.Errorf("unexpected inference config: %+v", in.InferenceConfig)
+	}
+	if in.ToolConfig == nil || len(in.ToolConfig.Tools) != 1 {
+		t.Fatalf("expected one tool, got %+v", in.ToolConfig)
+	}
+
+	if resp.Content != "Checking the weather." {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "tool-2" || resp.ToolCalls[0].Function.Name != "get_weather" {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(resp.ToolCalls[0].Function.Arguments), &args); err != nil || args["city"] != "Madrid" {
+		t.Errorf("unexpected arguments: %s", resp.ToolCalls[0].Function.Arguments)
+	}
+	if resp.Usage != (llm.Usage{PromptTokens: 12, CompletionTokens: 8, TotalTokens: 20}) {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestConvertMessagesMergesToolResults(t *testing.T) {
+	_, msgs, err := convertMessages([]llm.Message{
+		{Role: llm.RoleUser, Content: "hi"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+			{ID: "a", Function: llm.FunctionCall{Name: "one"}},
+			{ID: "b", Function: llm.FunctionCall{Name: "two"}},
+		}},
+		{Role: llm.RoleTool, ToolCallID: "a", Content: "1"},
+		{Role: llm.RoleTool, ToolCallID: "b", Content: "2"},
+	})
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if len(msgs) != 3 || len(msgs[2].Content) != 2 {
+		t.Fatalf("expected tool results merged into one user message, got %+v", msgs)
+	}
+}
+
+func TestProcessStream(t *testing.T) {
+	events := make(chan types.ConverseStreamOutput, 10)
+	events <- &types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{
+		Delta: &types.ContentBlockDeltaMemberText{Value: "Hola"},
+	}}
+	events <- &types.ConverseStreamOutputMemberContentBlockStart{Value: types.ContentBlockStartEvent{
+		Start: &types.ContentBlockStartMemberToolUse{Value: types.ToolUseBlockStart{
+			ToolUseId: aws.String("t1"),
+			Name:      aws.String("lookup"),
+		}},
+	}}
+	events <- &types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{
+		Delta: &types.ContentBlockDeltaMemberToolUse{Value: types.ToolUseBlockDelta{Input: aws.String(`{"q":`)}},
+	}}
+	events <- &types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{
+		Delta: &types.ContentBlockDeltaMemberToolUse{Value: types.ToolUseBlockDelta{Input: aws.String(`"x"}`)}},
+	}}
+	events <- &types.ConverseStreamOutputMemberContentBlockStop{}
+	events <- &types.ConverseStreamOutputMemberMessageStop{}
+	events <- &types.ConverseStreamOutputMemberMetadata{Value: types.ConverseStreamMetadataEvent{
+		Usage: &types.TokenUsage{InputTokens: aws.Int32(3), OutputTokens: aws.Int32(4), TotalTokens: aws.Int32(7)},
+	}}
+	close(events)
+
+	chunks := make(chan llm.StreamChunk, 10)
+	if !processStream(context.Background(), events, chunks) {
+		t.Fatal("unexpected cancellation")
+	}
+	close(chunks)
+
+	var got []llm.StreamChunk
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	if len(got) != 2 || got[0].Content != "Hola" {
+		t.Fatalf("unexpected chunks: %+v", got)
+	}
+	final := got[1]
+	if !final.Done || final.Usage == nil || final.Usage.TotalTokens != 7 {
+		t.Fatalf("unexpected final chunk: %+v", final)
+	}
+	if len(final.ToolCalls) != 1 || final.ToolCalls[0].Function.Arguments != `{"q":"x"}` {
+		t.Fatalf("unexpected tool calls: %+v", final.ToolCalls)
+	}
+}
@@ -66,6 +66,16 @@ func NewWithAPIKey(ctx context.Context, apiKey string, opts ...Option) (*Provide
 	return p, nil
 }
 
+// SupportsToolCalling implements llm.ToolCallingProvider.
+func (p *Provider) SupportsToolCalling() bool {
+	return true
+}
+
+// SupportsResponseFormat implements llm.ResponseFormatProvider.
+func (p *Provider) SupportsResponseFormat(format llm.ResponseFormatType) bool {
+	return format == llm.ResponseFormatJSONObject || format == llm.ResponseFormatJSONSchema
+}
+
 // Chat implements llm.Provider.
 func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
 	model := req.Model
@@ -97,6 +107,10 @@ func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResp
 		}
 	}
 
+	if req.ResponseFormat != nil {
+		applyResponseFormat(config, *req.ResponseFormat)
+	}
+
 	// Make the API call
 	resp, err := p.client.Models.GenerateContent(ctx, model, contents, config)
 	if err != nil {
@@ -169,6 +183,22 @@ func convertMessages(messages []llm.Message) ([]*genai.Content, string) {
 	return contents, systemInstruction
 }
 
+// applyResponseFormat requests JSON output, constrained to the schema of a
+// JSON schema format.
+func applyResponseFormat(config *genai.GenerateContentConfig, format llm.ResponseFormat) {
+	switch format.Type {
+	case llm.ResponseFormatJSONObject:
+		config.ResponseMIMEType = "application/json"
+	case llm.ResponseFormatJSONSchema:
+		config.ResponseMIMEType = "application/json"
+		schemaJSON, _ := json.Marshal(format.Schema)
+		var schema *genai.Schema
+		if err := json.Unmarshal(schemaJSON, &schema); err == nil {
+			config.ResponseSchema = schema
+		}
+	}
+}
+
 // convertTools converts Kairos tools to Gemini function declarations.
 func convertTools(tools []llm.Tool) []*genai.FunctionDeclaration {
 	declarations := make([]*genai.FunctionDeclaration, 0, len(tools))
@@ -259,6 +289,10 @@ func (p *Provider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan
 		}
 	}
 
+	if req.ResponseFormat != nil {
+		applyResponseFormat(config, *req.ResponseFormat)
+	}
+
 	// Create output channel
 	chunks := make(chan llm.StreamChunk, 100)
 
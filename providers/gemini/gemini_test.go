@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/jllopis/kairos/pkg/llm"
+	"google.golang.org/genai"
 )
 
 func TestProviderImplementsInterface(t *testing.T) {
@@ -75,3 +76,22 @@ func TestClose(t *testing.T) {
 		t.Errorf("expected nil error, got %v", err)
 	}
 }
+
+func TestApplyResponseFormat(t *testing.T) {
+	var _ llm.ResponseFormatProvider = (*Provider)(nil)
+
+	config := &genai.GenerateContentConfig{}
+	applyResponseFormat(config, llm.ResponseFormat{
+		Type: llm.ResponseFormatJSONSchema,
+		Schema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"total": map[string]any{"type": "integer"}},
+		},
+	})
+	if config.ResponseMIMEType != "application/json" {
+		t.Fatalf("unexpected mime type %q", config.ResponseMIMEType)
+	}
+	if config.ResponseSchema == nil || config.ResponseSchema.Properties["total"] == nil {
+		t.Fatalf("expected the response schema, got %+v", config.ResponseSchema)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/shared"
 )
 
 // Provider implements llm.Provider for OpenAI API.
@@ -64,6 +65,16 @@ func NewWithAPIKey(apiKey string, opts ...Option) *Provider {
 	return New(opts...)
 }
 
+// SupportsToolCalling implements llm.ToolCallingProvider.
+func (p *Provider) SupportsToolCalling() bool {
+	return true
+}
+
+// SupportsResponseFormat implements llm.ResponseFormatProvider.
+func (p *Provider) SupportsResponseFormat(format llm.ResponseFormatType) bool {
+	return format == llm.ResponseFormatJSONObject || format == llm.ResponseFormatJSONSchema
+}
+
 // Chat implements llm.Provider.
 func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
 	model := req.Model
@@ -97,6 +108,10 @@ func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResp
 		params.Tools = tools
 	}
 
+	if req.ResponseFormat != nil {
+		params.ResponseFormat = convertResponseFormat(*req.ResponseFormat)
+	}
+
 	// Make the API call
 	completion, err := p.client.Chat.Completions.New(ctx, params)
 	if err != nil {
@@ -107,6 +122,23 @@ func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResp
 	return convertResponse(completion), nil
 }
 
+// convertResponseFormat converts a Kairos response format to OpenAI format.
+func convertResponseFormat(format llm.ResponseFormat) openai.ChatCompletionNewParamsResponseFormatUnion {
+	switch format.Type {
+	case llm.ResponseFormatJSONObject:
+		return openai.ChatCompletionNewParamsResponseFormatUnion{OfJSONObject: &shared.ResponseFormatJSONObjectParam{}}
+	case llm.ResponseFormatJSONSchema:
+		return openai.ChatCompletionNewParamsResponseFormatUnion{OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+			JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:   format.Name,
+				Schema: format.Schema,
+			},
+		}}
+	default:
+		return openai.ChatCompletionNewParamsResponseFormatUnion{OfText: &shared.ResponseFormatTextParam{}}
+	}
+}
+
 // convertMessage converts Kairos message to OpenAI format.
 func convertMessage(msg llm.Message) openai.ChatCompletionMessageParamUnion {
 	switch msg.Role {
@@ -230,6 +262,10 @@ func (p *Provider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan
 		params.Tools = tools
 	}
 
+	if req.ResponseFormat != nil {
+		params.ResponseFormat = convertResponseFormat(*req.ResponseFormat)
+	}
+
 	// Create streaming request
 	stream := p.client.Chat.Completions.NewStreaming(ctx, params)
 
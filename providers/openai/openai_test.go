@@ -4,6 +4,8 @@
 package openai
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/jllopis/kairos/pkg/llm"
@@ -90,3 +92,25 @@ func TestConvertTool(t *testing.T) {
 	// Just verify conversion doesn't panic
 	_ = convertTool(tool)
 }
+
+func TestConvertResponseFormat(t *testing.T) {
+	var _ llm.ResponseFormatProvider = (*Provider)(nil)
+
+	format := convertResponseFormat(llm.ResponseFormat{
+		Type:   llm.ResponseFormatJSONSchema,
+		Name:   "response",
+		Schema: map[string]any{"type": "object"},
+	})
+	data, err := json.Marshal(format)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"json_schema"`) || !strings.Contains(string(data), `"name":"response"`) {
+		t.Fatalf("unexpected response format %s", data)
+	}
+
+	data, _ = json.Marshal(convertResponseFormat(llm.ResponseFormat{Type: llm.ResponseFormatJSONObject}))
+	if !strings.Contains(string(data), `"type":"json_object"`) {
+		t.Fatalf("unexpected response format %s", data)
+	}
+}
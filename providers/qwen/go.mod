@@ -4,4 +4,11 @@ go 1.25
 
 require github.com/jllopis/kairos v0.0.0
 
+require (
+	golang.org/x/sys v0.39.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
+	google.golang.org/grpc v1.77.0 // indirect
+	google.golang.org/protobuf v1.36.10 // indirect
+)
+
 replace github.com/jllopis/kairos => ../..
@@ -69,6 +69,11 @@ func New(apiKey string, opts ...Option) *Provider {
 	return p
 }
 
+// SupportsToolCalling implements llm.ToolCallingProvider.
+func (p *Provider) SupportsToolCalling() bool {
+	return true
+}
+
 // Chat implements llm.Provider.
 func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
 	model := req.Model